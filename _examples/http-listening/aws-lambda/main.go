@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/lambda"
+
+	awslambda "github.com/aws/aws-lambda-go/lambda"
+)
+
+// Build for the Lambda Go runtime:
+// $ GOOS=linux go build -o main
+// and deploy the "main" binary behind an API Gateway (REST or HTTP API)
+// or an Application Load Balancer.
+func main() {
+	app := newApp()
+	awslambda.Start(lambda.New(app.BuildHandler()).Handle)
+}
+
+func newApp() *iris.Application {
+	app := iris.New()
+
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("Hello from AWS Lambda")
+	})
+
+	app.Get("/users/{id:uint64}", func(ctx iris.Context) {
+		ctx.JSON(iris.Map{"id": ctx.Params().GetUint64Default("id", 0)})
+	})
+
+	return app
+}
@@ -0,0 +1,57 @@
+package main
+
+import "github.com/kataras/iris/v12"
+
+// User example struct to bind to.
+type User struct {
+	Firstname string `cbor:"firstname"`
+	Lastname  string `cbor:"lastname"`
+	City      string `cbor:"city"`
+	Age       int    `cbor:"age"`
+}
+
+// readCBOR reads a `User` from CBOR post body and sends it back.
+func readCBOR(ctx iris.Context) {
+	var u User
+	err := ctx.ReadCBOR(&u)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.WriteString(err.Error())
+		return
+	}
+
+	ctx.CBOR(u)
+}
+
+// readBody reads a `User` from a JSON, XML, YAML, MsgPack or CBOR post body
+// and responds with the format the client accepts.
+func readBody(ctx iris.Context) {
+	var u User
+	err := ctx.ReadBody(&u)
+	if err != nil {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.WriteString(err.Error())
+		return
+	}
+
+	ctx.Negotiation().JSON().XML().YAML().MsgPack().CBOR()
+	ctx.Negotiate(u)
+}
+
+func newApp() *iris.Application {
+	app := iris.New()
+	app.Post("/", readCBOR)
+	app.Post("/any", readBody)
+
+	return app
+}
+
+func main() {
+	app := newApp()
+
+	// POST: http://localhost:8080
+	// Content-Type: application/cbor
+	//
+	// The response is the same user encoded in CBOR.
+	app.Listen(":8080")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12/core/cbor"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestReadCBOR(t *testing.T) {
+	app := newApp()
+	e := httptest.New(t, app)
+
+	expectedUser := User{
+		Firstname: "John",
+		Lastname:  "Doe",
+		City:      "Neither FBI knows!!!",
+		Age:       25,
+	}
+
+	body, err := cbor.Marshal(expectedUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := e.POST("/").WithHeader("Content-Type", "application/cbor").WithBytes(body).Expect().
+		Status(httptest.StatusOK).ContentType("application/cbor")
+
+	var got User
+	if err = cbor.Unmarshal([]byte(resp.Body().Raw()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != expectedUser {
+		t.Fatalf("expected user: %#+v but got: %#+v", expectedUser, got)
+	}
+
+	e.POST("/").WithHeader("Content-Type", "application/cbor").WithBytes([]byte{0xa1}).Expect().
+		Status(httptest.StatusBadRequest)
+
+	// read CBOR, write JSON.
+	e.POST("/any").WithHeader("Content-Type", "application/cbor").WithHeader("Accept", "application/json").
+		WithBytes(body).Expect().Status(httptest.StatusOK).JSON().Equal(expectedUser)
+
+	// read JSON, write CBOR.
+	resp = e.POST("/any").WithHeader("Accept", "application/cbor").WithJSON(expectedUser).Expect().
+		Status(httptest.StatusOK).ContentType("application/cbor")
+
+	got = User{}
+	if err = cbor.Unmarshal([]byte(resp.Body().Raw()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got != expectedUser {
+		t.Fatalf("expected user: %#+v but got: %#+v", expectedUser, got)
+	}
+}
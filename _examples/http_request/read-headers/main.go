@@ -0,0 +1,39 @@
+// package main contains an example on how to use the ReadHeaders
+// and how to bind request headers to a handler's payload struct through the "header" field tag.
+package main
+
+import (
+	"github.com/kataras/iris/v12"
+)
+
+type requestHeaders struct {
+	TenantID  string `header:"X-Tenant-ID"`
+	RequestID int64  `header:"X-Request-ID"`
+}
+
+type createBook struct {
+	TenantID string `header:"X-Tenant-ID" json:"-"`
+	Title    string `json:"title"`
+}
+
+func main() {
+	app := iris.New()
+
+	// curl -H "X-Tenant-ID: acme" -H "X-Request-ID: 42" http://localhost:8080
+	app.Get("/", func(ctx iris.Context) {
+		var hs requestHeaders
+		if err := ctx.ReadHeaders(&hs); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.Writef("Headers: %#v", hs)
+	})
+
+	// curl -H "X-Tenant-ID: acme" -d '{"title":"Go"}' -H "Content-Type: application/json" http://localhost:8080/books
+	app.ConfigureContainer().Post("/books", func(b createBook) string {
+		return b.TenantID + ": " + b.Title
+	})
+
+	app.Listen(":8080")
+}
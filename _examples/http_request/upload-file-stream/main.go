@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"github.com/kataras/iris/v12"
+)
+
+func newApp() *iris.Application {
+	app := iris.New()
+	app.Post("/upload", upload)
+
+	return app
+}
+
+// upload streams the received files to a hash, without storing them in memory or on disk,
+// a real application could stream them to a cloud storage instead.
+func upload(ctx iris.Context) {
+	var description string
+
+	err := ctx.ReadMultipartStream(iris.MultipartStreamHandlers{
+		MaxFileSize: 1 << 20, // 1MB per file.
+		Field: func(name, value string) error {
+			if name == "description" {
+				description = value
+			}
+			return nil
+		},
+		File: func(part *multipart.Part, r io.Reader) error {
+			h := sha256.New()
+			n, err := io.Copy(h, r)
+			if err != nil {
+				return err
+			}
+
+			ctx.Writef("%s: %d bytes, sha256: %x\n", part.FileName(), n, h.Sum(nil))
+			return nil
+		},
+	})
+
+	if err != nil {
+		if err == iris.ErrMultipartPartTooLarge {
+			ctx.StatusCode(iris.StatusRequestEntityTooLarge)
+		} else {
+			ctx.StatusCode(iris.StatusBadRequest)
+		}
+
+		ctx.WriteString(err.Error())
+		return
+	}
+
+	ctx.WriteString(fmt.Sprintf("description: %s", description))
+}
+
+func main() {
+	app := newApp()
+	// curl -F "description=my files" -F "file=@main.go" http://localhost:8080/upload
+	app.Listen(":8080")
+}
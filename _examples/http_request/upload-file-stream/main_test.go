@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"mime/multipart"
+	"testing"
+
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestUploadFileStream(t *testing.T) {
+	app := newApp()
+	e := httptest.New(t, app)
+
+	contents := []byte("Hello, Iris!")
+	body, contentType := newMultipartBody(t, "my files", "hello.txt", contents)
+
+	expected := fmt.Sprintf("hello.txt: %d bytes, sha256: %x\ndescription: my files", len(contents), sha256.Sum256(contents))
+	e.POST("/upload").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(httptest.StatusOK).Body().Equal(expected)
+
+	body, contentType = newMultipartBody(t, "too large", "large.bin", make([]byte, 1<<20+1))
+	e.POST("/upload").WithHeader("Content-Type", contentType).WithBytes(body).Expect().
+		Status(httptest.StatusRequestEntityTooLarge).Body().Equal("multipart: part too large")
+}
+
+func newMultipartBody(t *testing.T, description, filename string, contents []byte) ([]byte, string) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	if err := w.WriteField("description", description); err != nil {
+		t.Fatal(err)
+	}
+
+	fw, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fw.Write(contents); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes(), w.FormDataContentType()
+}
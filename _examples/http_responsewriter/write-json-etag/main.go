@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+)
+
+type book struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// store is a tiny in-memory storage of books.
+type store struct {
+	mu    sync.RWMutex
+	books map[string]book
+}
+
+func (s *store) get(id string) (book, bool) {
+	s.mu.RLock()
+	b, ok := s.books[id]
+	s.mu.RUnlock()
+	return b, ok
+}
+
+func main() {
+	app := newApp()
+	// GET: curl -i http://localhost:8080/books/1
+	// PUT: curl -i -X PUT -H "If-Match: <etag>" -d '{"title":"new"}' http://localhost:8080/books/1
+	app.Listen(":8080")
+}
+
+func newApp() *iris.Application {
+	s := &store{books: map[string]book{"1": {ID: "1", Title: "Go Programming Blueprints"}}}
+
+	app := iris.New()
+	app.Get("/books/{id}", func(ctx iris.Context) {
+		b, ok := s.get(ctx.Params().Get("id"))
+		if !ok {
+			ctx.NotFound()
+			return
+		}
+
+		// Sends the "ETag" header and a 304 when the client's
+		// "If-None-Match" matches the current representation.
+		ctx.WriteJSONWithETag(b)
+	})
+
+	app.Put("/books/{id}", func(ctx iris.Context) {
+		id := ctx.Params().Get("id")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		current, ok := s.books[id]
+		if !ok {
+			ctx.NotFound()
+			return
+		}
+
+		// The ETag should be computed exactly as the GET route does.
+		etag, err := etagOf(ctx, current)
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		// Fires 412 Precondition Failed if the client's
+		// "If-Match" header does not match the current ETag (lost update).
+		if !ctx.CheckIfMatch(etag) {
+			return
+		}
+
+		var b book
+		if err := ctx.ReadJSON(&b); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+		b.ID = id
+		s.books[id] = b
+
+		ctx.WriteJSONWithETag(b)
+	})
+
+	return app
+}
+
+func etagOf(ctx iris.Context, v interface{}) (string, error) {
+	buf := new(bytes.Buffer)
+	if _, err := context.WriteJSON(buf, v, context.DefaultJSONOptions, ctx.Application().ConfigurationReadOnly().GetEnableOptimizations()); err != nil {
+		return "", err
+	}
+
+	return context.ETag(buf.Bytes()), nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestWriteJSONWithETag(t *testing.T) {
+	app := newApp()
+	e := httptest.New(t, app)
+
+	etag := e.GET("/books/1").Expect().Status(httptest.StatusOK).
+		Header("ETag").NotEmpty().Raw()
+
+	e.GET("/books/1").WithHeader("If-None-Match", etag).Expect().
+		Status(httptest.StatusNotModified).Body().Empty()
+
+	// stale or unknown tag, lost update is prevented.
+	e.PUT("/books/1").WithHeader("If-Match", `"stale"`).WithJSON(map[string]string{"title": "new"}).Expect().
+		Status(httptest.StatusPreconditionFailed)
+
+	newETag := e.PUT("/books/1").WithHeader("If-Match", etag).WithJSON(map[string]string{"title": "new"}).Expect().
+		Status(httptest.StatusOK).Header("ETag").NotEqual(etag).Raw()
+
+	// the old tag is now stale.
+	e.PUT("/books/1").WithHeader("If-Match", etag).WithJSON(map[string]string{"title": "newer"}).Expect().
+		Status(httptest.StatusPreconditionFailed)
+
+	e.GET("/books/1").WithHeader("If-None-Match", newETag).Expect().
+		Status(httptest.StatusNotModified)
+	e.GET("/books/1").Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("title").Equal("new")
+}
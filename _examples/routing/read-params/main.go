@@ -0,0 +1,35 @@
+// package main contains an example on how to bind the path parameters
+// to a struct through the "param" field tag, manually or through a handler's input argument.
+package main
+
+import (
+	"github.com/kataras/iris/v12"
+)
+
+type postParams struct {
+	UserID int64  `param:"userid"`
+	Year   int    `param:"year"`
+	Slug   string `param:"slug"`
+}
+
+func main() {
+	app := iris.New()
+
+	// http://localhost:8080/users/42/posts/2020/hello-world
+	app.Get("/users/{userid:int64}/posts/{year:int}/{slug}", func(ctx iris.Context) {
+		var p postParams
+		if err := ctx.ReadParams(&p); err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+
+		ctx.Writef("Params: %#v", p)
+	})
+
+	// http://localhost:8080/v2/users/42/posts/2020/hello-world
+	app.ConfigureContainer().Get("/v2/users/{userid:int64}/posts/{year:int}/{slug}", func(p postParams) iris.Map {
+		return iris.Map{"user": p.UserID, "year": p.Year, "slug": p.Slug}
+	})
+
+	app.Listen(":8080")
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kataras/iris/v12/cache/client/rule"
@@ -13,6 +14,8 @@ import (
 // the original response, the memory cache entry and
 // the validator for each of the incoming requests and post responses
 type Handler struct {
+	// the atomic counters of the Stats, first for 64-bit alignment.
+	hits, misses uint64
 	// Rule optional validators for pre cache and post cache actions
 	//
 	// See more at ruleset.go
@@ -24,6 +27,29 @@ type Handler struct {
 	mu      sync.RWMutex
 }
 
+// Stats holds the statistics of a cache Handler, see `Handler.Stats`.
+type Stats struct {
+	// Entries is the number of the stored responses.
+	Entries int `json:"entries"`
+	// Hits is the number of the requests served from the cache.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of the cacheable requests that executed the handler.
+	Misses uint64 `json:"misses"`
+}
+
+// Stats returns the current statistics of the cache.
+func (h *Handler) Stats() Stats {
+	h.mu.RLock()
+	n := len(h.entries)
+	h.mu.RUnlock()
+
+	return Stats{
+		Entries: n,
+		Hits:    atomic.LoadUint64(&h.hits),
+		Misses:  atomic.LoadUint64(&h.misses),
+	}
+}
+
 // NewHandler returns a new cached handler for the "bodyHandler"
 // which expires every "expiration".
 func NewHandler(expiration time.Duration) *Handler {
@@ -120,6 +146,7 @@ func (h *Handler) ServeHTTP(ctx context.Context) {
 	}
 
 	if !valid {
+		atomic.AddUint64(&h.misses, 1)
 		// if it's expired, then execute the original handler
 		// with our custom response recorder response writer
 		// because the net/http doesn't give us
@@ -160,6 +187,7 @@ func (h *Handler) ServeHTTP(ctx context.Context) {
 	}
 
 	// if it's valid then just write the cached results
+	atomic.AddUint64(&h.hits, 1)
 	entry.CopyHeaders(ctx.ResponseWriter().Header(), response.Headers())
 	ctx.SetLastModified(e.LastModified)
 	ctx.StatusCode(response.StatusCode())
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// The options of the `Context.CacheControl` and `Party.SetCacheHeaders`, i.e
+//  ctx.CacheControl(cache.Public, cache.MaxAge(5*time.Minute), cache.SWR(30*time.Second))
+var (
+	// Public marks the response as cacheable by any cache, the "public" directive.
+	Public context.CacheControlOption = func(c *context.CacheControl) { c.Public = true }
+	// Private marks the response as cacheable only by the browser, the "private" directive.
+	Private context.CacheControlOption = func(c *context.CacheControl) { c.Private = true }
+	// NoCacheDirective requires the caches to revalidate the response before each reuse, the "no-cache" directive.
+	NoCacheDirective context.CacheControlOption = func(c *context.CacheControl) { c.NoCache = true }
+	// NoStore disallows any cache to store the response, the "no-store" directive.
+	NoStore context.CacheControlOption = func(c *context.CacheControl) { c.NoStore = true }
+	// MustRevalidate disallows the reuse of a stale response without revalidation, the "must-revalidate" directive.
+	MustRevalidate context.CacheControlOption = func(c *context.CacheControl) { c.MustRevalidate = true }
+	// ProxyRevalidate is the "must-revalidate" for the shared caches only, the "proxy-revalidate" directive.
+	ProxyRevalidate context.CacheControlOption = func(c *context.CacheControl) { c.ProxyRevalidate = true }
+	// NoTransform disallows the caches to modify the response body, the "no-transform" directive.
+	NoTransform context.CacheControlOption = func(c *context.CacheControl) { c.NoTransform = true }
+	// Immutable marks a fresh response as never changing, the "immutable" directive,
+	// use it for the versioned static files.
+	Immutable context.CacheControlOption = func(c *context.CacheControl) { c.Immutable = true }
+)
+
+// MaxAge sets the "max-age" directive and the Expires header.
+func MaxAge(d time.Duration) context.CacheControlOption {
+	return func(c *context.CacheControl) { c.MaxAge = d }
+}
+
+// SMaxAge sets the "s-maxage" directive, the max age of the shared caches.
+func SMaxAge(d time.Duration) context.CacheControlOption {
+	return func(c *context.CacheControl) { c.SharedMaxAge = d }
+}
+
+// SWR sets the "stale-while-revalidate" directive, the duration which a stale response
+// can be served while it's revalidated in the background.
+func SWR(d time.Duration) context.CacheControlOption {
+	return func(c *context.CacheControl) { c.StaleWhileRevalidate = d }
+}
+
+// StaleIfError sets the "stale-if-error" directive, the duration which a stale response
+// can be served when the revalidation fails.
+func StaleIfError(d time.Duration) context.CacheControlOption {
+	return func(c *context.CacheControl) { c.StaleIfError = d }
+}
+
+// SurrogateMaxAge sets the "max-age" of the Surrogate-Control header, the max age of the CDNs.
+func SurrogateMaxAge(d time.Duration) context.CacheControlOption {
+	return func(c *context.CacheControl) { c.SurrogateMaxAge = d }
+}
@@ -0,0 +1,69 @@
+package cache_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/cache"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestCacheControlDirectives(t *testing.T) {
+	tests := []struct {
+		options  []context.CacheControlOption
+		expected string
+	}{
+		{[]context.CacheControlOption{cache.Public, cache.MaxAge(5 * time.Minute), cache.SWR(30 * time.Second)},
+			"public, max-age=300, stale-while-revalidate=30"},
+		{[]context.CacheControlOption{cache.Public, cache.Private, cache.MaxAge(time.Minute), cache.SMaxAge(time.Hour), cache.ProxyRevalidate},
+			"private, max-age=60"},
+		{[]context.CacheControlOption{cache.Public, cache.NoStore, cache.MaxAge(time.Minute)},
+			"no-store"},
+		{[]context.CacheControlOption{cache.NoCacheDirective, cache.Immutable, cache.MustRevalidate},
+			"no-cache, must-revalidate"},
+		{[]context.CacheControlOption{cache.Public, cache.MaxAge(365 * 24 * time.Hour), cache.Immutable, cache.StaleIfError(time.Hour)},
+			"public, max-age=31536000, immutable, stale-if-error=3600"},
+	}
+
+	for i, tt := range tests {
+		if got := context.NewCacheControl(tt.options...).String(); got != tt.expected {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	app := iris.New()
+
+	assets := app.Party("/assets").SetCacheHeaders(cache.Public, cache.MaxAge(time.Hour), cache.SurrogateMaxAge(24*time.Hour))
+	assets.Get("/app.js", func(ctx iris.Context) {
+		ctx.WriteString("app")
+	})
+	assets.Get("/user.js", func(ctx iris.Context) {
+		ctx.CacheControl(cache.Private, cache.NoCacheDirective)
+		ctx.WriteString("user")
+	})
+
+	e := httptest.New(t, app)
+
+	r := e.GET("/assets/app.js").Expect().Status(httptest.StatusOK)
+	r.Header(context.CacheControlHeaderKey).Equal("public, max-age=3600")
+	r.Header(context.SurrogateControlHeaderKey).Equal("max-age=86400")
+
+	expires, err := time.Parse(http.TimeFormat, r.Raw().Header.Get(context.ExpiresHeaderKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expires); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("expected the Expires header to be an hour later but got: %s", d)
+	}
+
+	r = e.GET("/assets/user.js").Expect().Status(httptest.StatusOK)
+	r.Header(context.CacheControlHeaderKey).Equal("private, no-cache")
+	r.Header(context.ExpiresHeaderKey).Equal("0")
+	r.Header(context.SurrogateControlHeaderKey).Equal("no-store")
+}
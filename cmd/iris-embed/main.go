@@ -0,0 +1,132 @@
+// Command iris-embed prepares a directory of assets to be embedded and served by an Iris application:
+// it writes the gzip pre-compressed siblings ("name.gz") of the compressible files
+// and the fingerprints manifest ("iris-embed.json") of all files, used for cache busting.
+//
+// Usage:
+//  //go:generate go run github.com/kataras/iris/v12/cmd/iris-embed -dir ./assets
+//  //go:embed assets
+//  var assets embed.FS
+//
+//  app.HandleDir("/static", "./assets", iris.EmbeddedDir(assets, false))
+//  manifest, _ := iris.LoadAssetManifest(assets, "assets")
+//
+// Brotli siblings ("name.br") are served too when they exist,
+// generate them with the brotli command line tool as there is no brotli encoder in the standard library.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func main() {
+	dir := flag.String("dir", "./assets", "the assets directory")
+	compress := flag.Bool("gzip", true, "write the gzip pre-compressed siblings of the compressible files")
+	minSize := flag.Int("min", 1024, "the minimum size of a file to be compressed")
+	flag.Parse()
+
+	if err := run(*dir, *compress, *minSize); err != nil {
+		fmt.Fprintf(os.Stderr, "iris-embed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, compress bool, minSize int) error {
+	manifest := make(router.AssetManifest)
+
+	err := filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if filename != dir && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasPrefix(name, ".") || name == router.AssetManifestName ||
+			strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".br") {
+			return nil
+		}
+
+		contents, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filename)
+		if err != nil {
+			return err
+		}
+		manifest[filepath.ToSlash(rel)] = router.AssetFingerprint(contents)
+
+		if compress && len(contents) >= minSize && compressible(name) {
+			return writeGzip(filename+".gz", contents)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, router.AssetManifestName), b, 0644)
+}
+
+func compressible(name string) bool {
+	ctype := router.TypeByExtension(filepath.Ext(name))
+	if idx := strings.IndexByte(ctype, ';'); idx != -1 {
+		ctype = ctype[:idx]
+	}
+
+	switch {
+	case strings.HasPrefix(ctype, "text/"),
+		strings.HasSuffix(ctype, "json"),
+		strings.HasSuffix(ctype, "xml"),
+		strings.HasSuffix(ctype, "javascript"),
+		ctype == "image/svg+xml",
+		ctype == "application/wasm":
+		return true
+	default:
+		return false
+	}
+}
+
+func writeGzip(filename string, contents []byte) error {
+	buf := new(bytes.Buffer)
+	w, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(contents); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	if buf.Len() >= len(contents) { // not worth it.
+		return nil
+	}
+
+	return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+}
@@ -0,0 +1,355 @@
+// Package config provides a live-reloadable application configuration.
+// Values are loaded from one or more sources (files, environment variables, remote endpoints),
+// they can be watched for changes and registered callbacks are fired on each modified key,
+// so things like the log level, rate limits and feature flags are updated without a restart.
+//
+// Example Code:
+//  config.Load(config.File("iris.yml"), config.Env("IRIS"))
+//  config.OnChange("logger.level", func(level string) {
+//      app.Logger().SetLevel(level)
+//  })
+//  stop := config.Watch(2 * time.Second)
+//  defer stop()
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// Config holds the configuration values loaded by its sources.
+// Later sources override the values of the previous ones.
+//
+// Use the `New` function to create a new Config.
+type Config struct {
+	// ErrorHandler is fired when a reload through `Watch` failed,
+	// the previous values are kept.
+	// Defaults to a function which logs the error.
+	ErrorHandler func(error)
+
+	sources []Source
+
+	mu        sync.RWMutex
+	values    map[string]interface{}
+	listeners map[string][]func(Value)
+}
+
+// New returns a new Config of the given "sources".
+// Call its `Load` method to read the values.
+//
+// Example Code:
+//  c := config.New(config.File("iris.yml"), config.Env("IRIS"))
+//  if err := c.Load(); err != nil { [...] }
+func New(sources ...Source) *Config {
+	return &Config{
+		ErrorHandler: func(err error) {
+			golog.Errorf("config: reload: %v", err)
+		},
+		sources:   sources,
+		values:    make(map[string]interface{}),
+		listeners: make(map[string][]func(Value)),
+	}
+}
+
+// AddSource registers one or more sources, they override the values of the existing ones.
+// Call the `Load` method to apply them.
+func (c *Config) AddSource(sources ...Source) {
+	c.mu.Lock()
+	c.sources = append(c.sources, sources...)
+	c.mu.Unlock()
+}
+
+// Load reads all sources and replaces the current values.
+// The registered `OnChange` callbacks are fired for each modified key.
+// If a source fails then the current values are kept and the error is returned.
+func (c *Config) Load() error {
+	c.mu.RLock()
+	sources := c.sources
+	c.mu.RUnlock()
+
+	values := make(map[string]interface{})
+	for _, src := range sources {
+		v, err := src.Load()
+		if err != nil {
+			return err
+		}
+		flatten(values, "", v)
+	}
+
+	c.mu.Lock()
+	old := c.values
+	c.values = values
+	changed := diff(old, values)
+
+	var calls []func()
+	for key, fns := range c.listeners {
+		if !hasChanged(changed, key) {
+			continue
+		}
+
+		v := c.get(key)
+		for _, fn := range fns {
+			fn := fn
+			calls = append(calls, func() { fn(v) })
+		}
+	}
+	c.mu.Unlock()
+
+	for _, call := range calls {
+		call()
+	}
+
+	return nil
+}
+
+// Watch reloads the sources every "interval"
+// and fires the `OnChange` callbacks of the modified keys.
+// Reload errors are passed to the `ErrorHandler`.
+// It returns a function which stops watching,
+// it waits for an in-progress reload to finish.
+func (c *Config) Watch(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := c.Load(); err != nil && c.ErrorHandler != nil {
+					c.ErrorHandler(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// OnChange registers a callback which is fired when the value of the "key" is modified
+// (added, changed or removed) after a `Load` or a `Watch` reload.
+// The "key" is case-insensitive and it can also target a section,
+// e.g. "logger" fires on any "logger.*" modification.
+//
+// The "fn" can be one of the following:
+//  func(config.Value)
+//  func(string)
+//  func(int)
+//  func(int64)
+//  func(float64)
+//  func(bool)
+//  func(time.Duration)
+//  func([]string)
+//  func()
+//
+// It panics on any other type.
+func (c *Config) OnChange(key string, fn interface{}) {
+	cb := convertCallback(fn)
+
+	key = strings.ToLower(key)
+	c.mu.Lock()
+	c.listeners[key] = append(c.listeners[key], cb)
+	c.mu.Unlock()
+}
+
+// Get returns the value of the "key", it is case-insensitive.
+// If the "key" targets a section then the value holds a map of its children.
+func (c *Config) Get(key string) Value {
+	c.mu.RLock()
+	v := c.get(strings.ToLower(key))
+	c.mu.RUnlock()
+	return v
+}
+
+// Keys returns the sorted keys of all values.
+func (c *Config) Keys() []string {
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Decode binds the value of the "key" to the "outPtr",
+// field names are matched case-insensitively.
+// An empty "key" decodes all values.
+//
+// Example Code:
+//  var cfg iris.Configuration
+//  c.Decode("iris", &cfg)
+func (c *Config) Decode(key string, outPtr interface{}) error {
+	v := c.Get(key).Raw()
+	if v == nil && key == "" {
+		c.mu.RLock()
+		v = expand(c.values, "")
+		c.mu.RUnlock()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("config: decode: %w", err)
+	}
+
+	if err = json.Unmarshal(b, outPtr); err != nil {
+		return fmt.Errorf("config: decode: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Config) get(key string) Value {
+	if v, ok := c.values[key]; ok {
+		return Value{raw: v}
+	}
+
+	if key == "" {
+		return Value{}
+	}
+
+	if section := expand(c.values, key); len(section) > 0 {
+		return Value{raw: section}
+	}
+
+	return Value{}
+}
+
+// expand builds a nested map of the flat "values" under the "prefix".
+func expand(values map[string]interface{}, prefix string) map[string]interface{} {
+	if prefix != "" {
+		prefix += "."
+	}
+
+	m := make(map[string]interface{})
+	for k, v := range values {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		parts := strings.Split(k[len(prefix):], ".")
+		cur := m
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := cur[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				cur[part] = next
+			}
+			cur = next
+		}
+		cur[parts[len(parts)-1]] = v
+	}
+
+	return m
+}
+
+func diff(old, values map[string]interface{}) map[string]struct{} {
+	changed := make(map[string]struct{})
+	for k, v := range values {
+		if ov, ok := old[k]; !ok || !reflect.DeepEqual(ov, v) {
+			changed[k] = struct{}{}
+		}
+	}
+
+	for k := range old {
+		if _, ok := values[k]; !ok {
+			changed[k] = struct{}{}
+		}
+	}
+
+	return changed
+}
+
+func hasChanged(changed map[string]struct{}, key string) bool {
+	if _, ok := changed[key]; ok {
+		return true
+	}
+
+	prefix := key + "."
+	for k := range changed {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func convertCallback(fn interface{}) func(Value) {
+	switch cb := fn.(type) {
+	case func(Value):
+		return cb
+	case func(string):
+		return func(v Value) { cb(v.String()) }
+	case func(int):
+		return func(v Value) { cb(v.Int()) }
+	case func(int64):
+		return func(v Value) { cb(v.Int64()) }
+	case func(float64):
+		return func(v Value) { cb(v.Float64()) }
+	case func(bool):
+		return func(v Value) { cb(v.Bool()) }
+	case func(time.Duration):
+		return func(v Value) { cb(v.Duration()) }
+	case func([]string):
+		return func(v Value) { cb(v.Strings()) }
+	case func():
+		return func(Value) { cb() }
+	default:
+		panic(fmt.Sprintf("config: unsupported OnChange callback type: %T", fn))
+	}
+}
+
+// Default is the package-level Config instance
+// which is used by the package-level functions.
+var Default = New()
+
+// Load registers the "sources" to the `Default` Config and loads them.
+// See `Config.Load` for more.
+func Load(sources ...Source) error {
+	Default.AddSource(sources...)
+	return Default.Load()
+}
+
+// Watch reloads the `Default` Config every "interval".
+// See `Config.Watch` for more.
+func Watch(interval time.Duration) (stop func()) {
+	return Default.Watch(interval)
+}
+
+// OnChange registers a callback for the "key" of the `Default` Config.
+// See `Config.OnChange` for more.
+func OnChange(key string, fn interface{}) {
+	Default.OnChange(key, fn)
+}
+
+// Get returns the value of the "key" from the `Default` Config.
+// See `Config.Get` for more.
+func Get(key string) Value {
+	return Default.Get(key)
+}
+
+// Decode binds the value of the "key" from the `Default` Config to the "outPtr".
+// See `Config.Decode` for more.
+func Decode(key string, outPtr interface{}) error {
+	return Default.Decode(key, outPtr)
+}
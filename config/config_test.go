@@ -0,0 +1,125 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/config"
+)
+
+func TestConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "iris.yml")
+	write := func(contents string) {
+		if err := ioutil.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`
+Logger:
+  Level: info
+RateLimit:
+  Limit: 10
+  Window: 1m
+Features:
+  - search
+`)
+
+	os.Setenv("IRIS_TEST_FEATURE__DARK", "true")
+	os.Setenv("IRIS_TEST_FEATURE__DARK_MODE", "true")
+	defer func() {
+		os.Unsetenv("IRIS_TEST_FEATURE__DARK")
+		os.Unsetenv("IRIS_TEST_FEATURE__DARK_MODE")
+	}()
+
+	c := config.New(config.Map(map[string]interface{}{"logger": map[string]interface{}{"level": "error"}}),
+		config.File(filename), config.Env("IRIS_TEST"))
+
+	var (
+		level     string
+		limit     int
+		section   int
+		errReload error
+	)
+	c.OnChange("logger.level", func(v string) { level = v })
+	c.OnChange("ratelimit.limit", func(v int) { limit = v })
+	c.OnChange("ratelimit", func() { section++ })
+	c.ErrorHandler = func(err error) { errReload = err }
+
+	if err = c.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "info"; level != expected {
+		t.Fatalf("expected level: %s but got: %s", expected, level)
+	}
+	if expected := 10; limit != expected {
+		t.Fatalf("expected limit: %d but got: %d", expected, limit)
+	}
+	if expected := time.Minute; c.Get("RateLimit.Window").Duration() != expected {
+		t.Fatalf("expected window: %s but got: %s", expected, c.Get("RateLimit.Window").Duration())
+	}
+	if !c.Get("feature.dark").Bool() {
+		t.Fatalf("expected environment variable to set the feature.dark key")
+	}
+	if !c.Get("feature.dark_mode").Bool() {
+		t.Fatalf("expected environment variable to set the feature.dark_mode key")
+	}
+	if expected, got := "search", c.Get("features").Strings(); len(got) != 1 || got[0] != expected {
+		t.Fatalf("expected features: [%s] but got: %v", expected, got)
+	}
+
+	var rateLimit struct {
+		Limit  int
+		Window string
+	}
+	if err = c.Decode("ratelimit", &rateLimit); err != nil {
+		t.Fatal(err)
+	}
+	if rateLimit.Limit != 10 || rateLimit.Window != "1m" {
+		t.Fatalf("unexpected decoded value: %#v", rateLimit)
+	}
+
+	// modify only the level.
+	write(`
+Logger:
+  Level: debug
+RateLimit:
+  Limit: 10
+  Window: 1m
+`)
+
+	stop := c.Watch(10 * time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	if expected := "debug"; c.Get("logger.level").String() != expected {
+		t.Fatalf("expected level: %s but got: %s", expected, c.Get("logger.level").String())
+	}
+	if expected := 1; section != expected {
+		t.Fatalf("expected section callback to be fired %d time(s) but got: %d", expected, section)
+	}
+	if errReload != nil {
+		t.Fatal(errReload)
+	}
+
+	// invalid contents should keep the previous values.
+	if err = ioutil.WriteFile(filename, []byte("Logger: ["), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Load(); err == nil {
+		t.Fatalf("expected an error on invalid file contents")
+	}
+	if expected := "debug"; c.Get("logger.level").String() != expected {
+		t.Fatalf("expected level: %s to be kept but got: %s", expected, c.Get("logger.level").String())
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Source loads a tree of configuration values,
+// nested maps are flattened to dot-separated keys, e.g. "logger.level".
+// See `File`, `Env`, `Remote` and `Map` package-level functions.
+type Source interface {
+	Load() (map[string]interface{}, error)
+}
+
+// SourceFunc is a function which implements the `Source` interface.
+type SourceFunc func() (map[string]interface{}, error)
+
+// Load calls the "fn" itself.
+func (fn SourceFunc) Load() (map[string]interface{}, error) {
+	return fn()
+}
+
+// Map returns a `Source` of static values, commonly used for defaults.
+func Map(values map[string]interface{}) Source {
+	return SourceFunc(func() (map[string]interface{}, error) {
+		return values, nil
+	})
+}
+
+// File returns a `Source` which reads the "filename" on each load.
+// The format is resolved by the file's extension:
+// ".yml" or ".yaml", ".toml" or ".tml" and ".json".
+//
+// A missing file is not an error, it just provides no values,
+// so a configuration file is optional when defaults or environment variables are set.
+func File(filename string) Source {
+	return SourceFunc(func() (map[string]interface{}, error) {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("config: %w", err)
+		}
+
+		values, err := decode(filepath.Ext(filename), data)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", filename, err)
+		}
+
+		return values, nil
+	})
+}
+
+// envSeparator separates the nested keys of an environment variable,
+// a single underline is part of the key.
+const envSeparator = "__"
+
+// Env returns a `Source` of the environment variables which start with the "prefix" and an underline.
+// The rest of the variable's name is lowercased and each double underline separates a nested key,
+// e.g. "IRIS_LOGGER__LEVEL=debug" with "IRIS" prefix sets the "logger.level" key
+// and "IRIS_READ_TIMEOUT=5s" sets the "read_timeout" key.
+// Values are parsed as YAML scalars, so "true" is a boolean and "10" an integer.
+func Env(prefix string) Source {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	return SourceFunc(func() (map[string]interface{}, error) {
+		values := make(map[string]interface{})
+		for _, kv := range os.Environ() {
+			idx := strings.IndexByte(kv, '=')
+			if idx <= 0 || !strings.HasPrefix(kv[:idx], prefix) {
+				continue
+			}
+
+			key := strings.Replace(strings.ToLower(kv[len(prefix):idx]), envSeparator, ".", -1)
+			if key == "" {
+				continue
+			}
+
+			values[key] = parseScalar(kv[idx+1:])
+		}
+
+		return values, nil
+	})
+}
+
+// Remote returns a `Source` which fetches the values from the "url" through a GET request.
+// The format is resolved by the response's Content-Type or by the url's extension,
+// it defaults to JSON.
+func Remote(url string) Source {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return SourceFunc(func() (map[string]interface{}, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("config: %s: unexpected status code: %d", url, resp.StatusCode)
+		}
+
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+
+		ext := ".json"
+		if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+			switch {
+			case strings.Contains(mediaType, "yaml"):
+				ext = ".yml"
+			case strings.Contains(mediaType, "toml"):
+				ext = ".toml"
+			}
+		}
+		if e := filepath.Ext(url); e != "" && ext == ".json" {
+			ext = e
+		}
+
+		values, err := decode(ext, data)
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", url, err)
+		}
+
+		return values, nil
+	})
+}
+
+func decode(ext string, data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	var err error
+	switch strings.ToLower(ext) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml", ".tml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		err = json.Unmarshal(data, &values)
+	}
+
+	return values, err
+}
+
+func parseScalar(s string) interface{} {
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		// keep complex or empty values as they are.
+		return s
+	default:
+		return v
+	}
+}
+
+// flatten converts the nested "src" map to a flat map of lowercase dot-separated keys.
+func flatten(dest map[string]interface{}, prefix string, src map[string]interface{}) {
+	for k, v := range src {
+		key := strings.ToLower(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch m := v.(type) {
+		case map[string]interface{}:
+			flatten(dest, key, m)
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(m))
+			for mk, mv := range m {
+				converted[fmt.Sprint(mk)] = mv
+			}
+			flatten(dest, key, converted)
+		default:
+			dest[key] = v
+		}
+	}
+}
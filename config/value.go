@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value holds a configuration value, it is returned from the `Config.Get` method
+// and it is passed to the `OnChange` callbacks.
+// Its methods convert the underline value to the wanted type.
+type Value struct {
+	raw interface{}
+}
+
+// Exists reports whether the key of this value was set by any of the sources.
+func (v Value) Exists() bool {
+	return v.raw != nil
+}
+
+// Raw returns the underline value.
+func (v Value) Raw() interface{} {
+	return v.raw
+}
+
+// String returns the value as string.
+func (v Value) String() string {
+	if v.raw == nil {
+		return ""
+	}
+
+	if s, ok := v.raw.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(v.raw)
+}
+
+// Int returns the value as int, zero on failure.
+func (v Value) Int() int {
+	return int(v.Int64())
+}
+
+// Int64 returns the value as int64, zero on failure.
+func (v Value) Int64() int64 {
+	switch n := v.raw.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case uint64:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	default:
+		return 0
+	}
+}
+
+// Float64 returns the value as float64, zero on failure.
+func (v Value) Float64() float64 {
+	switch n := v.raw.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// Bool returns the value as boolean, false on failure.
+func (v Value) Bool() bool {
+	switch b := v.raw.(type) {
+	case bool:
+		return b
+	case string:
+		ok, _ := strconv.ParseBool(b)
+		return ok
+	default:
+		return v.Int64() != 0
+	}
+}
+
+// Duration returns the value as time.Duration.
+// String values are parsed through `time.ParseDuration`, i.e. "5s",
+// numbers are treated as seconds.
+func (v Value) Duration() time.Duration {
+	if s, ok := v.raw.(string); ok {
+		d, _ := time.ParseDuration(s)
+		return d
+	}
+
+	return time.Duration(v.Float64() * float64(time.Second))
+}
+
+// Strings returns the value as a slice of strings,
+// a single string value is separated by commas.
+func (v Value) Strings() []string {
+	switch s := v.raw.(type) {
+	case []string:
+		return s
+	case []interface{}:
+		values := make([]string, 0, len(s))
+		for _, e := range s {
+			values = append(values, fmt.Sprint(e))
+		}
+		return values
+	case string:
+		values := strings.Split(s, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values
+	case nil:
+		return nil
+	default:
+		return []string{v.String()}
+	}
+}
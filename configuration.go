@@ -16,7 +16,9 @@ import (
 	"strings"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/netutil"
+	"github.com/kataras/iris/v12/core/router"
 
 	"github.com/BurntSushi/toml"
 	"github.com/kataras/sitemap"
@@ -255,6 +257,44 @@ var WithoutAutoFireStatusCode = func(app *Application) {
 	app.config.DisableAutoFireStatusCode = true
 }
 
+// WithDebugErrorPages enables the EnableDebugErrorPages setting.
+//
+// See `Configuration`.
+var WithDebugErrorPages = func(app *Application) {
+	app.config.EnableDebugErrorPages = true
+}
+
+// WithRouteStats enables the EnableRouteStats setting.
+//
+// See `Configuration`.
+var WithRouteStats = func(app *Application) {
+	app.config.EnableRouteStats = true
+}
+
+// WithRequestHardening sets the RequestHardening setting.
+//
+// See `Configuration`.
+func WithRequestHardening(options router.RequestHardening) Configurator {
+	return func(app *Application) {
+		app.config.RequestHardening = options
+	}
+}
+
+// WithLogger sets the structured logger of the Application,
+// i.e iris.WithLogger(logging.New(logging.JSON(os.Stdout), level)).
+// The "level" is a `*logging.LevelVar` which can be changed at runtime.
+//
+// See `Application.Log` and the `core/logging` package.
+func WithLogger(logger Logger) Configurator {
+	return func(app *Application) {
+		if logger == nil {
+			logger = logging.Nop
+		}
+
+		app.log = logger
+	}
+}
+
 // WithPathEscape sets the EnablePathEscape setting to true.
 //
 // See `Configuration`.
@@ -284,6 +324,16 @@ var WithFireMethodNotAllowed = func(app *Application) {
 	app.config.FireMethodNotAllowed = true
 }
 
+// WithRouteCache sets the RouteCacheSize setting,
+// the number of the resolved routes to cache by request method and path.
+//
+// See `Configuration`.
+func WithRouteCache(size int) Configurator {
+	return func(app *Application) {
+		app.config.RouteCacheSize = size
+	}
+}
+
 // WithTimeFormat sets the TimeFormat setting.
 //
 // See `Configuration`.
@@ -794,6 +844,14 @@ type Configuration struct {
 	//  fires the 405 error instead of 404
 	// Defaults to false.
 	FireMethodNotAllowed bool `json:"fireMethodNotAllowed,omitempty" yaml:"FireMethodNotAllowed" toml:"FireMethodNotAllowed"`
+	// RouteCacheSize if it's greater than zero then the router keeps
+	// up to "RouteCacheSize" recently resolved routes and their path parameters, by request method and path,
+	// so repeated identical requests (e.g. API polling) skip the routes tree's search.
+	// The cache is cleared on `Application.RefreshRouter`.
+	// It is not used when routes are registered on subdomains.
+	//
+	// Defaults to 0 (disabled).
+	RouteCacheSize int `json:"routeCacheSize,omitempty" yaml:"RouteCacheSize" toml:"RouteCacheSize"`
 
 	// DisableBodyConsumptionOnUnmarshal manages the reading behavior of the context's body readers/binders.
 	// If set to true then it
@@ -819,6 +877,35 @@ type Configuration struct {
 	// Defaults to false.
 	DisableAutoFireStatusCode bool `json:"disableAutoFireStatusCode,omitempty" yaml:"DisableAutoFireStatusCode" toml:"DisableAutoFireStatusCode"`
 
+	// EnableDebugErrorPages if true then the default http error handlers
+	// of the 5xx status codes render a developer-friendly HTML page,
+	// which contains the error, the stack frames of a recovered panic
+	// (see the recover middleware), the request's headers and body,
+	// the session's values and, for template errors, the failing template's line.
+	//
+	// It exposes internal information, use it only on development.
+	// Custom error handlers registered through `OnErrorCode` are not affected.
+	//
+	// Defaults to false.
+	EnableDebugErrorPages bool `json:"enableDebugErrorPages,omitempty" yaml:"EnableDebugErrorPages" toml:"EnableDebugErrorPages"`
+
+	// EnableRouteStats if true then a lightweight, in-memory, collector
+	// keeps the requests count, the error rate, the latency percentiles
+	// and the time of the last error per route.
+	// The statistics are available through `Application.Stats` and `Application.StatsHandler`.
+	//
+	// Defaults to false.
+	EnableRouteStats bool `json:"enableRouteStats,omitempty" yaml:"EnableRouteStats" toml:"EnableRouteStats"`
+
+	// RequestHardening contains the options to reject malformed or suspicious requests,
+	// i.e requests with conflicting Content-Length and Transfer-Encoding headers,
+	// too many or too large headers, disallowed methods and NUL bytes in their path.
+	// Rejected requests are answered with 400 Bad Request before routing
+	// and they are counted per reason, see `Application.RejectedRequests`.
+	//
+	// Defaults to an empty hardening which rejects nothing.
+	RequestHardening router.RequestHardening `json:"requestHardening,omitempty" yaml:"RequestHardening" toml:"RequestHardening"`
+
 	// TimeFormat time format for any kind of datetime parsing
 	// Defaults to  "Mon, 02 Jan 2006 15:04:05 GMT".
 	TimeFormat string `json:"timeFormat,omitempty" yaml:"TimeFormat" toml:"TimeFormat"`
@@ -835,6 +922,12 @@ type Configuration struct {
 	//
 	// Defaults to 32MB or 32 << 20 if you prefer.
 	PostMaxMemory int64 `json:"postMaxMemory" yaml:"PostMaxMemory" toml:"PostMaxMemory"`
+	// RecordRequestBodyLimit is the maximum size of a request body
+	// which is recorded by the `context#RecordRequestBody`,
+	// larger bodies fail the `context#GetBody` with the `context#ErrRequestBodyTooLarge`.
+	//
+	// Defaults to 32MB or 32 << 20 if you prefer.
+	RecordRequestBodyLimit int64 `json:"recordRequestBodyLimit" yaml:"RecordRequestBodyLimit" toml:"RecordRequestBodyLimit"`
 	//  +----------------------------------------------------+
 	//  | Context's keys for values used on various featuers |
 	//  +----------------------------------------------------+
@@ -859,6 +952,17 @@ type Configuration struct {
 	//
 	// Defaults to "iris.viewData"
 	ViewDataContextKey string `json:"viewDataContextKey,omitempty" yaml:"ViewDataContextKey" toml:"ViewDataContextKey"`
+	// ViewBufferSize is the number of bytes of a rendered template (see `Context.View`)
+	// which are buffered before the output is streamed to the client.
+	// A template error before that limit is reached discards the buffered output,
+	// so the status code can still be changed, i.e. to 500.
+	// The larger pages are not kept in memory as a whole.
+	// A negative value buffers the whole output
+	// and a zero value writes directly to the client.
+	// On `EnableDebugErrorPages` the whole output is always buffered.
+	//
+	// Defaults to 32KB or 32 << 10 if you prefer.
+	ViewBufferSize int `json:"viewBufferSize,omitempty" yaml:"ViewBufferSize" toml:"ViewBufferSize"`
 	// RemoteAddrHeaders are the allowed request headers names
 	// that can be valid to parse the client's IP based on.
 	// By-default no "X-" header is consired safe to be used for retrieving the
@@ -950,6 +1054,11 @@ func (c Configuration) GetFireMethodNotAllowed() bool {
 	return c.FireMethodNotAllowed
 }
 
+// GetRouteCacheSize returns the Configuration#RouteCacheSize.
+func (c Configuration) GetRouteCacheSize() int {
+	return c.RouteCacheSize
+}
+
 // GetDisableBodyConsumptionOnUnmarshal returns the Configuration#GetDisableBodyConsumptionOnUnmarshal,
 // manages the reading behavior of the context's body readers/binders.
 // If returns true then the body consumption by the `context.UnmarshalBody/ReadJSON/ReadXML`
@@ -969,6 +1078,18 @@ func (c Configuration) GetDisableAutoFireStatusCode() bool {
 	return c.DisableAutoFireStatusCode
 }
 
+// GetEnableDebugErrorPages returns the Configuration#EnableDebugErrorPages.
+// Returns true when the default 5xx error handlers render the developer error pages.
+func (c Configuration) GetEnableDebugErrorPages() bool {
+	return c.EnableDebugErrorPages
+}
+
+// GetEnableRouteStats returns the Configuration#EnableRouteStats.
+// Returns true when the per-route statistics collector is enabled.
+func (c Configuration) GetEnableRouteStats() bool {
+	return c.EnableRouteStats
+}
+
 // GetTimeFormat returns the Configuration#TimeFormat,
 // format for any kind of datetime parsing.
 func (c Configuration) GetTimeFormat() string {
@@ -992,6 +1113,12 @@ func (c Configuration) GetPostMaxMemory() int64 {
 	return c.PostMaxMemory
 }
 
+// GetRecordRequestBodyLimit returns the maximum size of a request body
+// which is recorded by the `context#RecordRequestBody`.
+func (c Configuration) GetRecordRequestBodyLimit() int64 {
+	return c.RecordRequestBodyLimit
+}
+
 // GetLocaleContextKey returns the configuration's LocaleContextKey value,
 // used for i18n.
 func (c Configuration) GetLocaleContextKey() string {
@@ -1013,6 +1140,12 @@ func (c Configuration) GetViewDataContextKey() string {
 	return c.ViewDataContextKey
 }
 
+// GetViewBufferSize returns the number of bytes of a rendered template
+// which are buffered before streamed to the client.
+func (c Configuration) GetViewBufferSize() int {
+	return c.ViewBufferSize
+}
+
 // GetRemoteAddrHeaders returns the allowed request headers names
 // that can be valid to parse the client's IP based on.
 // By-default no "X-" header is consired safe to be used for retrieving the
@@ -1108,6 +1241,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.FireMethodNotAllowed = v
 		}
 
+		if v := c.RouteCacheSize; v > 0 {
+			main.RouteCacheSize = v
+		}
+
 		if v := c.DisableBodyConsumptionOnUnmarshal; v {
 			main.DisableBodyConsumptionOnUnmarshal = v
 		}
@@ -1116,6 +1253,18 @@ func WithConfiguration(c Configuration) Configurator {
 			main.DisableAutoFireStatusCode = v
 		}
 
+		if v := c.EnableDebugErrorPages; v {
+			main.EnableDebugErrorPages = v
+		}
+
+		if v := c.EnableRouteStats; v {
+			main.EnableRouteStats = v
+		}
+
+		if v := c.RequestHardening; v.Enabled() {
+			main.RequestHardening = v
+		}
+
 		if v := c.TimeFormat; v != "" {
 			main.TimeFormat = v
 		}
@@ -1128,6 +1277,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.PostMaxMemory = v
 		}
 
+		if v := c.RecordRequestBodyLimit; v > 0 {
+			main.RecordRequestBodyLimit = v
+		}
+
 		if v := c.LocaleContextKey; v != "" {
 			main.LocaleContextKey = v
 		}
@@ -1140,6 +1293,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.ViewDataContextKey = v
 		}
 
+		if v := c.ViewBufferSize; v != 0 {
+			main.ViewBufferSize = v
+		}
+
 		if v := c.RemoteAddrHeaders; len(v) > 0 {
 			if main.RemoteAddrHeaders == nil {
 				main.RemoteAddrHeaders = make(map[string]bool, len(v))
@@ -1175,6 +1332,8 @@ func DefaultConfiguration() Configuration {
 		FireMethodNotAllowed:              false,
 		DisableBodyConsumptionOnUnmarshal: false,
 		DisableAutoFireStatusCode:         false,
+		EnableDebugErrorPages:             false,
+		EnableRouteStats:                  false,
 		TimeFormat:                        "Mon, 02 Jan 2006 15:04:05 GMT",
 		Charset:                           "utf-8",
 
@@ -1184,9 +1343,11 @@ func DefaultConfiguration() Configuration {
 		// can be set by the middleware `LimitRequestBodySize`
 		// or `context#SetMaxRequestBodySize`.
 		PostMaxMemory:            32 << 20, // 32MB
+		RecordRequestBodyLimit:   32 << 20, // 32MB
 		LocaleContextKey:         "iris.locale",
 		ViewLayoutContextKey:     "iris.viewLayout",
 		ViewDataContextKey:       "iris.viewData",
+		ViewBufferSize:           32 << 10, // 32KB
 		RemoteAddrHeaders:        make(map[string]bool),
 		RemoteAddrPrivateSubnets: []netutil.IPRange{},
 		EnableOptimizations:      false,
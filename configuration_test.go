@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kataras/iris/v12/config"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -326,3 +328,65 @@ Charset = "utf-8"
 		t.Fatalf("error on TestConfigurationTOML: Expected Other['MyServerName'] %s but got %s", expected, got)
 	}
 }
+
+func TestConfigurationProfile(t *testing.T) {
+	os.Setenv("IRIS_TEST_ENABLEROUTESTATS", "true")
+	os.Setenv("IRIS_TEST_PROFILE__LOGLEVEL", "error")
+	os.Setenv("IRIS_TEST_PROFILE__IDLETIMEOUT", "5m")
+	defer func() {
+		os.Unsetenv("IRIS_TEST_ENABLEROUTESTATS")
+		os.Unsetenv("IRIS_TEST_PROFILE__LOGLEVEL")
+		os.Unsetenv("IRIS_TEST_PROFILE__IDLETIMEOUT")
+	}()
+
+	app := New().Configure(WithProfile("production", config.Map(map[string]interface{}{
+		"charset": "ISO-8859-1",
+	}), config.Env("IRIS_TEST")))
+
+	dump := app.ConfigDump()
+	if expected, got := "production", dump.Profile; expected != got {
+		t.Fatalf("expected profile: %q but got: %q", expected, got)
+	}
+
+	if expected, got := "error", dump.LogLevel; expected != got {
+		t.Fatalf("expected log level: %q but got: %q", expected, got)
+	}
+
+	if expected, got := 5*time.Minute, dump.Settings.IdleTimeout; expected != got {
+		t.Fatalf("expected idle timeout: %s but got: %s", expected, got)
+	}
+
+	if expected, got := 10*time.Second, dump.Settings.ReadHeaderTimeout; expected != got {
+		t.Fatalf("expected read header timeout: %s but got: %s", expected, got)
+	}
+
+	c := dump.Configuration
+	if !c.EnableOptimizations || !c.EnableRouteStats || c.EnableDebugErrorPages || c.Charset != "ISO-8859-1" {
+		t.Fatalf("unexpected effective configuration: %#+v", c)
+	}
+
+	if dump.String() == "" {
+		t.Fatal("expected the dump's representation")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected a panic on unknown profile")
+		}
+	}()
+	New().Configure(WithProfile("unknown"))
+}
+
+func TestWithProfileStaging(t *testing.T) {
+	// the detailed error pages are not enabled on the, usually reachable, staging servers.
+	if c := New().Configure(WithProfile("staging")).ConfigDump().Configuration; c.EnableDebugErrorPages {
+		t.Fatalf("expected the debug error pages to be disabled on staging")
+	}
+
+	app := New().Configure(WithProfile("staging", config.Map(map[string]interface{}{
+		"profile": map[string]interface{}{"debugErrorPages": true},
+	})))
+	if c := app.ConfigDump().Configuration; !c.EnableDebugErrorPages {
+		t.Fatalf("expected the debug error pages to be enabled through the overrides")
+	}
+}
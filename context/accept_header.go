@@ -0,0 +1,136 @@
+package context
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AcceptHeaderKey is the header key of "Accept".
+	AcceptHeaderKey = "Accept"
+	// AcceptCharsetHeaderKey is the header key of "Accept-Charset".
+	AcceptCharsetHeaderKey = "Accept-Charset"
+	// AcceptLanguageHeaderKey is the header key of "Accept-Language".
+	AcceptLanguageHeaderKey = "Accept-Language"
+)
+
+// AcceptValue is a value of an "Accept" request header, e.g. "text/html;q=0.9".
+type AcceptValue struct {
+	// Value is the media type, the charset, the encoding or the language, e.g. "text/html".
+	Value string
+	// Quality is the "q" parameter of the value, defaults to 1.
+	Quality float64
+}
+
+// AcceptHeader is a parsed "Accept", "Accept-Charset", "Accept-Encoding" or "Accept-Language"
+// request header. Its values are sorted by quality,
+// the ones of the same quality keep the client's order.
+//
+// See `Context.AcceptHeader`.
+type AcceptHeader []AcceptValue
+
+// ParseAcceptHeader parses an "Accept" request header's value, see `AcceptHeader`.
+func ParseAcceptHeader(headerValue string) AcceptHeader {
+	if headerValue == "" {
+		return nil
+	}
+
+	var h AcceptHeader
+	for _, part := range strings.Split(headerValue, ",") {
+		v := AcceptValue{Quality: 1}
+
+		params := ""
+		if idx := strings.IndexByte(part, ';'); idx != -1 {
+			part, params = part[:idx], part[idx+1:]
+		}
+
+		v.Value = strings.TrimSpace(part)
+		if v.Value == "" {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			param = strings.TrimSpace(param)
+			if len(param) > 2 && (param[0] == 'q' || param[0] == 'Q') && param[1] == '=' {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					v.Quality = q
+				}
+			}
+		}
+
+		h = append(h, v)
+	}
+
+	sort.SliceStable(h, func(i, j int) bool {
+		return h[i].Quality > h[j].Quality
+	})
+
+	return h
+}
+
+// Values returns the accepted values, by preference.
+// The not acceptable ones ("q=0") are omitted.
+func (h AcceptHeader) Values() []string {
+	values := make([]string, 0, len(h))
+	for _, v := range h {
+		if v.Quality > 0 {
+			values = append(values, v.Value)
+		}
+	}
+
+	return values
+}
+
+// Accepts reports whether the "value" (case-insensitive) is accepted,
+// explicitly or through the "*" wildcard.
+// Media type wildcards, e.g. "text/*", are not checked.
+func (h AcceptHeader) Accepts(value string) bool {
+	wildcard := false
+	for _, v := range h {
+		if strings.EqualFold(v.Value, value) {
+			return v.Quality > 0
+		}
+
+		if v.Value == "*" {
+			wildcard = v.Quality > 0
+		}
+	}
+
+	return wildcard
+}
+
+type acceptHeaderEntry struct {
+	raw    string
+	header AcceptHeader
+}
+
+var acceptHeaderContextKeys = map[string]string{
+	AcceptHeaderKey:         "iris.accept",
+	AcceptCharsetHeaderKey:  "iris.accept_charset",
+	AcceptEncodingHeaderKey: "iris.accept_encoding",
+	AcceptLanguageHeaderKey: "iris.accept_language",
+}
+
+// AcceptHeader returns the parsed request header of the "key",
+// i.e. "Accept", "Accept-Charset", "Accept-Encoding" or "Accept-Language".
+// The header is parsed once per request, on first use, and it is kept to the context's values,
+// so the negotiation, the compression and the i18n features do not parse the same header again.
+func (ctx *context) AcceptHeader(key string) AcceptHeader {
+	contextKey, ok := acceptHeaderContextKeys[key]
+	if !ok {
+		contextKey = "iris.accept." + key
+	}
+
+	raw := ctx.GetHeader(key)
+	if v := ctx.values.Get(contextKey); v != nil {
+		// the header may be modified by a middleware, i.e. the i18n's URL path language.
+		if entry, ok := v.(*acceptHeaderEntry); ok && entry.raw == raw {
+			return entry.header
+		}
+	}
+
+	h := ParseAcceptHeader(raw)
+	ctx.values.Set(contextKey, &acceptHeaderEntry{raw: raw, header: h})
+	return h
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/kataras/iris/v12/core/logging"
+
 	"github.com/kataras/golog"
 )
 
@@ -17,6 +19,9 @@ type Application interface {
 
 	// Logger returns the golog logger instance(pointer) that is being used inside the "app".
 	Logger() *golog.Logger
+	// Log returns the structured logger of the "app",
+	// defaults to a logger which writes to the `Logger`.
+	Log() logging.Logger
 
 	// I18nReadOnly returns the i18n's read-only features.
 	I18nReadOnly() I18nReadOnly
@@ -25,6 +30,10 @@ type Application interface {
 	// the failure reason if not.
 	Validate(interface{}) error
 
+	// GetJSONCodec returns the `JSONCodec` of the `Context.JSON`,
+	// nil means the default one.
+	GetJSONCodec() JSONCodec
+
 	// View executes and write the result of a template file to the writer.
 	//
 	// Use context.View to render templates to the client instead.
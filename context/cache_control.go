@@ -0,0 +1,143 @@
+package context
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ExpiresHeaderKey is the header key of "Expires".
+	ExpiresHeaderKey = "Expires"
+	// SurrogateControlHeaderKey is the header key of "Surrogate-Control",
+	// the Cache-Control of the CDNs and the reverse proxies.
+	SurrogateControlHeaderKey = "Surrogate-Control"
+)
+
+type (
+	// CacheControl holds the directives of the Cache-Control, Expires and Surrogate-Control
+	// response headers, see `Context.CacheControl` and the cache package's options.
+	// The durations are ignored when they are negative.
+	CacheControl struct {
+		Public          bool
+		Private         bool
+		NoCache         bool
+		NoStore         bool
+		MustRevalidate  bool
+		ProxyRevalidate bool
+		NoTransform     bool
+		Immutable       bool
+
+		MaxAge               time.Duration
+		SharedMaxAge         time.Duration
+		StaleWhileRevalidate time.Duration
+		StaleIfError         time.Duration
+		// SurrogateMaxAge is the "max-age" of the Surrogate-Control header.
+		SurrogateMaxAge time.Duration
+	}
+
+	// CacheControlOption sets a directive of the CacheControl.
+	CacheControlOption func(*CacheControl)
+)
+
+// NewCacheControl returns a CacheControl with the "options" applied.
+func NewCacheControl(options ...CacheControlOption) *CacheControl {
+	c := &CacheControl{
+		MaxAge:               -1,
+		SharedMaxAge:         -1,
+		StaleWhileRevalidate: -1,
+		StaleIfError:         -1,
+		SurrogateMaxAge:      -1,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+func writeSeconds(b *strings.Builder, directive string, d time.Duration) {
+	if d < 0 {
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteString(", ")
+	}
+
+	b.WriteString(directive)
+	b.WriteByte('=')
+	b.WriteString(strconv.FormatInt(int64(d/time.Second), 10))
+}
+
+func writeDirective(b *strings.Builder, directive string, ok bool) {
+	if !ok {
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteString(", ")
+	}
+
+	b.WriteString(directive)
+}
+
+// String returns the value of the Cache-Control header.
+// The contradicting directives are omitted: "no-store" is written alone,
+// "private" wins over "public" and the shared caches' directives are omitted from the private responses.
+func (c *CacheControl) String() string {
+	if c.NoStore {
+		return "no-store"
+	}
+
+	var b strings.Builder
+	writeDirective(&b, "private", c.Private)
+	writeDirective(&b, "public", c.Public && !c.Private)
+	writeDirective(&b, "no-cache", c.NoCache)
+	writeSeconds(&b, "max-age", c.MaxAge)
+	if !c.Private {
+		writeSeconds(&b, "s-maxage", c.SharedMaxAge)
+	}
+	writeDirective(&b, "must-revalidate", c.MustRevalidate)
+	writeDirective(&b, "proxy-revalidate", c.ProxyRevalidate && !c.Private)
+	writeDirective(&b, "no-transform", c.NoTransform)
+	writeDirective(&b, "immutable", c.Immutable && !c.NoCache)
+	writeSeconds(&b, "stale-while-revalidate", c.StaleWhileRevalidate)
+	writeSeconds(&b, "stale-if-error", c.StaleIfError)
+
+	return b.String()
+}
+
+// Apply sets the Cache-Control, Expires and Surrogate-Control headers of the "header".
+// The Expires header is set for the clients which do not support the Cache-Control,
+// to the "now" plus the max age, or to "0" when the response must not be reused without revalidation.
+func (c *CacheControl) Apply(header http.Header, now time.Time) {
+	header.Set(CacheControlHeaderKey, c.String())
+
+	switch {
+	case c.NoStore || c.NoCache || c.MaxAge == 0:
+		header.Set(ExpiresHeaderKey, "0")
+	case c.MaxAge > 0:
+		header.Set(ExpiresHeaderKey, now.Add(c.MaxAge).UTC().Format(http.TimeFormat))
+	default:
+		header.Del(ExpiresHeaderKey)
+	}
+
+	switch {
+	case c.NoStore || c.Private:
+		header.Set(SurrogateControlHeaderKey, "no-store")
+	case c.SurrogateMaxAge >= 0:
+		header.Set(SurrogateControlHeaderKey, "max-age="+strconv.FormatInt(int64(c.SurrogateMaxAge/time.Second), 10))
+	default:
+		header.Del(SurrogateControlHeaderKey)
+	}
+}
+
+// CacheControl sets the Cache-Control, Expires and Surrogate-Control response headers, i.e
+//  ctx.CacheControl(cache.Public, cache.MaxAge(5*time.Minute), cache.SWR(30*time.Second))
+// A call overrides the headers of any previous call, e.g. the `Party.SetCacheHeaders` defaults.
+func (ctx *context) CacheControl(options ...CacheControlOption) {
+	NewCacheControl(options...).Apply(ctx.writer.Header(), time.Now())
+}
@@ -43,6 +43,8 @@ type ConfigurationReadOnly interface {
 
 	// GetFireMethodNotAllowed returns the configuration.FireMethodNotAllowed.
 	GetFireMethodNotAllowed() bool
+	// GetRouteCacheSize returns the configuration.RouteCacheSize.
+	GetRouteCacheSize() int
 	// GetDisableBodyConsumptionOnUnmarshal returns the configuration.GetDisableBodyConsumptionOnUnmarshal,
 	// manages the reading behavior of the context's body readers/binders.
 	// If returns true then the body consumption by the `context.UnmarshalBody/ReadJSON/ReadXML`
@@ -57,6 +59,12 @@ type ConfigurationReadOnly interface {
 	// GetDisableAutoFireStatusCode returns the configuration.DisableAutoFireStatusCode.
 	// Returns true when the http error status code handler automatic execution turned off.
 	GetDisableAutoFireStatusCode() bool
+	// GetEnableDebugErrorPages returns the configuration.EnableDebugErrorPages.
+	// Returns true when the default 5xx error handlers render the developer error pages.
+	GetEnableDebugErrorPages() bool
+	// GetEnableRouteStats returns the configuration.EnableRouteStats.
+	// Returns true when the per-route statistics collector is enabled.
+	GetEnableRouteStats() bool
 
 	// GetTimeFormat returns the configuration.TimeFormat,
 	// format for any kind of datetime parsing.
@@ -74,6 +82,9 @@ type ConfigurationReadOnly interface {
 	//
 	// Defaults to 32MB or 32 << 20 if you prefer.
 	GetPostMaxMemory() int64
+	// GetRecordRequestBodyLimit returns the maximum size of a request body
+	// which is recorded by the `Context.RecordRequestBody`.
+	GetRecordRequestBodyLimit() int64
 
 	// GetTranslateLanguageContextKey returns the configuration's LocaleContextKey value,
 	// used for i18n. Defaults to "iris.locale".
@@ -88,6 +99,9 @@ type ConfigurationReadOnly interface {
 	// which is being used to set the template
 	// binding data from a middleware or the main handler.
 	GetViewDataContextKey() string
+	// GetViewBufferSize returns the number of bytes of a rendered template
+	// which are buffered before streamed to the client.
+	GetViewBufferSize() int
 
 	// GetRemoteAddrHeaders returns the allowed request headers names
 	// that can be valid to parse the client's IP based on.
@@ -6,6 +6,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"io/ioutil"
 	"mime"
@@ -24,16 +25,15 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/kataras/iris/v12/core/cbor"
 	"github.com/kataras/iris/v12/core/memstore"
 	"github.com/kataras/iris/v12/core/netutil"
 
 	"github.com/Shopify/goreferrer"
 	"github.com/fatih/structs"
 	"github.com/golang/protobuf/proto"
-	"github.com/iris-contrib/blackfriday"
 	"github.com/iris-contrib/schema"
 	jsoniter "github.com/json-iterator/go"
-	"github.com/microcosm-cc/bluemonday"
 	"github.com/vmihailenco/msgpack/v5"
 	"gopkg.in/yaml.v3"
 )
@@ -135,6 +135,14 @@ type Context interface {
 	// stdCtx := context.WithValue(r.Context(), key, val)
 	// ctx.ResetRequest(r.WithContext(stdCtx)).
 	ResetRequest(r *http.Request)
+	// Detach returns a snapshot of the Context which is safe to be used after the handler returned,
+	// i.e inside a goroutine started by the handler. The Context itself is released to the pool
+	// and reused by another request right after its handlers are executed, the snapshot is not.
+	//
+	// The snapshot holds a copy of the values, the path parameters and the current route,
+	// and a clone of the request with its body already read and a context.Background() as its standard context.
+	// Its response writes are dropped.
+	Detach() Context
 
 	// SetCurrentRouteName sets the route's name internally,
 	// in order to be able to find the correct current "read-only" Route when
@@ -149,6 +157,13 @@ type Context interface {
 	// GetCurrentRoute returns the current registered "read-only" route that
 	// was being registered to this request's path.
 	GetCurrentRoute() RouteReadOnly
+	// RouteMetadata returns the custom information attached to the current route
+	// through its `SetMetadata` method, if any. It can be used by generic middleware
+	// (e.g. authentication, caching or rate limiting) to configure itself per route:
+	//  app.Get("/health", health).SetMetadata("auth", "none")
+	//  // [...] inside the auth middleware:
+	//  if ctx.RouteMetadata()["auth"] == "none" { ctx.Next(); return }
+	RouteMetadata() Map
 
 	// Do calls the SetHandlers(handlers)
 	// and executes the first handler,
@@ -332,6 +347,12 @@ type Context interface {
 	// You can use this function to Set and Get local values
 	// that can be used to share information between handlers and middleware.
 	Values() *memstore.Store
+	// SetErr stores the "err" for the rest of the handlers of this request,
+	// e.g. the error code handlers. It can be retrieved through `GetErr`.
+	// A nil "err" clears the stored error.
+	SetErr(err error)
+	// GetErr returns the error stored by `SetErr`, if any.
+	GetErr() error
 
 	//  +------------------------------------------------------------+
 	//  | Path, Host, Subdomain, IP, Headers, Localization etc...    |
@@ -414,6 +435,18 @@ type Context interface {
 	// See `GetLocale` too.
 	// Example: https://github.com/kataras/iris/tree/master/_examples/i18n
 	Tr(format string, args ...interface{}) string
+	// SetLocale sets the "locale" as the current request's locale,
+	// it overrides the one found by the i18n middleware, see `GetLocale`.
+	SetLocale(locale Locale)
+	// SetTimezone sets the current request's time zone, e.g. the user's preference,
+	// see `GetTimezone` and `Formatter`.
+	SetTimezone(loc *time.Location)
+	// GetTimezone returns the current request's time zone, see `SetTimezone`.
+	// Defaults to the server's local time zone.
+	GetTimezone() *time.Location
+	// Formatter returns a `Formatter` of the current request's locale and time zone,
+	// which formats dates and numbers based on the user's preferences.
+	Formatter() Formatter
 
 	//  +------------------------------------------------------------+
 	//  | Headers helpers                                            |
@@ -421,6 +454,23 @@ type Context interface {
 
 	// Header adds a header to the response writer.
 	Header(name string, value string)
+	// SetTrailer declares the trailers of the "names", which their values
+	// are sent after the response body through `WriteTrailer`.
+	// It should be called before the first write of the response body.
+	SetTrailer(names ...string)
+	// WriteTrailer sets the value of the trailer of the "name",
+	// it can be called at any time, even after the response body was written or flushed.
+	// The trailer should be declared through `SetTrailer`, so the client knows it
+	// and the response is sent chunked, otherwise the trailer may be not sent at all.
+	WriteTrailer(name string, value string)
+	// DigestWriter declares the trailer of the "trailerName" ("Digest" if empty)
+	// and returns a writer of the response body which computes its SHA-256 digest,
+	// the digest is sent as the trailer's value at the end of the request, i.e
+	//  io.Copy(ctx.DigestWriter(""), file)
+	// sends the "Digest: SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=" trailer.
+	// Useful for streamed downloads, which their size or checksum is not known before.
+	// Only the body written through the returned writer is digested.
+	DigestWriter(trailerName string) io.Writer
 
 	// ContentType sets the response writer's header key "Content-Type" to the 'cType'.
 	ContentType(cType string)
@@ -599,6 +649,23 @@ type Context interface {
 	//
 	// Example: https://github.com/kataras/iris/tree/master/_examples/http_request/upload-files
 	UploadFormFiles(destDirectory string, before ...func(Context, *multipart.FileHeader)) (n int64, err error)
+	// ReadMultipartStream reads a multipart/form-data request body part by part, as it arrives,
+	// and fires the "handlers" for each form field and file.
+	// Files are not stored in memory or in temporary files, so it can be used to
+	// handle very large uploads, i.e. stream them to a cloud storage.
+	// A part which exceeds its maximum size returns the `ErrMultipartPartTooLarge` error.
+	// A handler's non-nil error stops the reading and it is returned as it's.
+	//
+	// Example Code:
+	//  err := ctx.ReadMultipartStream(iris.MultipartStreamHandlers{
+	//      MaxFileSize: 10 << 30,
+	//      Field: func(name, value string) error { [...] },
+	//      File: func(part *multipart.Part, r io.Reader) error {
+	//          _, err := io.Copy(dst, r)
+	//          return err
+	//      },
+	//  })
+	ReadMultipartStream(handlers MultipartStreamHandlers) error
 
 	//  +------------------------------------------------------------+
 	//  | Custom HTTP Errors                                         |
@@ -626,6 +693,13 @@ type Context interface {
 	//
 	// However, whenever you can use the `ctx.Request().Body` instead.
 	GetBody() ([]byte, error)
+	// RecordRequestBody enables or disables the recording of the request body.
+	// When enabled, the body is read once, on the first `GetBody` call, and kept in memory
+	// so the next `GetBody`, `ReadJSON`, `ReadXML` and e.t.c. calls and the `Request().Body`
+	// replay it, e.g. a middleware verifies the body's signature and the handler binds it.
+	// The body is kept as it's, binary-safe, up to the `Configuration.RecordRequestBodyLimit`.
+	// Nothing is read or allocated until the body is used.
+	RecordRequestBody(record bool)
 	// UnmarshalBody reads the request's body and binds it to a value or pointer of any type.
 	// Examples of usage: context.ReadJSON, context.ReadXML.
 	//
@@ -636,9 +710,19 @@ type Context interface {
 	// However you are still free to read the `ctx.Request().Body io.Reader` manually.
 	UnmarshalBody(outPtr interface{}, unmarshaler Unmarshaler) error
 	// ReadJSON reads JSON from request's body and binds it to a pointer of a value of any json-valid type.
+	// The Party's options, if any, are used, see `Party.SetReadJSONOptions` and `ReadJSONWithOptions`.
 	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-json/main.go
 	ReadJSON(jsonObjectPtr interface{}) error
+	// ReadJSONWithOptions same as `ReadJSON` but it reads the body based on the "opts",
+	// which can make it strict, e.g. disallow unknown fields and limit the body's size.
+	// Its errors are `*ReadJSONError` values which name the offending field.
+	ReadJSONWithOptions(jsonObjectPtr interface{}, opts ReadJSONOptions) error
+	// ReadJSONStream reads a JSON array from the request's body element by element,
+	// without loading the whole body into memory. The "onElement" is called for each element
+	// and it should decode it, i.e. `dec.Decode(&item)`.
+	// The optional "opts" limit the number of the elements and the body's size.
+	ReadJSONStream(onElement func(dec *json.Decoder) error, opts ...JSONStreamOptions) error
 	// ReadXML reads XML from request's body and binds it to a pointer of a value of any xml-valid type.
 	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-xml/main.go
@@ -658,14 +742,22 @@ type Context interface {
 	//
 	// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-query/main.go
 	ReadQuery(ptr interface{}) error
+	// ReadHeaders binds request headers to "ptr". The struct field tag is "header",
+	// e.g. `header:"X-Tenant-ID"`. Values are converted to the field's type.
+	ReadHeaders(ptr interface{}) error
+	// ReadParams binds the route's path parameters to "ptr". The struct field tag is "param",
+	// e.g. `param:"id"`. Values are converted to the field's type.
+	ReadParams(ptr interface{}) error
 	// ReadProtobuf binds the body to the "ptr" of a proto Message and returns any error.
 	ReadProtobuf(ptr proto.Message) error
 	// ReadMsgPack binds the request body of msgpack format to the "ptr" and returns any error.
 	ReadMsgPack(ptr interface{}) error
+	// ReadCBOR binds the request body of CBOR (RFC 8949) format to the "ptr" and returns any error.
+	ReadCBOR(ptr interface{}) error
 	// ReadBody binds the request body to the "ptr" depending on the HTTP Method and the Request's Content-Type.
 	// If a GET method request then it reads from a form (or URL Query), otherwise
 	// it tries to match (depending on the request content-type) the data format e.g.
-	// JSON, Protobuf, MsgPack, XML, YAML, MultipartForm and binds the result to the "ptr".
+	// JSON, Protobuf, MsgPack, CBOR, XML, YAML, MultipartForm and binds the result to the "ptr".
 	ReadBody(ptr interface{}) error
 
 	//  +------------------------------------------------------------+
@@ -737,6 +829,15 @@ type Context interface {
 	// based on the "modtime" input argument,
 	// otherwise sends a 304 status code in order to let the client-side render the cached content.
 	WriteWithExpiration(body []byte, modtime time.Time) (int, error)
+	// CheckIfMatch reports whether the "If-Match" request header, if any,
+	// matches the current "etag" of the resource (strong comparison).
+	// If it does not then it sends a 412 "Precondition Failed" status code,
+	// stops the handlers chain and returns false.
+	// A missing "If-Match" header or a "*" value always passes.
+	//
+	// Useful to prevent lost updates on REST update endpoints (optimistic concurrency), e.g.
+	// if !ctx.CheckIfMatch(ETag(current)) { return }
+	CheckIfMatch(etag string) bool
 	// StreamWriter registers the given stream writer for populating
 	// response body.
 	//
@@ -752,6 +853,20 @@ type Context interface {
 	// receives a function which receives the response writer
 	// and returns false when it should stop writing, otherwise true in order to continue
 	StreamWriter(writer func(w io.Writer) bool)
+	// MultipartStream sets the "multipart/x-mixed-replace" content type
+	// and returns the writer of its parts, each part replaces the previous one on the client,
+	// e.g. the frames of a MJPEG camera stream or the progressive results of a long task.
+	// A random boundary is used when "boundary" is empty.
+	// It returns a nil stream and an error on invalid boundary.
+	//
+	// Example:
+	//  stream, err := ctx.MultipartStream("")
+	//  for frame := range frames {
+	//      if err = stream.WritePart("image/jpeg", frame); err != nil {
+	//          return // client is gone.
+	//      }
+	//  }
+	MultipartStream(boundary string) (*MultipartStream, error)
 
 	//  +------------------------------------------------------------+
 	//  | Body Writers with compression                              |
@@ -844,6 +959,11 @@ type Context interface {
 	HTML(format string, args ...interface{}) (int, error)
 	// JSON marshals the given interface object and writes the JSON response.
 	JSON(v interface{}, options ...JSON) (int, error)
+	// WriteJSONWithETag works like `JSON` but it computes and sends a strong "ETag"
+	// header based on the marshaled body. On GET and HEAD requests it sends
+	// a 304 "Not Modified" instead when the "If-None-Match" request header matches that "ETag".
+	// See `CheckIfMatch` too.
+	WriteJSONWithETag(v interface{}, options ...JSON) (int, error)
 	// JSONP marshals the given interface object and writes the JSON response.
 	JSONP(v interface{}, options ...JSONP) (int, error)
 	// XML marshals the given interface object and writes the XML response.
@@ -869,12 +989,19 @@ type Context interface {
 	Protobuf(v proto.Message) (int, error)
 	// MsgPack parses the "v" of msgpack format and renders its result to the client.
 	MsgPack(v interface{}) (int, error)
+	// CBOR parses the "v" of CBOR (RFC 8949) format and renders its result to the client.
+	CBOR(v interface{}) (int, error)
 
 	//  +-----------------------------------------------------------------------+
 	//  | Content Νegotiation                                                   |
 	//  | https://developer.mozilla.org/en-US/docs/Web/HTTP/Content_negotiation |                                       |
 	//  +-----------------------------------------------------------------------+
 
+	// AcceptHeader returns the parsed request header of the "key",
+	// i.e. "Accept", "Accept-Charset", "Accept-Encoding" or "Accept-Language".
+	// The header is parsed once per request, on first use, and it is kept to the context's values,
+	// so the negotiation, the compression and the i18n features do not parse the same header again.
+	AcceptHeader(key string) AcceptHeader
 	// Negotiation creates once and returns the negotiation builder
 	// to build server-side available content for specific mime type(s)
 	// and charset(s).
@@ -922,8 +1049,7 @@ type Context interface {
 	//
 	// You can define your own "Content-Type" with `context#ContentType`, before this function call.
 	//
-	// This function doesn't support resuming (by range),
-	// use ctx.SendFile or router's `HandleDir` instead.
+	// Resuming (by range) is supported when the content is not compressed.
 	ServeContent(content io.ReadSeeker, filename string, modtime time.Time, gzipCompression bool) error
 	// ServeFile serves a file (to send a file, a zip for example to the client you should use the `SendFile` instead)
 	// receives two parameters
@@ -932,8 +1058,7 @@ type Context interface {
 	//
 	// You can define your own "Content-Type" with `context#ContentType`, before this function call.
 	//
-	// This function doesn't support resuming (by range),
-	// use ctx.SendFile or router's `HandleDir` instead.
+	// Resuming (by range) is supported when "gzipCompression" is false.
 	//
 	// Use it when you want to serve dynamic files to the client.
 	ServeFile(filename string, gzipCompression bool) error
@@ -1002,6 +1127,9 @@ type Context interface {
 	// seconds as int64
 	// if header not found or parse failed then it returns -1.
 	MaxAge() int64
+	// CacheControl sets the Cache-Control, Expires and Surrogate-Control response headers
+	// from the "options", see the cache package's `Public`, `MaxAge`, `SWR` and e.t.c.
+	CacheControl(options ...CacheControlOption)
 
 	//  +------------------------------------------------------------+
 	//  | Advanced: Response Recorder and Transactions               |
@@ -1019,6 +1147,19 @@ type Context interface {
 	// else returns nil and false.
 	IsRecording() (*ResponseRecorder, bool)
 
+	// Timeline returns the timeline of the request, if enabled through `SetTimeline`,
+	// e.g. by the iris/middleware/timeline, otherwise nil.
+	// A nil timeline can be used too, its methods do nothing, i.e
+	//  defer ctx.Timeline().Begin("db")()
+	Timeline() *Timeline
+	// SetTimeline enables the timeline of the request, see `Timeline`.
+	SetTimeline(t *Timeline)
+	// ServerTiming returns the request's metrics of the Server-Timing response header, i.e
+	//  ctx.ServerTiming().Add("db", time.Since(start), "users query")
+	// The header is sent at the end of the request, if the response headers were already sent,
+	// i.e after a partial write or a flush when streaming, the metrics are sent as a trailer instead.
+	ServerTiming() *ServerTiming
+
 	// BeginTransaction starts a scoped transaction.
 	//
 	// You can search third-party articles or books on how Business Transaction works (it's quite simple, especially here).
@@ -1152,6 +1293,14 @@ type context struct {
 	handlers Handlers
 	// the current position of the handler's chain
 	currentHandlerIndex int
+	// the request's timeline, nil if not enabled.
+	timeline *Timeline
+	// the request's Server-Timing metrics, nil if not used.
+	serverTiming *ServerTiming
+	digest       *digestWriter
+	// the request body recording, see `RecordRequestBody`.
+	recordRequestBody bool
+	requestBody       []byte // nil until the first `GetBody` call.
 }
 
 // NewContext returns the default, internal, context implementation.
@@ -1180,6 +1329,11 @@ func (ctx *context) BeginRequest(w http.ResponseWriter, r *http.Request) {
 	ctx.request = r
 	ctx.currentHandlerIndex = 0
 	ctx.deferFunc = nil
+	ctx.timeline = nil
+	ctx.serverTiming = nil
+	ctx.digest = nil
+	ctx.recordRequestBody = false
+	ctx.requestBody = nil
 	ctx.writer = AcquireResponseWriter()
 	ctx.writer.BeginResponse(w)
 }
@@ -1235,6 +1389,14 @@ func (ctx *context) EndRequest() {
 		}
 	}
 
+	if ctx.serverTiming != nil {
+		ctx.serverTiming.end(ctx.writer)
+	}
+
+	if ctx.digest != nil {
+		ctx.WriteTrailer(ctx.digest.trailer, ctx.digest.value())
+	}
+
 	ctx.writer.FlushResponse()
 	ctx.writer.EndResponse()
 }
@@ -1295,6 +1457,18 @@ func (ctx *context) GetCurrentRoute() RouteReadOnly {
 	return ctx.app.GetRouteReadOnly(ctx.currentRouteName)
 }
 
+// RouteMetadata returns the custom information attached to the current route
+// through its `SetMetadata` method, if any. It can be used by generic middleware
+// (e.g. authentication, caching or rate limiting) to configure itself per route.
+// The returned map should not be modified.
+func (ctx *context) RouteMetadata() Map {
+	if route := ctx.GetCurrentRoute(); route != nil {
+		return route.GetMetadata()
+	}
+
+	return nil
+}
+
 // Do calls the SetHandlers(handlers)
 // and executes the first handler,
 // handlers should not be empty.
@@ -1423,6 +1597,12 @@ var Next = DefaultNext
 // or by just override the `context.Next` package-level field, `context.DefaultNext` is exported
 // in order to be able for developers to merge your customized version one with the default behavior as well.
 func DefaultNext(ctx Context) {
+	if c, ok := ctx.(*context); ok {
+		// fast path, no need of the interface's method calls.
+		c.next()
+		return
+	}
+
 	if ctx.IsStopped() {
 		return
 	}
@@ -1432,6 +1612,17 @@ func DefaultNext(ctx Context) {
 	}
 }
 
+func (ctx *context) next() {
+	if ctx.IsStopped() {
+		return
+	}
+
+	if n := ctx.currentHandlerIndex + 1; n < len(ctx.handlers) {
+		ctx.currentHandlerIndex = n
+		ctx.handlers[n](ctx)
+	}
+}
+
 // Next calls all the next handler from the handlers chain,
 // it should be used inside a middleware.
 //
@@ -1656,6 +1847,31 @@ func (ctx *context) Values() *memstore.Store {
 	return &ctx.values
 }
 
+const errorContextKey = "iris.context.error"
+
+// SetErr stores the "err" for the rest of the handlers of this request,
+// e.g. the error code handlers. It can be retrieved through `GetErr`.
+// A nil "err" clears the stored error.
+func (ctx *context) SetErr(err error) {
+	if err == nil {
+		ctx.values.Remove(errorContextKey)
+		return
+	}
+
+	ctx.values.Set(errorContextKey, err)
+}
+
+// GetErr returns the error stored by `SetErr`, if any.
+func (ctx *context) GetErr() error {
+	if v := ctx.values.Get(errorContextKey); v != nil {
+		if err, ok := v.(error); ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
 //  +------------------------------------------------------------+
 //  | Path, Host, Subdomain, IP, Headers etc...                  |
 //  +------------------------------------------------------------+
@@ -1992,6 +2208,44 @@ func (ctx *context) Header(name string, value string) {
 	ctx.writer.Header().Add(name, value)
 }
 
+// SetTrailer declares the trailers of the "names", which their values
+// are sent after the response body through `WriteTrailer`.
+// It should be called before the first write of the response body.
+func (ctx *context) SetTrailer(names ...string) {
+	h := ctx.writer.Header()
+	for _, name := range names {
+		h.Add(TrailerHeaderKey, http.CanonicalHeaderKey(name))
+	}
+}
+
+// WriteTrailer sets the value of the trailer of the "name",
+// it can be called at any time, even after the response body was written or flushed.
+// The trailer should be declared through `SetTrailer`, so the client knows it
+// and the response is sent chunked, otherwise the trailer may be not sent at all.
+func (ctx *context) WriteTrailer(name string, value string) {
+	ctx.writer.Header().Set(http.TrailerPrefix+name, value)
+}
+
+// DigestWriter declares the trailer of the "trailerName" ("Digest" if empty)
+// and returns a writer of the response body which computes its SHA-256 digest,
+// the digest is sent as the trailer's value at the end of the request, i.e
+//  io.Copy(ctx.DigestWriter(""), file)
+// sends the "Digest: SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=" trailer.
+// Useful for streamed downloads, which their size or checksum is not known before.
+// Only the body written through the returned writer is digested.
+func (ctx *context) DigestWriter(trailerName string) io.Writer {
+	if ctx.digest == nil {
+		if trailerName == "" {
+			trailerName = DigestHeaderKey
+		}
+
+		ctx.SetTrailer(trailerName)
+		ctx.digest = newDigestWriter(ctx.writer, trailerName)
+	}
+
+	return ctx.digest
+}
+
 const contentTypeContextKey = "_iris_content_type"
 
 func shouldAppendCharset(cType string) bool {
@@ -2589,6 +2843,124 @@ func uploadTo(fh *multipart.FileHeader, destDirectory string) (int64, error) {
 	return io.Copy(out, src)
 }
 
+// ErrMultipartPartTooLarge is returned from the `Context.ReadMultipartStream` method
+// when a form field or file exceeds its maximum size.
+var ErrMultipartPartTooLarge = errors.New("multipart: part too large")
+
+// MultipartStreamHandlers holds the callbacks and the limits of the `Context.ReadMultipartStream` method.
+type MultipartStreamHandlers struct {
+	// Field is fired for each form field part.
+	// Defaults to nil, fields are skipped.
+	Field func(name, value string) error
+	// File is fired for each file part, the "part" holds the form name, the filename and the headers
+	// and the "r" reads its contents, limited to the `MaxFileSize`.
+	// The unread contents are discarded when it returns.
+	// Defaults to nil, files are skipped.
+	File func(part *multipart.Part, r io.Reader) error
+	// MaxFieldSize is the maximum size of a form field's value.
+	// Defaults to 1MB.
+	MaxFieldSize int64
+	// MaxFileSize is the maximum size of a single file.
+	// Defaults to zero, unlimited.
+	MaxFileSize int64
+}
+
+// limitedPartReader reads up to "remaining" bytes,
+// reading more than that results to an `ErrMultipartPartTooLarge` error.
+type limitedPartReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedPartReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			l.exceeded = true
+			return 0, ErrMultipartPartTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// ReadMultipartStream reads a multipart/form-data request body part by part, as it arrives,
+// and fires the "handlers" for each form field and file.
+// Files are not stored in memory or in temporary files, so it can be used to
+// handle very large uploads, i.e. stream them to a cloud storage.
+// A part which exceeds its maximum size returns the `ErrMultipartPartTooLarge` error.
+// A handler's non-nil error stops the reading and it is returned as it's.
+func (ctx *context) ReadMultipartStream(handlers MultipartStreamHandlers) error {
+	mr, err := ctx.request.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	maxFieldSize := handlers.MaxFieldSize
+	if maxFieldSize <= 0 {
+		maxFieldSize = 1 << 20
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if part.FileName() == "" {
+			if handlers.Field == nil {
+				part.Close()
+				continue
+			}
+
+			lr := &limitedPartReader{r: part, remaining: maxFieldSize}
+			value, err := ioutil.ReadAll(lr)
+			part.Close()
+			if err != nil {
+				return err
+			}
+
+			if err = handlers.Field(part.FormName(), string(value)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if handlers.File == nil {
+			part.Close()
+			continue
+		}
+
+		var r io.Reader = part
+		lr := &limitedPartReader{r: part, remaining: handlers.MaxFileSize}
+		if handlers.MaxFileSize > 0 {
+			r = lr
+		}
+
+		err = handlers.File(part, r)
+		part.Close()
+		if err != nil {
+			return err
+		}
+
+		if lr.exceeded {
+			return ErrMultipartPartTooLarge
+		}
+	}
+}
+
 // AbsoluteURI parses the "s" and returns its absolute URI form.
 func (ctx *context) AbsoluteURI(s string) string {
 	if s == "" {
@@ -2684,15 +3056,77 @@ func (ctx *context) SetMaxRequestBodySize(limitOverBytes int64) {
 	ctx.request.Body = http.MaxBytesReader(ctx.writer, ctx.request.Body, limitOverBytes)
 }
 
+// ErrRequestBodyTooLarge is returned by the `GetBody` when a recorded request body
+// exceeds the `Configuration.RecordRequestBodyLimit`, see `RecordRequestBody`.
+var ErrRequestBodyTooLarge = errors.New("request body too large")
+
+// RecordRequestBody enables or disables the recording of the request body.
+// When enabled, the body is read once, on the first `GetBody` call, and kept in memory
+// so the next `GetBody`, `ReadJSON`, `ReadXML` and e.t.c. calls and the `Request().Body`
+// replay it, e.g. a middleware verifies the body's signature and the handler binds it.
+// The body is kept as it's, binary-safe, up to the `Configuration.RecordRequestBodyLimit`.
+// Nothing is read or allocated until the body is used.
+func (ctx *context) RecordRequestBody(record bool) {
+	ctx.recordRequestBody = record
+}
+
 // GetBody reads and returns the request body.
 // The default behavior for the http request reader is to consume the data readen
-// but you can change that behavior by passing the `WithoutBodyConsumptionOnUnmarshal` iris option.
+// but you can change that behavior by passing the `WithoutBodyConsumptionOnUnmarshal` iris option
+// or by the `RecordRequestBody`.
 //
 // However, whenever you can use the `ctx.Request().Body` instead.
 func (ctx *context) GetBody() ([]byte, error) {
+	if ctx.recordRequestBody {
+		return ctx.getRecordedBody()
+	}
+
 	return GetBody(ctx.request, ctx.Application().ConfigurationReadOnly().GetDisableBodyConsumptionOnUnmarshal())
 }
 
+// getRecordedBody reads and records the request body, once,
+// and resets the request's body to replay it. The result should not be modified.
+func (ctx *context) getRecordedBody() ([]byte, error) {
+	if ctx.requestBody == nil {
+		body := ctx.request.Body
+		if body == nil {
+			return nil, nil
+		}
+
+		limit := ctx.Application().ConfigurationReadOnly().GetRecordRequestBodyLimit()
+		if limit > 0 && ctx.request.ContentLength > limit {
+			return nil, ErrRequestBodyTooLarge
+		}
+
+		var r io.Reader = body
+		if limit > 0 {
+			r = io.LimitReader(body, limit+1)
+		}
+
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if limit > 0 && int64(len(data)) > limit {
+			// keep the body readable as it was.
+			ctx.request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), body), body}
+			return nil, ErrRequestBodyTooLarge
+		}
+
+		if data == nil {
+			data = []byte{}
+		}
+		ctx.requestBody = data
+	}
+
+	ctx.request.Body = ioutil.NopCloser(bytes.NewReader(ctx.requestBody))
+	return ctx.requestBody, nil
+}
+
 // GetBody reads and returns the request body.
 func GetBody(r *http.Request, resetBody bool) ([]byte, error) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -2765,9 +3199,14 @@ func (ctx *context) shouldOptimize() bool {
 }
 
 // ReadJSON reads JSON from request's body and binds it to a value of any json-valid type.
+// The Party's options, if any, are used, see `Party.SetReadJSONOptions` and `ReadJSONWithOptions`.
 //
 // Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-json/main.go
 func (ctx *context) ReadJSON(outPtr interface{}) error {
+	if options, ok := ctx.readJSONOptions(); ok {
+		return ctx.readJSON(outPtr, options)
+	}
+
 	unmarshaler := json.Unmarshal
 	if ctx.shouldOptimize() {
 		unmarshaler = jsoniter.Unmarshal
@@ -2775,6 +3214,13 @@ func (ctx *context) ReadJSON(outPtr interface{}) error {
 	return ctx.UnmarshalBody(outPtr, UnmarshalerFunc(unmarshaler))
 }
 
+// ReadJSONWithOptions same as `ReadJSON` but it reads the body based on the "opts",
+// which can make it strict, e.g. disallow unknown fields and limit the body's size.
+// Its errors are `*ReadJSONError` values which name the offending field.
+func (ctx *context) ReadJSONWithOptions(outPtr interface{}, opts ReadJSONOptions) error {
+	return ctx.readJSON(outPtr, opts)
+}
+
 // ReadXML reads XML from request's body and binds it to a value of any xml-valid type.
 //
 // Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-xml/main.go
@@ -2833,6 +3279,178 @@ func (ctx *context) ReadQuery(ptr interface{}) error {
 	return ctx.Application().Validate(ptr)
 }
 
+var (
+	headerDecoder = newTagDecoder(HeaderTagName)
+	paramDecoder  = newTagDecoder(ParamTagName)
+	queryDecoder  = newTagDecoder(QueryTagName)
+)
+
+func newTagDecoder(tagName string) *schema.Decoder {
+	d := schema.NewDecoder()
+	d.SetAliasTag(tagName)
+	d.IgnoreUnknownKeys(true)
+	return d
+}
+
+const (
+	// HeaderTagName is the struct field tag name of the `ReadHeaders`.
+	HeaderTagName = "header"
+	// ParamTagName is the struct field tag name of the `ReadParams`.
+	ParamTagName = "param"
+	// QueryTagName is the struct field tag name of the `ReadQuery` and `DecodeQueryValues`.
+	QueryTagName = "url"
+)
+
+// DecodeQueryValues binds the URL "query" to the "ptr" fields tagged with "url",
+// unlike `Context.ReadQuery` it ignores unknown keys and does not validate the result.
+func DecodeQueryValues(query url.Values, ptr interface{}) error {
+	if len(query) == 0 {
+		return nil
+	}
+
+	return queryDecoder.Decode(ptr, query)
+}
+
+// ReadHeaders binds request headers to "ptr". The struct field tag is "header",
+// e.g. `header:"X-Tenant-ID"`. Values are converted to the field's type.
+//
+// Example: https://github.com/kataras/iris/blob/master/_examples/http_request/read-headers/main.go
+func (ctx *context) ReadHeaders(ptr interface{}) error {
+	err := DecodeHeaders(ctx.request.Header, ptr)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Application().Validate(ptr)
+}
+
+// DecodeHeaders binds the "header" to the "ptr" like `Context.ReadHeaders` does
+// but without validating the result.
+func DecodeHeaders(header http.Header, ptr interface{}) error {
+	values := make(map[string][]string)
+	collectTaggedValues(reflect.TypeOf(ptr), HeaderTagName, func(name string) ([]string, bool) {
+		v, ok := header[http.CanonicalHeaderKey(name)]
+		return v, ok
+	}, values)
+	if len(values) == 0 {
+		return nil
+	}
+
+	return headerDecoder.Decode(ptr, values)
+}
+
+// ReadParams binds the route's path parameters to "ptr". The struct field tag is "param",
+// e.g. `param:"id"`. Values are converted to the field's type.
+//
+// Example: https://github.com/kataras/iris/blob/master/_examples/routing/read-params/main.go
+func (ctx *context) ReadParams(ptr interface{}) error {
+	err := DecodeParams(ctx.Params(), ptr)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Application().Validate(ptr)
+}
+
+// DecodeParams binds the path "params" to the "ptr" like `Context.ReadParams` does
+// but without validating the result.
+func DecodeParams(params *RequestParams, ptr interface{}) error {
+	values := make(map[string][]string)
+	collectTaggedValues(reflect.TypeOf(ptr), ParamTagName, func(name string) ([]string, bool) {
+		entry, ok := params.Store.GetEntry(name)
+		if !ok {
+			return nil, false
+		}
+		return []string{entry.String()}, true
+	}, values)
+	if len(values) == 0 {
+		return nil
+	}
+
+	return paramDecoder.Decode(ptr, values)
+}
+
+// HasHeaderFields reports whether the struct "typ" (or a pointer to it)
+// has at least one field with the "header" tag, see `ReadHeaders`.
+func HasHeaderFields(typ reflect.Type) bool {
+	return hasTaggedFields(typ, HeaderTagName)
+}
+
+// HasQueryFields reports whether the struct "typ" (or a pointer to it)
+// has at least one field with the "url" tag, see `DecodeQueryValues`.
+func HasQueryFields(typ reflect.Type) bool {
+	return hasTaggedFields(typ, QueryTagName)
+}
+
+// HasParamFields reports whether the struct "typ" (or a pointer to it)
+// has at least one field with the "param" tag, see `ReadParams`.
+func HasParamFields(typ reflect.Type) bool {
+	return hasTaggedFields(typ, ParamTagName)
+}
+
+func hasTaggedFields(typ reflect.Type, tagName string) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if name, _ := parseFieldTag(f, tagName); name != "" {
+			return true
+		}
+
+		if f.Anonymous && hasTaggedFields(f.Type, tagName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseFieldTag(f reflect.StructField, tagName string) (string, bool) {
+	tag, ok := f.Tag.Lookup(tagName)
+	if !ok {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", false
+	}
+
+	return name, true
+}
+
+// collectTaggedValues fills "values" with the values of the "tagName" fields of "typ",
+// keyed by their tag names, through the "lookup" function.
+func collectTaggedValues(typ reflect.Type, tagName string, lookup func(name string) ([]string, bool), values map[string][]string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if name, ok := parseFieldTag(f, tagName); ok && name != "" {
+			if v, found := lookup(name); found {
+				values[name] = v
+			}
+			continue
+		}
+
+		if f.Anonymous {
+			collectTaggedValues(f.Type, tagName, lookup, values)
+		}
+	}
+}
+
 // ReadProtobuf binds the body to the "ptr" of a proto Message and returns any error.
 func (ctx *context) ReadProtobuf(ptr proto.Message) error {
 	rawData, err := ctx.GetBody()
@@ -2858,10 +3476,25 @@ func (ctx *context) ReadMsgPack(ptr interface{}) error {
 	return ctx.Application().Validate(ptr)
 }
 
+// ReadCBOR binds the request body of CBOR (RFC 8949) format to the "ptr" and returns any error.
+func (ctx *context) ReadCBOR(ptr interface{}) error {
+	rawData, err := ctx.GetBody()
+	if err != nil {
+		return err
+	}
+
+	err = cbor.Unmarshal(rawData, ptr)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Application().Validate(ptr)
+}
+
 // ReadBody binds the request body to the "ptr" depending on the HTTP Method and the Request's Content-Type.
 // If a GET method request then it reads from a form (or URL Query), otherwise
 // it tries to match (depending on the request content-type) the data format e.g.
-// JSON, Protobuf, MsgPack, XML, YAML, MultipartForm and binds the result to the "ptr".
+// JSON, Protobuf, MsgPack, CBOR, XML, YAML, MultipartForm and binds the result to the "ptr".
 func (ctx *context) ReadBody(ptr interface{}) error {
 	if ctx.Method() == http.MethodGet {
 		return ctx.ReadForm(ptr)
@@ -2885,6 +3518,8 @@ func (ctx *context) ReadBody(ptr interface{}) error {
 		return ctx.ReadProtobuf(msg)
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return ctx.ReadMsgPack(ptr)
+	case ContentCBORHeaderValue:
+		return ctx.ReadCBOR(ptr)
 	default:
 		if ctx.Request().URL.RawQuery != "" {
 			// try read from query.
@@ -2919,6 +3554,10 @@ func (ctx *context) ReadBody(ptr interface{}) error {
 // by all HTTP/2 clients. Handlers should read before writing if
 // possible to maximize compatibility.
 func (ctx *context) Write(rawBody []byte) (int, error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	return ctx.writer.Write(rawBody)
 }
 
@@ -2926,6 +3565,10 @@ func (ctx *context) Write(rawBody []byte) (int, error) {
 //
 // Returns the number of bytes written and any write error encountered.
 func (ctx *context) Writef(format string, a ...interface{}) (n int, err error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	return ctx.writer.Writef(format, a...)
 }
 
@@ -2933,6 +3576,10 @@ func (ctx *context) Writef(format string, a ...interface{}) (n int, err error) {
 //
 // Returns the number of bytes written and any write error encountered.
 func (ctx *context) WriteString(body string) (n int, err error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	return ctx.writer.WriteString(body)
 }
 
@@ -2948,6 +3595,10 @@ const (
 	CacheControlHeaderKey = "Cache-Control"
 	// ETagHeaderKey is the header key of "ETag".
 	ETagHeaderKey = "ETag"
+	// IfMatchHeaderKey is the header key of "If-Match".
+	IfMatchHeaderKey = "If-Match"
+	// IfNoneMatchHeaderKey is the header key of "If-None-Match".
+	IfNoneMatchHeaderKey = "If-None-Match"
 
 	// ContentDispositionHeaderKey is the header key of "Content-Disposition".
 	ContentDispositionHeaderKey = "Content-Disposition"
@@ -3085,6 +3736,64 @@ func (ctx *context) WriteWithExpiration(body []byte, modtime time.Time) (int, er
 	return ctx.writer.Write(body)
 }
 
+// ETag returns a strong entity tag, quoted, for the given "body".
+// It's used by `Context.WriteJSONWithETag` and it can be used
+// to compute the current tag of a resource before calling `Context.CheckIfMatch`.
+func ETag(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return fmt.Sprintf("\"%x-%x\"", len(body), h.Sum64())
+}
+
+// etagMatches reports whether the comma-separated "header" list
+// contains the "etag". When "weak" is true the "W/" prefixes are ignored
+// (weak comparison), otherwise weak tags never match (strong comparison).
+func etagMatches(header, etag string, weak bool) bool {
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = tag[2:]
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckIfMatch reports whether the "If-Match" request header, if any,
+// matches the current "etag" of the resource (strong comparison).
+// If it does not then it sends a 412 "Precondition Failed" status code,
+// stops the handlers chain and returns false.
+// A missing "If-Match" header or a "*" value always passes.
+//
+// Useful to prevent lost updates on REST update endpoints (optimistic concurrency), e.g.
+// if !ctx.CheckIfMatch(ETag(current)) { return }
+func (ctx *context) CheckIfMatch(etag string) bool {
+	ifMatch := ctx.GetHeader(IfMatchHeaderKey)
+	if ifMatch == "" || etagMatches(ifMatch, etag, false) {
+		return true
+	}
+
+	ctx.StopWithStatus(http.StatusPreconditionFailed)
+	return false
+}
+
 // StreamWriter registers the given stream writer for populating
 // response body.
 //
@@ -3117,20 +3826,38 @@ func (ctx *context) StreamWriter(writer func(w io.Writer) bool) {
 	}
 }
 
+// MultipartStream sets the "multipart/x-mixed-replace" content type
+// and returns the writer of its parts, each part replaces the previous one on the client,
+// e.g. the frames of a MJPEG camera stream or the progressive results of a long task.
+// A random boundary is used when "boundary" is empty.
+// It returns a nil stream and an error on invalid boundary.
+//
+// Example:
+//  stream, err := ctx.MultipartStream("")
+//  for frame := range frames {
+//      if err = stream.WritePart("image/jpeg", frame); err != nil {
+//          return // client is gone.
+//      }
+//  }
+func (ctx *context) MultipartStream(boundary string) (*MultipartStream, error) {
+	mw := multipart.NewWriter(ctx.writer)
+	if boundary != "" {
+		if err := mw.SetBoundary(boundary); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx.writer.Header().Set(ContentTypeHeaderKey, "multipart/x-mixed-replace; boundary="+mw.Boundary())
+	return &MultipartStream{ctx: ctx, mw: mw}, nil
+}
+
 //  +------------------------------------------------------------+
 //  | Body Writers with compression                              |
 //  +------------------------------------------------------------+
 
 // ClientSupportsGzip retruns true if the client supports gzip compression.
 func (ctx *context) ClientSupportsGzip() bool {
-	if h := ctx.GetHeader(AcceptEncodingHeaderKey); h != "" {
-		for _, v := range strings.Split(h, ";") {
-			if strings.Contains(v, GzipHeaderValue) { // we do Contains because sometimes browsers has the q=, we don't use it atm. || strings.Contains(v,"deflate"){
-				return true
-			}
-		}
-	}
-	return false
+	return ctx.AcceptHeader(AcceptEncodingHeaderKey).Accepts(GzipHeaderValue)
 }
 
 // ErrGzipNotSupported may be returned from `WriteGzip` methods if
@@ -3309,6 +4036,10 @@ func (ctx *context) GetViewData() map[string]interface{} {
 //
 // Examples: https://github.com/kataras/iris/tree/master/_examples/view
 func (ctx *context) View(filename string, optionalViewModel ...interface{}) error {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("view")()
+	}
+
 	ctx.ContentType(ContentHTMLHeaderValue)
 	cfg := ctx.Application().ConfigurationReadOnly()
 
@@ -3322,13 +4053,43 @@ func (ctx *context) View(filename string, optionalViewModel ...interface{}) erro
 		bindingData = ctx.values.Get(cfg.GetViewDataContextKey())
 	}
 
-	err := ctx.Application().View(ctx, filename, layout, bindingData)
+	limit := cfg.GetViewBufferSize()
+	if cfg.GetEnableDebugErrorPages() {
+		// render to a buffer first, so a partial output
+		// can be replaced by the developer error page.
+		limit = -1
+	}
+
+	if limit == 0 {
+		err := ctx.Application().View(ctx, filename, layout, bindingData)
+		if err != nil {
+			ctx.SetErr(err)
+			ctx.StatusCode(http.StatusInternalServerError)
+			ctx.StopExecution()
+		}
+
+		return err
+	}
+
+	w := newViewWriter(ctx, limit)
+	defer w.release()
+
+	err := ctx.Application().View(w, filename, layout, bindingData)
 	if err != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
+		ctx.SetErr(err)
 		ctx.StopExecution()
+
+		if w.flushed {
+			// the status code and a part of the page are already sent.
+			ctx.Application().Logger().Debugf("View: %s: partial response: %v", filename, err)
+			return err
+		}
+
+		ctx.StatusCode(http.StatusInternalServerError)
+		return err
 	}
 
-	return err
+	return w.flush()
 }
 
 const (
@@ -3364,6 +4125,8 @@ const (
 	ContentMsgPackHeaderValue = "application/msgpack"
 	// ContentMsgPack2HeaderValue alternative header value for MsgPack data.
 	ContentMsgPack2HeaderValue = "application/x-msgpack"
+	// ContentCBORHeaderValue header value for CBOR (RFC 8949) data.
+	ContentCBORHeaderValue = "application/cbor"
 	// ContentFormHeaderValue header value for post form data.
 	ContentFormHeaderValue = "application/x-www-form-urlencoded"
 	// ContentFormMultipartHeaderValue header value for post multipart form data.
@@ -3400,6 +4163,11 @@ type JSON struct {
 	Prefix       string
 	ASCII        bool // if true writes with unicode to ASCII content.
 	Secure       bool // if true then it adds a "while(1);" when Go slice (to JSON Array) value.
+	// Codec, if not nil, overrides the `Application.JSONCodec` for this call.
+	Codec JSONCodec
+	// ContentLength, if true, sets the "Content-Length" header of the encoded value,
+	// when the response is not compressed or recorded. Ignored on StreamingJSON.
+	ContentLength bool
 }
 
 // JSONP contains the options for the JSONP (Context's) Renderer.
@@ -3419,20 +4187,30 @@ type XML struct {
 // Markdown contains the options for the Markdown (Context's) Renderer.
 type Markdown struct {
 	// content-specific
+	// Sanitize removes any unsafe HTML (XSS) from the rendered output.
 	Sanitize bool
+	// Footnotes enables the Pandoc-style footnotes,
+	// tables, fenced code blocks, autolinks and strikethrough are always enabled.
+	Footnotes bool
+	// Highlight, if not nil, renders the fenced code blocks with syntax highlighting,
+	// it reports false to fallback to the default code block.
+	// The "lang" is the language of the fenced code block, if any.
+	// On Sanitize, only the "class" attributes of the highlighted output are kept.
+	Highlight func(code []byte, lang string) (html []byte, ok bool)
+	// HighlightKey identifies the Highlight func and its settings on the Cache,
+	// e.g. "chroma/monokai". The output of a Highlight without a key is not cached.
+	HighlightKey string
+	// Cache keeps the rendered output of the contents by their hash,
+	// useful when the same contents are rendered on each request.
+	Cache bool
+	// CacheSize is the maximum number of the rendered contents kept by the Cache,
+	// the oldest ones are removed first. A negative value disables the Cache.
+	// Defaults to `DefaultMarkdownCacheSize`.
+	CacheSize int
 }
 
 var (
 	newLineB = []byte("\n")
-	// the html codes for unescaping.
-	ltHex = []byte("\\u003c")
-	lt    = []byte("<")
-
-	gtHex = []byte("\\u003e")
-	gt    = []byte(">")
-
-	andHex = []byte("\\u0026")
-	and    = []byte("&")
 
 	// secure JSON.
 	jsonArrayPrefix  = []byte("[")
@@ -3441,70 +4219,25 @@ var (
 )
 
 // WriteJSON marshals the given interface object and writes the JSON response to the 'writer'.
-// Ignores StatusCode, Gzip, StreamingJSON options.
+// Ignores StatusCode, Gzip, StreamingJSON and ContentLength options.
 func WriteJSON(writer io.Writer, v interface{}, options JSON, optimize bool) (int, error) {
-	var (
-		result []byte
-		err    error
-	)
-
 	if !optimize && options.Indent == "" {
 		options.Indent = "  "
 	}
 
-	if indent := options.Indent; indent != "" {
-		marshalIndent := json.MarshalIndent
-		if optimize {
-			marshalIndent = jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent
-		}
-
-		result, err = marshalIndent(v, "", indent)
-		result = append(result, newLineB...)
-	} else {
-		marshal := json.Marshal
-		if optimize {
-			marshal = jsoniter.ConfigCompatibleWithStandardLibrary.Marshal
-		}
-
-		result, err = marshal(v)
-	}
-
-	if err != nil {
-		return 0, err
-	}
-
-	if options.UnescapeHTML {
-		result = bytes.Replace(result, ltHex, lt, -1)
-		result = bytes.Replace(result, gtHex, gt, -1)
-		result = bytes.Replace(result, andHex, and, -1)
-	}
-
-	if options.Secure {
-		if bytes.HasPrefix(result, jsonArrayPrefix) && bytes.HasSuffix(result, jsonArraySuffix) {
-			result = append(secureJSONPrefix, result...)
-		}
+	codec := options.Codec
+	if codec == nil {
+		codec = defaultJSONCodec(optimize)
 	}
 
-	if options.ASCII {
-		if len(result) > 0 {
-			buf := new(bytes.Buffer)
-			for _, s := range bytesToString(result) {
-				char := string(s)
-				if s >= 128 {
-					char = fmt.Sprintf("\\u%04x", int64(s))
-				}
-				buf.WriteString(char)
-			}
-
-			result = buf.Bytes()
-		}
-	}
+	buf := acquireJSONBuffer()
+	defer releaseJSONBuffer(buf)
 
-	if prefix := options.Prefix; prefix != "" {
-		result = append(stringToBytes(prefix), result...)
+	if err := encodeJSON(buf, v, options, codec); err != nil {
+		return 0, err
 	}
 
-	return writer.Write(result)
+	return writer.Write(buf.Bytes())
 }
 
 // See https://golang.org/src/strings/builder.go#L45
@@ -3522,6 +4255,10 @@ var DefaultJSONOptions = JSON{}
 
 // JSON marshals the given interface object and writes the JSON response to the client.
 func (ctx *context) JSON(v interface{}, opts ...JSON) (n int, err error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	options := DefaultJSONOptions
 
 	if len(opts) > 0 {
@@ -3530,8 +4267,21 @@ func (ctx *context) JSON(v interface{}, opts ...JSON) (n int, err error) {
 
 	ctx.ContentType(ContentJSONHeaderValue)
 
+	if options.Codec == nil {
+		options.Codec = ctx.Application().GetJSONCodec()
+	}
+
 	if options.StreamingJSON {
-		if ctx.shouldOptimize() {
+		if options.Codec != nil {
+			enc := options.Codec.NewEncoder(ctx.writer)
+			if options.UnescapeHTML {
+				enc.SetEscapeHTML(false)
+			}
+			if options.Prefix != "" || options.Indent != "" {
+				enc.SetIndent(options.Prefix, options.Indent)
+			}
+			err = enc.Encode(v)
+		} else if ctx.shouldOptimize() {
 			jsoniterConfig := jsoniter.Config{
 				EscapeHTML:    !options.UnescapeHTML,
 				IndentionStep: 4,
@@ -3553,14 +4303,74 @@ func (ctx *context) JSON(v interface{}, opts ...JSON) (n int, err error) {
 		return ctx.writer.Written(), err
 	}
 
-	n, err = WriteJSON(ctx.writer, v, options, ctx.shouldOptimize())
-	if err != nil {
+	if !options.ContentLength {
+		n, err = WriteJSON(ctx.writer, v, options, ctx.shouldOptimize())
+		if err != nil {
+			ctx.Application().Logger().Debugf("JSON: %v", err)
+			ctx.StatusCode(http.StatusInternalServerError)
+			return 0, err
+		}
+
+		return n, err
+	}
+
+	buf := acquireJSONBuffer()
+	defer releaseJSONBuffer(buf)
+
+	if _, err = WriteJSON(buf, v, options, ctx.shouldOptimize()); err != nil {
 		ctx.Application().Logger().Debugf("JSON: %v", err)
 		ctx.StatusCode(http.StatusInternalServerError)
 		return 0, err
 	}
 
-	return n, err
+	if _, ok := ctx.writer.(*responseWriter); ok {
+		ctx.writer.Header().Set(ContentLengthHeaderKey, strconv.Itoa(buf.Len()))
+	}
+
+	return ctx.writer.Write(buf.Bytes())
+}
+
+// WriteJSONWithETag works like `JSON` but it computes and sends a strong "ETag"
+// header based on the marshaled body. On GET and HEAD requests it sends
+// a 304 "Not Modified" instead when the "If-None-Match" request header matches that "ETag".
+// See `CheckIfMatch` too.
+func (ctx *context) WriteJSONWithETag(v interface{}, opts ...JSON) (int, error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
+	options := DefaultJSONOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	// the same body as the `JSON`'s one.
+	if options.Codec == nil {
+		options.Codec = ctx.Application().GetJSONCodec()
+	}
+
+	buf := acquireJSONBuffer()
+	defer releaseJSONBuffer(buf)
+
+	if _, err := WriteJSON(buf, v, options, ctx.shouldOptimize()); err != nil {
+		ctx.Application().Logger().Debugf("JSON: %v", err)
+		ctx.StatusCode(http.StatusInternalServerError)
+		return 0, err
+	}
+
+	body := buf.Bytes()
+	etag := ETag(body)
+	ctx.Header(ETagHeaderKey, etag)
+
+	if method := ctx.Method(); method == http.MethodGet || method == http.MethodHead {
+		if ifNoneMatch := ctx.GetHeader(IfNoneMatchHeaderKey); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+			ctx.WriteNotModified()
+			return 0, nil
+		}
+	}
+
+	ctx.ContentType(ContentJSONHeaderValue)
+	return ctx.writer.Write(body)
 }
 
 var finishCallbackB = []byte(");")
@@ -3611,6 +4421,10 @@ var DefaultJSONPOptions = JSONP{}
 
 // JSONP marshals the given interface object and writes the JSON response to the client.
 func (ctx *context) JSONP(v interface{}, opts ...JSONP) (int, error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	options := DefaultJSONPOptions
 
 	if len(opts) > 0 {
@@ -3708,6 +4522,10 @@ var DefaultXMLOptions = XML{}
 // XML marshals the given interface object and writes the XML response to the client.
 // To render maps as XML see the `XMLMap` package-level function.
 func (ctx *context) XML(v interface{}, opts ...XML) (int, error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	options := DefaultXMLOptions
 
 	if len(opts) > 0 {
@@ -3775,11 +4593,7 @@ func (ctx *context) Problem(v interface{}, opts ...ProblemOptions) (int, error)
 
 // WriteMarkdown parses the markdown to html and writes these contents to the writer.
 func WriteMarkdown(writer io.Writer, markdownB []byte, options Markdown) (int, error) {
-	buf := blackfriday.Run(markdownB)
-	if options.Sanitize {
-		buf = bluemonday.UGCPolicy().SanitizeBytes(buf)
-	}
-	return writer.Write(buf)
+	return writer.Write(RenderMarkdown(markdownB, options))
 }
 
 // DefaultMarkdownOptions is the optional settings that are being used
@@ -3788,6 +4602,10 @@ var DefaultMarkdownOptions = Markdown{}
 
 // Markdown parses the markdown to html and renders its result to the client.
 func (ctx *context) Markdown(markdownB []byte, opts ...Markdown) (int, error) {
+	if ctx.timeline != nil {
+		defer ctx.timeline.Begin("write")()
+	}
+
 	options := DefaultMarkdownOptions
 
 	if len(opts) > 0 {
@@ -3841,6 +4659,17 @@ func (ctx *context) MsgPack(v interface{}) (int, error) {
 	return ctx.Write(out)
 }
 
+// CBOR parses the "v" of CBOR (RFC 8949) format and renders its result to the client.
+func (ctx *context) CBOR(v interface{}) (int, error) {
+	out, err := cbor.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx.ContentType(ContentCBORHeaderValue)
+	return ctx.Write(out)
+}
+
 //  +-----------------------------------------------------------------------+
 //  | Content Νegotiation                                                   |
 //  | https://developer.mozilla.org/en-US/docs/Web/HTTP/Content_negotiation |                                       |
@@ -3893,6 +4722,7 @@ type N struct {
 	YAML     interface{}
 	Protobuf interface{}
 	MsgPack  interface{}
+	CBOR     interface{}
 
 	Other []byte // custom content types.
 }
@@ -3922,6 +4752,8 @@ func (n N) SelectContent(mime string) interface{} {
 		return n.Protobuf
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return n.MsgPack
+	case ContentCBORHeaderValue:
+		return n.CBOR
 	default:
 		return n.Other
 	}
@@ -3940,8 +4772,9 @@ func (ctx *context) Negotiation() *NegotiationBuilder {
 	}
 
 	acceptBuilder := NegotiationAcceptBuilder{}
-	acceptBuilder.accept = parseHeader(ctx.GetHeader("Accept"))
-	acceptBuilder.charset = parseHeader(ctx.GetHeader("Accept-Charset"))
+	acceptBuilder.accept = ctx.AcceptHeader(AcceptHeaderKey).Values()
+	acceptBuilder.charset = ctx.AcceptHeader(AcceptCharsetHeaderKey).Values()
+	acceptBuilder.encoding = ctx.AcceptHeader(AcceptEncodingHeaderKey).Values()
 
 	n := &NegotiationBuilder{Accept: acceptBuilder}
 
@@ -4064,6 +4897,8 @@ func (ctx *context) Negotiate(v interface{}) (int, error) {
 		return ctx.Protobuf(msg)
 	case ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue:
 		return ctx.MsgPack(v)
+	case ContentCBORHeaderValue:
+		return ctx.CBOR(v)
 	default:
 		// maybe "Other" or v is []byte or string but not a built-in framework mime,
 		// for custom content types,
@@ -4265,6 +5100,19 @@ func (n *NegotiationBuilder) MsgPack(v ...interface{}) *NegotiationBuilder {
 	return n.MIME(ContentMsgPackHeaderValue+","+ContentMsgPack2HeaderValue, content)
 }
 
+// CBOR registers the "application/cbor" content type and, optionally,
+// a value that `Context.Negotiate` will render
+// when a client accepts the "application/cbor" content type.
+//
+// Returns itself for recursive calls.
+func (n *NegotiationBuilder) CBOR(v ...interface{}) *NegotiationBuilder {
+	var content interface{}
+	if len(v) > 0 {
+		content = v[0]
+	}
+	return n.MIME(ContentCBORHeaderValue, content)
+}
+
 // Any registers a wildcard that can match any client's accept content type.
 //
 // Returns itself for recursive calls.
@@ -4507,13 +5355,19 @@ func (n *NegotiationAcceptBuilder) YAML() *NegotiationAcceptBuilder {
 // Protobuf adds the "application/x-protobuf" as accepted client content type.
 // Returns itself.
 func (n *NegotiationAcceptBuilder) Protobuf() *NegotiationAcceptBuilder {
-	return n.MIME(ContentYAMLHeaderValue)
+	return n.MIME(ContentProtobufHeaderValue)
 }
 
 // MsgPack adds the "application/msgpack" and "application/x-msgpack" as accepted client content types.
 // Returns itself.
 func (n *NegotiationAcceptBuilder) MsgPack() *NegotiationAcceptBuilder {
-	return n.MIME(ContentYAMLHeaderValue)
+	return n.MIME(ContentMsgPackHeaderValue, ContentMsgPack2HeaderValue)
+}
+
+// CBOR adds the "application/cbor" as accepted client content type.
+// Returns itself.
+func (n *NegotiationAcceptBuilder) CBOR() *NegotiationAcceptBuilder {
+	return n.MIME(ContentCBORHeaderValue)
 }
 
 // Charset adds one or more client accepted charsets.
@@ -4547,8 +5401,9 @@ func (n *NegotiationAcceptBuilder) EncodingGzip() *NegotiationAcceptBuilder {
 // ServeContent serves content, headers are autoset
 // receives three parameters, it's low-level function, instead you can use .ServeFile(string,bool)/SendFile(string,string)
 //
-// You can define your own "Content-Type" header also, after this function call
-// Doesn't implements resuming (by range), use ctx.SendFile instead
+// You can define your own "Content-Type" with `context#ContentType`, before this function call.
+//
+// Resuming (by range) is supported when the content is not compressed.
 func (ctx *context) ServeContent(content io.ReadSeeker, filename string, modtime time.Time, gzipCompression bool) error {
 	if modified, err := ctx.CheckIfModifiedSince(modtime); !modified && err == nil {
 		ctx.WriteNotModified()
@@ -4560,18 +5415,37 @@ func (ctx *context) ServeContent(content io.ReadSeeker, filename string, modtime
 	}
 
 	ctx.SetLastModified(modtime)
-	var out io.Writer
 	if gzipCompression && ctx.ClientSupportsGzip() {
 		AddGzipHeaders(ctx.writer)
 
 		gzipWriter := acquireGzipWriter(ctx.writer)
 		defer releaseGzipWriter(gzipWriter)
-		out = gzipWriter
-	} else {
-		out = ctx.writer
+		_, err := io.Copy(gzipWriter, content)
+		return err ///TODO: add an int64 as return value for the content length written like other writers or let it as it's in order to keep the stable api?
+	}
+
+	if ctx.isCompressing() {
+		// the content length and the ranges of the uncompressed content
+		// do not match the compressed response, send it as it's.
+		ctx.writer.Header().Del(ContentLengthHeaderKey)
+		_, err := io.Copy(ctx.writer, content)
+		return err
+	}
+
+	// sets the content length, handles the ranges and sends the files
+	// through the zero-copy path of the response writer, if available.
+	http.ServeContent(ctx.writer, ctx.request, filename, modtime, content)
+	return nil
+}
+
+// isCompressing reports whether the response writer compresses the written data,
+// e.g. after a `Gzip(true)` call.
+func (ctx *context) isCompressing() bool {
+	if w, ok := ctx.writer.(*GzipResponseWriter); ok && !w.disabled {
+		return true
 	}
-	_, err := io.Copy(out, content)
-	return err ///TODO: add an int64 as return value for the content length written like other writers or let it as it's in order to keep the stable api?
+
+	return ctx.writer.Header().Get(ContentEncodingHeaderKey) != ""
 }
 
 // ServeFile serves a view file, to send a file ( zip for example) to the client you should use the SendFile(serverfilename,clientfilename)
@@ -4580,7 +5454,7 @@ func (ctx *context) ServeContent(content io.ReadSeeker, filename string, modtime
 // gzipCompression (bool)
 //
 // You can define your own "Content-Type" header also, after this function call
-// This function doesn't implement resuming (by range), use ctx.SendFile instead
+// Resuming (by range) is supported when "gzipCompression" is false.
 //
 // Use it when you want to serve css/js/... files to the client, for bigger files and 'force-download' use the SendFile.
 func (ctx *context) ServeFile(filename string, gzipCompression bool) error {
@@ -4912,9 +5786,50 @@ func (ctx *context) IsRecording() (*ResponseRecorder, bool) {
 	return rr, ok
 }
 
-// ErrPanicRecovery may be returned from `Context` actions of a `Handler`
-// which recovers from a manual panic.
-// var ErrPanicRecovery = errors.New("recovery from panic")
+// Timeline returns the timeline of the request, if enabled through `SetTimeline`,
+// e.g. by the iris/middleware/timeline, otherwise nil.
+// A nil timeline can be used too, its methods do nothing, i.e
+//  defer ctx.Timeline().Begin("db")()
+func (ctx *context) Timeline() *Timeline {
+	return ctx.timeline
+}
+
+// SetTimeline enables the timeline of the request, see `Timeline`.
+func (ctx *context) SetTimeline(t *Timeline) {
+	ctx.timeline = t
+}
+
+// ServerTiming returns the request's metrics of the Server-Timing response header, i.e
+//  ctx.ServerTiming().Add("db", time.Since(start), "users query")
+// The header is sent at the end of the request, if the response headers were already sent,
+// i.e after a partial write or a flush when streaming, the metrics are sent as a trailer instead.
+func (ctx *context) ServerTiming() *ServerTiming {
+	if ctx.serverTiming == nil {
+		st := newServerTiming()
+		if ctx.writer.Written() == NoWritten {
+			onWriteHeader(ctx.writer, st.writeHeader)
+		}
+		ctx.serverTiming = st
+	}
+
+	return ctx.serverTiming
+}
+
+// ErrPanicRecovery is the error which is stored through `SetErr`
+// by the recover middleware when a `Handler` panics.
+type ErrPanicRecovery struct {
+	// Cause is the value passed to the panic.
+	Cause interface{}
+	// HandlerName is the name of the handler which panicked.
+	HandlerName string
+	// Callers is the list of the "file:line" stack frames, starting from the panic.
+	Callers []string
+}
+
+// Error implements the error interface.
+func (e *ErrPanicRecovery) Error() string {
+	return fmt.Sprintf("recovered from panic in %s: %v", e.HandlerName, e.Cause)
+}
 
 // ErrTransactionInterrupt can be used to manually force-complete a Context's transaction
 // and log(warn) the wrapped error's message.
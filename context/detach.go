@@ -0,0 +1,67 @@
+package context
+
+import (
+	"bytes"
+	stdContext "context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+// discardResponseWriter is the underline response writer of a detached Context,
+// the request is already served, so the writes are dropped.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+// Detach returns a snapshot of the Context which is safe to be used after the handler returned,
+// i.e inside a goroutine started by the handler. The Context itself is released to the pool
+// and reused by another request right after its handlers are executed, the snapshot is not.
+//
+// The snapshot holds a copy of the values, the path parameters and the current route,
+// and a clone of the request with its body already read,
+// so it can be read again, and a context.Background() as its standard context,
+// a background task should not be canceled because the client's request is done.
+// Its response writes are dropped.
+//
+// Example:
+//  func handler(ctx iris.Context) {
+//      detached := ctx.Detach()
+//      go func() {
+//          var order Order
+//          detached.ReadJSON(&order)
+//          notify(order, detached.Values().GetString("user"))
+//      }()
+//      ctx.StatusCode(iris.StatusAccepted)
+//  }
+func (ctx *context) Detach() Context {
+	var body []byte
+	if ctx.request.Body != nil {
+		// the body is reset so the handler can still read it.
+		body, _ = GetBody(ctx.request, true)
+	}
+
+	r := ctx.request.Clone(stdContext.Background())
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	w := &responseWriter{}
+	w.BeginResponse(&discardResponseWriter{header: ctx.writer.Header().Clone()})
+	w.statusCode = ctx.GetStatusCode()
+
+	detached := &context{
+		id:               ctx.id,
+		writer:           w,
+		request:          r,
+		currentRouteName: ctx.currentRouteName,
+		values:           append(memstore.Store(nil), ctx.values...),
+		app:              ctx.app,
+	}
+	detached.params.Store = append(memstore.Store(nil), ctx.params.Store...)
+
+	return detached
+}
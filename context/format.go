@@ -0,0 +1,85 @@
+package context
+
+import (
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Formatter formats dates and numbers based on the user's preferences,
+// the request's locale and time zone, see `Context.Formatter`.
+//
+// The view engines can call its methods, e.g. `{{ .fmt.Time .CreatedAt "Jan 2, 2006 15:04" }}`
+// with `ctx.ViewData("fmt", ctx.Formatter())`, or use the builtin `formatTime`
+// and `formatNumber` view functions, e.g. `{{ formatNumber .fmt 1234.5 }}`.
+type Formatter struct {
+	// Locale is the language of the numbers, e.g. "1,234.5" or "1.234,5".
+	// Defaults to English when nil.
+	Locale Locale
+	// Timezone is the time zone of the dates.
+	// Defaults to the server's local time zone when nil.
+	Timezone *time.Location
+}
+
+// In returns the "t" in the formatter's time zone.
+func (f Formatter) In(t time.Time) time.Time {
+	if f.Timezone == nil {
+		return t.Local()
+	}
+
+	return t.In(f.Timezone)
+}
+
+// Time returns the "t" in the formatter's time zone, formatted by the "layout".
+func (f Formatter) Time(t time.Time, layout string) string {
+	return f.In(t).Format(layout)
+}
+
+// Number returns the decimal number "v" formatted based on the formatter's locale,
+// i.e. its digits grouping and decimal separator.
+func (f Formatter) Number(v interface{}) string {
+	tag := language.English
+	if f.Locale != nil {
+		if t := f.Locale.Tag(); t != nil {
+			tag = *t
+		}
+	}
+
+	return message.NewPrinter(tag).Sprint(number.Decimal(v))
+}
+
+const timezoneContextKey = "iris.timezone"
+
+// SetLocale sets the "locale" as the current request's locale,
+// it overrides the one found by the i18n middleware, see `GetLocale`.
+func (ctx *context) SetLocale(locale Locale) {
+	ctx.values.Set(ctx.app.ConfigurationReadOnly().GetLocaleContextKey(), locale)
+}
+
+// SetTimezone sets the current request's time zone, e.g. the user's preference,
+// see `GetTimezone` and `Formatter`.
+func (ctx *context) SetTimezone(loc *time.Location) {
+	ctx.values.Set(timezoneContextKey, loc)
+}
+
+// GetTimezone returns the current request's time zone, see `SetTimezone`.
+// Defaults to the server's local time zone.
+func (ctx *context) GetTimezone() *time.Location {
+	if v := ctx.values.Get(timezoneContextKey); v != nil {
+		if loc, ok := v.(*time.Location); ok && loc != nil {
+			return loc
+		}
+	}
+
+	return time.Local
+}
+
+// Formatter returns a `Formatter` of the current request's locale and time zone.
+func (ctx *context) Formatter() Formatter {
+	return Formatter{
+		Locale:   ctx.GetLocale(),
+		Timezone: ctx.GetTimezone(),
+	}
+}
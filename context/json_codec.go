@@ -0,0 +1,136 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// JSONEncoder is a JSON stream encoder, i.e the `*json.Encoder`.
+type JSONEncoder interface {
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+	Encode(v interface{}) error
+}
+
+// JSONCodec creates the JSON encoders of the `Context.JSON`.
+// Set it through the `Application.JSONCodec` field for all handlers,
+// i.e to use a faster third-party encoder, or through the `JSON.Codec` option per call.
+type JSONCodec interface {
+	NewEncoder(w io.Writer) JSONEncoder
+}
+
+// JSONCodecFunc is a function which implements the `JSONCodec` interface.
+type JSONCodecFunc func(w io.Writer) JSONEncoder
+
+// NewEncoder calls the "fn" itself.
+func (fn JSONCodecFunc) NewEncoder(w io.Writer) JSONEncoder {
+	return fn(w)
+}
+
+var (
+	// StdJSONCodec is the `JSONCodec` of the standard encoding/json package.
+	StdJSONCodec JSONCodec = JSONCodecFunc(func(w io.Writer) JSONEncoder {
+		return json.NewEncoder(w)
+	})
+	// JSONIterCodec is the `JSONCodec` of the json-iterator package,
+	// it's the default one when the `Configuration.EnableOptimizations` is true.
+	JSONIterCodec JSONCodec = JSONCodecFunc(func(w io.Writer) JSONEncoder {
+		return jsoniter.ConfigCompatibleWithStandardLibrary.NewEncoder(w)
+	})
+)
+
+func defaultJSONCodec(optimize bool) JSONCodec {
+	if optimize {
+		return JSONIterCodec
+	}
+
+	return StdJSONCodec
+}
+
+// maxPooledJSONBufferSize is the maximum capacity of a pooled buffer,
+// the bigger ones are left to the garbage collector.
+const maxPooledJSONBufferSize = 64 * 1024
+
+var jsonBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func acquireJSONBuffer() *bytes.Buffer {
+	return jsonBufferPool.Get().(*bytes.Buffer)
+}
+
+func releaseJSONBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledJSONBufferSize {
+		return
+	}
+
+	buf.Reset()
+	jsonBufferPool.Put(buf)
+}
+
+// encodeJSON writes the JSON encoding of the "v" to the "buf" based on the "options".
+func encodeJSON(buf *bytes.Buffer, v interface{}, options JSON, codec JSONCodec) error {
+	if options.Secure || options.ASCII || options.Prefix != "" {
+		// post-processed, encode to a temporary buffer.
+		tmp := acquireJSONBuffer()
+		defer releaseJSONBuffer(tmp)
+
+		if err := encodeJSONValue(tmp, v, options, codec); err != nil {
+			return err
+		}
+
+		result := tmp.Bytes()
+		buf.WriteString(options.Prefix)
+
+		if options.Secure && bytes.HasPrefix(result, jsonArrayPrefix) && bytes.HasSuffix(result, jsonArraySuffix) {
+			buf.Write(secureJSONPrefix)
+		}
+
+		if options.ASCII {
+			for _, s := range bytesToString(result) {
+				if s >= 128 {
+					fmt.Fprintf(buf, "\\u%04x", int64(s))
+					continue
+				}
+				buf.WriteRune(s)
+			}
+		} else {
+			buf.Write(result)
+		}
+
+		return nil
+	}
+
+	return encodeJSONValue(buf, v, options, codec)
+}
+
+func encodeJSONValue(buf *bytes.Buffer, v interface{}, options JSON, codec JSONCodec) error {
+	enc := codec.NewEncoder(buf)
+	if options.UnescapeHTML {
+		enc.SetEscapeHTML(false)
+	}
+
+	if options.Indent != "" {
+		enc.SetIndent("", options.Indent)
+	}
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	if options.Indent == "" {
+		// keep the output of the json.Marshal, without the encoder's new line.
+		if b := buf.Bytes(); len(b) > 0 && b[len(b)-1] == '\n' {
+			buf.Truncate(len(b) - 1)
+		}
+	}
+
+	return nil
+}
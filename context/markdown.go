@@ -0,0 +1,180 @@
+package context
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultMarkdownCacheSize is the default `Markdown.CacheSize`.
+const DefaultMarkdownCacheSize = 512
+
+// RenderMarkdown parses the markdown to html based on the "options", see `Markdown`.
+func RenderMarkdown(markdownB []byte, options Markdown) []byte {
+	// the output of a Highlight func can not be cached without a key to identify it.
+	if !options.Cache || (options.Highlight != nil && options.HighlightKey == "") {
+		return renderMarkdown(markdownB, options)
+	}
+
+	key := markdownCacheKey(markdownB, options)
+	if b, ok := markdownCache.get(key); ok {
+		return b
+	}
+
+	b := renderMarkdown(markdownB, options)
+
+	size := options.CacheSize
+	if size == 0 {
+		size = DefaultMarkdownCacheSize
+	}
+	markdownCache.set(key, b, size)
+	return b
+}
+
+func renderMarkdown(markdownB []byte, options Markdown) []byte {
+	extensions := []goldmark.Extender{extension.GFM}
+	if options.Footnotes {
+		extensions = append(extensions, extension.Footnote)
+	}
+
+	rendererOptions := []renderer.Option{html.WithUnsafe()}
+	if options.Highlight != nil {
+		r := &markdownHighlightRenderer{highlight: options.Highlight}
+		// the default renderer of the code blocks which are not highlighted.
+		html.NewRenderer(html.WithUnsafe()).RegisterFuncs(r)
+		rendererOptions = append(rendererOptions, renderer.WithNodeRenderers(util.Prioritized(r, 100)))
+	}
+
+	md := goldmark.New(goldmark.WithExtensions(extensions...), goldmark.WithRendererOptions(rendererOptions...))
+
+	var buf bytes.Buffer
+	if err := md.Convert(markdownB, &buf); err != nil {
+		return nil
+	}
+
+	b := buf.Bytes()
+	if options.Sanitize {
+		policy := bluemonday.UGCPolicy()
+		if options.Highlight != nil {
+			policy.AllowAttrs("class").OnElements("pre", "code", "span", "div")
+		}
+
+		b = policy.SanitizeBytes(b)
+	}
+
+	return b
+}
+
+// markdownHighlightRenderer renders the fenced code blocks through the `Markdown.Highlight`.
+type markdownHighlightRenderer struct {
+	highlight   func(code []byte, lang string) ([]byte, bool)
+	fallback    renderer.NodeRendererFunc
+	highlighted ast.Node // the current fenced code block, if highlighted.
+}
+
+// Register keeps the default fenced code block renderer as the fallback.
+func (r *markdownHighlightRenderer) Register(kind ast.NodeKind, fn renderer.NodeRendererFunc) {
+	if kind == ast.KindFencedCodeBlock {
+		r.fallback = fn
+	}
+}
+
+func (r *markdownHighlightRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *markdownHighlightRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		if r.highlighted == node {
+			r.highlighted = nil
+			return ast.WalkContinue, nil
+		}
+
+		return r.fallback(w, source, node, entering)
+	}
+
+	n := node.(*ast.FencedCodeBlock)
+
+	var code []byte
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code = append(code, line.Value(source)...)
+	}
+
+	if b, ok := r.highlight(code, string(n.Language(source))); ok {
+		r.highlighted = node
+		_, err := w.Write(b)
+		return ast.WalkSkipChildren, err
+	}
+
+	return r.fallback(w, source, node, entering)
+}
+
+type markdownRenderCache struct {
+	mu      sync.RWMutex
+	entries map[[sha256.Size]byte][]byte
+	keys    [][sha256.Size]byte // by insertion order.
+}
+
+var markdownCache = &markdownRenderCache{entries: make(map[[sha256.Size]byte][]byte)}
+
+// markdownCacheKey returns the hash of the contents and the options which change the output.
+func markdownCacheKey(markdownB []byte, options Markdown) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(markdownB)
+
+	var flags [2]byte
+	if options.Sanitize {
+		flags[0] = 1
+	}
+	if options.Footnotes {
+		flags[1] = 1
+	}
+	h.Write(flags[:])
+
+	if options.Highlight != nil {
+		h.Write([]byte(options.HighlightKey))
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+func (c *markdownRenderCache) get(key [sha256.Size]byte) ([]byte, bool) {
+	c.mu.RLock()
+	b, ok := c.entries[key]
+	c.mu.RUnlock()
+	return b, ok
+}
+
+// set adds the rendered output "b", the oldest ones are removed to keep up to "size" entries.
+func (c *markdownRenderCache) set(key [sha256.Size]byte, b []byte, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+
+	for len(c.keys) > 0 && len(c.keys) >= size {
+		delete(c.entries, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+
+	if size <= 0 {
+		return
+	}
+
+	c.entries[key] = b
+	c.keys = append(c.keys, key)
+}
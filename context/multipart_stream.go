@@ -0,0 +1,69 @@
+package context
+
+import (
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strconv"
+)
+
+// MultipartStream writes the parts of a multipart/x-mixed-replace response,
+// each part replaces the previous one on the client,
+// e.g. the frames of a MJPEG camera stream or the progressive results of a long task.
+// See `Context.MultipartStream`.
+type MultipartStream struct {
+	ctx Context
+	mw  *multipart.Writer
+}
+
+// Boundary returns the boundary of the parts.
+func (s *MultipartStream) Boundary() string {
+	return s.mw.Boundary()
+}
+
+// WritePart writes a part of the "contentType" and the "body" and flushes it to the client.
+// It returns the request's context error if the client is gone.
+func (s *MultipartStream) WritePart(contentType string, body []byte) error {
+	if err := s.ctx.Request().Context().Err(); err != nil {
+		return err
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set(ContentTypeHeaderKey, contentType)
+	h.Set(ContentLengthHeaderKey, strconv.Itoa(len(body)))
+
+	w, err := s.mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+
+	s.Flush()
+	return nil
+}
+
+// NextPart starts a new part of the "header" and returns its body's writer,
+// the part is not flushed until the `Flush` or the `WritePart` is called,
+// useful to write a part's body in pieces.
+func (s *MultipartStream) NextPart(header textproto.MIMEHeader) (io.Writer, error) {
+	if err := s.ctx.Request().Context().Err(); err != nil {
+		return nil, err
+	}
+
+	return s.mw.CreatePart(header)
+}
+
+// Flush sends any buffered data to the client.
+func (s *MultipartStream) Flush() {
+	s.ctx.ResponseWriter().Flush()
+}
+
+// Close writes the closing boundary and flushes the response.
+func (s *MultipartStream) Close() error {
+	err := s.mw.Close()
+	s.Flush()
+	return err
+}
@@ -1,16 +1,26 @@
 package context
 
 import (
+	"fmt"
 	"net/http"
+	"reflect"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Pool is the context pool, it's used inside router and the framework by itself.
 //
 // It's the only one real implementation inside this package because it used widely.
 type Pool struct {
+	acquired uint64 // atomic, first for 64-bit alignment.
+	released uint64 // atomic.
+
 	pool    *sync.Pool
 	newFunc func() Context // we need a field otherwise is not working if we change the return value
+
+	debug *poolDebug
 }
 
 // New creates and returns a new context pool.
@@ -33,7 +43,16 @@ func (c *Pool) Attach(newFunc func() Context) {
 // Acquire returns a Context from pool.
 // See Release.
 func (c *Pool) Acquire(w http.ResponseWriter, r *http.Request) Context {
-	ctx := c.pool.Get().(Context)
+	atomic.AddUint64(&c.acquired, 1)
+
+	var ctx Context
+	if c.debug != nil {
+		ctx = c.newFunc()
+		c.debug.track(ctx)
+	} else {
+		ctx = c.pool.Get().(Context)
+	}
+
 	ctx.BeginRequest(w, r)
 	return ctx
 }
@@ -41,6 +60,14 @@ func (c *Pool) Acquire(w http.ResponseWriter, r *http.Request) Context {
 // Release puts a Context back to its pull, this function releases its resources.
 // See Acquire.
 func (c *Pool) Release(ctx Context) {
+	atomic.AddUint64(&c.released, 1)
+
+	if c.debug != nil {
+		c.debug.release(ctx)
+		ctx.EndRequest()
+		return
+	}
+
 	ctx.EndRequest()
 	c.pool.Put(ctx)
 }
@@ -51,3 +78,189 @@ func (c *Pool) Release(ctx Context) {
 func (c *Pool) ReleaseLight(ctx Context) {
 	c.pool.Put(ctx)
 }
+
+// PoolStats holds the statistics of a context Pool, see `Pool.Stats`.
+type PoolStats struct {
+	// Acquired is the total number of the acquired contexts.
+	Acquired uint64
+	// Released is the total number of the released contexts.
+	Released uint64
+	// InUse is the number of the contexts which serve requests right now.
+	InUse uint64
+	// Retained is the number of the released contexts which are still referenced,
+	// it's always zero when the debug mode is disabled.
+	Retained int
+}
+
+// Stats returns the pool's statistics.
+func (c *Pool) Stats() PoolStats {
+	released := atomic.LoadUint64(&c.released)
+	stats := PoolStats{
+		Acquired: atomic.LoadUint64(&c.acquired),
+		Released: released,
+	}
+	stats.InUse = stats.Acquired - released
+
+	if c.debug != nil {
+		stats.Retained = len(c.debug.leaks())
+	}
+
+	return stats
+}
+
+// Leak describes a Context which is still referenced, i.e by a goroutine, after its request ended,
+// see `Pool.EnableDebug`.
+type Leak struct {
+	Method string
+	Path   string
+	// Route is the name of the route which served the request.
+	Route      string
+	AcquiredAt time.Time
+	ReleasedAt time.Time
+	// Stack is the stack trace of the Context's acquisition.
+	Stack string
+}
+
+// String returns a human readable description of the leak.
+func (l Leak) String() string {
+	return fmt.Sprintf("context of %s %s (route: %s) is retained %s after its release, acquired at:\n%s",
+		l.Method, l.Path, l.Route, time.Since(l.ReleasedAt).Round(time.Millisecond), l.Stack)
+}
+
+type (
+	poolDebug struct {
+		timeout time.Duration
+		onLeak  func(Leak)
+
+		mu      sync.Mutex
+		entries map[uintptr]*poolDebugEntry // keyed by address, so the contexts can be collected.
+		cycle   uint64                      // the number of the garbage collections forced by the debug mode.
+	}
+
+	poolDebugEntry struct {
+		leak         Leak
+		releaseCycle uint64
+		reported     bool
+	}
+)
+
+// EnableDebug enables the debug mode of the pool, it detects the contexts which are still
+// referenced "timeout" after their request ended, the classic mistake of using a Context
+// inside a goroutine which outlives the handler, and reports them to "onLeak", once per context.
+//
+// In debug mode the contexts are not reused, each request allocates a new one
+// and the garbage collector runs every "timeout" to find the contexts which could not be collected.
+// Do NOT enable it in production. It should be called before the server starts.
+//
+// Example:
+//  app.ContextPool.EnableDebug(5*time.Second, func(leak context.Leak) {
+//      app.Logger().Warn(leak)
+//  })
+func (c *Pool) EnableDebug(timeout time.Duration, onLeak func(Leak)) {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	d := &poolDebug{
+		timeout: timeout,
+		onLeak:  onLeak,
+		entries: make(map[uintptr]*poolDebugEntry),
+	}
+	c.debug = d
+
+	go func() {
+		for range time.Tick(timeout / 2) {
+			d.check()
+		}
+	}()
+}
+
+// Leaks returns the contexts which are retained after their release,
+// it's always empty when the debug mode is disabled.
+func (c *Pool) Leaks() []Leak {
+	if c.debug == nil {
+		return nil
+	}
+
+	return c.debug.leaks()
+}
+
+func contextAddr(ctx Context) uintptr {
+	return reflect.ValueOf(ctx).Pointer()
+}
+
+func (d *poolDebug) track(ctx Context) {
+	buf := make([]byte, 4096)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	addr := contextAddr(ctx)
+	d.mu.Lock()
+	d.entries[addr] = &poolDebugEntry{leak: Leak{AcquiredAt: time.Now(), Stack: string(buf)}}
+	d.mu.Unlock()
+
+	runtime.SetFinalizer(ctx, func(interface{}) {
+		d.mu.Lock()
+		delete(d.entries, addr)
+		d.mu.Unlock()
+	})
+}
+
+func (d *poolDebug) release(ctx Context) {
+	addr := contextAddr(ctx)
+
+	d.mu.Lock()
+	if entry, ok := d.entries[addr]; ok {
+		entry.leak.Method = ctx.Method()
+		entry.leak.Path = ctx.Path()
+		if route := ctx.GetCurrentRoute(); route != nil {
+			entry.leak.Route = route.Name()
+		}
+		entry.leak.ReleasedAt = time.Now()
+		entry.releaseCycle = d.cycle
+	}
+	d.mu.Unlock()
+}
+
+// isLeak reports whether the released context survived at least two garbage collections,
+// so its finalizer had the chance to run, and the timeout.
+func (d *poolDebug) isLeak(entry *poolDebugEntry, now time.Time) bool {
+	return !entry.leak.ReleasedAt.IsZero() && d.cycle-entry.releaseCycle >= 2 && now.Sub(entry.leak.ReleasedAt) >= d.timeout
+}
+
+func (d *poolDebug) check() {
+	runtime.GC()
+
+	var leaks []Leak
+	now := time.Now()
+
+	d.mu.Lock()
+	d.cycle++
+	for _, entry := range d.entries {
+		if !entry.reported && d.isLeak(entry, now) {
+			entry.reported = true
+			leaks = append(leaks, entry.leak)
+		}
+	}
+	d.mu.Unlock()
+
+	if d.onLeak != nil {
+		for _, leak := range leaks {
+			d.onLeak(leak)
+		}
+	}
+}
+
+func (d *poolDebug) leaks() []Leak {
+	var leaks []Leak
+	now := time.Now()
+
+	d.mu.Lock()
+	for _, entry := range d.entries {
+		if d.isLeak(entry, now) {
+			leaks = append(leaks, entry.leak)
+		}
+	}
+	d.mu.Unlock()
+
+	return leaks
+}
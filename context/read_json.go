@@ -0,0 +1,321 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ReadJSONOptions are the options of the `Context.ReadJSON`,
+// per call or as the defaults of a Party's routes, see `ReadJSONDefaults` and `Party.SetReadJSONOptions`.
+// When set, the body is decoded by the standard encoding/json package.
+type ReadJSONOptions struct {
+	// DisallowUnknownFields fails when the JSON object has a field
+	// which does not match any of the destination struct's fields.
+	DisallowUnknownFields bool
+	// MaxDepth is the maximum nesting of the objects and arrays, zero means unlimited.
+	MaxDepth int
+	// MaxBytes is the maximum size of the request body, zero means unlimited.
+	MaxBytes int64
+	// UseNumber decodes the numbers of interface{} values as `json.Number` instead of float64.
+	UseNumber bool
+}
+
+var (
+	// ErrJSONBodyTooLarge is the error of a `ReadJSON` call when the body exceeds the `ReadJSONOptions.MaxBytes`.
+	ErrJSONBodyTooLarge = errors.New("body too large")
+	// ErrJSONMaxDepth is the error of a `ReadJSON` call when the body exceeds the `ReadJSONOptions.MaxDepth`.
+	ErrJSONMaxDepth = errors.New("max depth exceeded")
+	// ErrJSONUnknownField is the error of a `ReadJSON` call when the body has an unknown field,
+	// see `ReadJSONOptions.DisallowUnknownFields`.
+	ErrJSONUnknownField = errors.New("unknown field")
+	// ErrJSONMaxElements is the error of a `ReadJSONStream` call
+	// when the array exceeds the `JSONStreamOptions.MaxElements`.
+	ErrJSONMaxElements = errors.New("max elements exceeded")
+	// ErrJSONNotArray is the error of a `ReadJSONStream` call when the body is not a JSON array.
+	ErrJSONNotArray = errors.New("not an array")
+)
+
+// ReadJSONError is the error of a `ReadJSON` call with `ReadJSONOptions`,
+// it names the offending field, when known, so it can be sent back to the client.
+// Use the `errors.Is` to check its reason, e.g. `errors.Is(err, context.ErrJSONUnknownField)`.
+type ReadJSONError struct {
+	// Field is the name of the offending field, if known.
+	Field string
+	// Offset is the offset of the body where the error occurred, if known.
+	Offset int64
+	// Err is the reason, i.e. one of the `ErrJSONBodyTooLarge`, `ErrJSONMaxDepth`, `ErrJSONUnknownField`
+	// or an encoding/json error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ReadJSONError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("read json: field %q: %v", e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("read json: offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the reason of the error.
+func (e *ReadJSONError) Unwrap() error {
+	return e.Err
+}
+
+const readJSONOptionsContextKey = "iris.read.json.options"
+
+// ReadJSONDefaults returns a handler which sets the "opts" as the `ReadJSONOptions`
+// of the next handlers' `ReadJSON` calls without options.
+// See `Party.SetReadJSONOptions` too.
+func ReadJSONDefaults(opts ReadJSONOptions) Handler {
+	return func(ctx Context) {
+		ctx.Values().Set(readJSONOptionsContextKey, opts)
+		ctx.Next()
+	}
+}
+
+func (ctx *context) readJSONOptions() (ReadJSONOptions, bool) {
+	if v := ctx.values.Get(readJSONOptionsContextKey); v != nil {
+		if opts, ok := v.(ReadJSONOptions); ok {
+			return opts, true
+		}
+	}
+
+	return ReadJSONOptions{}, false
+}
+
+// readJSON reads the body and binds it to the "outPtr" based on the "opts".
+func (ctx *context) readJSON(outPtr interface{}, opts ReadJSONOptions) error {
+	if ctx.request.Body == nil {
+		return fmt.Errorf("unmarshal: empty body: %w", ErrNotFound)
+	}
+
+	if opts.MaxBytes > 0 {
+		if ctx.request.ContentLength > opts.MaxBytes {
+			return &ReadJSONError{Err: ErrJSONBodyTooLarge, Offset: opts.MaxBytes}
+		}
+
+		if !ctx.recordRequestBody { // don't record a part of it.
+			ctx.request.Body = ioutil.NopCloser(io.LimitReader(ctx.request.Body, opts.MaxBytes+1))
+		}
+	}
+
+	rawData, err := ctx.GetBody()
+	if err != nil {
+		return err
+	}
+
+	if opts.MaxBytes > 0 && int64(len(rawData)) > opts.MaxBytes {
+		return &ReadJSONError{Err: ErrJSONBodyTooLarge, Offset: opts.MaxBytes}
+	}
+
+	if opts.MaxDepth > 0 {
+		if offset, ok := checkJSONDepth(rawData, opts.MaxDepth); !ok {
+			return &ReadJSONError{Err: ErrJSONMaxDepth, Offset: offset}
+		}
+	}
+
+	if decoder, isDecoder := outPtr.(BodyDecoder); isDecoder {
+		return decoder.Decode(rawData)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(rawData))
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if opts.UseNumber {
+		dec.UseNumber()
+	}
+
+	if err = dec.Decode(outPtr); err != nil {
+		return newReadJSONError(err, dec.InputOffset())
+	}
+
+	// like json.Unmarshal, the body should contain a single JSON value.
+	if _, err = dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+		return &ReadJSONError{Err: err, Offset: dec.InputOffset()}
+	}
+
+	return ctx.Application().Validate(outPtr)
+}
+
+func newReadJSONError(err error, offset int64) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return &ReadJSONError{Err: err, Offset: e.Offset}
+	case *json.UnmarshalTypeError:
+		return &ReadJSONError{Err: err, Field: e.Field, Offset: e.Offset}
+	}
+
+	// the encoding/json package does not export an unknown field error.
+	if msg := err.Error(); strings.HasPrefix(msg, "json: unknown field ") {
+		field, _ := strconv.Unquote(strings.TrimPrefix(msg, "json: unknown field "))
+		return &ReadJSONError{Err: ErrJSONUnknownField, Field: field, Offset: offset}
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return &ReadJSONError{Err: io.ErrUnexpectedEOF, Offset: offset}
+	}
+
+	return err
+}
+
+// checkJSONDepth reports whether the nesting of the "data"'s objects and arrays
+// does not exceed the "max", otherwise it returns the offset where it did.
+func checkJSONDepth(data []byte, max int) (int64, bool) {
+	var (
+		depth    int
+		inString bool
+		escaped  bool
+	)
+
+	for i, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > max {
+				return int64(i), false
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return 0, true
+}
+
+// JSONStreamOptions are the options of the `Context.ReadJSONStream`.
+type JSONStreamOptions struct {
+	// DisallowUnknownFields fails when an element has a field
+	// which does not match any of the destination struct's fields.
+	DisallowUnknownFields bool
+	// MaxElements is the maximum number of the array's elements, zero means unlimited.
+	MaxElements int
+	// MaxBytes is the maximum size of the request body, zero means unlimited.
+	MaxBytes int64
+	// UseNumber decodes the numbers of interface{} values as `json.Number` instead of float64.
+	UseNumber bool
+}
+
+// ReadJSONStream reads a JSON array from the request's body element by element,
+// without loading the whole body into memory. The "onElement" is called for each element
+// and it should decode it, e.g.
+//  err := ctx.ReadJSONStream(func(dec *json.Decoder) error {
+//      var item Item
+//      if err := dec.Decode(&item); err != nil {
+//          return err
+//      }
+//      return store(item)
+//  }, context.JSONStreamOptions{MaxElements: 10000, MaxBytes: 100 << 20})
+// An error returned by the "onElement" stops the reading and it's returned as it's.
+// The body is consumed, the `Configuration.DisableBodyConsumptionOnUnmarshal` is ignored.
+func (ctx *context) ReadJSONStream(onElement func(dec *json.Decoder) error, opts ...JSONStreamOptions) error {
+	if ctx.request.Body == nil {
+		return fmt.Errorf("unmarshal: empty body: %w", ErrNotFound)
+	}
+
+	var options JSONStreamOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	var r io.Reader = ctx.request.Body
+	if options.MaxBytes > 0 {
+		if ctx.request.ContentLength > options.MaxBytes {
+			return &ReadJSONError{Err: ErrJSONBodyTooLarge, Offset: options.MaxBytes}
+		}
+
+		r = &maxBytesReader{r: r, n: options.MaxBytes}
+	}
+
+	dec := json.NewDecoder(r)
+	if options.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if options.UseNumber {
+		dec.UseNumber()
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return newReadJSONError(err, dec.InputOffset())
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return &ReadJSONError{Err: ErrJSONNotArray}
+	}
+
+	for n := 1; dec.More(); n++ {
+		if options.MaxElements > 0 && n > options.MaxElements {
+			return &ReadJSONError{Err: ErrJSONMaxElements, Offset: dec.InputOffset()}
+		}
+
+		if err = onElement(dec); err != nil {
+			return err
+		}
+	}
+
+	// the closing bracket.
+	if _, err = dec.Token(); err != nil {
+		return newReadJSONError(err, dec.InputOffset())
+	}
+
+	if _, err = dec.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+		return newReadJSONError(err, dec.InputOffset())
+	}
+
+	return nil
+}
+
+// maxBytesReader fails with the `ErrJSONBodyTooLarge` when the "r" has more than "n" bytes.
+type maxBytesReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *maxBytesReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		var b [1]byte
+		if n, err := r.r.Read(b[:]); n > 0 {
+			return 0, ErrJSONBodyTooLarge
+		} else if err != nil {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+
+	n, err := r.r.Read(p)
+	r.n -= int64(n)
+	return n, err
+}
@@ -1,7 +1,6 @@
 package context
 
 import (
-	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -9,9 +8,14 @@ import (
 	"github.com/kataras/iris/v12/core/memstore"
 )
 
-// RequestParams is a key string - value string storage which
+// RequestParams is a key string - value storage which
 // context's request dynamic path params are being kept.
 // Empty if the route is static.
+//
+// The values of the typed parameters, e.g. {id:uint64} or {enabled:bool},
+// are stored as int64, uint64, bool and e.t.c. by the macro system at match time,
+// so the typed getters (e.g. `GetUint64`) and the hero's parameter bindings do not parse them again.
+// The rest, e.g. {name} and {name:string}, are stored as strings.
 type RequestParams struct {
 	memstore.Store
 }
@@ -40,9 +44,9 @@ func (r *RequestParams) GetEntry(key string) memstore.Entry {
 // Visit accepts a visitor which will be filled
 // by the key-value params.
 func (r *RequestParams) Visit(visitor func(key string, value string)) {
-	r.Store.Visit(func(k string, v interface{}) {
-		visitor(k, fmt.Sprintf("%v", v)) // always string here.
-	})
+	for _, entry := range r.Store {
+		visitor(entry.Key, entry.String())
+	}
 }
 
 // Get returns a path parameter's value based on its route's dynamic path key.
@@ -133,6 +133,9 @@ type responseWriter struct {
 	// Sometimes is useful to keep the event,
 	// so we keep one func only and let the user decide when he/she wants to override it with an empty func before the FireStatusCode (context's behavior)
 	beforeFlush func()
+	// beforeWriteHeader is called once, right before the status code and the headers are sent,
+	// see `onWriteHeader`.
+	beforeWriteHeader func(http.Header)
 }
 
 var _ ResponseWriter = (*responseWriter)(nil)
@@ -155,6 +158,7 @@ func (w *responseWriter) Naive() http.ResponseWriter {
 // and initialize or reset the response writer's field's values.
 func (w *responseWriter) BeginResponse(underline http.ResponseWriter) {
 	w.beforeFlush = nil
+	w.beforeWriteHeader = nil
 	w.written = NoWritten
 	w.statusCode = defaultStatusCode
 	w.ResponseWriter = underline
@@ -196,6 +200,9 @@ func (w *responseWriter) WriteHeader(statusCode int) {
 func (w *responseWriter) tryWriteHeader() {
 	if w.written == NoWritten { // before write, once.
 		w.written = StatusCodeWritten
+		if w.beforeWriteHeader != nil {
+			w.beforeWriteHeader(w.ResponseWriter.Header())
+		}
 		w.ResponseWriter.WriteHeader(w.statusCode)
 	}
 }
@@ -234,6 +241,27 @@ func (w *responseWriter) Write(contents []byte) (int, error) {
 	return n, err
 }
 
+// ReadFrom reads the data of the "src" until EOF or error and writes it to the response.
+// It implements the `io.ReaderFrom`, so the `io.Copy` and the `http.ServeContent`
+// use the underline response writer's one which sends the files
+// through the sendfile system call (zero-copy), when possible.
+func (w *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	rf, ok := w.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{w}, src)
+	}
+
+	w.tryWriteHeader()
+	n, err := rf.ReadFrom(src)
+	w.written += int(n)
+	return n, err
+}
+
+// writerOnly hides the `ReadFrom` method of a writer, to avoid the recursion of `io.Copy`.
+type writerOnly struct {
+	io.Writer
+}
+
 // Writef formats according to a format specifier and writes to the response.
 //
 // Returns the number of bytes written and any write error encountered.
@@ -256,6 +284,25 @@ func (w *responseWriter) StatusCode() int {
 	return w.statusCode
 }
 
+// onWriteHeader registers the "cb" to the underline response writer of the "w",
+// it's called with the headers to be sent right before the status code and the headers are sent.
+// It reports false if the underline response writer is not the default one.
+func onWriteHeader(w ResponseWriter, cb func(http.Header)) bool {
+	for {
+		switch v := w.(type) {
+		case *responseWriter:
+			v.beforeWriteHeader = cb
+			return true
+		case *ResponseRecorder:
+			w = v.ResponseWriter
+		case *GzipResponseWriter:
+			w = v.ResponseWriter
+		default:
+			return false
+		}
+	}
+}
+
 func (w *responseWriter) GetBeforeFlush() func() {
 	return w.beforeFlush
 }
@@ -61,6 +61,21 @@ type RouteReadOnly interface {
 
 	// MainHandlerIndex returns the first registered handler's index for the route.
 	MainHandlerIndex() int
+	// HandlersNames returns the names of the route's handlers chain (middleware included), by execution order.
+	HandlersNames() []string
+
+	// SourceFileName returns the source file of the route's main handler.
+	SourceFileName() string
+	// SourceLineNumber returns the source line of the route's main handler.
+	SourceLineNumber() int
+	// RegisterFileName returns the file that this route was registered from.
+	RegisterFileName() string
+	// RegisterLineNumber returns the line that this route was registered from.
+	RegisterLineNumber() int
+	// PartyPath returns the relative path of the Party that this route was registered to.
+	PartyPath() string
+	// GetMetadata returns the custom information attached to this route, if any.
+	GetMetadata() Map
 
 	// StaticSites if not empty, refers to the system (or virtual if embedded) directory
 	// and sub directories that this "GET" route was registered to serve files and folders
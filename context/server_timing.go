@@ -0,0 +1,195 @@
+package context
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerTimingHeaderKey is the header key of "Server-Timing".
+const ServerTimingHeaderKey = "Server-Timing"
+
+// ServerTimingMetric is a metric of the Server-Timing header, see `ServerTiming`.
+type ServerTimingMetric struct {
+	// Name is the metric's name, a token, i.e "db".
+	Name string `json:"name"`
+	// Duration is the metric's duration, sent in milliseconds, zero means no duration.
+	Duration time.Duration `json:"duration"`
+	// Description is the optional, human-readable, description of the metric.
+	Description string `json:"description,omitempty"`
+}
+
+// String returns the metric as a Server-Timing header's value,
+// i.e `db;dur=12.30;desc="users query"`.
+func (m ServerTimingMetric) String() string {
+	var b strings.Builder
+	b.WriteString(serverTimingToken(m.Name))
+	if m.Duration > 0 {
+		b.WriteString(";dur=")
+		b.WriteString(strconv.FormatFloat(float64(m.Duration)/float64(time.Millisecond), 'f', 2, 64))
+	}
+
+	if m.Description != "" {
+		b.WriteString(";desc=")
+		b.WriteString(quoteServerTimingDescription(m.Description))
+	}
+
+	return b.String()
+}
+
+// ServerTiming accumulates the metrics of the Server-Timing response header
+// (https://www.w3.org/TR/server-timing/), see `Context.ServerTiming`.
+// It's safe for concurrent use.
+type ServerTiming struct {
+	mu      sync.Mutex
+	metrics []ServerTimingMetric
+	// the number of the metrics sent through the header, -1 if not sent yet.
+	sent int
+}
+
+func newServerTiming() *ServerTiming {
+	return &ServerTiming{sent: -1}
+}
+
+// Add adds a metric of the "name", "dur" duration and the optional "desc" description, i.e
+//  ctx.ServerTiming().Add("db", time.Since(start), "users query")
+// Invalid characters of the "name" are replaced with underscores.
+//
+// Returns itself.
+func (st *ServerTiming) Add(name string, dur time.Duration, desc string) *ServerTiming {
+	st.mu.Lock()
+	st.metrics = append(st.metrics, ServerTimingMetric{Name: name, Duration: dur, Description: desc})
+	st.mu.Unlock()
+	return st
+}
+
+// Start starts measuring a metric of the "name" and the "desc" description
+// and returns the function which adds it, i.e
+//  defer ctx.ServerTiming().Start("db", "users query")()
+func (st *ServerTiming) Start(name, desc string) (end func()) {
+	start := time.Now()
+	return func() {
+		st.Add(name, time.Since(start), desc)
+	}
+}
+
+// Metrics returns a copy of the added metrics.
+func (st *ServerTiming) Metrics() []ServerTimingMetric {
+	st.mu.Lock()
+	metrics := make([]ServerTimingMetric, len(st.metrics))
+	copy(metrics, st.metrics)
+	st.mu.Unlock()
+	return metrics
+}
+
+// String returns the value of the Server-Timing header of the added metrics.
+func (st *ServerTiming) String() string {
+	return joinServerTimingMetrics(st.Metrics())
+}
+
+func joinServerTimingMetrics(metrics []ServerTimingMetric) string {
+	values := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		values = append(values, m.String())
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// writeHeader sets the Server-Timing header of the added metrics, once.
+// Called right before the response headers are sent.
+func (st *ServerTiming) writeHeader(h http.Header) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.sent != -1 {
+		return
+	}
+
+	st.sent = len(st.metrics)
+	if st.sent > 0 {
+		h.Set(ServerTimingHeaderKey, joinServerTimingMetrics(st.metrics))
+	}
+}
+
+// end sends the metrics which were not sent through the header,
+// as a header if the response headers are not sent yet,
+// otherwise (i.e streaming) as a trailer after the response body,
+// which requires a chunked (without Content-Length) or an HTTP/2 response.
+// Called by the Context on `EndRequest`.
+func (st *ServerTiming) end(w ResponseWriter) {
+	if st.sent == -1 && w.Written() == NoWritten {
+		st.writeHeader(w.Header())
+		return
+	}
+
+	st.mu.Lock()
+	from := st.sent
+	if from == -1 { // a custom response writer which sent the headers.
+		from = 0
+	}
+	metrics := st.metrics[from:]
+	st.mu.Unlock()
+
+	if len(metrics) > 0 {
+		w.Header().Set(http.TrailerPrefix+ServerTimingHeaderKey, joinServerTimingMetrics(metrics))
+	}
+}
+
+// serverTimingToken replaces the invalid token (RFC 7230, section 3.2.6) characters of the "s" with underscores.
+func serverTimingToken(s string) string {
+	if isServerTimingToken(s) {
+		return s
+	}
+
+	if s == "" {
+		return "_"
+	}
+
+	return strings.Map(func(c rune) rune {
+		if isServerTimingTokenChar(c) {
+			return c
+		}
+
+		return '_'
+	}, s)
+}
+
+func isServerTimingToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if !isServerTimingTokenChar(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isServerTimingTokenChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	default:
+		return strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+	}
+}
+
+// quoteServerTimingDescription returns the "s" as a quoted-string (RFC 7230, section 3.2.6).
+func quoteServerTimingDescription(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		if c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
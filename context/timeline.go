@@ -0,0 +1,149 @@
+package context
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TimelineSegment is a timed phase of a request, see `Timeline`.
+type TimelineSegment struct {
+	Name string `json:"name"`
+	// Offset is the start of the segment since the start of the timeline.
+	Offset time.Duration `json:"offset"`
+	// Duration is the time spent on the segment, including its nested ones.
+	// It's negative while the segment is not ended yet.
+	Duration time.Duration `json:"duration"`
+	// Depth is the nesting level of the segment, i.e
+	// a handler's segment is nested to its previous middleware's one.
+	Depth int `json:"depth"`
+}
+
+// Timeline records the timed phases (segments) of a request,
+// e.g. the handlers, the session's load, the view's render and the response writes,
+// so the developers can see where the latency goes.
+//
+// Its methods can be called on a nil Timeline, they do nothing,
+// so the instrumented code does not have to check if a timeline is enabled.
+//
+// See `Context.Timeline` and the iris/middleware/timeline package.
+type Timeline struct {
+	start time.Time
+
+	mu       sync.Mutex
+	segments []TimelineSegment
+	depth    int
+}
+
+// NewTimeline returns a new Timeline which starts now.
+func NewTimeline() *Timeline {
+	return &Timeline{start: time.Now()}
+}
+
+func noopEnd() {}
+
+// Begin starts a segment of the "name" and returns the function which ends it,
+// it's commonly used as:
+//  defer ctx.Timeline().Begin("db")()
+func (t *Timeline) Begin(name string) (end func()) {
+	if t == nil {
+		return noopEnd
+	}
+
+	now := time.Now()
+
+	t.mu.Lock()
+	idx := len(t.segments)
+	t.segments = append(t.segments, TimelineSegment{
+		Name:     name,
+		Offset:   now.Sub(t.start),
+		Duration: -1,
+		Depth:    t.depth,
+	})
+	t.depth++
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d := time.Since(now)
+
+			t.mu.Lock()
+			t.segments[idx].Duration = d
+			t.depth--
+			t.mu.Unlock()
+		})
+	}
+}
+
+// Segments returns a copy of the recorded segments, by start order.
+func (t *Timeline) Segments() []TimelineSegment {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	segments := make([]TimelineSegment, len(t.segments))
+	copy(segments, t.segments)
+	t.mu.Unlock()
+
+	return segments
+}
+
+// Elapsed returns the time passed since the start of the timeline.
+func (t *Timeline) Elapsed() time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	return time.Since(t.start)
+}
+
+// ServerTimingMetrics returns the Server-Timing metrics of the ended segments and the "total" time.
+// The durations of the segments with the same name, e.g. "write", are summed.
+// Segment names which are not valid tokens are replaced by "h" followed by the segment's index
+// and the original name is set as the description.
+func (t *Timeline) ServerTimingMetrics() []ServerTimingMetric {
+	if t == nil {
+		return nil
+	}
+
+	var (
+		metrics []ServerTimingMetric
+		indices = make(map[string]int)
+	)
+
+	for i, s := range t.Segments() {
+		if s.Duration < 0 {
+			continue
+		}
+
+		m := ServerTimingMetric{Name: s.Name, Duration: s.Duration}
+		if !isServerTimingToken(m.Name) {
+			m.Name = "h" + strconv.Itoa(i)
+			m.Description = s.Name
+		}
+
+		if idx, ok := indices[m.Name]; ok {
+			metrics[idx].Duration += m.Duration
+			continue
+		}
+
+		indices[m.Name] = len(metrics)
+		metrics = append(metrics, m)
+	}
+
+	return append(metrics, ServerTimingMetric{Name: "total", Duration: t.Elapsed()})
+}
+
+// ServerTiming returns the value of a "Server-Timing" response header
+// of the `ServerTimingMetrics`, i.e
+// `session;dur=0.52, h1;dur=12.10;desc="main.listUsers", total;dur=13.40`.
+func (t *Timeline) ServerTiming() string {
+	if t == nil {
+		return ""
+	}
+
+	st := &ServerTiming{metrics: t.ServerTimingMetrics()}
+	return st.String()
+}
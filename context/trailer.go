@@ -0,0 +1,42 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"io"
+)
+
+const (
+	// TrailerHeaderKey is the header key which declares the trailers of a response.
+	TrailerHeaderKey = "Trailer"
+	// DigestHeaderKey is the default trailer key of the `Context.DigestWriter`.
+	DigestHeaderKey = "Digest"
+)
+
+// digestWriter writes to the response and computes the SHA-256 digest of the written body,
+// see `Context.DigestWriter`.
+type digestWriter struct {
+	w       io.Writer
+	h       hash.Hash
+	trailer string
+}
+
+func newDigestWriter(w io.Writer, trailer string) *digestWriter {
+	return &digestWriter{
+		w:       w,
+		h:       sha256.New(),
+		trailer: trailer,
+	}
+}
+
+func (d *digestWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.h.Write(p[:n])
+	return n, err
+}
+
+// value returns the digest in the RFC 3230 format, i.e "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=".
+func (d *digestWriter) value() string {
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(d.h.Sum(nil))
+}
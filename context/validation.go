@@ -0,0 +1,104 @@
+package context
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ValidationErrorsContextKey is the context key which the validation errors
+// of a failed request payload are stored, see `NewValidationProblem`.
+const ValidationErrorsContextKey = "iris.validation.errors"
+
+type (
+	// ValidationError describes a single field's validation failure.
+	ValidationError struct {
+		Field   string      `json:"field" xml:"field"`
+		Tag     string      `json:"tag,omitempty" xml:"tag,omitempty"`
+		Param   string      `json:"param,omitempty" xml:"param,omitempty"`
+		Value   interface{} `json:"value,omitempty" xml:"-"`
+		Message string      `json:"message" xml:"message"`
+	}
+
+	// ValidationErrors is a list of `ValidationError`,
+	// custom `Validator` implementations may return it directly.
+	ValidationErrors []ValidationError
+)
+
+// Error completes the error interface.
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// fieldError is implemented by the go-playground/validator's FieldError.
+type fieldError interface {
+	error
+	Field() string
+	Tag() string
+	Param() string
+	Value() interface{}
+}
+
+// AsValidationErrors reports whether the "err" is (or wraps) a validation failure
+// and converts it to `ValidationErrors`. It accepts the `ValidationErrors` type
+// and any list of field errors like the go-playground/validator's ValidationErrors one.
+func AsValidationErrors(err error) (ValidationErrors, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return errs, true
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		if v.Kind() != reflect.Slice || v.Len() == 0 {
+			continue
+		}
+
+		errs = make(ValidationErrors, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			fe, ok := v.Index(i).Interface().(fieldError)
+			if !ok {
+				break
+			}
+
+			errs = append(errs, ValidationError{
+				Field:   fe.Field(),
+				Tag:     fe.Tag(),
+				Param:   fe.Param(),
+				Value:   fe.Value(),
+				Message: fe.Error(),
+			})
+		}
+
+		if len(errs) == v.Len() {
+			return errs, true
+		}
+	}
+
+	return nil, false
+}
+
+// NewValidationProblem returns a new 422 Unprocessable Entity `Problem`
+// which contains the validation "errs" under its "errors" key.
+// The "errs" are also stored to the context's values (see `ValidationErrorsContextKey`),
+// so a registered error code handler can render them differently.
+func NewValidationProblem(ctx Context, errs ValidationErrors) Problem {
+	ctx.Values().Set(ValidationErrorsContextKey, errs)
+
+	return NewProblem().
+		Type("validation-error").
+		Title("Validation Failure").
+		Status(http.StatusUnprocessableEntity).
+		Detail("One or more fields failed to be validated").
+		Key("errors", errs)
+}
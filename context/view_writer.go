@@ -0,0 +1,67 @@
+package context
+
+import (
+	"bytes"
+	"sync"
+)
+
+var viewBufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// viewWriter buffers the first "limit" bytes of a template's output
+// and streams the rest directly to the response writer,
+// so an early render error can still change the status code
+// but large pages are not kept in memory as a whole.
+// A negative "limit" buffers the whole output.
+type viewWriter struct {
+	ctx     *context
+	buf     *bytes.Buffer
+	limit   int
+	flushed bool
+}
+
+func newViewWriter(ctx *context, limit int) *viewWriter {
+	buf := viewBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+
+	return &viewWriter{ctx: ctx, buf: buf, limit: limit}
+}
+
+func (w *viewWriter) Write(p []byte) (int, error) {
+	if w.flushed {
+		return w.ctx.Write(p)
+	}
+
+	if w.limit < 0 || w.buf.Len()+len(p) <= w.limit {
+		return w.buf.Write(p)
+	}
+
+	if err := w.flush(); err != nil {
+		return 0, err
+	}
+
+	return w.ctx.Write(p)
+}
+
+// flush writes the buffered output, if any, and switches to streaming.
+func (w *viewWriter) flush() error {
+	w.flushed = true
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.ctx.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// release returns the buffer to the pool, the writer should not be used after that.
+func (w *viewWriter) release() {
+	if w.buf.Cap() <= maxPooledViewBufferSize {
+		viewBufferPool.Put(w.buf)
+	}
+	w.buf = nil
+}
+
+// maxPooledViewBufferSize is the maximum capacity of a buffer which is returned to the pool,
+// the larger ones (e.g. of the whole buffered pages) are left to the garbage collector.
+const maxPooledViewBufferSize = 256 << 10
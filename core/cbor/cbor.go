@@ -0,0 +1,887 @@
+// Package cbor implements encoding and decoding of the Concise Binary Object Representation (RFC 8949)
+// data format, it is used by the `Context.ReadCBOR` and `Context.CBOR` methods.
+//
+// Go values are mapped like the encoding/json package does:
+// struct fields are encoded as map entries, their names can be customized
+// through the "cbor" struct field tag (or the "json" one if missing),
+// i.e. `cbor:"name,omitempty"` or `cbor:"-"` to skip a field.
+// Byte slices are encoded as byte strings and time.Time values as RFC 3339 text (tag 0).
+package cbor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CBOR major types.
+const (
+	majorUint byte = iota
+	majorNegInt
+	majorBytes
+	majorText
+	majorArray
+	majorMap
+	majorTag
+	majorSimple
+)
+
+const (
+	simpleFalse          = 20
+	simpleTrue           = 21
+	simpleNull           = 22
+	simpleUndefined      = 23
+	additionalFloat16    = 25
+	additionalFloat32    = 26
+	additionalFloat64    = 27
+	additionalIndefinite = 31
+	breakCode            = 0xff
+
+	tagDateTimeString = 0
+	tagEpochDateTime  = 1
+
+	maxDepth = 256
+)
+
+var (
+	// ErrUnexpectedEnd is returned when the data are incomplete.
+	ErrUnexpectedEnd = errors.New("cbor: unexpected end of data")
+	// ErrMaxDepth is returned when the data are nested too deeply.
+	ErrMaxDepth = errors.New("cbor: exceeded max nesting depth")
+
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// Marshal returns the CBOR encoding of "v".
+func Marshal(v interface{}) ([]byte, error) {
+	e := &encoder{}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// Unmarshal parses the CBOR encoded "data" and stores the result to the value pointed by "v".
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cbor: unmarshal: non-nil pointer expected but got: %T", v)
+	}
+
+	d := &decoder{data: data}
+	if err := d.decode(rv.Elem(), 0); err != nil {
+		return err
+	}
+
+	if d.off != len(d.data) {
+		return fmt.Errorf("cbor: unmarshal: %d unexpected trailing bytes", len(d.data)-d.off)
+	}
+
+	return nil
+}
+
+//  +------------------------------------------------------------+
+//  | Struct fields                                              |
+//  +------------------------------------------------------------+
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+var fieldsCache sync.Map // map[reflect.Type][]field
+
+func structFields(typ reflect.Type) []field {
+	if v, ok := fieldsCache.Load(typ); ok {
+		return v.([]field)
+	}
+
+	fields := collectFields(typ, nil)
+	fieldsCache.Store(typ, fields)
+	return fields
+}
+
+func collectFields(typ reflect.Type, index []int) (fields []field) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+
+		tag, ok := f.Tag.Lookup("cbor")
+		if !ok {
+			tag = f.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(f.Type, fieldIndex)...)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			index:     fieldIndex,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+
+	return
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+//  +------------------------------------------------------------+
+//  | Encoder                                                    |
+//  +------------------------------------------------------------+
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) writeHead(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		e.buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.Write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		var b [3]byte
+		b[0] = major | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		e.buf.Write(b[:])
+	case n <= math.MaxUint32:
+		var b [5]byte
+		b[0] = major | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		e.buf.Write(b[:])
+	default:
+		var b [9]byte
+		b[0] = major | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		e.buf.Write(b[:])
+	}
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.buf.WriteByte(majorSimple<<5 | simpleNull)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		e.writeHead(majorTag, tagDateTimeString)
+		s := t.Format(time.RFC3339Nano)
+		e.writeHead(majorText, uint64(len(s)))
+		e.buf.WriteString(s)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf.WriteByte(majorSimple<<5 | simpleTrue)
+		} else {
+			e.buf.WriteByte(majorSimple<<5 | simpleFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n < 0 {
+			e.writeHead(majorNegInt, uint64(-(n + 1)))
+		} else {
+			e.writeHead(majorUint, uint64(n))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeHead(majorUint, v.Uint())
+	case reflect.Float32:
+		var b [5]byte
+		b[0] = majorSimple<<5 | additionalFloat32
+		binary.BigEndian.PutUint32(b[1:], math.Float32bits(float32(v.Float())))
+		e.buf.Write(b[:])
+	case reflect.Float64:
+		var b [9]byte
+		b[0] = majorSimple<<5 | additionalFloat64
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(v.Float()))
+		e.buf.Write(b[:])
+	case reflect.String:
+		s := v.String()
+		e.writeHead(majorText, uint64(len(s)))
+		e.buf.WriteString(s)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf.WriteByte(majorSimple<<5 | simpleNull)
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.writeHead(majorBytes, uint64(len(b)))
+			e.buf.Write(b)
+			return nil
+		}
+
+		e.writeHead(majorArray, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf.WriteByte(majorSimple<<5 | simpleNull)
+			return nil
+		}
+
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteByte(majorSimple<<5 | simpleNull)
+			return nil
+		}
+
+		return e.encode(v.Elem())
+	default:
+		return fmt.Errorf("cbor: unsupported type: %s", v.Type())
+	}
+
+	return nil
+}
+
+// encodeMap encodes the map entries sorted by their encoded keys (deterministic encoding).
+func (e *encoder) encodeMap(v reflect.Value) error {
+	type entry struct {
+		key, value []byte
+	}
+
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		ke := &encoder{}
+		if err := ke.encode(iter.Key()); err != nil {
+			return err
+		}
+
+		ve := &encoder{}
+		if err := ve.encode(iter.Value()); err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{key: ke.buf.Bytes(), value: ve.buf.Bytes()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.writeHead(majorMap, uint64(len(entries)))
+	for _, entry := range entries {
+		e.buf.Write(entry.key)
+		e.buf.Write(entry.value)
+	}
+
+	return nil
+}
+
+func (e *encoder) encodeStruct(v reflect.Value) error {
+	fields := structFields(v.Type())
+
+	values := make([]reflect.Value, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index, false)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+
+		values = append(values, fv)
+		names = append(names, f.name)
+	}
+
+	e.writeHead(majorMap, uint64(len(values)))
+	for i, fv := range values {
+		e.writeHead(majorText, uint64(len(names[i])))
+		e.buf.WriteString(names[i])
+		if err := e.encode(fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex returns the nested field of "v",
+// it allocates nil embedded pointers when "alloc" is true.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+
+	return v, true
+}
+
+//  +------------------------------------------------------------+
+//  | Decoder                                                    |
+//  +------------------------------------------------------------+
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+// head reads the initial byte and the argument of the next data item.
+func (d *decoder) head() (major, additional byte, arg uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, ErrUnexpectedEnd
+	}
+
+	b := d.data[d.off]
+	d.off++
+	major, additional = b>>5, b&0x1f
+
+	var n int
+	switch {
+	case additional < 24:
+		return major, additional, uint64(additional), nil
+	case additional == 24:
+		n = 1
+	case additional == 25:
+		n = 2
+	case additional == 26:
+		n = 4
+	case additional == 27:
+		n = 8
+	case additional == additionalIndefinite:
+		return major, additional, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid additional information: %d", additional)
+	}
+
+	if len(d.data)-d.off < n {
+		return 0, 0, 0, ErrUnexpectedEnd
+	}
+
+	buf := d.data[d.off : d.off+n]
+	d.off += n
+	switch n {
+	case 1:
+		arg = uint64(buf[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(buf))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(buf))
+	default:
+		arg = binary.BigEndian.Uint64(buf)
+	}
+
+	return major, additional, arg, nil
+}
+
+func (d *decoder) isBreak() bool {
+	if d.off < len(d.data) && d.data[d.off] == breakCode {
+		d.off++
+		return true
+	}
+
+	return false
+}
+
+// readString reads the contents of a (possible indefinite length) byte or text string.
+func (d *decoder) readString(major, additional byte, arg uint64) ([]byte, error) {
+	if additional != additionalIndefinite {
+		if uint64(len(d.data)-d.off) < arg {
+			return nil, ErrUnexpectedEnd
+		}
+
+		b := d.data[d.off : d.off+int(arg)]
+		d.off += int(arg)
+		return b, nil
+	}
+
+	var buf []byte
+	for !d.isBreak() {
+		m, a, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+
+		if m != major || a == additionalIndefinite {
+			return nil, fmt.Errorf("cbor: invalid indefinite length string chunk")
+		}
+
+		chunk, err := d.readString(m, a, n)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, chunk...)
+	}
+
+	return buf, nil
+}
+
+func (d *decoder) decode(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return ErrMaxDepth
+	}
+
+	// null and undefined set the zero value.
+	if d.off < len(d.data) {
+		if b := d.data[d.off]; b == majorSimple<<5|simpleNull || b == majorSimple<<5|simpleUndefined {
+			d.off++
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(v.Elem(), depth)
+	}
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		value, err := d.decodeAny(depth)
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.Set(reflect.Zero(v.Type()))
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+
+	major, additional, arg, err := d.head()
+	if err != nil {
+		return err
+	}
+
+	if major == majorTag {
+		if v.Type() == timeType {
+			return d.decodeTime(v, arg, depth)
+		}
+		// ignore other tags.
+		return d.decode(v, depth+1)
+	}
+
+	switch major {
+	case majorUint, majorNegInt:
+		return setInt(v, major, arg)
+	case majorBytes, majorText:
+		b, err := d.readString(major, additional, arg)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(string(b))
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			v.SetBytes(append([]byte(nil), b...))
+		case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+			reflect.Copy(v, reflect.ValueOf(b))
+		default:
+			return typeError(major, v)
+		}
+		return nil
+	case majorArray:
+		return d.decodeArray(v, additional, arg, depth)
+	case majorMap:
+		return d.decodeMap(v, additional, arg, depth)
+	case majorSimple:
+		switch additional {
+		case simpleFalse, simpleTrue:
+			if v.Kind() != reflect.Bool {
+				return typeError(major, v)
+			}
+			v.SetBool(additional == simpleTrue)
+			return nil
+		case additionalFloat16, additionalFloat32, additionalFloat64:
+			f := decodeFloat(additional, arg)
+			switch v.Kind() {
+			case reflect.Float32, reflect.Float64:
+				v.SetFloat(f)
+			default:
+				return typeError(major, v)
+			}
+			return nil
+		}
+	}
+
+	return typeError(major, v)
+}
+
+func (d *decoder) decodeTime(v reflect.Value, tag uint64, depth int) error {
+	value, err := d.decodeAny(depth + 1)
+	if err != nil {
+		return err
+	}
+
+	var t time.Time
+	switch tag {
+	case tagDateTimeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("cbor: invalid date/time string")
+		}
+		if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return err
+		}
+	case tagEpochDateTime:
+		switch n := value.(type) {
+		case int64:
+			t = time.Unix(n, 0)
+		case uint64:
+			t = time.Unix(int64(n), 0)
+		case float64:
+			sec, frac := math.Modf(n)
+			t = time.Unix(int64(sec), int64(frac*1e9))
+		default:
+			return fmt.Errorf("cbor: invalid epoch date/time")
+		}
+	default:
+		return fmt.Errorf("cbor: cannot decode tag %d into time.Time", tag)
+	}
+
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
+
+func (d *decoder) decodeArray(v reflect.Value, additional byte, arg uint64, depth int) error {
+	indefinite := additional == additionalIndefinite
+
+	switch v.Kind() {
+	case reflect.Slice:
+		// each item takes one byte at least, protect from huge allocations.
+		if !indefinite && arg > uint64(len(d.data)-d.off) {
+			return ErrUnexpectedEnd
+		}
+
+		n := int(arg)
+		slice := reflect.MakeSlice(v.Type(), 0, n)
+		for i := 0; indefinite || i < n; i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(elem, depth+1); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+
+		v.Set(slice)
+		return nil
+	case reflect.Array:
+		for i := 0; indefinite || i < int(arg); i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+
+			if i < v.Len() {
+				if err := d.decode(v.Index(i), depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := d.skip(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return typeError(majorArray, v)
+}
+
+func (d *decoder) decodeMap(v reflect.Value, additional byte, arg uint64, depth int) error {
+	indefinite := additional == additionalIndefinite
+	if !indefinite && arg > uint64(len(d.data)-d.off) {
+		return ErrUnexpectedEnd
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		for i := 0; indefinite || i < int(arg); i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decode(key, depth+1); err != nil {
+				return err
+			}
+
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(value, depth+1); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(key, value)
+		}
+		return nil
+	case reflect.Struct:
+		fields := structFields(v.Type())
+
+		for i := 0; indefinite || i < int(arg); i++ {
+			if indefinite && d.isBreak() {
+				break
+			}
+
+			var name string
+			if err := d.decode(reflect.ValueOf(&name).Elem(), depth+1); err != nil {
+				return err
+			}
+
+			f, ok := findField(fields, name)
+			if !ok {
+				if err := d.skip(depth + 1); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fv, _ := fieldByIndex(v, f.index, true)
+			if err := d.decode(fv, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return typeError(majorMap, v)
+}
+
+func findField(fields []field, name string) (field, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+// decodeAny decodes the next data item to its generic Go value:
+// int64 or uint64, float64, bool, string, []byte, []interface{}, map[string]interface{}
+// (or map[interface{}]interface{} if keys are not strings), time.Time or nil.
+func (d *decoder) decodeAny(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, ErrMaxDepth
+	}
+
+	major, additional, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUint:
+		if arg <= math.MaxInt64 {
+			return int64(arg), nil
+		}
+		return arg, nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("cbor: negative integer overflows int64")
+		}
+		return -1 - int64(arg), nil
+	case majorBytes:
+		b, err := d.readString(major, additional, arg)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case majorText:
+		b, err := d.readString(major, additional, arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case majorArray:
+		var items []interface{}
+		err := d.decodeArray(reflect.ValueOf(&items).Elem(), additional, arg, depth)
+		return items, err
+	case majorMap:
+		m := make(map[interface{}]interface{})
+		err := d.decodeMap(reflect.ValueOf(&m).Elem(), additional, arg, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		sm := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			s, ok := k.(string)
+			if !ok {
+				return m, nil
+			}
+			sm[s] = v
+		}
+		return sm, nil
+	case majorTag:
+		if arg == tagDateTimeString || arg == tagEpochDateTime {
+			var t time.Time
+			err := d.decodeTime(reflect.ValueOf(&t).Elem(), arg, depth)
+			return t, err
+		}
+		return d.decodeAny(depth + 1)
+	default: // majorSimple.
+		switch additional {
+		case simpleFalse:
+			return false, nil
+		case simpleTrue:
+			return true, nil
+		case simpleNull, simpleUndefined:
+			return nil, nil
+		case additionalFloat16, additionalFloat32, additionalFloat64:
+			return decodeFloat(additional, arg), nil
+		}
+
+		return nil, fmt.Errorf("cbor: unsupported simple value: %d", additional)
+	}
+}
+
+func (d *decoder) skip(depth int) error {
+	_, err := d.decodeAny(depth)
+	return err
+}
+
+func setInt(v reflect.Value, major byte, arg uint64) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if arg > math.MaxInt64 {
+			return fmt.Errorf("cbor: integer overflows %s", v.Type())
+		}
+
+		n := int64(arg)
+		if major == majorNegInt {
+			n = -1 - n
+		}
+
+		if v.OverflowInt(n) {
+			return fmt.Errorf("cbor: integer %d overflows %s", n, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if major == majorNegInt || v.OverflowUint(arg) {
+			return fmt.Errorf("cbor: integer overflows %s", v.Type())
+		}
+		v.SetUint(arg)
+	case reflect.Float32, reflect.Float64:
+		f := float64(arg)
+		if major == majorNegInt {
+			f = -1 - f
+		}
+		v.SetFloat(f)
+	default:
+		return typeError(major, v)
+	}
+
+	return nil
+}
+
+func decodeFloat(additional byte, arg uint64) float64 {
+	switch additional {
+	case additionalFloat16:
+		return float16ToFloat64(uint16(arg))
+	case additionalFloat32:
+		return float64(math.Float32frombits(uint32(arg)))
+	default:
+		return math.Float64frombits(arg)
+	}
+}
+
+func float16ToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	default:
+		return sign * math.Ldexp(mant+1024, exp-25)
+	}
+}
+
+func typeError(major byte, v reflect.Value) error {
+	names := [...]string{"unsigned integer", "negative integer", "byte string", "text string", "array", "map", "tag", "simple value"}
+	return fmt.Errorf("cbor: cannot decode %s into %s", names[major&7], v.Type())
+}
@@ -0,0 +1,184 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMarshalRFCExamples(t *testing.T) {
+	// See RFC 8949, Appendix A.
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{uint64(18446744073709551615), "1bffffffffffffffff"},
+		{-1, "20"},
+		{-1000, "3903e7"},
+		{1.1, "fb3ff199999999999a"},
+		{float32(100000.0), "fa47c35000"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]int{1, 2, 3}, "83010203"},
+		{[]interface{}{1, []int{2, 3}, []int{4, 5}}, "8301820203820405"},
+		{map[string]interface{}{"a": 1, "b": []int{2, 3}}, "a26161016162820203"},
+		{map[int]int{3: 4, 1: 2}, "a201020304"},
+	}
+
+	for i, tt := range tests {
+		b, err := Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got := hex.EncodeToString(b); got != tt.expected {
+			t.Fatalf("[%d] expected: %s but got: %s", i, tt.expected, got)
+		}
+	}
+}
+
+func TestUnmarshalRFCExamples(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected interface{}
+	}{
+		{"00", int64(0)},
+		{"1bffffffffffffffff", uint64(18446744073709551615)},
+		{"3903e7", int64(-1000)},
+		{"f93c00", 1.0},
+		{"f9c400", -4.0},
+		{"f97c00", math.Inf(1)},
+		{"fa47c35000", 100000.0},
+		{"f5", true},
+		{"f6", nil},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"62c3bc", "ü"},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9f018202039f0405ffff", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		{"bf61610161629f0203ffff", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+		{"a201020304", map[interface{}]interface{}{int64(1): int64(2), int64(3): int64(4)}},
+		{"c11a514b67b0", time.Unix(1363896240, 0)},
+	}
+
+	for i, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+
+		var got interface{}
+		if err := Unmarshal(data, &got); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if !reflect.DeepEqual(tt.expected, got) {
+			t.Fatalf("[%d] expected: %#+v but got: %#+v", i, tt.expected, got)
+		}
+	}
+}
+
+type (
+	base struct {
+		ID uint64 `cbor:"id"`
+	}
+
+	user struct {
+		base
+		Username  string            `cbor:"username"`
+		Email     string            `json:"email,omitempty"`
+		Age       int8              `cbor:"age"`
+		Score     float64           `cbor:"score"`
+		Active    bool              `cbor:"active"`
+		Tags      []string          `cbor:"tags"`
+		Meta      map[string]string `cbor:"meta"`
+		Avatar    []byte            `cbor:"avatar"`
+		Manager   *user             `cbor:"manager,omitempty"`
+		CreatedAt time.Time         `cbor:"created_at"`
+		Secret    string            `cbor:"-"`
+		internal  int
+	}
+)
+
+func TestMarshalUnmarshalStruct(t *testing.T) {
+	expected := user{
+		base:      base{ID: 42},
+		Username:  "makis",
+		Age:       -3,
+		Score:     9.5,
+		Active:    true,
+		Tags:      []string{"a", "b"},
+		Meta:      map[string]string{"k": "v"},
+		Avatar:    []byte{0xde, 0xad},
+		Manager:   &user{Username: "gerasimos"},
+		CreatedAt: time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC),
+		Secret:    "ignored",
+		internal:  1,
+	}
+
+	b, err := Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(b, []byte("ignored")) || bytes.Contains(b, []byte("email")) {
+		t.Fatalf("expected skipped and empty fields to be omitted")
+	}
+
+	var got user
+	if err = Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected.Secret = ""
+	expected.internal = 0
+	if !got.CreatedAt.Equal(expected.CreatedAt) {
+		t.Fatalf("expected time: %s but got: %s", expected.CreatedAt, got.CreatedAt)
+	}
+	got.CreatedAt = expected.CreatedAt
+	got.Manager.CreatedAt = expected.Manager.CreatedAt
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected: %#+v but got: %#+v", expected, got)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		ptr  interface{}
+	}{
+		{"", new(int)},
+		{"1903", new(int)},
+		{"6449", new(string)},
+		{"1903e8", new(int8)},
+		{"20", new(uint)},
+		{"6449455446", new(int)},
+		{"9bffffffffffffffff", new([]int)},
+		{"0000", new(int)},
+	}
+
+	for i, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		if err := Unmarshal(data, tt.ptr); err == nil {
+			t.Fatalf("[%d] expected an error", i)
+		}
+	}
+
+	if err := Unmarshal([]byte{0}, user{}); err == nil {
+		t.Fatalf("expected an error for non-pointer value")
+	}
+
+	nested := bytes.Repeat([]byte{0x81}, maxDepth+2)
+	var v interface{}
+	if err := Unmarshal(append(nested, 0), &v); err != ErrMaxDepth {
+		t.Fatalf("expected error: %v but got: %v", ErrMaxDepth, err)
+	}
+}
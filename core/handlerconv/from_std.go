@@ -1,6 +1,7 @@
 package handlerconv
 
 import (
+	stdContext "context"
 	"fmt"
 	"net/http"
 
@@ -13,6 +14,12 @@ import (
 // 		 .FromStd(h http.Handler)
 // 		 .FromStd(func(w http.ResponseWriter, r *http.Request))
 // 		 .FromStd(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc))
+//
+// The route's path parameters are passed to the standard handler
+// through its request's context, see the `Param` and `Params` functions.
+// Note that the request's path is kept as it is, e.g.
+// mount a standard mux (which registers its routes with the full path) with:
+// app.Any("/legacy/{p:path}", iris.FromStd(mux)).
 func FromStd(handler interface{}) context.Handler {
 	switch h := handler.(type) {
 	case context.Handler:
@@ -29,7 +36,7 @@ func FromStd(handler interface{}) context.Handler {
 		//
 		{
 			return func(ctx context.Context) {
-				h.ServeHTTP(ctx.ResponseWriter(), ctx.Request())
+				h.ServeHTTP(ctx.ResponseWriter(), withParams(ctx))
 			}
 		}
 
@@ -75,6 +82,37 @@ func FromStdWithNext(h func(w http.ResponseWriter, r *http.Request, next http.Ha
 			ctx.Next()
 		})
 
-		h(ctx.ResponseWriter(), ctx.Request(), next)
+		h(ctx.ResponseWriter(), withParams(ctx), next)
+	}
+}
+
+type paramsContextKey struct{}
+
+// withParams returns the Context's request with its path parameters stored in its context.
+func withParams(ctx context.Context) *http.Request {
+	r := ctx.Request()
+	if ctx.Params().Len() == 0 {
+		return r
 	}
+
+	params := make(map[string]string, ctx.Params().Len())
+	ctx.Params().Visit(func(key, value string) {
+		params[key] = value
+	})
+
+	return r.WithContext(stdContext.WithValue(r.Context(), paramsContextKey{}, params))
+}
+
+// Params returns the Iris route's path parameters of a request
+// served by a standard handler converted through `FromStd`.
+func Params(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(paramsContextKey{}).(map[string]string)
+	return params
+}
+
+// Param returns the value of an Iris route's path parameter of a request
+// served by a standard handler converted through `FromStd`.
+// It returns an empty string if the parameter does not exist.
+func Param(r *http.Request, key string) string {
+	return Params(r)[key]
 }
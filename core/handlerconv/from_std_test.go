@@ -68,3 +68,24 @@ func TestFromStdWithNext(t *testing.T) {
 	e.GET("/handlerwithnext").WithBasicAuth(basicauth, basicauth).
 		Expect().Status(iris.StatusOK).Body().Equal(passed)
 }
+
+func TestFromStdParams(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/legacy/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + " " + handlerconv.Param(r, "p")))
+	})
+
+	app := iris.New()
+	app.Any("/legacy/{p:path}", handlerconv.FromStd(mux))
+	app.Get("/users/{id:int}", handlerconv.FromStd(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		w.Write([]byte(handlerconv.Params(r)["id"]))
+		next(w, r)
+	}), func(ctx context.Context) {
+		ctx.WriteString(" " + handlerconv.Param(ctx.Request(), "id"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/legacy/users/list").Expect().Status(iris.StatusOK).Body().Equal("/legacy/users/list users/list")
+	e.POST("/legacy/a").Expect().Status(iris.StatusOK).Body().Equal("/legacy/a a")
+	e.GET("/users/42").Expect().Status(iris.StatusOK).Body().Equal("42 42")
+}
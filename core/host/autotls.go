@@ -0,0 +1,658 @@
+package host
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/crypto/ocsp"
+)
+
+// DNSProvider is the interface which should be implemented
+// by DNS services in order to solve the ACME "dns-01" challenges.
+// The "dns-01" challenge is the only one which can issue wildcard certificates (e.g. "*.iris-go.com").
+type DNSProvider interface {
+	// Present should create a TXT record of the "fqdn" (e.g. "_acme-challenge.iris-go.com.")
+	// with the given "value".
+	Present(ctx context.Context, domain, fqdn, value string) error
+	// CleanUp should remove the TXT record created by `Present`.
+	CleanUp(ctx context.Context, domain, fqdn, value string) error
+}
+
+// AutoTLSConfig contains the optional settings of the `ListenAndServeAutoTLS`.
+// Use the `WithAutoTLSConfig` host Configurator to set it.
+type AutoTLSConfig struct {
+	// Cache is the certificates and account keys storage.
+	// Set it to a shared storage (e.g. `SQLCache` or the autotlscache `Redis`)
+	// when the application runs on multiple instances.
+	//
+	// Defaults to a directory cache of the "cacheDir" argument.
+	Cache autocert.Cache
+	// DNSProvider, if not nil, enables the "dns-01" challenge
+	// through this provider instead of the "http-01" and "tls-alpn-01" ones.
+	// Required for wildcard domains.
+	DNSProvider DNSProvider
+	// DirectoryURL is the ACME directory endpoint.
+	//
+	// Defaults to the Let's Encrypt production directory.
+	DirectoryURL string
+	// RenewBefore specifies how early certificates should be renewed before they expire.
+	//
+	// Defaults to 30 days.
+	RenewBefore time.Duration
+	// OCSPStapling staples the certificates' OCSP responses to the TLS handshakes.
+	//
+	// Defaults to false.
+	OCSPStapling bool
+	// OnRenew, if not nil, is called when a certificate for the "domain"
+	// was issued or renewed and stored in the Cache.
+	OnRenew func(domain string)
+	// OnError, if not nil, is called when a certificate could not be
+	// issued, renewed or stapled.
+	//
+	// Defaults to a warning on the `Supervisor.Logger`, excluding the handshake errors.
+	OnError func(domain string, err error)
+}
+
+// WithAutoTLSConfig returns a host Configurator which
+// sets the optional settings of the `ListenAndServeAutoTLS`.
+//
+// Usage:
+//  app.Run(iris.AutoTLS(":443", "iris-go.com *.iris-go.com", "mail@example.com",
+//    host.WithAutoTLSConfig(host.AutoTLSConfig{DNSProvider: provider, OCSPStapling: true})))
+func WithAutoTLSConfig(cfg AutoTLSConfig) Configurator {
+	return func(su *Supervisor) {
+		su.autoTLSConfig = &cfg
+	}
+}
+
+func (cfg *AutoTLSConfig) onError(domain string, err error) {
+	if cfg.OnError != nil && err != nil {
+		cfg.OnError(domain, err)
+	}
+}
+
+// renewCache notifies the `OnRenew` when a certificate is stored.
+type renewCache struct {
+	autocert.Cache
+	onRenew func(domain string)
+}
+
+func (c *renewCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := c.Cache.Put(ctx, key, data); err != nil {
+		return err
+	}
+
+	// certificates are stored by their domain name or "domain+rsa",
+	// anything else with a "+" is an account key or a challenge token.
+	domain := strings.TrimSuffix(key, "+rsa")
+	if !strings.Contains(domain, "+") {
+		c.onRenew(domain)
+	}
+
+	return nil
+}
+
+// SQLCache returns an `autocert.Cache` which stores the certificates and keys
+// to the "table" of a SQL database, so they can be shared between multiple instances.
+// The table should have an "id" varchar primary key and a "data" blob column.
+// The queries are using the "?" placeholders (e.g. MySQL and SQLite).
+func SQLCache(db *sql.DB, table string) autocert.Cache {
+	return &sqlCache{db: db, table: table}
+}
+
+type sqlCache struct {
+	db    *sql.DB
+	table string
+}
+
+func (c *sqlCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRowContext(ctx, "SELECT data FROM "+c.table+" WHERE id = ?", key).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, autocert.ErrCacheMiss
+	}
+
+	return data, err
+}
+
+func (c *sqlCache) Put(ctx context.Context, key string, data []byte) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM "+c.table+" WHERE id = ?", key); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "INSERT INTO "+c.table+" (id, data) VALUES (?, ?)", key, data); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (c *sqlCache) Delete(ctx context.Context, key string) error {
+	_, err := c.db.ExecContext(ctx, "DELETE FROM "+c.table+" WHERE id = ?", key)
+	return err
+}
+
+// dnsManager issues and renews a single certificate for all the domains
+// through the ACME "dns-01" challenge.
+type dnsManager struct {
+	config  *AutoTLSConfig
+	domains []string
+	email   string
+	cache   autocert.Cache
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	// selfSigned reports whether the cert is the temporary, self-signed, one
+	// which is served until the real one is obtained.
+	selfSigned bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (m *dnsManager) cacheKey() string {
+	return "dns01:" + strings.Join(m.domains, ",")
+}
+
+func (m *dnsManager) renewBefore() time.Duration {
+	if m.config.RenewBefore > 0 {
+		return m.config.RenewBefore
+	}
+
+	return 30 * 24 * time.Hour
+}
+
+// start loads the cached certificate, or a self-signed one if not cached,
+// and starts the renewal loop which obtains the real one in the background,
+// so a slow DNS provider does not delay the server's start.
+func (m *dnsManager) start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if data, err := m.cache.Get(ctx, m.cacheKey()); err == nil {
+		if cert, err := decodeCertificate(data); err == nil {
+			m.setCertificate(cert)
+		}
+	}
+
+	if m.cert == nil {
+		cert, err := selfSignedCertificate(m.domains)
+		if err != nil {
+			return err
+		}
+
+		m.mu.Lock()
+		m.cert, m.selfSigned = cert, true
+		m.mu.Unlock()
+	}
+
+	m.stop = make(chan struct{})
+	go m.renewLoop()
+	return nil
+}
+
+// close stops the renewal loop.
+func (m *dnsManager) close() {
+	m.stopOnce.Do(func() {
+		if m.stop != nil {
+			close(m.stop)
+		}
+	})
+}
+
+func (m *dnsManager) shouldRenew() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.cert == nil || m.selfSigned || time.Now().Add(m.renewBefore()).After(m.cert.Leaf.NotAfter)
+}
+
+func (m *dnsManager) renewLoop() {
+	for {
+		if m.shouldRenew() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			err := m.obtain(ctx)
+			cancel()
+			if err != nil {
+				m.config.onError(m.domains[0], err)
+
+				// retry sooner while the self-signed certificate is served.
+				retry := time.Hour
+				m.mu.RLock()
+				if m.selfSigned {
+					retry = time.Minute
+				}
+				m.mu.RUnlock()
+
+				if !m.sleep(retry) {
+					return
+				}
+				continue
+			}
+		}
+
+		m.mu.RLock()
+		next := time.Until(m.cert.Leaf.NotAfter.Add(-m.renewBefore()))
+		m.mu.RUnlock()
+		if next < time.Minute {
+			next = time.Minute
+		}
+
+		if !m.sleep(next) {
+			return
+		}
+	}
+}
+
+// sleep waits for "d", it reports false if the manager was closed meanwhile.
+func (m *dnsManager) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-m.stop:
+		return false
+	}
+}
+
+func (m *dnsManager) setCertificate(cert *tls.Certificate) {
+	m.mu.Lock()
+	m.cert, m.selfSigned = cert, false
+	m.mu.Unlock()
+}
+
+func (m *dnsManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	cert := m.cert
+	m.mu.RUnlock()
+
+	if cert == nil {
+		return nil, errors.New("autotls: certificate is not ready")
+	}
+
+	return cert, nil
+}
+
+// selfSignedCertificate returns a temporary certificate of the "domains".
+func selfSignedCertificate(domains []string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(7 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func (m *dnsManager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	const keyName = "acme_account+key"
+
+	if data, err := m.cache.Get(ctx, keyName); err == nil {
+		if block, _ := pem.Decode(data); block != nil {
+			return x509.ParseECPrivateKey(block.Bytes)
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
+	return key, m.cache.Put(ctx, keyName, data)
+}
+
+func (m *dnsManager) obtain(ctx context.Context) error {
+	accountKey, err := m.accountKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := &acme.Client{Key: accountKey, DirectoryURL: m.config.DirectoryURL}
+
+	account := &acme.Account{}
+	if m.email != "" {
+		account.Contact = []string{"mailto:" + m.email}
+	}
+	if _, err = client.Register(ctx, account, autocert.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		return err
+	}
+
+	ids := make([]acme.AuthzID, 0, len(m.domains))
+	for _, domain := range m.domains {
+		ids = append(ids, acme.AuthzID{Type: "dns", Value: domain})
+	}
+
+	order, err := client.AuthorizeOrder(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err = m.authorize(ctx, client, authzURL); err != nil {
+			return err
+		}
+	}
+
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return err
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: m.domains}, certKey)
+	if err != nil {
+		return err
+	}
+
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return err
+	}
+
+	data, err := encodeCertificate(certKey, der)
+	if err != nil {
+		return err
+	}
+
+	cert, err := decodeCertificate(data)
+	if err != nil {
+		return err
+	}
+
+	m.setCertificate(cert)
+	if err = m.cache.Put(ctx, m.cacheKey(), data); err != nil {
+		return err
+	}
+
+	if m.config.OnRenew != nil {
+		for _, domain := range m.domains {
+			m.config.OnRenew(domain)
+		}
+	}
+
+	return nil
+}
+
+func (m *dnsManager) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+
+	if chal == nil {
+		return fmt.Errorf("autotls: no dns-01 challenge for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	domain := strings.TrimPrefix(authz.Identifier.Value, "*.")
+	fqdn := "_acme-challenge." + domain + "."
+
+	if err = m.config.DNSProvider.Present(ctx, domain, fqdn, value); err != nil {
+		return err
+	}
+	defer m.config.DNSProvider.CleanUp(ctx, domain, fqdn, value)
+
+	if _, err = client.Accept(ctx, chal); err != nil {
+		return err
+	}
+
+	_, err = client.WaitAuthorization(ctx, authz.URI)
+	return err
+}
+
+func encodeCertificate(key *ecdsa.PrivateKey, der [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}); err != nil {
+		return nil, err
+	}
+
+	for _, c := range der {
+		if err = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c}); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeCertificate(data []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// ocspRetryInterval is the time to wait before a failed OCSP fetch is retried.
+const ocspRetryInterval = 10 * time.Minute
+
+// ocspStapler wraps a GetCertificate function
+// and staples the OCSP response of the certificates.
+// The responses are fetched in the background, the handshakes are served
+// with the cached response, if it's still valid, or without a staple.
+type ocspStapler struct {
+	getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	onError        func(domain string, err error)
+	// fetch defaults to fetchOCSP.
+	fetch func(leaf *x509.Certificate, issuerDER []byte) ([]byte, time.Time, error)
+
+	mu      sync.Mutex
+	entries map[string]*ocspEntry // by leaf's serial number.
+}
+
+type ocspEntry struct {
+	staple     []byte
+	nextUpdate time.Time
+	// refreshAt is the time to fetch a new response,
+	// halfway to the nextUpdate or after the ocspRetryInterval on failures.
+	refreshAt  time.Time
+	refreshing bool
+}
+
+func (s *ocspStapler) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := s.getCertificate(hello)
+	if err != nil || len(cert.Certificate) < 2 {
+		return cert, err
+	}
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return cert, nil
+		}
+	}
+
+	key := leaf.SerialNumber.String()
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = new(ocspEntry)
+		s.entries[key] = entry
+	}
+
+	if !entry.refreshing && !now.Before(entry.refreshAt) {
+		entry.refreshing = true
+		go s.refresh(entry, hello.ServerName, leaf, cert.Certificate[1])
+	}
+
+	var staple []byte
+	if now.Before(entry.nextUpdate) {
+		staple = entry.staple
+	}
+	s.mu.Unlock()
+
+	if staple == nil {
+		return cert, nil
+	}
+
+	stapled := *cert
+	stapled.OCSPStaple = staple
+	return &stapled, nil
+}
+
+func (s *ocspStapler) refresh(entry *ocspEntry, domain string, leaf *x509.Certificate, issuerDER []byte) {
+	fetch := s.fetch
+	if fetch == nil {
+		fetch = fetchOCSP
+	}
+
+	staple, nextUpdate, err := fetch(leaf, issuerDER)
+	now := time.Now()
+
+	s.mu.Lock()
+	entry.refreshing = false
+	if err != nil {
+		// the previous response, if any, is served until its next update.
+		entry.refreshAt = now.Add(ocspRetryInterval)
+	} else {
+		entry.staple = staple
+		entry.nextUpdate = nextUpdate
+		entry.refreshAt = now.Add(nextUpdate.Sub(now) / 2)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.onError(domain, err)
+	}
+}
+
+func fetchOCSP(leaf *x509.Certificate, issuerDER []byte) ([]byte, time.Time, error) {
+	issuer, err := x509.ParseCertificate(issuerDER)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	res, raw, err := queryOCSP(leaf, issuer)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if res.Status != ocsp.Good {
+		return nil, time.Time{}, fmt.Errorf("ocsp: certificate status is not good: %d", res.Status)
+	}
+
+	nextUpdate := res.NextUpdate
+	if nextUpdate.IsZero() {
+		nextUpdate = time.Now().Add(time.Hour)
+	}
+
+	return raw, nextUpdate, nil
+}
+
+// queryOCSP sends an OCSP request for the "leaf" certificate
+// to its OCSP server and returns the parsed and the raw response.
+func queryOCSP(leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil, errors.New("ocsp: certificate has no OCSP server")
+	}
+
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := ocsp.ParseResponse(raw, issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, raw, nil
+}
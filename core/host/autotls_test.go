@@ -0,0 +1,211 @@
+// white-box testing
+
+package host
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestAutoTLSRenewCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-autotls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var renewed []string
+	cache := &renewCache{Cache: autocert.DirCache(dir), onRenew: func(domain string) {
+		renewed = append(renewed, domain)
+	}}
+
+	ctx := context.Background()
+	for _, key := range []string{"acme_account+key", "iris-go.com", "token+http-01", "www.iris-go.com+rsa"} {
+		if err = cache.Put(ctx, key, []byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"iris-go.com", "www.iris-go.com"}
+	if len(renewed) != len(expected) || renewed[0] != expected[0] || renewed[1] != expected[1] {
+		t.Fatalf("expected renewed domains: %v but got: %v", expected, renewed)
+	}
+}
+
+func TestAutoTLSCertificateEncoding(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "iris-go.com"},
+		DNSNames:     []string{"iris-go.com", "*.iris-go.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := encodeCertificate(key, [][]byte{der})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := decodeCertificate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "*.iris-go.com", cert.Leaf.DNSNames[1]; expected != got {
+		t.Fatalf("expected DNS name: %s but got: %s", expected, got)
+	}
+
+	m := &dnsManager{config: &AutoTLSConfig{RenewBefore: 2 * time.Hour}}
+	m.setCertificate(cert)
+	if !m.shouldRenew() {
+		t.Fatalf("expected certificate to be renewed")
+	}
+
+	su := New(&http.Server{}).Configure(WithAutoTLSConfig(AutoTLSConfig{OCSPStapling: true}))
+	if su.autoTLSConfig == nil || !su.autoTLSConfig.OCSPStapling {
+		t.Fatalf("expected auto tls configuration to be set")
+	}
+}
+
+func TestAutoTLSDNSManagerStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-autotls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	errs := make(chan error, 1)
+	m := &dnsManager{
+		config: &AutoTLSConfig{
+			DirectoryURL: "http://127.0.0.1:1/directory", // unavailable.
+			OnError: func(domain string, err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			},
+		},
+		domains: []string{"iris-go.com", "*.iris-go.com"},
+		cache:   autocert.DirCache(dir),
+	}
+
+	// the server starts with a self-signed certificate,
+	// the real one is obtained in the background.
+	if err = m.start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.close()
+
+	cert, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "iris-go.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := "*.iris-go.com", cert.Leaf.DNSNames[1]; expected != got {
+		t.Fatalf("expected DNS name: %s but got: %s", expected, got)
+	}
+	if !m.shouldRenew() {
+		t.Fatalf("expected the self-signed certificate to be renewed")
+	}
+
+	select {
+	case <-errs:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected the certificate to be obtained in the background")
+	}
+}
+
+func TestAutoTLSOCSPStapler(t *testing.T) {
+	leaf := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	cert := &tls.Certificate{Certificate: [][]byte{{1}, {2}}, Leaf: leaf}
+
+	var (
+		fetches = make(chan struct{})
+		release = make(chan error)
+		errs    = make(chan error, 1)
+	)
+
+	s := &ocspStapler{
+		getCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return cert, nil },
+		onError:        func(domain string, err error) { errs <- err },
+		fetch: func(*x509.Certificate, []byte) ([]byte, time.Time, error) {
+			fetches <- struct{}{}
+			if err := <-release; err != nil {
+				return nil, time.Time{}, err
+			}
+			return []byte("staple"), time.Now().Add(time.Hour), nil
+		},
+		entries: make(map[string]*ocspEntry),
+	}
+
+	getStaple := func() string {
+		c, err := s.GetCertificate(&tls.ClientHelloInfo{ServerName: "iris-go.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(c.OCSPStaple)
+	}
+
+	// the handshakes are not blocked by the fetch.
+	if staple := getStaple(); staple != "" {
+		t.Fatalf("expected no staple but got: %q", staple)
+	}
+	<-fetches
+	if staple := getStaple(); staple != "" {
+		t.Fatalf("expected no staple but got: %q", staple)
+	}
+	release <- nil
+
+	deadline := time.Now().Add(5 * time.Second)
+	for getStaple() != "staple" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the staple to be served")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// failures are cached and the previous staple is served.
+	s.mu.Lock()
+	s.entries[leaf.SerialNumber.String()].refreshAt = time.Time{}
+	s.mu.Unlock()
+
+	getStaple()
+	<-fetches
+	release <- errors.New("ocsp: unavailable")
+	<-errs
+
+	for i := 0; i < 3; i++ {
+		if staple := getStaple(); staple != "staple" {
+			t.Fatalf("expected the previous staple but got: %q", staple)
+		}
+	}
+
+	select {
+	case <-fetches:
+		t.Fatalf("expected the failure to be cached")
+	default:
+	}
+}
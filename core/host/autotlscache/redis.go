@@ -0,0 +1,57 @@
+// Package autotlscache provides shared certificates storages for the `host.AutoTLSConfig.Cache`,
+// so the applications which run on multiple instances use the same certificates and account keys.
+package autotlscache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kataras/iris/v12/sessions/sessiondb/redis"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Redis returns an `autocert.Cache` which stores the certificates and the account keys
+// of the `iris.AutoTLS` runner to the "db" redis database.
+// The keys are prefixed with "autotls:".
+//
+// Usage:
+//  db := redis.New(redis.Config{...})
+//  app.Run(iris.AutoTLS(":443", "iris-go.com", "mail@example.com",
+//    host.WithAutoTLSConfig(host.AutoTLSConfig{Cache: autotlscache.Redis(db)})))
+func Redis(db *redis.Database) autocert.Cache {
+	return &redisCache{driver: db.Config().Driver}
+}
+
+type redisCache struct {
+	driver redis.Driver
+}
+
+const autoTLSKeyPrefix = "autotls:"
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := c.driver.Get(autoTLSKeyPrefix + key)
+	if err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return nil, autocert.ErrCacheMiss
+		}
+		return nil, err
+	}
+
+	switch data := v.(type) {
+	case []byte:
+		return data, nil
+	case string:
+		return []byte(data), nil
+	default:
+		return nil, autocert.ErrCacheMiss
+	}
+}
+
+func (c *redisCache) Put(ctx context.Context, key string, data []byte) error {
+	return c.driver.Set(autoTLSKeyPrefix+key, data, 0)
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.driver.Delete(autoTLSKeyPrefix + key)
+}
@@ -0,0 +1,252 @@
+package host
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ClientAuthConfig contains the client certificates verification settings (mutual TLS).
+// Use the `WithClientAuth` host Configurator to set it.
+type ClientAuthConfig struct {
+	// ClientCAs are the certificate authorities which are used to verify the client certificates.
+	// See `LoadCertPool` too.
+	ClientCAs *x509.CertPool
+	// Require, if true, rejects all the TLS handshakes without a valid client certificate.
+	// If false, the client certificates are verified only if they are given,
+	// so specific routes can demand them through the `middleware/mtls#Require`.
+	//
+	// Defaults to false.
+	Require bool
+	// CRLs is a list of certificate revocation lists to check the client certificates against.
+	// See `LoadCRL` too.
+	CRLs []*pkix.CertificateList
+	// OCSP, if true, checks the client certificates' revocation status
+	// through their OCSP servers. The responses are fetched in the background,
+	// once per certificate for all the concurrent handshakes, and they are cached until their next update.
+	//
+	// Defaults to false.
+	OCSP bool
+	// OCSPFailClosed, if true, rejects the client certificates whose OCSP status is unknown,
+	// i.e. their OCSP server is unavailable. The first handshake of a certificate
+	// waits for its OCSP response.
+	// If false, the handshakes do not wait for the OCSP servers
+	// and the certificates of an unknown status are accepted.
+	//
+	// Defaults to false.
+	OCSPFailClosed bool
+
+	ocspCache *ocspCache
+}
+
+var (
+	// ErrCertificateRevoked is returned on TLS handshakes when the client certificate is revoked.
+	ErrCertificateRevoked = errors.New("client certificate is revoked")
+	// ErrCertificateStatusUnknown is returned on TLS handshakes when the revocation status
+	// of the client certificate is unknown and the `ClientAuthConfig.OCSPFailClosed` is true.
+	ErrCertificateStatusUnknown = errors.New("client certificate revocation status is unknown")
+)
+
+// WithClientAuth returns a host Configurator which enables the verification
+// of client certificates (mutual TLS) on the TLS servers of the host.
+//
+// Usage:
+//  pool, _ := host.LoadCertPool("ca.crt")
+//  app.Run(iris.TLS(":443", "server.crt", "server.key", host.WithClientAuth(host.ClientAuthConfig{ClientCAs: pool})))
+func WithClientAuth(cfg ClientAuthConfig) Configurator {
+	// shared between the hosts of the configurator.
+	cfg.ocspCache = &ocspCache{entries: make(map[string]*ocspCacheEntry)}
+
+	return func(su *Supervisor) {
+		su.clientAuthConfig = &cfg
+	}
+}
+
+// LoadCertPool returns a certificates pool of the PEM encoded "files".
+func LoadCertPool(files ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in: %s", file)
+		}
+	}
+
+	return pool, nil
+}
+
+// LoadCRL returns the PEM or DER encoded certificate revocation list of the "file".
+func LoadCRL(file string) (*pkix.CertificateList, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(b); block != nil {
+		b = block.Bytes
+	}
+
+	return x509.ParseDERCRL(b)
+}
+
+func (cfg *ClientAuthConfig) apply(tlsConfig *tls.Config) {
+	tlsConfig.ClientCAs = cfg.ClientCAs
+	if cfg.Require {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	if len(cfg.CRLs) > 0 || cfg.OCSP {
+		tlsConfig.VerifyPeerCertificate = cfg.verifyPeerCertificate
+	}
+}
+
+func (cfg *ClientAuthConfig) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if len(chain) < 2 {
+			continue
+		}
+
+		leaf, issuer := chain[0], chain[1]
+		if err := cfg.checkRevocation(leaf, issuer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRevocation returns the ErrCertificateRevoked if the "leaf" is revoked by a CRL
+// or by its OCSP server and the ErrCertificateStatusUnknown if its OCSP status is unknown
+// and the OCSPFailClosed is true.
+func (cfg *ClientAuthConfig) checkRevocation(leaf, issuer *x509.Certificate) error {
+	for _, crl := range cfg.CRLs {
+		if issuer.CheckCRLSignature(crl) != nil {
+			continue // not issued by this certificate authority.
+		}
+
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				return ErrCertificateRevoked
+			}
+		}
+	}
+
+	if cfg.OCSP {
+		return cfg.ocspCache.check(leaf, issuer, cfg.OCSPFailClosed)
+	}
+
+	return nil
+}
+
+// ocspFailureTTL is the time to wait before an unavailable OCSP server is queried again.
+const ocspFailureTTL = time.Minute
+
+// ocspCache caches the revocation status of the client certificates until their next update.
+// The statuses are fetched in the background, one fetch per certificate at a time.
+type ocspCache struct {
+	// query defaults to queryOCSP.
+	query func(leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error)
+
+	mu      sync.Mutex
+	entries map[string]*ocspCacheEntry // by issuer's subject and leaf's serial number.
+}
+
+type ocspCacheEntry struct {
+	revoked bool
+	// expires is the next update of the status, it's unknown after that.
+	expires time.Time
+	// refreshAt is the time to fetch a new status,
+	// halfway to the expiration or after the ocspFailureTTL on failures.
+	refreshAt  time.Time
+	refreshing bool
+	// fetched is closed when the first fetch is completed, successfully or not.
+	fetched chan struct{}
+}
+
+func (c *ocspCache) check(leaf, issuer *x509.Certificate, failClosed bool) error {
+	key := string(issuer.RawSubject) + leaf.SerialNumber.String()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &ocspCacheEntry{fetched: make(chan struct{})}
+		c.entries[key] = entry
+	}
+
+	if !entry.refreshing && !time.Now().Before(entry.refreshAt) {
+		entry.refreshing = true
+		go c.refresh(entry, leaf, issuer)
+	}
+	c.mu.Unlock()
+
+	if failClosed {
+		<-entry.fetched
+	}
+
+	c.mu.Lock()
+	revoked, known := entry.revoked, time.Now().Before(entry.expires)
+	c.mu.Unlock()
+
+	switch {
+	case known && revoked:
+		return ErrCertificateRevoked
+	case !known && failClosed:
+		return ErrCertificateStatusUnknown
+	default:
+		return nil
+	}
+}
+
+func (c *ocspCache) refresh(entry *ocspCacheEntry, leaf, issuer *x509.Certificate) {
+	query := c.query
+	if query == nil {
+		query = queryOCSP
+	}
+
+	res, _, err := query(leaf, issuer)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refreshing = false
+	if err != nil || res.Status == ocsp.Unknown {
+		// the previous status, if any, is used until it expires.
+		entry.refreshAt = now.Add(ocspFailureTTL)
+	} else {
+		expires := res.NextUpdate
+		if expires.IsZero() {
+			expires = now.Add(time.Hour)
+		}
+
+		entry.revoked = res.Status == ocsp.Revoked
+		entry.expires = expires
+		entry.refreshAt = now.Add(expires.Sub(now) / 2)
+	}
+
+	select {
+	case <-entry.fetched:
+	default:
+		close(entry.fetched)
+	}
+
+	for k, e := range c.entries {
+		if !e.refreshing && !now.Before(e.expires) && !now.Before(e.refreshAt) {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// white-box testing
+
+package host
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestClientAuthRevocation(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crlDER, err := ca.CreateCRL(rand.Reader, key,
+		[]pkix.RevokedCertificate{{SerialNumber: big.NewInt(2), RevocationTime: time.Now()}},
+		time.Now(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "iris-crl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	pem.Encode(f, &pem.Block{Type: "X509 CRL", Bytes: crlDER})
+	f.Close()
+
+	crl, err := LoadCRL(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ClientAuthConfig{CRLs: []*pkix.CertificateList{crl}}
+	if err = cfg.checkRevocation(&x509.Certificate{SerialNumber: big.NewInt(2)}, ca); err != ErrCertificateRevoked {
+		t.Fatalf("expected the certificate to be revoked but got: %v", err)
+	}
+
+	if err = cfg.checkRevocation(&x509.Certificate{SerialNumber: big.NewInt(3)}, ca); err != nil {
+		t.Fatalf("expected the certificate to not be revoked but got: %v", err)
+	}
+}
+
+func TestClientAuthOCSP(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		queries int
+		status  = ocsp.Revoked
+		err     error
+	)
+	release := make(chan struct{})
+
+	cache := &ocspCache{
+		entries: make(map[string]*ocspCacheEntry),
+		query: func(leaf, issuer *x509.Certificate) (*ocsp.Response, []byte, error) {
+			<-release
+
+			mu.Lock()
+			defer mu.Unlock()
+			queries++
+			return &ocsp.Response{Status: status, NextUpdate: time.Now().Add(time.Hour)}, nil, err
+		},
+	}
+
+	issuer := &x509.Certificate{RawSubject: []byte("ca")}
+	leaf := &x509.Certificate{SerialNumber: big.NewInt(4)}
+
+	// fail open: the handshake does not wait for the OCSP server.
+	if err := cache.check(leaf, issuer, false); err != nil {
+		t.Fatalf("expected the certificate of an unknown status to be accepted but got: %v", err)
+	}
+
+	// fail closed: the concurrent handshakes wait for the same response.
+	errs := make(chan error, 3)
+	for i := 0; i < cap(errs); i++ {
+		go func() { errs <- cache.check(leaf, issuer, true) }()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != ErrCertificateRevoked {
+			t.Fatalf("expected the certificate to be revoked but got: %v", err)
+		}
+	}
+
+	// cached until its next update.
+	if err := cache.check(leaf, issuer, false); err != ErrCertificateRevoked {
+		t.Fatalf("expected the cached revocation status but got: %v", err)
+	}
+
+	mu.Lock()
+	if queries != 1 {
+		t.Fatalf("expected a single OCSP query but got: %d", queries)
+	}
+	mu.Unlock()
+
+	// unavailable OCSP servers are rejected only when fail closed.
+	mu.Lock()
+	err = errors.New("unavailable")
+	mu.Unlock()
+
+	other := &x509.Certificate{SerialNumber: big.NewInt(5)}
+	if err := cache.check(other, issuer, true); err != ErrCertificateStatusUnknown {
+		t.Fatalf("expected an unknown status but got: %v", err)
+	}
+	if err := cache.check(other, issuer, false); err != nil {
+		t.Fatalf("expected the certificate of an unknown status to be accepted but got: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package host
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Rule is a host redirection rule, see `RedirectRules`.
+// It receives the request and its absolute URL, which it may modify,
+// and it reports whether it changed the URL, so a redirect is required.
+type Rule func(r *http.Request, u *url.URL) bool
+
+// RedirectRules returns a router wrapper which evaluates the "rules", in order,
+// against the request's absolute URL and redirects the client, with a single redirect,
+// to the resulted URL if at least one rule changed it. Otherwise the request is served as it's.
+//
+// The redirects are permanent: 301 for GET and HEAD requests, 308 for the rest
+// so the method and the body are kept.
+//
+// Usage:
+//  app.WrapRouter(host.RedirectRules(host.ForwardedProto(), host.RedirectHTTPS(), host.NonWWW()))
+// See the `Application.UseHostRules` too.
+func RedirectRules(rules ...Rule) func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		u := &url.URL{
+			Scheme:   "http",
+			Host:     r.Host,
+			Path:     r.URL.Path,
+			RawPath:  r.URL.RawPath,
+			RawQuery: r.URL.RawQuery,
+		}
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+
+		redirect := false
+		for _, rule := range rules {
+			if rule(r, u) {
+				redirect = true
+			}
+		}
+
+		if !redirect {
+			next(w, r)
+			return
+		}
+
+		status := http.StatusMovedPermanently
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			status = http.StatusPermanentRedirect
+		}
+
+		http.Redirect(w, r, u.String(), status)
+	}
+}
+
+// ForwardedProto is a rule which sets the URL's scheme to the "X-Forwarded-Proto" request header,
+// if it's "http" or "https". Use it, first, when the server is behind a proxy which terminates the TLS,
+// so the `RedirectHTTPS` does not redirect in a loop.
+// Do not use it when the server is accessible without that proxy, the header can be set by the client.
+func ForwardedProto() Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		if proto := strings.ToLower(r.Header.Get("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			u.Scheme = proto
+		}
+
+		return false
+	}
+}
+
+// RedirectHTTPS is a rule which redirects the "http" requests to "https",
+// the port, if any, is removed.
+func RedirectHTTPS() Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		if u.Scheme == "https" {
+			return false
+		}
+
+		u.Scheme = "https"
+		removePort(u)
+		return true
+	}
+}
+
+// CanonicalHost is a rule which redirects the requests of any other host name to the "hostname",
+// e.g. "example.com", the port, if any, is kept.
+func CanonicalHost(hostname string) Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		if strings.EqualFold(u.Hostname(), hostname) {
+			return false
+		}
+
+		setHostname(u, hostname)
+		return true
+	}
+}
+
+// NonWWW is a rule which redirects the "www." host names to their apex domain,
+// e.g. www.example.com to example.com.
+func NonWWW() Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		hostname := u.Hostname()
+		if !strings.HasPrefix(strings.ToLower(hostname), "www.") {
+			return false
+		}
+
+		setHostname(u, hostname[4:])
+		return true
+	}
+}
+
+// WWW is a rule which redirects the apex domains to their "www." host name,
+// e.g. example.com to www.example.com. The IPs, "localhost" and the subdomains are not redirected.
+func WWW() Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		hostname := u.Hostname()
+		if strings.Count(hostname, ".") != 1 || net.ParseIP(hostname) != nil {
+			return false
+		}
+
+		setHostname(u, "www."+hostname)
+		return true
+	}
+}
+
+// CanonicalPort is a rule which removes the default port of the URL's scheme,
+// i.e. ":80" of "http" and ":443" of "https".
+func CanonicalPort() Rule {
+	return func(r *http.Request, u *url.URL) bool {
+		port := u.Port()
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			removePort(u)
+			return true
+		}
+
+		return false
+	}
+}
+
+func setHostname(u *url.URL, hostname string) {
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(hostname, port)
+		return
+	}
+
+	u.Host = hostname
+}
+
+func removePort(u *url.URL) {
+	hostname := u.Hostname()
+	if strings.Contains(hostname, ":") { // IPv6.
+		hostname = "[" + hostname + "]"
+	}
+
+	u.Host = hostname
+}
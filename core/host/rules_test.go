@@ -0,0 +1,62 @@
+package host_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kataras/iris/v12/core/host"
+)
+
+func TestRedirectRules(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	tests := []struct {
+		rules            []host.Rule
+		method, url      string
+		tls              bool
+		forwardedProto   string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{[]host.Rule{host.RedirectHTTPS()}, http.MethodGet, "http://example.com:80/path?q=1", false, "", http.StatusMovedPermanently, "https://example.com/path?q=1"},
+		{[]host.Rule{host.RedirectHTTPS()}, http.MethodGet, "https://example.com/path", true, "", http.StatusOK, ""},
+		{[]host.Rule{host.RedirectHTTPS()}, http.MethodPost, "http://example.com/path", false, "", http.StatusPermanentRedirect, "https://example.com/path"},
+		{[]host.Rule{host.ForwardedProto(), host.RedirectHTTPS()}, http.MethodGet, "http://example.com/", false, "https", http.StatusOK, ""},
+		// a single redirect for all rules.
+		{[]host.Rule{host.RedirectHTTPS(), host.NonWWW()}, http.MethodGet, "http://www.example.com/", false, "", http.StatusMovedPermanently, "https://example.com/"},
+		{[]host.Rule{host.CanonicalHost("example.com")}, http.MethodGet, "http://example.org:8080/", false, "", http.StatusMovedPermanently, "http://example.com:8080/"},
+		{[]host.Rule{host.CanonicalHost("example.com")}, http.MethodGet, "http://EXAMPLE.com/", false, "", http.StatusOK, ""},
+		{[]host.Rule{host.WWW()}, http.MethodGet, "http://example.com/", false, "", http.StatusMovedPermanently, "http://www.example.com/"},
+		{[]host.Rule{host.WWW()}, http.MethodGet, "http://api.example.com/", false, "", http.StatusOK, ""},
+		{[]host.Rule{host.WWW()}, http.MethodGet, "http://127.0.0.1/", false, "", http.StatusOK, ""},
+		{[]host.Rule{host.CanonicalPort()}, http.MethodGet, "https://example.com:443/", true, "", http.StatusMovedPermanently, "https://example.com/"},
+		{[]host.Rule{host.CanonicalPort()}, http.MethodGet, "http://example.com:8080/", false, "", http.StatusOK, ""},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.url, nil)
+		if !tt.tls {
+			req.TLS = nil
+		} else if req.TLS == nil {
+			req.TLS = new(tls.ConnectionState)
+		}
+		if tt.forwardedProto != "" {
+			req.Header.Set("X-Forwarded-Proto", tt.forwardedProto)
+		}
+
+		rec := httptest.NewRecorder()
+		host.RedirectRules(tt.rules...)(rec, req, next)
+
+		if rec.Code != tt.expectedStatus {
+			t.Fatalf("[%d] expected status: %d but got: %d", i, tt.expectedStatus, rec.Code)
+		}
+
+		if got := rec.Header().Get("Location"); got != tt.expectedLocation {
+			t.Fatalf("[%d] expected location: %q but got: %q", i, tt.expectedLocation, got)
+		}
+	}
+}
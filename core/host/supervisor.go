@@ -11,8 +11,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/netutil"
 )
 
@@ -45,6 +47,20 @@ type Supervisor struct {
 	// Defaults to empty.
 	IgnoredErrors []string
 	onErr         []func(error)
+
+	// Logger is the structured logger of the supervisor's background tasks,
+	// i.e the certificate renewals and the upgrades.
+	// The Application sets it to its `Log()`.
+	//
+	// Defaults to the `logging.Default()`.
+	Logger logging.Logger
+
+	// autoTLSConfig is the optional settings of the `ListenAndServeAutoTLS`,
+	// see `WithAutoTLSConfig`.
+	autoTLSConfig *AutoTLSConfig
+	// clientAuthConfig is the optional client certificates verification settings,
+	// see `WithClientAuth`.
+	clientAuthConfig *ClientAuthConfig
 }
 
 // New returns a new host supervisor
@@ -61,6 +77,14 @@ func New(srv *http.Server) *Supervisor {
 	}
 }
 
+func (su *Supervisor) logger() logging.Logger {
+	if su.Logger != nil {
+		return su.Logger
+	}
+
+	return logging.Default()
+}
+
 // Configure accepts one or more `Configurator`.
 // With this function you can use simple functions
 // that are spread across your app to modify
@@ -122,6 +146,7 @@ func (su *Supervisor) newListener() (net.Listener, error) {
 	// here we can check for sure, without the need of the supervisor's `manuallyTLS` field.
 	if netutil.IsTLS(su.Server) {
 		// means tls
+		su.configureClientAuth()
 		tlsl := tls.NewListener(l, su.Server.TLSConfig)
 		return tlsl, nil
 	}
@@ -129,6 +154,12 @@ func (su *Supervisor) newListener() (net.Listener, error) {
 	return l, nil
 }
 
+func (su *Supervisor) configureClientAuth() {
+	if su.clientAuthConfig != nil && su.Server.TLSConfig != nil {
+		su.clientAuthConfig.apply(su.Server.TLSConfig)
+	}
+}
+
 // RegisterOnError registers a function to call when errors occurred by the underline http server.
 func (su *Supervisor) RegisterOnError(cb func(error)) {
 	su.mu.Lock()
@@ -265,6 +296,8 @@ func (su *Supervisor) ListenAndServeTLS(certFile string, keyFile string) error {
 		return errors.New("empty certFile or keyFile and Server.TLSConfig")
 	}
 
+	su.configureClientAuth()
+
 	return su.supervise(func() error { return su.Server.ListenAndServeTLS("", "") })
 }
 
@@ -294,30 +327,112 @@ func (su *Supervisor) ListenAndServeAutoTLS(domain string, email string, cacheDi
 	var (
 		cache      autocert.Cache
 		hostPolicy autocert.HostPolicy
+		domains    []string
 	)
 
-	if cacheDir != "" {
+	cfg := new(AutoTLSConfig)
+	if su.autoTLSConfig != nil {
+		*cfg = *su.autoTLSConfig
+	}
+
+	// the handshake errors are reported to the OnError of the caller only, i.e. on unknown server names.
+	onHandshakeError := cfg.OnError
+	if cfg.OnError == nil {
+		logger := su.logger()
+		cfg.OnError = func(domain string, err error) {
+			logger.Warn("host: autotls", "domain", domain, "error", err)
+		}
+	}
+
+	if cfg.Cache != nil {
+		cache = cfg.Cache
+	} else if cacheDir != "" {
 		cache = autocert.DirCache(cacheDir)
 	}
 
 	if domain != "" {
-		domains := strings.Split(domain, " ")
+		domains = strings.Split(domain, " ")
 		hostPolicy = autocert.HostWhitelist(domains...)
 	}
 
-	autoTLSManager := &autocert.Manager{
-		Prompt:     autocert.AcceptTOS,
-		HostPolicy: hostPolicy,
-		Email:      email,
-		Cache:      cache,
-		ForceRSA:   true,
+	var (
+		getCertificate  func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+		redirectHandler http.Handler
+	)
+
+	if cfg.DNSProvider != nil {
+		if len(domains) == 0 {
+			return errors.New("autotls: dns-01 challenge requires at least one domain")
+		}
+
+		if cache == nil {
+			cache = autocert.DirCache("letscache")
+		}
+
+		dnsManager := &dnsManager{
+			config:  cfg,
+			domains: domains,
+			email:   email,
+			cache:   cache,
+		}
+
+		if err := dnsManager.start(); err != nil {
+			cfg.onError(domain, err)
+			return err
+		}
+		su.RegisterOnShutdown(dnsManager.close)
+
+		getCertificate = dnsManager.GetCertificate
+		redirectHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "https://"+r.Host+r.URL.RequestURI(), http.StatusFound)
+		})
+	} else {
+		if cache != nil && cfg.OnRenew != nil {
+			cache = &renewCache{Cache: cache, onRenew: cfg.OnRenew}
+		}
+
+		autoTLSManager := &autocert.Manager{
+			Prompt:      autocert.AcceptTOS,
+			HostPolicy:  hostPolicy,
+			Email:       email,
+			Cache:       cache,
+			ForceRSA:    true,
+			RenewBefore: cfg.RenewBefore,
+		}
+
+		if cfg.DirectoryURL != "" {
+			autoTLSManager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+		}
+
+		getCertificate = autoTLSManager.GetCertificate
+		redirectHandler = autoTLSManager.HTTPHandler(nil) // nil for redirect.
+	}
+
+	if onHandshakeError != nil {
+		get := getCertificate
+		getCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, err := get(hello)
+			if err != nil {
+				onHandshakeError(hello.ServerName, err)
+			}
+			return cert, err
+		}
+	}
+
+	if cfg.OCSPStapling {
+		stapler := &ocspStapler{
+			getCertificate: getCertificate,
+			onError:        cfg.onError,
+			entries:        make(map[string]*ocspEntry),
+		}
+		getCertificate = stapler.GetCertificate
 	}
 
 	srv2 := &http.Server{
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		Addr:         ":http",
-		Handler:      autoTLSManager.HTTPHandler(nil), // nil for redirect.
+		Handler:      redirectHandler,
 	}
 
 	// register a shutdown callback to this
@@ -333,7 +448,7 @@ func (su *Supervisor) ListenAndServeAutoTLS(domain string, email string, cacheDi
 
 	su.Server.TLSConfig = &tls.Config{
 		MinVersion:               tls.VersionTLS10,
-		GetCertificate:           autoTLSManager.GetCertificate,
+		GetCertificate:           getCertificate,
 		PreferServerCipherSuites: true,
 		// Keep the defaults.
 		CurvePreferences: []tls.CurveID{
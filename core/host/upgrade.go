@@ -0,0 +1,377 @@
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/core/logging"
+)
+
+// Environment variables which are set by the `Upgrader` on the new process.
+const (
+	// UpgradeListenersEnv contains the addresses of the inherited listeners, separated by ";",
+	// their file descriptors start from 3, in the same order.
+	UpgradeListenersEnv = "IRIS_UPGRADE_LISTENERS"
+	// UpgradeReadyEnv contains the file descriptor which the new process
+	// writes to when it is ready to accept connections.
+	UpgradeReadyEnv = "IRIS_UPGRADE_READY_FD"
+)
+
+// ErrUpgradeInProgress is returned from `Upgrader.Upgrade` when another upgrade is running.
+var ErrUpgradeInProgress = errors.New("upgrade: already in progress")
+
+// UpgradeConfig contains the options for the `Upgrader`.
+type UpgradeConfig struct {
+	// ReusePort sets the SO_REUSEPORT socket option to the listeners
+	// created by the upgrader, so a completely new process (e.g. started by systemd)
+	// can bind the same address while the old one drains.
+	//
+	// Defaults to false.
+	ReusePort bool
+	// Signal is the signal which triggers an `Upgrade`.
+	//
+	// Defaults to SIGHUP on unix systems.
+	Signal os.Signal
+	// DisableSignal, if true, does not listen for the upgrade Signal,
+	// the upgrades are triggered only through manual `Upgrade` calls.
+	//
+	// Defaults to false.
+	DisableSignal bool
+	// ReadyTimeout is the maximum duration to wait for the new process to become ready.
+	//
+	// Defaults to 30 seconds.
+	ReadyTimeout time.Duration
+	// DrainTimeout is the maximum duration to wait for the old process' servers
+	// to finish their in-flight requests after the new process became ready.
+	//
+	// Defaults to 30 seconds.
+	DrainTimeout time.Duration
+}
+
+// Upgrader provides binary upgrades without dropping connections.
+// The listeners are created through its `Listen` method
+// and their file descriptors are passed to the new process on `Upgrade`.
+// The new process inherits them, notifies the old one when it is ready to serve
+// and, after that, the old process gracefully terminates its servers.
+//
+// Usage:
+//  u := host.Upgradable()
+//  app.Run(iris.Upgradable(u, ":8080"))
+//  // $ kill -HUP <pid>
+type Upgrader struct {
+	config UpgradeConfig
+
+	mu        sync.Mutex
+	inherited map[string]net.Listener
+	listeners []*upgradeListener
+	accepting int // the number of the listeners which are served.
+	hosts     []*Supervisor
+	onDrain   []func(ctx context.Context)
+	upgrading bool
+	once      sync.Once
+}
+
+// upgradeListener notifies its Upgrader on the first Accept,
+// the listener is served from that point.
+type upgradeListener struct {
+	addr string
+	net.Listener
+	u        *Upgrader
+	accepted uint32 // accessed atomically.
+}
+
+func (l *upgradeListener) Accept() (net.Conn, error) {
+	if atomic.CompareAndSwapUint32(&l.accepted, 0, 1) {
+		l.u.serving()
+	}
+
+	return l.Listener.Accept()
+}
+
+type filer interface {
+	File() (*os.File, error)
+}
+
+// Upgradable returns a new `Upgrader`.
+// Receives an optional configuration.
+//
+// Note that only one Upgrader should be used per process.
+func Upgradable(cfg ...UpgradeConfig) *Upgrader {
+	var c UpgradeConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.Signal == nil {
+		c.Signal = defaultUpgradeSignal
+	}
+	if c.ReadyTimeout <= 0 {
+		c.ReadyTimeout = 30 * time.Second
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = 30 * time.Second
+	}
+
+	u := &Upgrader{config: c, inherited: make(map[string]net.Listener)}
+	u.inherit()
+	return u
+}
+
+// IsUpgraded reports whether the current process was started by an `Upgrade`.
+func (u *Upgrader) IsUpgraded() bool {
+	return os.Getenv(UpgradeListenersEnv) != ""
+}
+
+func (u *Upgrader) inherit() {
+	addrs := os.Getenv(UpgradeListenersEnv)
+	if addrs == "" {
+		return
+	}
+
+	for i, addr := range strings.Split(addrs, ";") {
+		f := os.NewFile(uintptr(3+i), addr)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		u.inherited[addr] = l
+	}
+}
+
+// Listen returns the listener of the "addr" inherited from the parent process
+// or a new TCP listener if not inherited.
+// The parent process is notified when all of the returned listeners are served, see `Ready`.
+func (u *Upgrader) Listen(addr string) (net.Listener, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	l, ok := u.inherited[addr]
+	if ok {
+		delete(u.inherited, addr)
+	} else {
+		lc := net.ListenConfig{}
+		if u.config.ReusePort {
+			lc.Control = reusePortControl
+		}
+
+		var err error
+		l, err = lc.Listen(context.Background(), "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ul := &upgradeListener{addr: addr, Listener: l, u: u}
+	u.listeners = append(u.listeners, ul)
+	return ul, nil
+}
+
+// serving calls `Ready` when all of the listeners accept connections.
+func (u *Upgrader) serving() {
+	u.mu.Lock()
+	u.accepting++
+	ready := u.accepting == len(u.listeners)
+	u.mu.Unlock()
+
+	if ready {
+		if err := u.Ready(); err != nil {
+			u.logger().Error("host: upgrade: ready", "error", err)
+		}
+	}
+}
+
+// logger returns the logger of the first registered host.
+func (u *Upgrader) logger() logging.Logger {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if len(u.hosts) == 0 {
+		return logging.Default()
+	}
+	return u.hosts[0].logger()
+}
+
+// Configure is a host `Configurator` which should be passed to the
+// hosts that serve the upgrader's listeners.
+// It registers the host to be gracefully terminated after a successful `Upgrade`
+// and starts listening for the configured upgrade signal.
+func (u *Upgrader) Configure(su *Supervisor) {
+	u.mu.Lock()
+	u.hosts = append(u.hosts, su)
+	u.mu.Unlock()
+
+	u.once.Do(func() {
+		if u.config.DisableSignal || u.config.Signal == nil {
+			return
+		}
+
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, u.config.Signal)
+		go func() {
+			for range ch {
+				if err := u.Upgrade(); err != nil {
+					su.logger().Error("host: upgrade", "error", err)
+				}
+			}
+		}()
+	})
+}
+
+// Ready notifies the parent process that this process is ready to accept connections.
+// It is called automatically when all of the upgrader's listeners accept connections.
+func (u *Upgrader) Ready() error {
+	v := os.Getenv(UpgradeReadyEnv)
+	if v == "" {
+		return nil
+	}
+	os.Unsetenv(UpgradeReadyEnv)
+
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+
+	f := os.NewFile(uintptr(fd), "ready")
+	_, err = f.Write([]byte{1})
+	f.Close()
+	return err
+}
+
+// Upgrade starts a new process of the same executable and arguments
+// which inherits the listeners, waits for it to become ready
+// and gracefully terminates the current process' servers.
+// After a successful upgrade the current process can not be upgraded again.
+func (u *Upgrader) Upgrade() (err error) {
+	u.mu.Lock()
+	if u.upgrading {
+		u.mu.Unlock()
+		return ErrUpgradeInProgress
+	}
+	u.upgrading = true
+	listeners := u.listeners
+	hosts := u.hosts
+	onDrain := u.onDrain
+	u.mu.Unlock()
+
+	// keep it until the new process is ready, or killed,
+	// this process terminates after that.
+	defer func() {
+		if err != nil {
+			u.mu.Lock()
+			u.upgrading = false
+			u.mu.Unlock()
+		}
+	}()
+
+	var (
+		files []*os.File
+		addrs []string
+	)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, l := range listeners {
+		fl, ok := l.Listener.(filer)
+		if !ok {
+			return fmt.Errorf("listener of %s does not support file descriptors", l.addr)
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+		addrs = append(addrs, l.addr)
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyR.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		readyW.Close()
+		return err
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = append(files, readyW)
+	cmd.Env = append(os.Environ(),
+		UpgradeListenersEnv+"="+strings.Join(addrs, ";"),
+		UpgradeReadyEnv+"="+strconv.Itoa(3+len(files)),
+	)
+
+	err = cmd.Start()
+	readyW.Close()
+	if err != nil {
+		return err
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		b := make([]byte, 1)
+		_, err := readyR.Read(b)
+		ready <- err
+	}()
+
+	select {
+	case err = <-ready:
+		if err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("new process exited before ready: %w", err)
+		}
+	case <-time.After(u.config.ReadyTimeout):
+		cmd.Process.Kill()
+		cmd.Wait()
+		return errors.New("new process was not ready in time")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), u.config.DrainTimeout)
+	defer cancel()
+
+	if len(onDrain) > 0 {
+		for _, cb := range onDrain {
+			cb(ctx)
+		}
+		return nil
+	}
+
+	for _, su := range hosts {
+		su.shutdownOnInterrupt(ctx)
+		su.RestoreFlow()
+	}
+
+	return nil
+}
+
+// OnDrain registers one or more functions which terminate the current process' servers,
+// and anything else should be closed, after a successful `Upgrade`,
+// instead of the hosts registered through `Configure`.
+// The "ctx" expires after the DrainTimeout.
+//
+// The `iris.Upgradable` Runner registers the Application's graceful shutdown,
+// so its `OnShutdown` hooks, background workers and plugins are terminated too.
+func (u *Upgrader) OnDrain(cb ...func(ctx context.Context)) {
+	u.mu.Lock()
+	u.onDrain = append(u.onDrain, cb...)
+	u.mu.Unlock()
+}
@@ -0,0 +1,21 @@
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package host
+
+import (
+	"syscall"
+)
+
+var defaultUpgradeSignal = syscall.SIGHUP
+
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	var err error
+	if cerr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEPORT, 1)
+	}); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
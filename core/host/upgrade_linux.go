@@ -0,0 +1,21 @@
+package host
+
+import (
+	"syscall"
+)
+
+// SO_REUSEPORT is not part of the standard syscall package for linux.
+const soReusePort = 0xf
+
+var defaultUpgradeSignal = syscall.SIGHUP
+
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	var err error
+	if cerr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+	}); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
@@ -0,0 +1,63 @@
+// white-box testing
+
+package host
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestUpgraderReady(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// the descriptor is closed by Ready.
+	fd, err := syscall.Dup(int(w.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Unsetenv(UpgradeListenersEnv)
+	os.Setenv(UpgradeReadyEnv, strconv.Itoa(fd))
+	defer os.Unsetenv(UpgradeReadyEnv)
+
+	u := Upgradable()
+	l, err := u.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready := make(chan struct{})
+	go func() {
+		b := make([]byte, 1)
+		r.Read(b)
+		close(ready)
+	}()
+
+	su := New(&http.Server{Handler: http.NotFoundHandler()})
+	su.Configure(u.Configure)
+
+	select {
+	case <-ready:
+		t.Fatalf("expected the parent process to be notified after the listener is served")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	go su.Serve(l)
+	defer su.Shutdown(context.Background())
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the parent process to be notified when the listener is served")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package host
+
+import (
+	"os"
+	"syscall"
+)
+
+// no signal-triggered upgrades on this platform, the `Upgrader.Upgrade` can be called manually.
+var defaultUpgradeSignal os.Signal
+
+// SO_REUSEPORT is not supported on this platform.
+func reusePortControl(network, address string, c syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,65 @@
+// white-box testing
+
+package host
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestUpgraderListen(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT test runs on linux only")
+	}
+
+	os.Unsetenv(UpgradeListenersEnv)
+
+	u := Upgradable(UpgradeConfig{ReusePort: true})
+	if u.IsUpgraded() {
+		t.Fatalf("expected a non-upgraded process")
+	}
+
+	l, err := u.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// a second process (or upgrader) can bind the same address.
+	other := Upgradable(UpgradeConfig{ReusePort: true})
+	l2, err := other.Listen(l.Addr().String())
+	if err != nil {
+		t.Fatalf("expected to reuse the port but: %v", err)
+	}
+	l2.Close()
+
+	if err = u.Ready(); err != nil {
+		t.Fatalf("expected Ready to be a no-op on a non-upgraded process but: %v", err)
+	}
+
+	su := New(&http.Server{Handler: http.NotFoundHandler()})
+	su.Configure(u.Configure)
+
+	go su.Serve(l)
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err = http.Get("http://" + l.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+
+	su.Shutdown(context.Background())
+}
+
+func TestUpgradableDefaults(t *testing.T) {
+	u := Upgradable(UpgradeConfig{ReusePort: true})
+	if u.config.Signal != defaultUpgradeSignal {
+		t.Fatalf("expected the default upgrade signal but got: %v", u.config.Signal)
+	}
+	if u.config.ReadyTimeout != 30*time.Second || u.config.DrainTimeout != 30*time.Second {
+		t.Fatalf("expected the default timeouts but got: %s and %s", u.config.ReadyTimeout, u.config.DrainTimeout)
+	}
+}
@@ -0,0 +1,283 @@
+// Package logging provides a structured logger, with key/value fields and a runtime level,
+// which writes its records to pluggable sinks: golog (the default), JSON, text,
+// slog, zap and zerolog.
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// Level is the severity of a log record.
+type Level int32
+
+// The available log levels, a logger writes the records
+// with a level equal or greater than its level.
+const (
+	DebugLevel Level = iota - 1
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	// DisableLevel disables all records.
+	DisableLevel
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case DisableLevel:
+		return "disable"
+	default:
+		return fmt.Sprintf("level(%d)", int32(l))
+	}
+}
+
+// ParseLevel returns the Level of a level name, i.e "debug", "info", "warn", "error" or "disable".
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info", "":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "disable", "disabled", "off":
+		return DisableLevel, nil
+	default:
+		return InfoLevel, fmt.Errorf("logging: unknown level: %q", name)
+	}
+}
+
+// LevelVar is a Level which can be changed at runtime, safe for concurrent use.
+// Its zero value is the InfoLevel.
+type LevelVar struct {
+	level int32
+}
+
+// NewLevelVar returns a new LevelVar set to "level".
+func NewLevelVar(level Level) *LevelVar {
+	v := new(LevelVar)
+	v.Set(level)
+	return v
+}
+
+// Level returns the current level.
+func (v *LevelVar) Level() Level {
+	return Level(atomic.LoadInt32(&v.level))
+}
+
+// Set changes the current level.
+func (v *LevelVar) Set(level Level) {
+	atomic.StoreInt32(&v.level, int32(level))
+}
+
+// Field is a key/value pair of a log record.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Record is a log entry, it's passed to the sinks.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Sink writes the log records, i.e to a file or to a third-party logger.
+type Sink interface {
+	Write(r Record)
+}
+
+// SinkFunc is a function which implements the `Sink`.
+type SinkFunc func(r Record)
+
+// Write calls the "fn" function.
+func (fn SinkFunc) Write(r Record) {
+	fn(r)
+}
+
+// Logger is the structured logger of the framework.
+// The "keyvals" are alternating keys and values, i.e
+//  logger.Info("route registered", "method", "GET", "path", "/users")
+// A `Field` can be passed instead of a key/value pair too.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+	// With returns a Logger which includes the "keyvals" fields to all its records.
+	With(keyvals ...interface{}) Logger
+	// Enabled reports whether the records of this level are written.
+	Enabled(level Level) bool
+}
+
+// levelEnabler is implemented by sinks which filter the records on their own,
+// i.e the golog sink respects the golog logger's level.
+type levelEnabler interface {
+	Enabled(level Level) bool
+}
+
+type logger struct {
+	sink   Sink
+	level  *LevelVar
+	fields []Field
+}
+
+// New returns a new Logger which writes its records to the "sink".
+// The "level" can be changed at runtime, a nil level means that
+// all records are passed to the sink.
+func New(sink Sink, level *LevelVar) Logger {
+	if level == nil {
+		level = NewLevelVar(DebugLevel)
+	}
+
+	return &logger{sink: sink, level: level}
+}
+
+func (l *logger) Enabled(level Level) bool {
+	if level < l.level.Level() {
+		return false
+	}
+
+	if e, ok := l.sink.(levelEnabler); ok {
+		return e.Enabled(level)
+	}
+
+	return true
+}
+
+func (l *logger) log(level Level, msg string, keyvals []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	fields := make([]Field, 0, len(l.fields)+len(keyvals)/2)
+	fields = append(fields, l.fields...)
+	fields = appendFields(fields, keyvals)
+
+	l.sink.Write(Record{Time: time.Now(), Level: level, Message: msg, Fields: fields})
+}
+
+func (l *logger) Debug(msg string, keyvals ...interface{}) { l.log(DebugLevel, msg, keyvals) }
+func (l *logger) Info(msg string, keyvals ...interface{})  { l.log(InfoLevel, msg, keyvals) }
+func (l *logger) Warn(msg string, keyvals ...interface{})  { l.log(WarnLevel, msg, keyvals) }
+func (l *logger) Error(msg string, keyvals ...interface{}) { l.log(ErrorLevel, msg, keyvals) }
+
+func (l *logger) With(keyvals ...interface{}) Logger {
+	fields := make([]Field, 0, len(l.fields)+len(keyvals)/2)
+	fields = append(fields, l.fields...)
+	fields = appendFields(fields, keyvals)
+
+	return &logger{sink: l.sink, level: l.level, fields: fields}
+}
+
+// appendFields converts the alternating keys and values to fields,
+// a missing value is set to "!MISSING" and a non-string key is converted to string.
+func appendFields(fields []Field, keyvals []interface{}) []Field {
+	for i := 0; i < len(keyvals); i++ {
+		if f, ok := keyvals[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", keyvals[i])
+		}
+
+		var value interface{} = "!MISSING"
+		if i+1 < len(keyvals) {
+			i++
+			value = keyvals[i]
+		}
+
+		fields = append(fields, Field{Key: key, Value: value})
+	}
+
+	return fields
+}
+
+type nop struct{}
+
+func (nop) Debug(string, ...interface{}) {}
+func (nop) Info(string, ...interface{})  {}
+func (nop) Warn(string, ...interface{})  {}
+func (nop) Error(string, ...interface{}) {}
+func (n nop) With(...interface{}) Logger { return n }
+func (nop) Enabled(Level) bool           { return false }
+
+// Nop is a Logger which writes nothing.
+var Nop Logger = nop{}
+
+// the atomic.Value requires the same concrete type on each Store.
+type loggerHolder struct{ Logger }
+
+var defaultLogger atomic.Value // loggerHolder.
+
+func init() {
+	SetDefault(New(Golog(golog.Default), nil))
+}
+
+// Default returns the package-level Logger, which is used by the parts of the framework
+// that are not bound to an Application, i.e the MVC warnings.
+// Defaults to a Logger which writes to the `golog.Default`.
+func Default() Logger {
+	return defaultLogger.Load().(loggerHolder).Logger
+}
+
+// SetDefault replaces the package-level Logger.
+func SetDefault(l Logger) {
+	if l == nil {
+		l = Nop
+	}
+
+	defaultLogger.Store(loggerHolder{l})
+}
+
+// Var is a Logger which can be replaced at runtime, safe for concurrent use.
+// Its zero value writes to the `Default()` Logger, until `Set` is called.
+// A Var must not be copied after first use.
+type Var struct {
+	v atomic.Value // loggerHolder.
+}
+
+// Logger returns the current Logger.
+func (v *Var) Logger() Logger {
+	if h, ok := v.v.Load().(loggerHolder); ok {
+		return h.Logger
+	}
+
+	return Default()
+}
+
+// Set replaces the current Logger.
+func (v *Var) Set(l Logger) {
+	if l == nil {
+		l = Nop
+	}
+
+	v.v.Store(loggerHolder{l})
+}
+
+func (v *Var) Debug(msg string, keyvals ...interface{}) { v.Logger().Debug(msg, keyvals...) }
+func (v *Var) Info(msg string, keyvals ...interface{})  { v.Logger().Info(msg, keyvals...) }
+func (v *Var) Warn(msg string, keyvals ...interface{})  { v.Logger().Warn(msg, keyvals...) }
+func (v *Var) Error(msg string, keyvals ...interface{}) { v.Logger().Error(msg, keyvals...) }
+func (v *Var) With(keyvals ...interface{}) Logger       { return v.Logger().With(keyvals...) }
+func (v *Var) Enabled(level Level) bool                 { return v.Logger().Enabled(level) }
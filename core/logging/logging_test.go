@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kataras/golog"
+)
+
+func TestLogger(t *testing.T) {
+	var records []Record
+	level := NewLevelVar(InfoLevel)
+	logger := New(SinkFunc(func(r Record) {
+		records = append(records, r)
+	}), level)
+
+	logger.Debug("debug")
+	logger.With("request", 42).Info("info", "method", "GET", Field{Key: "path", Value: "/"}, "odd")
+
+	level.Set(DebugLevel)
+	logger.Debug("debug")
+
+	if expected, got := 2, len(records); expected != got {
+		t.Fatalf("expected %d records but got %d", expected, got)
+	}
+
+	expected := []Field{{"request", 42}, {"method", "GET"}, {"path", "/"}, {"odd", "!MISSING"}}
+	if got := records[0].Fields; len(got) != len(expected) {
+		t.Fatalf("expected fields: %v but got: %v", expected, got)
+	}
+	for i, f := range expected {
+		if got := records[0].Fields[i]; got != f {
+			t.Fatalf("[%d] expected field: %v but got: %v", i, f, got)
+		}
+	}
+
+	if records[0].Level != InfoLevel || records[1].Level != DebugLevel {
+		t.Fatalf("unexpected levels: %s, %s", records[0].Level, records[1].Level)
+	}
+}
+
+func TestVar(t *testing.T) {
+	var v Var
+	if v.Logger() != Default() {
+		t.Fatalf("expected the zero Var to write to the default logger")
+	}
+
+	var records []Record
+	v.Set(New(SinkFunc(func(r Record) {
+		records = append(records, r)
+	}), nil))
+	v.Warn("warn", "key", "value")
+
+	if len(records) != 1 || records[0].Message != "warn" {
+		t.Fatalf("expected a record of the set logger but got: %v", records)
+	}
+
+	v.Set(nil)
+	if v.Logger() != Nop {
+		t.Fatalf("expected the Nop logger")
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	New(JSON(buf), nil).Error("failed", "error", errors.New("timeout"), "status", 500)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["level"] != "error" || got["msg"] != "failed" || got["error"] != "timeout" || got["status"] != float64(500) {
+		t.Fatalf("unexpected record: %s", buf.String())
+	}
+}
+
+func TestGologSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	g := golog.New()
+	g.SetOutput(buf)
+	g.SetTimeFormat("")
+	g.SetLevel("info")
+
+	logger := New(Golog(g), nil)
+	logger.Debug("hidden")
+	logger.Info("route registered", "method", "GET", "path", "/users list")
+
+	if expected, got := "[INFO] route registered method=GET path=\"/users list\"\n", buf.String(); expected != got {
+		t.Fatalf("expected: %q but got: %q", expected, got)
+	}
+
+	g.SetLevel("debug")
+	if !logger.Enabled(DebugLevel) {
+		t.Fatalf("expected the debug level to be enabled after golog's SetLevel")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{"debug": DebugLevel, "WARN": WarnLevel, "disable": DisableLevel} {
+		if got, err := ParseLevel(name); err != nil || got != expected {
+			t.Fatalf("[%s] expected level: %s but got: %s (%v)", name, expected, got, err)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil || !strings.Contains(err.Error(), "verbose") {
+		t.Fatalf("expected an error for unknown level")
+	}
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+type gologSink struct {
+	logger *golog.Logger
+}
+
+// Golog returns a Sink which writes the records to a golog logger,
+// the fields are appended to the message as key=value pairs.
+// The golog logger's level is respected, so `Application.Logger().SetLevel`
+// changes the level of the Application's structured logger too.
+func Golog(logger *golog.Logger) Sink {
+	return &gologSink{logger: logger}
+}
+
+func gologLevel(level Level) golog.Level {
+	switch level {
+	case DebugLevel:
+		return golog.DebugLevel
+	case InfoLevel:
+		return golog.InfoLevel
+	case WarnLevel:
+		return golog.WarnLevel
+	case ErrorLevel:
+		return golog.ErrorLevel
+	default:
+		return golog.DisableLevel
+	}
+}
+
+func (s *gologSink) Enabled(level Level) bool {
+	l := gologLevel(level)
+	return l != golog.DisableLevel && s.logger.Level >= l
+}
+
+func (s *gologSink) Write(r Record) {
+	s.logger.Log(gologLevel(r.Level), formatText(r.Message, r.Fields))
+}
+
+// formatText returns the "msg" followed by the key=value "fields".
+func formatText(msg string, fields []Field) string {
+	if len(fields) == 0 {
+		return msg
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+
+		s := fmt.Sprintf("%v", fieldValue(f.Value))
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+
+	return b.String()
+}
+
+// fieldValue returns the error's message for errors,
+// they are not JSON-friendly, otherwise the "v" as it is.
+func fieldValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return v
+	}
+}
+
+// Text returns a Sink which writes the records as lines of
+// time, level, message and key=value fields to "w", safe for concurrent use.
+func Text(w io.Writer) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(r Record) {
+		line := r.Time.Format(time.RFC3339) + " " + strings.ToUpper(r.Level.String()) + " " + formatText(r.Message, r.Fields) + "\n"
+
+		mu.Lock()
+		io.WriteString(w, line)
+		mu.Unlock()
+	})
+}
+
+// JSONKeys are the keys of the time, level and message of the JSON records.
+type JSONKeys struct {
+	Time    string
+	Level   string
+	Message string
+}
+
+// JSON returns a Sink which writes the records as JSON lines to "w", safe for concurrent use, i.e
+//  {"time":"2020-06-01T12:00:00Z","level":"info","msg":"route registered","method":"GET"}
+func JSON(w io.Writer) Sink {
+	return jsonSink(w, JSONKeys{Time: "time", Level: "level", Message: "msg"})
+}
+
+// Zerolog returns a Sink which writes the records as JSON lines in the zerolog format to "w",
+// so they can be consumed by the writers of the github.com/rs/zerolog package, i.e
+//  logging.Zerolog(zerolog.ConsoleWriter{Out: os.Stderr})
+func Zerolog(w io.Writer) Sink {
+	return jsonSink(w, JSONKeys{Time: "time", Level: "level", Message: "message"})
+}
+
+func jsonSink(w io.Writer, keys JSONKeys) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(r Record) {
+		buf := new(bytes.Buffer)
+		buf.WriteByte('{')
+		writeJSONField(buf, keys.Time, r.Time.Format(time.RFC3339Nano))
+		buf.WriteByte(',')
+		writeJSONField(buf, keys.Level, r.Level.String())
+		buf.WriteByte(',')
+		writeJSONField(buf, keys.Message, r.Message)
+		for _, f := range r.Fields {
+			buf.WriteByte(',')
+			writeJSONField(buf, f.Key, fieldValue(f.Value))
+		}
+		buf.WriteString("}\n")
+
+		mu.Lock()
+		w.Write(buf.Bytes())
+		mu.Unlock()
+	})
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buf.Write(v)
+}
+
+// SugaredLogger is the interface of the `*zap.SugaredLogger` of the go.uber.org/zap package
+// which the `Zap` sink writes to.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// Zap returns a Sink which writes the records to a zap logger, i.e
+//  logging.Zap(zapLogger.Sugar())
+func Zap(logger SugaredLogger) Sink {
+	return SinkFunc(func(r Record) {
+		keyvals := make([]interface{}, 0, 2*len(r.Fields))
+		for _, f := range r.Fields {
+			keyvals = append(keyvals, f.Key, f.Value)
+		}
+
+		switch r.Level {
+		case DebugLevel:
+			logger.Debugw(r.Message, keyvals...)
+		case InfoLevel:
+			logger.Infow(r.Message, keyvals...)
+		case WarnLevel:
+			logger.Warnw(r.Message, keyvals...)
+		default:
+			logger.Errorw(r.Message, keyvals...)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	stdContext "context"
+	"log/slog"
+)
+
+// Slog returns a Sink which writes the records to a log/slog logger, i.e
+//  logging.Slog(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+// The slog logger's level is respected.
+func Slog(logger *slog.Logger) Sink {
+	return &slogSink{logger: logger}
+}
+
+type slogSink struct {
+	logger *slog.Logger
+}
+
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+func (s *slogSink) Enabled(level Level) bool {
+	return s.logger.Enabled(stdContext.Background(), slogLevel(level))
+}
+
+func (s *slogSink) Write(r Record) {
+	record := slog.NewRecord(r.Time, slogLevel(r.Level), r.Message, 0)
+	for _, f := range r.Fields {
+		record.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+
+	s.logger.Handler().Handle(stdContext.Background(), record)
+}
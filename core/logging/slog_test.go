@@ -0,0 +1,31 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogSink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	logger := New(Slog(slog.New(h)), nil)
+	logger.Debug("hidden")
+	logger.With("app", "iris").Warn("slow request", "path", "/users")
+
+	if expected, got := "level=WARN msg=\"slow request\" app=iris path=/users\n", buf.String(); expected != got {
+		t.Fatalf("expected: %q but got: %q", expected, got)
+	}
+}
@@ -78,8 +78,18 @@ func (e Entry) StringDefault(def string) string {
 		return def
 	}
 
-	if vString, ok := v.(string); ok {
-		return vString
+	switch vv := v.(type) {
+	case string:
+		return vv
+	// the typed path parameters, no need of the fmt package.
+	case int:
+		return strconv.Itoa(vv)
+	case int64:
+		return strconv.FormatInt(vv, 10)
+	case uint64:
+		return strconv.FormatUint(vv, 10)
+	case bool:
+		return strconv.FormatBool(vv)
 	}
 
 	val := fmt.Sprintf("%v", v)
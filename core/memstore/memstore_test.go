@@ -165,3 +165,20 @@ func TestJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestEntryString(t *testing.T) {
+	var p Store
+
+	p.Set("int", 42)
+	p.Set("int64", int64(-42))
+	p.Set("uint64", uint64(42))
+	p.Set("bool", true)
+	p.Set("float", 4.2)
+
+	expected := map[string]string{"int": "42", "int64": "-42", "uint64": "42", "bool": "true", "float": "4.2"}
+	for key, value := range expected {
+		if got := p.GetString(key); got != value {
+			t.Fatalf("[%s] expected: %q but got: %q", key, value, got)
+		}
+	}
+}
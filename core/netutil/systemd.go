@@ -0,0 +1,60 @@
+package netutil
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSystemdListeners is returned from `SystemdListeners`
+// when the process was not started through systemd socket activation.
+var ErrNoSystemdListeners = errors.New("systemd: no socket activation listeners")
+
+// systemd passes the listeners starting from this file descriptor.
+const systemdListenFdsStart = 3
+
+// SystemdListeners returns the listeners passed by systemd socket activation,
+// see the "LISTEN_PID", "LISTEN_FDS" and "LISTEN_FDNAMES" environment variables
+// at https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html.
+// The environment variables are unset, so child processes will not inherit them.
+func SystemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, ErrNoSystemdListeners
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, ErrNoSystemdListeners
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for i := 0; i < n; i++ {
+		name := "LISTEN_FD_" + strconv.Itoa(systemdListenFdsStart+i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		f := os.NewFile(uintptr(systemdListenFdsStart+i), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+
+		listeners = append(listeners, l)
+	}
+
+	return listeners, nil
+}
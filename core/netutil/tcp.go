@@ -63,7 +63,17 @@ func TCPKeepAlive(addr string) (ln net.Listener, err error) {
 }
 
 // UNIX returns a new unix(file) Listener.
+// A stale socket file, left by a previous process, is removed
+// but a socket file which is still in use by another server is not.
+// The socket file is removed when the listener is closed.
 func UNIX(socketFile string, mode os.FileMode) (net.Listener, error) {
+	if _, errOs := os.Stat(socketFile); errOs == nil {
+		if conn, err := net.DialTimeout("unix", socketFile, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s: socket file is in use", socketFile)
+		}
+	}
+
 	if errOs := os.Remove(socketFile); errOs != nil && !os.IsNotExist(errOs) {
 		return nil, fmt.Errorf("%s: %w", socketFile, errOs)
 	}
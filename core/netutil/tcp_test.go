@@ -0,0 +1,44 @@
+package netutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUNIX(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-unix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socketFile := filepath.Join(dir, "iris.sock")
+
+	// stale socket file.
+	if err = ioutil.WriteFile(socketFile, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := UNIX(socketFile, 0666)
+	if err != nil {
+		t.Fatalf("expected stale socket file to be removed but: %v", err)
+	}
+
+	if _, err = UNIX(socketFile, 0666); err == nil {
+		t.Fatalf("expected an error when the socket file is in use")
+	}
+
+	l.Close()
+	if _, err = os.Stat(socketFile); !os.IsNotExist(err) {
+		t.Fatalf("expected socket file to be removed on close but: %v", err)
+	}
+}
+
+func TestSystemdListenersNoActivation(t *testing.T) {
+	os.Unsetenv("LISTEN_PID")
+	if _, err := SystemdListeners(); err != ErrNoSystemdListeners {
+		t.Fatalf("expected error: %v but got: %v", ErrNoSystemdListeners, err)
+	}
+}
@@ -3,11 +3,14 @@ package router
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
@@ -41,6 +44,19 @@ var AllMethods = []string{
 type repository struct {
 	routes []*Route
 	pos    map[string]int
+
+	buildHooks    []func() error
+	registerHooks []func(*Route)
+}
+
+func (repo *repository) runBuildHooks() error {
+	for _, hook := range repo.buildHooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (repo *repository) get(routeName string) *Route {
@@ -106,6 +122,11 @@ func (repo *repository) register(route *Route, rule RouteRegisterRule) (*Route,
 	}
 
 	repo.pos[route.tmpl.Src] = len(repo.routes) - 1
+
+	for _, hook := range repo.registerHooks {
+		hook(route)
+	}
+
 	return route, nil
 }
 
@@ -150,6 +171,14 @@ type APIBuilder struct {
 	// per any party's (and its children) routes registered
 	// if the method "x" wasn't registered already via  the `Handle` (and its extensions like `Get`, `Post`...).
 	allowMethods []string
+	// autoOptions is set by the `AutoOptions` func.
+	// When true, the party's (and its children) routes
+	// register an OPTIONS route for their path, if not registered already.
+	autoOptions bool
+	// autoHead is set by the `AutoHead` func.
+	// When true, the party's (and its children) GET routes
+	// register a HEAD route for their path, if not registered already.
+	autoHead bool
 
 	// the per-party (and its children) execution rules for begin, main and done handlers.
 	handlerExecutionRules ExecutionRules
@@ -207,6 +236,34 @@ func (api *APIBuilder) GetReporter() *errgroup.Group {
 	return api.errors
 }
 
+// SetCacheHeaders sets the default Cache-Control, Expires and Surrogate-Control response headers
+// of the future routes of this Party and its children, i.e
+//  app.Party("/assets").SetCacheHeaders(cache.Public, cache.MaxAge(24*time.Hour), cache.Immutable)
+// A route handler can override them through `Context.CacheControl`.
+//
+// Returns this Party.
+func (api *APIBuilder) SetCacheHeaders(options ...context.CacheControlOption) Party {
+	cc := context.NewCacheControl(options...)
+	api.Use(func(ctx context.Context) {
+		cc.Apply(ctx.ResponseWriter().Header(), time.Now())
+		ctx.Next()
+	})
+
+	return api
+}
+
+// SetReadJSONOptions sets the default options of the `Context.ReadJSON` calls
+// of the future routes of this Party and its children, i.e
+//  api := app.Party("/api")
+//  api.SetReadJSONOptions(context.ReadJSONOptions{DisallowUnknownFields: true, MaxBytes: 1 << 20})
+// A `Context.ReadJSON` call with options overrides them.
+//
+// Returns this Party.
+func (api *APIBuilder) SetReadJSONOptions(opts context.ReadJSONOptions) Party {
+	api.Use(context.ReadJSONDefaults(opts))
+	return api
+}
+
 // AllowMethods will re-register the future routes that will be registered
 // via `Handle`, `Get`, `Post`, ... to the given "methods" on that Party and its children "Parties",
 // duplicates are not registered.
@@ -217,6 +274,32 @@ func (api *APIBuilder) AllowMethods(methods ...string) Party {
 	return api
 }
 
+// AutoOptions enables or disables the automatic OPTIONS responses for the future routes
+// of that Party and its children. When enabled, an OPTIONS route is registered
+// for each route's path, unless one is registered by the caller,
+// which responds with 204 and an "Allow" header listing the methods of that path.
+//
+// The OPTIONS route runs the Party's middleware, so a CORS middleware registered through `Use`
+// answers the preflight requests without hand-registered OPTIONS routes.
+//
+// Returns this Party.
+func (api *APIBuilder) AutoOptions(enable bool) Party {
+	api.autoOptions = enable
+	return api
+}
+
+// AutoHead enables or disables the automatic HEAD responses for the future GET routes
+// of that Party and its children. When enabled, a HEAD route is registered
+// for each GET route's path, unless one is registered by the caller,
+// which runs the GET route's handlers but discards the response body,
+// the "Content-Length" header is still set to the length of the discarded body.
+//
+// Returns this Party.
+func (api *APIBuilder) AutoHead(enable bool) Party {
+	api.autoHead = enable
+	return api
+}
+
 // SetExecutionRules alters the execution flow of the route handlers outside of the handlers themselves.
 //
 // For example, if for some reason the desired result is the (done or all) handlers to be executed no matter what
@@ -289,11 +372,115 @@ func (api *APIBuilder) Handle(method string, relativePath string, handlers ...co
 			api.errors.Add(err)
 			break
 		}
+
+		if api.autoHead && route.Method == http.MethodGet {
+			api.registerAutoHead(relativePath, handlers)
+		}
+
+		if api.autoOptions && route.Method != http.MethodOptions && route.Method != MethodNone {
+			api.registerAutoOptions(route, relativePath)
+		}
 	}
 
 	return route
 }
 
+// registerAutoRoutes registers the "method" routes of the "relativePath"
+// without the Party's allow methods, existing routes are kept as they're.
+func (api *APIBuilder) registerAutoRoutes(method, relativePath, description string, handlers context.Handlers, begin context.Handler) {
+	allowMethods := api.allowMethods
+	api.allowMethods = nil
+	routes := api.CreateRoutes([]string{method}, relativePath, handlers...)
+	api.allowMethods = allowMethods
+
+	for _, r := range routes {
+		if begin != nil {
+			// before any other handler, including the global ones.
+			r.beginHandlers = append(context.Handlers{begin}, r.beginHandlers...)
+		}
+
+		r.SetDescription(description)
+		if _, err := api.routes.register(r, RouteSkip); err != nil {
+			api.errors.Add(err)
+		}
+	}
+}
+
+// registerAutoHead registers the HEAD route of a GET route's path,
+// an existing HEAD route of that path is kept as it's.
+func (api *APIBuilder) registerAutoHead(relativePath string, handlers context.Handlers) {
+	api.registerAutoRoutes(http.MethodHead, relativePath, "auto head", handlers, autoHeadHandler)
+}
+
+// autoHeadHandler discards the response body of the next handlers,
+// the "Content-Length" header is set to the length of the body.
+func autoHeadHandler(ctx context.Context) {
+	w := &headResponseWriter{ResponseWriter: ctx.ResponseWriter()}
+	ctx.ResetResponseWriter(w)
+	ctx.Next()
+	ctx.ResetResponseWriter(w.ResponseWriter)
+
+	if w.Header().Get(context.ContentLengthHeaderKey) == "" {
+		w.Header().Set(context.ContentLengthHeaderKey, strconv.Itoa(w.length))
+	}
+}
+
+// headResponseWriter counts the bytes of the response body instead of writing them.
+type headResponseWriter struct {
+	context.ResponseWriter
+	length int
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.length == 0 && len(b) > 0 {
+		if _, ok := w.Header()[context.ContentTypeHeaderKey]; !ok {
+			w.Header().Set(context.ContentTypeHeaderKey, http.DetectContentType(b))
+		}
+	}
+
+	w.length += len(b)
+	return len(b), nil
+}
+
+func (w *headResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *headResponseWriter) Writef(format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, format, a...)
+}
+
+// registerAutoOptions registers the OPTIONS route of the "route"'s path,
+// an existing OPTIONS route of that path is kept as it's.
+func (api *APIBuilder) registerAutoOptions(route *Route, relativePath string) {
+	api.registerAutoRoutes(http.MethodOptions, relativePath, "auto options", context.Handlers{autoOptionsHandler(api.routes, route)}, nil)
+}
+
+// autoOptionsHandler responds with the methods registered for the "route"'s path.
+// The methods are collected on the first request, after the application is built.
+func autoOptionsHandler(routes *repository, route *Route) context.Handler {
+	var (
+		once  sync.Once
+		allow string
+	)
+
+	return func(ctx context.Context) {
+		once.Do(func() {
+			var methods []string
+			for _, r := range routes.getAll() {
+				if r.Subdomain == route.Subdomain && r.tmpl.Src == route.tmpl.Src && r.Method != http.MethodOptions && r.Method != MethodNone {
+					methods = append(methods, r.Method)
+				}
+			}
+
+			allow = strings.Join(removeDuplicates(append(methods, http.MethodOptions)), ", ")
+		})
+
+		ctx.Header("Allow", allow)
+		ctx.StatusCode(http.StatusNoContent)
+	}
+}
+
 // HandleMany works like `Handle` but can receive more than one
 // paths separated by spaces and returns always a slice of *Route instead of a single instance of Route.
 //
@@ -412,6 +599,115 @@ func (api *APIBuilder) HandleDir(requestPath, directory string, opts ...DirOptio
 	return getRoute
 }
 
+// Mount serves the "app", e.g. another Iris Application, under the "relativePath" of this Party.
+// The optional "handlers" run before the "app", e.g. authentication for that mount only.
+//
+// If the "app" is an Iris Application (a `RoutesProvider`) then its route tree is merged into this one:
+// each of its routes is registered under the "relativePath", i.e "/admin" + "/users/{id}",
+// with this Party's middleware and the "handlers" first. So the parameters of a dynamic "relativePath",
+// e.g. "/{lang}/admin", are available to its handlers too. Register the "app"'s routes before Mount.
+// The configurations stay isolated: the "app"'s configuration, error code handlers and router wrappers
+// are not applied to this Application and the "app" itself is not built or served.
+//
+// Any other http.Handler receives the requests of the "relativePath" and its sub paths
+// without the matched path prefix, i.e "/admin/users" is served by the handler as "/users".
+// If it can be built then it's built on this Party's build state.
+//
+// Example:
+//  admin := iris.New()
+//  admin.Get("/users", listUsers)
+//  app.Mount("/admin", admin, basicauth.Default(users))
+//
+// Returns the registered routes.
+func (api *APIBuilder) Mount(relativePath string, app http.Handler, handlers ...context.Handler) []*Route {
+	if provider, ok := app.(RoutesProvider); ok {
+		return api.mountRoutes(relativePath, provider, handlers)
+	}
+
+	if b, ok := app.(interface{ Build() error }); ok {
+		api.OnBuild(b.Build)
+	}
+
+	_, fullpath := splitSubdomainAndPath(joinPath(api.relativePath, relativePath))
+	// the matched segments of the request path, the prefix may contain dynamic parameters.
+	segments := strings.Count(strings.TrimSuffix(fullpath, "/"), "/")
+
+	h := func(ctx context.Context) {
+		r := ctx.Request()
+		path, rawPath := r.URL.Path, r.URL.RawPath
+		defer func() {
+			r.URL.Path, r.URL.RawPath = path, rawPath
+		}()
+
+		escaped := stripPathSegments(r.URL.EscapedPath(), segments)
+		unescaped, err := url.PathUnescape(escaped)
+		if err != nil {
+			unescaped = stripPathSegments(path, segments)
+		}
+
+		r.URL.Path = unescaped
+		r.URL.RawPath = ""
+		if escaped != unescaped {
+			r.URL.RawPath = escaped
+		}
+
+		app.ServeHTTP(ctx.ResponseWriter(), r)
+	}
+
+	handlers = joinHandlers(handlers, context.Handlers{h})
+	routes := append(api.Any(relativePath, handlers...), api.Any(joinPath(relativePath, WildcardParam("mount")), handlers...)...)
+	for _, r := range routes {
+		r.SetDescription("mount")
+	}
+
+	return routes
+}
+
+// mountRoutes registers the routes of the "provider" under the "relativePath".
+func (api *APIBuilder) mountRoutes(relativePath string, provider RoutesProvider, handlers context.Handlers) []*Route {
+	p := api.Party(relativePath, handlers...)
+
+	var routes []*Route
+	for _, r := range provider.GetRoutes() {
+		if r.Subdomain != "" {
+			api.errors.Addf("mount: %s: subdomain routes can not be mounted", r.String())
+			continue
+		}
+
+		// the route's handlers, without the parameters evaluator of its own path,
+		// a new one is added for the mounted path.
+		mainHandlers := r.Handlers
+		if macroHandler.CanMakeHandler(r.tmpl) && len(mainHandlers) > 0 {
+			mainHandlers = mainHandlers[1:]
+		}
+
+		chain := joinHandlers(joinHandlers(r.beginHandlers, mainHandlers), r.doneHandlers)
+		if mounted := p.Handle(r.Method, r.tmpl.Src, chain...); mounted != nil {
+			mounted.Description = r.Description
+			mounted.MainHandlerName = r.MainHandlerName
+			mounted.SourceFileName, mounted.SourceLineNumber = r.SourceFileName, r.SourceLineNumber
+			mounted.Metadata = r.Metadata
+			mounted.RequestType, mounted.ResponseType = r.RequestType, r.ResponseType
+			routes = append(routes, mounted)
+		}
+	}
+
+	return routes
+}
+
+// stripPathSegments removes the first "n" segments of the "path".
+func stripPathSegments(path string, n int) string {
+	for ; n > 0; n-- {
+		i := strings.IndexByte(path[1:], '/')
+		if i == -1 {
+			return "/"
+		}
+		path = path[i+1:]
+	}
+
+	return path
+}
+
 // CreateRoutes returns a list of Party-based Routes.
 // It does NOT registers the route. Use `Handle, Get...` methods instead.
 // This method can be used for third-parties Iris helpers packages and tools
@@ -485,6 +781,7 @@ func (api *APIBuilder) CreateRoutes(methods []string, relativePath string, handl
 		// The caller tiself, if anonymous, it's the first line of `app.X("/path", here)`
 		route.RegisterFileName = filename
 		route.RegisterLineNumber = line
+		route.PartyPath = api.relativePath
 
 		route.MainHandlerName = mainHandlerName
 		route.MainHandlerIndex = mainHandlerIndex
@@ -565,6 +862,8 @@ func (api *APIBuilder) Party(relativePath string, handlers ...context.Handler) P
 		doneHandlers:          api.doneHandlers[0:],
 		relativePath:          fullpath,
 		allowMethods:          allowMethods,
+		autoOptions:           api.autoOptions,
+		autoHead:              api.autoHead,
 		handlerExecutionRules: api.handlerExecutionRules,
 		routeRegisterRule:     api.routeRegisterRule,
 		apiBuilderDI: &APIContainer{
@@ -642,6 +941,31 @@ func (api *APIBuilder) Macros() *macro.Macros {
 	return api.macros
 }
 
+// OnBuild registers a function which is called right before the router is built,
+// after all routes have been registered, e.g. on `Application.Build` and `RefreshRouter`.
+// Hooks may modify the registered routes, i.e `Route.Use`. The hooks are shared between
+// all parties of the same root and they run in the order they were registered.
+// If a hook returns a non-nil error then the build fails with that error.
+func (api *APIBuilder) OnBuild(hook func() error) {
+	if hook == nil {
+		return
+	}
+
+	api.routes.buildHooks = append(api.routes.buildHooks, hook)
+}
+
+// OnRouteRegistered registers a function which is called on each route registration,
+// right after the route was added to the routes repository.
+// The hooks are shared between all parties of the same root and
+// they are not called for the routes that were registered before the hook.
+func (api *APIBuilder) OnRouteRegistered(hook func(*Route)) {
+	if hook == nil {
+		return
+	}
+
+	api.routes.registerHooks = append(api.routes.registerHooks, hook)
+}
+
 // GetRoutes returns the routes information,
 // some of them can be changed at runtime some others not.
 //
@@ -930,7 +1254,7 @@ func (api *APIBuilder) Favicon(favPath string, requestPath ...string) *Route {
 		ctx.ContentType(cType)
 		if _, err := ctx.WriteWithExpiration(cacheFav, modtime); err != nil {
 			ctx.StatusCode(http.StatusInternalServerError)
-			ctx.Application().Logger().Debugf("while trying to serve the favicon: %s", err.Error())
+			ctx.Application().Log().Debug("favicon: write failed", "file", favPath, "error", err)
 		}
 	}
 
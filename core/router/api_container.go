@@ -117,7 +117,13 @@ func (api *APIContainer) Done(handlersFn ...interface{}) {
 // See `OnError`, `RegisterDependency`, `Use`, `Done`, `Get`, `Post`, `Put`, `Patch` and `Delete` too.
 func (api *APIContainer) Handle(method, relativePath string, handlersFn ...interface{}) *Route {
 	handlers := api.convertHandlerFuncs(relativePath, handlersFn...)
-	return api.Self.Handle(method, relativePath, handlers...)
+	route := api.Self.Handle(method, relativePath, handlers...)
+	if route != nil && len(handlersFn) > 0 {
+		paramsCount := macro.CountParams(api.Self.GetRelPath()+relativePath, *api.Self.Macros())
+		route.RequestType, route.ResponseType = api.Container.FuncTypes(handlersFn[len(handlersFn)-1], paramsCount)
+	}
+
+	return route
 }
 
 // Get registers a route for the Get HTTP Method.
@@ -0,0 +1,50 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// AssetManifestName is the filename of the `AssetManifest`, written by the "iris-embed" command
+// to the root of the assets directory.
+const AssetManifestName = "iris-embed.json"
+
+// AssetManifest maps the asset files, relative to their directory, to the fingerprints of their contents,
+// i.e "css/main.css": "012a66c25ad2c94d", see the "iris-embed" command.
+type AssetManifest map[string]string
+
+// ReadAssetManifest decodes an `AssetManifest` from the "r" reader.
+func ReadAssetManifest(r io.Reader) (AssetManifest, error) {
+	m := make(AssetManifest)
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// AssetFingerprint returns the fingerprint of the "contents" of an asset,
+// the first 8 bytes of its SHA-256 hash, hex encoded.
+func AssetFingerprint(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Path returns the request path of the asset of the "name" served under the "requestPath"
+// with its fingerprint as the "v" query parameter, i.e "/static/css/main.css?v=012a66c25ad2c94d",
+// so the clients can cache the assets forever and a new build busts their caches.
+// It's commonly registered as a view func, i.e
+//  tmpl.AddFunc("asset", func(name string) string { return manifest.Path("/static", name) })
+func (m AssetManifest) Path(requestPath, name string) string {
+	name = strings.TrimPrefix(name, "/")
+	p := joinPath(requestPath, name)
+	if fp, ok := m[name]; ok {
+		p += "?v=" + url.QueryEscape(fp)
+	}
+
+	return p
+}
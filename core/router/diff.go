@@ -0,0 +1,56 @@
+package router
+
+import "strings"
+
+// RoutesDiff holds the differences between two sets of routes,
+// see `APIBuilder.RoutesDiff`.
+type RoutesDiff struct {
+	// Added are the routes that exist only on the current routes provider.
+	Added []*Route `json:"added"`
+	// Removed are the routes that exist only on the other routes provider.
+	Removed []*Route `json:"removed"`
+	// Changed are the routes of the current routes provider
+	// that their handlers chain differs from the other's one.
+	Changed []*Route `json:"changed"`
+}
+
+// Empty reports whether there are no differences.
+func (d RoutesDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// RoutesDiff compares the registered routes against the "other" routes provider's ones,
+// e.g. a previous deployment's Application. Routes are matched by their method,
+// subdomain and template path and two matched routes are considered changed
+// when their handlers chain (middleware included) differs.
+func (api *APIBuilder) RoutesDiff(other RoutesProvider) RoutesDiff {
+	var diff RoutesDiff
+
+	otherRoutes := make(map[string]*Route)
+	for _, r := range other.GetRoutes() {
+		otherRoutes[r.String()] = r
+	}
+
+	for _, r := range api.GetRoutes() {
+		key := r.String()
+		o, ok := otherRoutes[key]
+		if !ok {
+			diff.Added = append(diff.Added, r)
+			continue
+		}
+
+		delete(otherRoutes, key)
+
+		if strings.Join(r.HandlersNames(), ",") != strings.Join(o.HandlersNames(), ",") {
+			diff.Changed = append(diff.Changed, r)
+		}
+	}
+
+	for _, r := range other.GetRoutes() { // keep registration order.
+		if _, ok := otherRoutes[r.String()]; ok {
+			diff.Removed = append(diff.Removed, r)
+		}
+	}
+
+	return diff
+}
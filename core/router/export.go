@@ -0,0 +1,232 @@
+package router
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RouteDescription is the machine-readable description of a route,
+// consumable by generators of API clients, see `ExportRoutes`.
+type RouteDescription struct {
+	Name      string             `json:"name" yaml:"name"`
+	Method    string             `json:"method" yaml:"method"`
+	Subdomain string             `json:"subdomain,omitempty" yaml:"subdomain,omitempty"`
+	Path      string             `json:"path" yaml:"path"` // the path template, i.e "/users/{id:uint64}".
+	Params    []ParamDescription `json:"params,omitempty" yaml:"params,omitempty"`
+	// Request is the request payload's type, if any.
+	Request *TypeDescription `json:"request,omitempty" yaml:"request,omitempty"`
+	// Response is the response's type, if any.
+	Response    *TypeDescription `json:"response,omitempty" yaml:"response,omitempty"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ParamDescription describes a path parameter of a route.
+type ParamDescription struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"` // the macro's type, i.e "uint64".
+}
+
+// TypeDescription describes a Go type the way it's encoded to JSON.
+type TypeDescription struct {
+	// Name is the Go type's name, i.e "main.User", empty for unnamed types.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Kind is one of: "struct", "array", "map", "string", "bool",
+	// "int", "uint", "float", "time" and "any".
+	Kind string `json:"kind" yaml:"kind"`
+	// Nullable reports whether the value can be null, i.e a pointer.
+	Nullable bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	// Elem is the type of the array's and map's elements.
+	Elem *TypeDescription `json:"elem,omitempty" yaml:"elem,omitempty"`
+	// Fields are the struct's fields, empty when a struct is referenced by itself
+	// (recursive types), see the `Name` field for the definition instead.
+	Fields []FieldDescription `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// FieldDescription describes a struct field by its JSON name.
+type FieldDescription struct {
+	Name     string           `json:"name" yaml:"name"`
+	Type     *TypeDescription `json:"type" yaml:"type"`
+	Optional bool             `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+// DescribeRoutes returns the descriptions of the registered routes, except the offline ones.
+// The request payload and the response types are available for the routes
+// registered through the dependency injection, i.e `ConfigureContainer().Post("/users", createUser)`.
+func (api *APIBuilder) DescribeRoutes() []RouteDescription {
+	routes := api.GetRoutes()
+	descriptions := make([]RouteDescription, 0, len(routes))
+
+	for _, r := range routes {
+		if r.Method == MethodNone {
+			continue
+		}
+
+		d := RouteDescription{
+			Name:        r.Name,
+			Method:      r.Method,
+			Subdomain:   r.Subdomain,
+			Path:        r.tmpl.Src,
+			Request:     DescribeType(r.RequestType),
+			Response:    DescribeType(r.ResponseType),
+			Description: r.Description,
+		}
+
+		for _, p := range r.tmpl.Params {
+			d.Params = append(d.Params, ParamDescription{Name: p.Name, Type: p.Type.Indent()})
+		}
+
+		descriptions = append(descriptions, d)
+	}
+
+	return descriptions
+}
+
+// ExportRoutes returns the `DescribeRoutes` encoded to the "format",
+// "json" or "yaml", to be consumed by generators of TypeScript or Go API clients, i.e
+//  b, err := app.ExportRoutes("json")
+//  ioutil.WriteFile("routes.json", b, 0644)
+func (api *APIBuilder) ExportRoutes(format string) ([]byte, error) {
+	descriptions := api.DescribeRoutes()
+
+	switch strings.ToLower(format) {
+	case "json":
+		return json.MarshalIndent(descriptions, "", "  ")
+	case "yaml", "yml":
+		return yaml.Marshal(descriptions)
+	default:
+		return nil, fmt.Errorf("export routes: unsupported format: %q", format)
+	}
+}
+
+var (
+	timeTyp          = reflect.TypeOf(time.Time{})
+	jsonMarshalerTyp = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerTyp = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// DescribeType returns the description of the "typ", based on its JSON encoding.
+// It returns nil if "typ" is nil.
+func DescribeType(typ reflect.Type) *TypeDescription {
+	if typ == nil {
+		return nil
+	}
+
+	return describeType(typ, make(map[reflect.Type]struct{}))
+}
+
+func describeType(typ reflect.Type, seen map[reflect.Type]struct{}) *TypeDescription {
+	nullable := false
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		nullable = true
+	}
+
+	d := &TypeDescription{Nullable: nullable}
+	if typ.Name() != "" {
+		d.Name = typ.String()
+	}
+
+	switch {
+	case typ == timeTyp:
+		d.Kind = "time"
+		return d
+	case typ.Implements(jsonMarshalerTyp) || reflect.PtrTo(typ).Implements(jsonMarshalerTyp):
+		d.Kind = "any"
+		return d
+	case typ.Implements(textMarshalerTyp) || reflect.PtrTo(typ).Implements(textMarshalerTyp):
+		d.Kind = "string"
+		return d
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		d.Kind = "string"
+	case reflect.Bool:
+		d.Kind = "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.Kind = "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.Kind = "uint"
+	case reflect.Float32, reflect.Float64:
+		d.Kind = "float"
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 { // base64 encoded.
+			d.Kind = "string"
+			break
+		}
+
+		d.Kind = "array"
+		d.Nullable = d.Nullable || typ.Kind() == reflect.Slice
+		d.Elem = describeType(typ.Elem(), seen)
+	case reflect.Map:
+		d.Kind = "map"
+		d.Elem = describeType(typ.Elem(), seen)
+	case reflect.Struct:
+		d.Kind = "struct"
+		if _, ok := seen[typ]; ok {
+			return d
+		}
+
+		seen[typ] = struct{}{}
+		d.Fields = describeFields(typ, seen)
+		delete(seen, typ)
+	default: // interfaces, channels, funcs.
+		d.Kind = "any"
+	}
+
+	return d
+}
+
+func describeFields(typ reflect.Type, seen map[reflect.Type]struct{}) (fields []FieldDescription) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" && !f.Anonymous { // unexported.
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			if idx := strings.IndexByte(tag, ','); idx != -1 {
+				tag, opts = tag[:idx], tag[idx:]
+			}
+
+			if tag != "" {
+				name = tag
+			} else if f.Anonymous {
+				name = ""
+			}
+		} else if f.Anonymous {
+			name = ""
+		}
+
+		if name == "" { // embedded struct, its fields are promoted.
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, describeFields(ft, seen)...)
+			}
+			continue
+		}
+
+		fields = append(fields, FieldDescription{
+			Name:     name,
+			Type:     describeType(f.Type, seen),
+			Optional: strings.Contains(opts, ",omitempty"),
+		})
+	}
+
+	return
+}
@@ -0,0 +1,91 @@
+package router_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+type exportUser struct {
+	ID        uint64        `json:"id"`
+	Name      string        `json:"name"`
+	Email     string        `json:"email,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	Friends   []*exportUser `json:"friends"`
+	password  string
+}
+
+type exportCreateUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestExportRoutes(t *testing.T) {
+	app := iris.New()
+	app.Get("/health", func(ctx iris.Context) {})
+	users := app.Party("/users").ConfigureContainer()
+	users.Post("/", func(req exportCreateUser) (exportUser, error) {
+		return exportUser{Name: req.Name}, nil
+	})
+	users.Get("/{id:uint64}", func(id uint64) *exportUser {
+		return &exportUser{ID: id}
+	})
+
+	descriptions := app.DescribeRoutes()
+	if expected, got := 3, len(descriptions); expected != got {
+		t.Fatalf("expected %d routes but got %d", expected, got)
+	}
+
+	if d := descriptions[0]; d.Path != "/health" || d.Request != nil || d.Response != nil {
+		t.Fatalf("unexpected description of a native handler: %#+v", d)
+	}
+
+	create := descriptions[1]
+	if create.Method != iris.MethodPost || create.Request == nil || create.Request.Name != "router_test.exportCreateUser" {
+		t.Fatalf("unexpected request description: %#+v", create)
+	}
+
+	userType := &router.TypeDescription{
+		Name: "router_test.exportUser",
+		Kind: "struct",
+		Fields: []router.FieldDescription{
+			{Name: "id", Type: &router.TypeDescription{Kind: "uint", Name: "uint64"}},
+			{Name: "name", Type: &router.TypeDescription{Kind: "string", Name: "string"}},
+			{Name: "email", Type: &router.TypeDescription{Kind: "string", Name: "string"}, Optional: true},
+			{Name: "created_at", Type: &router.TypeDescription{Kind: "time", Name: "time.Time"}},
+			{Name: "friends", Type: &router.TypeDescription{Kind: "array", Nullable: true, Elem: &router.TypeDescription{
+				Name:     "router_test.exportUser",
+				Kind:     "struct",
+				Nullable: true,
+			}}},
+		},
+	}
+
+	if !reflect.DeepEqual(create.Response, userType) {
+		b, _ := json.Marshal(create.Response)
+		t.Fatalf("unexpected response description: %s", b)
+	}
+
+	get := descriptions[2]
+	if expected, got := []router.ParamDescription{{Name: "id", Type: "uint64"}}, get.Params; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected params: %#+v but got: %#+v", expected, got)
+	}
+
+	if get.Request != nil || get.Response == nil || !get.Response.Nullable {
+		t.Fatalf("unexpected description: %#+v", get)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		if b, err := app.ExportRoutes(format); err != nil || len(b) == 0 {
+			t.Fatalf("[%s] unexpected export result: %v", format, err)
+		}
+	}
+
+	if _, err := app.ExportRoutes("xml"); err == nil {
+		t.Fatal("expected an unsupported format error")
+	}
+}
@@ -42,6 +42,16 @@ type DirOptions struct {
 
 	// Optional validator that loops through each requested resource.
 	AssetValidator func(ctx context.Context, name string) bool
+
+	// PreCompressed serves the pre-compressed sibling of a requested file,
+	// "name.br" (brotli) or "name.gz" (gzip), when it exists and the client accepts its encoding,
+	// e.g. generated at build time through the "iris-embed" command.
+	PreCompressed bool
+	// MemoryCache, if greater than zero, serves the system files up to that size, in bytes,
+	// from memory, read on their first request and read again when they are modified,
+	// so the frequently served small files are not read from the disk on each request.
+	// The bigger files are sent through the sendfile system call, when possible.
+	MemoryCache int64
 }
 
 func getDirOptions(opts ...DirOptions) (options DirOptions) {
@@ -178,8 +188,12 @@ func FileServer(directory string, opts ...DirOptions) context.Handler {
 
 	options := getDirOptions(opts...)
 
-	// `embeddedFileSystem` (if AssetInfo, Asset and AssetNames are defined) or `http.Dir`.
+	// `embeddedFileSystem` (if AssetInfo, Asset and AssetNames are defined),
+	// `memoryFileSystem` (if MemoryCache is set) or `http.Dir`.
 	var fs http.FileSystem = http.Dir(directory)
+	if options.MemoryCache > 0 {
+		fs = newMemoryFileSystem(http.Dir(directory), options.MemoryCache)
+	}
 
 	if options.Asset != nil && options.AssetInfo != nil && options.AssetNames != nil {
 		// Depends on the command the user gave to the go-bindata
@@ -425,6 +439,11 @@ func FileServer(directory string, opts ...DirOptions) context.Handler {
 		// and the binary data inside "f".
 		detectOrWriteContentType(ctx, info.Name(), f)
 
+		if options.PreCompressed && servePreCompressed(ctx, fs, name, info) {
+			ctx.Next()
+			return
+		}
+
 		if gzip {
 			// set the last modified as "serveContent" does.
 			ctx.SetLastModified(info.ModTime())
@@ -432,7 +451,7 @@ func FileServer(directory string, opts ...DirOptions) context.Handler {
 			// write the file to the response writer.
 			contents, err := ioutil.ReadAll(f)
 			if err != nil {
-				ctx.Application().Logger().Debugf("err reading file: %v", err)
+				ctx.Application().Log().Debug("file server: read failed", "file", info.Name(), "error", err)
 				plainStatusCode(ctx, http.StatusInternalServerError)
 				return
 			}
@@ -442,7 +461,7 @@ func FileServer(directory string, opts ...DirOptions) context.Handler {
 			// the `FlushResponse`.
 			_, err = ctx.GzipResponseWriter().Write(contents)
 			if err != nil {
-				ctx.Application().Logger().Debugf("short write: %v", err)
+				ctx.Application().Log().Debug("file server: short write", "file", info.Name(), "error", err)
 				plainStatusCode(ctx, http.StatusInternalServerError)
 				return
 			}
@@ -517,6 +536,52 @@ func Abs(path string) string {
 // The algorithm uses at most sniffLen bytes to make its decision.
 const sniffLen = 512
 
+// preCompressedEncodings are the content encodings of the `DirOptions.PreCompressed`
+// and their file extensions, by preference.
+var preCompressedEncodings = [...]struct {
+	encoding, ext string
+}{
+	{"br", ".br"},
+	{context.GzipHeaderValue, ".gz"},
+}
+
+// servePreCompressed serves the pre-compressed sibling of the file of the "name",
+// if the client accepts its encoding. It reports whether the file was served.
+func servePreCompressed(ctx context.Context, fs http.FileSystem, name string, info os.FileInfo) bool {
+	accept := ctx.AcceptHeader(context.AcceptEncodingHeaderKey)
+	if len(accept) == 0 {
+		return false
+	}
+
+	for _, enc := range preCompressedEncodings {
+		if !accept.Accepts(enc.encoding) {
+			continue
+		}
+
+		f, err := fs.Open(name + enc.ext)
+		if err != nil {
+			continue
+		}
+
+		if cinfo, err := f.Stat(); err != nil || cinfo.IsDir() {
+			f.Close()
+			continue
+		}
+
+		if w, ok := ctx.ResponseWriter().(*context.GzipResponseWriter); ok && w != nil {
+			w.Disable() // already compressed.
+		}
+
+		ctx.Header(context.VaryHeaderKey, context.AcceptEncodingHeaderKey)
+		ctx.ResponseWriter().Header().Set(context.ContentEncodingHeaderKey, enc.encoding)
+		http.ServeContent(ctx.ResponseWriter(), ctx.Request(), "", info.ModTime(), f)
+		f.Close()
+		return true
+	}
+
+	return false
+}
+
 func detectOrWriteContentType(ctx context.Context, name string, content io.ReadSeeker) (string, error) {
 	// If Content-Type isn't set, use the file's extension to find it, but
 	// if the Content-Type is unset explicitly, do not sniff the type.
@@ -0,0 +1,65 @@
+// +build go1.16
+
+package router
+
+import (
+	"io/fs"
+	"os"
+	"path"
+)
+
+// EmbeddedDir returns the `DirOptions` of the `HandleDir` and `FileServer`
+// which serve the files of the "fsys", i.e an embed.FS, instead of the system directory.
+// The directory of the `HandleDir` is the files' directory inside the "fsys", i.e
+//  //go:embed assets
+//  var assets embed.FS
+//
+//  app.HandleDir("/static", "./assets", iris.EmbeddedDir(assets, *dev))
+// When "disk" is true, the files are served from the system directory instead,
+// so the assets can be edited without a rebuild during the development.
+// The pre-compressed siblings of the files (see the "iris-embed" command) are served on both cases.
+func EmbeddedDir(fsys fs.FS, disk bool, opts ...DirOptions) DirOptions {
+	options := getDirOptions(opts...)
+	options.PreCompressed = true
+
+	if disk {
+		return options
+	}
+
+	options.Asset = func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+
+	options.AssetInfo = func(name string) (os.FileInfo, error) {
+		return fs.Stat(fsys, name)
+	}
+
+	options.AssetNames = func() []string {
+		var names []string
+		fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				names = append(names, name)
+			}
+			return nil
+		})
+
+		return names
+	}
+
+	return options
+}
+
+// LoadAssetManifest reads the `AssetManifest` of the "dir" directory of the "fsys".
+// A missing manifest is not an error, an empty manifest is returned instead.
+func LoadAssetManifest(fsys fs.FS, dir string) (AssetManifest, error) {
+	f, err := fsys.Open(path.Join(dir, AssetManifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(AssetManifest), nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadAssetManifest(f)
+}
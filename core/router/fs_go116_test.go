@@ -0,0 +1,54 @@
+//go:build go1.16
+// +build go1.16
+
+package router_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestEmbeddedDir(t *testing.T) {
+	css := []byte("body { color: red; }")
+	gz := new(bytes.Buffer)
+	w := gzip.NewWriter(gz)
+	w.Write(css)
+	w.Close()
+
+	fsys := fstest.MapFS{
+		"assets/css/main.css":                {Data: css},
+		"assets/css/main.css.gz":             {Data: gz.Bytes()},
+		"assets/js/app.js":                   {Data: []byte("console.log(1)")},
+		"assets/" + router.AssetManifestName: {Data: []byte(`{"css/main.css": "3f2a9c1b"}`)},
+	}
+
+	app := iris.New()
+	app.HandleDir("/static", "./assets", iris.EmbeddedDir(fsys, false))
+
+	manifest, err := iris.LoadAssetManifest(fsys, "./assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "/static/css/main.css?v=3f2a9c1b", manifest.Path("/static", "css/main.css"); expected != got {
+		t.Fatalf("expected asset path: %s but got: %s", expected, got)
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/static/js/app.js").Expect().Status(httptest.StatusOK).
+		ContentType("text/javascript").Body().Equal("console.log(1)")
+
+	e.GET("/static/css/main.css").WithHeader("Accept-Encoding", "br;q=0, gzip").Expect().Status(httptest.StatusOK).
+		ContentType("text/css").
+		Header("Content-Encoding").Equal("gzip")
+
+	e.GET("/static/css/main.css").WithHeader("Accept-Encoding", "identity").Expect().Status(httptest.StatusOK).
+		Header("Content-Encoding").Empty()
+	e.GET("/static/css/main.css").WithHeader("Accept-Encoding", "identity").Expect().Body().Equal(string(css))
+}
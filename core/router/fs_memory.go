@@ -0,0 +1,75 @@
+package router
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// memoryFileSystem serves the small files of a system directory from memory,
+// see `DirOptions.MemoryCache`.
+type memoryFileSystem struct {
+	dir     http.Dir
+	maxSize int64
+
+	mu    sync.RWMutex
+	files map[string]*memoryEntry
+}
+
+type memoryEntry struct {
+	data    []byte
+	modTime time.Time
+}
+
+func newMemoryFileSystem(dir http.Dir, maxSize int64) *memoryFileSystem {
+	return &memoryFileSystem{
+		dir:     dir,
+		maxSize: maxSize,
+		files:   make(map[string]*memoryEntry),
+	}
+}
+
+func (fs *memoryFileSystem) Open(name string) (http.File, error) {
+	f, err := fs.dir.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() || info.Size() == 0 || info.Size() > fs.maxSize {
+		return f, err
+	}
+
+	fs.mu.RLock()
+	entry, ok := fs.files[name]
+	fs.mu.RUnlock()
+
+	if !ok || int64(len(entry.data)) != info.Size() || !entry.modTime.Equal(info.ModTime()) {
+		data := make([]byte, info.Size())
+		if _, err = io.ReadFull(f, data); err != nil {
+			// modified while reading, serve it from the disk.
+			f.Seek(0, io.SeekStart)
+			return f, nil
+		}
+
+		fs.mu.Lock()
+		// keep the entry of a concurrent first request, if it's the same.
+		if cur, ok := fs.files[name]; ok && len(cur.data) == len(data) && cur.modTime.Equal(info.ModTime()) {
+			entry = cur
+		} else {
+			// the previous entry's data are released by the garbage collector
+			// after the requests which still read them are done.
+			entry = &memoryEntry{data: data, modTime: info.ModTime()}
+			fs.files[name] = entry
+		}
+		fs.mu.Unlock()
+	}
+
+	f.Close()
+	return &embeddedFile{
+		FileInfo:   info,
+		ReadSeeker: bytes.NewReader(entry.data),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/netutil"
+	"github.com/kataras/iris/v12/macro"
+)
+
+// Fuzz is the entry point for the go-fuzz tool (https://github.com/dvyukov/go-fuzz)
+// and it's used by the native fuzz targets of this package too.
+// The "data" is split by new lines into a route path template, i.e "/users/{id:uint64 min(1)}",
+// a request path, i.e "/users/%2F42" and a request host, i.e "admin.mydomain.com".
+// The template is parsed by the path macro parser and inserted into a routes tree,
+// then the (percent-decoded) request path is searched through that tree
+// and the host is checked the way the router checks subdomains.
+//
+// It returns 1 when the route path template is valid, otherwise 0.
+func Fuzz(data []byte) int {
+	input := strings.SplitN(string(data), "\n", 3)
+	routePath := input[0]
+	requestPath, host := "/", ""
+	if len(input) > 1 {
+		requestPath = input[1]
+	}
+	if len(input) > 2 {
+		host = input[2]
+	}
+
+	netutil.IsLoopbackSubdomain(host)
+	netutil.ResolveVHost(host)
+
+	hasSubdomain(routePath)
+	_, path := splitSubdomainAndPath(routePath)
+
+	tmpl, err := macro.Parse(path, *macro.Defaults)
+	if err != nil {
+		return 0
+	}
+
+	for i := range tmpl.Params {
+		p := tmpl.Params[i]
+		if p.CanEval() {
+			p.Eval(requestPath)
+		}
+	}
+
+	tr := &trie{root: newTrieNode()}
+	tr.insert(convertMacroTmplToNodePath(tmpl), "fuzz", nil)
+
+	params := new(context.RequestParams)
+	tr.search(requestPath, params)
+	if decoded, err := url.PathUnescape(requestPath); err == nil {
+		tr.search(decoded, new(context.RequestParams))
+	}
+
+	return 1
+}
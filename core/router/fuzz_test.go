@@ -0,0 +1,41 @@
+//go:build go1.18
+// +build go1.18
+
+package router
+
+import "testing"
+
+func FuzzRouter(f *testing.F) {
+	for _, seed := range []string{
+		"/\n/\n",
+		"/users/{id:uint64 min(1)}\n/users/42\nlocalhost",
+		"/files/{file:path}\n/files/a/b/c.txt\n127.0.0.1:8080",
+		"admin./{name:string regexp(^[a-z]+$)}\n/kataras\nadmin.mydomain.com",
+		"*./api/{version:int64}/{p:path}\n/api/1/%2F..%2F\nsub.localhost",
+		"/{a}/{b:alphabetical}/static\n/a/b/static\n",
+		"/{n:int range(1,5)}\n/3\n",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		Fuzz(data)
+	})
+}
+
+func FuzzMacroParser(f *testing.F) {
+	for _, seed := range []string{
+		"/{id:int min(1) max(10) else 404}",
+		"/{name:string contains(a) suffix(b)}",
+		"/{p:path}",
+		"/{s:string regexp(\\d+)}",
+		"/{",
+		"/{}",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, tmpl string) {
+		Fuzz([]byte(tmpl + "\n/"))
+	})
+}
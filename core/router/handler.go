@@ -29,6 +29,7 @@ type routerHandler struct {
 	trees  []*trie
 	hosts  bool // true if at least one route contains a Subdomain.
 	config context.ConfigurationReadOnly
+	cache  *routeCache // see `Configuration.RouteCacheSize`.
 }
 
 var _ RequestHandler = &routerHandler{}
@@ -84,6 +85,7 @@ type RoutesProvider interface { // api builder
 
 func (h *routerHandler) Build(provider RoutesProvider) error {
 	h.trees = h.trees[0:0] // reset, inneed when rebuilding.
+	h.cache = nil
 	rp := errgroup.New("Routes Builder")
 	registeredRoutes := provider.GetRoutes()
 
@@ -196,6 +198,12 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 		}
 	}
 
+	if h.config != nil && !h.hosts {
+		if size := h.config.GetRouteCacheSize(); size > 0 {
+			h.cache = newRouteCache(size)
+		}
+	}
+
 	return errgroup.Check(rp)
 }
 
@@ -265,6 +273,14 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 		}
 	}
 
+	if h.cache != nil {
+		if n := h.cache.get(method, path, &ctx.Params().Store); n != nil {
+			ctx.SetCurrentRouteName(n.RouteName)
+			ctx.Do(n.Handlers)
+			return
+		}
+	}
+
 	for i := range h.trees {
 		t := h.trees[i]
 		if method != t.method {
@@ -302,8 +318,14 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 				continue
 			}
 		}
-		n := t.search(path, ctx.Params())
+		params := ctx.Params()
+		paramsLen := params.Len()
+		n := t.search(path, params)
 		if n != nil {
+			if h.cache != nil {
+				h.cache.add(method, path, n, params.Store[paramsLen:])
+			}
+
 			ctx.SetCurrentRouteName(n.RouteName)
 			ctx.Do(n.Handlers)
 			// found
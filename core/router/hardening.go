@@ -0,0 +1,170 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// RequestHardening contains the options to reject malformed or suspicious requests
+// with 400 Bad Request before they reach the router, see `NewRequestGuard`.
+// The zero value rejects nothing.
+type RequestHardening struct {
+	// RejectConflictingLength rejects requests which are vulnerable to request smuggling:
+	// requests with both Content-Length and Transfer-Encoding headers,
+	// with more than one, different, Content-Length values
+	// or with a Transfer-Encoding other than "chunked".
+	//
+	// The net/http server already protects against them: it rejects the different Content-Length values
+	// and the unsupported transfer encodings and it ignores the Content-Length of the chunked requests.
+	// This option matters only when the requests are not parsed by net/http,
+	// e.g. on the lambda package or a custom front end which constructs the *http.Request itself.
+	RejectConflictingLength bool `json:"rejectConflictingLength,omitempty" yaml:"RejectConflictingLength" toml:"RejectConflictingLength"`
+	// MaxHeaderCount is the maximum number of the request header values.
+	// Zero means no limit.
+	MaxHeaderCount int `json:"maxHeaderCount,omitempty" yaml:"MaxHeaderCount" toml:"MaxHeaderCount"`
+	// MaxHeaderBytes is the maximum length of all request header names and values.
+	// Zero means no limit.
+	MaxHeaderBytes int `json:"maxHeaderBytes,omitempty" yaml:"MaxHeaderBytes" toml:"MaxHeaderBytes"`
+	// AllowMethods if not empty then requests with a method
+	// which is not part of this list are rejected, i.e []string{"GET", "POST"}.
+	AllowMethods []string `json:"allowMethods,omitempty" yaml:"AllowMethods" toml:"AllowMethods"`
+	// RejectNULBytes rejects requests with NUL bytes in their path, raw or percent-encoded.
+	RejectNULBytes bool `json:"rejectNULBytes,omitempty" yaml:"RejectNULBytes" toml:"RejectNULBytes"`
+}
+
+// Enabled reports whether at least one of the options is set.
+func (h RequestHardening) Enabled() bool {
+	return h.RejectConflictingLength || h.MaxHeaderCount > 0 || h.MaxHeaderBytes > 0 ||
+		len(h.AllowMethods) > 0 || h.RejectNULBytes
+}
+
+// RejectedRequests contains the number of the requests
+// rejected by a `RequestGuard`, per reason.
+type RejectedRequests struct {
+	ConflictingLength uint64 `json:"conflictingLength"`
+	TooManyHeaders    uint64 `json:"tooManyHeaders"`
+	HeadersTooLarge   uint64 `json:"headersTooLarge"`
+	MethodNotAllowed  uint64 `json:"methodNotAllowed"`
+	NULBytes          uint64 `json:"nulBytes"`
+}
+
+// Total returns the number of all rejected requests.
+func (r RejectedRequests) Total() uint64 {
+	return r.ConflictingLength + r.TooManyHeaders + r.HeadersTooLarge + r.MethodNotAllowed + r.NULBytes
+}
+
+// RequestGuard rejects requests based on its `RequestHardening` options
+// and keeps the number of the rejected ones.
+type RequestGuard struct {
+	options        RequestHardening
+	allowedMethods map[string]struct{}
+
+	rejected RejectedRequests // atomic.
+}
+
+// NewRequestGuard returns a new `RequestGuard`,
+// register its `Wrapper` through `Router.WrapRouter`.
+func NewRequestGuard(options RequestHardening) *RequestGuard {
+	g := &RequestGuard{options: options}
+	if len(options.AllowMethods) > 0 {
+		g.allowedMethods = make(map[string]struct{}, len(options.AllowMethods))
+		for _, method := range options.AllowMethods {
+			g.allowedMethods[strings.ToUpper(method)] = struct{}{}
+		}
+	}
+
+	return g
+}
+
+// Wrapper is the router wrapper which responds with 400 Bad Request
+// to the requests that do not pass the checks, the router is never executed for them.
+func (g *RequestGuard) Wrapper(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+	if counter := g.check(r); counter != nil {
+		atomic.AddUint64(counter, 1)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	router(w, r)
+}
+
+// Rejected returns the number of the rejected requests so far.
+func (g *RequestGuard) Rejected() RejectedRequests {
+	return RejectedRequests{
+		ConflictingLength: atomic.LoadUint64(&g.rejected.ConflictingLength),
+		TooManyHeaders:    atomic.LoadUint64(&g.rejected.TooManyHeaders),
+		HeadersTooLarge:   atomic.LoadUint64(&g.rejected.HeadersTooLarge),
+		MethodNotAllowed:  atomic.LoadUint64(&g.rejected.MethodNotAllowed),
+		NULBytes:          atomic.LoadUint64(&g.rejected.NULBytes),
+	}
+}
+
+// check returns the counter of the failed check's reason or nil if the request is accepted.
+func (g *RequestGuard) check(r *http.Request) *uint64 {
+	if g.allowedMethods != nil {
+		if _, ok := g.allowedMethods[r.Method]; !ok {
+			return &g.rejected.MethodNotAllowed
+		}
+	}
+
+	if g.options.RejectNULBytes {
+		if strings.IndexByte(r.URL.Path, 0) != -1 || strings.Contains(strings.ToLower(r.URL.EscapedPath()), "%00") {
+			return &g.rejected.NULBytes
+		}
+	}
+
+	if g.options.RejectConflictingLength && hasConflictingLength(r) {
+		return &g.rejected.ConflictingLength
+	}
+
+	if g.options.MaxHeaderCount > 0 || g.options.MaxHeaderBytes > 0 {
+		count, size := 0, 0
+		for key, values := range r.Header {
+			for _, value := range values {
+				count++
+				size += len(key) + len(value)
+			}
+		}
+
+		if g.options.MaxHeaderCount > 0 && count > g.options.MaxHeaderCount {
+			return &g.rejected.TooManyHeaders
+		}
+
+		if g.options.MaxHeaderBytes > 0 && size > g.options.MaxHeaderBytes {
+			return &g.rejected.HeadersTooLarge
+		}
+	}
+
+	return nil
+}
+
+func hasConflictingLength(r *http.Request) bool {
+	// the net/http server moves the Transfer-Encoding header to the Request.TransferEncoding field.
+	transferEncoding := r.TransferEncoding
+	if values := r.Header.Values("Transfer-Encoding"); len(values) > 0 {
+		transferEncoding = append(transferEncoding, values...)
+	}
+
+	contentLength := r.Header.Values("Content-Length")
+
+	if len(transferEncoding) > 0 {
+		if len(contentLength) > 0 {
+			return true
+		}
+
+		for _, te := range transferEncoding {
+			if !strings.EqualFold(strings.TrimSpace(te), "chunked") {
+				return true
+			}
+		}
+	}
+
+	for i := 1; i < len(contentLength); i++ {
+		if strings.TrimSpace(contentLength[i]) != strings.TrimSpace(contentLength[0]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package router_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func TestRequestHardening(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithRequestHardening(router.RequestHardening{
+		RejectConflictingLength: true,
+		MaxHeaderCount:          3,
+		MaxHeaderBytes:          64,
+		AllowMethods:            []string{"GET", "POST"},
+		RejectNULBytes:          true,
+	}))
+	app.Any("/{p:path}", func(ctx iris.Context) {
+		ctx.WriteString("ok")
+	})
+
+	handler := app.BuildHandler()
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		header   http.Header
+		te       []string
+		expected int
+	}{
+		{"valid", "GET", "/users", http.Header{"X-A": {"a"}}, nil, iris.StatusOK},
+		{"disallowed method", "DELETE", "/users", nil, nil, iris.StatusBadRequest},
+		{"nul byte", "GET", "/users%00.html", nil, nil, iris.StatusBadRequest},
+		{"length and chunked", "POST", "/users", http.Header{"Content-Length": {"4"}}, []string{"chunked"}, iris.StatusBadRequest},
+		{"different lengths", "POST", "/users", http.Header{"Content-Length": {"4", "5"}}, nil, iris.StatusBadRequest},
+		{"unknown encoding", "POST", "/users", nil, []string{"gzip"}, iris.StatusBadRequest},
+		{"chunked", "POST", "/users", nil, []string{"chunked"}, iris.StatusOK},
+		{"too many headers", "GET", "/users", http.Header{"X-A": {"a", "b"}, "X-B": {"c", "d"}}, nil, iris.StatusBadRequest},
+		{"headers too large", "GET", "/users", http.Header{"X-A": {string(make([]byte, 65))}}, nil, iris.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := stdhttptest.NewRequest(tt.method, tt.target, nil)
+		for key, values := range tt.header {
+			req.Header[key] = values
+		}
+		req.TransferEncoding = tt.te
+
+		rec := stdhttptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.expected {
+			t.Fatalf("[%s] expected status code: %d but got: %d", tt.name, tt.expected, rec.Code)
+		}
+	}
+
+	expected := router.RejectedRequests{
+		ConflictingLength: 3,
+		TooManyHeaders:    1,
+		HeadersTooLarge:   1,
+		MethodNotAllowed:  1,
+		NULBytes:          1,
+	}
+	if got := app.RejectedRequests(); got != expected {
+		t.Fatalf("expected rejected requests: %#+v but got: %#+v", expected, got)
+	}
+}
+
+// TestRequestHardeningServer sends smuggling requests as raw bytes to a net/http server,
+// which rejects them, or drops their Content-Length, before the router.
+func TestRequestHardeningServer(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithRequestHardening(router.RequestHardening{RejectConflictingLength: true}))
+	app.Post("/", func(ctx iris.Context) {
+		body, _ := ctx.GetBody()
+		ctx.Writef("%d:%s", ctx.Request().ContentLength, body)
+	})
+
+	srv := stdhttptest.NewServer(app.BuildHandler())
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		raw          string
+		expected     int
+		expectedBody string
+	}{
+		{"length and chunked", "Content-Length: 4\r\nTransfer-Encoding: chunked\r\n\r\n2\r\nok\r\n0\r\n\r\n", http.StatusOK, "-1:ok"},
+		{"different lengths", "Content-Length: 2\r\nContent-Length: 3\r\n\r\nok", http.StatusBadRequest, ""},
+		{"unknown encoding", "Transfer-Encoding: gzip\r\n\r\n", http.StatusNotImplemented, ""},
+	}
+
+	for _, tt := range tests {
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conn.Write([]byte("POST / HTTP/1.1\r\nHost: localhost\r\n" + tt.raw))
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		conn.Close()
+
+		if resp.StatusCode != tt.expected {
+			t.Fatalf("[%s] expected status code: %d but got: %d", tt.name, tt.expected, resp.StatusCode)
+		}
+
+		if tt.expectedBody != "" && string(body) != tt.expectedBody {
+			t.Fatalf("[%s] expected body: %q but got: %q", tt.name, tt.expectedBody, body)
+		}
+	}
+
+	if got := app.RejectedRequests().ConflictingLength; got != 0 {
+		t.Fatalf("expected no requests to reach the guard but got: %d", got)
+	}
+}
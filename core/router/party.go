@@ -1,6 +1,8 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/errgroup"
 	"github.com/kataras/iris/v12/macro"
@@ -74,6 +76,27 @@ type Party interface {
 	//
 	// Returns this Party.
 	Reset() Party
+	// OnBuild registers a function which is called right before the router is built,
+	// after all routes have been registered.
+	// Hooks are shared between all parties of the same root.
+	// A non-nil error returned by a hook fails the build.
+	OnBuild(hook func() error)
+	// OnRouteRegistered registers a function which is called right after a route was registered.
+	// Hooks are shared between all parties of the same root.
+	OnRouteRegistered(hook func(*Route))
+
+	// SetCacheHeaders sets the default Cache-Control, Expires and Surrogate-Control response headers
+	// of the future routes of this Party and its children, see the cache package's options.
+	// A route handler can override them through `Context.CacheControl`.
+	//
+	// Returns this Party.
+	SetCacheHeaders(options ...context.CacheControlOption) Party
+	// SetReadJSONOptions sets the default options of the `Context.ReadJSON` calls
+	// of the future routes of this Party and its children, e.g. to disallow unknown fields.
+	// A `Context.ReadJSON` call with options overrides them.
+	//
+	// Returns this Party.
+	SetReadJSONOptions(opts context.ReadJSONOptions) Party
 
 	// AllowMethods will re-register the future routes that will be registered
 	// via `Handle`, `Get`, `Post`, ... to the given "methods" on that Party and its children "Parties",
@@ -81,6 +104,22 @@ type Party interface {
 	//
 	// Call of `AllowMethod` will override any previous allow methods.
 	AllowMethods(methods ...string) Party
+	// AutoOptions enables or disables the automatic OPTIONS responses for the future routes
+	// of that Party and its children. When enabled, an OPTIONS route is registered
+	// for each route's path, unless one is registered by the caller,
+	// which responds with 204 and an "Allow" header listing the methods of that path.
+	// The Party's middleware, e.g. CORS, runs before it.
+	//
+	// Returns this Party.
+	AutoOptions(enable bool) Party
+	// AutoHead enables or disables the automatic HEAD responses for the future GET routes
+	// of that Party and its children. When enabled, a HEAD route is registered
+	// for each GET route's path, unless one is registered by the caller,
+	// which runs the GET route's handlers but discards the response body,
+	// the "Content-Length" header is still set to the length of the discarded body.
+	//
+	// Returns this Party.
+	AutoHead(enable bool) Party
 
 	// SetExecutionRules alters the execution flow of the route handlers outside of the handlers themselves.
 	//
@@ -147,6 +186,16 @@ type Party interface {
 	//
 	// Examples can be found at: https://github.com/kataras/iris/tree/master/_examples/file-server
 	HandleDir(requestPath, directory string, opts ...DirOptions) *Route
+	// Mount serves the "app", e.g. another Iris Application, under the "relativePath" of this Party.
+	// The optional "handlers" run before the "app".
+	//
+	// The routes of an Iris Application are merged into this Party's routes, under the "relativePath",
+	// its configuration, error code handlers and router wrappers stay isolated and they are not applied.
+	// Any other http.Handler receives the requests of the "relativePath" and its sub paths,
+	// without the matched path prefix.
+	//
+	// Returns the registered routes.
+	Mount(relativePath string, app http.Handler, handlers ...context.Handler) []*Route
 
 	// None registers an "offline" route
 	// see context.ExecRoute(routeName) and
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/netutil"
 	"github.com/kataras/iris/v12/macro"
 	"github.com/kataras/iris/v12/macro/interpreter/ast"
@@ -230,7 +231,11 @@ func splitSubdomainAndPath(fullUnparsedPath string) (subdomain string, path stri
 	}
 
 	slashIdx := strings.IndexByte(s, '/')
-	if slashIdx > 0 {
+	if slashIdx == -1 {
+		// no subdomain and no leading slash, i.e "users".
+		s = "/" + s
+		slashIdx = 0
+	} else if slashIdx > 0 {
 		// has subdomain
 		subdomain = s[0:slashIdx]
 	}
@@ -335,6 +340,22 @@ func (ps *RoutePathReverser) Path(routeName string, paramValues ...interface{})
 	return r.ResolvePath(toStringSlice(paramValues)...)
 }
 
+// LocalePath same as `Path` but it prefixes the result with the current request's language,
+// i.e /el-GR/hello/iris, the default language is not prefixed.
+// See `Context.GetLocale` and the i18n's `CanonicalRedirect` field too.
+func (ps *RoutePathReverser) LocalePath(ctx context.Context, routeName string, paramValues ...interface{}) string {
+	p := ps.Path(routeName, paramValues...)
+	if p == "" {
+		return ""
+	}
+
+	if loc := ctx.GetLocale(); loc != nil && loc.Index() > 0 {
+		return "/" + loc.Language() + p
+	}
+
+	return p
+}
+
 func toStringSlice(args []interface{}) (argsString []string) {
 	argsSize := len(args)
 	if argsSize <= 0 {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
@@ -49,6 +50,15 @@ type Route struct {
 	RegisterFileName   string `json:"registerFileName"`
 	RegisterLineNumber int    `json:"registerLineNumber"`
 
+	// the relative path of the Party that this route was registered to, i.e "/api".
+	PartyPath string `json:"partyPath"`
+	// Metadata holds custom information attached to this route, see `SetMetadata`.
+	Metadata context.Map `json:"metadata,omitempty"`
+	// RequestType and ResponseType are the request payload and the response types
+	// of a dependency-injected route handler, if any, see `APIContainer.Handle` and `ExportRoutes`.
+	RequestType  reflect.Type `json:"-"`
+	ResponseType reflect.Type `json:"-"`
+
 	// StaticSites if not empty, refers to the system (or virtual if embedded) directory
 	// and sub directories that this "GET" route was registered to serve files and folders
 	// that contain index.html (a site). The index handler may registered by other
@@ -162,6 +172,32 @@ func (r *Route) SetSourceLine(fileName string, lineNumber int) *Route {
 	return r
 }
 
+// SetMetadata attaches a custom "key" "value" pair to this route,
+// it can be retrieved through `Metadata` or `GetCurrentRoute().GetMetadata()`.
+// Returns the `Route` itself.
+func (r *Route) SetMetadata(key string, value interface{}) *Route {
+	if r.Metadata == nil {
+		r.Metadata = make(context.Map)
+	}
+
+	r.Metadata[key] = value
+	return r
+}
+
+// HandlersNames returns the names of the route's handlers chain, by execution order.
+// Before the build state the begin (Use) and done (Done) handlers are included too.
+func (r *Route) HandlersNames() []string {
+	n := len(r.beginHandlers) + len(r.Handlers) + len(r.doneHandlers)
+	names := make([]string, 0, n)
+	for _, handlers := range []context.Handlers{r.beginHandlers, r.Handlers, r.doneHandlers} {
+		for _, h := range handlers {
+			names = append(names, context.HandlerName(h))
+		}
+	}
+
+	return names
+}
+
 // RestoreStatus will try to restore the status of this route instance, i.e if `SetStatusOffline` called on a "GET" route,
 // then this function will make this route available with "GET" HTTP Method.
 // Note if that you want to set status online for an offline registered route then you should call the `ChangeMethod` instead.
@@ -174,16 +210,24 @@ func (r *Route) RestoreStatus() bool {
 // BuildHandlers is executed automatically by the router handler
 // at the `Application#Build` state. Do not call it manually, unless
 // you were defined your own request mux handler.
+//
+// It flattens the begin (`Use`), the main and the done (`Done`) handlers
+// into a single, pre-merged, slice of their exact size, which is
+// not shared with any Party, so the request's dispatch does not copy or append handlers.
 func (r *Route) BuildHandlers() {
-	if len(r.beginHandlers) > 0 {
-		r.Handlers = append(r.beginHandlers, r.Handlers...)
-		r.beginHandlers = r.beginHandlers[0:0]
+	if len(r.beginHandlers) == 0 && len(r.doneHandlers) == 0 && len(r.Handlers) == cap(r.Handlers) {
+		return // already built.
 	}
 
-	if len(r.doneHandlers) > 0 {
-		r.Handlers = append(r.Handlers, r.doneHandlers...)
-		r.doneHandlers = r.doneHandlers[0:0]
-	} // note: no mutex needed, this should be called in-sync when server is not running of course.
+	handlers := make(context.Handlers, 0, len(r.beginHandlers)+len(r.Handlers)+len(r.doneHandlers))
+	handlers = append(handlers, r.beginHandlers...)
+	handlers = append(handlers, r.Handlers...)
+	handlers = append(handlers, r.doneHandlers...)
+
+	r.Handlers = handlers
+	r.beginHandlers = nil
+	r.doneHandlers = nil
+	// note: no mutex needed, this should be called in-sync when server is not running of course.
 }
 
 // String returns the form of METHOD, SUBDOMAIN, TMPL PATH.
@@ -538,3 +582,27 @@ func (rd routeReadOnlyWrapper) GetChangeFreq() string {
 func (rd routeReadOnlyWrapper) GetPriority() float32 {
 	return rd.Route.Priority
 }
+
+func (rd routeReadOnlyWrapper) SourceFileName() string {
+	return rd.Route.SourceFileName
+}
+
+func (rd routeReadOnlyWrapper) SourceLineNumber() int {
+	return rd.Route.SourceLineNumber
+}
+
+func (rd routeReadOnlyWrapper) RegisterFileName() string {
+	return rd.Route.RegisterFileName
+}
+
+func (rd routeReadOnlyWrapper) RegisterLineNumber() int {
+	return rd.Route.RegisterLineNumber
+}
+
+func (rd routeReadOnlyWrapper) PartyPath() string {
+	return rd.Route.PartyPath
+}
+
+func (rd routeReadOnlyWrapper) GetMetadata() context.Map {
+	return rd.Route.Metadata
+}
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/kataras/iris/v12/core/memstore"
+)
+
+const (
+	// routeCacheShards is the maximum number of the independently locked parts of the cache,
+	// so concurrent lookups of different paths do not wait for each other.
+	routeCacheShards = 16
+	// routeCacheEvictionSamples is the number of the entries compared to find
+	// the least recently used one, when a shard is full.
+	routeCacheEvictionSamples = 8
+)
+
+// routeCache is a bounded, approximately least recently used, cache of the resolved routes
+// and their path parameters by request method and path.
+// Its lookups take a read lock of one of its shards,
+// the least recently used entry is chosen from a sample of the shard's entries.
+// See `Configuration.RouteCacheSize`.
+type routeCache struct {
+	clock  uint64 // accessed atomically, advanced on each addition.
+	shards []*routeCacheShard
+}
+
+type routeCacheShard struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[routeCacheKey]*routeCacheEntry
+}
+
+type routeCacheKey struct {
+	method string
+	path   string
+}
+
+type routeCacheEntry struct {
+	used   uint64 // accessed atomically, the cache's clock of the last use.
+	node   *trieNode
+	params []memstore.Entry
+}
+
+func newRouteCache(size int) *routeCache {
+	n := routeCacheShards
+	if size < n {
+		n = size
+	}
+
+	c := &routeCache{shards: make([]*routeCacheShard, n)}
+	for i := range c.shards {
+		shardSize := size / n
+		if i < size%n {
+			shardSize++
+		}
+
+		c.shards[i] = &routeCacheShard{
+			size:    shardSize,
+			entries: make(map[routeCacheKey]*routeCacheEntry, shardSize),
+		}
+	}
+
+	return c
+}
+
+// shard returns the shard of the "method" and "path", by their FNV-1a hash.
+func (c *routeCache) shard(method, path string) *routeCacheShard {
+	h := uint32(2166136261)
+	for i := 0; i < len(method); i++ {
+		h = (h ^ uint32(method[i])) * 16777619
+	}
+	for i := 0; i < len(path); i++ {
+		h = (h ^ uint32(path[i])) * 16777619
+	}
+
+	return c.shards[h%uint32(len(c.shards))]
+}
+
+// get returns the cached route node of the "method" and "path"
+// and appends its path parameters to the "params".
+func (c *routeCache) get(method, path string, params *memstore.Store) *trieNode {
+	s := c.shard(method, path)
+	s.mu.RLock()
+	entry, ok := s.entries[routeCacheKey{method, path}]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	// avoid the write, and the cache line's invalidation, if it's already marked as used.
+	if now := atomic.LoadUint64(&c.clock); atomic.LoadUint64(&entry.used) != now {
+		atomic.StoreUint64(&entry.used, now)
+	}
+
+	// the entries are copied, the macro evaluator replaces them with their typed values.
+	*params = append(*params, entry.params...)
+	return entry.node
+}
+
+// add caches the resolved route node of the "method" and "path" and a copy of its "params".
+func (c *routeCache) add(method, path string, n *trieNode, params []memstore.Entry) {
+	entry := &routeCacheEntry{
+		used:   atomic.AddUint64(&c.clock, 1),
+		node:   n,
+		params: append([]memstore.Entry(nil), params...),
+	}
+	key := routeCacheKey{method, path}
+
+	s := c.shard(method, path)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= s.size {
+		s.evict()
+	}
+
+	s.entries[key] = entry
+}
+
+// evict removes the least recently used entry of a sample of the shard's entries,
+// the map's iteration order is random.
+func (s *routeCacheShard) evict() {
+	var (
+		oldestKey  routeCacheKey
+		oldestUsed uint64
+		sampled    int
+	)
+
+	for key, entry := range s.entries {
+		if used := atomic.LoadUint64(&entry.used); sampled == 0 || used < oldestUsed {
+			oldestKey, oldestUsed = key, used
+		}
+
+		if sampled++; sampled == routeCacheEvictionSamples {
+			break
+		}
+	}
+
+	delete(s.entries, oldestKey)
+}
@@ -100,6 +100,12 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 		return errors.New("router: context pool is nil")
 	}
 
+	if api, ok := routesProvider.(*APIBuilder); ok {
+		if err := api.routes.runBuildHooks(); err != nil {
+			return err
+		}
+	}
+
 	// build the handler using the routesProvider
 	if err := requestHandler.Build(routesProvider); err != nil {
 		return err
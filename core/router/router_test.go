@@ -1,12 +1,19 @@
 package router_test
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	stdhttptest "net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
 	"github.com/kataras/iris/v12/httptest"
 )
 
@@ -72,3 +79,363 @@ func TestLowercaseRouting(t *testing.T) {
 		e.GET(strings.ToUpper(tt)).Expect().Status(httptest.StatusOK).Body().Equal(s)
 	}
 }
+
+func testRouteIntrospectionMiddleware(ctx iris.Context) {
+	ctx.Next()
+}
+
+func TestRouteIntrospection(t *testing.T) {
+	app := iris.New()
+	api := app.Party("/api", testRouteIntrospectionMiddleware)
+	api.Get("/users", func(ctx iris.Context) {
+		r := ctx.GetCurrentRoute()
+		ctx.JSON(iris.Map{
+			"party":    r.PartyPath(),
+			"handlers": len(r.HandlersNames()),
+			"owner":    r.GetMetadata()["owner"],
+			"register": r.RegisterLineNumber() > 0,
+		})
+	}).SetMetadata("owner", "users-team")
+
+	route := app.GetRoute("GET/api/users")
+	names := route.HandlersNames()
+	if expected, got := 2, len(names); expected != got {
+		t.Fatalf("expected %d handlers but got %d: %v", expected, got, names)
+	}
+
+	if expected, got := "github.com/kataras/iris/v12/core/router_test.testRouteIntrospectionMiddleware", names[0]; expected != got {
+		t.Fatalf("expected first handler to be %q but got %q", expected, got)
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/api/users").Expect().Status(httptest.StatusOK).JSON().Equal(iris.Map{
+		"party":    "/api",
+		"handlers": 2,
+		"owner":    "users-team",
+		"register": true,
+	})
+}
+
+func TestRouteMetadataMiddleware(t *testing.T) {
+	auth := func(ctx iris.Context) {
+		if ctx.RouteMetadata()["auth"] == "none" {
+			ctx.Next()
+			return
+		}
+
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		}
+
+		ctx.Next()
+	}
+
+	h := func(ctx iris.Context) {
+		ctx.WriteString(ctx.Path())
+	}
+
+	app := iris.New()
+	app.Use(auth)
+	app.Get("/health", h).SetMetadata("auth", "none")
+	app.Get("/private", h)
+
+	e := httptest.New(t, app)
+	e.GET("/health").Expect().Status(httptest.StatusOK).Body().Equal("/health")
+	e.GET("/private").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/private").WithHeader("Authorization", "Bearer token").Expect().Status(httptest.StatusOK).Body().Equal("/private")
+}
+
+func TestAutoOptions(t *testing.T) {
+	cors := func(ctx iris.Context) {
+		ctx.Header("Access-Control-Allow-Origin", "*")
+		if ctx.Method() == iris.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.Header("Access-Control-Allow-Methods", ctx.GetHeader("Access-Control-Request-Method"))
+			ctx.StopWithStatus(iris.StatusOK)
+			return
+		}
+
+		ctx.Next()
+	}
+
+	h := func(ctx iris.Context) {
+		ctx.WriteString(ctx.Method())
+	}
+
+	app := iris.New()
+	api := app.Party("/api", cors).AutoOptions(true)
+	api.Get("/users", h)
+	api.Post("/users", h)
+	api.Options("/custom", h)
+	api.Get("/custom", h)
+	app.Get("/manual", h)
+
+	e := httptest.New(t, app)
+	e.OPTIONS("/api/users").Expect().Status(httptest.StatusNoContent).
+		Header("Allow").Equal("GET, POST, OPTIONS")
+	e.OPTIONS("/api/users").WithHeader("Origin", "https://example.com").
+		WithHeader("Access-Control-Request-Method", "POST").Expect().
+		Status(httptest.StatusOK).Header("Access-Control-Allow-Methods").Equal("POST")
+	e.OPTIONS("/api/custom").Expect().Status(httptest.StatusOK).Body().Equal("OPTIONS")
+	e.OPTIONS("/manual").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestAutoHead(t *testing.T) {
+	app := iris.New()
+	app.Get("/manual", func(ctx iris.Context) {})
+
+	users := app.Party("/users").AutoHead(true)
+	users.Get("/", func(ctx iris.Context) {
+		if ctx.URLParamExists("stream") {
+			for i := 0; i < 4; i++ {
+				ctx.Write(make([]byte, 1024))
+			}
+			return
+		}
+
+		ctx.Header("X-Total", "2")
+		ctx.WriteString("[1,2]")
+	})
+	users.Head("/{id:int}", func(ctx iris.Context) {
+		ctx.StatusCode(iris.StatusAccepted)
+	})
+	users.Get("/{id:int}", func(ctx iris.Context) {
+		ctx.WriteString("user")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("[1,2]")
+	resp := e.HEAD("/users").Expect().Status(httptest.StatusOK)
+	resp.Header("X-Total").Equal("2")
+	resp.Header("Content-Length").Equal("5")
+	resp.Body().Empty()
+
+	e.HEAD("/users/1").Expect().Status(httptest.StatusAccepted)
+	e.HEAD("/users").WithQuery("stream", true).Expect().Status(httptest.StatusOK).
+		Header("Content-Length").Equal("4096")
+	e.HEAD("/manual").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestMount(t *testing.T) {
+	admin := iris.New()
+	admin.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("admin index")
+	})
+	admin.Get("/users/{id:int}", func(ctx iris.Context) {
+		ctx.Writef("%s admin user %d at %s", ctx.Params().Get("lang"), ctx.Params().GetIntDefault("id", 0), ctx.Path())
+	})
+
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+	routes := app.Party("/{lang:string}").Mount("/admin", admin, func(ctx iris.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		}
+
+		ctx.Next()
+	})
+	if expected, got := 2, len(routes); expected != got {
+		t.Fatalf("expected %d mounted routes but got %d", expected, got)
+	}
+	if expected, got := "GET /{lang:string}/admin/users/{id:int}", routes[1].String(); expected != got {
+		t.Fatalf("expected mounted route %q but got %q", expected, got)
+	}
+
+	files := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.URL.Path, r.URL.EscapedPath())
+	})
+	app.Party("/{lang:string}").Mount("/files", files)
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("index")
+	e.GET("/en/admin/users/1").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/en/files").Expect().Status(httptest.StatusOK).Body().Equal("/ /")
+	// the escaped path is kept, httpexpect escapes the percent sign.
+	rec := stdhttptest.NewRecorder()
+	app.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodGet, "/en/files/a%2Fb/c", nil))
+	if expected, got := "/a/b/c /a%2Fb/c", rec.Body.String(); expected != got {
+		t.Fatalf("expected body %q but got %q", expected, got)
+	}
+
+	e = e.Builder(func(req *httptest.Request) {
+		req.WithHeader("Authorization", "Bearer token")
+	})
+	e.GET("/en/admin").Expect().Status(httptest.StatusOK).Body().Equal("admin index")
+	e.GET("/el/admin/users/1").Expect().Status(httptest.StatusOK).Body().Equal("el admin user 1 at /el/admin/users/1")
+	e.GET("/en/admin/users/one").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestRoutesDiff(t *testing.T) {
+	h := func(ctx iris.Context) {}
+
+	previous := iris.New()
+	previous.Get("/", h)
+	previous.Get("/users", h)
+	previous.Get("/old", h)
+
+	current := iris.New()
+	current.Get("/", h)
+	current.Get("/users", testRouteIntrospectionMiddleware, h)
+	current.Post("/users", h)
+
+	diff := current.RoutesDiff(previous)
+	if diff.Empty() {
+		t.Fatalf("expected differences")
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0].String() != "POST /users" {
+		t.Fatalf("unexpected added routes: %v", diff.Added)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].String() != "GET /old" {
+		t.Fatalf("unexpected removed routes: %v", diff.Removed)
+	}
+
+	if len(diff.Changed) != 1 || diff.Changed[0].String() != "GET /users" {
+		t.Fatalf("unexpected changed routes: %v", diff.Changed)
+	}
+
+	if diff = previous.RoutesDiff(previous); !diff.Empty() {
+		t.Fatalf("expected no differences but got: %#+v", diff)
+	}
+}
+
+func TestRouteSplit(t *testing.T) {
+	var recorded string
+
+	app := iris.New()
+	app.Use(func(ctx iris.Context) {
+		ctx.Next()
+		recorded = ctx.Values().GetString(router.VariantContextKey)
+	})
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("control")
+	}).Split(map[string]iris.Handler{
+		"blue": func(ctx iris.Context) { ctx.WriteString("blue") },
+	}, iris.SplitStrategy{
+		Weights: map[string]int{"blue": 50},
+		Key:     func(ctx iris.Context) string { return ctx.URLParam("user") },
+	})
+
+	e := httptest.New(t, app)
+
+	served := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		user := strconv.Itoa(i)
+		variant := e.GET("/").WithQuery("user", user).Expect().Status(httptest.StatusOK).Body().Raw()
+		if variant != recorded {
+			t.Fatalf("expected recorded variant: %s but got: %s", variant, recorded)
+		}
+
+		e.GET("/").WithQuery("user", user).Expect().Body().Equal(variant) // sticky.
+		served[variant]++
+	}
+
+	if served["blue"] < 70 || served[router.ControlVariant] < 70 {
+		t.Fatalf("expected an even distribution but got: %v", served)
+	}
+
+	// anonymous clients are identified by a cookie.
+	cookie := e.GET("/").Expect().Status(httptest.StatusOK).Cookie(router.DefaultSplitCookie).Value().Raw()
+	variant := e.GET("/").WithCookie(router.DefaultSplitCookie, cookie).Expect().Body().Raw()
+	for i := 0; i < 5; i++ {
+		e.GET("/").WithCookie(router.DefaultSplitCookie, cookie).Expect().Body().Equal(variant)
+	}
+}
+
+func TestFileServerMemoryCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-memory-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.js")
+	if err = ioutil.WriteFile(filename, []byte("console.log(1)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.HandleDir("/static", dir, iris.DirOptions{MemoryCache: 1 << 20})
+
+	e := httptest.New(t, app)
+	e.GET("/static/app.js").Expect().Status(httptest.StatusOK).Body().Equal("console.log(1)")
+	e.GET("/static/app.js").WithHeader("Range", "bytes=8-13").Expect().
+		Status(httptest.StatusPartialContent).Body().Equal("log(1)")
+
+	// modified files are read again.
+	tmp := filename + ".tmp"
+	if err = ioutil.WriteFile(tmp, []byte("console.log(2);"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Rename(tmp, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	e.GET("/static/app.js").Expect().Status(httptest.StatusOK).Body().Equal("console.log(2);")
+
+	// truncated in place.
+	if err = ioutil.WriteFile(filename, []byte("log(3)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/static/app.js").Expect().Status(httptest.StatusOK).Body().Equal("log(3)")
+}
+
+func TestRouteBuildHandlers(t *testing.T) {
+	app := iris.New()
+
+	writeName := func(name string) iris.Handler {
+		return func(ctx iris.Context) {
+			ctx.WriteString(name)
+			ctx.Next()
+		}
+	}
+
+	app.UseGlobal(writeName("global"))
+	app.DoneGlobal(writeName("done"))
+
+	users := app.Party("/users", writeName("party"))
+	r := users.Get("/", writeName("main"))
+	r.Use(writeName("begin"))
+
+	e := httptest.New(t, app)
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("globalbeginpartymaindone")
+
+	if expected, got := 5, len(r.Handlers); expected != got {
+		t.Fatalf("expected %d flattened handlers but got %d", expected, got)
+	}
+
+	if len(r.Handlers) != cap(r.Handlers) {
+		t.Fatalf("expected a handlers chain of its exact size but got capacity of: %d", cap(r.Handlers))
+	}
+}
+
+func TestRouteCache(t *testing.T) {
+	app := iris.New().Configure(iris.WithRouteCache(2))
+
+	app.Get("/users/{id:uint64}/{name}", func(ctx iris.Context) {
+		id, _ := ctx.Params().GetUint64("id")
+		ctx.Writef("%d:%s", id, ctx.Params().Get("name"))
+	})
+	status := app.Get("/status", func(ctx iris.Context) {
+		ctx.WriteString("ok")
+	})
+
+	e := httptest.New(t, app)
+	for i := 0; i < 3; i++ {
+		e.GET("/users/42/kataras").Expect().Status(httptest.StatusOK).Body().Equal("42:kataras")
+		e.GET("/users/7/makis").Expect().Status(httptest.StatusOK).Body().Equal("7:makis")
+		e.GET("/users/invalid/makis").Expect().Status(httptest.StatusNotFound)
+		e.GET("/status").Expect().Status(httptest.StatusOK).Body().Equal("ok")
+	}
+
+	// the cache is cleared on router's refresh.
+	status.SetStatusOffline()
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/status").Expect().Status(httptest.StatusNotFound)
+}
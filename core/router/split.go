@@ -0,0 +1,158 @@
+package router
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/kataras/iris/v12/context"
+
+	uuid "github.com/iris-contrib/go.uuid"
+)
+
+const (
+	// VariantContextKey is the context values key of the variant which served the request,
+	// see `Route.Split`. Add it to the `MessageContextKeys` of the logger middleware
+	// to record the variants in the access logs.
+	VariantContextKey = "iris.route.variant"
+	// ControlVariant is the name of the variant of a split route's original handler.
+	ControlVariant = "control"
+	// DefaultSplitCookie is the default name of the cookie which keeps
+	// the sticky identifier of the anonymous clients, see `SplitStrategy.Cookie`.
+	DefaultSplitCookie = "iris.split"
+)
+
+// SplitStrategy holds the options of the traffic's distribution of `Route.Split`.
+type SplitStrategy struct {
+	// Weights are the percentages (0-100) of the traffic for each variant by name,
+	// the rest is served by the original handler (the "control" variant).
+	// Defaults to an even distribution among the variants and the original handler.
+	Weights map[string]int
+	// Key, if not nil, returns the sticky key of a request, e.g. the user's ID,
+	// so a user is always served by the same variant.
+	// When it's nil or it returns empty, a random identifier is kept in the `Cookie`.
+	Key func(ctx context.Context) string
+	// Cookie is the name of the cookie which keeps the identifier of the anonymous clients.
+	// Defaults to `DefaultSplitCookie`.
+	Cookie string
+}
+
+type splitVariant struct {
+	name    string
+	handler context.Handler
+	upTo    int // exclusive bucket upper bound.
+}
+
+// Split routes a deterministic percentage of the route's traffic to alternative handlers,
+// for A/B testing and blue/green deployments. The "variants" replace the route's main handler,
+// the rest of its middleware and done handlers are executed as usual.
+// The selected variant's name (or "control" for the original handler) is stored
+// to the context values under the `VariantContextKey`, e.g.
+//  app.Get("/checkout", checkout).Split(map[string]iris.Handler{
+//      "new": newCheckout,
+//  }, router.SplitStrategy{Weights: map[string]int{"new": 10}})
+//
+// Should be called before the `Application#Build`. It panics on invalid weights.
+// Returns the `Route` itself.
+func (r *Route) Split(variants map[string]context.Handler, strategy SplitStrategy) *Route {
+	idx := -1
+	for i := len(r.Handlers) - 1; i >= 0; i-- {
+		if context.HandlerName(r.Handlers[i]) == r.MainHandlerName {
+			idx = i
+			break
+		}
+	}
+
+	if idx == -1 || len(variants) == 0 {
+		return r
+	}
+
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	weights := strategy.Weights
+	if weights == nil {
+		weights = make(map[string]int, len(names))
+		for _, name := range names {
+			weights[name] = 100 / (len(names) + 1)
+		}
+	}
+
+	list := make([]splitVariant, 0, len(names))
+	total := 0
+	for _, name := range names {
+		w := weights[name]
+		if w < 0 {
+			panic(fmt.Sprintf("route: %s: split: negative weight of variant %q", r.String(), name))
+		}
+
+		total += w
+		list = append(list, splitVariant{name: name, handler: variants[name], upTo: total})
+	}
+
+	for name := range weights {
+		if _, ok := variants[name]; !ok {
+			panic(fmt.Sprintf("route: %s: split: weight of unknown variant %q", r.String(), name))
+		}
+	}
+
+	if total > 100 {
+		panic(fmt.Sprintf("route: %s: split: weights sum %d exceeds 100", r.String(), total))
+	}
+
+	cookie := strategy.Cookie
+	if cookie == "" {
+		cookie = DefaultSplitCookie
+	}
+
+	control := r.Handlers[idx]
+	name := r.String()
+
+	r.Handlers[idx] = func(ctx context.Context) {
+		key := ""
+		if strategy.Key != nil {
+			key = strategy.Key(ctx)
+		}
+
+		if key == "" {
+			if key = ctx.GetCookie(cookie); key == "" {
+				id, err := uuid.NewV4()
+				if err != nil {
+					ctx.Values().Set(VariantContextKey, ControlVariant)
+					control(ctx)
+					return
+				}
+
+				key = id.String()
+				ctx.SetCookieKV(cookie, key)
+			}
+		}
+
+		b := splitBucket(name, key)
+		for _, v := range list {
+			if b < v.upTo {
+				ctx.Values().Set(VariantContextKey, v.name)
+				v.handler(ctx)
+				return
+			}
+		}
+
+		ctx.Values().Set(VariantContextKey, ControlVariant)
+		control(ctx)
+	}
+
+	return r
+}
+
+// splitBucket returns the bucket (0-99) of the "key" for the route of the "name",
+// each route has different buckets so the same clients are not always served by the variants.
+func splitBucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,162 @@
+package router
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// statsSamples is the number of the latest latencies kept per route
+// to calculate the percentiles.
+const statsSamples = 1024
+
+// RouteStats contains the statistics of a single route, see `Stats`.
+type RouteStats struct {
+	// Route is the route's string representation, i.e "GET /users/{id:int}".
+	Route string `json:"route"`
+	Name  string `json:"name"`
+	// Count is the number of the served requests.
+	Count uint64 `json:"count"`
+	// Errors is the number of the requests that responded with a 5xx status code
+	// or stored an error through `Context.SetErr`.
+	Errors uint64 `json:"errors"`
+	// ErrorRate is the Errors/Count ratio.
+	ErrorRate float64 `json:"errorRate"`
+	// The latency percentiles of the latest requests.
+	P50 time.Duration `json:"p50"`
+	P95 time.Duration `json:"p95"`
+	P99 time.Duration `json:"p99"`
+	// LastError is the time of the latest error, zero if no error occurred.
+	LastError time.Time `json:"lastError"`
+}
+
+// Stats is a lightweight, in-memory, per-route statistics collector.
+// It's independent of any metrics system and it's meant for quick in-process diagnostics.
+type Stats struct {
+	mu     sync.RWMutex
+	routes []*routeStats
+	known  map[*Route]struct{}
+}
+
+type routeStats struct {
+	route *Route
+
+	mu        sync.Mutex
+	count     uint64
+	errors    uint64
+	lastError time.Time
+	latencies []time.Duration // ring buffer.
+	next      int
+}
+
+// NewStats returns a new, empty, routes statistics collector.
+// Use its `Attach` to collect the statistics of a set of routes.
+func NewStats() *Stats {
+	return &Stats{known: make(map[*Route]struct{})}
+}
+
+// Attach prepends the statistics handler to the given "routes",
+// routes that are already attached are skipped.
+// It should be called before the router is built, see `APIBuilder.OnBuild`.
+func (s *Stats) Attach(routes []*Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range routes {
+		if _, ok := s.known[r]; ok {
+			continue
+		}
+		s.known[r] = struct{}{}
+
+		rs := &routeStats{route: r}
+		s.routes = append(s.routes, rs)
+		r.Use(rs.handler)
+	}
+}
+
+func (rs *routeStats) handler(ctx context.Context) {
+	start := time.Now()
+	ctx.Next()
+	latency := time.Since(start)
+
+	failed := ctx.GetStatusCode() >= 500 || ctx.GetErr() != nil
+
+	rs.mu.Lock()
+	rs.count++
+	if failed {
+		rs.errors++
+		rs.lastError = time.Now()
+	}
+
+	if len(rs.latencies) < statsSamples {
+		rs.latencies = append(rs.latencies, latency)
+	} else {
+		rs.latencies[rs.next] = latency
+	}
+	rs.next = (rs.next + 1) % statsSamples
+	rs.mu.Unlock()
+}
+
+// Get returns a snapshot of the statistics of the served routes,
+// sorted by the number of requests, the busiest first.
+func (s *Stats) Get() []RouteStats {
+	s.mu.RLock()
+	routes := s.routes
+	s.mu.RUnlock()
+
+	list := make([]RouteStats, 0, len(routes))
+	for _, rs := range routes {
+		rs.mu.Lock()
+		if rs.count == 0 {
+			rs.mu.Unlock()
+			continue
+		}
+
+		st := RouteStats{
+			Route:     rs.route.String(),
+			Name:      rs.route.Name,
+			Count:     rs.count,
+			Errors:    rs.errors,
+			ErrorRate: float64(rs.errors) / float64(rs.count),
+			LastError: rs.lastError,
+		}
+		latencies := append([]time.Duration(nil), rs.latencies...)
+		rs.mu.Unlock()
+
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		st.P50 = percentile(latencies, 50)
+		st.P95 = percentile(latencies, 95)
+		st.P99 = percentile(latencies, 99)
+
+		list = append(list, st)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Count > list[j].Count
+	})
+
+	return list
+}
+
+// Handler returns a handler which writes the statistics as JSON.
+func (s *Stats) Handler() context.Handler {
+	return func(ctx context.Context) {
+		ctx.JSON(s.Get())
+	}
+}
+
+// percentile returns the nearest-rank "p" percentile of the sorted "values".
+func percentile(values []time.Duration, p int) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+
+	idx := (p*len(values)+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	return values[idx]
+}
@@ -0,0 +1,51 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestRouteStats(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithRouteStats)
+	app.Get("/users/{id:int}", func(ctx iris.Context) {
+		if ctx.Params().GetIntDefault("id", 0) == 0 {
+			ctx.StatusCode(iris.StatusInternalServerError)
+		}
+	})
+	app.Get("/idle", func(ctx iris.Context) {})
+	app.Get("/stats", app.StatsHandler())
+
+	e := httptest.New(t, app)
+	for _, id := range []string{"1", "2", "3", "0"} {
+		e.GET("/users/" + id).Expect()
+	}
+
+	stats := app.Stats()
+	if expected, got := 1, len(stats); expected != got { // the idle and stats routes are not served yet.
+		t.Fatalf("expected %d route stats but got %d: %#+v", expected, got, stats)
+	}
+
+	st := stats[0]
+	if expected, got := "GET /users/{id:int}", st.Route; expected != got {
+		t.Fatalf("expected route %q but got %q", expected, got)
+	}
+
+	if st.Count != 4 || st.Errors != 1 || st.ErrorRate != 0.25 {
+		t.Fatalf("unexpected count, errors or error rate: %#+v", st)
+	}
+
+	if st.P50 <= 0 || st.P99 < st.P50 || st.LastError.IsZero() {
+		t.Fatalf("unexpected latencies or last error time: %#+v", st)
+	}
+
+	arr := e.GET("/stats").Expect().Status(httptest.StatusOK).JSON().Array()
+	arr.Length().Equal(1)
+	arr.Element(0).Object().ValueEqual("count", 4).ValueEqual("errors", 1)
+
+	if stats = iris.New().Stats(); stats != nil {
+		t.Fatalf("expected nil stats when disabled")
+	}
+}
@@ -98,6 +98,11 @@ func defaultErrorCodeHandlers() *ErrorCodeHandlers {
 
 func statusText(statusCode int) context.Handler {
 	return func(ctx context.Context) {
+		if statusCode >= http.StatusInternalServerError && ctx.Application().ConfigurationReadOnly().GetEnableDebugErrorPages() {
+			renderDebugError(ctx, statusCode)
+			return
+		}
+
 		ctx.WriteString(http.StatusText(statusCode))
 	}
 }
@@ -0,0 +1,197 @@
+package router
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/view"
+)
+
+// debugErrorMaxBody is the maximum request body's length shown on the developer error page.
+const debugErrorMaxBody = 64 << 10
+
+type (
+	debugErrorPage struct {
+		StatusCode int
+		StatusText string
+		Method     string
+		Path       string
+		Route      string
+		Error      string
+		Handler    string
+		Frames     []debugErrorFrame
+		Headers    []debugErrorPair
+		Body       string
+		Session    []debugErrorPair
+		Template   *debugErrorTemplate
+	}
+
+	debugErrorFrame struct {
+		Location string
+		App      bool // false for runtime and framework frames.
+	}
+
+	debugErrorPair struct {
+		Key   string
+		Value string
+	}
+
+	debugErrorTemplate struct {
+		Name  string
+		Lines []debugErrorTemplateLine
+	}
+
+	debugErrorTemplateLine struct {
+		Number  int
+		Text    string
+		Failing bool
+	}
+)
+
+// renderDebugError writes the developer error page of the current request,
+// see the `Configuration.EnableDebugErrorPages` field.
+func renderDebugError(ctx context.Context, statusCode int) {
+	page := debugErrorPage{
+		StatusCode: statusCode,
+		StatusText: http.StatusText(statusCode),
+		Method:     ctx.Method(),
+		Path:       ctx.Request().URL.RequestURI(),
+	}
+
+	if r := ctx.GetCurrentRoute(); r != nil {
+		page.Route = r.String()
+	}
+
+	if err := ctx.GetErr(); err != nil {
+		page.Error = err.Error()
+
+		var panicErr *context.ErrPanicRecovery
+		if errors.As(err, &panicErr) {
+			page.Handler = panicErr.HandlerName
+			page.Frames = debugErrorFrames(panicErr.Callers)
+		}
+
+		var tmplErr *view.TemplateError
+		if errors.As(err, &tmplErr) {
+			page.Template = debugErrorTemplateSource(tmplErr)
+		}
+	}
+
+	for key, values := range ctx.Request().Header {
+		page.Headers = append(page.Headers, debugErrorPair{key, strings.Join(values, ", ")})
+	}
+	sortDebugErrorPairs(page.Headers)
+
+	if body, err := ctx.GetBody(); err == nil && len(body) > 0 {
+		if len(body) > debugErrorMaxBody {
+			body = append(body[:debugErrorMaxBody:debugErrorMaxBody], "..."...)
+		}
+		page.Body = string(body)
+	}
+
+	// the sessions package stores the *Session under this key, see `sessions.Get`.
+	if sess, ok := ctx.Values().Get("iris.session").(interface {
+		GetAll() map[string]interface{}
+	}); ok {
+		for key, value := range sess.GetAll() {
+			page.Session = append(page.Session, debugErrorPair{key, fmt.Sprintf("%v", value)})
+		}
+		sortDebugErrorPairs(page.Session)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := debugErrorTmpl.Execute(buf, page); err != nil {
+		ctx.Application().Log().Error("debug error page: render failed", "error", err)
+		ctx.WriteString(http.StatusText(statusCode))
+		return
+	}
+
+	ctx.ContentType(context.ContentHTMLHeaderValue)
+	ctx.Write(buf.Bytes())
+}
+
+func debugErrorFrames(callers []string) []debugErrorFrame {
+	goroot := runtime.GOROOT()
+	frames := make([]debugErrorFrame, 0, len(callers))
+	for _, caller := range callers {
+		app := !strings.Contains(caller, "github.com/kataras/iris/v12") &&
+			(goroot == "" || !strings.HasPrefix(caller, goroot))
+		frames = append(frames, debugErrorFrame{Location: caller, App: app})
+	}
+
+	return frames
+}
+
+func debugErrorTemplateSource(err *view.TemplateError) *debugErrorTemplate {
+	// show a few lines around the failing one.
+	const around = 5
+
+	lines := strings.Split(string(err.Source), "\n")
+	start, end := err.Line-around, err.Line+around
+	if start < 1 {
+		start = 1
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	tmpl := &debugErrorTemplate{Name: err.Name}
+	for n := start; n <= end; n++ {
+		tmpl.Lines = append(tmpl.Lines, debugErrorTemplateLine{
+			Number:  n,
+			Text:    strings.TrimRight(lines[n-1], "\r"),
+			Failing: n == err.Line,
+		})
+	}
+
+	return tmpl
+}
+
+func sortDebugErrorPairs(pairs []debugErrorPair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key < pairs[j].Key
+	})
+}
+
+var debugErrorTmpl = template.Must(template.New("debug_error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.StatusCode}} {{.StatusText}}</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;margin:0;color:#222;background:#f6f7f9}
+header{background:#b3261e;color:#fff;padding:24px 32px}
+header h1{margin:0;font-size:22px}
+header p{margin:8px 0 0;font-family:monospace;font-size:14px}
+section{margin:24px 32px;background:#fff;border:1px solid #e1e4e8;border-radius:4px}
+section h2{margin:0;padding:12px 16px;font-size:15px;border-bottom:1px solid #e1e4e8}
+pre,table{margin:0;padding:12px 16px;font-family:monospace;font-size:13px;white-space:pre-wrap;word-break:break-all}
+table{border-collapse:collapse;width:100%}
+td{padding:2px 8px;vertical-align:top}
+td.key{font-weight:bold;white-space:nowrap;width:1%}
+.frame{color:#888}
+.frame.app{color:#222;font-weight:bold;background:#fff5d6}
+.failing{background:#ffdce0;font-weight:bold}
+</style>
+</head>
+<body>
+<header>
+<h1>{{.StatusCode}} {{.StatusText}}</h1>
+<p>{{.Method}} {{.Path}}{{if .Route}} &mdash; route: {{.Route}}{{end}}</p>
+</header>
+{{if .Error}}<section><h2>Error{{if .Handler}} in {{.Handler}}{{end}}</h2><pre>{{.Error}}</pre></section>{{end}}
+{{if .Template}}<section><h2>Template: {{.Template.Name}}</h2><table>{{range .Template.Lines}}<tr{{if .Failing}} class="failing"{{end}}><td class="key">{{.Number}}</td><td>{{.Text}}</td></tr>{{end}}</table></section>{{end}}
+{{if .Frames}}<section><h2>Stack</h2><pre>{{range .Frames}}<div class="frame{{if .App}} app{{end}}">{{.Location}}</div>{{end}}</pre></section>{{end}}
+<section><h2>Request Headers</h2><table>{{range .Headers}}<tr><td class="key">{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table></section>
+{{if .Body}}<section><h2>Request Body</h2><pre>{{.Body}}</pre></section>{{end}}
+{{if .Session}}<section><h2>Session</h2><table>{{range .Session}}<tr><td class="key">{{.Key}}</td><td>{{.Value}}</td></tr>{{end}}</table></section>{{end}}
+</body>
+</html>
+`))
@@ -3,11 +3,17 @@ package router_test
 
 import (
 	"bytes"
+	"errors"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/recover"
 
 	"github.com/kataras/iris/v12/httptest"
 )
@@ -70,3 +76,97 @@ func checkAndClearBuf(t *testing.T, buff *bytes.Buffer, expected string) {
 
 	buff.Reset()
 }
+
+func TestDebugErrorPages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "debug-error-pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>\n{{.Title}}\n{{.Missing}}\n</h1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newApp := func(debug bool) *iris.Application {
+		app := iris.New()
+		if debug {
+			app.Configure(iris.WithDebugErrorPages)
+		}
+		app.RegisterView(iris.HTML(dir, ".html"))
+		app.Use(recover.New())
+
+		app.Get("/panic", func(ctx iris.Context) {
+			panic("something bad")
+		})
+		app.Get("/view", func(ctx iris.Context) {
+			ctx.View("index.html", struct{ Title string }{"title"})
+		})
+		app.Post("/error", func(ctx iris.Context) {
+			ctx.SetErr(errors.New("custom error"))
+			ctx.StatusCode(iris.StatusServiceUnavailable)
+		})
+		return app
+	}
+
+	e := httptest.New(t, newApp(false))
+	e.GET("/panic").Expect().Status(iris.StatusInternalServerError).Body().Equal("Internal Server Error")
+
+	e = httptest.New(t, newApp(true))
+	body := e.GET("/panic").Expect().Status(iris.StatusInternalServerError).
+		ContentType("text/html", "utf-8").Body()
+	body.Contains("recovered from panic").Contains("something bad").Contains("status_test.go")
+
+	e.GET("/view").Expect().Status(iris.StatusInternalServerError).Body().
+		Contains("Template: index.html").Contains(`<tr class="failing"><td class="key">3</td><td>{{.Missing}}</td></tr>`).NotContains("<h1>\ntitle")
+
+	e.POST("/error").WithHeader("X-Debug", "value").WithText("request body").Expect().
+		Status(iris.StatusServiceUnavailable).Body().
+		Contains("503 Service Unavailable").Contains("custom error").
+		Contains(`<td class="key">X-Debug</td><td>value</td>`).Contains("request body")
+}
+
+func TestViewBufferSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "view-buffer-size")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("{{range .Items}}{{.}}{{end}}{{if .Fail}}{{fail}}{{end}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	type viewModel struct {
+		Items []string
+		Fail  bool
+	}
+
+	items := make([]string, 100)
+	for i := range items {
+		items[i] = "0123456789"
+	}
+	page := strings.Repeat("0123456789", 100)
+
+	app := iris.New().Configure(iris.WithConfiguration(iris.Configuration{ViewBufferSize: 64}))
+	engine := iris.HTML(dir, ".html")
+	engine.AddFunc("fail", func() (string, error) { return "", errors.New("render failure") })
+	app.RegisterView(engine)
+
+	app.Get("/", func(ctx iris.Context) {
+		ctx.View("index.html", viewModel{Items: items})
+	})
+	app.Get("/early-error", func(ctx iris.Context) {
+		ctx.View("index.html", viewModel{Items: items[:1], Fail: true})
+	})
+	app.Get("/late-error", func(ctx iris.Context) {
+		ctx.View("index.html", viewModel{Items: items, Fail: true})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK).Body().Equal(page)
+	// the error occurred before the buffer's limit, the status code can still change.
+	e.GET("/early-error").Expect().Status(iris.StatusInternalServerError).Body().NotContains("0123456789")
+	// the output is already streamed, the client receives the partial page.
+	e.GET("/late-error").Expect().Status(iris.StatusOK).Body().Equal(page)
+}
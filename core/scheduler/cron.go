@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes the activation times of a job.
+type Schedule interface {
+	// Next returns the next activation time after "t",
+	// or the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule which activates every "d" duration,
+// its activations are aligned to the multiples of "d" since the zero time,
+// so all the instances of an application share the same activation times.
+func Every(d time.Duration) Schedule {
+	if d < time.Second {
+		d = time.Second
+	}
+
+	return every(d)
+}
+
+type every time.Duration
+
+func (d every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(d)).Add(time.Duration(d))
+}
+
+// cron is a parsed cron expression,
+// each field is a bitset of its allowed values.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar report whether the day fields were "*",
+	// if both are restricted a day matches any of them.
+	domStar, dowStar bool
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses a standard cron expression of five fields:
+// minute (0-59), hour (0-23), day of month (1-31), month (1-12 or JAN-DEC)
+// and day of week (0-7 or SUN-SAT, both 0 and 7 are Sunday).
+// Each field accepts "*", values, ranges ("1-5"), steps ("*/15", "0-30/10") and lists ("1,15").
+//
+// The descriptors "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"
+// and "@every <duration>" (e.g. "@every 1h30m") are supported too.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(spec[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: %q: %w", spec, err)
+		}
+
+		return Every(d), nil
+	}
+
+	if expr, ok := descriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("scheduler: %q: expected 5 fields but got %d", spec, len(fields))
+	}
+
+	c := new(cron)
+	var err error
+	if c.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("scheduler: %q: minute: %w", spec, err)
+	}
+	if c.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("scheduler: %q: hour: %w", spec, err)
+	}
+	if c.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("scheduler: %q: day of month: %w", spec, err)
+	}
+	if c.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("scheduler: %q: month: %w", spec, err)
+	}
+	if c.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("scheduler: %q: day of week: %w", spec, err)
+	}
+
+	if c.dow&(1<<7) != 0 { // 7 is Sunday too.
+		c.dow |= 1
+	}
+
+	c.domStar = fields[2] == "*" || fields[2] == "?"
+	c.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return c, nil
+}
+
+func parseField(field string, b bounds) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		start, end, step := b.min, b.max, 1
+
+		rangePart := part
+		if idx := strings.IndexByte(part, '/'); idx != -1 {
+			if step, err = strconv.Atoi(part[idx+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			rangePart = part[:idx]
+		}
+
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.IndexByte(rangePart, '-') > 0:
+			idx := strings.IndexByte(rangePart, '-')
+			if start, err = parseValue(rangePart[:idx], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(rangePart[idx+1:], b); err != nil {
+				return 0, err
+			}
+		default:
+			if start, err = parseValue(rangePart, b); err != nil {
+				return 0, err
+			}
+			if strings.IndexByte(part, '/') == -1 {
+				end = start
+			}
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %q out of range [%d-%d]", s, b.min, b.max)
+	}
+
+	return v, nil
+}
+
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *cron) matchDay(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,224 @@
+// Package scheduler runs background workers and cron jobs
+// which are stopped on the Application's shutdown.
+// See the `Application.Go` and `Application.Schedule` methods.
+package scheduler
+
+import (
+	stdContext "context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// Locker is the distributed lock of the scheduled jobs,
+// so only one instance of a horizontally scaled application runs a job's activation.
+// Each activation has its own key, the job's name and its activation time.
+//
+// A Redis implementation can use the `SET key value NX PX ttl` command.
+type Locker interface {
+	// TryLock acquires the "key" for "ttl" duration.
+	// It reports false if the "key" is already acquired.
+	TryLock(ctx stdContext.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// Options holds the optional settings of a scheduled job.
+type Options struct {
+	// Name is the job's name, it is used on logs and on the `Locker`'s keys.
+	// Defaults to the job function's name.
+	Name string
+	// Locker, if not nil, is used to run each activation
+	// of the job on a single instance of the application.
+	// Defaults to nil.
+	Locker Locker
+	// Location is the time zone of the cron expression.
+	// Defaults to time.Local.
+	Location *time.Location
+}
+
+// Scheduler runs the background workers and the scheduled jobs,
+// their context is cancelled on `Shutdown`.
+type Scheduler struct {
+	logger *golog.Logger
+
+	ctx    stdContext.Context
+	cancel stdContext.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a new Scheduler which logs the recovered panics and
+// the locker's errors to the "logger".
+func New(logger *golog.Logger) *Scheduler {
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	return &Scheduler{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs the "worker" on a new goroutine.
+// The "worker" should return when its context is cancelled.
+// Panics are recovered and logged.
+func (s *Scheduler) Go(worker func(ctx stdContext.Context)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run("worker", funcName(worker), worker)
+	}()
+}
+
+// Schedule runs the "job" on each activation of the "spec" cron expression, see `Parse`.
+// A job's activation is skipped while its previous one is still running.
+// Panics are recovered and logged.
+func (s *Scheduler) Schedule(spec string, job func(ctx stdContext.Context), opts ...Options) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.Name == "" {
+		options.Name = funcName(job)
+	}
+
+	if options.Location == nil {
+		options.Location = time.Local
+	}
+
+	s.schedule(schedule, job, options)
+	return nil
+}
+
+func (s *Scheduler) schedule(schedule Schedule, job func(ctx stdContext.Context), options Options) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.loop(schedule, job, options)
+	}()
+}
+
+func (s *Scheduler) loop(schedule Schedule, job func(ctx stdContext.Context), options Options) {
+	for {
+		now := time.Now().In(options.Location)
+		next := schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if options.Locker != nil {
+			key := options.Name + ":" + strconv.FormatInt(next.Unix(), 10)
+			ttl := schedule.Next(next).Sub(next)
+			if ttl <= 0 { // last activation.
+				ttl = time.Minute
+			}
+			acquired, err := options.Locker.TryLock(s.ctx, key, ttl)
+			if err != nil {
+				s.logger.Errorf("Scheduler: job('%s'): lock: %v", options.Name, err)
+				continue
+			}
+
+			if !acquired {
+				continue
+			}
+		}
+
+		s.run("job", options.Name, job)
+	}
+}
+
+// run calls the "fn" and recovers from its panics.
+func (s *Scheduler) run(kind, name string, fn func(ctx stdContext.Context)) {
+	defer func() {
+		if err := recover(); err != nil {
+			var stacktrace string
+			for i := 1; ; i++ {
+				_, f, l, got := runtime.Caller(i)
+				if !got {
+					break
+				}
+
+				stacktrace += fmt.Sprintf("%s:%d\n", f, l)
+			}
+
+			logMessage := fmt.Sprintf("Recovered from a background %s('%s')\n", kind, name)
+			logMessage += fmt.Sprintf("Trace: %s\n", err)
+			logMessage += fmt.Sprintf("\n%s", stacktrace)
+			s.logger.Warn(logMessage)
+		}
+	}()
+
+	fn(s.ctx)
+}
+
+// Shutdown cancels the context of the workers and jobs
+// and waits for them to return or "ctx" to be done.
+func (s *Scheduler) Shutdown(ctx stdContext.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func funcName(fn interface{}) string {
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+}
+
+// MemoryLocker is a `Locker` for a single instance of the application, useful for tests.
+type MemoryLocker struct {
+	mu   sync.Mutex
+	keys map[string]time.Time // key: expiration.
+}
+
+var _ Locker = (*MemoryLocker)(nil)
+
+// NewMemoryLocker returns a new in-memory Locker.
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{keys: make(map[string]time.Time)}
+}
+
+// TryLock acquires the "key" for "ttl" duration.
+func (l *MemoryLocker) TryLock(_ stdContext.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := l.keys[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	for k, expiresAt := range l.keys {
+		if !now.Before(expiresAt) {
+			delete(l.keys, k)
+		}
+	}
+
+	l.keys[key] = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	stdContext "context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+func TestParse(t *testing.T) {
+	from := time.Date(2020, time.May, 15, 10, 30, 20, 0, time.UTC) // Friday.
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2020, time.May, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, time.May, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2020, time.May, 16, 3, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2020, time.May, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2020, time.May, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 7", time.Date(2020, time.May, 17, 0, 0, 0, 0, time.UTC)}, // day of month or Sunday.
+		{"@monthly", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2020, time.May, 15, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2020, time.May, 15, 10, 31, 30, 0, time.UTC)},
+	}
+
+	for i, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("[%d] %s: %v", i, tt.spec, err)
+		}
+
+		if got := schedule.Next(from); !got.Equal(tt.expected) {
+			t.Fatalf("[%d] %s: expected next activation: %s but got: %s", i, tt.spec, tt.expected, got)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "@every x"} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("expected error for spec: %q", spec)
+		}
+	}
+}
+
+func TestSchedulerShutdown(t *testing.T) {
+	var logs strings.Builder
+	logger := golog.New().SetOutput(&logs)
+	s := New(logger)
+
+	var stopped int32
+	s.Go(func(ctx stdContext.Context) {
+		<-ctx.Done()
+		atomic.StoreInt32(&stopped, 1)
+	})
+	s.Go(func(ctx stdContext.Context) {
+		panic("worker failure")
+	})
+
+	if err := s.Schedule("@every 1s", func(ctx stdContext.Context) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Schedule("invalid", func(ctx stdContext.Context) {}); err == nil {
+		t.Fatalf("expected an error on invalid spec")
+	}
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if atomic.LoadInt32(&stopped) != 1 {
+		t.Fatalf("expected worker to be stopped")
+	}
+
+	if !strings.Contains(logs.String(), "Recovered from a background worker") || !strings.Contains(logs.String(), "worker failure") {
+		t.Fatalf("expected the panic to be logged but got: %s", logs.String())
+	}
+}
+
+type onceSchedule time.Time
+
+func (at onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(time.Time(at)) {
+		return time.Time(at)
+	}
+
+	return time.Time{}
+}
+
+func TestSchedulerLocker(t *testing.T) {
+	locker := NewMemoryLocker()
+	activation := onceSchedule(time.Now().Add(100 * time.Millisecond))
+
+	var runs int32
+	job := func(ctx stdContext.Context) {
+		atomic.AddInt32(&runs, 1)
+	}
+
+	// two instances of the same job.
+	instances := []*Scheduler{New(golog.New()), New(golog.New())}
+	for _, s := range instances {
+		s.schedule(activation, job, Options{Name: "job", Locker: locker, Location: time.Local})
+	}
+
+	for _, s := range instances {
+		s.wg.Wait() // no more activations.
+	}
+
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		t.Fatalf("expected a single run of the activation but got: %d", got)
+	}
+}
@@ -0,0 +1,19 @@
+// +build go1.16
+
+package iris
+
+import "github.com/kataras/iris/v12/core/router"
+
+var (
+	// EmbeddedDir returns the `DirOptions` which serve the files of an `embed.FS` (or any `fs.FS`)
+	// through the `Party#HandleDir`, or the system directory when its "disk" argument is true, i.e
+	//  app.HandleDir("/static", "./assets", iris.EmbeddedDir(assets, *dev))
+	//
+	// A shortcut for the `router.EmbeddedDir`.
+	EmbeddedDir = router.EmbeddedDir
+	// LoadAssetManifest reads the fingerprints of the assets,
+	// generated by the "iris-embed" command, of a directory of an `fs.FS`.
+	//
+	// A shortcut for the `router.LoadAssetManifest`.
+	LoadAssetManifest = router.LoadAssetManifest
+)
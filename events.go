@@ -0,0 +1,124 @@
+package iris
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// BuildEvent is passed to the `Application.OnBuildFinished` listeners.
+type BuildEvent struct {
+	// Routes is the number of the registered routes.
+	Routes int
+	// Duration is the time the build took.
+	Duration time.Duration
+	// Err is the build error, if any.
+	Err error
+}
+
+type slowRequestListener struct {
+	threshold time.Duration
+	fn        func(ctx Context, latency time.Duration)
+}
+
+// events holds the application event listeners,
+// see `OnBuildFinished`, `OnConfigurationApplied` and `OnRequestSlow`.
+type events struct {
+	mu            sync.RWMutex
+	buildFinished []func(BuildEvent)
+	configured    []func(Configuration)
+	slowRequest   []slowRequestListener
+}
+
+// OnBuildFinished registers a function which is called once the application is built,
+// on `Build`, `Listen` and `Run`, with the build's details and its error, if any.
+//
+// See `OnRouteRegistered` for route registration events too.
+func (app *Application) OnBuildFinished(fn func(BuildEvent)) {
+	if fn == nil {
+		return
+	}
+
+	app.events.mu.Lock()
+	app.events.buildFinished = append(app.events.buildFinished, fn)
+	app.events.mu.Unlock()
+}
+
+// OnConfigurationApplied registers a function which is called with a copy of the
+// current configuration after each `Configure` call.
+func (app *Application) OnConfigurationApplied(fn func(Configuration)) {
+	if fn == nil {
+		return
+	}
+
+	app.events.mu.Lock()
+	app.events.configured = append(app.events.configured, fn)
+	app.events.mu.Unlock()
+}
+
+// OnRequestSlow registers a function which is called after the handlers of a request
+// which took longer than the "threshold" to be served, i.e
+//  app.OnRequestSlow(time.Second, func(ctx iris.Context, latency time.Duration) {
+//      ctx.Application().Log().Warn("slow request", "path", ctx.Path(), "latency", latency)
+//  })
+// The listeners must be registered before `Build`.
+// The function is called from the request's goroutine, it should not block.
+func (app *Application) OnRequestSlow(threshold time.Duration, fn func(ctx Context, latency time.Duration)) {
+	if fn == nil || threshold <= 0 {
+		return
+	}
+
+	app.events.mu.Lock()
+	app.events.slowRequest = append(app.events.slowRequest, slowRequestListener{threshold: threshold, fn: fn})
+	app.events.mu.Unlock()
+}
+
+func (app *Application) fireBuildFinished(evt BuildEvent) {
+	app.events.mu.RLock()
+	listeners := app.events.buildFinished
+	app.events.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(evt)
+	}
+}
+
+func (app *Application) fireConfigurationApplied() {
+	app.events.mu.RLock()
+	listeners := app.events.configured
+	app.events.mu.RUnlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+
+	c := *app.config
+	for _, fn := range listeners {
+		fn(c)
+	}
+}
+
+// slowRequestHandler returns the global handler which measures the requests,
+// it returns nil if no `OnRequestSlow` listeners were registered.
+func (app *Application) slowRequestHandler() context.Handler {
+	app.events.mu.RLock()
+	listeners := app.events.slowRequest
+	app.events.mu.RUnlock()
+
+	if len(listeners) == 0 {
+		return nil
+	}
+
+	return func(ctx Context) {
+		start := time.Now()
+		ctx.Next()
+		latency := time.Since(start)
+
+		for _, l := range listeners {
+			if latency >= l.threshold {
+				l.fn(ctx, latency)
+			}
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package events implements an in-process, typed publish-subscribe event bus
+// which can be bridged to external brokers (see the events/redis and events/nats packages)
+// to deliver the events to the other instances of a horizontally scaled application.
+//
+// Example Code:
+//  bus := events.New(events.Options{Container: app.ConfigureContainer().Container})
+//  bus.On(func(e UserRegistered, mailer *Mailer) error {
+//      return mailer.SendWelcome(e.Email)
+//  })
+//
+//  app.Post("/users", func(ctx iris.Context) {
+//      // [...]
+//      bus.Emit(ctx, UserRegistered{Email: email})
+//  })
+package events
+
+import (
+	stdContext "context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+
+	"github.com/kataras/golog"
+)
+
+// Topic is the optional interface of an event to customize its topic name,
+// the name it is published to the bridged brokers.
+// Defaults to the event's Go type name.
+type Topic interface {
+	Topic() string
+}
+
+// Broker is an external message broker, e.g. Redis or NATS,
+// see the `Bus.Bridge` method.
+type Broker interface {
+	// Publish sends the "payload" to the subscribers of the "topic".
+	Publish(topic string, payload []byte) error
+	// Subscribe registers the "handler" to the "topic".
+	Subscribe(topic string, handler func(payload []byte)) error
+}
+
+// Options holds the optional settings of an event `Bus`.
+type Options struct {
+	// Container is the dependency injection container which resolves the handlers' input arguments.
+	// Pass the `app.ConfigureContainer().Container` to share the application's dependencies.
+	// Defaults to a new `hero.Container` with the builtin dependencies.
+	Container *hero.Container
+	// Logger logs the errors of the asynchronous handlers and of the bridged events.
+	// Defaults to the `golog.Default`.
+	Logger *golog.Logger
+}
+
+// Bus is a typed event bus, see the `New` package-level function.
+type Bus struct {
+	id        string
+	container *hero.Container
+	logger    *golog.Logger
+
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]*handler
+	brokers  map[reflect.Type][]Broker
+
+	wg sync.WaitGroup
+}
+
+// New returns a new event Bus.
+// Register handlers through its `On` and `OnAsync` methods and send events through its `Emit` one.
+func New(opts ...Options) *Bus {
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	container := options.Container
+	if container == nil {
+		container = hero.New()
+	}
+
+	logger := options.Logger
+	if logger == nil {
+		logger = golog.Default
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	return &Bus{
+		id:        hex.EncodeToString(id),
+		container: container,
+		logger:    logger,
+		handlers:  make(map[reflect.Type][]*handler),
+		brokers:   make(map[reflect.Type][]Broker),
+	}
+}
+
+// On registers a "handler" which is called, synchronously, on each emitted event of its type.
+//
+// The "handler" is a function which returns nothing or an error. Its input arguments are:
+//  - the event, its first input argument which is not a context
+//  - the Iris Context or the standard context.Context of the `Emit` call
+//  - any dependency registered to the `Options.Container`.
+func (b *Bus) On(handler interface{}) *Bus {
+	return b.register(handler, false)
+}
+
+// OnAsync like `On` but the "handler" is called on its own goroutine, the `Emit` does not wait for it.
+// Its dependencies are resolved before the `Emit` returns.
+// Note that the Iris Context is released after the request,
+// prefer the standard context.Context (which is not cancelled) on asynchronous handlers.
+// Errors and panics are logged to the `Options.Logger`.
+func (b *Bus) OnAsync(handler interface{}) *Bus {
+	return b.register(handler, true)
+}
+
+func (b *Bus) register(fn interface{}, async bool) *Bus {
+	h := newHandler(fn, async)
+
+	b.mu.Lock()
+	b.handlers[h.eventType] = append(b.handlers[h.eventType], h)
+	b.mu.Unlock()
+
+	return b
+}
+
+// Emit sends the "event" to its handlers and to the bridged brokers.
+// The "ctx" is the current request's Context, it can be nil, e.g. on background jobs.
+//
+// It returns the first error of the synchronous handlers or of the brokers.
+func (b *Bus) Emit(ctx context.Context, event interface{}) error {
+	v := reflect.ValueOf(event)
+	if !v.IsValid() {
+		return fmt.Errorf("events: emit: nil event")
+	}
+
+	firstErr := b.dispatch(ctx, v)
+
+	b.mu.RLock()
+	brokers := b.brokers[indirectType(v.Type())]
+	b.mu.RUnlock()
+
+	if len(brokers) > 0 {
+		payload, err := json.Marshal(envelope{Origin: b.id, Event: event})
+		if err != nil {
+			return err
+		}
+
+		topic := topicOf(event)
+		for _, broker := range brokers {
+			if err = broker.Publish(topic, payload); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func (b *Bus) dispatch(ctx context.Context, v reflect.Value) error {
+	b.mu.RLock()
+	handlers := b.handlers[indirectType(v.Type())]
+	b.mu.RUnlock()
+
+	var firstErr error
+	for _, h := range handlers {
+		inputs, err := h.inputs(ctx, b.container, v)
+		if err != nil {
+			if h.async {
+				b.logger.Errorf("Events: %s: %v", h.eventType, err)
+			} else if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if h.async {
+			b.wg.Add(1)
+			go func(h *handler) {
+				defer b.wg.Done()
+				if err := h.call(inputs); err != nil {
+					b.logger.Errorf("Events: %s: %v", h.eventType, err)
+				}
+			}(h)
+			continue
+		}
+
+		if err = h.call(inputs); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Wait waits for the running asynchronous handlers to return,
+// e.g. register it on `app.OnShutdown(bus.Wait)`.
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
+
+type envelope struct {
+	Origin string      `json:"origin"`
+	Event  interface{} `json:"event"`
+}
+
+// Bridge publishes the emitted "events" (one value of each event type) to the "broker"
+// and dispatches the events published by the other instances of the application to the local handlers.
+// The events are encoded as JSON, their handlers receive a nil Iris Context.
+//
+// Example Code:
+//  bus.Bridge(natsbroker.New(conn), UserRegistered{}, OrderPlaced{})
+func (b *Bus) Bridge(broker Broker, events ...interface{}) error {
+	for _, event := range events {
+		typ := indirectType(reflect.TypeOf(event))
+
+		err := broker.Subscribe(topicOf(event), func(payload []byte) {
+			b.receive(typ, payload)
+		})
+		if err != nil {
+			return err
+		}
+
+		b.mu.Lock()
+		b.brokers[typ] = append(b.brokers[typ], broker)
+		b.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (b *Bus) receive(typ reflect.Type, payload []byte) {
+	ptr := reflect.New(typ)
+	msg := envelope{Event: ptr.Interface()}
+
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		b.logger.Errorf("Events: %s: bridge: %v", typ, err)
+		return
+	}
+
+	if msg.Origin == b.id { // already dispatched.
+		return
+	}
+
+	if err := b.dispatch(nil, ptr.Elem()); err != nil {
+		b.logger.Errorf("Events: %s: %v", typ, err)
+	}
+}
+
+func topicOf(event interface{}) string {
+	if t, ok := event.(Topic); ok {
+		return t.Topic()
+	}
+
+	return indirectType(reflect.TypeOf(event)).Name()
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
+
+var (
+	contextType    = reflect.TypeOf((*context.Context)(nil)).Elem()
+	stdContextType = reflect.TypeOf((*stdContext.Context)(nil)).Elem()
+	errorType      = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type handler struct {
+	fn         reflect.Value
+	eventType  reflect.Type // indirect.
+	eventIndex int
+	async      bool
+}
+
+func newHandler(fn interface{}, async bool) *handler {
+	v := reflect.ValueOf(fn)
+	typ := v.Type()
+	if typ.Kind() != reflect.Func {
+		panic(fmt.Sprintf("events: handler: expected a function but got: %T", fn))
+	}
+
+	if n := typ.NumOut(); n > 1 || (n == 1 && typ.Out(0) != errorType) {
+		panic(fmt.Sprintf("events: handler: %s: expected to return nothing or an error", typ))
+	}
+
+	for i := 0; i < typ.NumIn(); i++ {
+		if in := typ.In(i); in != contextType && in != stdContextType {
+			return &handler{fn: v, eventType: indirectType(in), eventIndex: i, async: async}
+		}
+	}
+
+	panic(fmt.Sprintf("events: handler: %s: expected an event input argument", typ))
+}
+
+// inputs resolves the input arguments of the handler for the "event".
+func (h *handler) inputs(ctx context.Context, container *hero.Container, event reflect.Value) (inputs []reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil { // e.g. a request-scoped dependency with a nil Context.
+			err = fmt.Errorf("events: resolve dependencies: %v", r)
+		}
+	}()
+
+	typ := h.fn.Type()
+	inputs = make([]reflect.Value, typ.NumIn())
+	for i := range inputs {
+		in := typ.In(i)
+
+		switch {
+		case i == h.eventIndex:
+			inputs[i] = convertEvent(event, in)
+		case in == contextType:
+			if ctx == nil {
+				inputs[i] = reflect.Zero(in)
+			} else {
+				inputs[i] = reflect.ValueOf(ctx)
+			}
+		case in == stdContextType:
+			if ctx == nil || h.async {
+				inputs[i] = reflect.ValueOf(stdContext.Background())
+			} else {
+				inputs[i] = reflect.ValueOf(ctx.Request().Context())
+			}
+		default:
+			v, err := container.Resolve(ctx, in)
+			if err != nil {
+				return nil, fmt.Errorf("events: resolve %s: %w", in, err)
+			}
+			inputs[i] = v
+		}
+	}
+
+	return inputs, nil
+}
+
+// convertEvent converts the "event" to "typ", they may differ on their pointer indirection.
+func convertEvent(event reflect.Value, typ reflect.Type) reflect.Value {
+	switch {
+	case event.Type() == typ:
+		return event
+	case event.Kind() == reflect.Ptr:
+		return event.Elem()
+	default:
+		ptr := reflect.New(event.Type())
+		ptr.Elem().Set(event)
+		return ptr
+	}
+}
+
+func (h *handler) call(inputs []reflect.Value) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("events: %s: recovered from a panic: %v", h.eventType, r)
+		}
+	}()
+
+	outputs := h.fn.Call(inputs)
+	if len(outputs) == 1 && !outputs[0].IsNil() {
+		return outputs[0].Interface().(error)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package events_test
+
+import (
+	stdContext "context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/events"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/kataras/golog"
+)
+
+type (
+	userRegistered struct {
+		Email string `json:"email"`
+	}
+
+	mailer struct {
+		mu   sync.Mutex
+		sent []string
+	}
+)
+
+func (m *mailer) Send(to string) {
+	m.mu.Lock()
+	m.sent = append(m.sent, to)
+	m.mu.Unlock()
+}
+
+func (m *mailer) Sent() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.sent...)
+}
+
+func TestBus(t *testing.T) {
+	app := iris.New()
+	m := new(mailer)
+	app.ConfigureContainer().RegisterDependency(m)
+
+	bus := events.New(events.Options{Container: app.ConfigureContainer().Container})
+	bus.On(func(ctx iris.Context, e userRegistered, m *mailer) {
+		m.Send(e.Email + " " + ctx.Method())
+	})
+	bus.OnAsync(func(ctx stdContext.Context, e *userRegistered, m *mailer) error {
+		m.Send("async " + e.Email)
+		return nil
+	})
+	bus.On(func(e userRegistered) error {
+		if e.Email == "" {
+			return errors.New("email is required")
+		}
+		return nil
+	})
+
+	app.Post("/users", func(ctx iris.Context) {
+		if err := bus.Emit(ctx, userRegistered{Email: ctx.URLParam("email")}); err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusCreated)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/users").WithQuery("email", "kataras2006@hotmail.com").Expect().Status(httptest.StatusCreated)
+	e.POST("/users").Expect().Status(httptest.StatusBadRequest).Body().Equal("email is required")
+	bus.Wait()
+
+	sent := m.Sent()
+	expected := []string{"kataras2006@hotmail.com POST", "async kataras2006@hotmail.com", " POST", "async "}
+	if len(sent) != len(expected) {
+		t.Fatalf("expected sent: %v but got: %v", expected, sent)
+	}
+
+	for _, s := range expected {
+		found := false
+		for _, got := range sent {
+			if got == s {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Fatalf("expected sent: %v but got: %v", expected, sent)
+		}
+	}
+
+	// emit outside of a request, e.g. on a background job.
+	bus = events.New(events.Options{Container: app.ConfigureContainer().Container})
+	bus.On(func(ctx stdContext.Context, e *userRegistered, m *mailer) {
+		m.Send(e.Email)
+	})
+	if err := bus.Emit(nil, &userRegistered{Email: "makis@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent = m.Sent(); sent[len(sent)-1] != "makis@example.com" {
+		t.Fatalf("expected last sent: %s but got: %v", "makis@example.com", sent)
+	}
+}
+
+// memoryBroker delivers the published messages to all subscribers, including the publisher.
+type memoryBroker struct {
+	mu          sync.Mutex
+	subscribers map[string][]func([]byte)
+}
+
+func (b *memoryBroker) Publish(topic string, payload []byte) error {
+	b.mu.Lock()
+	subscribers := b.subscribers[topic]
+	b.mu.Unlock()
+
+	for _, handler := range subscribers {
+		handler(payload)
+	}
+
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(topic string, handler func([]byte)) error {
+	b.mu.Lock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[string][]func([]byte))
+	}
+	b.subscribers[topic] = append(b.subscribers[topic], handler)
+	b.mu.Unlock()
+
+	return nil
+}
+
+func TestBusBridge(t *testing.T) {
+	var logs strings.Builder
+	logger := golog.New().SetOutput(&logs)
+
+	broker := new(memoryBroker)
+
+	var received []string
+	instances := []*events.Bus{events.New(events.Options{Logger: logger}), events.New(events.Options{Logger: logger})}
+	for i, bus := range instances {
+		name := []string{"first", "second"}[i]
+		bus.On(func(e userRegistered) {
+			received = append(received, name+": "+e.Email)
+		})
+
+		if err := bus.Bridge(broker, userRegistered{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := instances[0].Emit(nil, userRegistered{Email: "kataras2006@hotmail.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"first: kataras2006@hotmail.com", "second: kataras2006@hotmail.com"}
+	if len(received) != len(expected) || received[0] != expected[0] || received[1] != expected[1] {
+		t.Fatalf("expected received: %v but got: %v", expected, received)
+	}
+
+	if logs.Len() > 0 {
+		t.Fatalf("unexpected logs: %s", logs.String())
+	}
+}
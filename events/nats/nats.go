@@ -0,0 +1,46 @@
+// Package nats provides a NATS `events.Broker`.
+package nats
+
+import (
+	"github.com/kataras/iris/v12/events"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Broker is a NATS `events.Broker`,
+// see the `New` package-level function.
+type Broker struct {
+	// Prefix is prepended to the topics to form the NATS subjects.
+	// Defaults to "iris.events.".
+	Prefix string
+
+	conn *nats.Conn
+}
+
+var _ events.Broker = (*Broker)(nil)
+
+// New returns a new NATS Broker of the "conn" connection.
+//
+// Example Code:
+//  conn, _ := nats.Connect(nats.DefaultURL)
+//  bus.Bridge(natsbroker.New(conn), UserRegistered{})
+func New(conn *nats.Conn) *Broker {
+	return &Broker{
+		Prefix: "iris.events.",
+		conn:   conn,
+	}
+}
+
+// Publish sends the "payload" to the NATS subject of the "topic".
+func (b *Broker) Publish(topic string, payload []byte) error {
+	return b.conn.Publish(b.Prefix+topic, payload)
+}
+
+// Subscribe registers the "handler" to the NATS subject of the "topic".
+func (b *Broker) Subscribe(topic string, handler func(payload []byte)) error {
+	_, err := b.conn.Subscribe(b.Prefix+topic, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+
+	return err
+}
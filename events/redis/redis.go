@@ -0,0 +1,57 @@
+// Package redis provides a Redis Pub/Sub `events.Broker`.
+package redis
+
+import (
+	"github.com/kataras/iris/v12/events"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// Broker is a Redis Pub/Sub `events.Broker`,
+// see the `New` package-level function.
+type Broker struct {
+	// Prefix is prepended to the topics to form the Redis channels.
+	// Defaults to "iris:events:".
+	Prefix string
+
+	client radix.Client
+	pubsub radix.PubSubConn
+}
+
+var _ events.Broker = (*Broker)(nil)
+
+// New returns a new Redis Broker which publishes through the "client" (e.g. a radix.Pool)
+// and subscribes through the "pubsub" connection (e.g. a radix.PersistentPubSub).
+//
+// Example Code:
+//  pool, _ := radix.NewPool("tcp", "127.0.0.1:6379", 10)
+//  pubsub, _ := radix.PersistentPubSubWithOpts("tcp", "127.0.0.1:6379")
+//  bus.Bridge(redis.New(pool, pubsub), UserRegistered{})
+func New(client radix.Client, pubsub radix.PubSubConn) *Broker {
+	return &Broker{
+		Prefix: "iris:events:",
+		client: client,
+		pubsub: pubsub,
+	}
+}
+
+// Publish sends the "payload" to the Redis channel of the "topic".
+func (b *Broker) Publish(topic string, payload []byte) error {
+	return b.client.Do(radix.FlatCmd(nil, "PUBLISH", b.Prefix+topic, payload))
+}
+
+// Subscribe registers the "handler" to the Redis channel of the "topic".
+func (b *Broker) Subscribe(topic string, handler func(payload []byte)) error {
+	msgCh := make(chan radix.PubSubMessage, 64)
+	if err := b.pubsub.Subscribe(msgCh, b.Prefix+topic); err != nil {
+		return err
+	}
+
+	go func() {
+		for msg := range msgCh {
+			handler(msg.Message)
+		}
+	}()
+
+	return nil
+}
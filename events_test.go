@@ -0,0 +1,67 @@
+package iris
+
+import (
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func TestApplicationEvents(t *testing.T) {
+	app := New()
+
+	var registered []string
+	app.OnRouteRegistered(func(r *router.Route) {
+		registered = append(registered, r.Method+" "+r.Path)
+	})
+
+	var configured []string
+	app.OnConfigurationApplied(func(c Configuration) {
+		configured = append(configured, c.Charset)
+	})
+	app.Configure(WithCharset("iso-8859-7"))
+
+	var slow []string
+	app.OnRequestSlow(20*time.Millisecond, func(ctx Context, latency time.Duration) {
+		if latency < 20*time.Millisecond {
+			t.Fatalf("expected latency >= threshold but got: %s", latency)
+		}
+		slow = append(slow, ctx.Path())
+	})
+
+	var builds []BuildEvent
+	app.OnBuildFinished(func(evt BuildEvent) {
+		builds = append(builds, evt)
+	})
+
+	app.Get("/", func(ctx Context) {})
+	app.Party("/api").Post("/sleep", func(ctx Context) {
+		time.Sleep(30 * time.Millisecond)
+	})
+
+	if expected, got := []string{"GET /", "POST /api/sleep"}, registered; len(got) != len(expected) || got[0] != expected[0] || got[1] != expected[1] {
+		t.Fatalf("expected registered routes: %v but got: %v", expected, got)
+	}
+
+	if len(configured) != 1 || configured[0] != "iso-8859-7" {
+		t.Fatalf("expected one configuration event but got: %v", configured)
+	}
+
+	h := app.BuildHandler()
+	if err := app.Build(); err != nil { // already built, no event.
+		t.Fatal(err)
+	}
+
+	if len(builds) != 1 || builds[0].Routes != 2 || builds[0].Err != nil {
+		t.Fatalf("expected one successful build event of 2 routes but got: %#+v", builds)
+	}
+
+	h.ServeHTTP(stdhttptest.NewRecorder(), stdhttptest.NewRequest(http.MethodGet, "/", nil))
+	h.ServeHTTP(stdhttptest.NewRecorder(), stdhttptest.NewRequest(http.MethodPost, "/api/sleep", nil))
+
+	if len(slow) != 1 || slow[0] != "/api/sleep" {
+		t.Fatalf("expected one slow request event but got: %v", slow)
+	}
+}
@@ -0,0 +1,291 @@
+// Package featureflags provides the feature flags of an application,
+// evaluated per request against the request's user and tenant.
+// The flags are loaded from one or more providers (static values, files, environment variables
+// and remote flag services) and they are available through `IsEnabled(ctx, "new-checkout")`,
+// as a hero/mvc `*featureflags.Evaluator` input argument, when `Dependency` is registered,
+// and as the ".Flags" view data.
+//
+// Example Code:
+//  flags := featureflags.New(featureflags.File("flags.yml"), featureflags.Env("FLAGS"))
+//  if err := flags.Load(); err != nil { [...] }
+//  app.Use(flags.Handler())
+//  app.Get("/checkout", func(ctx iris.Context) {
+//      if featureflags.IsEnabled(ctx, "new-checkout") { [...] }
+//  })
+package featureflags
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/tenancy"
+
+	"github.com/kataras/golog"
+)
+
+func init() {
+	context.SetHandlerName("iris/featureflags.*", "Feature Flags")
+}
+
+// ErrNoFlags is returned from the `Dependency`
+// when the `Flags.Handler` middleware is not registered.
+var ErrNoFlags = errors.New("featureflags: no flags")
+
+// Flag is the definition of a feature flag.
+type Flag struct {
+	// Enabled is the flag's switch, when false the flag is disabled for everyone.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Users and Tenants, if not empty, enable the flag only for the listed users and tenants,
+	// unless the Rollout enables it for others too.
+	Users   []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Tenants []string `json:"tenants,omitempty" yaml:"tenants,omitempty"`
+	// Rollout, if not zero, is the percentage (1-100) of the users (or tenants when there is no user)
+	// which the flag is enabled for. A user is always in or out of the same rollout.
+	Rollout int `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+}
+
+// EvalContext is the context which a flag is evaluated against, see `Flags.Evaluate`.
+type EvalContext struct {
+	User   string
+	Tenant string
+}
+
+// Evaluate reports whether the flag of the "name" is enabled for the "ec".
+func (f Flag) Evaluate(name string, ec EvalContext) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if len(f.Users) == 0 && len(f.Tenants) == 0 && f.Rollout == 0 {
+		return true
+	}
+
+	if ec.User != "" && contains(f.Users, ec.User) || ec.Tenant != "" && contains(f.Tenants, ec.Tenant) {
+		return true
+	}
+
+	if f.Rollout <= 0 {
+		return false
+	}
+
+	if f.Rollout >= 100 {
+		return true
+	}
+
+	key := ec.User
+	if key == "" {
+		if key = ec.Tenant; key == "" {
+			return false
+		}
+	}
+
+	return bucket(name, key) < f.Rollout
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bucket returns the rollout bucket (0-99) of the "key" for the flag of the "name",
+// each flag has different buckets so the same users are not always the first ones.
+func bucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
+
+// Flags holds the feature flags loaded by its providers.
+// Later providers override the flags of the previous ones.
+//
+// Use the `New` function to create a new Flags.
+type Flags struct {
+	// Resolve returns the evaluation context of a request.
+	// Defaults to the tenant's ID of the tenancy package
+	// and the username of the request's basic authentication.
+	Resolve func(ctx context.Context) EvalContext
+	// ErrorHandler is fired when a reload through `Watch` failed,
+	// the previous flags are kept.
+	// Defaults to a function which logs the error.
+	ErrorHandler func(error)
+
+	providers []Provider
+
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// New returns a new Flags of the given "providers".
+// Call its `Load` method to read the flags.
+func New(providers ...Provider) *Flags {
+	return &Flags{
+		Resolve: defaultResolve,
+		ErrorHandler: func(err error) {
+			golog.Errorf("featureflags: reload: %v", err)
+		},
+		providers: providers,
+		flags:     make(map[string]Flag),
+	}
+}
+
+func defaultResolve(ctx context.Context) EvalContext {
+	var ec EvalContext
+	if t := tenancy.FromContext(ctx); t != nil {
+		ec.Tenant = t.ID
+	}
+
+	if username, _, ok := ctx.Request().BasicAuth(); ok {
+		ec.User = username
+	}
+
+	return ec
+}
+
+// Load reads the flags of all providers.
+// On failure the previous flags are kept.
+func (f *Flags) Load() error {
+	flags := make(map[string]Flag)
+	for _, p := range f.providers {
+		loaded, err := p.Load()
+		if err != nil {
+			return err
+		}
+
+		for name, flag := range loaded {
+			flags[name] = flag
+		}
+	}
+
+	f.mu.Lock()
+	f.flags = flags
+	f.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the flags every "interval", so flags can be switched without a restart.
+// It returns a function which stops the watcher.
+func (f *Flags) Watch(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := f.Load(); err != nil && f.ErrorHandler != nil {
+					f.ErrorHandler(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// Flag returns the definition of the flag of the "name".
+func (f *Flags) Flag(name string) (Flag, bool) {
+	f.mu.RLock()
+	flag, ok := f.flags[name]
+	f.mu.RUnlock()
+	return flag, ok
+}
+
+// Evaluate reports whether the flag of the "name" is enabled for the "ec",
+// unknown flags are disabled.
+func (f *Flags) Evaluate(name string, ec EvalContext) bool {
+	flag, ok := f.Flag(name)
+	return ok && flag.Evaluate(name, ec)
+}
+
+// Dependency is the hero/mvc dependency of the request's `*Evaluator`,
+// it fails with `ErrNoFlags` when the `Flags.Handler` is not registered.
+//
+// Usage:
+//  app.ConfigureContainer().RegisterDependency(featureflags.Dependency)
+//  app.ConfigureContainer().Get("/", func(flags *featureflags.Evaluator) string { ... })
+func Dependency(ctx context.Context) (*Evaluator, error) {
+	if e := Get(ctx); e != nil {
+		return e, nil
+	}
+
+	return nil, ErrNoFlags
+}
+
+// IsEnabled reports whether the flag of the "name" is enabled for the request.
+func (f *Flags) IsEnabled(ctx context.Context, name string) bool {
+	return f.Evaluate(name, f.Resolve(ctx))
+}
+
+// Evaluator evaluates the flags for a request, see `Get`.
+type Evaluator struct {
+	flags *Flags
+	// Context is the request's evaluation context.
+	Context EvalContext
+}
+
+// IsEnabled reports whether the flag of the "name" is enabled for the request.
+// It returns false on a nil Evaluator.
+func (e *Evaluator) IsEnabled(name string) bool {
+	if e == nil {
+		return false
+	}
+
+	return e.flags.Evaluate(name, e.Context)
+}
+
+const (
+	contextKey = "iris.featureflags"
+	// ViewDataKey is the view data key of the request's `Evaluator`, i.e
+	//  {{ if .Flags.IsEnabled "new-checkout" }} [...] {{ end }}
+	ViewDataKey = "Flags"
+)
+
+// Handler returns a middleware which resolves the evaluation context of each request
+// and stores its `Evaluator`, see `Get` and `IsEnabled` package-level functions.
+// Register it after the middlewares which resolve the user and the tenant.
+func (f *Flags) Handler() context.Handler {
+	return func(ctx context.Context) {
+		e := &Evaluator{flags: f, Context: f.Resolve(ctx)}
+		ctx.Values().Set(contextKey, e)
+		ctx.ViewData(ViewDataKey, e)
+		ctx.Next()
+	}
+}
+
+// Get returns the `Evaluator` of the request, or nil if the `Flags.Handler` is not registered.
+func Get(ctx context.Context) *Evaluator {
+	if v := ctx.Values().Get(contextKey); v != nil {
+		if e, ok := v.(*Evaluator); ok {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// IsEnabled reports whether the flag of the "name" is enabled for the request,
+// the `Flags.Handler` should be registered.
+func IsEnabled(ctx context.Context, name string) bool {
+	return Get(ctx).IsEnabled(name)
+}
@@ -0,0 +1,78 @@
+package featureflags_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/featureflags"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestFlagEvaluate(t *testing.T) {
+	flag := featureflags.Flag{Enabled: true, Tenants: []string{"acme"}, Rollout: 50}
+
+	if !flag.Evaluate("new-checkout", featureflags.EvalContext{Tenant: "acme"}) {
+		t.Fatal("expected flag to be enabled for a listed tenant")
+	}
+
+	if flag.Evaluate("new-checkout", featureflags.EvalContext{}) {
+		t.Fatal("expected rollout flag to be disabled without a user or a tenant")
+	}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		ec := featureflags.EvalContext{User: "user" + string(rune('a'+i%26)) + string(rune('a'+i/26))}
+		if flag.Evaluate("new-checkout", ec) {
+			enabled++
+		}
+
+		if flag.Evaluate("new-checkout", ec) != flag.Evaluate("new-checkout", ec) {
+			t.Fatal("expected sticky rollout")
+		}
+	}
+
+	if enabled < 400 || enabled > 600 {
+		t.Fatalf("expected about half of the users to be in the rollout but got: %d/1000", enabled)
+	}
+
+	if (featureflags.Flag{Enabled: false}).Evaluate("new-checkout", featureflags.EvalContext{User: "kataras"}) {
+		t.Fatal("expected disabled flag")
+	}
+}
+
+func TestFlags(t *testing.T) {
+	os.Setenv("IRIS_FLAGS_DARK_MODE", "true")
+	defer os.Unsetenv("IRIS_FLAGS_DARK_MODE")
+
+	flags := featureflags.New(
+		featureflags.Static(map[string]featureflags.Flag{
+			"new-checkout": {Enabled: true, Users: []string{"kataras"}},
+			"dark-mode":    {Enabled: false},
+		}),
+		featureflags.Env("IRIS_FLAGS"),
+	)
+	if err := flags.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.Use(flags.Handler())
+	app.Get("/", func(ctx iris.Context) {
+		ctx.Writef("%v:%v:%v", featureflags.IsEnabled(ctx, "new-checkout"), featureflags.IsEnabled(ctx, "dark-mode"),
+			ctx.GetViewData()[featureflags.ViewDataKey] != nil)
+	})
+	app.ConfigureContainer().RegisterDependency(featureflags.Dependency)
+	app.ConfigureContainer().Get("/hero", func(flags *featureflags.Evaluator) string {
+		if flags.IsEnabled("new-checkout") {
+			return "new checkout"
+		}
+
+		return "checkout"
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithBasicAuth("kataras", "pass").Expect().Status(httptest.StatusOK).Body().Equal("true:true:true")
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("false:true:true")
+	e.GET("/hero").WithBasicAuth("kataras", "pass").Expect().Status(httptest.StatusOK).Body().Equal("new checkout")
+}
@@ -0,0 +1,165 @@
+package featureflags
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Provider loads the flag definitions by name.
+// See `Static`, `File`, `Env` and `HTTP` package-level functions.
+type Provider interface {
+	Load() (map[string]Flag, error)
+}
+
+// ProviderFunc is a function which implements the `Provider` interface.
+type ProviderFunc func() (map[string]Flag, error)
+
+// Load calls the "fn" itself.
+func (fn ProviderFunc) Load() (map[string]Flag, error) {
+	return fn()
+}
+
+// Static returns a `Provider` of static flags, commonly used for defaults.
+func Static(flags map[string]Flag) Provider {
+	return ProviderFunc(func() (map[string]Flag, error) {
+		return flags, nil
+	})
+}
+
+// File returns a `Provider` which reads the "filename" on each load,
+// a ".yml" or ".yaml" file is decoded as YAML, otherwise as JSON, e.g.
+//  new-checkout:
+//    enabled: true
+//    tenants: [acme]
+//    rollout: 25
+// A missing file is not an error, it just provides no flags.
+func File(filename string) Provider {
+	return ProviderFunc(func() (map[string]Flag, error) {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("featureflags: %w", err)
+		}
+
+		flags, err := decode(filepath.Ext(filename), data)
+		if err != nil {
+			return nil, fmt.Errorf("featureflags: %s: %w", filename, err)
+		}
+
+		return flags, nil
+	})
+}
+
+// Env returns a `Provider` of the environment variables which start with the "prefix" and an underline.
+// The rest of the variable's name is lowercased and each underline is replaced with a dash,
+// e.g. "FLAGS_NEW_CHECKOUT=true" with "FLAGS" prefix enables the "new-checkout" flag.
+// The value is a boolean or a rollout percentage, e.g. "25%".
+func Env(prefix string) Provider {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	return ProviderFunc(func() (map[string]Flag, error) {
+		flags := make(map[string]Flag)
+		for _, kv := range os.Environ() {
+			idx := strings.IndexByte(kv, '=')
+			if idx <= 0 || !strings.HasPrefix(kv[:idx], prefix) {
+				continue
+			}
+
+			name := strings.Replace(strings.ToLower(kv[len(prefix):idx]), "_", "-", -1)
+			if name == "" {
+				continue
+			}
+
+			flag, err := parseEnvFlag(kv[idx+1:])
+			if err != nil {
+				return nil, fmt.Errorf("featureflags: %s: %w", kv[:idx], err)
+			}
+
+			flags[name] = flag
+		}
+
+		return flags, nil
+	})
+}
+
+func parseEnvFlag(s string) (Flag, error) {
+	if strings.HasSuffix(s, "%") {
+		rollout, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+		if err != nil {
+			return Flag{}, err
+		}
+
+		return Flag{Enabled: rollout > 0, Rollout: rollout}, nil
+	}
+
+	enabled, err := strconv.ParseBool(s)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	return Flag{Enabled: enabled}, nil
+}
+
+// HTTP returns a `Provider` which fetches the flags from a remote flag service
+// through a GET request to the "url", the response is decoded as JSON (or YAML by its Content-Type)
+// of the same format as the `File` provider's one.
+// Use the `Flags.Watch` to poll the service for changes.
+func HTTP(url string) Provider {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return ProviderFunc(func() (map[string]Flag, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, fmt.Errorf("featureflags: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("featureflags: %s: unexpected status code: %d", url, resp.StatusCode)
+		}
+
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("featureflags: %w", err)
+		}
+
+		ext := ".json"
+		if strings.Contains(resp.Header.Get("Content-Type"), "yaml") {
+			ext = ".yml"
+		}
+
+		flags, err := decode(ext, data)
+		if err != nil {
+			return nil, fmt.Errorf("featureflags: %s: %w", url, err)
+		}
+
+		return flags, nil
+	})
+}
+
+func decode(ext string, data []byte) (map[string]Flag, error) {
+	flags := make(map[string]Flag)
+
+	var err error
+	switch strings.ToLower(ext) {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &flags)
+	default:
+		err = json.Unmarshal(data, &flags)
+	}
+
+	return flags, err
+}
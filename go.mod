@@ -14,7 +14,6 @@ require (
 	github.com/golang/protobuf v1.4.0
 	github.com/gomodule/redigo v1.7.1-0.20190724094224-574c33c3df38
 	github.com/hashicorp/go-version v1.2.0
-	github.com/iris-contrib/blackfriday v2.0.0+incompatible
 	github.com/iris-contrib/go.uuid v2.0.0+incompatible
 	github.com/iris-contrib/jade v1.1.3
 	github.com/iris-contrib/pongo2 v0.0.1
@@ -27,9 +26,11 @@ require (
 	github.com/klauspost/compress v1.10.5
 	github.com/mediocregopher/radix/v3 v3.5.0
 	github.com/microcosm-cc/bluemonday v1.0.2
+	github.com/nats-io/nats.go v1.9.2
 	github.com/ryanuber/columnize v2.1.0+incompatible
 	github.com/schollz/closestmatch v2.1.0+incompatible
 	github.com/vmihailenco/msgpack/v5 v5.0.0-alpha.2
+	github.com/yuin/goldmark v1.4.13
 	go.etcd.io/bbolt v1.3.4
 	golang.org/x/crypto v0.0.0-20200427165652-729f1e841bcc
 	golang.org/x/text v0.3.2
@@ -5,7 +5,9 @@ package iris
 import (
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/host"
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/core/scheduler"
 	"github.com/kataras/iris/v12/hero"
 )
 
@@ -72,6 +74,25 @@ type (
 	//
 	// It is an alias of the `context#XML` type.
 	XML = context.XML
+	// Locale is the interface which returns from the `Context.GetLocale` method,
+	// it can be used as a handler's input parameter to receive the current request's i18n locale.
+	//
+	// It is an alias of the `context#Locale` type.
+	Locale = context.Locale
+	// ValidationError describes a single field's validation failure.
+	// See `Application.Validator` and `context.AsValidationErrors` too.
+	//
+	// It is an alias of the `context#ValidationError` type.
+	ValidationError = context.ValidationError
+	// ValidationErrors is a list of `ValidationError`,
+	// custom `Validator` implementations may return it directly.
+	//
+	// It is an alias of the `context#ValidationErrors` type.
+	ValidationErrors = context.ValidationErrors
+	// MultipartStreamHandlers holds the callbacks and the limits of the `Context.ReadMultipartStream` method.
+	//
+	// It is an alias of the `context#MultipartStreamHandlers` type.
+	MultipartStreamHandlers = context.MultipartStreamHandlers
 	// Supervisor is a shortcut of the `host#Supervisor`.
 	// Used to add supervisor configurators on common Runners
 	// without the need of importing the `core/host` package.
@@ -109,6 +130,10 @@ type (
 	//
 	// See `ExecutionRules` and `core/router/Party#SetExecutionRules` for more.
 	ExecutionOptions = router.ExecutionOptions
+	// SplitStrategy holds the options of the traffic's distribution of the `Route.Split`.
+	//
+	// A shortcut for the `core/router#SplitStrategy`.
+	SplitStrategy = router.SplitStrategy
 
 	// CookieOption is the type of function that is accepted on
 	// context's methods like `SetCookieKV`, `RemoveCookie` and `SetCookie`
@@ -127,4 +152,16 @@ type (
 	//
 	// An alias for the `context/Context#N`.
 	N = context.N
+
+	// ScheduleOptions holds the optional settings of a job registered through the `Application.Schedule` method.
+	//
+	// An alias for the `core/scheduler#Options`.
+	ScheduleOptions = scheduler.Options
+
+	// Logger is the structured logger of the Application,
+	// with key/value fields, a runtime level and pluggable sinks.
+	// See `Application.Log` and `WithLogger`.
+	//
+	// An alias for the `core/logging#Logger`.
+	Logger = logging.Logger
 )
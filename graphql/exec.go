@@ -0,0 +1,422 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+type (
+	// Request is a GraphQL request, as sent by the clients.
+	Request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName,omitempty"`
+		Variables     map[string]interface{} `json:"variables,omitempty"`
+	}
+
+	// Response is the result of a GraphQL request.
+	Response struct {
+		// Data is nil when the request failed before its execution, e.g. on syntax errors.
+		Data   interface{} `json:"data,omitempty"`
+		Errors []*Error    `json:"errors,omitempty"`
+	}
+
+	// Error is a GraphQL error.
+	Error struct {
+		Message   string        `json:"message"`
+		Locations []Location    `json:"locations,omitempty"`
+		Path      []interface{} `json:"path,omitempty"`
+	}
+
+	// Location is the line and column of a query's token.
+	Location struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	}
+)
+
+// Error completes the error interface.
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// orderedMap is a JSON object which keeps the order of the selected fields.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]interface{})}
+}
+
+func (m *orderedMap) Set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+}
+
+func (m *orderedMap) Get(key string) interface{} {
+	return m.values[key]
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+type executor struct {
+	g         *GraphQL
+	ctx       context.Context
+	doc       *document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+// Do executes the GraphQL "req" and returns its response.
+// The "ctx" is the Iris Context the resolvers accept.
+// Mutations are not allowed if "readOnly" is true, e.g. for GET requests.
+func (g *GraphQL) Do(ctx context.Context, req Request, readOnly bool) *Response {
+	doc, err := parse(req.Query, g.opts.MaxDepth)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	var root *Object
+	switch op.typ {
+	case "query":
+		root = g.schema.Query
+	case "mutation":
+		if readOnly {
+			return errorResponse(fmt.Errorf("mutations are not allowed on read-only requests"))
+		}
+		root = g.schema.Mutation
+	default:
+		return errorResponse(fmt.Errorf("%s operations are not supported", op.typ))
+	}
+
+	e := &executor{
+		g:         g,
+		ctx:       ctx,
+		doc:       doc,
+		variables: make(map[string]interface{}, len(op.variables)),
+	}
+
+	for _, def := range op.variables {
+		if v, ok := req.Variables[def.name]; ok {
+			e.variables[def.name] = normalizeValue(v)
+		} else if def.hasDefault {
+			e.variables[def.name] = e.value(def.defaultValue)
+		}
+	}
+
+	data := e.executeSelections(root, nil, op.selections, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func errorResponse(err error) *Response {
+	gqlErr, ok := err.(*Error)
+	if !ok {
+		gqlErr = &Error{Message: err.Error()}
+	}
+
+	return &Response{Errors: []*Error{gqlErr}}
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operation name is required for documents with multiple operations")
+		}
+		return doc.operations[0], nil
+	}
+
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation named %q", name)
+}
+
+// normalizeValue converts the JSON numbers of the variables to int64 or float64.
+func normalizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	case float64:
+		if n := int64(value); float64(n) == value {
+			return n
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = normalizeValue(value[i])
+		}
+	case map[string]interface{}:
+		for k := range value {
+			value[k] = normalizeValue(value[k])
+		}
+	}
+
+	return v
+}
+
+// value resolves the variables and enums of a value literal.
+func (e *executor) value(v interface{}) interface{} {
+	switch value := v.(type) {
+	case variable:
+		return e.variables[string(value)]
+	case enumValue:
+		return string(value)
+	case listValue:
+		list := make([]interface{}, len(value))
+		for i := range value {
+			list[i] = e.value(value[i])
+		}
+		return list
+	case objectValue:
+		obj := make(map[string]interface{}, len(value))
+		for _, f := range value {
+			obj[f.name] = e.value(f.value)
+		}
+		return obj
+	}
+
+	return v
+}
+
+func (e *executor) args(args []*argument) Args {
+	values := make(Args, len(args))
+	for _, arg := range args {
+		values[arg.name] = e.value(arg.value)
+	}
+
+	return values
+}
+
+// shouldInclude reports whether a selection should be executed based on its @skip and @include directives.
+func (e *executor) shouldInclude(directives []*directive) bool {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			continue
+		}
+
+		condition, _ := e.args(d.args)["if"].(bool)
+		if (d.name == "skip") == condition {
+			return false
+		}
+	}
+
+	return true
+}
+
+// collectFields flattens the fragments of the "selections" and groups the fields by their response key.
+func (e *executor) collectFields(typeName string, selections []selection, keys *[]string, fields map[string][]*field, visited map[string]struct{}) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *field:
+			if !e.shouldInclude(s.directives) {
+				continue
+			}
+
+			key := s.responseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], s)
+		case *inlineFragment:
+			if !e.shouldInclude(s.directives) || (s.typeCondition != "" && s.typeCondition != typeName) {
+				continue
+			}
+
+			e.collectFields(typeName, s.selections, keys, fields, visited)
+		case *fragmentSpread:
+			if _, ok := visited[s.name]; ok || !e.shouldInclude(s.directives) {
+				continue
+			}
+			visited[s.name] = struct{}{}
+
+			frag, ok := e.doc.fragments[s.name]
+			if !ok {
+				e.errors = append(e.errors, &Error{Message: fmt.Sprintf("unknown fragment %q", s.name), Locations: []Location{s.loc}})
+				continue
+			}
+
+			if frag.typeCondition != typeName {
+				continue
+			}
+
+			e.collectFields(typeName, frag.selections, keys, fields, visited)
+		}
+	}
+}
+
+func (e *executor) executeSelections(obj *Object, parent interface{}, selections []selection, path []interface{}) *orderedMap {
+	typeName := ""
+	if obj != nil {
+		typeName = obj.Name
+	} else if typ := indirectType(reflect.TypeOf(parent)); typ != nil {
+		typeName = typ.Name()
+	}
+
+	var keys []string
+	fields := make(map[string][]*field)
+	e.collectFields(typeName, selections, &keys, fields, make(map[string]struct{}))
+
+	result := newOrderedMap()
+	for _, key := range keys {
+		fs := fields[key]
+		f := fs[0]
+
+		if f.name == "__typename" {
+			result.Set(key, typeName)
+			continue
+		}
+
+		// merge the sub-selections of the same response key.
+		subSelections := f.selections
+		for _, other := range fs[1:] {
+			subSelections = append(subSelections, other.selections...)
+		}
+
+		fieldPath := append(append([]interface{}(nil), path...), key)
+		value, err := e.resolveField(obj, typeName, parent, f, fieldPath)
+		if err != nil {
+			e.fieldError(err, f, fieldPath)
+			result.Set(key, nil)
+			continue
+		}
+
+		result.Set(key, e.complete(value, subSelections, f, fieldPath))
+	}
+
+	return result
+}
+
+func (e *executor) fieldError(err error, f *field, path []interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: []Location{f.loc},
+		Path:      path,
+	})
+}
+
+func (e *executor) resolveField(obj *Object, typeName string, parent interface{}, f *field, path []interface{}) (interface{}, error) {
+	var schemaField *Field
+	if obj != nil {
+		schemaField = obj.fields[f.name]
+	}
+
+	if schemaField == nil && (obj == nil || obj.typ == nil) && parent == nil {
+		return nil, fmt.Errorf("cannot query field %q on type %q", f.name, typeName)
+	}
+
+	resolve := func(rctx *ResolveContext) (interface{}, error) {
+		if schemaField != nil && schemaField.resolver != nil {
+			return schemaField.resolver.call(e.g.container, rctx)
+		}
+
+		return defaultResolve(rctx.Parent, rctx.Object, rctx.Field)
+	}
+
+	if schemaField != nil {
+		for i := len(schemaField.middleware) - 1; i >= 0; i-- {
+			resolve = schemaField.middleware[i](resolve)
+		}
+
+		for i := len(e.g.opts.Middleware) - 1; i >= 0; i-- {
+			resolve = e.g.opts.Middleware[i](resolve)
+		}
+	}
+
+	return resolve(&ResolveContext{
+		Context: e.ctx,
+		Object:  typeName,
+		Field:   f.name,
+		Path:    path,
+		Args:    e.args(f.args),
+		Parent:  parent,
+	})
+}
+
+// complete executes the "selections" against the resolved "value" of the field "f".
+func (e *executor) complete(value interface{}, selections []selection, f *field, path []interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			itemPath := append(append([]interface{}(nil), path...), i)
+			list[i] = e.complete(v.Index(i).Interface(), selections, f, itemPath)
+		}
+		return list
+	case reflect.Struct, reflect.Map:
+		if len(selections) == 0 {
+			return value
+		}
+
+		return e.executeSelections(e.g.schema.objectOf(v.Type()), value, selections, path)
+	}
+
+	if len(selections) > 0 {
+		e.fieldError(fmt.Errorf("field %q of type %q must not have a selection", f.name, v.Type()), f, path)
+		return nil
+	}
+
+	return value
+}
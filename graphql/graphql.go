@@ -0,0 +1,221 @@
+// Package graphql provides a GraphQL endpoint and playground for Iris applications.
+// The schema is described in Go code, the resolvers are functions
+// which accept the Iris Context, the field's arguments and any
+// dependency registered to a hero Container.
+//
+// Example Code:
+//  schema := graphql.NewSchema()
+//  schema.Query.Field("hello", func(args struct{ Name string }) string {
+//      return "Hello " + args.Name
+//  })
+//
+//  g := graphql.New(schema, graphql.Options{Container: app.ConfigureContainer().Container})
+//  app.Post("/graphql", g.Handler)
+//  app.Get("/graphql", g.Handler)
+//  app.Get("/playground", g.Playground)
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+)
+
+func init() {
+	context.SetHandlerName("iris/graphql.*", "GraphQL")
+}
+
+// Options holds the optional settings of a `GraphQL` endpoint.
+type Options struct {
+	// Container is the dependency injection container which resolves the resolvers' input arguments.
+	// Pass the `app.ConfigureContainer().Container` to share the application's dependencies.
+	// Defaults to a new `hero.Container` with the builtin dependencies.
+	Container *hero.Container
+	// Middleware runs before the resolvers of all fields registered through `Object.Field`,
+	// e.g. to authorize the access per field based on the `ResolveContext`.
+	// Defaults to nil.
+	Middleware []Middleware
+	// Endpoint is the URL which the Playground sends the queries to.
+	// Defaults to the Playground's request path.
+	Endpoint string
+	// PlaygroundCDN is the base URL which the Playground loads the GraphiQL, React and ReactDOM assets from,
+	// the assets are not embedded. Set it to serve them from a self-hosted mirror of the unpkg layout.
+	// Defaults to "https://unpkg.com".
+	PlaygroundCDN string
+	// MaxDepth is the maximum nesting of a query's selection sets, values and types,
+	// deeper queries are rejected with a syntax error. A negative value means unlimited.
+	// Defaults to 64.
+	MaxDepth int
+	// MaxBodySize is the maximum size of a POST request's body in bytes,
+	// larger bodies are rejected with 413 Request Entity Too Large.
+	// A negative value means unlimited.
+	// Defaults to 1MB.
+	MaxBodySize int64
+}
+
+const (
+	// DefaultMaxDepth is the default `Options.MaxDepth`.
+	DefaultMaxDepth = 64
+	// DefaultMaxBodySize is the default `Options.MaxBodySize`.
+	DefaultMaxBodySize = 1 << 20
+	// DefaultPlaygroundCDN is the default `Options.PlaygroundCDN`.
+	DefaultPlaygroundCDN = "https://unpkg.com"
+)
+
+// GraphQL is the GraphQL endpoint of a `Schema`,
+// see its `Handler` and `Playground` methods.
+type GraphQL struct {
+	schema    *Schema
+	opts      Options
+	container *hero.Container
+}
+
+// New returns a new GraphQL endpoint for the "schema".
+// See `NewSchema` and `Options` too.
+func New(schema *Schema, opts ...Options) *GraphQL {
+	g := &GraphQL{schema: schema}
+	if len(opts) > 0 {
+		g.opts = opts[0]
+	}
+
+	if g.opts.MaxDepth == 0 {
+		g.opts.MaxDepth = DefaultMaxDepth
+	}
+
+	if g.opts.MaxBodySize == 0 {
+		g.opts.MaxBodySize = DefaultMaxBodySize
+	}
+
+	if g.opts.PlaygroundCDN == "" {
+		g.opts.PlaygroundCDN = DefaultPlaygroundCDN
+	}
+	g.opts.PlaygroundCDN = strings.TrimSuffix(g.opts.PlaygroundCDN, "/")
+
+	g.container = g.opts.Container
+	if g.container == nil {
+		g.container = hero.New()
+	}
+
+	return g
+}
+
+// Handler executes the GraphQL queries of GET (through the "query", "operationName" and "variables" URL parameters)
+// and POST requests (JSON or "application/graphql" body) and sends the JSON response.
+// Mutations are allowed on POST requests only.
+func (g *GraphQL) Handler(ctx context.Context) {
+	var (
+		req Request
+		err error
+	)
+
+	readOnly := ctx.Method() == http.MethodGet
+	if readOnly {
+		req.Query = ctx.URLParam("query")
+		req.OperationName = ctx.URLParam("operationName")
+		if variables := ctx.URLParam("variables"); variables != "" {
+			err = decodeJSON([]byte(variables), &req.Variables)
+		}
+	} else {
+		if max := g.opts.MaxBodySize; max > 0 {
+			r := ctx.Request()
+			if r.ContentLength > max {
+				ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			if r.Body != nil {
+				r.Body = ioutil.NopCloser(io.LimitReader(r.Body, max+1))
+			}
+		}
+
+		var body []byte
+		body, err = ctx.GetBody()
+		if max := g.opts.MaxBodySize; max > 0 && int64(len(body)) > max {
+			ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err == nil {
+			if ctx.GetContentTypeRequested() == "application/graphql" {
+				req.Query = string(body)
+			} else {
+				err = decodeJSON(body, &req)
+			}
+		}
+	}
+
+	var resp *Response
+	if err != nil {
+		resp = errorResponse(err)
+	} else {
+		resp = g.Do(ctx, req, readOnly)
+	}
+
+	if resp.Data == nil {
+		ctx.StatusCode(http.StatusBadRequest)
+	}
+
+	ctx.JSON(resp)
+}
+
+func decodeJSON(b []byte, ptr interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(ptr)
+}
+
+// Playground serves the GraphiQL in-browser IDE which sends its queries to the `Options.Endpoint`.
+// The page loads its assets from the `Options.PlaygroundCDN`, they are not embedded.
+func (g *GraphQL) Playground(ctx context.Context) {
+	endpoint := g.opts.Endpoint
+	if endpoint == "" {
+		endpoint = ctx.Path()
+	}
+
+	var buf strings.Builder
+	data := struct {
+		Endpoint string
+		CDN      string
+	}{endpoint, g.opts.PlaygroundCDN}
+	if err := playgroundTmpl.Execute(&buf, data); err != nil {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.HTML(buf.String())
+}
+
+var playgroundTmpl = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>GraphQL Playground</title>
+  <link rel="stylesheet" href="{{.CDN}}/graphiql@1.0.3/graphiql.min.css" />
+  <style>body { height: 100vh; margin: 0; overflow: hidden; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script src="{{.CDN}}/react@16/umd/react.production.min.js"></script>
+  <script src="{{.CDN}}/react-dom@16/umd/react-dom.production.min.js"></script>
+  <script src="{{.CDN}}/graphiql@1.0.3/graphiql.min.js"></script>
+  <script>
+    var endpoint = {{.Endpoint}};
+    function fetcher(params) {
+      return fetch(endpoint, {
+        method: "POST",
+        headers: { "Accept": "application/json", "Content-Type": "application/json" },
+        body: JSON.stringify(params),
+        credentials: "same-origin"
+      }).then(function (resp) { return resp.json(); });
+    }
+    ReactDOM.render(React.createElement(GraphiQL, { fetcher: fetcher }), document.getElementById("graphiql"));
+  </script>
+</body>
+</html>`))
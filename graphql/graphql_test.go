@@ -0,0 +1,186 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/graphql"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type (
+	user struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+
+	post struct {
+		Title string
+	}
+
+	userService struct {
+		users []*user
+	}
+
+	postService struct {
+		posts map[int][]post
+	}
+)
+
+func (s *userService) Get(id int) (*user, error) {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+
+	return nil, errors.New("user not found")
+}
+
+func newApp() *iris.Application {
+	app := iris.New()
+
+	api := app.ConfigureContainer()
+	api.RegisterDependency(&userService{users: []*user{
+		{ID: 1, Username: "kataras", Email: "kataras@example.com"},
+		{ID: 2, Username: "makis", Email: "makis@example.com"},
+	}})
+	api.RegisterDependency(&postService{posts: map[int][]post{1: {{Title: "Iris"}, {Title: "GraphQL"}}}})
+
+	schema := graphql.NewSchema()
+	schema.Query.Field("version", "v1")
+	schema.Query.Field("users", func(svc *userService) []*user {
+		return svc.users
+	})
+	schema.Query.Field("user", func(svc *userService, args struct {
+		ID int `json:"id"`
+	}) (*user, error) {
+		return svc.Get(args.ID)
+	})
+	schema.Query.Field("hello", func(ctx iris.Context, args graphql.Args) string {
+		return ctx.Method() + " " + args["name"].(string)
+	})
+	schema.Mutation.Field("rename", func(svc *userService, args struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	}) (*user, error) {
+		u, err := svc.Get(args.ID)
+		if err != nil {
+			return nil, err
+		}
+		u.Username = args.Username
+		return u, nil
+	})
+
+	userObject := schema.Object(user{})
+	userObject.Field("posts", func(u *user, svc *postService) []post {
+		return svc.posts[u.ID]
+	})
+	// authorize the email field access.
+	userObject.Field("email", nil).Use(func(next graphql.Resolver) graphql.Resolver {
+		return func(rctx *graphql.ResolveContext) (interface{}, error) {
+			if rctx.Context.GetHeader("Authorization") == "" {
+				return nil, errors.New("unauthorized")
+			}
+			return next(rctx)
+		}
+	})
+
+	g := graphql.New(schema, graphql.Options{Container: api.Container})
+	app.Get("/graphql", g.Handler)
+	app.Post("/graphql", g.Handler)
+	app.Get("/playground", g.Playground)
+
+	return app
+}
+
+func TestGraphQL(t *testing.T) {
+	app := newApp()
+	e := httptest.New(t, app)
+
+	expectJSON(t, e.GET("/graphql").WithQuery("query", `{ version hello(name: "iris") }`).Expect().
+		Status(httptest.StatusOK),
+		`{"data":{"version":"v1","hello":"GET iris"}}`)
+
+	query := `
+	# fragments, aliases and variables.
+	query GetUser($id: Int!, $withPosts: Boolean = true) {
+		first: user(id: 1) { ...userFields }
+		second: user(id: $id) {
+			__typename
+			...userFields
+			posts @include(if: $withPosts) { title }
+		}
+	}
+
+	fragment userFields on user { id username }
+	`
+	expectJSON(t, e.POST("/graphql").WithJSON(iris.Map{"query": query, "variables": iris.Map{"id": 1}}).Expect().
+		Status(httptest.StatusOK),
+		`{"data":{"first":{"id":1,"username":"kataras"},"second":{"__typename":"user","id":1,"username":"kataras","posts":[{"title":"Iris"},{"title":"GraphQL"}]}}}`)
+
+	// field errors.
+	expectJSON(t, e.POST("/graphql").WithJSON(iris.Map{"query": `{ users { username email } user(id: 3) { id } }`}).Expect().
+		Status(httptest.StatusOK),
+		`{"data":{"users":[{"username":"kataras","email":null},{"username":"makis","email":null}],"user":null},"errors":[`+
+			`{"message":"unauthorized","locations":[{"line":1,"column":20}],"path":["users",0,"email"]},`+
+			`{"message":"unauthorized","locations":[{"line":1,"column":20}],"path":["users",1,"email"]},`+
+			`{"message":"user not found","locations":[{"line":1,"column":28}],"path":["user"]}]}`)
+
+	expectJSON(t, e.POST("/graphql").WithHeader("Authorization", "Bearer token").
+		WithJSON(iris.Map{"query": `{ user(id: 2) { email } }`}).Expect().
+		Status(httptest.StatusOK),
+		`{"data":{"user":{"email":"makis@example.com"}}}`)
+
+	// mutations.
+	mutation := `mutation { rename(id: 2, username: "gerasimos") { id username } }`
+	e.GET("/graphql").WithQuery("query", mutation).Expect().Status(httptest.StatusBadRequest).
+		JSON().Object().Value("errors").Array().Element(0).Object().Value("message").Equal("mutations are not allowed on read-only requests")
+	expectJSON(t, e.POST("/graphql").WithHeader("Content-Type", "application/graphql").WithText(mutation).Expect().
+		Status(httptest.StatusOK),
+		`{"data":{"rename":{"id":2,"username":"gerasimos"}}}`)
+
+	// request errors.
+	e.POST("/graphql").WithJSON(iris.Map{"query": `{ users { `}).Expect().Status(httptest.StatusBadRequest).
+		JSON().Object().Value("errors").Array().Element(0).Object().Value("message").Equal("Syntax Error: unexpected <EOF>")
+	e.POST("/graphql").WithJSON(iris.Map{"query": `{ unknown }`}).Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("errors").Array().Element(0).Object().Value("message").Equal(`cannot query field "unknown" on type "Query"`)
+
+	// limits.
+	deep := strings.Repeat("{a", graphql.DefaultMaxDepth+1) + strings.Repeat("}", graphql.DefaultMaxDepth+1)
+	e.POST("/graphql").WithHeader("Content-Type", "application/graphql").WithText(deep).Expect().
+		Status(httptest.StatusBadRequest).JSON().Object().Value("errors").Array().Element(0).Object().
+		Value("message").Equal(fmt.Sprintf("Syntax Error: maximum nesting depth of %d exceeded", graphql.DefaultMaxDepth))
+	deep = `{ user(id: ` + strings.Repeat("[", graphql.DefaultMaxDepth) + strings.Repeat("]", graphql.DefaultMaxDepth) + `) { id } }`
+	e.POST("/graphql").WithHeader("Content-Type", "application/graphql").WithText(deep).Expect().
+		Status(httptest.StatusBadRequest).JSON().Object().Value("errors").Array().Element(0).Object().
+		Value("message").Equal(fmt.Sprintf("Syntax Error: maximum nesting depth of %d exceeded", graphql.DefaultMaxDepth))
+	e.POST("/graphql").WithHeader("Content-Type", "application/graphql").
+		WithText("{ users { " + strings.Repeat(" ", graphql.DefaultMaxBodySize) + "id } }").Expect().
+		Status(httptest.StatusRequestEntityTooLarge)
+
+	body := e.GET("/playground").Expect().Status(httptest.StatusOK).ContentType("text/html").Body().Raw()
+	if !strings.Contains(body, `var endpoint = "/playground";`) || !strings.Contains(body, graphql.DefaultPlaygroundCDN+"/graphiql@") {
+		t.Fatalf("expected playground to send queries to its path but got:\n%s", body)
+	}
+}
+
+func expectJSON(t *testing.T, resp *httpexpect.Response, expected string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(resp.Body().Raw())); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected response:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,428 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+)
+
+type (
+	// Args is the arguments of a field, as declared on the query.
+	// Variables are already resolved.
+	Args map[string]interface{}
+
+	// ResolveContext holds the information of the field which is being resolved.
+	// A resolver may accept it as input argument.
+	ResolveContext struct {
+		// Context is the current request's Iris Context.
+		Context context.Context
+		// Object is the parent object's type name, e.g. "Query" or "User".
+		Object string
+		// Field is the field name (not its alias).
+		Field string
+		// Path is the response path of the field, e.g. ["users", 0, "name"].
+		Path []interface{}
+		// Args is the field's arguments.
+		Args Args
+		// Parent is the parent object's value, nil for the root fields.
+		Parent interface{}
+	}
+
+	// Resolver resolves the value of a field.
+	Resolver func(rctx *ResolveContext) (interface{}, error)
+
+	// Middleware wraps a field's Resolver, e.g. to authorize a field access.
+	// A middleware may return an error without calling the "next" one,
+	// then the field's value is null and the error is reported on the response's "errors".
+	Middleware func(next Resolver) Resolver
+)
+
+// Schema describes the GraphQL operations (Query and Mutation root objects)
+// and the objects which their fields resolve to.
+// The Go types of the values a resolver returns are the GraphQL types;
+// their struct fields (by their "json" tag name or their case-insensitive name)
+// or map keys are resolved by default, use the `Schema.Object` method
+// to register custom field resolvers for a type.
+//
+// Schema introspection is not supported.
+type Schema struct {
+	// Query is the root object of the query operations.
+	Query *Object
+	// Mutation is the root object of the mutation operations.
+	Mutation *Object
+
+	mu      sync.RWMutex
+	objects map[reflect.Type]*Object
+}
+
+// NewSchema returns a new empty GraphQL Schema.
+//
+// Example Code:
+//  schema := graphql.NewSchema()
+//  schema.Query.Field("user", func(svc *UserService, args struct{ ID int }) (*User, error) {
+//      return svc.Get(args.ID)
+//  })
+//  schema.Object(User{}).Field("posts", func(u *User, svc *PostService) []Post {
+//      return svc.ListByUser(u.ID)
+//  })
+func NewSchema() *Schema {
+	return &Schema{
+		Query:    newObject("Query", nil),
+		Mutation: newObject("Mutation", nil),
+		objects:  make(map[reflect.Type]*Object),
+	}
+}
+
+// Object returns the object of the "v"'s type (a struct value or a pointer to it)
+// to register field resolvers. Its type name is the Go type's name.
+func (s *Schema) Object(v interface{}) *Object {
+	typ := indirectType(reflect.TypeOf(v))
+	if typ == nil || typ.Name() == "" {
+		panic(fmt.Sprintf("graphql: object: a named type is expected but got: %T", v))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[typ]
+	if !ok {
+		obj = newObject(typ.Name(), typ)
+		s.objects[typ] = obj
+	}
+
+	return obj
+}
+
+func (s *Schema) objectOf(typ reflect.Type) *Object {
+	s.mu.RLock()
+	obj := s.objects[typ]
+	s.mu.RUnlock()
+	return obj
+}
+
+// Object holds the custom field resolvers of a GraphQL object type.
+type Object struct {
+	// Name is the type name, used for the `__typename` field and the fragments' type conditions.
+	Name string
+
+	typ    reflect.Type // nil for the root objects.
+	fields map[string]*Field
+}
+
+func newObject(name string, typ reflect.Type) *Object {
+	return &Object{Name: name, typ: typ, fields: make(map[string]*Field)}
+}
+
+// Field registers a "resolver" for the "name" field of this Object.
+// The "resolver" can be a static value or a function which returns a value and optionally an error.
+// The function's input arguments are bound as follows:
+//  - context.Context (iris) to the current request's Context
+//  - *ResolveContext to the field's information
+//  - Args to the field's arguments
+//  - the Object's Go type (or a pointer to it) to the parent value
+//  - any dependency registered to the `Options.Container`
+//  - any other struct (or a pointer to it) to the field's arguments, validated by the `Application.Validator`.
+//
+// A nil "resolver" keeps the default field resolution, useful to register a field middleware only.
+func (o *Object) Field(name string, resolver interface{}) *Field {
+	f := &Field{Name: name}
+	if resolver != nil {
+		f.resolver = newResolverFunc(resolver, o.typ)
+	}
+
+	o.fields[name] = f
+	return f
+}
+
+// Field is a field of an `Object` with a custom resolver or middleware.
+type Field struct {
+	Name string
+
+	resolver   *resolverFunc // nil for the default resolver.
+	middleware []Middleware
+}
+
+// Use adds one or more middleware to this field's resolver.
+// They are executed after the `Options.Middleware` ones.
+func (f *Field) Use(middleware ...Middleware) *Field {
+	f.middleware = append(f.middleware, middleware...)
+	return f
+}
+
+//  +------------------------------------------------------------+
+//  | Resolver functions                                         |
+//  +------------------------------------------------------------+
+
+type inputKind uint8
+
+const (
+	contextInput inputKind = iota
+	resolveContextInput
+	argsInput
+	parentInput
+	dependencyInput
+)
+
+var (
+	contextType        = reflect.TypeOf((*context.Context)(nil)).Elem()
+	resolveContextType = reflect.TypeOf((*ResolveContext)(nil))
+	argsType           = reflect.TypeOf(Args{})
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type resolverFunc struct {
+	fn         reflect.Value // invalid for static values.
+	static     interface{}
+	inputs     []inputKind
+	parentType reflect.Type
+}
+
+func newResolverFunc(resolver interface{}, parentType reflect.Type) *resolverFunc {
+	fn := reflect.ValueOf(resolver)
+	if fn.Kind() != reflect.Func {
+		return &resolverFunc{static: resolver}
+	}
+
+	typ := fn.Type()
+	if n := typ.NumOut(); n == 0 || n > 2 || (n == 2 && typ.Out(1) != errorType) {
+		panic(fmt.Sprintf("graphql: resolver: %s: expected to return a value and optionally an error", typ))
+	}
+
+	r := &resolverFunc{fn: fn, parentType: parentType, inputs: make([]inputKind, typ.NumIn())}
+	for i := range r.inputs {
+		in := typ.In(i)
+		switch {
+		case in == contextType:
+			r.inputs[i] = contextInput
+		case in == resolveContextType:
+			r.inputs[i] = resolveContextInput
+		case in == argsType:
+			r.inputs[i] = argsInput
+		case parentType != nil && indirectType(in) == parentType:
+			r.inputs[i] = parentInput
+		default:
+			r.inputs[i] = dependencyInput
+		}
+	}
+
+	return r
+}
+
+func (r *resolverFunc) call(container *hero.Container, rctx *ResolveContext) (interface{}, error) {
+	if !r.fn.IsValid() {
+		return r.static, nil
+	}
+
+	typ := r.fn.Type()
+	inputs := make([]reflect.Value, len(r.inputs))
+	for i, kind := range r.inputs {
+		in := typ.In(i)
+
+		var (
+			v   reflect.Value
+			err error
+		)
+
+		switch kind {
+		case contextInput:
+			v = reflect.ValueOf(rctx.Context)
+		case resolveContextInput:
+			v = reflect.ValueOf(rctx)
+		case argsInput:
+			v = reflect.ValueOf(rctx.Args)
+		case parentInput:
+			v = convertValue(rctx.Parent, in)
+		default:
+			v, err = container.Resolve(rctx.Context, in)
+			if err == hero.ErrMissingDependency && indirectType(in).Kind() == reflect.Struct {
+				v, err = decodeArgs(rctx, in)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !v.IsValid() {
+			v = reflect.Zero(in)
+		}
+		inputs[i] = v
+	}
+
+	outputs := r.fn.Call(inputs)
+	if len(outputs) == 2 && !outputs[1].IsNil() {
+		return nil, outputs[1].Interface().(error)
+	}
+
+	return outputs[0].Interface(), nil
+}
+
+// decodeArgs binds the field's arguments to a new value of "typ" and validates it.
+func decodeArgs(rctx *ResolveContext, typ reflect.Type) (reflect.Value, error) {
+	b, err := json.Marshal(rctx.Args)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	ptr := reflect.New(indirectType(typ))
+	if err = json.Unmarshal(b, ptr.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if err = rctx.Context.Application().Validate(ptr.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		return ptr, nil
+	}
+
+	return ptr.Elem(), nil
+}
+
+// convertValue converts the "v" to "typ", they may differ on their pointer indirection.
+func convertValue(v interface{}, typ reflect.Type) reflect.Value {
+	val := reflect.ValueOf(v)
+	if !val.IsValid() {
+		return val
+	}
+
+	switch {
+	case val.Type() == typ:
+		return val
+	case val.Kind() == reflect.Ptr && val.Type().Elem() == typ:
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		return val.Elem()
+	case typ.Kind() == reflect.Ptr && typ.Elem() == val.Type():
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		return ptr
+	}
+
+	return val
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
+
+//  +------------------------------------------------------------+
+//  | Default field resolution                                   |
+//  +------------------------------------------------------------+
+
+var structFieldsCache sync.Map // map[reflect.Type]map[string][]int
+
+// structFieldIndex returns the index of the "name" field of "typ",
+// it matches the "json" tag name first and then the case-insensitive field name.
+func structFieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	var names map[string][]int
+	if v, ok := structFieldsCache.Load(typ); ok {
+		names = v.(map[string][]int)
+	} else {
+		names = make(map[string][]int)
+		for _, f := range visibleFields(typ, nil) {
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+
+			if idx := strings.IndexByte(tag, ','); idx != -1 {
+				tag = tag[:idx]
+			}
+
+			if tag != "" {
+				names[tag] = f.Index
+			}
+
+			lower := strings.ToLower(f.Name)
+			if _, exists := names[lower]; !exists {
+				names[lower] = f.Index
+			}
+		}
+
+		structFieldsCache.Store(typ, names)
+	}
+
+	if index, ok := names[name]; ok {
+		return index, true
+	}
+
+	index, ok := names[strings.ToLower(name)]
+	return index, ok
+}
+
+// visibleFields returns the exported fields of "typ", including the promoted ones.
+func visibleFields(typ reflect.Type, index []int) (fields []reflect.StructField) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		f.Index = append(append([]int(nil), index...), i)
+
+		if f.Anonymous && indirectType(f.Type).Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			fields = append(fields, visibleFields(indirectType(f.Type), f.Index)...)
+			continue
+		}
+
+		if f.PkgPath == "" {
+			fields = append(fields, f)
+		}
+	}
+
+	return
+}
+
+// defaultResolve resolves the "name" field of the "parent" struct or map.
+func defaultResolve(parent interface{}, object, name string) (interface{}, error) {
+	v := reflect.ValueOf(parent)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if value.IsValid() {
+				return value.Interface(), nil
+			}
+			return nil, nil
+		}
+	case reflect.Struct:
+		if index, ok := structFieldIndex(v.Type(), name); ok {
+			value, ok := fieldByIndex(v, index)
+			if !ok {
+				return nil, nil
+			}
+			return value.Interface(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot query field %q on type %q", name, object)
+}
+
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+
+	return v, true
+}
@@ -0,0 +1,96 @@
+package hero
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ErrAsyncTimeout is returned when an async result of a handler
+// was not resolved in time, see `Container.AsyncTimeout`.
+// The response status code is 504 Gateway Timeout.
+var ErrAsyncTimeout = errors.New("hero: async result timeout")
+
+var (
+	contextHandlerTyp          = reflect.TypeOf((func(context.Context))(nil))
+	contextHandlerWithErrorTyp = reflect.TypeOf((func(context.Context) error)(nil))
+)
+
+// isAsyncOutput reports whether a handler's output of type "typ" is an async result:
+// a channel, i.e <-chan Result or <-chan User, which is awaited for its value,
+// or a func(iris.Context) or func(iris.Context) error callback which is called to write the response.
+func isAsyncOutput(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Chan {
+		return typ.ChanDir()&reflect.RecvDir != 0
+	}
+
+	return typ == contextHandlerTyp || typ == contextHandlerWithErrorTyp
+}
+
+func hasAsyncOutput(fnTyp reflect.Type) bool {
+	for i := 0; i < fnTyp.NumOut(); i++ {
+		if isAsyncOutput(fnTyp.Out(i)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveAsync awaits the channel "outputs" for their values, up to the "timeout" (zero means no timeout)
+// or until the client is gone, and calls the callback ones.
+// The resolved values replace the async outputs, a closed channel without a value resolves to no output.
+func resolveAsync(ctx context.Context, outputs []reflect.Value, timeout time.Duration) error {
+	var timer *time.Timer
+	if timeout > 0 {
+		timer = time.NewTimer(timeout)
+		defer timer.Stop()
+	}
+
+	for i, out := range outputs {
+		if !isAsyncOutput(out.Type()) || out.IsNil() {
+			continue
+		}
+
+		if out.Kind() == reflect.Func {
+			outputs[i] = reflect.Value{}
+			if out.Type() == contextHandlerTyp {
+				out.Interface().(func(context.Context))(ctx)
+				continue
+			}
+
+			if err := out.Interface().(func(context.Context) error)(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: out},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Request().Context().Done())},
+		}
+		if timer != nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)})
+		}
+
+		chosen, v, ok := reflect.Select(cases)
+		switch chosen {
+		case 0:
+			if ok {
+				outputs[i] = v
+			} else {
+				outputs[i] = reflect.Value{}
+			}
+		case 1:
+			return ctx.Request().Context().Err()
+		default:
+			ctx.StatusCode(http.StatusGatewayTimeout)
+			return ErrAsyncTimeout
+		}
+	}
+
+	return nil
+}
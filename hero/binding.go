@@ -12,6 +12,8 @@ import (
 type binding struct {
 	Dependency *Dependency
 	Input      *Input
+	// payload reports whether the input is bound from the request body, see `payloadBinding`.
+	payload bool
 }
 
 // Input contains the input reference of which a dependency is binded to.
@@ -256,7 +258,7 @@ func getBindingsForStruct(v reflect.Value, dependencies []*Dependency, paramsCou
 		})
 	}
 
-	fields := lookupFields(elem, true, true, nil)
+	fields, requestFields := splitRequestFields(lookupFields(elem, true, true, nil))
 	n := len(fields)
 
 	if n > 1 && sorter != nil {
@@ -271,6 +273,9 @@ func getBindingsForStruct(v reflect.Value, dependencies []*Dependency, paramsCou
 		inputs[i] = fields[i].Type
 	}
 	exportedBindings := getBindingsFor(inputs, dependencies, paramsCount)
+	for _, f := range requestFields {
+		exportedBindings = append(exportedBindings, requestFieldBinding(f))
+	}
 
 	// fmt.Printf("Controller [%s] Inputs length: %d vs Bindings length: %d\n", typ, n, len(exportedBindings))
 	if len(nonZero) >= len(exportedBindings) { // if all are fields are defined then just return.
@@ -298,6 +303,81 @@ func getBindingsForStruct(v reflect.Value, dependencies []*Dependency, paramsCou
 	Builtin dynamic bindings.
 */
 
+// struct field tags of the request-scoped struct (e.g. controller) fields.
+const (
+	paramFieldTag = "param" // path parameter.
+	queryFieldTag = "query" // URL query parameter.
+)
+
+// splitRequestFields separates the struct fields which are tagged with a
+// path parameter (`param:"name"`) or a URL query parameter (`query:"name"`)
+// from the rest ones which are bound through the registered dependencies.
+func splitRequestFields(all []reflect.StructField) (fields, requestFields []reflect.StructField) {
+	for _, f := range all {
+		if _, _, ok := requestFieldTag(f); ok {
+			requestFields = append(requestFields, f)
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	return
+}
+
+func requestFieldTag(f reflect.StructField) (tag, name string, ok bool) {
+	for _, tag = range []string{paramFieldTag, queryFieldTag} {
+		if name = f.Tag.Get(tag); name != "" && name != "-" {
+			return tag, name, true
+		}
+	}
+
+	return "", "", false
+}
+
+// requestFieldBinding binds a struct field to a path or URL query parameter on each request,
+// the value is converted to the field's type. Missing parameters leave the field's zero value.
+func requestFieldBinding(f reflect.StructField) *binding {
+	tag, name, _ := requestFieldTag(f)
+
+	return &binding{
+		Dependency: &Dependency{
+			Handle: func(ctx context.Context, input *Input) (reflect.Value, error) {
+				var (
+					v   reflect.Value
+					err error
+				)
+
+				if tag == paramFieldTag {
+					entry, ok := ctx.Params().Store.GetEntry(name)
+					if !ok {
+						return emptyValue, ErrSeeOther
+					}
+
+					// the typed parameters are not parsed again.
+					v, err = convertParam(entry.ValueRaw, input.Type)
+				} else {
+					values, ok := ctx.Request().URL.Query()[name]
+					if !ok || len(values) == 0 {
+						return emptyValue, ErrSeeOther
+					}
+
+					v, err = convertString(values[0], input.Type)
+				}
+
+				if err != nil {
+					return emptyValue, fmt.Errorf("%s %q: %w", tag, name, err)
+				}
+
+				return v, nil
+			},
+			DestType: f.Type,
+			Source:   getSource(),
+		},
+		Input: newInput(f.Type, f.Index[0], f.Index),
+	}
+}
+
 func paramBinding(index, paramIndex int, typ reflect.Type) *binding {
 	return &binding{
 		Dependency: &Dependency{Handle: paramDependencyHandler(paramIndex), DestType: typ, Source: getSource()},
@@ -311,13 +391,54 @@ func paramDependencyHandler(paramIndex int) DependencyHandler {
 			return emptyValue, ErrSeeOther
 		}
 
-		return reflect.ValueOf(ctx.Params().Store[paramIndex].ValueRaw), nil
+		raw := ctx.Params().Store[paramIndex].ValueRaw
+		if v := reflect.ValueOf(raw); !v.IsValid() || v.Type() == input.Type {
+			return v, nil
+		}
+
+		return convertParam(raw, input.Type)
 	}
 }
 
+// BindingError is returned when a payload input failed to bind from one of the request's sources,
+// e.g. "body", "query", "headers" or "path parameters".
+type BindingError struct {
+	Source string
+	Err    error
+}
+
+func (e *BindingError) Error() string {
+	return fmt.Sprintf("binding %s: %v", e.Source, e.Err)
+}
+
+// Unwrap returns the underline error, e.g. a `context.ValidationErrors`.
+func (e *BindingError) Unwrap() error {
+	return e.Err
+}
+
+// payloadSources reports which request's sources, other than the body,
+// a payload input struct is bound from, based on its field tags.
+type payloadSources struct {
+	query   bool // `url:"name"`
+	headers bool // `header:"Name"`
+	params  bool // `param:"name"`
+}
+
+func (s payloadSources) any() bool {
+	return s.query || s.headers || s.params
+}
+
 // registered if input parameters are more than matched dependencies.
 // It binds an input to a request body based on the request content-type header (JSON, XML, YAML, Query, Form).
+// An input struct can combine fields from the body, the URL query (`url:"name"`),
+// the request headers (`header:"Name"`) and the path parameters (`param:"name"`), see `readPayload`.
 func payloadBinding(index int, typ reflect.Type) *binding {
+	sources := payloadSources{
+		query:   context.HasQueryFields(typ),
+		headers: context.HasHeaderFields(typ),
+		params:  context.HasParamFields(typ),
+	}
+
 	return &binding{
 		Dependency: &Dependency{
 			Handle: func(ctx context.Context, input *Input) (newValue reflect.Value, err error) {
@@ -325,7 +446,12 @@ func payloadBinding(index int, typ reflect.Type) *binding {
 
 				newValue = reflect.New(indirectType(input.Type))
 				ptr := newValue.Interface()
-				err = ctx.ReadBody(ptr)
+				if sources.any() {
+					err = readPayload(ctx, ptr, sources)
+				} else {
+					err = ctx.ReadBody(ptr)
+				}
+
 				if !wasPtr {
 					newValue = newValue.Elem()
 				}
@@ -334,7 +460,53 @@ func payloadBinding(index int, typ reflect.Type) *binding {
 			},
 			Source: getSource(),
 		},
-		Input: newInput(typ, index, nil),
+		Input:   newInput(typ, index, nil),
+		payload: true,
+	}
+}
+
+// readPayload binds a composite input struct in a single pass, with the following precedence (last wins):
+// body, URL query, headers and path parameters.
+// The query, headers and path parameters are decoded before the body too,
+// so the body's validation can see them. Errors are wrapped in a `BindingError`.
+func readPayload(ctx context.Context, ptr interface{}, sources payloadSources) error {
+	r := ctx.Request()
+
+	decode := func() error {
+		if sources.query {
+			if err := context.DecodeQueryValues(r.URL.Query(), ptr); err != nil {
+				return &BindingError{Source: "query", Err: err}
+			}
+		}
+
+		if sources.headers {
+			if err := context.DecodeHeaders(r.Header, ptr); err != nil {
+				return &BindingError{Source: "headers", Err: err}
+			}
+		}
+
+		if sources.params {
+			if err := context.DecodeParams(ctx.Params(), ptr); err != nil {
+				return &BindingError{Source: "path parameters", Err: err}
+			}
+		}
+
+		return nil
+	}
+
+	if err := decode(); err != nil {
+		return err
+	}
+
+	// the URL query is read by the body readers on GET requests,
+	// unless the input has its own query fields.
+	if r.ContentLength == 0 && (r.URL.RawQuery == "" || sources.query) {
+		return ctx.Application().Validate(ptr)
+	}
+
+	if err := ctx.ReadBody(ptr); err != nil {
+		return &BindingError{Source: "body", Err: err}
 	}
 
+	return decode()
 }
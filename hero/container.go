@@ -33,6 +33,11 @@ type Container struct {
 	// GetErrorHandler should return a valid `ErrorHandler` to handle bindings AND handler dispatch errors.
 	// Defaults to a functon which returns the `DefaultErrorHandler`.
 	GetErrorHandler func(context.Context) ErrorHandler // cannot be nil.
+	// AsyncTimeout is the maximum time to wait for the async results of the handlers,
+	// the channels they return, i.e func() <-chan Result. On timeout the `ErrAsyncTimeout` is handled
+	// by the ErrorHandler with a 504 status code.
+	// Defaults to zero, wait until the result is resolved or the client is gone.
+	AsyncTimeout time.Duration
 
 	// resultHandlers is a list of functions that serve the return struct value of a function handler.
 	// Defaults to "defaultResultHandler" but it can be overridden.
@@ -40,7 +45,7 @@ type Container struct {
 }
 
 // BuiltinDependencies is a list of builtin dependencies that are added on Container's initilization.
-// Contains the iris context, standard context, iris sessions and time dependencies.
+// Contains the iris context, standard context, iris sessions, time, time zone, formatter, client certificate, tenant and feature flags dependencies.
 var BuiltinDependencies = []*Dependency{
 	// iris context dependency.
 	NewDependency(func(ctx context.Context) context.Context { return ctx }).Explicitly(),
@@ -73,6 +78,18 @@ var BuiltinDependencies = []*Dependency{
 	NewDependency(func(ctx context.Context) http.Header {
 		return ctx.Request().Header
 	}).Explicitly(),
+	// current request's i18n locale dependency.
+	NewDependency(func(ctx context.Context) context.Locale {
+		return ctx.GetLocale()
+	}).Explicitly(),
+	// current request's time zone dependency.
+	NewDependency(func(ctx context.Context) *time.Location {
+		return ctx.GetTimezone()
+	}).Explicitly(),
+	// current request's dates and numbers formatter dependency.
+	NewDependency(func(ctx context.Context) context.Formatter {
+		return ctx.Formatter()
+	}).Explicitly(),
 	// payload and param bindings are dynamically allocated and declared at the end of the `binding` source file.
 }
 
@@ -104,6 +121,7 @@ func (c *Container) Clone() *Container {
 	cloned := New()
 	cloned.GetErrorHandler = c.GetErrorHandler
 	cloned.Sorter = c.Sorter
+	cloned.AsyncTimeout = c.AsyncTimeout
 	clonedDeps := make([]*Dependency, len(c.Dependencies))
 	copy(clonedDeps, c.Dependencies)
 	cloned.Dependencies = clonedDeps
@@ -197,6 +215,33 @@ func (c *Container) Struct(ptrValue interface{}, partyParamsCount int) *Struct {
 // when not a matching dependency found for "toPtr".
 var ErrMissingDependency = errors.New("missing dependency")
 
+// Resolve returns the value of the last registered dependency which matches the "typ",
+// request-scoped dependencies are resolved through the "ctx".
+// It returns an `ErrMissingDependency` if no matching dependency found.
+//
+// Useful for functions which bind their inputs at serve-time, e.g. the graphql resolvers.
+func (c *Container) Resolve(ctx context.Context, typ reflect.Type) (reflect.Value, error) {
+	for i := len(c.Dependencies) - 1; i >= 0; i-- {
+		d := c.Dependencies[i]
+		if !matchDependency(d, typ) {
+			continue
+		}
+
+		v, err := d.Handle(ctx, &Input{Type: typ})
+		if err != nil {
+			if err == ErrSeeOther {
+				continue
+			}
+
+			return reflect.Value{}, err
+		}
+
+		return v, nil
+	}
+
+	return reflect.Value{}, ErrMissingDependency
+}
+
 // Inject SHOULD only be used outside of HTTP handlers (performance is not priority for this method)
 // as it does not pre-calculate the available list of bindings for the "toPtr" and the registered dependencies.
 //
@@ -98,6 +98,39 @@ func TestContainerInject(t *testing.T) {
 	}
 }
 
+func TestContainerResolve(t *testing.T) {
+	c := New()
+	expected := &testServiceImpl{prefix: "prefix: "}
+	c.Register(expected)
+	c.Register(func(ctx iris.Context) testInput {
+		return testInput{Name: ctx.URLParam("name")}
+	})
+
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		svc, err := c.Resolve(ctx, reflect.TypeOf((*testService)(nil)).Elem())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := svc.Interface(); got != expected {
+			t.Fatalf("expected service: %#+v but got: %#+v", expected, got)
+		}
+
+		if _, err = c.Resolve(ctx, reflect.TypeOf(testOutput{})); err != ErrMissingDependency {
+			t.Fatalf("expected error: %v but got: %v", ErrMissingDependency, err)
+		}
+
+		in, err := c.Resolve(ctx, reflect.TypeOf(testInput{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx.WriteString(in.Interface().(testInput).Name)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithQuery("name", "kataras").Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+}
+
 func TestContainerUseResultHandler(t *testing.T) {
 	c := New()
 	resultLogger := func(next ResultHandler) ResultHandler {
@@ -0,0 +1,57 @@
+package hero
+
+import (
+	"reflect"
+)
+
+// FuncTypes returns the request payload and the response types of the "fn" function handler,
+// based on its input bindings and its output values. The "paramsCount" is the total path parameters
+// of the route, see `HandlerWithParams`. A nil type means that the handler does not read a payload
+// or does not respond with a value, e.g. a native `context.Handler`.
+//
+// Used to describe the routes for API clients code generation.
+func (c *Container) FuncTypes(fn interface{}, paramsCount int) (payload, response reflect.Type) {
+	if fn == nil {
+		return
+	}
+
+	if _, ok := isHandler(fn); ok {
+		return
+	}
+
+	if _, ok := isHandlerWithError(fn); ok {
+		return
+	}
+
+	v := valueOf(fn)
+	if !isFunc(v) {
+		return
+	}
+
+	for _, b := range getBindingsForFunc(v, c.Dependencies, paramsCount) {
+		if b.payload {
+			payload = b.Input.Type
+			break
+		}
+	}
+
+	typ := v.Type()
+	for i := 0; i < typ.NumOut(); i++ {
+		out := typ.Out(i)
+		if out.Kind() == reflect.Chan && isAsyncOutput(out) {
+			out = out.Elem()
+		}
+
+		switch {
+		case isError(out), isAsyncOutput(out):
+			continue
+		case out.Kind() == reflect.Int || out.Kind() == reflect.Bool: // status code or a "found" flag.
+			continue
+		}
+
+		response = out
+		break
+	}
+
+	return
+}
@@ -335,7 +335,10 @@ func dispatchCommon(ctx context.Context,
 
 	status := statusCode
 	if status == 0 {
-		status = 200
+		// respect any status code set by a previous handler, e.g. 404 on a NotFound controller method.
+		if status = ctx.GetStatusCode(); status == 0 {
+			status = 200
+		}
 	}
 
 	// write the status code, the rest will need that before any write ofc.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/context"
@@ -258,3 +259,49 @@ func TestPreflightResult(t *testing.T) {
 	e.POST("/alternative").WithJSON(testInput{expected4.Name}).
 		Expect().Status(httptest.StatusAccepted).JSON().Equal(expected4)
 }
+
+func TestFuncResultAsync(t *testing.T) {
+	app := iris.New()
+	c := app.ConfigureContainer()
+	c.Container.AsyncTimeout = 100 * time.Millisecond
+
+	work := make(chan func(), 1)
+	go func() { // a worker pool of one.
+		for fn := range work {
+			fn()
+		}
+	}()
+	defer close(work)
+
+	c.Get("/user", func() <-chan testCustomResult {
+		result := make(chan testCustomResult, 1)
+		work <- func() { result <- testCustomResult{HTML: "<b>kataras</b>"} }
+		return result
+	})
+	c.Get("/value", func() (<-chan interface{}, string) {
+		result := make(chan interface{}, 1)
+		work <- func() { result <- iris.Map{"name": "kataras"} }
+		return result, "application/json"
+	})
+	c.Get("/error", func() <-chan error {
+		result := make(chan error, 1)
+		work <- func() { result <- errors.New("failed") }
+		return result
+	})
+	c.Get("/timeout", func() <-chan string {
+		return make(chan string)
+	})
+	c.Get("/callback", func() func(iris.Context) error {
+		return func(ctx iris.Context) error {
+			_, err := ctx.WriteString("callback")
+			return err
+		}
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/user").Expect().Status(httptest.StatusOK).ContentType("text/html", "utf-8").Body().Equal("<b>kataras</b>")
+	e.GET("/value").Expect().Status(httptest.StatusOK).JSON().Equal(iris.Map{"name": "kataras"})
+	e.GET("/error").Expect().Status(httptest.StatusBadRequest).Body().Equal("failed")
+	e.GET("/timeout").Expect().Status(httptest.StatusGatewayTimeout).Body().Equal(ErrAsyncTimeout.Error())
+	e.GET("/callback").Expect().Status(httptest.StatusOK).Body().Equal("callback")
+}
@@ -2,6 +2,7 @@ package hero
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 
 	"github.com/kataras/iris/v12/context"
@@ -42,8 +43,17 @@ var (
 
 	// DefaultErrorHandler is the default error handler which is fired
 	// when a function returns a non-nil error or a request-scoped dependency failed to binded.
+	// Validation errors of a payload (see `Application.Validator`) are sent as a 422 problem,
+	// see `context.NewValidationProblem` too.
 	DefaultErrorHandler = ErrorHandlerFunc(func(ctx context.Context, err error) {
+		if errs, ok := context.AsValidationErrors(err); ok {
+			ctx.StopWithProblem(http.StatusUnprocessableEntity, context.NewValidationProblem(ctx, errs))
+			return
+		}
+
 		if err != ErrStopExecution {
+			ctx.Application().Log().Debug("hero: handler failed", "method", ctx.Method(), "path", ctx.Path(), "error", err)
+
 			if status := ctx.GetStatusCode(); status == 0 || !context.StatusCodeNotSuccessful(status) {
 				ctx.StatusCode(DefaultErrStatusCode)
 			}
@@ -84,6 +94,8 @@ func makeHandler(fn interface{}, c *Container, paramsCount int) context.Handler
 		resultHandler = c.resultHandlers[lidx-i](resultHandler)
 	}
 
+	async := hasAsyncOutput(v.Type())
+
 	return func(ctx context.Context) {
 		inputs := make([]reflect.Value, numIn)
 
@@ -107,6 +119,13 @@ func makeHandler(fn interface{}, c *Container, paramsCount int) context.Handler
 		}
 
 		outputs := v.Call(inputs)
+		if async {
+			if err := resolveAsync(ctx, outputs, c.AsyncTimeout); err != nil {
+				c.GetErrorHandler(ctx).HandleError(ctx, err)
+				return
+			}
+		}
+
 		if err := dispatchFuncResult(ctx, outputs, resultHandler); err != nil {
 			c.GetErrorHandler(ctx).HandleError(ctx, err)
 		}
@@ -1,7 +1,9 @@
 package hero_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/kataras/iris/v12"
@@ -241,3 +243,158 @@ func TestHandlerPathParams(t *testing.T) {
 		testReq.Expect().Status(httptest.StatusOK).Body().Equal("42")
 	}
 }
+
+// testFieldError and testValidationErrors act like the go-playground/validator's ones.
+type testFieldError struct {
+	field, tag string
+}
+
+func (e testFieldError) Error() string      { return e.field + " failed on the " + e.tag + " tag" }
+func (e testFieldError) Field() string      { return e.field }
+func (e testFieldError) Tag() string        { return e.tag }
+func (e testFieldError) Param() string      { return "" }
+func (e testFieldError) Value() interface{} { return nil }
+
+type testValidationErrors []testFieldError
+
+func (errs testValidationErrors) Error() string { return "validation failed" }
+
+type testValidator struct{}
+
+func (testValidator) Struct(v interface{}) error {
+	if u, ok := v.(*testUserStruct); ok && u.Username == "" {
+		return testValidationErrors{{field: "username", tag: "required"}}
+	}
+
+	return nil
+}
+
+func TestHandlerPayloadValidation(t *testing.T) {
+	app := iris.New()
+	app.Validator = testValidator{}
+
+	app.ConfigureContainer().Post("/", func(u testUserStruct) string {
+		return u.Username
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/").WithJSON(iris.Map{"username": "kataras"}).Expect().
+		Status(httptest.StatusOK).Body().Equal("kataras")
+
+	body := e.POST("/").WithJSON(iris.Map{"id": 42}).Expect().
+		Status(httptest.StatusUnprocessableEntity).
+		ContentType("application/problem+json", "utf-8").Body().Raw()
+
+	var problem struct {
+		Status int                    `json:"status"`
+		Errors []iris.ValidationError `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &problem); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []iris.ValidationError{{Field: "username", Tag: "required", Message: "username failed on the required tag"}}
+	if problem.Status != httptest.StatusUnprocessableEntity || !reflect.DeepEqual(problem.Errors, expected) {
+		t.Fatalf("expected validation errors: %#v but got: %s", expected, body)
+	}
+}
+
+type testHeadersPayload struct {
+	TenantID  string `header:"X-Tenant-ID" json:"-"`
+	RequestID int64  `header:"x-request-id" json:"-"`
+	Username  string `json:"username"`
+}
+
+func TestPayloadBindingHeaders(t *testing.T) {
+	app := iris.New()
+	c := app.ConfigureContainer()
+	c.Get("/", func(input testHeadersPayload) string {
+		return fmt.Sprintf("%s:%d", input.TenantID, input.RequestID)
+	})
+	c.Post("/", func(input *testHeadersPayload) string {
+		return fmt.Sprintf("%s:%d:%s", input.TenantID, input.RequestID, input.Username)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader("X-Tenant-ID", "acme").WithHeader("X-Request-ID", "42").Expect().
+		Status(httptest.StatusOK).Body().Equal("acme:42")
+	e.POST("/").WithHeader("X-Tenant-ID", "acme").WithHeader("X-Request-ID", "42").
+		WithJSON(iris.Map{"username": "kataras"}).Expect().
+		Status(httptest.StatusOK).Body().Equal("acme:42:kataras")
+	// type conversion failure.
+	e.GET("/").WithHeader("X-Request-ID", "NaN").Expect().Status(httptest.StatusBadRequest)
+}
+
+type testParamsPayload struct {
+	ID       int64  `param:"id" json:"id"`
+	Name     string `param:"name" json:"-"`
+	Username string `json:"username"`
+}
+
+func TestPayloadBindingParams(t *testing.T) {
+	app := iris.New()
+	c := app.ConfigureContainer()
+	c.Get("/users/{id:int64}/{name}", func(input testParamsPayload) string {
+		return fmt.Sprintf("%d:%s", input.ID, input.Name)
+	})
+	c.Put("/users/{id:int64}/{name}", func(input *testParamsPayload) string {
+		return fmt.Sprintf("%d:%s:%s", input.ID, input.Name, input.Username)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/users/42/kataras").Expect().Status(httptest.StatusOK).Body().Equal("42:kataras")
+	// path parameters take precedence over the body.
+	e.PUT("/users/42/kataras").WithJSON(iris.Map{"id": 1, "username": "makis"}).Expect().
+		Status(httptest.StatusOK).Body().Equal("42:kataras:makis")
+}
+
+type testCompositePayload struct {
+	ID       int64  `param:"id" json:"-"`
+	Page     int    `url:"page" json:"-"`
+	TenantID string `header:"X-Tenant-ID" json:"-"`
+	Username string `json:"username" url:"username"`
+}
+
+func TestPayloadBindingComposite(t *testing.T) {
+	app := iris.New()
+	app.ConfigureContainer().Post("/users/{id:int64}", func(input testCompositePayload) string {
+		return fmt.Sprintf("%d:%d:%s:%s", input.ID, input.Page, input.TenantID, input.Username)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/users/42").WithQuery("page", 2).WithHeader("X-Tenant-ID", "acme").
+		WithJSON(iris.Map{"username": "kataras"}).Expect().
+		Status(httptest.StatusOK).Body().Equal("42:2:acme:kataras")
+	// the URL query takes precedence over the body.
+	e.POST("/users/42").WithQuery("username", "makis").
+		WithJSON(iris.Map{"username": "kataras"}).Expect().
+		Status(httptest.StatusOK).Body().Equal("42:0::makis")
+	// descriptive binding errors.
+	e.POST("/users/42").WithQuery("page", "two").Expect().
+		Status(httptest.StatusBadRequest).Body().Contains("binding query: ")
+	e.POST("/users/42").WithBytes([]byte("{")).WithHeader("Content-Type", "application/json").Expect().
+		Status(httptest.StatusBadRequest).Body().Contains("binding body: ")
+}
+
+type testTypedParamsPayload struct {
+	ID      int32  `param:"id"`
+	Enabled bool   `param:"enabled"`
+	Code    uint16 `param:"code"`
+}
+
+func TestPayloadBindingTypedParams(t *testing.T) {
+	app := iris.New()
+	c := app.ConfigureContainer()
+	c.Get("/flags/{id:int64}/{enabled:bool}/{code:uint64}", func(input testTypedParamsPayload) string {
+		return fmt.Sprintf("%d:%v:%d", input.ID, input.Enabled, input.Code)
+	})
+	c.Get("/users/{id:int}", func(id int64) string {
+		return fmt.Sprintf("%d", id)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/flags/42/true/200").Expect().Status(httptest.StatusOK).Body().Equal("42:true:200")
+	// overflows the field's type.
+	e.GET("/flags/42/true/70000").Expect().Status(httptest.StatusBadRequest)
+	e.GET("/users/7").Expect().Status(httptest.StatusOK).Body().Equal("7")
+}
@@ -1,7 +1,11 @@
 package hero
 
 import (
+	"encoding"
+	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/kataras/iris/v12/context"
 )
@@ -232,3 +236,115 @@ func isNil(v reflect.Value) bool {
 		return false
 	}
 }
+
+var (
+	textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationTyp        = reflect.TypeOf(time.Duration(0))
+)
+
+// convertString converts a textual request value (e.g. a path or URL query parameter)
+// to a value of "typ".
+func convertString(s string, typ reflect.Type) (reflect.Value, error) {
+	if typ.Kind() == reflect.Ptr {
+		v, err := convertString(s, typ.Elem())
+		if err != nil {
+			return emptyValue, err
+		}
+
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(v)
+		return ptr, nil
+	}
+
+	if reflect.PtrTo(typ).Implements(textUnmarshalerTyp) {
+		ptr := reflect.New(typ)
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return emptyValue, err
+		}
+
+		return ptr.Elem(), nil
+	}
+
+	v := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return emptyValue, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typ == durationTyp {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return emptyValue, err
+			}
+			v.SetInt(int64(d))
+			break
+		}
+
+		n, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return emptyValue, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return emptyValue, err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return emptyValue, err
+		}
+		v.SetFloat(n)
+	default:
+		return emptyValue, fmt.Errorf("unsupported type: %s", typ)
+	}
+
+	return v, nil
+}
+
+// convertParam converts a path parameter's value to a value of "typ".
+// The values of the typed parameters (e.g. {id:uint64}) are already set by the macro system at match time,
+// so they are returned as they are, or converted to a number of the same sign, without parsing.
+// The rest are converted through their textual representation.
+func convertParam(raw interface{}, typ reflect.Type) (reflect.Value, error) {
+	if s, ok := raw.(string); ok {
+		return convertString(s, typ)
+	}
+
+	v := reflect.ValueOf(raw)
+	if !v.IsValid() {
+		return emptyValue, fmt.Errorf("unsupported type: %s", typ)
+	}
+
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+
+	if typ != durationTyp {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			switch typ.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if !reflect.Zero(typ).OverflowInt(v.Int()) {
+					return v.Convert(typ), nil
+				}
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			switch typ.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				if !reflect.Zero(typ).OverflowUint(v.Uint()) {
+					return v.Convert(typ), nil
+				}
+			}
+		}
+	}
+
+	return convertString(fmt.Sprintf("%v", raw), typ)
+}
@@ -149,3 +149,25 @@ func (s *Struct) MethodHandler(methodName string, paramsCount int) context.Handl
 
 	return makeHandler(m.Func, s.Container, paramsCount)
 }
+
+// StructField describes a struct field which is bound to a dependency,
+// see `Struct.BoundFields`.
+type StructField struct {
+	reflect.StructField
+	// Static reports whether the field's value is the same for all requests,
+	// otherwise it's resolved on each request.
+	Static bool
+}
+
+// BoundFields returns the struct fields which are bound to a dependency.
+func (s *Struct) BoundFields() []StructField {
+	fields := make([]StructField, 0, len(s.bindings))
+	for _, b := range s.bindings {
+		fields = append(fields, StructField{
+			StructField: s.elementType.FieldByIndex(b.Input.StructFieldIndex),
+			Static:      b.Dependency.Static,
+		})
+	}
+
+	return fields
+}
@@ -0,0 +1,59 @@
+package httptest
+
+import "github.com/gavv/httpexpect"
+
+// Form is a builder of a multipart form request body.
+//
+// Usage:
+//  httptest.NewForm().
+//      Field("title", "avatar").
+//      File("file", "avatar.png", contents).
+//      Apply(e.POST("/upload")).
+//      Expect().Status(httptest.StatusOK)
+type Form struct {
+	fields []formField
+	files  []formFile
+}
+
+type formField struct {
+	key   string
+	value interface{}
+}
+
+type formFile struct {
+	key      string
+	filename string
+	contents []byte
+}
+
+// NewForm returns a new, empty, multipart form builder.
+func NewForm() *Form {
+	return new(Form)
+}
+
+// Field adds a form field, values are converted to strings.
+func (f *Form) Field(key string, value interface{}) *Form {
+	f.fields = append(f.fields, formField{key, value})
+	return f
+}
+
+// File adds a file, with its filename and its contents, to the form.
+func (f *Form) File(key, filename string, contents []byte) *Form {
+	f.files = append(f.files, formFile{key, filename, contents})
+	return f
+}
+
+// Apply writes the form's fields and files to the "req" request as a multipart body.
+// Returns the "req" itself.
+func (f *Form) Apply(req *httpexpect.Request) *httpexpect.Request {
+	req = req.WithMultipart()
+	for _, field := range f.fields {
+		req = req.WithFormField(field.key, field.value)
+	}
+
+	for _, file := range f.files {
+		req = req.WithFileBytes(file.key, file.filename, file.contents)
+	}
+
+	return req
+}
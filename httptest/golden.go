@@ -0,0 +1,157 @@
+package httptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+)
+
+// UpdateGoldenFlag is the name of the test flag which,
+// when set, makes the `ExpectGolden` to write the golden files instead of comparing them,
+// i.e `go test -update`.
+const UpdateGoldenFlag = "update"
+
+func init() {
+	if flag.Lookup(UpdateGoldenFlag) == nil {
+		flag.Bool(UpdateGoldenFlag, false, "update the golden files of httptest.ExpectGolden")
+	}
+}
+
+func shouldUpdateGolden() bool {
+	f := flag.Lookup(UpdateGoldenFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// Normalizer replaces the volatile parts of a response body,
+// e.g. dates and generated IDs, before it's compared to a golden file.
+type Normalizer struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Normalize returns a Normalizer which replaces all matches of the "pattern"
+// with the "replacement", e.g. Normalize(`"id":\s*\d+`, `"id": "<id>"`).
+func Normalize(pattern, replacement string) Normalizer {
+	return Normalizer{Pattern: regexp.MustCompile(pattern), Replacement: replacement}
+}
+
+// DefaultNormalizers are the normalizers which are always applied by the `ExpectGolden`,
+// they replace RFC3339 dates with <date> and UUIDs with <uuid>.
+var DefaultNormalizers = []Normalizer{
+	Normalize(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`, "<date>"),
+	Normalize(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, "<uuid>"),
+}
+
+// ExpectGolden compares the "resp" body with the contents of the "filename" golden file.
+// JSON bodies are indented and all bodies are normalized through the `DefaultNormalizers`
+// and the given "normalizers" before the comparison.
+// When the test runs with the -update flag the golden file is written instead.
+// On mismatch the test fails with a line diff.
+//
+// Usage:
+//  resp := e.GET("/users").Expect().Status(httptest.StatusOK)
+//  httptest.ExpectGolden(t, resp, "testdata/users_list.json",
+//      httptest.Normalize(`"id":\s*\d+`, `"id": "<id>"`))
+func ExpectGolden(t *testing.T, resp *httpexpect.Response, filename string, normalizers ...Normalizer) {
+	t.Helper()
+
+	got := normalizeGolden([]byte(resp.Body().Raw()), normalizers)
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+
+		if err := ioutil.WriteFile(filename, got, 0644); err != nil {
+			t.Fatalf("golden: %v", err)
+		}
+
+		t.Logf("golden: %s updated", filename)
+		return
+	}
+
+	expected, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("golden: %s does not exist, run the test with the -%s flag to create it", filename, UpdateGoldenFlag)
+		}
+		t.Fatalf("golden: %v", err)
+	}
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("golden: response body does not match %s (-expected +got):\n%s", filename, diffLines(string(expected), string(got)))
+	}
+}
+
+func normalizeGolden(body []byte, normalizers []Normalizer) []byte {
+	if json.Valid(body) {
+		buf := new(bytes.Buffer)
+		if err := json.Indent(buf, body, "", "  "); err == nil {
+			body = buf.Bytes()
+		}
+	}
+
+	for _, n := range DefaultNormalizers {
+		body = n.Pattern.ReplaceAll(body, []byte(n.Replacement))
+	}
+
+	for _, n := range normalizers {
+		body = n.Pattern.ReplaceAll(body, []byte(n.Replacement))
+	}
+
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		body = append(body, '\n')
+	}
+
+	return body
+}
+
+// diffLines returns the lines which differ between "a" and "b",
+// prefixed by "-" and "+" respectively, based on their longest common subsequence.
+func diffLines(a, b string) string {
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+	n, m := len(x), len(y)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && x[i] == y[j]:
+			i++
+			j++
+		case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + x[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + y[j] + "\n")
+			j++
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,55 @@
+package httptest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+)
+
+func TestExpectGolden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	id := 1
+	app := iris.New()
+	app.Get("/users", func(ctx iris.Context) {
+		id++
+		ctx.JSON([]iris.Map{{"id": id, "name": "kataras", "created": time.Now()}})
+	})
+
+	e := New(t, app)
+	filename := filepath.Join(dir, "testdata", "users_list.json")
+	normalizeID := Normalize(`"id":\s*\d+`, `"id": "<id>"`)
+
+	flag.Set(UpdateGoldenFlag, "true")
+	ExpectGolden(t, e.GET("/users").Expect().Status(StatusOK), filename, normalizeID)
+	flag.Set(UpdateGoldenFlag, "false")
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[\n  {\n    \"created\": \"<date>\",\n    \"id\": \"<id>\",\n    \"name\": \"kataras\"\n  }\n]\n"
+	if got := string(b); expected != got {
+		t.Fatalf("expected golden file:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	// the id and the date are different now.
+	ExpectGolden(t, e.GET("/users").Expect().Status(StatusOK), filename, normalizeID)
+}
+
+func TestGoldenDiff(t *testing.T) {
+	expected := "- b\n+ c\n+ e\n"
+	if got := diffLines("a\nb\nd", "a\nc\nd\ne"); expected != got {
+		t.Fatalf("expected diff:\n%s\nbut got:\n%s", expected, got)
+	}
+}
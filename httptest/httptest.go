@@ -3,6 +3,7 @@ package httptest
 import (
 	"crypto/tls"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/kataras/iris/v12"
@@ -78,6 +79,11 @@ func DefaultConfiguration() *Configuration {
 }
 
 // New Prepares and returns a new test framework based on the "app".
+// Requests are served by the "app" in-memory, no listener is required.
+// Cookies are kept across the requests of the same returned Expect instance,
+// websocket requests (see `Request.WithWebsocketUpgrade`) are dialed in-memory too
+// and multipart bodies can be built through `NewForm`.
+//
 // You can find example on the https://github.com/kataras/iris/tree/master/_examples/testing/httptest
 func New(t *testing.T, app *iris.Application, setters ...OptionSetter) *httpexpect.Expect {
 	conf := DefaultConfiguration()
@@ -99,9 +105,11 @@ func New(t *testing.T, app *iris.Application, setters ...OptionSetter) *httpexpe
 		BaseURL: conf.URL,
 		Client: &http.Client{
 			Transport: httpexpect.NewBinder(app),
-			Jar:       httpexpect.NewJar(),
+			Jar:       relativeJar{httpexpect.NewJar()},
 		},
-		Reporter: httpexpect.NewAssertReporter(t),
+		// dials the websocket requests to the app, without a real network connection.
+		WebsocketDialer: newWebsocketDialer(app),
+		Reporter:        httpexpect.NewAssertReporter(t),
 	}
 
 	if conf.Debug {
@@ -113,6 +121,31 @@ func New(t *testing.T, app *iris.Application, setters ...OptionSetter) *httpexpe
 	return httpexpect.WithConfig(testConfiguration)
 }
 
+// relativeJar keeps the cookies of the requests without a base URL (the default),
+// the standard cookie jar ignores URLs without a scheme and a host.
+type relativeJar struct {
+	http.CookieJar
+}
+
+func (j relativeJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(absoluteURL(u), cookies)
+}
+
+func (j relativeJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.CookieJar.Cookies(absoluteURL(u))
+}
+
+func absoluteURL(u *url.URL) *url.URL {
+	if u.Host != "" {
+		return u
+	}
+
+	abs := *u
+	abs.Scheme = "http"
+	abs.Host = "localhost"
+	return &abs
+}
+
 // NewInsecure same as New but receives a single host instead of the whole framework.
 // Useful for testing running TLS servers.
 func NewInsecure(t *testing.T, setters ...OptionSetter) *httpexpect.Expect {
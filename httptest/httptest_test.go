@@ -0,0 +1,66 @@
+package httptest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/websocket"
+)
+
+func TestNew(t *testing.T) {
+	app := iris.New()
+	app.Get("/login", func(ctx iris.Context) {
+		ctx.SetCookieKV("session", "token")
+	})
+	app.Get("/me", func(ctx iris.Context) {
+		ctx.WriteString(ctx.GetCookie("session"))
+	})
+	app.Post("/upload", func(ctx iris.Context) {
+		file, header, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.StopWithError(iris.StatusBadRequest, err)
+			return
+		}
+		defer file.Close()
+
+		contents, _ := ioutil.ReadAll(file)
+		ctx.JSON(iris.Map{
+			"title":    ctx.FormValue("title"),
+			"filename": header.Filename,
+			"contents": string(contents),
+		})
+	})
+
+	ws := websocket.New(websocket.DefaultGorillaUpgrader, websocket.Events{
+		websocket.OnNativeMessage: func(nsConn *websocket.NSConn, msg websocket.Message) error {
+			nsConn.Conn.Write(msg) // echo.
+			return nil
+		},
+	})
+	app.Get("/echo", websocket.Handler(ws))
+
+	e := New(t, app)
+
+	// cookies are kept across requests.
+	e.GET("/login").Expect().Status(StatusOK)
+	e.GET("/me").Expect().Status(StatusOK).Body().Equal("token")
+
+	obj := NewForm().Field("title", "notes").File("file", "notes.txt", []byte("contents")).
+		Apply(e.POST("/upload")).Expect().Status(StatusOK).JSON()
+	obj.Schema(`{
+		"type": "object",
+		"properties": {
+			"title":    {"type": "string"},
+			"filename": {"type": "string"},
+			"contents": {"type": "string"}
+		},
+		"required": ["title", "filename", "contents"]
+	}`)
+	obj.Object().ContainsMap(map[string]interface{}{"title": "notes", "filename": "notes.txt", "contents": "contents"})
+
+	conn := e.GET("/echo").WithWebsocketUpgrade().Expect().Status(StatusSwitchingProtocols).Websocket()
+	defer conn.Disconnect()
+
+	conn.WriteText("hello").Expect().TextMessage().Body().Equal("hello")
+}
@@ -0,0 +1,60 @@
+package httptest
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	nethttptest "net/http/httptest"
+
+	"github.com/gavv/httpexpect"
+)
+
+// newWebsocketDialer returns a websocket dialer which dials to the "handler"
+// through an in-memory connection. Unlike the httpexpect's one, it stops serving requests
+// once the connection is hijacked, so handlers which upgrade and return
+// (i.e the neffos-based websocket.Handler) can read the websocket frames.
+func newWebsocketDialer(handler http.Handler) httpexpect.WebsocketDialer {
+	dialer := httpexpect.NewWebsocketDialer(handler)
+	dialer.NetDial = func(network, addr string) (net.Conn, error) {
+		dialConn, backConn := net.Pipe()
+		go serveConn(handler, backConn)
+		return dialConn, nil
+	}
+
+	return dialer
+}
+
+func serveConn(handler http.Handler, conn net.Conn) {
+	br := bufio.NewReader(conn)
+	for {
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		w := &hijackRecorder{ResponseRecorder: nethttptest.NewRecorder(), conn: conn, br: br}
+		handler.ServeHTTP(w, req)
+		if w.hijacked { // the connection is owned by the handler now.
+			return
+		}
+
+		if err = w.Result().Write(conn); err != nil {
+			conn.Close()
+			return
+		}
+	}
+}
+
+type hijackRecorder struct {
+	*nethttptest.ResponseRecorder
+	conn     net.Conn
+	br       *bufio.Reader
+	hijacked bool
+}
+
+// Hijack implements the http.Hijacker interface.
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return r.conn, bufio.NewReadWriter(r.br, bufio.NewWriter(r.conn)), nil
+}
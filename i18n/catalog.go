@@ -0,0 +1,153 @@
+package i18n
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+type (
+	// CatalogLoader loads the translations of all languages from a message catalog backend,
+	// i.e. a database table, a cloud storage bucket or a translation service.
+	// The result maps a language code to its (nested or flat) key-value translations.
+	//
+	// See `Catalog`, `SQLCatalog` and `RemoteCatalog` package-level functions.
+	CatalogLoader interface {
+		LoadCatalog() (map[string]map[string]interface{}, error)
+	}
+
+	// CatalogLoaderFunc is a function which implements the `CatalogLoader` interface.
+	CatalogLoaderFunc func() (map[string]map[string]interface{}, error)
+)
+
+// LoadCatalog calls the "fn" itself.
+func (fn CatalogLoaderFunc) LoadCatalog() (map[string]map[string]interface{}, error) {
+	return fn()
+}
+
+// Catalog returns a `Loader` which loads the locales from a `CatalogLoader`.
+// Use the `I18n.Refresh` method to fetch the updated translations periodically.
+//
+// Example Code:
+//  app.I18n.Reset(i18n.Catalog(i18n.SQLCatalog(db, "translations")), "en-US", "el-GR")
+//  app.I18n.Refresh(time.Minute, nil)
+func Catalog(loader CatalogLoader, options ...LoaderOption) Loader {
+	c := newLoaderConfig(options...)
+
+	return func(m *Matcher) (Localizer, error) {
+		catalog, err := loader.LoadCatalog()
+		if err != nil {
+			return nil, err
+		}
+
+		locales := make(MemoryLocalizer)
+
+		for lang, keyValues := range catalog {
+			tag, err := language.Parse(lang)
+			if err != nil {
+				if c.Strict {
+					return nil, err
+				}
+				continue
+			}
+
+			_, langIndex, conf := m.MatchOrAdd(tag)
+			if conf <= language.Low {
+				continue
+			}
+
+			loc, err := c.newLocale(m, langIndex, keyValues)
+			if err != nil {
+				return nil, err
+			}
+
+			locales[langIndex] = loc
+		}
+
+		if n := len(locales); n == 0 {
+			return nil, fmt.Errorf("locales not found in catalog")
+		} else if c.Strict && n < len(m.Languages) {
+			return nil, fmt.Errorf("locales expected to be %d but %d parsed", len(m.Languages), n)
+		}
+
+		return locales, nil
+	}
+}
+
+// SQLCatalog returns a `CatalogLoader` which reads the translations
+// from a database "table" of "lang", "name" and "value" text columns,
+// where the "name" column is the message key.
+// Keys may be separated by dots, i.e. "nav.home" or "items.one".
+func SQLCatalog(db *sql.DB, table string) CatalogLoader {
+	query := "SELECT lang, name, value FROM " + table
+
+	return CatalogLoaderFunc(func() (map[string]map[string]interface{}, error) {
+		rows, err := db.Query(query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		catalog := make(map[string]map[string]interface{})
+		for rows.Next() {
+			var lang, key, value string
+			if err = rows.Scan(&lang, &key, &value); err != nil {
+				return nil, err
+			}
+
+			keyValues, ok := catalog[lang]
+			if !ok {
+				keyValues = make(map[string]interface{})
+				catalog[lang] = keyValues
+			}
+			keyValues[key] = value
+		}
+
+		return catalog, rows.Err()
+	})
+}
+
+// RemoteCatalog returns a `CatalogLoader` which fetches the translations through a GET request to the "url",
+// i.e. a translation service's export endpoint or a (presigned) cloud storage object URL.
+// The response body should be a JSON object of language codes and their key-value translations, e.g.
+//  {"en-US": {"hi": "Hello"}, "el-GR": {"hi": "Γειά"}}
+//
+// The optional "header" is sent on each request, i.e. for authorization.
+func RemoteCatalog(url string, header http.Header) CatalogLoader {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	return CatalogLoaderFunc(func() (map[string]map[string]interface{}, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			b, _ := ioutil.ReadAll(resp.Body)
+			return nil, fmt.Errorf("remote catalog: %s: %d: %s", url, resp.StatusCode, strings.TrimSpace(string(b)))
+		}
+
+		var catalog map[string]map[string]interface{}
+		if err = json.NewDecoder(resp.Body).Decode(&catalog); err != nil {
+			return nil, fmt.Errorf("remote catalog: %s: %w", url, err)
+		}
+
+		return catalog, nil
+	})
+}
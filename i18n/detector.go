@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Detector extracts a language code (or an Accept-Language header value) from the request.
+// It returns an empty string if not found.
+//
+// See the `I18n.Detectors` field and the `DetectPath`, `DetectURLParam`,
+// `DetectCookie`, `DetectSubdomain` and `DetectHeader` package-level functions.
+type Detector func(ctx context.Context) string
+
+type pathLanguageContextKey struct{}
+
+// pathLanguage returns the language path prefix, as requested by the client,
+// which is removed by the i18n router wrapper.
+func pathLanguage(ctx context.Context) (string, bool) {
+	prefix, ok := ctx.Request().Context().Value(pathLanguageContextKey{}).(string)
+	return prefix, ok
+}
+
+// DetectPath returns a `Detector` which extracts the language
+// from the path prefix, e.g. /el-GR/$path.
+// It requires the `I18n.PathRedirect` field.
+func DetectPath() Detector {
+	return func(ctx context.Context) string {
+		prefix, _ := pathLanguage(ctx)
+		return prefix
+	}
+}
+
+// DetectURLParam returns a `Detector` which extracts the language
+// from the URL query parameter of "name", e.g. ?lang=el-GR.
+func DetectURLParam(name string) Detector {
+	return func(ctx context.Context) string {
+		return ctx.URLParam(name)
+	}
+}
+
+// DetectCookie returns a `Detector` which extracts the language
+// from the cookie of "name".
+func DetectCookie(name string) Detector {
+	return func(ctx context.Context) string {
+		return ctx.GetCookie(name)
+	}
+}
+
+// DetectSubdomain returns a `Detector` which extracts the language
+// from the subdomain, e.g. el.mydomain.com.
+func DetectSubdomain() Detector {
+	return func(ctx context.Context) string {
+		return ctx.Subdomain()
+	}
+}
+
+// DetectHeader returns a `Detector` which extracts the languages
+// from the Accept-Language request header.
+func DetectHeader() Detector {
+	return func(ctx context.Context) string {
+		return ctx.GetHeader(acceptLanguageHeaderKey)
+	}
+}
+
+// CanonicalRedirectHandler returns a handler which redirects the requests to their canonical localized URL.
+// Iris registers it automatically when the `CanonicalRedirect` field is true.
+func (i *I18n) CanonicalRedirectHandler() context.Handler {
+	return func(ctx context.Context) {
+		if method := ctx.Method(); !i.Loaded() || (method != http.MethodGet && method != http.MethodHead) {
+			ctx.Next()
+			return
+		}
+
+		loc := ctx.GetLocale()
+		if loc == nil {
+			ctx.Next()
+			return
+		}
+
+		prefix, hasPrefix := pathLanguage(ctx)
+		if (hasPrefix && prefix != loc.Language()) || (!hasPrefix && loc.Index() > 0) {
+			target := "/" + loc.Language() + ctx.Path()
+			if query := ctx.Request().URL.RawQuery; query != "" {
+				target += "?" + query
+			}
+
+			ctx.Redirect(target, http.StatusFound)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Path returns the "path" prefixed with the current request's language,
+// the default language is not prefixed.
+// See `router.RoutePathReverser.LocalePath` too.
+func (i *I18n) Path(ctx context.Context, path string) string {
+	loc := ctx.GetLocale()
+	if loc == nil || loc.Index() == 0 {
+		return path
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return "/" + loc.Language() + path
+}
@@ -3,11 +3,13 @@
 package i18n
 
 import (
+	stdContext "context"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/router"
@@ -37,7 +39,10 @@ type I18n struct {
 	matcher   *Matcher
 
 	loader Loader
-	mu     sync.Mutex
+	mu     sync.RWMutex
+	// reloadMu serializes the Reset, Reload and SetDefault calls,
+	// the locales are loaded without blocking the serving requests.
+	reloadMu sync.Mutex
 
 	// ExtractFunc is the type signature for declaring custom logic
 	// to extract the language tag name.
@@ -58,6 +63,42 @@ type I18n struct {
 	// If true then it will return empty string when translation for a a specific language's key was not found.
 	// Defaults to false, fallback defaultLang:key will be used.
 	Strict bool
+	// Fallbacks maps a language code to an ordered list of language codes
+	// which are tried when a translation for a specific language's key was not found,
+	// e.g. {"pt-BR": {"pt-PT", "es-ES"}}.
+	// The default language is tried last, unless `Strict` is true.
+	//
+	// Defaults to nil.
+	Fallbacks map[string][]string
+	// MissingKeyFunc is fired when a translation for a specific language's key was not found,
+	// before the fallback languages are tried. Useful to report the missing translations to translators.
+	// It should not block.
+	//
+	// Defaults to nil.
+	MissingKeyFunc func(lang, key string)
+
+	// Detectors is the ordered list of the locale detection strategies,
+	// the first one which returns a registered language wins.
+	// If empty then the `ExtractFunc`, `URLParameter`, `Cookie`, `Subdomain` fields
+	// and the Accept-Language header are checked, in that order.
+	//
+	// Example Code:
+	//  app.I18n.Detectors = []i18n.Detector{
+	//      i18n.DetectPath(),
+	//      i18n.DetectURLParam("lang"),
+	//      i18n.DetectCookie("lang"),
+	//      i18n.DetectHeader(),
+	//  }
+	//
+	// Defaults to nil.
+	Detectors []Detector
+	// If true then Iris will redirect the requests to their canonical localized URL:
+	// 1. /$path to /$lang/$path when the detected language is not the default one.
+	// 2. /$lang_prefix/$path to /$lang/$path when the $lang_prefix is not the exact registered language, e.g. /el/ to /el-GR/.
+	// Only GET and HEAD requests are redirected. It requires the `PathRedirect` field.
+	//
+	// Defaults to false.
+	CanonicalRedirect bool
 
 	// If true then Iris will wrap its router with the i18n router wrapper on its Build state.
 	// It will (local) redirect requests like:
@@ -112,39 +153,123 @@ func (i *I18n) LoadAssets(assetNames func() []string, asset func(string) ([]byte
 // a custom `Loader` must be used instead of the default one.
 func (i *I18n) Reset(loader Loader, languages ...string) error {
 	tags := makeTags(languages...)
-
-	i.loader = loader
-	i.matcher = &Matcher{
+	matcher := &Matcher{
 		strict:    len(tags) > 0,
 		Languages: tags,
 		matcher:   language.NewMatcher(tags),
 	}
 
-	return i.reload()
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	return i.load(loader, matcher)
 }
 
-// reload loads the language files from the provided Loader,
-// the `New` package-level function preloads those files already.
-func (i *I18n) reload() error { // May be an exported function, if requested.
-	i.mu.Lock()
-	defer i.mu.Unlock()
+// Reload loads the locales from the provided Loader again,
+// i.e. to fetch the updated translations of a `Catalog` loader.
+// On failure the current locales are kept.
+// It is safe to be called while serving requests.
+//
+// See `Refresh` too.
+func (i *I18n) Reload() error {
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	i.mu.RLock()
+	loader, matcher := i.loader, i.matcher
+	i.mu.RUnlock()
 
-	if i.loader == nil {
+	if loader == nil {
 		return fmt.Errorf("nil loader")
 	}
 
-	localizer, err := i.loader(i.matcher)
+	return i.load(loader, matcher)
+}
+
+// load loads the locales on a copy of the "matcher", without holding the lock,
+// and replaces the current ones on success. Callers should hold the reloadMu.
+func (i *I18n) load(loader Loader, matcher *Matcher) error {
+	// the current matcher is in use by the serving requests.
+	matcher = &Matcher{
+		strict:    matcher.strict,
+		Languages: append([]language.Tag(nil), matcher.Languages...),
+		matcher:   matcher.matcher,
+	}
+
+	localizer, err := loader(matcher)
 	if err != nil {
 		return err
 	}
 
+	i.mu.Lock()
+	i.loader = loader
 	i.localizer = localizer
+	i.matcher = matcher
+	i.mu.Unlock()
 	return nil
 }
 
+// Refresh calls `Reload` every "interval", on failure the "onError" is called, if not nil.
+// It returns a function which stops refreshing.
+//
+// Example Code:
+//  stop := app.I18n.Refresh(5*time.Minute, func(err error) {
+//      app.Logger().Errorf("i18n: refresh: %v", err)
+//  })
+//  defer stop()
+func (i *I18n) Refresh(interval time.Duration, onError func(error)) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := i.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+func (i *I18n) getLocalizer() Localizer {
+	i.mu.RLock()
+	l := i.localizer
+	i.mu.RUnlock()
+	return l
+}
+
+func (i *I18n) getMatcher() *Matcher {
+	i.mu.RLock()
+	m := i.matcher
+	i.mu.RUnlock()
+	return m
+}
+
 // Loaded reports whether `New` or `Load/LoadAssets` called.
 func (i *I18n) Loaded() bool {
-	return i != nil && i.loader != nil && i.localizer != nil && i.matcher != nil
+	if i == nil {
+		return false
+	}
+
+	i.mu.RLock()
+	loaded := i.loader != nil && i.localizer != nil && i.matcher != nil
+	i.mu.RUnlock()
+	return loaded
 }
 
 // Tags returns the registered languages or dynamically resolved by files.
@@ -154,7 +279,7 @@ func (i *I18n) Tags() []language.Tag {
 		return nil
 	}
 
-	return i.matcher.Languages
+	return i.getMatcher().Languages
 }
 
 // SetDefault changes the default language.
@@ -166,24 +291,52 @@ func (i *I18n) SetDefault(langCode string) bool {
 		return false
 	}
 
-	if tag, index, conf := i.matcher.Match(t); conf > language.Low {
-		if l, ok := i.localizer.(interface {
-			SetDefault(int) bool
-		}); ok {
-			if l.SetDefault(index) {
-				tags := i.matcher.Languages
-				// set the order
-				tags[index] = tags[0]
-				tags[0] = tag
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
 
-				i.matcher.Languages = tags
-				i.matcher.matcher = language.NewMatcher(tags)
-				return true
-			}
+	i.mu.RLock()
+	localizer, matcher := i.localizer, i.matcher
+	i.mu.RUnlock()
+
+	if matcher == nil {
+		return false
+	}
+
+	tag, index, conf := matcher.Match(t)
+	if conf <= language.Low {
+		return false
+	}
+
+	// change a copy, the current localizer and matcher are in use by the serving requests.
+	if m, ok := localizer.(MemoryLocalizer); ok {
+		c := make(MemoryLocalizer, len(m))
+		for idx, loc := range m {
+			c[idx] = loc
 		}
+		localizer = c
+	}
+
+	l, ok := localizer.(interface {
+		SetDefault(int) bool
+	})
+	if !ok || !l.SetDefault(index) {
+		return false
 	}
 
-	return false
+	tags := append([]language.Tag(nil), matcher.Languages...)
+	// set the order
+	tags[index] = tags[0]
+	tags[0] = tag
+
+	i.mu.Lock()
+	i.localizer = localizer
+	i.matcher = &Matcher{
+		strict:    matcher.strict,
+		Languages: tags,
+		matcher:   language.NewMatcher(tags),
+	}
+	i.mu.Unlock()
+	return true
 }
 
 // Matcher implements the languae.Matcher.
@@ -275,7 +428,7 @@ func parseLanguage(path string) (language.Tag, bool) {
 // It returns -1 as the language index and false if not found.
 func (i *I18n) TryMatchString(s string) (language.Tag, int, bool) {
 	if tag, err := language.Parse(s); err == nil {
-		if tag, index, conf := i.matcher.Match(tag); conf > language.Low {
+		if tag, index, conf := i.getMatcher().Match(tag); conf > language.Low {
 			return tag, index, true
 		}
 	}
@@ -293,20 +446,66 @@ func (i *I18n) Tr(lang, format string, args ...interface{}) string {
 		index = 0
 	}
 
-	loc := i.localizer.GetLocale(index)
+	loc := i.getLocalizer().GetLocale(index)
 	if loc != nil {
-		msg := loc.GetMessage(format, args...)
-		if msg == "" && !i.Strict && index > 0 {
-			// it's not the default/fallback language and not message found for that lang:key.
-			return i.localizer.GetLocale(0).GetMessage(format, args...)
-		}
-		return msg
+		return i.getMessage(loc, format, args...)
 	}
 
 	return fmt.Sprintf(format, args...)
 }
 
-const acceptLanguageHeaderKey = "Accept-Language"
+// getMessage returns the "loc" translation of the "key",
+// if not found then it walks through the `Fallbacks` of that language and the default one.
+func (i *I18n) getMessage(loc context.Locale, key string, args ...interface{}) string {
+	if msg := loc.GetMessage(key, args...); msg != "" {
+		return msg
+	}
+
+	if i.MissingKeyFunc != nil {
+		i.MissingKeyFunc(loc.Language(), key)
+	}
+
+	for lang, fallbacks := range i.Fallbacks {
+		if _, index, ok := i.TryMatchString(lang); !ok || index != loc.Index() {
+			continue
+		}
+
+		for _, fallback := range fallbacks {
+			_, index, ok := i.TryMatchString(fallback)
+			if !ok || index == loc.Index() {
+				continue
+			}
+
+			if fallbackLoc := i.getLocalizer().GetLocale(index); fallbackLoc != nil {
+				if msg := fallbackLoc.GetMessage(key, args...); msg != "" {
+					return msg
+				}
+			}
+		}
+	}
+
+	if !i.Strict && loc.Index() > 0 {
+		// it's not the default/fallback language and not message found for that lang:key.
+		if defaultLoc := i.getLocalizer().GetLocale(0); defaultLoc != nil {
+			return defaultLoc.GetMessage(key, args...)
+		}
+	}
+
+	return ""
+}
+
+// fallbackLocale is the `Locale` which is returned from `GetLocale`,
+// its `GetMessage` walks through the fallback languages.
+type fallbackLocale struct {
+	context.Locale
+	i *I18n
+}
+
+func (l *fallbackLocale) GetMessage(key string, args ...interface{}) string {
+	return l.i.getMessage(l.Locale, key, args...)
+}
+
+const acceptLanguageHeaderKey = context.AcceptLanguageHeaderKey
 
 // GetLocale returns the found locale of a request.
 // It will return the first registered language if nothing else matched.
@@ -317,11 +516,31 @@ func (i *I18n) GetLocale(ctx context.Context) context.Locale {
 	// 	}
 	// }
 
+	if !i.Loaded() {
+		return nil
+	}
+
 	var (
 		index int
 		ok    bool
 	)
 
+	if len(i.Detectors) > 0 {
+		for _, detect := range i.Detectors {
+			if v := detect(ctx); v != "" {
+				if index, ok = i.matchLanguage(v); ok {
+					break
+				}
+			}
+		}
+
+		if locale := i.getLocalizer().GetLocale(index); locale != nil {
+			return &fallbackLocale{Locale: locale, i: i}
+		}
+
+		return nil
+	}
+
 	if !ok && i.ExtractFunc != nil {
 		if v := i.ExtractFunc(ctx); v != "" {
 			_, index, ok = i.TryMatchString(v)
@@ -348,38 +567,55 @@ func (i *I18n) GetLocale(ctx context.Context) context.Locale {
 	}
 
 	if !ok {
-		if v := ctx.GetHeader(acceptLanguageHeaderKey); v != "" {
-			desired, _, err := language.ParseAcceptLanguage(v)
-			if err == nil {
-				if _, idx, conf := i.matcher.Match(desired...); conf > language.Low {
-					index = idx
+		// the header is parsed once per request, see `Context.AcceptHeader`.
+		if accept := ctx.AcceptHeader(acceptLanguageHeaderKey); len(accept) > 0 {
+			desired := make([]language.Tag, 0, len(accept))
+			for _, v := range accept.Values() {
+				if tag, err := language.Parse(v); err == nil {
+					desired = append(desired, tag)
 				}
 			}
+
+			if _, idx, conf := i.getMatcher().Match(desired...); conf > language.Low {
+				index = idx
+			}
 		}
 	}
 
-	// locale := i.localizer.GetLocale(index)
+	// locale := i.getLocalizer().GetLocale(index)
 	// ctx.Values().Set(ctx.Application().ConfigurationReadOnly().GetLocaleContextKey(), locale)
 
 	// // if 0 then it defaults to the first language.
 	// return locale
-	locale := i.localizer.GetLocale(index)
+	locale := i.getLocalizer().GetLocale(index)
 	if locale == nil {
 		return nil
 	}
 
-	return locale
+	return &fallbackLocale{Locale: locale, i: i}
+}
+
+// matchLanguage matches a language code or an Accept-Language header value
+// with a registered language.
+func (i *I18n) matchLanguage(v string) (int, bool) {
+	if _, index, ok := i.TryMatchString(v); ok {
+		return index, true
+	}
+
+	desired, _, err := language.ParseAcceptLanguage(v)
+	if err == nil {
+		if _, index, conf := i.getMatcher().Match(desired...); conf > language.Low {
+			return index, true
+		}
+	}
+
+	return 0, false
 }
 
 // GetMessage returns the localized text message for this "r" request based on the key "format".
 func (i *I18n) GetMessage(ctx context.Context, format string, args ...interface{}) string {
-	loc := i.GetLocale(ctx)
-	if loc != nil {
-		// it's not the default/fallback language and not message found for that lang:key.
-		msg := loc.GetMessage(format, args...)
-		if msg == "" && !i.Strict && loc.Index() > 0 {
-			return i.localizer.GetLocale(0).GetMessage(format, args...)
-		}
+	if loc := i.GetLocale(ctx); loc != nil {
+		return loc.GetMessage(format, args...)
 	}
 
 	return fmt.Sprintf(format, args...)
@@ -406,6 +642,7 @@ func (i *I18n) Wrapper() router.WrapperFunc {
 		if path != "" {
 			if tag, _, ok := i.TryMatchString(path); ok {
 				lang := tag.String()
+				prefix := path
 
 				path = r.URL.Path[len(path)+1:]
 				if path == "" {
@@ -415,6 +652,7 @@ func (i *I18n) Wrapper() router.WrapperFunc {
 				r.RequestURI = path
 				r.URL.Path = path
 				r.Header.Set(acceptLanguageHeaderKey, lang)
+				r = r.WithContext(stdContext.WithValue(r.Context(), pathLanguageContextKey{}, prefix))
 				found = true
 			}
 		}
@@ -0,0 +1,274 @@
+package i18n_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	nethttptest "net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/i18n"
+)
+
+var testFiles = map[string]string{
+	"en-US.yml": `
+hi: "Hello {name}"
+balance: "You owe {amount:%.2f}"
+nav:
+  home: Home
+  about: About
+items:
+  zero: "No items"
+  one: "{{.Count}} item"
+  other: "{{.Count}} items"
+`,
+	"ru-RU.yml": `
+hi: "Привет {name}"
+items:
+  one: "%d предмет"
+  few: "%d предмета"
+  many: "%d предметов"
+  other: "%d предмета"
+`,
+	"uk-UA.yml": `
+nav:
+  home: Головна
+`,
+}
+
+func testAssets() (func() []string, func(string) ([]byte, error)) {
+	names := make([]string, 0, len(testFiles))
+	for name := range testFiles {
+		names = append(names, name)
+	}
+
+	return func() []string { return names }, func(name string) ([]byte, error) {
+		contents, ok := testFiles[name]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", name)
+		}
+		return []byte(contents), nil
+	}
+}
+
+func TestI18nMessages(t *testing.T) {
+	app := iris.New()
+	app.I18n.Fallbacks = map[string][]string{"uk-UA": {"ru-RU"}}
+	if err := app.I18n.Reset(i18n.Assets(testAssets()), "en-US", "ru-RU", "uk-UA"); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Get("/hi", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Tr("hi", iris.Map{"name": "iris"}))
+	})
+	app.Get("/balance", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Tr("balance", iris.Map{"amount": 4.5}))
+	})
+	app.Get("/nav/{key}", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Tr("nav." + ctx.Params().Get("key")))
+	})
+	app.Get("/items/{count:int}", func(ctx iris.Context) {
+		count := ctx.Params().GetIntDefault("count", 0)
+		if ctx.GetLocale().Language() == "en-US" {
+			ctx.WriteString(ctx.Tr("items", iris.Map{"Count": count}))
+			return
+		}
+
+		ctx.WriteString(ctx.Tr("items", count))
+	})
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.Get("/lang", func(loc iris.Locale) string {
+			return loc.Language()
+		})
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/hi").Expect().Status(httptest.StatusOK).Body().Equal("Hello iris")
+	e.GET("/hi").WithQuery("lang", "ru-RU").Expect().Status(httptest.StatusOK).Body().Equal("Привет iris")
+	e.GET("/balance").Expect().Status(httptest.StatusOK).Body().Equal("You owe 4.50")
+
+	e.GET("/nav/home").Expect().Status(httptest.StatusOK).Body().Equal("Home")
+	e.GET("/nav/home").WithQuery("lang", "uk-UA").Expect().Status(httptest.StatusOK).Body().Equal("Головна")
+	// uk-UA -> ru-RU -> en-US (default).
+	e.GET("/nav/about").WithQuery("lang", "uk-UA").Expect().Status(httptest.StatusOK).Body().Equal("About")
+	e.GET("/hi").WithQuery("lang", "uk-UA").Expect().Status(httptest.StatusOK).Body().Equal("Привет iris")
+
+	for count, expected := range map[int]string{0: "No items", 1: "1 item", 2: "2 items"} {
+		e.GET(fmt.Sprintf("/items/%d", count)).Expect().Status(httptest.StatusOK).Body().Equal(expected)
+	}
+
+	for count, expected := range map[int]string{1: "1 предмет", 3: "3 предмета", 5: "5 предметов", 21: "21 предмет"} {
+		e.GET(fmt.Sprintf("/items/%d", count)).WithQuery("lang", "ru-RU").Expect().
+			Status(httptest.StatusOK).Body().Equal(expected)
+	}
+
+	e.GET("/lang").WithHeader("Accept-Language", "ru").Expect().Status(httptest.StatusOK).Body().Equal("ru-RU")
+}
+
+func TestI18nDetectors(t *testing.T) {
+	app := iris.New()
+	if err := app.I18n.Reset(i18n.Assets(testAssets()), "en-US", "ru-RU", "uk-UA"); err != nil {
+		t.Fatal(err)
+	}
+	app.I18n.Detectors = []i18n.Detector{
+		i18n.DetectPath(),
+		i18n.DetectCookie("lang"),
+		i18n.DetectHeader(),
+	}
+	app.I18n.CanonicalRedirect = true
+
+	rv := router.NewRoutePathReverser(app)
+	app.Get("/nav/{key}", func(ctx iris.Context) {
+		ctx.Writef("%s %s", ctx.Tr("nav."+ctx.Params().Get("key")), rv.LocalePath(ctx, "about"))
+	})
+	app.Get("/about", func(ctx iris.Context) {
+		ctx.WriteString(app.I18n.Path(ctx, ctx.Path()))
+	}).Name = "about"
+
+	e := httptest.New(t, app)
+
+	e.GET("/nav/home").Expect().Status(httptest.StatusOK).Body().Equal("Home /about")
+	// path has priority over the header.
+	e.GET("/uk-UA/nav/home").WithHeader("Accept-Language", "ru-RU").Expect().
+		Status(httptest.StatusOK).Body().Equal("Головна /uk-UA/about")
+
+	// redirect to the canonical localized url.
+	e.GET("/about").WithCookie("lang", "ru").Expect().Status(httptest.StatusOK).Body().Equal("/ru-RU/about")
+
+	for _, tt := range []struct {
+		path, cookie, location string
+	}{
+		{"/about", "ru", "/ru-RU/about"},
+		{"/uk/about?q=1", "", "/uk-UA/about?q=1"},
+		{"/about", "", ""},
+		{"/ru-RU/about", "", ""},
+	} {
+		req := nethttptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+		rec := nethttptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		if tt.location == "" {
+			if rec.Code != httptest.StatusOK {
+				t.Fatalf("[%s] expected status code: %d but got: %d", tt.path, httptest.StatusOK, rec.Code)
+			}
+			continue
+		}
+
+		if expected, got := tt.location, rec.Header().Get("Location"); rec.Code != httptest.StatusFound || expected != got {
+			t.Fatalf("[%s] expected redirect to: %s but got: %d %s", tt.path, expected, rec.Code, got)
+		}
+	}
+}
+
+func TestI18nCatalog(t *testing.T) {
+	var mu sync.Mutex
+	catalog := map[string]map[string]interface{}{
+		"en-US": {"hi": "Hello", "nav": map[string]interface{}{"home": "Home"}},
+		"el-GR": {"hi": "Γειά"},
+	}
+
+	srv := nethttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		json.NewEncoder(w).Encode(catalog)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	app := iris.New()
+	loader := i18n.RemoteCatalog(srv.URL, http.Header{"Authorization": []string{"Bearer token"}})
+	if err := app.I18n.Reset(i18n.Catalog(loader), "en-US", "el-GR"); err != nil {
+		t.Fatal(err)
+	}
+
+	var missing []string
+	app.I18n.MissingKeyFunc = func(lang, key string) {
+		missing = append(missing, lang+":"+key)
+	}
+
+	app.Get("/{key}", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Tr(ctx.Params().Get("key")))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/hi").WithQuery("lang", "el-GR").Expect().Status(httptest.StatusOK).Body().Equal("Γειά")
+	e.GET("/nav.home").WithQuery("lang", "el-GR").Expect().Status(httptest.StatusOK).Body().Equal("Home")
+
+	if expected := []string{"el-GR:nav.home"}; len(missing) != 1 || missing[0] != expected[0] {
+		t.Fatalf("expected missing keys: %v but got: %v", expected, missing)
+	}
+
+	mu.Lock()
+	catalog["el-GR"]["nav.home"] = "Αρχική"
+	mu.Unlock()
+
+	stop := app.I18n.Refresh(10*time.Millisecond, func(err error) { t.Error(err) })
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	e.GET("/nav.home").WithQuery("lang", "el-GR").Expect().Status(httptest.StatusOK).Body().Equal("Αρχική")
+}
+
+func TestI18nReloadAndSetDefault(t *testing.T) {
+	var (
+		block   = make(chan struct{})
+		loading = make(chan struct{}, 1)
+		blocked bool
+	)
+
+	assets := i18n.Assets(testAssets())
+	loader := func(m *i18n.Matcher) (i18n.Localizer, error) {
+		if blocked {
+			loading <- struct{}{}
+			<-block
+		}
+		return assets(m)
+	}
+
+	i := i18n.New()
+	if err := i.Reset(loader, "en-US", "ru-RU"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the serving requests are not blocked by a slow loader.
+	blocked = true
+	reloaded := make(chan error)
+	go func() { reloaded <- i.Reload() }()
+	<-loading
+
+	if got, expected := i.Tr("en-US", "nav.home"), "Home"; got != expected {
+		t.Fatalf("expected: %q but got: %q", expected, got)
+	}
+
+	close(block)
+	if err := <-reloaded; err != nil {
+		t.Fatal(err)
+	}
+
+	tags := i.Tags()
+	if !i.SetDefault("ru-RU") {
+		t.Fatalf("expected to change the default language")
+	}
+
+	if got := i.Tags()[0].String(); got != "ru-RU" {
+		t.Fatalf("expected the default language to be ru-RU but got: %s", got)
+	}
+
+	// the previous languages are not modified.
+	if got := tags[0].String(); got != "en-US" {
+		t.Fatalf("expected the previous default language to be en-US but got: %s", got)
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/kataras/iris/v12/context"
 
 	"github.com/BurntSushi/toml"
+	"golang.org/x/text/feature/plural"
 	"golang.org/x/text/language"
 	"gopkg.in/ini.v1"
 	"gopkg.in/yaml.v3"
@@ -20,6 +23,13 @@ import (
 // LoaderConfig is an optional configuration structure which contains
 // some options about how the template loader should act.
 //
+// Messages can be nested, their keys are separated by dots, e.g. "nav.home".
+// A key which contains only CLDR plural categories ("zero", "one", "two", "few", "many" and "other")
+// is a plural message, the form is selected by the first numeric argument or
+// the "Count" entry of a map argument, e.g. ctx.Tr("items", iris.Map{"Count": 2}).
+// Simple text messages may contain named variables, e.g. "Hello {name}, you owe {amount:%.2f}",
+// which are filled by a map argument.
+//
 // See `Glob` and `Assets` package-level functions.
 type LoaderConfig struct {
 	// Template delimeters, defaults to {{ }}.
@@ -62,13 +72,7 @@ func Assets(assetNames func() []string, asset func(string) ([]byte, error), opti
 	return load(assetNames(), asset, options...)
 }
 
-// load accepts a list of filenames (physical or virtual),
-// a function that should return the contents of a specific file
-// and any Loader options.
-// It returns a valid `Loader` which loads and maps the locale files.
-//
-// See `Glob`, `Assets` and `LoaderConfig` too.
-func load(assetNames []string, asset func(string) ([]byte, error), options ...LoaderOption) Loader {
+func newLoaderConfig(options ...LoaderOption) LoaderConfig {
 	var c = LoaderConfig{
 		Left:   "{{",
 		Right:  "}}",
@@ -79,6 +83,18 @@ func load(assetNames []string, asset func(string) ([]byte, error), options ...Lo
 		opt(&c)
 	}
 
+	return c
+}
+
+// load accepts a list of filenames (physical or virtual),
+// a function that should return the contents of a specific file
+// and any Loader options.
+// It returns a valid `Loader` which loads and maps the locale files.
+//
+// See `Glob`, `Assets` and `LoaderConfig` too.
+func load(assetNames []string, asset func(string) ([]byte, error), options ...LoaderOption) Loader {
+	c := newLoaderConfig(options...)
+
 	return func(m *Matcher) (Localizer, error) {
 		languageFiles, err := m.ParseLanguageFiles(assetNames)
 		if err != nil {
@@ -113,43 +129,12 @@ func load(assetNames []string, asset func(string) ([]byte, error), options ...Lo
 				}
 			}
 
-			var (
-				templateKeys = make(map[string]*template.Template)
-				lineKeys     = make(map[string]string)
-				other        = make(map[string]interface{})
-			)
-
-			for k, v := range keyValues {
-				// fmt.Printf("[%d] %s = %v of type: [%T]\n", langIndex, k, v, v)
-
-				switch value := v.(type) {
-				case string:
-					if leftIdx, rightIdx := strings.Index(value, c.Left), strings.Index(value, c.Right); leftIdx != -1 && rightIdx > leftIdx {
-						// we assume it's template?
-						if t, err := template.New(k).Delims(c.Left, c.Right).Funcs(c.FuncMap).Parse(value); err == nil {
-							templateKeys[k] = t
-							continue
-						} else if c.Strict {
-							return nil, err
-						}
-					}
-
-					lineKeys[k] = value
-				default:
-					other[k] = v
-				}
-
+			loc, err := c.newLocale(m, langIndex, keyValues)
+			if err != nil {
+				return nil, err
 			}
 
-			t := m.Languages[langIndex]
-			locales[langIndex] = &defaultLocale{
-				index:        langIndex,
-				id:           t.String(),
-				tag:          &t,
-				templateKeys: templateKeys,
-				lineKeys:     lineKeys,
-				other:        other,
-			}
+			locales[langIndex] = loc
 		}
 
 		if n := len(locales); n == 0 {
@@ -162,6 +147,54 @@ func load(assetNames []string, asset func(string) ([]byte, error), options ...Lo
 	}
 }
 
+// newLocale builds the locale of the "langIndex" language
+// from its nested or flat "keyValues" translations.
+func (c LoaderConfig) newLocale(m *Matcher, langIndex int, keyValues map[string]interface{}) (*defaultLocale, error) {
+	var (
+		flatKeyValues = make(map[string]interface{})
+		messages      = make(map[string]*message)
+		other         = make(map[string]interface{})
+	)
+
+	// nested structures are converted to dot-separated keys, e.g. "nav.home".
+	flattenKeys(flatKeyValues, "", keyValues)
+
+	for k, v := range flatKeyValues {
+		// fmt.Printf("[%d] %s = %v of type: [%T]\n", langIndex, k, v, v)
+
+		value, ok := v.(string)
+		if !ok {
+			other[k] = v
+			continue
+		}
+
+		msg, err := c.parseMessage(k, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent, form, ok := parsePluralKey(k); ok && isPluralGroup(flatKeyValues, parent) {
+			pluralMsg, exists := messages[parent]
+			if !exists {
+				pluralMsg = &message{plurals: make(map[plural.Form]*message)}
+				messages[parent] = pluralMsg
+			}
+			pluralMsg.plurals[form] = msg
+		}
+
+		messages[k] = msg
+	}
+
+	t := m.Languages[langIndex]
+	return &defaultLocale{
+		index:    langIndex,
+		id:       t.String(),
+		tag:      &t,
+		messages: messages,
+		other:    other,
+	}, nil
+}
+
 // MemoryLocalizer is a map which implements the `Localizer`.
 type MemoryLocalizer map[int]context.Locale
 
@@ -195,9 +228,8 @@ type defaultLocale struct {
 	id    string
 	tag   *language.Tag
 	// templates *template.Template // we could use the ExecuteTemplate too.
-	templateKeys map[string]*template.Template
-	lineKeys     map[string]string
-	other        map[string]interface{}
+	messages map[string]*message
+	other    map[string]interface{}
 }
 
 func (l *defaultLocale) Index() int {
@@ -213,23 +245,12 @@ func (l *defaultLocale) Language() string {
 }
 
 func (l *defaultLocale) GetMessage(key string, args ...interface{}) string {
-	n := len(args)
-	if n > 0 {
-		// search on templates.
-		if tmpl, ok := l.templateKeys[key]; ok {
-			buf := new(bytes.Buffer)
-			if err := tmpl.Execute(buf, args[0]); err == nil {
-				return buf.String()
-			}
-		}
-	}
-
-	if text, ok := l.lineKeys[key]; ok {
-		return fmt.Sprintf(text, args...)
+	if msg, ok := l.messages[key]; ok {
+		return msg.render(*l.tag, args)
 	}
 
 	if v, ok := l.other[key]; ok {
-		if n > 0 {
+		if len(args) > 0 {
 			return fmt.Sprintf("%v [%v]", v, args)
 		}
 		return fmt.Sprintf("%v", v)
@@ -238,6 +259,222 @@ func (l *defaultLocale) GetMessage(key string, args ...interface{}) string {
 	return ""
 }
 
+// message is a translated text of a key.
+// It is a template, a plural group or a simple text line
+// which may contain named variables.
+type message struct {
+	tmpl    *template.Template
+	text    string
+	named   bool
+	plurals map[plural.Form]*message
+}
+
+// namedVariableRegexp matches the {name} and {name:%verb} named variables.
+var namedVariableRegexp = regexp.MustCompile(`\{(\w+)(?::(%[^{}]+))?\}`)
+
+func (c LoaderConfig) parseMessage(key, value string) (*message, error) {
+	if leftIdx, rightIdx := strings.Index(value, c.Left), strings.Index(value, c.Right); leftIdx != -1 && rightIdx > leftIdx {
+		// we assume it's template?
+		if t, err := template.New(key).Delims(c.Left, c.Right).Funcs(c.FuncMap).Parse(value); err == nil {
+			return &message{tmpl: t}, nil
+		} else if c.Strict {
+			return nil, err
+		}
+	}
+
+	return &message{text: value, named: namedVariableRegexp.MatchString(value)}, nil
+}
+
+func (msg *message) render(tag language.Tag, args []interface{}) string {
+	if msg.plurals != nil {
+		count, ok := pluralCount(args)
+		if !ok {
+			return msg.renderForm(plural.Other, tag, args)
+		}
+
+		if count == 0 {
+			if zero, ok := msg.plurals[plural.Zero]; ok {
+				return zero.render(tag, args)
+			}
+		}
+
+		return msg.renderForm(matchPlural(tag, count), tag, args)
+	}
+
+	if msg.tmpl != nil {
+		if len(args) == 0 {
+			return ""
+		}
+
+		buf := new(bytes.Buffer)
+		if err := msg.tmpl.Execute(buf, args[0]); err != nil {
+			return ""
+		}
+		return buf.String()
+	}
+
+	if len(args) > 0 {
+		if vars, ok := args[0].(map[string]interface{}); ok {
+			if !msg.named {
+				return msg.text
+			}
+
+			return namedVariableRegexp.ReplaceAllStringFunc(msg.text, func(s string) string {
+				sub := namedVariableRegexp.FindStringSubmatch(s)
+				v, ok := vars[sub[1]]
+				if !ok {
+					return s
+				}
+
+				verb := sub[2]
+				if verb == "" {
+					verb = "%v"
+				}
+				return fmt.Sprintf(verb, v)
+			})
+		}
+	}
+
+	return fmt.Sprintf(msg.text, args...)
+}
+
+func (msg *message) renderForm(form plural.Form, tag language.Tag, args []interface{}) string {
+	m, ok := msg.plurals[form]
+	if !ok {
+		if m, ok = msg.plurals[plural.Other]; !ok {
+			return ""
+		}
+	}
+
+	return m.render(tag, args)
+}
+
+// pluralForms are the CLDR plural categories which can be used as the last part of a message key,
+// e.g. "items.one" and "items.other" are the plural forms of the "items" key.
+var pluralForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+func parsePluralKey(key string) (string, plural.Form, bool) {
+	idx := strings.LastIndexByte(key, '.')
+	if idx <= 0 {
+		return "", 0, false
+	}
+
+	form, ok := pluralForms[key[idx+1:]]
+	return key[:idx], form, ok
+}
+
+// isPluralGroup reports whether the "parent" key contains
+// only plural categories, including the required "other" one.
+func isPluralGroup(keyValues map[string]interface{}, parent string) bool {
+	if _, ok := keyValues[parent+".other"]; !ok {
+		return false
+	}
+
+	prefix := parent + "."
+	for k := range keyValues {
+		if strings.HasPrefix(k, prefix) {
+			if _, ok := pluralForms[k[len(prefix):]]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// pluralCount returns the number which selects the plural form,
+// it is the first argument or the "Count" (or "count") entry of a map argument.
+func pluralCount(args []interface{}) (float64, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	v := args[0]
+	if m, ok := v.(map[string]interface{}); ok {
+		if v, ok = m["Count"]; !ok {
+			v = m["count"]
+		}
+	}
+
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// matchPlural returns the CLDR plural form of the "count" for the language "tag".
+func matchPlural(tag language.Tag, count float64) plural.Form {
+	if count < 0 {
+		count = -count
+	}
+
+	s := strconv.FormatFloat(count, 'f', -1, 64)
+	i, fraction := s, ""
+	if idx := strings.IndexByte(s, '.'); idx != -1 {
+		i, fraction = s[:idx], s[idx+1:]
+	}
+
+	integer, _ := strconv.Atoi(i)
+	f, _ := strconv.Atoi(fraction)
+	trimmed := strings.TrimRight(fraction, "0")
+	t, _ := strconv.Atoi(trimmed)
+
+	return plural.Cardinal.MatchPlural(tag, integer, len(fraction), len(trimmed), f, t)
+}
+
+// flattenKeys converts the nested "src" map to dot-separated keys.
+func flattenKeys(dest map[string]interface{}, prefix string, src map[string]interface{}) {
+	for k, v := range src {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+
+		switch m := v.(type) {
+		case map[string]interface{}:
+			flattenKeys(dest, k, m)
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(m))
+			for mk, mv := range m {
+				converted[fmt.Sprintf("%v", mk)] = mv
+			}
+			flattenKeys(dest, k, converted)
+		default:
+			dest[k] = v
+		}
+	}
+}
+
 func unmarshalINI(data []byte, v interface{}) error {
 	f, err := ini.Load(data)
 	if err != nil {
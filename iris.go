@@ -6,14 +6,17 @@ import (
 	stdContext "context"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	// context for the handlers
@@ -23,8 +26,10 @@ import (
 	// core packages, required to build the application
 	"github.com/kataras/iris/v12/core/errgroup"
 	"github.com/kataras/iris/v12/core/host"
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/netutil"
 	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/core/scheduler"
 
 	// handlerconv conversions
 	"github.com/kataras/iris/v12/core/handlerconv"
@@ -148,6 +153,8 @@ type Application struct {
 
 	// the golog logger instance, defaults to "Info" level messages (all except "Debug")
 	logger *golog.Logger
+	// the structured logger, defaults to a logger which writes to the golog one.
+	log logging.Logger
 
 	// I18n contains localization and internationalization support.
 	// Use the `Load` or `LoadAssets` to locale language files.
@@ -156,10 +163,23 @@ type Application struct {
 	I18n *i18n.I18n
 
 	// Validator is the request body validator, defaults to nil.
+	// It accepts the go-playground/validator or any custom `Struct(interface{}) error` implementation.
+	// All payloads bound by the `ReadXXX` Context methods, the hero handlers and the MVC controllers are validated.
+	// Hero and MVC send failures as a structured 422 response,
+	// see `context.AsValidationErrors` and `context.NewValidationProblem`.
 	Validator context.Validator
+	// JSONCodec is the JSON encoder of the `Context.JSON`, for all handlers, defaults to nil,
+	// the encoding/json or, on `Configuration.EnableOptimizations`, the json-iterator one.
+	// See the `context.StdJSONCodec` and `context.JSONIterCodec`.
+	// It can be overridden per call through the `context.JSON.Codec` option.
+	JSONCodec context.JSONCodec
 
 	// view engine
 	view view.View
+	// per-route statistics, see `Configuration.EnableRouteStats`.
+	stats *router.Stats
+	// rejects malformed requests, see `Configuration.RequestHardening`.
+	requestGuard *router.RequestGuard
 	// used for build
 	builded     bool
 	defaultMode bool
@@ -175,6 +195,28 @@ type Application struct {
 	// Hosts field is available after `Run` or `NewHost`.
 	Hosts             []*host.Supervisor
 	hostConfigurators []host.Configurator
+
+	// onShutdown contains the functions that are called, in order,
+	// after the servers are gracefully terminated, see `OnShutdown`.
+	onShutdown []func()
+	// connections contains the tracked long-lived connections, see `TrackConnection`.
+	connections   trackedConnections
+	interruptOnce sync.Once
+	upgradeOnce   sync.Once
+	// scheduler runs the background workers and jobs, see `Go` and `Schedule`.
+	scheduler *scheduler.Scheduler
+	// the applied profile's name and settings, see `WithProfile`.
+	profile         string
+	profileSettings Profile
+	// maintenance holds the *maintenance state, see `SetMaintenance`.
+	maintenance atomic.Value
+	// draining is 1 when the application is shutting down.
+	draining uint32
+	// events holds the application event listeners, see `OnBuildFinished`.
+	events events
+	// plugins are the registered plugins, in order, see `RegisterPlugin`.
+	plugins      []Plugin
+	pluginErrors []error
 }
 
 // New creates and returns a fresh empty iris *Application instance.
@@ -189,9 +231,12 @@ func New() *Application {
 		Router:     router.NewRouter(),
 	}
 
+	app.log = logging.New(logging.Golog(app.logger), nil)
+
 	app.ContextPool = context.New(func() context.Context {
 		return context.NewContext(app)
 	})
+	app.scheduler = scheduler.New(app.logger)
 
 	return app
 }
@@ -243,6 +288,18 @@ func (app *Application) SubdomainRedirect(from, to router.Party) router.Party {
 	return to
 }
 
+// UseHostRules registers a router wrapper which evaluates the host redirection "rules"
+// before the routing and redirects the client, with a single redirect, when at least one rule matches,
+// e.g. HTTP to HTTPS, apex to www and port canonicalization.
+//
+// Usage:
+//  app.UseHostRules(host.RedirectHTTPS(), host.CanonicalHost("example.com"), host.NonWWW())
+//
+// See the `core/host#RedirectRules` function for more.
+func (app *Application) UseHostRules(rules ...host.Rule) {
+	app.WrapRouter(host.RedirectRules(rules...))
+}
+
 // Configure can called when modifications to the framework instance needed.
 // It accepts the framework instance
 // and returns an error which if it's not nil it's printed to the logger.
@@ -256,6 +313,7 @@ func (app *Application) Configure(configurators ...Configurator) *Application {
 		}
 	}
 
+	app.fireConfigurationApplied()
 	return app
 }
 
@@ -306,12 +364,28 @@ func (app *Application) Logger() *golog.Logger {
 	return app.logger
 }
 
+// Log returns the structured logger of the "app", which accepts key/value fields, i.e
+//  app.Log().Info("user created", "id", user.ID)
+// It's used by the framework for its own messages too.
+//
+// Defaults to a logger which writes to the golog `Logger`, so its level and outputs are respected.
+// Use the `WithLogger` Configurator to write to a JSON, slog, zap or zerolog sink instead,
+// see the `core/logging` package.
+func (app *Application) Log() Logger {
+	return app.log
+}
+
 // I18nReadOnly returns the i18n's read-only features.
 // See `I18n` method for more.
 func (app *Application) I18nReadOnly() context.I18nReadOnly {
 	return app.I18n
 }
 
+// GetJSONCodec returns the `JSONCodec` field, see `Context.JSON`.
+func (app *Application) GetJSONCodec() context.JSONCodec {
+	return app.JSONCodec
+}
+
 // Validate validates a value and returns nil if passed or
 // the failure reason if does not.
 func (app *Application) Validate(v interface{}) error {
@@ -452,6 +526,11 @@ var (
 	//
 	// A shortcut for the `handlerconv#FromStd`.
 	FromStd = handlerconv.FromStd
+	// FromStdParam returns the value of an Iris route's path parameter
+	// of a request served by a standard handler converted through `FromStd`.
+	//
+	// A shortcut for the `handlerconv#Param`.
+	FromStdParam = handlerconv.Param
 	// Cache is a middleware providing server-side cache functionalities
 	// to the next handlers, can be used as: `app.Get("/", iris.Cache, aboutHandler)`.
 	// It should be used after Static methods.
@@ -547,6 +626,11 @@ var (
 	//
 	// A shortcut for the `context#IsErrPath`.
 	IsErrPath = context.IsErrPath
+	// ErrMultipartPartTooLarge is returned from the `Context.ReadMultipartStream` method
+	// when a form field or file exceeds its maximum size.
+	//
+	// A shortcut for the `context#ErrMultipartPartTooLarge`.
+	ErrMultipartPartTooLarge = context.ErrMultipartPartTooLarge
 	// NewProblem returns a new Problem.
 	// Head over to the `Problem` type godoc for more.
 	//
@@ -646,6 +730,7 @@ func (app *Application) NewHost(srv *http.Server) *host.Supervisor {
 	// create the new host supervisor
 	// bind the constructed server and return it
 	su := host.New(srv)
+	su.Logger = app.log
 
 	if app.config.vhost == "" { // vhost now is useful for router subdomain on wildcard subdomains,
 		// in order to correct decide what to do on:
@@ -668,15 +753,19 @@ func (app *Application) NewHost(srv *http.Server) *host.Supervisor {
 	}
 
 	if !app.config.DisableInterruptHandler {
-		// when CTRL/CMD+C pressed.
-		shutdownTimeout := 10 * time.Second
-		host.RegisterOnInterrupt(host.ShutdownOnInterrupt(su, shutdownTimeout))
+		// when CTRL/CMD+C pressed, terminate all hosts and then run the shutdown hooks.
+		app.interruptOnce.Do(func() {
+			host.RegisterOnInterrupt(app.shutdownOnInterrupt)
+		})
 		// app.logger.Debugf("Host: register server shutdown on interrupt(CTRL+C/CMD+C)")
 	}
 
+	// notify the tracked connections when the server stopped accepting new ones.
+	su.RegisterOnShutdown(app.connections.notify)
+
 	su.IgnoredErrors = append(su.IgnoredErrors, app.config.IgnoreServerErrors...)
 	if len(su.IgnoredErrors) > 0 {
-		app.logger.Debugf("Host: server will ignore the following errors: %s", su.IgnoredErrors)
+		app.log.Debug("host: server will ignore errors", "addr", su.Server.Addr, "errors", su.IgnoredErrors)
 	}
 
 	su.Configure(app.hostConfigurators...)
@@ -691,15 +780,146 @@ func (app *Application) NewHost(srv *http.Server) *host.Supervisor {
 // A shortcut for the `host#RegisterOnInterrupt`.
 var RegisterOnInterrupt = host.RegisterOnInterrupt
 
-// Shutdown gracefully terminates all the application's server hosts and any tunnels.
-// Returns an error on the first failure, otherwise nil.
+// OnShutdown registers one or more functions to call, in order, when the application
+// is terminated through `Shutdown` or an interrupt signal, after the servers
+// stopped accepting new connections and the in-flight requests were drained.
+// Use it to close any sessions database, flush access logs and e.t.c.
+func (app *Application) OnShutdown(cb ...func()) {
+	app.mu.Lock()
+	app.onShutdown = append(app.onShutdown, cb...)
+	app.mu.Unlock()
+}
+
+// TrackConnection registers a long-lived connection, e.g. a websocket or a server-sent events stream,
+// so the `Shutdown` can notify it through the "notify" function and wait for it to be closed.
+// Note that the standard http server does not track nor wait for hijacked or streaming connections.
+//
+// The returned "done" function MUST be called when the connection is closed.
+//
+// Example Code:
+//  done := app.TrackConnection(func() { conn.Close() })
+//  defer done()
+func (app *Application) TrackConnection(notify func()) (done func()) {
+	return app.connections.add(notify)
+}
+
+// Go runs the "worker" on a new goroutine which is part of the application's lifecycle:
+// its context is cancelled on `Shutdown` and the shutdown waits for it to return.
+// Panics are recovered and logged to the application's logger.
+//
+// Example Code:
+//  app.Go(func(ctx context.Context) {
+//      for {
+//          select {
+//          case <-ctx.Done():
+//              return
+//          case msg := <-queue:
+//              process(msg)
+//          }
+//      }
+//  })
+func (app *Application) Go(worker func(ctx stdContext.Context)) {
+	app.scheduler.Go(worker)
+}
+
+// Schedule runs the "job" on each activation of the "spec" cron expression,
+// e.g. "*/5 * * * *", "0 3 * * MON-FRI", "@daily" or "@every 30s".
+// The job's context is cancelled on `Shutdown` and the shutdown waits for it to return.
+// A job's activation is skipped while its previous one is still running.
+// Panics are recovered and logged to the application's logger.
+//
+// Set the `ScheduleOptions.Locker` to run each activation on a single instance
+// of a horizontally scaled application.
+//
+// It returns an error if the "spec" is not a valid cron expression,
+// see the `core/scheduler#Parse` for its syntax.
+func (app *Application) Schedule(spec string, job func(ctx stdContext.Context), opts ...ScheduleOptions) error {
+	return app.scheduler.Schedule(spec, job, opts...)
+}
+
+// Shutdown gracefully terminates the application.
+// The order is: all the application's server hosts stop accepting new connections,
+// the tracked long-lived connections are notified, the in-flight requests are drained
+// and the tracked connections are waited to close, the background workers and jobs are cancelled and waited and, finally,
+// any tunnels are stopped and the `OnShutdown` functions are called.
+// The "ctx" is the deadline of the whole process.
+//
+// Returns the first error, if any, but it does not stop the shutdown process.
 func (app *Application) Shutdown(ctx stdContext.Context) error {
-	for i, su := range app.Hosts {
-		app.logger.Debugf("Host[%d]: Shutdown now", i)
+	return app.shutdown(ctx, func(i int, su *host.Supervisor) error {
+		app.log.Debug("host: shutdown now", "host", i)
 		if err := su.Shutdown(ctx); err != nil {
-			app.logger.Debugf("Host[%d]: Error while trying to shutdown", i)
+			app.log.Debug("host: shutdown failed", "host", i, "error", err)
 			return err
 		}
+
+		return nil
+	})
+}
+
+func (app *Application) shutdownOnInterrupt() {
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 10*time.Second)
+	defer cancel()
+
+	app.shutdownGracefully(ctx)
+}
+
+// shutdownGracefully terminates the application like the interrupt handler does,
+// the hosts' Serve methods return without an error.
+func (app *Application) shutdownGracefully(ctx stdContext.Context) {
+	shutdownTimeout := 10 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		shutdownTimeout = time.Until(deadline)
+	}
+
+	app.shutdown(ctx, func(_ int, su *host.Supervisor) error {
+		host.ShutdownOnInterrupt(su, shutdownTimeout)()
+		return nil
+	})
+}
+
+func (app *Application) shutdown(ctx stdContext.Context, shutdownHost func(int, *host.Supervisor) error) error {
+	var firstErr error
+	setErr := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	atomic.StoreUint32(&app.draining, 1)
+
+	app.mu.Lock()
+	hosts := app.Hosts
+	hooks := app.onShutdown
+	app.mu.Unlock()
+
+	// the hosts notify the tracked connections right after they stopped accepting new connections,
+	// see `NewHost`, and in parallel, so a streaming response of one host does not keep the others open.
+	errs := make([]error, len(hosts))
+	var wg sync.WaitGroup
+	for i, su := range hosts {
+		wg.Add(1)
+		go func(i int, su *host.Supervisor) {
+			defer wg.Done()
+			errs[i] = shutdownHost(i, su)
+		}(i, su)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		setErr(err)
+	}
+
+	app.connections.notify()
+
+	if err := app.connections.wait(ctx); err != nil {
+		app.log.Debug("shutdown: long-lived connections are still open", "error", err)
+		setErr(err)
+	}
+
+	if err := app.scheduler.Shutdown(ctx); err != nil {
+		app.log.Debug("shutdown: background workers are still running", "error", err)
+		setErr(err)
 	}
 
 	for _, t := range app.config.Tunneling.Tunnels {
@@ -707,12 +927,126 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 			continue
 		}
 
-		if err := app.config.Tunneling.stopTunnel(t); err != nil {
-			return err
+		setErr(app.config.Tunneling.stopTunnel(t))
+	}
+
+	setErr(app.shutdownPlugins(ctx))
+
+	for _, cb := range hooks {
+		cb()
+	}
+
+	return firstErr
+}
+
+// trackedConnections keeps the long-lived connections
+// that should be notified and waited on shutdown.
+type trackedConnections struct {
+	mu        sync.Mutex
+	nextID    uint64
+	notifiers map[uint64]func()
+	wg        sync.WaitGroup
+}
+
+func (c *trackedConnections) add(notify func()) func() {
+	c.mu.Lock()
+	if c.notifiers == nil {
+		c.notifiers = make(map[uint64]func())
+	}
+	id := c.nextID
+	c.nextID++
+	c.notifiers[id] = notify
+	c.wg.Add(1)
+	c.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			delete(c.notifiers, id)
+			c.mu.Unlock()
+			c.wg.Done()
+		})
+	}
+}
+
+// notify calls the notifiers of the tracked connections which were not notified yet.
+func (c *trackedConnections) notify() {
+	c.mu.Lock()
+	notifiers := make([]func(), 0, len(c.notifiers))
+	for id, notify := range c.notifiers {
+		if notify != nil {
+			notifiers = append(notifiers, notify)
+			c.notifiers[id] = nil
 		}
 	}
+	c.mu.Unlock()
 
-	return nil
+	for _, notify := range notifiers {
+		notify()
+	}
+}
+
+func (c *trackedConnections) wait(ctx stdContext.Context) error {
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// BuildHandler builds the Application, once, applies the "withOrWithout" configurators
+// and returns its http.Handler, without starting a server.
+// Use it to mount an Iris Application inside other routers, test servers
+// or serverless adapters. It panics if the Application could not be built.
+//
+// Example Code:
+// mux := http.NewServeMux()
+// mux.Handle("/api/", http.StripPrefix("/api", app.BuildHandler()))
+func (app *Application) BuildHandler(withOrWithout ...Configurator) http.Handler {
+	if err := app.Build(); err != nil {
+		app.logger.Error(err)
+		panic(err)
+	}
+
+	app.Configure(withOrWithout...)
+	return app.Router
+}
+
+// Stats returns the statistics of the served routes, the busiest first:
+// requests count, error rate, p50/p95/p99 latencies and the time of the last error.
+// It returns nil if the `Configuration.EnableRouteStats` is false.
+func (app *Application) Stats() []router.RouteStats {
+	if app.stats == nil {
+		return nil
+	}
+
+	return app.stats.Get()
+}
+
+// StatsHandler returns a handler which writes the `Stats` as JSON,
+// e.g. `app.Get("/stats", app.StatsHandler())`.
+func (app *Application) StatsHandler() context.Handler {
+	return func(ctx context.Context) {
+		ctx.JSON(app.Stats())
+	}
+}
+
+// RejectedRequests returns the number of the requests rejected
+// by the `Configuration.RequestHardening` options, per reason.
+func (app *Application) RejectedRequests() router.RejectedRequests {
+	if app.requestGuard == nil {
+		return router.RejectedRequests{}
+	}
+
+	return app.requestGuard.Rejected()
 }
 
 // Build sets up, once, the framework.
@@ -730,10 +1064,28 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 func (app *Application) Build() error {
 	rp := errgroup.New("Application Builder")
 
+	var start time.Time // zero when already built.
 	if !app.builded {
+		start = time.Now()
 		app.builded = true
 		rp.Err(app.APIBuilder.GetReporter())
 
+		for _, err := range app.buildPlugins() {
+			rp.Group("Plugins").Err(err)
+		}
+
+		if h := app.slowRequestHandler(); h != nil {
+			app.UseGlobal(h)
+		}
+
+		if app.config.EnableRouteStats {
+			app.stats = router.NewStats()
+			app.OnBuild(func() error {
+				app.stats.Attach(app.GetRoutes())
+				return nil
+			})
+		}
+
 		if app.defaultMode { // the app.I18n and app.View will be not available until Build.
 			if !app.I18n.Loaded() {
 				for _, s := range []string{"./locales/*/*", "./locales/*", "./translations"} {
@@ -766,6 +1118,9 @@ func (app *Application) Build() error {
 			// {{ tr "lang" "key" arg1 arg2 }}
 			app.view.AddFunc("tr", app.I18n.Tr)
 			app.Router.WrapRouter(app.I18n.Wrapper())
+			if app.I18n.CanonicalRedirect && app.I18n.PathRedirect {
+				app.UseGlobal(app.I18n.CanonicalRedirectHandler())
+			}
 		}
 
 		if n := app.view.Len(); n > 0 {
@@ -780,6 +1135,12 @@ func (app *Application) Build() error {
 			// Each engine has their defaults, i.e yield,render,render_r,partial, params...
 			rv := router.NewRoutePathReverser(app.APIBuilder)
 			app.view.AddFunc("urlpath", rv.Path)
+			// {{ markdown .Content }}
+			app.view.AddFunc("markdown", markdownViewFunc)
+			// {{ formatTime .fmt .CreatedAt "Jan 2, 2006" }} and {{ formatNumber .fmt .Price }},
+			// see Context.Formatter.
+			app.view.AddFunc("formatTime", context.Formatter.Time)
+			app.view.AddFunc("formatNumber", context.Formatter.Number)
 			// app.view.AddFunc("url", rv.URL)
 			if err := app.view.Load(); err != nil {
 				rp.Group("View Builder").Err(err)
@@ -799,6 +1160,15 @@ func (app *Application) Build() error {
 				})
 			}
 
+			// the maintenance mode responds before any other router wrapper.
+			app.Router.WrapRouter(app.maintenanceWrapper)
+
+			// malformed requests are rejected before anything else.
+			if app.config.RequestHardening.Enabled() {
+				app.requestGuard = router.NewRequestGuard(app.config.RequestHardening)
+				app.Router.WrapRouter(app.requestGuard.Wrapper)
+			}
+
 			// create the request handler, the default routing handler
 			routerHandler := router.NewDefaultHandler(app.config)
 			err := app.Router.BuildRouter(app.ContextPool, routerHandler, app.APIBuilder, false)
@@ -810,7 +1180,36 @@ func (app *Application) Build() error {
 		}
 	}
 
-	return errgroup.Check(rp)
+	err := errgroup.Check(rp)
+	if !start.IsZero() {
+		app.fireBuildFinished(BuildEvent{
+			Routes:   len(app.GetRoutes()),
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+
+	return err
+}
+
+// markdownViewFunc renders the markdown "v", a string or a byte slice, to sanitized html,
+// based on the `context.DefaultMarkdownOptions`.
+func markdownViewFunc(v interface{}) template.HTML {
+	var b []byte
+	switch m := v.(type) {
+	case string:
+		b = []byte(m)
+	case []byte:
+		b = m
+	case template.HTML:
+		b = []byte(m)
+	default:
+		return ""
+	}
+
+	options := context.DefaultMarkdownOptions
+	options.Sanitize = true
+	return template.HTML(context.RenderMarkdown(b, options))
 }
 
 // Runner is just an interface which accepts the framework instance
@@ -839,13 +1238,186 @@ type Runner func(*Application) error
 // See `Run` for more.
 func Listener(l net.Listener, hostConfigs ...host.Configurator) Runner {
 	return func(app *Application) error {
-		app.config.vhost = netutil.ResolveVHost(l.Addr().String())
+		// the virtual host is resolved by the listener's address on NewHost, if not already set.
 		return app.NewHost(&http.Server{Addr: l.Addr().String()}).
 			Configure(hostConfigs...).
 			Serve(l)
 	}
 }
 
+// UnixListener can be used as an argument for the `Run` method.
+// It starts a server on a unix domain socket, e.g. behind a reverse proxy such as nginx.
+// The "mode" is the socket file's permissions, e.g. 0666.
+//
+// A stale socket file, left by a previous process, is removed before listening
+// and the socket file is removed on server shutdown.
+//
+// Second argument is optional, it accepts one or more
+// `func(*host.Configurator)` that are being executed
+// on that specific host that this function will create to start the server.
+//
+// Example Code:
+//  app.Run(iris.UnixListener("/tmp/iris.sock", 0666))
+//
+// See `Run` for more.
+func UnixListener(socketFile string, mode os.FileMode, hostConfigs ...host.Configurator) Runner {
+	return func(app *Application) error {
+		l, err := netutil.UNIX(socketFile, mode)
+		if err != nil {
+			return err
+		}
+
+		return Listener(l, hostConfigs...)(app)
+	}
+}
+
+// SystemdActivation can be used as an argument for the `Run` method.
+// It starts the server(s) on the listeners passed by systemd socket activation.
+// Each listener is served by its own host, all of them are gracefully
+// terminated on interrupt signals or `app.Shutdown`.
+// It returns the `netutil.ErrNoSystemdListeners` error if
+// the process was not started through socket activation.
+//
+// Second argument is optional, it accepts one or more
+// `func(*host.Configurator)` that are being executed
+// on the hosts that this function will create to start the servers.
+//
+// Example Code:
+//  app.Run(iris.SystemdActivation())
+//
+// See `Run` for more.
+func SystemdActivation(hostConfigs ...host.Configurator) Runner {
+	return func(app *Application) error {
+		listeners, err := netutil.SystemdListeners()
+		if err != nil {
+			return err
+		}
+
+		// the first listener is the primary one, resolve the virtual host by its address
+		// before the rest of the hosts start.
+		app.mu.Lock()
+		if app.config.vhost == "" {
+			app.config.vhost = netutil.ResolveVHost(listeners[0].Addr().String())
+		}
+		app.mu.Unlock()
+
+		for _, l := range listeners[1:] {
+			go func(l net.Listener) {
+				if err := Listener(l, hostConfigs...)(app); err != nil {
+					app.logger.Error(err)
+				}
+			}(l)
+		}
+
+		return Listener(listeners[0], hostConfigs...)(app)
+	}
+}
+
+// Multi can be used as an argument for the `Run` method.
+// It serves the Application on several addresses, schemes and listeners
+// at the same time, each runner creates its own host.
+// All hosts are gracefully terminated together on interrupt signals or `app.Shutdown`.
+// If a host fails, the rest of the hosts are terminated too and the returned error
+// contains the errors of all the failed hosts. Per-host error handling can be
+// registered through the runners' `host.Configurator`s, e.g. `su.RegisterOnError`.
+//
+// Example Code:
+//  app.Run(iris.Multi(
+//      iris.Redirect(":80", "https://iris-go.com"),
+//      iris.TLS(":443", "server.crt", "server.key"),
+//      iris.UnixListener("/run/iris-admin.sock", 0600),
+//  ))
+//
+// See `Run` for more.
+func Multi(runners ...Runner) Runner {
+	return func(app *Application) error {
+		var stopped uint32
+		// hosts which are registered after a failure are terminated immediately.
+		app.ConfigureHost(func(su *host.Supervisor) {
+			if atomic.LoadUint32(&stopped) == 1 {
+				su.Shutdown(stdContext.Background())
+			}
+		})
+
+		errs := make(chan error, len(runners))
+		for _, runner := range runners {
+			go func(runner Runner) {
+				errs <- runner(app)
+			}(runner)
+		}
+
+		rp := errgroup.New("Multi Runner")
+		for range runners {
+			err := <-errs
+			if err == nil || errors.Is(err, http.ErrServerClosed) {
+				continue
+			}
+
+			rp.Add(err)
+			if atomic.CompareAndSwapUint32(&stopped, 0, 1) {
+				ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 10*time.Second)
+				app.Shutdown(ctx)
+				cancel()
+			}
+		}
+
+		return errgroup.Check(rp)
+	}
+}
+
+// Redirect can be used as an argument for the `Run` method, commonly inside the `Multi` Runner.
+// It starts a server on the "addr" which redirects all requests
+// to the "target", e.g. a plain http server which redirects to the https one.
+//
+// Example Code:
+//  iris.Redirect(":80", "https://iris-go.com")
+//
+// See `Multi` and `Run` for more.
+func Redirect(addr, target string, hostConfigs ...host.Configurator) Runner {
+	return func(app *Application) error {
+		targetURL, err := url.Parse(target)
+		if err != nil {
+			return err
+		}
+
+		redirectSrv := host.NewRedirection(addr, targetURL, StatusTemporaryRedirect).Server
+		return app.NewHost(redirectSrv).
+			Configure(hostConfigs...).
+			ListenAndServe()
+	}
+}
+
+// Upgradable can be used as an argument for the `Run` method.
+// It starts a server on the "addr" through the "u" host.Upgrader's listener
+// which is inherited from the parent process on binary upgrades (zero-downtime restarts).
+// On a SIGHUP signal (unix) or a manual `u.Upgrade()` call the current executable
+// is started again, it inherits the listener and, when it is ready to serve,
+// the current process gracefully terminates, as on interrupt signals.
+//
+// Second argument is optional, it accepts one or more
+// `func(*host.Configurator)` that are being executed
+// on that specific host that this function will create to start the server.
+//
+// Example Code:
+//  app.Run(iris.Upgradable(host.Upgradable(), ":8080"))
+//
+// See `Run` for more.
+func Upgradable(u *host.Upgrader, addr string, hostConfigs ...host.Configurator) Runner {
+	return func(app *Application) error {
+		l, err := u.Listen(addr)
+		if err != nil {
+			return err
+		}
+
+		// after a successful upgrade, terminate the whole application, not just its servers.
+		app.upgradeOnce.Do(func() {
+			u.OnDrain(app.shutdownGracefully)
+		})
+
+		return Listener(l, append(hostConfigs, u.Configure)...)(app)
+	}
+}
+
 // Server can be used as an argument for the `Run` method.
 // It can start a server with a *http.Server.
 //
@@ -973,7 +1545,7 @@ func AutoTLS(
 // See `Run` for more.
 func Raw(f func() error) Runner {
 	return func(app *Application) error {
-		app.logger.Debugf("HTTP Server will start from unknown, external function")
+		app.log.Debug("host: server will start from an external function")
 		return f()
 	}
 }
@@ -1023,7 +1595,7 @@ func (app *Application) Run(serve Runner, withOrWithout ...Configurator) error {
 	app.tryStartTunneling()
 
 	if len(app.Hosts) > 0 {
-		app.logger.Debugf("Application: running using %d host(s)", len(app.Hosts)+1 /* +1 the current */)
+		app.log.Debug("application: running", "hosts", len(app.Hosts)+1 /* +1 the current */)
 	}
 
 	// this will block until an error(unless supervisor's DeferFlow called from a Task).
@@ -0,0 +1,1270 @@
+package iris
+
+import (
+	"bytes"
+	"compress/gzip"
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/host"
+	"github.com/kataras/iris/v12/core/logging"
+	"github.com/kataras/iris/v12/sessions"
+
+	"golang.org/x/text/language"
+)
+
+func TestApplicationShutdown(t *testing.T) {
+	app := New().Configure(WithoutInterruptHandler, WithoutStartupLog)
+
+	var calls []string
+	app.OnShutdown(func() { calls = append(calls, "first") })
+	app.OnShutdown(func() { calls = append(calls, "second") })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+
+	closed := make(chan struct{})
+	done := app.TrackConnection(func() {
+		calls = append(calls, "notified")
+		// the listeners are closed before the tracked connections are notified.
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			calls = append(calls, "accepting")
+		}
+		close(closed)
+	})
+	go func() {
+		<-closed
+		time.Sleep(20 * time.Millisecond)
+		done()
+	}()
+
+	su := app.NewHost(&http.Server{Addr: addr})
+	go su.Serve(l)
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"notified", "first", "second"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls: %v but got: %v", expected, calls)
+	}
+	for i := range expected {
+		if expected[i] != calls[i] {
+			t.Fatalf("[%d] expected call: %s but got: %s", i, expected[i], calls[i])
+		}
+	}
+}
+
+func TestApplicationUpgradableDrain(t *testing.T) {
+	app := New().Configure(WithoutInterruptHandler, WithoutStartupLog)
+
+	hookCalled := false
+	app.OnShutdown(func() { hookCalled = true })
+
+	u := host.Upgradable(host.UpgradeConfig{DisableSignal: true})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- app.Run(Upgradable(u, l.Addr().String()))
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// what an upgrade does after the new process is ready.
+	app.shutdownGracefully(stdContext.Background())
+
+	select {
+	case err = <-errCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the server to be terminated")
+	}
+
+	if !hookCalled {
+		t.Fatalf("expected the shutdown hooks to be called on drain")
+	}
+}
+
+func TestApplicationShutdownDeadline(t *testing.T) {
+	app := New()
+
+	hookCalled := false
+	app.OnShutdown(func() { hookCalled = true })
+	app.TrackConnection(nil) // never done.
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := app.Shutdown(ctx); err != stdContext.DeadlineExceeded {
+		t.Fatalf("expected error: %v but got: %v", stdContext.DeadlineExceeded, err)
+	}
+
+	if !hookCalled {
+		t.Fatalf("expected shutdown hook to be called even on deadline")
+	}
+}
+
+func TestMultiRunner(t *testing.T) {
+	app := New().Configure(WithoutInterruptHandler, WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		ctx.WriteString("ok")
+	})
+
+	l1, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run(Multi(Listener(l1), Listener(l2)))
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	for _, l := range []net.Listener{l1, l2} {
+		resp, err := http.Get("http://" + l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != StatusOK {
+			t.Fatalf("[%s] expected status code: %d but got: %d", l.Addr(), StatusOK, resp.StatusCode)
+		}
+	}
+
+	if expected, got := 2, len(app.Hosts); expected != got {
+		t.Fatalf("expected %d hosts but got %d", expected, got)
+	}
+
+	if err = app.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = <-done; err != nil {
+		t.Fatalf("expected nil error on shutdown but got: %v", err)
+	}
+}
+
+func TestMultiRunnerFailure(t *testing.T) {
+	app := New().Configure(WithoutInterruptHandler, WithoutStartupLog)
+	app.Logger().SetLevel("disable")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second host cannot listen on the same address,
+	// the first one should be terminated too.
+	err = app.Run(Multi(Listener(l), Addr(l.Addr().String())))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestApplicationBuildHandler(t *testing.T) {
+	app := New()
+	app.Get("/ping", func(ctx Context) {
+		ctx.WriteString("pong " + ctx.Path())
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", app.BuildHandler()))
+
+	w := stdhttptest.NewRecorder()
+	mux.ServeHTTP(w, stdhttptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	if expected, got := "pong /ping", w.Body.String(); expected != got {
+		t.Fatalf("expected body: %q but got: %q", expected, got)
+	}
+}
+
+func TestApplicationLog(t *testing.T) {
+	buf := new(bytes.Buffer)
+	level := logging.NewLevelVar(logging.InfoLevel)
+
+	app := New()
+	app.Configure(WithLogger(logging.New(logging.JSON(buf), level)))
+	app.ConfigureContainer().Get("/users", func() error {
+		return errors.New("database is down")
+	})
+
+	handler := app.BuildHandler()
+	serve := func() {
+		handler.ServeHTTP(stdhttptest.NewRecorder(), stdhttptest.NewRequest(http.MethodGet, "/users", nil))
+	}
+
+	serve()
+	if buf.Len() > 0 {
+		t.Fatalf("expected no debug records but got: %s", buf.String())
+	}
+
+	level.Set(logging.DebugLevel)
+	serve()
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record["level"] != "debug" || record["path"] != "/users" || record["error"] != "database is down" {
+		t.Fatalf("unexpected record: %s", buf.String())
+	}
+}
+
+func TestApplicationShutdownBackgroundWorkers(t *testing.T) {
+	app := New()
+
+	var calls []string
+	app.OnShutdown(func() { calls = append(calls, "hook") })
+	app.Go(func(ctx stdContext.Context) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		calls = append(calls, "worker")
+	})
+
+	if err := app.Schedule("@daily", func(stdContext.Context) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Schedule("* * *", func(stdContext.Context) {}); err == nil {
+		t.Fatalf("expected an error on invalid cron expression")
+	}
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := app.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"worker", "hook"}; len(calls) != 2 || calls[0] != expected[0] || calls[1] != expected[1] {
+		t.Fatalf("expected calls: %v but got: %v", expected, calls)
+	}
+}
+
+func TestNewMockContext(t *testing.T) {
+	getUser := func(ctx Context) {
+		ctx.JSON(Map{
+			"id":   ctx.Params().GetIntDefault("id", 0),
+			"user": sessions.Get(ctx).GetString("user"),
+		})
+	}
+
+	w := stdhttptest.NewRecorder()
+	r := stdhttptest.NewRequest(http.MethodGet, "/users/42", nil)
+	ctx := NewMockContext(w, r, MockContextOptions{
+		Params:  map[string]string{"id": "42"},
+		Session: map[string]interface{}{"user": "kataras"},
+	})
+	getUser(ctx)
+	ctx.EndRequest()
+
+	if expected, got := http.StatusOK, w.Code; expected != got {
+		t.Fatalf("expected status code: %d but got: %d", expected, got)
+	}
+
+	var body struct {
+		ID   int    `json:"id"`
+		User string `json:"user"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.ID != 42 || body.User != "kataras" {
+		t.Fatalf("expected the path parameter and the session value but got: %s", w.Body.String())
+	}
+
+	w = stdhttptest.NewRecorder()
+	ctx = NewMockContext(w, stdhttptest.NewRequest(http.MethodGet, "/", nil))
+	ctx.StatusCode(http.StatusNotFound)
+	ctx.EndRequest()
+
+	if expected, got := http.StatusNotFound, w.Code; expected != got {
+		t.Fatalf("expected status code: %d but got: %d", expected, got)
+	}
+
+	if expected, got := "Not Found", w.Body.String(); expected != got {
+		t.Fatalf("expected the error handler's body: %s but got: %s", expected, got)
+	}
+}
+
+func TestContextPoolDebug(t *testing.T) {
+	app := New()
+
+	leaks := make(chan context.Leak, 10)
+	app.ContextPool.EnableDebug(50*time.Millisecond, func(leak context.Leak) {
+		leaks <- leak
+	})
+
+	retained := make(chan Context, 1)
+	app.Get("/leak", func(ctx Context) {
+		// the classic mistake: the Context is used after the handler returned.
+		retained <- ctx
+	}).Name = "leak"
+	app.Get("/ok", func(ctx Context) {
+		ctx.WriteString("ok")
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/ok", "/leak", "/ok"} {
+		app.ServeHTTP(stdhttptest.NewRecorder(), stdhttptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	select {
+	case leak := <-leaks:
+		if leak.Path != "/leak" || leak.Route != "leak" || leak.Stack == "" {
+			t.Fatalf("unexpected leak: %s", leak)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a leak report")
+	}
+
+	stats := app.ContextPool.Stats()
+	if stats.Acquired != 3 || stats.Released != 3 || stats.InUse != 0 || stats.Retained != 1 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	select {
+	case leak := <-leaks:
+		t.Fatalf("unexpected second leak: %s", leak)
+	default:
+	}
+
+	<-retained
+}
+
+func TestContextDetach(t *testing.T) {
+	app := New()
+
+	detached := make(chan Context, 1)
+	app.Post("/orders/{id}", func(ctx Context) {
+		ctx.Values().Set("user", "kataras")
+		d := ctx.Detach()
+
+		// the handler can still read the body.
+		body, _ := ctx.GetBody()
+		if expected, got := `{"total":42}`, string(body); expected != got {
+			t.Fatalf("expected body: %s but got: %s", expected, got)
+		}
+
+		detached <- d
+		ctx.StatusCode(http.StatusAccepted)
+	})
+	app.Post("/other/{id}", func(ctx Context) {
+		ctx.Values().Set("user", "other")
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := stdhttptest.NewRecorder()
+	app.ServeHTTP(w, stdhttptest.NewRequest(http.MethodPost, "/orders/1", bytes.NewBufferString(`{"total":42}`)))
+	if expected, got := http.StatusAccepted, w.Code; expected != got {
+		t.Fatalf("expected status code: %d but got: %d", expected, got)
+	}
+
+	// the pooled context is reused by the next requests.
+	for i := 0; i < 3; i++ {
+		app.ServeHTTP(stdhttptest.NewRecorder(), stdhttptest.NewRequest(http.MethodPost, "/other/2", nil))
+	}
+
+	ctx := <-detached
+	if expected, got := "kataras", ctx.Values().GetString("user"); expected != got {
+		t.Fatalf("expected value: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "1", ctx.Params().Get("id"); expected != got {
+		t.Fatalf("expected parameter: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "/orders/1", ctx.Path(); expected != got {
+		t.Fatalf("expected path: %s but got: %s", expected, got)
+	}
+
+	var order struct {
+		Total int `json:"total"`
+	}
+	if err := ctx.ReadJSON(&order); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 42, order.Total; expected != got {
+		t.Fatalf("expected total: %d but got: %d", expected, got)
+	}
+
+	if err := ctx.Request().Context().Err(); err != nil {
+		t.Fatalf("expected a non-canceled request context but got: %v", err)
+	}
+
+	ctx.WriteString("dropped")
+}
+
+func TestContextServerTiming(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		ctx.ServerTiming().Add("db", 15*time.Millisecond, `users "active" query`).Add("cache", 0, "miss")
+		ctx.WriteString("OK")
+	})
+	app.Get("/stream", func(ctx Context) {
+		ctx.WriteString("partial")
+		ctx.ResponseWriter().Flush()
+
+		end := ctx.ServerTiming().Start("render", "")
+		ctx.WriteString(" response")
+		end()
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if expected, got := `db;dur=15.00;desc="users \"active\" query", cache;desc="miss"`, resp.Header.Get(context.ServerTimingHeaderKey); expected != got {
+		t.Fatalf("expected Server-Timing header: %s but got: %s", expected, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := new(bytes.Buffer)
+	body.ReadFrom(resp.Body)
+	resp.Body.Close()
+
+	if expected, got := "partial response", body.String(); expected != got {
+		t.Fatalf("expected body: %q but got: %q", expected, got)
+	}
+
+	if got := resp.Header.Get(context.ServerTimingHeaderKey); got != "" {
+		t.Fatalf("expected no Server-Timing header after a partial write but got: %s", got)
+	}
+
+	if got := resp.Trailer.Get(context.ServerTimingHeaderKey); len(got) < len("render;dur=") || got[:len("render;dur=")] != "render;dur=" {
+		t.Fatalf("expected Server-Timing trailer but got: %q", got)
+	}
+}
+
+func TestContextTrailers(t *testing.T) {
+	payload := strings.Repeat("chunk", 1024)
+
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		ctx.SetTrailer("X-Rows")
+		ctx.WriteString("partial")
+		ctx.ResponseWriter().Flush()
+		ctx.WriteTrailer("X-Rows", "42")
+	})
+	app.Get("/download", func(ctx Context) {
+		w := ctx.DigestWriter("")
+		for i := 0; i < len(payload); i += 1024 {
+			w.Write([]byte(payload[i : i+1024]))
+			ctx.ResponseWriter().Flush()
+		}
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if expected, got := "42", resp.Trailer.Get("X-Rows"); expected != got {
+		t.Fatalf("expected trailer value: %q but got: %q", expected, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != payload {
+		t.Fatalf("unexpected body of length: %d", len(body))
+	}
+
+	sum := sha256.Sum256(body)
+	if expected, got := "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]), resp.Trailer.Get(context.DigestHeaderKey); expected != got {
+		t.Fatalf("expected digest trailer: %q but got: %q", expected, got)
+	}
+}
+
+func TestContextMultipartStream(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		stream, err := ctx.MultipartStream("frame")
+		if err != nil {
+			ctx.StopWithStatus(StatusInternalServerError)
+			return
+		}
+
+		for _, part := range []string{"first", "second"} {
+			if err = stream.WritePart("text/plain", []byte(part)); err != nil {
+				return
+			}
+		}
+
+		stream.Close()
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get(context.ContentTypeHeaderKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "multipart/x-mixed-replace" || params["boundary"] != "frame" {
+		t.Fatalf("unexpected content type: %s %v", mediaType, params)
+	}
+
+	var parts []string
+	r := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, _ := ioutil.ReadAll(p)
+		if expected, got := strconv.Itoa(len(b)), p.Header.Get(context.ContentLengthHeaderKey); expected != got {
+			t.Fatalf("expected part's content length: %s but got: %s", expected, got)
+		}
+		parts = append(parts, string(b))
+	}
+
+	if expected, got := "first,second", strings.Join(parts, ","); expected != got {
+		t.Fatalf("expected parts: %s but got: %s", expected, got)
+	}
+}
+
+func TestApplicationBuildStatic(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+	app.Logger().SetLevel("disable")
+	app.Get("/", func(ctx Context) {
+		ctx.HTML(`<link href="/css/main.css" rel="stylesheet"><a href="about">About</a><a href="https://iris-go.com">Iris</a><a href="/users/1#top">User</a><a href="/a%%20b">A B</a>`)
+	})
+	app.Get("/about", func(ctx Context) {
+		ctx.HTML(`<a href="/">Home</a><a href="/old">Old</a><a href="/missing">Missing</a>`)
+	})
+	app.Get("/old", func(ctx Context) {
+		ctx.Redirect("/new", StatusMovedPermanently)
+	})
+	app.Get("/new", func(ctx Context) {
+		ctx.HTML("new")
+	})
+	app.Get("/users/{id:uint64}", func(ctx Context) {
+		ctx.HTML("user " + ctx.Params().Get("id"))
+	})
+	app.Get("/a b", func(ctx Context) {
+		ctx.HTML("a b")
+	})
+	app.Get("/css/main.css", func(ctx Context) {
+		ctx.ContentType("text/css")
+		ctx.WriteString(`body { background: url("/img/bg.png"); }`)
+	})
+	app.Get("/img/bg.png", func(ctx Context) {
+		ctx.ContentType("image/png")
+		ctx.Write([]byte{137, 80, 78, 71})
+	})
+
+	outDir, err := ioutil.TempDir("", "iris-static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	if err = app.BuildStatic(outDir, "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	for filename, expected := range map[string]string{
+		"index.html":         "",
+		"about/index.html":   "",
+		"new/index.html":     "new",
+		"users/1/index.html": "user 1",
+		"a b/index.html":     "a b",
+		"css/main.css":       "",
+		"img/bg.png":         "",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(outDir, filepath.FromSlash(filename)))
+		if err != nil {
+			t.Fatalf("expected file: %s: %v", filename, err)
+		}
+
+		if expected != "" && string(b) != expected {
+			t.Fatalf("[%s] expected contents: %q but got: %q", filename, expected, string(b))
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(outDir, "missing")); !os.IsNotExist(err) {
+		t.Fatalf("expected the not found page to be skipped but got: %v", err)
+	}
+
+	// an asset can not be the directory of a page.
+	app = New().Configure(WithoutStartupLog)
+	app.Get("/docs", func(ctx Context) {
+		ctx.WriteString("docs")
+	})
+	app.Get("/docs/intro", func(ctx Context) {
+		ctx.HTML("intro")
+	})
+
+	if err = app.BuildStatic(outDir, "/docs/intro", "/docs"); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Fatalf("expected a conflict error but got: %v", err)
+	}
+}
+
+func TestContextMarkdown(t *testing.T) {
+	highlight := func(code []byte, lang string) ([]byte, bool) {
+		if lang != "go" {
+			return nil, false
+		}
+
+		return []byte(`<pre class="chroma"><span class="kd">` + strings.TrimSpace(string(code)) + `</span></pre>`), true
+	}
+
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		ctx.Markdown([]byte("# Title\n\nText[^1] <script>alert(1)</script>\n\n```go\npackage main\n```\n\n```sh\nls\n```\n\n[^1]: Note.\n"), context.Markdown{
+			Sanitize:  true,
+			Footnotes:    true,
+			Highlight:    highlight,
+			HighlightKey: "test",
+			Cache:        true,
+		})
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ { // the second one is served from the cache.
+		rec := stdhttptest.NewRecorder()
+		app.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodGet, "/", nil))
+		body := rec.Body.String()
+
+		if strings.Contains(body, "<script>") {
+			t.Fatalf("expected sanitized output but got: %s", body)
+		}
+
+		for _, expected := range []string{"<h1>Title</h1>", `class="footnotes"`, `<pre class="chroma"><span class="kd">package main</span></pre>`,
+			`<pre><code class="language-sh">ls
+</code></pre>`} {
+			if !strings.Contains(body, expected) {
+				t.Fatalf("expected output to contain: %s but got: %s", expected, body)
+			}
+		}
+	}
+
+	if expected, got := "<p><strong>bold</strong></p>\n", string(markdownViewFunc("**bold**<script>alert(1)</script>")); expected != got {
+		t.Fatalf("expected markdown view func output: %q but got: %q", expected, got)
+	}
+}
+
+func TestContextServeFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "iris-serve-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	contents := strings.Repeat("0123456789", 10000)
+	f.WriteString(contents)
+	f.Close()
+
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		ctx.ContentType("text/plain")
+		ctx.ServeFile(f.Name(), false)
+	})
+	app.Get("/gzip", Gzip, func(ctx Context) {
+		ctx.ContentType("text/plain")
+		ctx.ServeFile(f.Name(), false)
+	})
+	if err = app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != contents || resp.ContentLength != int64(len(contents)) {
+		t.Fatalf("unexpected response of length: %d and content length: %d", len(body), resp.ContentLength)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/", nil)
+	req.Header.Set("Range", "bytes=10-14")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent || string(body) != "01234" {
+		t.Fatalf("unexpected range response: %d: %q", resp.StatusCode, string(body))
+	}
+
+	// through the gzip response writer, the content length of the uncompressed file is not sent.
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/gzip", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=10-14")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("unexpected gzip response: %d: %v", resp.StatusCode, resp.Header)
+	}
+
+	gr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err = ioutil.ReadAll(gr); err != nil || string(body) != contents {
+		t.Fatalf("unexpected gzip response of length: %d: %v", len(body), err)
+	}
+}
+
+type prefixJSONCodec struct{}
+
+func (prefixJSONCodec) NewEncoder(w io.Writer) context.JSONEncoder {
+	io.WriteString(w, "codec:")
+	return json.NewEncoder(w)
+}
+
+func TestContextJSONCodec(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+	app.JSONCodec = prefixJSONCodec{}
+
+	largeValue := strings.Repeat("a", 10000)
+	app.Get("/", func(ctx Context) {
+		ctx.JSON(Map{"a": 1})
+	})
+	app.Get("/override", func(ctx Context) {
+		ctx.JSON(Map{"a": 1}, context.JSON{Codec: context.StdJSONCodec})
+	})
+	app.Get("/etag", func(ctx Context) {
+		ctx.WriteJSONWithETag(Map{"a": 1})
+	})
+	app.Get("/length", func(ctx Context) {
+		ctx.JSON(largeValue, context.JSON{Codec: context.StdJSONCodec, ContentLength: true})
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp, string(body)
+	}
+
+	if _, body := get("/"); body != "codec:{\n  \"a\": 1\n}\n" {
+		t.Fatalf("unexpected application codec body: %q", body)
+	}
+
+	// the same body as the JSON's one.
+	if _, body := get("/etag"); body != "codec:{\n  \"a\": 1\n}\n" {
+		t.Fatalf("unexpected etag codec body: %q", body)
+	}
+
+	if _, body := get("/override"); body != "{\n  \"a\": 1\n}\n" {
+		t.Fatalf("unexpected per-call codec body: %q", body)
+	}
+
+	expected := strconv.Quote(largeValue) + "\n"
+	if resp, body := get("/length"); body != expected || resp.ContentLength != int64(len(expected)) {
+		t.Fatalf("unexpected response of length: %d and content length: %d", len(body), resp.ContentLength)
+	}
+}
+
+func BenchmarkWriteJSON(b *testing.B) {
+	v := struct {
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags"`
+		Stars int      `json:"stars"`
+	}{"iris", []string{"web", "framework"}, 20000}
+
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result, _ := json.Marshal(v)
+			ioutil.Discard.Write(result)
+		}
+	})
+
+	for _, optimize := range []bool{false, true} {
+		b.Run("optimize="+strconv.FormatBool(optimize), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				context.WriteJSON(ioutil.Discard, v, context.JSON{}, optimize)
+			}
+		})
+	}
+}
+
+func BenchmarkServeHandlersChain(b *testing.B) {
+	app := New().Configure(WithoutStartupLog)
+
+	next := func(ctx Context) { ctx.Next() }
+	for i := 0; i < 5; i++ {
+		app.UseGlobal(next)
+		app.Use(next)
+	}
+	app.Get("/", func(ctx Context) {})
+
+	if err := app.Build(); err != nil {
+		b.Fatal(err)
+	}
+
+	req := stdhttptest.NewRequest(http.MethodGet, "/", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(stdhttptest.NewRecorder(), req)
+	}
+}
+
+func TestContextAcceptHeader(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+	app.Get("/", func(ctx Context) {
+		accept := ctx.AcceptHeader(context.AcceptHeaderKey)
+		if again := ctx.AcceptHeader(context.AcceptHeaderKey); len(again) == 0 || &again[0] != &accept[0] {
+			t.Fatal("expected the parsed header to be cached")
+		}
+
+		ctx.Writef("%s|%v|%v",
+			strings.Join(accept.Values(), ","),
+			ctx.ClientSupportsGzip(),
+			ctx.AcceptHeader(context.AcceptEncodingHeaderKey).Accepts("br"))
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		accept, acceptEncoding string
+		expected               string
+	}{
+		{"text/html;q=0.5, application/json, text/xml;q=0", "gzip, br", "application/json,text/html|true|true"},
+		{"text/plain", "gzip;q=0, *", "text/plain|false|true"},
+		{"*/*", "br;q=0.0, gzip; q=0.8", "*/*|true|false"},
+	}
+
+	for i, tt := range tests {
+		req := stdhttptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+		rec := stdhttptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		if got := rec.Body.String(); got != tt.expected {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func BenchmarkRouteCache(b *testing.B) {
+	for _, size := range []int{0, 1024} {
+		b.Run("size="+strconv.Itoa(size), func(b *testing.B) {
+			app := New().Configure(WithoutStartupLog, WithRouteCache(size))
+
+			for i := 0; i < 100; i++ {
+				prefix := "/api/v" + strconv.Itoa(i)
+				app.Get(prefix+"/organizations/{org}/projects/{project}/builds/{build:uint64}/logs/{line:int}", func(ctx Context) {})
+				app.Get(prefix+"/organizations/{org}/projects/{project}/builds/{build:uint64}/status", func(ctx Context) {})
+			}
+
+			if err := app.Build(); err != nil {
+				b.Fatal(err)
+			}
+
+			req := stdhttptest.NewRequest(http.MethodGet, "/api/v99/organizations/iris/projects/web/builds/42/logs/7", nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				app.ServeHTTP(stdhttptest.NewRecorder(), req)
+			}
+		})
+	}
+}
+
+func BenchmarkRouteCacheParallel(b *testing.B) {
+	for _, size := range []int{0, 1024} {
+		b.Run("size="+strconv.Itoa(size), func(b *testing.B) {
+			app := New().Configure(WithoutStartupLog, WithRouteCache(size))
+			app.Get("/organizations/{org}/projects/{project}/builds/{build:uint64}", func(ctx Context) {})
+
+			if err := app.Build(); err != nil {
+				b.Fatal(err)
+			}
+
+			// more paths than the cache can keep, so the lookups, the additions and the evictions run concurrently.
+			reqs := make([]*http.Request, 2048)
+			for i := range reqs {
+				reqs[i] = stdhttptest.NewRequest(http.MethodGet, "/organizations/iris/projects/web/builds/"+strconv.Itoa(i), nil)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					app.ServeHTTP(stdhttptest.NewRecorder(), reqs[i%len(reqs)])
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestContextReadJSONOptions(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	read := func(ctx Context, opts ...context.ReadJSONOptions) {
+		var (
+			u   user
+			err error
+		)
+		if len(opts) > 0 {
+			err = ctx.ReadJSONWithOptions(&u, opts[0])
+		} else {
+			err = ctx.ReadJSON(&u)
+		}
+
+		if err != nil {
+			var jsonErr *context.ReadJSONError
+			if errors.As(err, &jsonErr) {
+				ctx.Writef("%s|%v", jsonErr.Field, jsonErr.Err)
+				return
+			}
+
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		ctx.Writef("%s:%d", u.Name, u.Age)
+	}
+
+	app.Post("/", func(ctx Context) {
+		read(ctx)
+	})
+	api := app.Party("/api")
+	api.SetReadJSONOptions(context.ReadJSONOptions{DisallowUnknownFields: true, MaxDepth: 2, MaxBytes: 64})
+	api.Post("/", func(ctx Context) {
+		read(ctx)
+	})
+	api.Post("/loose", func(ctx Context) {
+		read(ctx, context.ReadJSONOptions{})
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	tests := []struct {
+		path, body string
+		expected   string
+	}{
+		{"/", `{"name":"kataras","age":27,"admin":true}`, "kataras:27"},
+		{"/api", `{"name":"kataras","age":27}`, "kataras:27"},
+		{"/api", `{"name":"kataras","admin":true}`, "admin|" + context.ErrJSONUnknownField.Error()},
+		{"/api", `{"name":"kataras","age":"27"}`, "age|json: cannot unmarshal string"},
+		{"/api", `{"name":{"first":[{"a":1}]}}`, "|" + context.ErrJSONMaxDepth.Error()},
+		{"/api", `{"name":"` + strings.Repeat("a", 64) + `"}`, "|" + context.ErrJSONBodyTooLarge.Error()},
+		{"/api/loose", `{"name":"kataras","admin":true}`, "kataras:0"},
+	}
+
+	for i, tt := range tests {
+		resp, err := http.Post(srv.URL+tt.path, "application/json", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if got := string(body); !strings.HasPrefix(got, tt.expected) {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestContextReadJSONStream(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	app.Post("/", func(ctx Context) {
+		sum := 0
+		err := ctx.ReadJSONStream(func(dec *json.Decoder) error {
+			var it item
+			if err := dec.Decode(&it); err != nil {
+				return err
+			}
+
+			sum += it.ID
+			return nil
+		}, context.JSONStreamOptions{MaxElements: 3, MaxBytes: 64})
+		if err != nil {
+			ctx.WriteString(err.Error())
+			return
+		}
+
+		ctx.Writef("%d", sum)
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	tests := []struct {
+		body     io.Reader
+		expected string
+	}{
+		{strings.NewReader(`[{"id":1},{"id":2},{"id":3}]`), "6"},
+		{strings.NewReader(`[]`), "0"},
+		{strings.NewReader(`[{"id":1},{"id":2},{"id":3},{"id":4}]`), context.ErrJSONMaxElements.Error()},
+		{strings.NewReader(`{"id":1}`), context.ErrJSONNotArray.Error()},
+		{strings.NewReader(`[{"id":1}] {}`), "invalid data after top-level value"},
+		// chunked, without a content length.
+		{ioutil.NopCloser(strings.NewReader(`[{"id":1},{"id":2,"pad":"` + strings.Repeat("a", 64) + `"}]`)), context.ErrJSONBodyTooLarge.Error()},
+	}
+
+	for i, tt := range tests {
+		resp, err := http.Post(srv.URL, "application/json", tt.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if got := string(body); !strings.HasSuffix(got, tt.expected) {
+			t.Fatalf("[%d] expected: %q but got: %q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestContextRecordRequestBody(t *testing.T) {
+	app := New().Configure(WithoutStartupLog, WithConfiguration(Configuration{RecordRequestBodyLimit: 32}))
+
+	verify := func(ctx Context) {
+		ctx.RecordRequestBody(true)
+		body, err := ctx.GetBody()
+		if err != nil {
+			if errors.Is(err, context.ErrRequestBodyTooLarge) {
+				ctx.StopWithStatus(StatusRequestEntityTooLarge)
+				return
+			}
+
+			ctx.StopWithStatus(StatusBadRequest)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		if base64.StdEncoding.EncodeToString(sum[:]) != ctx.GetHeader("X-Signature") {
+			ctx.StopWithStatus(StatusForbidden)
+			return
+		}
+
+		ctx.Next()
+	}
+
+	app.Post("/", verify, func(ctx Context) {
+		var v struct {
+			Name string `json:"name"`
+		}
+		if err := ctx.ReadJSON(&v); err != nil {
+			ctx.StopWithError(StatusBadRequest, err)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(ctx.Request().Body)
+		ctx.Writef("%s|%d", v.Name, len(body))
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	post := func(body string) (int, string) {
+		sum := sha256.Sum256([]byte(body))
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		req.Header.Set("X-Signature", base64.StdEncoding.EncodeToString(sum[:]))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode, string(b)
+	}
+
+	body := `{"name":"kataras"}`
+	if status, got := post(body); status != StatusOK || got != "kataras|"+strconv.Itoa(len(body)) {
+		t.Fatalf("unexpected response: %d: %q", status, got)
+	}
+
+	if status, got := post(`{"name":"` + strings.Repeat("a", 32) + `"}`); status != StatusRequestEntityTooLarge {
+		t.Fatalf("expected the body to exceed the record limit but got: %d: %q", status, got)
+	}
+}
+
+type testLocale struct{ tag language.Tag }
+
+func (l testLocale) Index() int                                     { return 0 }
+func (l testLocale) Tag() *language.Tag                             { return &l.tag }
+func (l testLocale) Language() string                               { return l.tag.String() }
+func (l testLocale) GetMessage(key string, _ ...interface{}) string { return key }
+
+func TestContextFormatter(t *testing.T) {
+	app := New().Configure(WithoutStartupLog)
+
+	tz, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	date := time.Date(2020, 4, 2, 10, 30, 0, 0, time.UTC)
+	app.Use(func(ctx Context) {
+		if ctx.URLParam("lang") == "de" {
+			ctx.SetLocale(testLocale{language.German})
+			ctx.SetTimezone(tz)
+		}
+		ctx.Next()
+	})
+	app.Get("/", func(ctx Context) {
+		f := ctx.Formatter()
+		ctx.Writef("%s|%s", f.Time(date, "15:04"), f.Number(1234.5))
+	})
+	app.ConfigureContainer().Get("/tz", func(loc *time.Location) string {
+		return loc.String()
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	tests := []struct {
+		path, expected string
+	}{
+		{"/", date.Local().Format("15:04") + "|1,234.5"},
+		{"/?lang=de", "13:30|1.234,5"},
+		{"/tz?lang=de", "Europe/Athens"},
+		{"/tz", time.Local.String()},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if got := string(body); got != tt.expected {
+			t.Fatalf("[%s] expected: %q but got: %q", tt.path, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package jsonrpc
+
+import (
+	"errors"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// The JSON-RPC 2.0 error codes.
+const (
+	// ParseErrorCode is sent when the server received an invalid JSON.
+	ParseErrorCode = -32700
+	// InvalidRequestCode is sent when the JSON is not a valid request object.
+	InvalidRequestCode = -32600
+	// MethodNotFoundCode is sent when the method does not exist.
+	MethodNotFoundCode = -32601
+	// InvalidParamsCode is sent on invalid method params, including validation failures.
+	InvalidParamsCode = -32602
+	// InternalErrorCode is sent on internal JSON-RPC errors, e.g. a method's panic.
+	InternalErrorCode = -32603
+	// ServerErrorCode is sent when a method returns a non `*Error` error.
+	ServerErrorCode = -32000
+)
+
+// Error is a JSON-RPC error object.
+// A method can return it to send a custom error code and data.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// NewError returns a new JSON-RPC error.
+func NewError(code int, message string, data interface{}) *Error {
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+// Error completes the error interface.
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// toError converts a method's error to a JSON-RPC error.
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	if errs, ok := context.AsValidationErrors(err); ok {
+		return NewError(InvalidParamsCode, "Invalid params", errs)
+	}
+
+	return NewError(ServerErrorCode, err.Error(), nil)
+}
@@ -0,0 +1,327 @@
+// Package jsonrpc implements a JSON-RPC 2.0 (https://www.jsonrpc.org/specification) server
+// over HTTP and websocket for Iris applications.
+//
+// The exported methods of the registered services are the remote procedures,
+// their input arguments are bound to the registered dependencies of a hero Container
+// and the request's (positional or named) params.
+//
+// Example Code:
+//  rpc := jsonrpc.New(jsonrpc.Options{Container: app.ConfigureContainer().Container})
+//  rpc.Register(new(ArithService))        // calls: "ArithService.Add"
+//  rpc.RegisterName("users", NewUsers)    // calls: "users.Get", "NewUsers" is called per request.
+//
+//  app.Post("/rpc", rpc.Handler)
+//  app.Get("/rpc/ws", websocket.Handler(websocket.New(websocket.DefaultGorillaUpgrader, rpc.Events())))
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+	"github.com/kataras/iris/v12/websocket"
+)
+
+func init() {
+	context.SetHandlerName("iris/jsonrpc.*", "JSON-RPC")
+}
+
+// Options holds the optional settings of a JSON-RPC `Server`.
+type Options struct {
+	// Container is the dependency injection container which resolves
+	// the services' constructors and methods input arguments.
+	// Pass the `app.ConfigureContainer().Container` to share the application's dependencies.
+	// Defaults to a new `hero.Container` with the builtin dependencies.
+	Container *hero.Container
+}
+
+// Server is a JSON-RPC 2.0 server, see the `New` package-level function.
+type Server struct {
+	container *hero.Container
+
+	mu      sync.RWMutex
+	methods map[string]*method // lowercase "service.method" names.
+}
+
+// New returns a new JSON-RPC 2.0 Server.
+// Register services through its `Register` and `RegisterName` methods
+// and serve it through its `Handler` (HTTP) and `Events` (websocket) methods.
+func New(opts ...Options) *Server {
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	container := options.Container
+	if container == nil {
+		container = hero.New()
+	}
+
+	return &Server{
+		container: container,
+		methods:   make(map[string]*method),
+	}
+}
+
+// Register registers the exported methods of the "service" under its type name,
+// see `RegisterName` for more.
+func (s *Server) Register(service interface{}) *Server {
+	typ := reflect.TypeOf(service)
+	if typ.Kind() == reflect.Func && typ.NumOut() > 0 {
+		typ = typ.Out(0)
+	}
+
+	name := indirectType(typ).Name()
+	if name == "" {
+		panic(fmt.Sprintf("jsonrpc: register: cannot resolve the name of %T, use RegisterName instead", service))
+	}
+
+	return s.RegisterName(name, service)
+}
+
+// RegisterName registers the exported methods of the "service" as "name.Method" remote procedures,
+// their names are case-insensitive.
+//
+// The "service" can be a value (e.g. a pointer to a struct)
+// or a constructor function which returns the service value and optionally an error,
+// the constructor's input arguments are resolved by the `Options.Container` on each call.
+//
+// A method can return nothing, a result, an error or a result and an error.
+// Its input arguments are bound to:
+//  - the registered dependencies, e.g. the iris.Context, the standard context.Context or a database service
+//  - the request's params; a positional params array is bound to the rest of the input arguments in order,
+//    a named params object is bound to the single (struct or map) rest input argument.
+// The struct params are validated by the `Application.Validator`.
+//
+// Errors are sent with the `ServerErrorCode` unless an `*Error` is returned.
+func (s *Server) RegisterName(name string, service interface{}) *Server {
+	v := reflect.ValueOf(service)
+
+	var constructor reflect.Value
+	typ := v.Type()
+	if typ.Kind() == reflect.Func {
+		if n := typ.NumOut(); n == 0 || n > 2 || (n == 2 && typ.Out(1) != errorType) {
+			panic(fmt.Sprintf("jsonrpc: register: %s: constructor expected to return a service and optionally an error", name))
+		}
+
+		constructor = v
+		typ = typ.Out(0)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if m.PkgPath != "" { // unexported.
+			continue
+		}
+
+		if out := m.Type.NumOut(); out > 2 || (out == 2 && m.Type.Out(1) != errorType) {
+			continue
+		}
+
+		s.methods[strings.ToLower(name+"."+m.Name)] = &method{
+			name:        name + "." + m.Name,
+			fn:          m.Func,
+			service:     v,
+			constructor: constructor,
+		}
+		n++
+	}
+
+	if n == 0 {
+		panic(fmt.Sprintf("jsonrpc: register: %s: service has no exported methods", name))
+	}
+
+	return s
+}
+
+// Methods returns the registered remote procedure names.
+func (s *Server) Methods() []string {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.methods))
+	for _, m := range s.methods {
+		names = append(names, m.name)
+	}
+	s.mu.RUnlock()
+
+	return names
+}
+
+// Handler serves the JSON-RPC requests (single or batch) of the POST request body.
+// It responds with 204 No Content when all requests are notifications.
+func (s *Server) Handler(ctx context.Context) {
+	if ctx.Method() != http.MethodPost {
+		ctx.StatusCode(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ctx.GetBody()
+	if err != nil {
+		ctx.StopWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	resp := s.Do(ctx, body)
+	if resp == nil {
+		ctx.StatusCode(http.StatusNoContent)
+		return
+	}
+
+	ctx.ContentType(context.ContentJSONHeaderValue)
+	ctx.Write(resp)
+}
+
+// Events returns the websocket events which serve the JSON-RPC requests
+// of the incoming native websocket messages, the responses are sent back to the connection.
+//
+// Usage:
+//  ws := websocket.New(websocket.DefaultGorillaUpgrader, rpc.Events())
+//  app.Get("/rpc/ws", websocket.Handler(ws))
+func (s *Server) Events() websocket.Events {
+	return websocket.Events{
+		websocket.OnNativeMessage: func(nsConn *websocket.NSConn, msg websocket.Message) error {
+			resp := s.Do(websocket.GetContext(nsConn.Conn), msg.Body)
+			if resp != nil {
+				nsConn.Conn.Write(websocket.Message{Body: resp, IsNative: true})
+			}
+
+			return nil
+		},
+	}
+}
+
+// Do executes the JSON-RPC request (or batch) "body" and returns the encoded response,
+// it returns nil if no response should be sent, i.e. on notifications.
+func (s *Server) Do(ctx context.Context, body []byte) []byte {
+	body = bytes.TrimSpace(body)
+
+	var result interface{}
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			result = newErrorResponse(nil, NewError(ParseErrorCode, "Parse error", err.Error()))
+		} else if len(batch) == 0 {
+			result = newErrorResponse(nil, NewError(InvalidRequestCode, "Invalid Request", nil))
+		} else {
+			responses := make([]*response, 0, len(batch))
+			for _, raw := range batch {
+				if resp := s.call(ctx, raw); resp != nil {
+					responses = append(responses, resp)
+				}
+			}
+
+			if len(responses) == 0 {
+				return nil
+			}
+			result = responses
+		}
+	} else {
+		resp := s.call(ctx, body)
+		if resp == nil {
+			return nil
+		}
+		result = resp
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		b, _ = json.Marshal(newErrorResponse(nil, NewError(InternalErrorCode, "Internal error", err.Error())))
+	}
+
+	return b
+}
+
+// call executes a single request, it returns nil on notifications.
+func (s *Server) call(ctx context.Context, raw json.RawMessage) *response {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil {
+		if _, isSyntaxErr := err.(*json.SyntaxError); isSyntaxErr {
+			return newErrorResponse(nil, NewError(ParseErrorCode, "Parse error", err.Error()))
+		}
+		return newErrorResponse(nil, NewError(InvalidRequestCode, "Invalid Request", nil))
+	}
+
+	id, hasID := members["id"]
+	if hasID && !isValidID(id) {
+		return newErrorResponse(nil, NewError(InvalidRequestCode, "Invalid Request", "invalid id"))
+	}
+
+	var version, name string
+	if json.Unmarshal(members["jsonrpc"], &version) != nil || version != "2.0" ||
+		json.Unmarshal(members["method"], &name) != nil || name == "" {
+		return newErrorResponse(id, NewError(InvalidRequestCode, "Invalid Request", nil))
+	}
+
+	params := bytes.TrimSpace(members["params"])
+	if len(params) > 0 && params[0] != '[' && params[0] != '{' {
+		return newErrorResponse(id, NewError(InvalidRequestCode, "Invalid Request", "params must be an array or an object"))
+	}
+
+	s.mu.RLock()
+	m, ok := s.methods[strings.ToLower(name)]
+	s.mu.RUnlock()
+
+	var (
+		result interface{}
+		rpcErr *Error
+	)
+
+	if !ok {
+		rpcErr = NewError(MethodNotFoundCode, "Method not found", name)
+	} else {
+		result, rpcErr = m.call(ctx, s.container, params)
+	}
+
+	if !hasID { // notification.
+		return nil
+	}
+
+	if rpcErr != nil {
+		return newErrorResponse(id, rpcErr)
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return newErrorResponse(id, NewError(InternalErrorCode, "Internal error", err.Error()))
+	}
+
+	return &response{JSONRPC: "2.0", Result: resultJSON, ID: id}
+}
+
+func isValidID(id json.RawMessage) bool {
+	id = bytes.TrimSpace(id)
+	if len(id) == 0 {
+		return false
+	}
+
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+
+	return false
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+func newErrorResponse(id json.RawMessage, err *Error) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+
+	return &response{JSONRPC: "2.0", Error: err, ID: id}
+}
@@ -0,0 +1,136 @@
+package jsonrpc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gavv/httpexpect"
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/jsonrpc"
+)
+
+type (
+	ArithService struct{}
+
+	addArgs struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+
+	greeter struct {
+		prefix string
+	}
+
+	usersService struct {
+		greeter *greeter
+		method  string
+	}
+)
+
+func (s *ArithService) Add(a, b int) int {
+	return a + b
+}
+
+func (s *ArithService) Sum(args addArgs) int {
+	return args.A + args.B
+}
+
+func (s *ArithService) Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, jsonrpc.NewError(1, "division by zero", nil)
+	}
+
+	return a / b, nil
+}
+
+func (s *ArithService) Fail() error {
+	return errors.New("failure")
+}
+
+func newUsersService(ctx iris.Context, g *greeter) *usersService {
+	return &usersService{greeter: g, method: ctx.Method()}
+}
+
+func (s *usersService) Greet(name string) string {
+	return s.greeter.prefix + " " + name + " (" + s.method + ")"
+}
+
+func newApp() *iris.Application {
+	app := iris.New()
+
+	api := app.ConfigureContainer()
+	api.RegisterDependency(&greeter{prefix: "Hello"})
+
+	rpc := jsonrpc.New(jsonrpc.Options{Container: api.Container})
+	rpc.Register(new(ArithService))
+	rpc.RegisterName("users", newUsersService)
+
+	app.Post("/rpc", rpc.Handler)
+	return app
+}
+
+func TestJSONRPC(t *testing.T) {
+	app := newApp()
+	e := httptest.New(t, app)
+
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"ArithService.Add","params":[1,2],"id":1}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","result":3,"id":1}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"arithservice.sum","params":{"a":3,"b":4},"id":"a"}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","result":7,"id":"a"}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"users.Greet","params":["iris"],"id":2}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","result":"Hello iris (POST)","id":2}`)
+
+	// errors.
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"ArithService.Divide","params":[1,0],"id":3}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":1,"message":"division by zero"},"id":3}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"ArithService.Fail","id":4}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":-32000,"message":"failure"},"id":4}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"ArithService.Add","params":[1,2,3],"id":5}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params","data":"expected 2 params but got 3"},"id":5}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"unknown","id":6}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found","data":"unknown"},"id":6}`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method":"ArithService.Add","params":1,"id":7}`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request","data":"params must be an array or an object"},"id":7}`)
+	e.POST("/rpc").WithBytes([]byte(`{"jsonrpc":"2.0","method"`)).Expect().
+		Status(httptest.StatusOK).JSON().Object().Value("error").Object().Value("code").Equal(jsonrpc.ParseErrorCode)
+
+	// batch.
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`[
+		{"jsonrpc":"2.0","method":"ArithService.Add","params":[1,1],"id":1},
+		{"jsonrpc":"2.0","method":"ArithService.Add","params":[2,2]},
+		{"jsonrpc":"1.0","method":"ArithService.Add","id":2}
+	]`)).Expect().
+		Status(httptest.StatusOK),
+		`[{"jsonrpc":"2.0","result":2,"id":1},{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":2}]`)
+	expectJSON(t, e.POST("/rpc").WithBytes([]byte(`[]`)).Expect().
+		Status(httptest.StatusOK),
+		`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`)
+
+	// notifications.
+	e.POST("/rpc").WithBytes([]byte(`[{"jsonrpc":"2.0","method":"ArithService.Add","params":[1,1]}]`)).Expect().
+		Status(httptest.StatusNoContent).Body().Empty()
+}
+
+func expectJSON(t *testing.T, resp *httpexpect.Response, expected string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(resp.Body().Raw())); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected response:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,158 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/hero"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type method struct {
+	name        string
+	fn          reflect.Value // the method expression, the receiver is its first input.
+	service     reflect.Value
+	constructor reflect.Value // optional.
+}
+
+func (m *method) call(ctx context.Context, container *hero.Container, params json.RawMessage) (result interface{}, rpcErr *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, rpcErr = nil, NewError(InternalErrorCode, "Internal error", fmt.Sprint(r))
+		}
+	}()
+
+	receiver := m.service
+	if m.constructor.IsValid() {
+		var err error
+		if receiver, err = construct(ctx, container, m.constructor); err != nil {
+			return nil, toError(err)
+		}
+	}
+
+	typ := m.fn.Type()
+	inputs := make([]reflect.Value, typ.NumIn())
+	inputs[0] = receiver
+
+	var paramsIndex []int
+	for i := 1; i < len(inputs); i++ {
+		v, err := container.Resolve(ctx, typ.In(i))
+		if err == nil {
+			inputs[i] = v
+			continue
+		}
+
+		if err != hero.ErrMissingDependency {
+			return nil, toError(err)
+		}
+
+		paramsIndex = append(paramsIndex, i)
+		inputs[i] = reflect.New(typ.In(i)) // pointer to decode, dereferenced below.
+	}
+
+	if err := bindParams(params, inputs, paramsIndex); err != nil {
+		return nil, err
+	}
+
+	for _, i := range paramsIndex {
+		ptr := inputs[i]
+		if ctx != nil && indirectType(ptr.Type()).Kind() == reflect.Struct {
+			if err := ctx.Application().Validate(ptr.Interface()); err != nil {
+				return nil, toError(err)
+			}
+		}
+
+		inputs[i] = ptr.Elem()
+	}
+
+	outputs := m.fn.Call(inputs)
+	switch len(outputs) {
+	case 1:
+		if typ.Out(0) == errorType {
+			if !outputs[0].IsNil() {
+				return nil, toError(outputs[0].Interface().(error))
+			}
+			return nil, nil
+		}
+		return outputs[0].Interface(), nil
+	case 2:
+		if !outputs[1].IsNil() {
+			return nil, toError(outputs[1].Interface().(error))
+		}
+		return outputs[0].Interface(), nil
+	}
+
+	return nil, nil
+}
+
+// bindParams decodes the positional or named "params" to the "inputs" of "paramsIndex".
+func bindParams(params json.RawMessage, inputs []reflect.Value, paramsIndex []int) *Error {
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil
+	}
+
+	if params[0] == '[' {
+		var positional []json.RawMessage
+		if err := json.Unmarshal(params, &positional); err != nil {
+			return NewError(InvalidParamsCode, "Invalid params", err.Error())
+		}
+
+		if len(positional) > len(paramsIndex) {
+			return NewError(InvalidParamsCode, "Invalid params",
+				fmt.Sprintf("expected %d params but got %d", len(paramsIndex), len(positional)))
+		}
+
+		for j, raw := range positional {
+			if err := json.Unmarshal(raw, inputs[paramsIndex[j]].Interface()); err != nil {
+				return NewError(InvalidParamsCode, "Invalid params", err.Error())
+			}
+		}
+
+		return nil
+	}
+
+	if len(paramsIndex) != 1 {
+		return NewError(InvalidParamsCode, "Invalid params", "named params require a single struct or map argument")
+	}
+
+	if err := json.Unmarshal(params, inputs[paramsIndex[0]].Interface()); err != nil {
+		return NewError(InvalidParamsCode, "Invalid params", err.Error())
+	}
+
+	return nil
+}
+
+// construct calls the service "constructor" with its input arguments resolved by the "container".
+func construct(ctx context.Context, container *hero.Container, constructor reflect.Value) (reflect.Value, error) {
+	typ := constructor.Type()
+	inputs := make([]reflect.Value, typ.NumIn())
+	for i := range inputs {
+		v, err := container.Resolve(ctx, typ.In(i))
+		if err != nil {
+			if err == hero.ErrMissingDependency {
+				err = NewError(InternalErrorCode, "Internal error", fmt.Sprintf("missing dependency: %s", typ.In(i)))
+			}
+			return reflect.Value{}, err
+		}
+		inputs[i] = v
+	}
+
+	outputs := constructor.Call(inputs)
+	if len(outputs) == 2 && !outputs[1].IsNil() {
+		return reflect.Value{}, outputs[1].Interface().(error)
+	}
+
+	return outputs[0], nil
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
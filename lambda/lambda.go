@@ -0,0 +1,348 @@
+// Package lambda runs Iris Applications on AWS Lambda.
+// It converts the API Gateway (REST and HTTP API v2 payloads)
+// and Application Load Balancer events into requests which are served
+// by the built Application's router and converts its responses back to the event's response format.
+//
+// Example Code:
+//  import awslambda "github.com/aws/aws-lambda-go/lambda"
+//
+//  app := iris.New()
+//  app.Get("/", func(ctx iris.Context) { ctx.WriteString("Hello from Lambda") })
+//  awslambda.Start(lambda.New(app.BuildHandler()).Handle)
+package lambda
+
+import (
+	"bytes"
+	stdContext "context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedEvent is returned by `Adapter.Handle`
+// when the event is not an API Gateway or Application Load Balancer request.
+var ErrUnsupportedEvent = errors.New("lambda: unsupported event")
+
+// Adapter converts Lambda events to HTTP requests served by an http.Handler,
+// see the `New` package-level function.
+type Adapter struct {
+	handler http.Handler
+}
+
+// New returns a new Lambda Adapter which serves the events through the "handler",
+// usually the `app.BuildHandler()` so the Application's router and its context pool are reused.
+func New(handler http.Handler) *Adapter {
+	return &Adapter{handler: handler}
+}
+
+type (
+	// event holds the fields of the API Gateway REST (payload v1), HTTP API (payload v2)
+	// and the Application Load Balancer events.
+	event struct {
+		Version                         string              `json:"version"`
+		HTTPMethod                      string              `json:"httpMethod"`
+		Path                            string              `json:"path"`
+		RawPath                         string              `json:"rawPath"`
+		RawQueryString                  string              `json:"rawQueryString"`
+		Headers                         map[string]string   `json:"headers"`
+		MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+		QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+		MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+		Cookies                         []string            `json:"cookies"`
+		Body                            string              `json:"body"`
+		IsBase64Encoded                 bool                `json:"isBase64Encoded"`
+		RequestContext                  struct {
+			RequestID string `json:"requestId"`
+			Identity  struct {
+				SourceIP string `json:"sourceIp"`
+			} `json:"identity"`
+			HTTP *struct {
+				Method   string `json:"method"`
+				SourceIP string `json:"sourceIp"`
+			} `json:"http"`
+			ELB *struct {
+				TargetGroupArn string `json:"targetGroupArn"`
+			} `json:"elb"`
+		} `json:"requestContext"`
+	}
+
+	// Response is the response of the API Gateway REST, HTTP API and Application Load Balancer events.
+	Response struct {
+		StatusCode        int                 `json:"statusCode"`
+		StatusDescription string              `json:"statusDescription,omitempty"` // Application Load Balancer only.
+		Headers           map[string]string   `json:"headers,omitempty"`
+		MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+		Cookies           []string            `json:"cookies,omitempty"` // HTTP API only.
+		Body              string              `json:"body"`
+		IsBase64Encoded   bool                `json:"isBase64Encoded"`
+	}
+)
+
+func (e *event) isV2() bool {
+	return e.Version == "2.0" && e.RequestContext.HTTP != nil
+}
+
+func (e *event) isALB() bool {
+	return e.RequestContext.ELB != nil
+}
+
+// Handle is the Lambda handler function, pass it to the aws-lambda-go's `lambda.Start`.
+// It serves the API Gateway (REST and HTTP API) and Application Load Balancer "event"
+// and returns its `*Response`.
+func (a *Adapter) Handle(ctx stdContext.Context, rawEvent json.RawMessage) (*Response, error) {
+	var e event
+	if err := json.Unmarshal(rawEvent, &e); err != nil {
+		return nil, err
+	}
+
+	r, err := newRequest(ctx, &e)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newResponseWriter()
+	a.handler.ServeHTTP(w, r)
+
+	return newResponse(&e, w), nil
+}
+
+// newRequest converts the event "e" to an HTTP request.
+func newRequest(ctx stdContext.Context, e *event) (*http.Request, error) {
+	var (
+		method, path, rawQuery, remoteAddr string
+	)
+
+	switch {
+	case e.isV2():
+		method, path, rawQuery = e.RequestContext.HTTP.Method, e.RawPath, e.RawQueryString
+		remoteAddr = e.RequestContext.HTTP.SourceIP
+	case e.HTTPMethod != "":
+		method, path = e.HTTPMethod, e.Path
+		remoteAddr = e.RequestContext.Identity.SourceIP
+		rawQuery = queryString(e)
+	default:
+		return nil, ErrUnsupportedEvent
+	}
+
+	body := []byte(e.Body)
+	if e.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(e.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	u := &url.URL{Path: path, RawQuery: rawQuery}
+	if unescaped, err := url.PathUnescape(path); err == nil && unescaped != path {
+		u.Path, u.RawPath = unescaped, path
+	}
+
+	r, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r = r.WithContext(ctx)
+	r.RequestURI = u.RequestURI()
+	r.RemoteAddr = remoteAddr
+
+	if len(e.MultiValueHeaders) > 0 {
+		for key, values := range e.MultiValueHeaders {
+			for _, value := range values {
+				r.Header.Add(key, value)
+			}
+		}
+	} else {
+		for key, value := range e.Headers {
+			// the HTTP API joins the values of a header with commas,
+			// only the headers which are lists can be split safely, e.g. not a date or a cookie.
+			if e.isV2() && isListHeader(key) {
+				for _, v := range strings.Split(value, ",") {
+					r.Header.Add(key, strings.TrimSpace(v))
+				}
+				continue
+			}
+
+			r.Header.Set(key, value)
+		}
+	}
+
+	if len(e.Cookies) > 0 {
+		r.Header.Set("Cookie", strings.Join(e.Cookies, "; "))
+	}
+
+	r.Host = r.Header.Get("Host")
+	r.ContentLength = int64(len(body))
+	if e.RequestContext.RequestID != "" && r.Header.Get("X-Request-Id") == "" {
+		r.Header.Set("X-Request-Id", e.RequestContext.RequestID)
+	}
+
+	return r, nil
+}
+
+// listHeaders are the request headers which are comma-separated lists of values.
+var listHeaders = map[string]struct{}{
+	"Accept":            {},
+	"Accept-Charset":    {},
+	"Accept-Encoding":   {},
+	"Accept-Language":   {},
+	"Cache-Control":     {},
+	"Connection":        {},
+	"Forwarded":         {},
+	"If-Match":          {},
+	"If-None-Match":     {},
+	"Pragma":            {},
+	"Te":                {},
+	"Trailer":           {},
+	"Upgrade":           {},
+	"Via":               {},
+	"X-Forwarded-For":   {},
+	"X-Forwarded-Host":  {},
+	"X-Forwarded-Proto": {},
+}
+
+func isListHeader(key string) bool {
+	_, ok := listHeaders[http.CanonicalHeaderKey(key)]
+	return ok
+}
+
+// queryString returns the raw query of a REST or Application Load Balancer event.
+// The Application Load Balancer sends the query parameters URL-encoded,
+// the REST API decoded.
+func queryString(e *event) string {
+	escape := url.QueryEscape
+	if e.isALB() {
+		escape = func(s string) string { return s }
+	}
+
+	var b strings.Builder
+	add := func(key, value string) {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(escape(key))
+		b.WriteByte('=')
+		b.WriteString(escape(value))
+	}
+
+	if len(e.MultiValueQueryStringParameters) > 0 {
+		for key, values := range e.MultiValueQueryStringParameters {
+			for _, value := range values {
+				add(key, value)
+			}
+		}
+	} else {
+		for key, value := range e.QueryStringParameters {
+			add(key, value)
+		}
+	}
+
+	return b.String()
+}
+
+// newResponse converts the recorded response "w" to the event's "e" response format.
+func newResponse(e *event, w *responseWriter) *Response {
+	resp := &Response{StatusCode: w.statusCode}
+
+	if isBinary(w.header) {
+		resp.Body = base64.StdEncoding.EncodeToString(w.body.Bytes())
+		resp.IsBase64Encoded = true
+	} else {
+		resp.Body = w.body.String()
+	}
+
+	switch {
+	case e.isV2():
+		resp.Headers = make(map[string]string, len(w.header))
+		for key, values := range w.header {
+			if key == "Set-Cookie" {
+				resp.Cookies = values
+				continue
+			}
+			resp.Headers[key] = strings.Join(values, ",")
+		}
+	case len(e.MultiValueHeaders) > 0 || !e.isALB():
+		// REST API supports both, ALB sends and expects multi-value headers
+		// only when they are enabled on its target group.
+		resp.MultiValueHeaders = map[string][]string(w.header)
+	default:
+		resp.Headers = make(map[string]string, len(w.header))
+		for key, values := range w.header {
+			resp.Headers[key] = values[len(values)-1]
+		}
+	}
+
+	if e.isALB() {
+		resp.StatusDescription = strconv.Itoa(w.statusCode) + " " + http.StatusText(w.statusCode)
+	}
+
+	return resp
+}
+
+// isBinary reports whether the response body should be base64-encoded.
+func isBinary(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" {
+		return true
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return false
+	}
+
+	if idx := strings.IndexByte(contentType, ';'); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+
+	if strings.HasPrefix(contentType, "text/") {
+		return false
+	}
+
+	for _, s := range []string{"json", "xml", "javascript", "yaml", "x-www-form-urlencoded", "graphql"} {
+		if strings.Contains(contentType, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// responseWriter records the response of the handler.
+type responseWriter struct {
+	header      http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+var _ http.ResponseWriter = (*responseWriter)(nil)
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.statusCode = statusCode
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush completes the http.Flusher, the body is sent once the handler returns.
+func (w *responseWriter) Flush() {}
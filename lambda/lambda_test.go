@@ -0,0 +1,138 @@
+package lambda_test
+
+import (
+	stdContext "context"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/lambda"
+)
+
+func newAdapter() *lambda.Adapter {
+	app := iris.New()
+	app.Get("/users/{id:int}", func(ctx iris.Context) {
+		ctx.SetCookieKV("session", "s1")
+		ctx.Header("X-Request-Id", ctx.GetHeader("X-Request-Id"))
+		ctx.Writef("%d %s %s %s", ctx.Params().GetIntDefault("id", 0), ctx.URLParam("name"), ctx.GetCookie("lang"), ctx.RemoteAddr())
+	})
+	app.Post("/echo", func(ctx iris.Context) {
+		body, _ := ctx.GetBody()
+		ctx.ContentType("application/octet-stream")
+		ctx.Write(body)
+	})
+	app.Get("/headers", func(ctx iris.Context) {
+		h := ctx.Request().Header
+		ctx.Writef("%d %s", len(h.Values("Accept-Language")), h.Get("If-Modified-Since"))
+	})
+
+	return lambda.New(app.BuildHandler())
+}
+
+func TestAdapterRESTAPI(t *testing.T) {
+	resp, err := newAdapter().Handle(stdContext.Background(), []byte(`{
+		"httpMethod": "GET",
+		"path": "/users/42",
+		"headers": {"Host": "example.com", "Cookie": "lang=en"},
+		"queryStringParameters": {"name": "makis kataras"},
+		"requestContext": {"requestId": "r1", "identity": {"sourceIp": "10.0.0.1"}},
+		"body": ""
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "42 makis kataras en 10.0.0.1"; resp.StatusCode != iris.StatusOK || resp.Body != expected || resp.IsBase64Encoded {
+		t.Fatalf("expected status: %d and body: %q but got: %d and %q", iris.StatusOK, expected, resp.StatusCode, resp.Body)
+	}
+
+	if expected, got := []string{"r1"}, resp.MultiValueHeaders["X-Request-Id"]; !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected request id header: %v but got: %v", expected, got)
+	}
+}
+
+func TestAdapterHTTPAPI(t *testing.T) {
+	adapter := newAdapter()
+	resp, err := adapter.Handle(stdContext.Background(), []byte(`{
+		"version": "2.0",
+		"rawPath": "/users/7",
+		"rawQueryString": "name=gerasimos",
+		"cookies": ["lang=el"],
+		"headers": {"host": "example.com"},
+		"requestContext": {"http": {"method": "GET", "sourceIp": "10.0.0.2"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "7 gerasimos el 10.0.0.2"; resp.StatusCode != iris.StatusOK || resp.Body != expected {
+		t.Fatalf("expected status: %d and body: %q but got: %d and %q", iris.StatusOK, expected, resp.StatusCode, resp.Body)
+	}
+
+	if len(resp.Cookies) != 1 || resp.Headers["Set-Cookie"] != "" {
+		t.Fatalf("expected the Set-Cookie header to be sent as cookies but got: %v and %v", resp.Cookies, resp.Headers)
+	}
+
+	// only the list headers are split.
+	resp, err = adapter.Handle(stdContext.Background(), []byte(`{
+		"version": "2.0",
+		"rawPath": "/headers",
+		"headers": {"accept-language": "el, en", "if-modified-since": "Mon, 02 Jan 2006 15:04:05 GMT"},
+		"requestContext": {"http": {"method": "GET"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "2 Mon, 02 Jan 2006 15:04:05 GMT"; resp.Body != expected {
+		t.Fatalf("expected body: %q but got: %q", expected, resp.Body)
+	}
+
+	// binary bodies.
+	payload := []byte{0, 1, 2, 3}
+	resp, err = adapter.Handle(stdContext.Background(), []byte(`{
+		"version": "2.0",
+		"rawPath": "/echo",
+		"body": "`+base64.StdEncoding.EncodeToString(payload)+`",
+		"isBase64Encoded": true,
+		"requestContext": {"http": {"method": "POST"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := base64.StdEncoding.EncodeToString(payload); !resp.IsBase64Encoded || resp.Body != expected {
+		t.Fatalf("expected base64 body: %q but got: %q (%v)", expected, resp.Body, resp.IsBase64Encoded)
+	}
+}
+
+func TestAdapterALB(t *testing.T) {
+	resp, err := newAdapter().Handle(stdContext.Background(), []byte(`{
+		"httpMethod": "GET",
+		"path": "/users/1",
+		"queryStringParameters": {"name": "a%20b"},
+		"headers": {"host": "example.com"},
+		"requestContext": {"elb": {"targetGroupArn": "arn"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "1 a b  "; resp.Body != expected || resp.StatusDescription != "200 OK" || resp.Headers["X-Request-Id"] != "" {
+		t.Fatalf("expected body: %q and status description: %q but got: %q and %q", expected, "200 OK", resp.Body, resp.StatusDescription)
+	}
+
+	resp, err = newAdapter().Handle(stdContext.Background(), []byte(`{"httpMethod": "GET", "path": "/missing", "requestContext": {"elb": {}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != iris.StatusNotFound || resp.StatusDescription != "404 Not Found" {
+		t.Fatalf("expected status: %d but got: %d (%s)", iris.StatusNotFound, resp.StatusCode, resp.StatusDescription)
+	}
+
+	if _, err = newAdapter().Handle(stdContext.Background(), []byte(`{"source": "aws.events"}`)); err != lambda.ErrUnsupportedEvent {
+		t.Fatalf("expected error: %v but got: %v", lambda.ErrUnsupportedEvent, err)
+	}
+}
@@ -0,0 +1,216 @@
+package iris
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// MaintenanceOptions holds the optional settings of the maintenance mode,
+// see the `Application.SetMaintenance` method.
+type MaintenanceOptions struct {
+	// RetryAfter is the value of the "Retry-After" response header.
+	// Defaults to zero, no header is sent.
+	RetryAfter time.Duration
+	// Allow is a list of request path prefixes which are served normally
+	// during the maintenance, e.g. "/health" and "/admin".
+	// Defaults to nil.
+	Allow []string
+	// AllowFunc, if not nil, reports whether a request is served normally during the maintenance,
+	// e.g. by the client's IP address.
+	// Defaults to nil.
+	AllowFunc func(r *http.Request) bool
+	// Message is the detail of the maintenance response.
+	// Defaults to "The service is under maintenance, please try again later.".
+	Message string
+	// HTML is the maintenance page for the clients which do not accept JSON,
+	// a template with the `.Message` and `.RetryAfter` (seconds) fields.
+	// Defaults to a minimal page with the message.
+	HTML string
+	// Signal, if not nil, toggles the maintenance mode on each receipt of it, e.g. syscall.SIGUSR1.
+	// Defaults to nil.
+	Signal os.Signal
+}
+
+type maintenance struct {
+	on     bool
+	opts   MaintenanceOptions
+	page   *template.Template
+	signal chan os.Signal
+}
+
+const defaultMaintenanceMessage = "The service is under maintenance, please try again later."
+
+var defaultMaintenancePage = template.Must(template.New("maintenance").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Service Unavailable</title></head>
+<body><h1>Service Unavailable</h1><p>{{.Message}}</p></body>
+</html>`))
+
+// SetMaintenance turns the maintenance mode on or off, at serve-time too.
+// During the maintenance all requests, except the `MaintenanceOptions.Allow` ones,
+// are answered with a 503 Service Unavailable "application/problem+json" response
+// (or an HTML page if the client does not accept JSON) and the "Retry-After" header.
+// The requests received while the application is shutting down are answered
+// with the same response and their connection is closed.
+//
+// The "opts" replace the previous ones, if passed.
+//
+// Example Code:
+//  app.SetMaintenance(true, iris.MaintenanceOptions{
+//      RetryAfter: 10 * time.Minute,
+//      Allow:      []string{"/health", "/admin"},
+//  })
+func (app *Application) SetMaintenance(on bool, opts ...MaintenanceOptions) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	m := new(maintenance)
+	if prev, ok := app.maintenance.Load().(*maintenance); ok {
+		*m = *prev
+	}
+	m.on = on
+
+	if len(opts) > 0 {
+		m.opts = opts[0]
+		m.page = defaultMaintenancePage
+		if m.opts.HTML != "" {
+			page, err := template.New("maintenance").Parse(m.opts.HTML)
+			if err != nil {
+				app.logger.Errorf("Maintenance: page: %v", err)
+			} else {
+				m.page = page
+			}
+		}
+
+		if m.signal != nil {
+			signal.Stop(m.signal)
+			close(m.signal)
+			m.signal = nil
+		}
+
+		if m.opts.Signal != nil {
+			m.signal = make(chan os.Signal, 1)
+			signal.Notify(m.signal, m.opts.Signal)
+			app.scheduler.Go(app.toggleMaintenanceOnSignal(m.signal))
+		}
+	} else if m.page == nil {
+		m.page = defaultMaintenancePage
+	}
+
+	app.maintenance.Store(m)
+}
+
+func (app *Application) toggleMaintenanceOnSignal(ch chan os.Signal) func(stdContext.Context) {
+	return func(ctx stdContext.Context) {
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				on := !app.Maintenance()
+				app.SetMaintenance(on)
+				app.logger.Infof("Maintenance: %s", map[bool]string{true: "on", false: "off"}[on])
+			}
+		}
+	}
+}
+
+// Maintenance reports whether the maintenance mode is on.
+func (app *Application) Maintenance() bool {
+	m, ok := app.maintenance.Load().(*maintenance)
+	return ok && m.on
+}
+
+// MaintenanceHandler is a handler which toggles the maintenance mode,
+// register it to an allowed (and protected) route, e.g. app.Any("/admin/maintenance", app.MaintenanceHandler).
+// Its POST, PUT or PATCH methods set the maintenance mode to the "on" URL query parameter ("true" or "false")
+// and all methods respond with the current state as JSON, e.g. {"maintenance": true}.
+func (app *Application) MaintenanceHandler(ctx Context) {
+	switch ctx.Method() {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		on, err := ctx.URLParamBool("on")
+		if err != nil {
+			ctx.StopWithError(http.StatusBadRequest, err)
+			return
+		}
+
+		app.SetMaintenance(on)
+	}
+
+	ctx.JSON(Map{"maintenance": app.Maintenance()})
+}
+
+// maintenanceWrapper is the router wrapper of the maintenance mode,
+// it answers with a 503 response when the maintenance mode is on or the application is shutting down.
+func (app *Application) maintenanceWrapper(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	m, _ := app.maintenance.Load().(*maintenance)
+	draining := atomic.LoadUint32(&app.draining) == 1
+
+	if (m == nil || !m.on) && !draining {
+		next(w, r)
+		return
+	}
+
+	if m == nil {
+		m = &maintenance{page: defaultMaintenancePage}
+	}
+
+	if !draining {
+		if m.opts.AllowFunc != nil && m.opts.AllowFunc(r) {
+			next(w, r)
+			return
+		}
+
+		for _, prefix := range m.opts.Allow {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next(w, r)
+				return
+			}
+		}
+	} else {
+		w.Header().Set("Connection", "close")
+	}
+
+	message := m.opts.Message
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	retryAfter := int(m.opts.RetryAfter / time.Second)
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if accept := r.Header.Get("Accept"); accept == "" || strings.Contains(accept, "json") || !strings.Contains(accept, "html") {
+		w.Header().Set("Content-Type", context.ContentJSONProblemHeaderValue+"; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(Map{
+			"type":   "about:blank",
+			"title":  http.StatusText(http.StatusServiceUnavailable),
+			"status": http.StatusServiceUnavailable,
+			"detail": message,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", context.ContentHTMLHeaderValue+"; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	m.page.Execute(w, Map{"Message": message, "RetryAfter": retryAfter})
+}
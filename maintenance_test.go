@@ -0,0 +1,80 @@
+package iris
+
+import (
+	stdContext "context"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplicationMaintenance(t *testing.T) {
+	app := New()
+	app.Get("/", func(ctx Context) {
+		ctx.WriteString("index")
+	})
+	app.Get("/health", func(ctx Context) {
+		ctx.WriteString("ok")
+	})
+	app.Any("/admin/maintenance", app.MaintenanceHandler)
+
+	h := app.BuildHandler()
+	serve := func(method, path, accept string) *stdhttptest.ResponseRecorder {
+		r := stdhttptest.NewRequest(method, path, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		w := stdhttptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve(http.MethodGet, "/", ""); w.Code != http.StatusOK || w.Body.String() != "index" {
+		t.Fatalf("expected index but got: %d %s", w.Code, w.Body.String())
+	}
+
+	app.SetMaintenance(true, MaintenanceOptions{
+		RetryAfter: 2 * time.Minute,
+		Allow:      []string{"/health", "/admin"},
+		Message:    "back soon",
+	})
+
+	w := serve(http.MethodGet, "/", "application/json")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "120" ||
+		!strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") || !strings.Contains(w.Body.String(), `"detail":"back soon"`) {
+		t.Fatalf("expected maintenance problem but got: %d %v %s", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = serve(http.MethodGet, "/", "text/html,application/xhtml+xml,*/*;q=0.8")
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "<p>back soon</p>") {
+		t.Fatalf("expected maintenance page but got: %d %s", w.Code, w.Body.String())
+	}
+
+	if w = serve(http.MethodGet, "/health", ""); w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("expected allowed health check but got: %d %s", w.Code, w.Body.String())
+	}
+
+	// toggle through the API, options are kept.
+	if w = serve(http.MethodPost, "/admin/maintenance?on=false", ""); !strings.Contains(w.Body.String(), `"maintenance": false`) {
+		t.Fatalf("expected maintenance off but got: %s", w.Body.String())
+	}
+	if w = serve(http.MethodGet, "/", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected status: %d but got: %d", http.StatusOK, w.Code)
+	}
+
+	app.SetMaintenance(true)
+	if w = serve(http.MethodGet, "/", ""); w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "120" {
+		t.Fatalf("expected maintenance but got: %d %v", w.Code, w.Header())
+	}
+	app.SetMaintenance(false)
+
+	// draining.
+	if err := app.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if w = serve(http.MethodGet, "/health", ""); w.Code != http.StatusServiceUnavailable || w.Header().Get("Connection") != "close" {
+		t.Fatalf("expected unavailable while draining but got: %d %v", w.Code, w.Header())
+	}
+}
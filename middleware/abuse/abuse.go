@@ -0,0 +1,242 @@
+// Package abuse provides honeypot routes, which temporarily ban the clients that request them,
+// and tarpit handlers, which slow down the blatant scanners.
+package abuse
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/abuse.*", "Abuse")
+}
+
+// Store keeps the banned client IPs, it can be shared between
+// the instances of a cluster, i.e through redis.
+type Store interface {
+	// Ban bans the "ip" for "d" duration.
+	Ban(ip string, d time.Duration) error
+	// IsBanned reports whether the "ip" is currently banned.
+	IsBanned(ip string) (bool, error)
+}
+
+// Config contains the options for the `Abuse`.
+type Config struct {
+	// Store keeps the banned client IPs.
+	//
+	// Defaults to a new `MemoryStore`.
+	Store Store
+	// BanDuration is the time a client which requested a honeypot route is banned.
+	//
+	// Defaults to one hour.
+	BanDuration time.Duration
+	// ClientIP returns the client IP of a request.
+	//
+	// Defaults to the `Context.RemoteAddr`.
+	ClientIP func(ctx context.Context) string
+	// OnFlagged, if not nil, is called when a client requested a honeypot route, i.e to log it.
+	OnFlagged func(ctx context.Context, ip string)
+	// OnBanned is fired when a banned client sends a request.
+	//
+	// Defaults to 403 Forbidden.
+	OnBanned context.Handler
+	// Tarpit, if greater than zero, delays the honeypot routes responses, see `Tarpit`.
+	Tarpit time.Duration
+}
+
+// Abuse bans the clients that requested one of its honeypot routes
+// from all the routes of the Application.
+type Abuse struct {
+	config Config
+}
+
+// Default is the `Abuse` instance used by the package-level `Honeypot` function,
+// it bans the clients for one hour and keeps them in memory.
+var Default = New(Config{})
+
+// New returns a new `Abuse` based on the "c" Config.
+func New(c Config) *Abuse {
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+
+	if c.BanDuration <= 0 {
+		c.BanDuration = time.Hour
+	}
+
+	if c.ClientIP == nil {
+		c.ClientIP = func(ctx context.Context) string {
+			return ctx.RemoteAddr()
+		}
+	}
+
+	if c.OnBanned == nil {
+		c.OnBanned = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusForbidden)
+		}
+	}
+
+	return &Abuse{config: c}
+}
+
+// Honeypot is a shortcut of the `Default.Honeypot`.
+func Honeypot(paths ...string) func(router.Party) {
+	return Default.Honeypot(paths...)
+}
+
+// Honeypot returns a function which registers the decoy "paths" routes, for all methods, to a Party
+// and guards all the routes of the Application, including the ones registered after it,
+// see the `Guard` middleware. A client which requests a decoy route is banned.
+//
+// Usage:
+//  app.PartyFunc("/", abuse.Honeypot("/wp-login.php", "/.env", "/phpmyadmin"))
+func (a *Abuse) Honeypot(paths ...string) func(router.Party) {
+	return func(p router.Party) {
+		decoys := make(map[*router.Route]struct{})
+		for _, path := range paths {
+			for _, r := range p.Any(path, a.flag) {
+				decoys[r] = struct{}{}
+			}
+		}
+
+		routes, ok := p.(router.RoutesProvider)
+		if !ok {
+			return
+		}
+
+		guarded := make(map[*router.Route]struct{})
+		p.OnBuild(func() error {
+			for _, r := range routes.GetRoutes() {
+				if _, ok := guarded[r]; ok { // on RefreshRouter.
+					continue
+				}
+
+				guarded[r] = struct{}{}
+				if _, ok := decoys[r]; !ok {
+					r.Use(a.Guard)
+				}
+			}
+
+			return nil
+		})
+	}
+}
+
+// Guard is the middleware which fires the OnBanned handler for banned clients.
+func (a *Abuse) Guard(ctx context.Context) {
+	banned, err := a.config.Store.IsBanned(a.config.ClientIP(ctx))
+	if err != nil {
+		ctx.Application().Logger().Errorf("abuse: %v", err)
+	}
+
+	if banned {
+		a.config.OnBanned(ctx)
+		return
+	}
+
+	ctx.Next()
+}
+
+// Ban bans the "ip" for the configured BanDuration.
+func (a *Abuse) Ban(ip string) error {
+	return a.config.Store.Ban(ip, a.config.BanDuration)
+}
+
+// IsBanned reports whether the "ip" is currently banned.
+func (a *Abuse) IsBanned(ip string) bool {
+	banned, _ := a.config.Store.IsBanned(ip)
+	return banned
+}
+
+func (a *Abuse) flag(ctx context.Context) {
+	ip := a.config.ClientIP(ctx)
+	if err := a.Ban(ip); err != nil {
+		ctx.Application().Logger().Errorf("abuse: %v", err)
+	}
+
+	if a.config.OnFlagged != nil {
+		a.config.OnFlagged(ctx, ip)
+	}
+
+	if a.config.Tarpit > 0 {
+		tarpit(ctx, a.config.Tarpit)
+		return
+	}
+
+	ctx.StopWithStatus(http.StatusNotFound)
+}
+
+// Tarpit returns a handler which holds the connection open for "delay",
+// writing one byte of the response body per second, and then responds with 404 Not Found.
+// The handler returns early if the client gives up.
+func Tarpit(delay time.Duration) context.Handler {
+	return func(ctx context.Context) {
+		tarpit(ctx, delay)
+	}
+}
+
+func tarpit(ctx context.Context, delay time.Duration) {
+	ctx.StatusCode(http.StatusNotFound)
+	ctx.ContentType(context.ContentTextHeaderValue)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			ctx.StopExecution()
+			return
+		case <-timer.C:
+			ctx.StopExecution()
+			return
+		case <-ticker.C:
+			ctx.WriteString(" ")
+			ctx.ResponseWriter().Flush()
+		}
+	}
+}
+
+// MemoryStore is an in-memory `Store`, which is the default one.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	banned map[string]time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new, empty, in-memory `Store`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{banned: make(map[string]time.Time)}
+}
+
+// Ban bans the "ip" for "d" duration, it removes the expired bans too.
+func (s *MemoryStore) Ban(ip string, d time.Duration) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	for key, until := range s.banned {
+		if now.After(until) {
+			delete(s.banned, key)
+		}
+	}
+	s.banned[ip] = now.Add(d)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsBanned reports whether the "ip" is currently banned.
+func (s *MemoryStore) IsBanned(ip string) (bool, error) {
+	s.mu.RLock()
+	until, ok := s.banned[ip]
+	s.mu.RUnlock()
+
+	return ok && time.Now().Before(until), nil
+}
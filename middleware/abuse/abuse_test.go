@@ -0,0 +1,81 @@
+package abuse_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/abuse"
+)
+
+func TestHoneypot(t *testing.T) {
+	var flagged []string
+	a := abuse.New(abuse.Config{
+		OnFlagged: func(ctx iris.Context, ip string) {
+			flagged = append(flagged, ip)
+		},
+		Tarpit: 10 * time.Millisecond,
+	})
+
+	app := iris.New()
+	// fake the client's address, the "X-Peer" header is the connection's remote address.
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		r.RemoteAddr = r.Header.Get("X-Peer") + ":1234"
+		router(w, r)
+	})
+	app.PartyFunc("/", a.Honeypot("/wp-login.php", "/.env"))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+	app.PartyFunc("/api", func(api iris.Party) {
+		api.Get("/users", func(ctx iris.Context) {
+			ctx.WriteString("users")
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/api/users").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusOK).Body().Equal("users")
+	e.POST("/wp-login.php").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusNotFound)
+	e.GET("/api/users").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusForbidden)
+	e.GET("/").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusForbidden)
+	// other clients are not affected.
+	e.GET("/").WithHeader("X-Peer", "10.0.0.2").Expect().Status(httptest.StatusOK).Body().Equal("index")
+
+	if expected := []string{"10.0.0.1"}; len(flagged) != 1 || flagged[0] != expected[0] {
+		t.Fatalf("expected flagged clients: %v but got: %v", expected, flagged)
+	}
+
+	if !a.IsBanned("10.0.0.1") || a.IsBanned("10.0.0.2") {
+		t.Fatalf("expected only 10.0.0.1 to be banned")
+	}
+}
+
+func TestTarpit(t *testing.T) {
+	app := iris.New()
+	app.Get("/admin.php", abuse.Tarpit(20*time.Millisecond))
+
+	e := httptest.New(t, app)
+	start := time.Now()
+	e.GET("/admin.php").Expect().Status(httptest.StatusNotFound)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("expected the response to be delayed by at least 20ms but it took: %s", elapsed)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := abuse.NewMemoryStore()
+	s.Ban("10.0.0.1", time.Millisecond)
+	s.Ban("10.0.0.2", time.Hour)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if banned, _ := s.IsBanned("10.0.0.1"); banned {
+		t.Fatalf("expected the ban to be expired")
+	}
+
+	if banned, _ := s.IsBanned("10.0.0.2"); !banned {
+		t.Fatalf("expected to be banned")
+	}
+}
@@ -0,0 +1,278 @@
+// Package access provides a middleware which allows or denies requests
+// based on the client's IP address and, optionally, its country.
+// The lists can be reloaded while the server is running, see `Access.Reload`.
+package access
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/access.*", "Access")
+}
+
+// GeoInfo is the geolocation information of a client IP address.
+// It's available to the handlers through `Geo` and,
+// after `RegisterDependency(access.Geo)`, as a hero/mvc handler input argument.
+type GeoInfo struct {
+	// Country is the ISO 3166-1 alpha-2 country code, i.e "GR".
+	Country string `json:"country"`
+	// ASN is the autonomous system number, zero if unknown.
+	ASN uint `json:"asn,omitempty"`
+	// Organization is the autonomous system organization, if known.
+	Organization string `json:"organization,omitempty"`
+}
+
+// GeoResolver resolves the geolocation information of an IP address.
+type GeoResolver interface {
+	Lookup(ip net.IP) (GeoInfo, error)
+}
+
+// GeoResolverFunc is a function which implements the `GeoResolver`.
+//
+// Example using a MaxMind GeoLite2/GeoIP2 database
+// through the github.com/oschwald/geoip2-golang package:
+//  db, _ := geoip2.Open("GeoLite2-Country.mmdb")
+//  resolver := access.GeoResolverFunc(func(ip net.IP) (access.GeoInfo, error) {
+//      record, err := db.Country(ip)
+//      if err != nil {
+//          return access.GeoInfo{}, err
+//      }
+//      return access.GeoInfo{Country: record.Country.IsoCode}, nil
+//  })
+type GeoResolverFunc func(ip net.IP) (GeoInfo, error)
+
+// Lookup calls the "fn" function.
+func (fn GeoResolverFunc) Lookup(ip net.IP) (GeoInfo, error) {
+	return fn(ip)
+}
+
+// Config contains the access lists and the options of the `Access` middleware.
+// The Allow, Deny and TrustedProxies entries are CIDRs, i.e "10.0.0.0/8", or single IPs.
+type Config struct {
+	// Allow if not empty then only the client IPs that are part of this list are allowed.
+	Allow []string
+	// Deny, the client IPs that are part of this list are denied.
+	// Deny has priority over Allow.
+	Deny []string
+	// AllowCountries if not empty then only the clients from these countries are allowed,
+	// clients with unknown country are denied. It requires a Geo resolver.
+	AllowCountries []string
+	// DenyCountries, the clients from these countries are denied. It requires a Geo resolver.
+	DenyCountries []string
+	// TrustedProxies if not empty then the client IP is resolved from the X-Forwarded-For header,
+	// skipping the addresses of these proxies, only when the request comes from one of them.
+	// If empty then the `Context.RemoteAddr` is used instead,
+	// see the `Configuration.RemoteAddrHeaders` too.
+	TrustedProxies []string
+	// Geo is the geolocation resolver, i.e a MaxMind database reader.
+	// If nil then countries are not checked and `Geo` returns an empty GeoInfo.
+	Geo GeoResolver
+	// OnDenied is fired when a client is denied.
+	//
+	// Defaults to 403 Forbidden.
+	OnDenied context.Handler
+}
+
+type rules struct {
+	allow, deny                   []*net.IPNet
+	allowCountries, denyCountries map[string]struct{}
+	trustedProxies                []*net.IPNet
+	geo                           GeoResolver
+}
+
+// Access is the IP and country access control middleware,
+// register its `Handler` through `Use` or `UseGlobal`.
+type Access struct {
+	rules    atomic.Value // *rules.
+	onDenied context.Handler
+}
+
+// New returns a new `Access` middleware.
+// It panics on invalid CIDRs, use `Reload` to update the lists at serve-time.
+//
+// Usage:
+//  a := access.New(access.Config{Allow: []string{"10.0.0.0/8"}, DenyCountries: []string{"KP"}, Geo: resolver})
+//  app.UseGlobal(a.Handler)
+//  app.ConfigureContainer().RegisterDependency(access.Geo)
+func New(c Config) *Access {
+	a := &Access{onDenied: c.OnDenied}
+	if a.onDenied == nil {
+		a.onDenied = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusForbidden)
+		}
+	}
+
+	if err := a.Reload(c); err != nil {
+		panic(err)
+	}
+
+	return a
+}
+
+// Reload replaces the access lists, the trusted proxies and the geolocation resolver
+// with the "c" ones. The OnDenied field is ignored.
+// It's safe to call it while the server is running, i.e on a configuration file change.
+func (a *Access) Reload(c Config) error {
+	r := &rules{
+		allowCountries: countries(c.AllowCountries),
+		denyCountries:  countries(c.DenyCountries),
+		geo:            c.Geo,
+	}
+
+	var err error
+	if r.allow, err = parseNets(c.Allow); err != nil {
+		return err
+	}
+	if r.deny, err = parseNets(c.Deny); err != nil {
+		return err
+	}
+	if r.trustedProxies, err = parseNets(c.TrustedProxies); err != nil {
+		return err
+	}
+
+	if r.geo == nil && (len(r.allowCountries) > 0 || len(r.denyCountries) > 0) {
+		return fmt.Errorf("access: countries lists require a Geo resolver")
+	}
+
+	a.rules.Store(r)
+	return nil
+}
+
+const geoContextKey = "iris.access.geo"
+
+// Handler is the middleware which fires the OnDenied handler
+// when the client is not allowed, otherwise it continues with the next handler.
+func (a *Access) Handler(ctx context.Context) {
+	r := a.rules.Load().(*rules)
+
+	ip := r.clientIP(ctx)
+	if ip == nil || contains(r.deny, ip) || (len(r.allow) > 0 && !contains(r.allow, ip)) {
+		a.onDenied(ctx)
+		return
+	}
+
+	if r.geo != nil {
+		info, err := r.geo.Lookup(ip)
+		if err == nil {
+			ctx.Values().Set(geoContextKey, info)
+		}
+
+		country := strings.ToUpper(info.Country)
+		if _, denied := r.denyCountries[country]; denied && country != "" {
+			a.onDenied(ctx)
+			return
+		}
+
+		if len(r.allowCountries) > 0 {
+			if _, allowed := r.allowCountries[country]; !allowed {
+				a.onDenied(ctx)
+				return
+			}
+		}
+	}
+
+	ctx.Next()
+}
+
+// ClientIP returns the client IP address of the request
+// based on the current trusted proxies.
+func (a *Access) ClientIP(ctx context.Context) net.IP {
+	return a.rules.Load().(*rules).clientIP(ctx)
+}
+
+// Geo returns the geolocation information of the current client,
+// resolved by the `Access` middleware. It returns an empty GeoInfo
+// if the Geo resolver is missing or it could not resolve the client IP.
+func Geo(ctx context.Context) GeoInfo {
+	info, _ := ctx.Values().Get(geoContextKey).(GeoInfo)
+	return info
+}
+
+func (r *rules) clientIP(ctx context.Context) net.IP {
+	if len(r.trustedProxies) == 0 {
+		return net.ParseIP(ctx.RemoteAddr())
+	}
+
+	peer := ctx.Request().RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(peer))
+	if ip == nil || !contains(r.trustedProxies, ip) {
+		return ip
+	}
+
+	// walk the chain from the closest proxy to the client,
+	// the first untrusted address is the client.
+	forwarded := strings.Split(ctx.GetHeader("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+
+		ip = hop
+		if !contains(r.trustedProxies, hop) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func parseNets(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("access: invalid IP address: %q", s)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("access: %w", err)
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countries(list []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(list))
+	for _, c := range list {
+		m[strings.ToUpper(strings.TrimSpace(c))] = struct{}{}
+	}
+
+	return m
+}
@@ -0,0 +1,80 @@
+package access_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/access"
+)
+
+func TestAccess(t *testing.T) {
+	resolver := access.GeoResolverFunc(func(ip net.IP) (access.GeoInfo, error) {
+		switch ip.String() {
+		case "10.0.0.1":
+			return access.GeoInfo{Country: "GR", ASN: 1241}, nil
+		case "10.0.0.2":
+			return access.GeoInfo{Country: "KP"}, nil
+		default:
+			return access.GeoInfo{}, errors.New("not found")
+		}
+	})
+
+	a := access.New(access.Config{
+		Allow:          []string{"10.0.0.0/8"},
+		Deny:           []string{"10.0.0.66"},
+		DenyCountries:  []string{"kp"},
+		TrustedProxies: []string{"192.168.1.1"},
+		Geo:            resolver,
+	})
+
+	app := iris.New()
+	// fake the client's address, the "X-Peer" header is the connection's remote address.
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		r.RemoteAddr = r.Header.Get("X-Peer") + ":1234"
+		router(w, r)
+	})
+	app.UseGlobal(a.Handler)
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.RegisterDependency(access.Geo)
+		api.Get("/", func(info access.GeoInfo) access.GeoInfo {
+			return info
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusOK).
+		JSON().Equal(access.GeoInfo{Country: "GR", ASN: 1241})
+	// not part of the allow list.
+	e.GET("/").WithHeader("X-Peer", "172.16.0.1").Expect().Status(httptest.StatusForbidden)
+	// part of the deny list.
+	e.GET("/").WithHeader("X-Peer", "10.0.0.66").Expect().Status(httptest.StatusForbidden)
+	// denied country.
+	e.GET("/").WithHeader("X-Peer", "10.0.0.2").Expect().Status(httptest.StatusForbidden)
+	// unknown country.
+	e.GET("/").WithHeader("X-Peer", "10.0.0.3").Expect().Status(httptest.StatusOK).
+		JSON().Equal(access.GeoInfo{})
+	// behind the trusted proxy, the spoofed first address is ignored.
+	e.GET("/").WithHeader("X-Peer", "192.168.1.1").WithHeader("X-Forwarded-For", "10.0.0.2, 10.0.0.1").
+		Expect().Status(httptest.StatusOK).JSON().Equal(access.GeoInfo{Country: "GR", ASN: 1241})
+	// the X-Forwarded-For is not trusted from other peers.
+	e.GET("/").WithHeader("X-Peer", "10.0.0.1").WithHeader("X-Forwarded-For", "10.0.0.66").
+		Expect().Status(httptest.StatusOK)
+
+	// hot reload.
+	if err := a.Reload(access.Config{Deny: []string{"10.0.0.1"}}); err != nil {
+		t.Fatal(err)
+	}
+	e.GET("/").WithHeader("X-Peer", "10.0.0.1").Expect().Status(httptest.StatusForbidden)
+	e.GET("/").WithHeader("X-Peer", "172.16.0.1").Expect().Status(httptest.StatusOK).JSON().Equal(access.GeoInfo{})
+
+	if err := a.Reload(access.Config{Allow: []string{"10.0.0.0/33"}}); err == nil {
+		t.Fatal("expected an error on invalid CIDR")
+	}
+	if err := a.Reload(access.Config{AllowCountries: []string{"GR"}}); err == nil {
+		t.Fatal("expected an error on countries without a Geo resolver")
+	}
+}
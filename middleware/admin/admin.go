@@ -0,0 +1,171 @@
+// Package admin provides an optional admin panel, an Iris Plugin, which shows the routes,
+// the configuration, the sessions and the cache statistics of the running Application
+// and controls its log level and maintenance mode at runtime.
+package admin
+
+import (
+	stdContext "context"
+	"strings"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/cache/client"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/sessions"
+
+	"github.com/kataras/golog"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/admin.*", "Admin")
+}
+
+// Config contains the options for the admin panel.
+type Config struct {
+	// Auth is the handler which guards all the panel's routes,
+	// e.g. a basicauth middleware. It is required, the client's address
+	// is not a proof of trust behind a reverse proxy.
+	Auth context.Handler
+	// Sessions are the session managers, by name, to show their active sessions.
+	Sessions map[string]*sessions.Sessions
+	// Caches are the cache handlers, by name, to show their statistics.
+	Caches map[string]*client.Handler
+	// Maintenance are the options of the maintenance mode when it's turned on from the panel,
+	// the panel itself is always allowed.
+	Maintenance iris.MaintenanceOptions
+}
+
+// Admin is the admin panel Plugin, see `New`.
+type Admin struct {
+	path string
+	c    Config
+}
+
+var _ iris.Plugin = (*Admin)(nil)
+
+// New returns the admin panel Plugin which serves the panel under the "path" Party:
+//  GET /                   the panel, served from the embedded assets
+//  GET /api/routes         the registered routes
+//  GET /api/config         the Application's configuration
+//  GET /api/sessions       the active sessions of each session manager
+//  GET /api/cache          the statistics of each cache handler
+//  GET|PUT /api/log-level  the level of the Application's logger, i.e {"level": "debug"}
+//  GET|PUT /api/maintenance the maintenance mode, i.e {"enabled": true}
+//
+// Usage:
+//  app.RegisterPlugin(admin.New("/admin", admin.Config{Auth: basicauth.New(...)}))
+//
+// It panics if the Config.Auth is nil.
+func New(path string, c Config) *Admin {
+	if c.Auth == nil {
+		panic("admin: Config.Auth is required")
+	}
+
+	c.Maintenance.Allow = append(append([]string(nil), c.Maintenance.Allow...), path)
+	return &Admin{path: path, c: c}
+}
+
+// Name returns "admin".
+func (a *Admin) Name() string {
+	return "admin"
+}
+
+// Configure registers the panel's routes.
+func (a *Admin) Configure(app *iris.Application) {
+	p := app.Party(a.path, a.c.Auth)
+
+	index := strings.Replace(indexHTML, "{{.}}", strings.TrimSuffix(a.path, "/"), -1)
+	p.Get("/", func(ctx iris.Context) {
+		ctx.ContentType(context.ContentHTMLHeaderValue)
+		ctx.WriteString(index)
+	})
+	p.Get("/assets/admin.js", func(ctx iris.Context) {
+		ctx.ContentType(context.ContentJavascriptHeaderValue)
+		ctx.WriteString(adminJS)
+	})
+
+	api := p.Party("/api")
+	api.Get("/routes", func(ctx iris.Context) {
+		routes := app.GetRoutesReadOnly()
+		infos := make([]iris.Map, 0, len(routes))
+		for _, r := range routes {
+			infos = append(infos, iris.Map{
+				"method":   r.Method(),
+				"path":     r.Subdomain() + r.Path(),
+				"name":     r.Name(),
+				"handlers": r.HandlersNames(),
+			})
+		}
+
+		ctx.JSON(infos)
+	})
+
+	api.Get("/config", func(ctx iris.Context) {
+		ctx.JSON(app.ConfigurationReadOnly())
+	})
+
+	api.Get("/sessions", func(ctx iris.Context) {
+		counts := make(map[string]int, len(a.c.Sessions))
+		for name, sess := range a.c.Sessions {
+			counts[name] = sess.Len()
+		}
+
+		ctx.JSON(counts)
+	})
+
+	api.Get("/cache", func(ctx iris.Context) {
+		stats := make(map[string]client.Stats, len(a.c.Caches))
+		for name, h := range a.c.Caches {
+			stats[name] = h.Stats()
+		}
+
+		ctx.JSON(stats)
+	})
+
+	api.Get("/log-level", func(ctx iris.Context) {
+		ctx.JSON(iris.Map{"level": golog.Levels[app.Logger().Level].Name})
+	})
+	api.Put("/log-level", func(ctx iris.Context) {
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := ctx.ReadJSON(&req); err != nil {
+			ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": err.Error()})
+			return
+		}
+
+		level := golog.ParseLevel(strings.ToLower(req.Level))
+		if level == golog.DisableLevel && !strings.HasPrefix(strings.ToLower(req.Level), "disable") {
+			ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": "unknown log level: " + req.Level})
+			return
+		}
+
+		app.Logger().Level = level
+		ctx.JSON(iris.Map{"level": golog.Levels[level].Name})
+	})
+
+	api.Get("/maintenance", func(ctx iris.Context) {
+		ctx.JSON(iris.Map{"enabled": app.Maintenance()})
+	})
+	api.Put("/maintenance", func(ctx iris.Context) {
+		var req struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := ctx.ReadJSON(&req); err != nil {
+			ctx.StopWithJSON(iris.StatusBadRequest, iris.Map{"error": err.Error()})
+			return
+		}
+
+		app.SetMaintenance(req.Enabled, a.c.Maintenance)
+		ctx.JSON(iris.Map{"enabled": app.Maintenance()})
+	})
+}
+
+// Build does nothing.
+func (a *Admin) Build(app *iris.Application) error {
+	return nil
+}
+
+// Shutdown does nothing.
+func (a *Admin) Shutdown(ctx stdContext.Context) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package admin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/cache"
+	"github.com/kataras/iris/v12/cache/client"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/admin"
+	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kataras/iris/v12/sessions"
+
+	"github.com/gavv/httpexpect"
+	"github.com/kataras/golog"
+)
+
+func TestAdmin(t *testing.T) {
+	app := iris.New()
+	sess := sessions.New(sessions.Config{Cookie: "sid"})
+	c := cache.Cache(time.Minute)
+
+	app.Get("/login", func(ctx iris.Context) {
+		sess.Start(ctx).Set("user", "kataras")
+	})
+	app.Get("/cached", c.ServeHTTP, func(ctx iris.Context) {
+		ctx.WriteString("cached")
+	})
+
+	auth := basicauth.New(basicauth.Config{Users: map[string]string{"admin": "password"}})
+	app.RegisterPlugin(admin.New("/admin", admin.Config{
+		Auth:     auth,
+		Sessions: map[string]*sessions.Sessions{"default": sess},
+		Caches:   map[string]*client.Handler{"cached": c},
+	}))
+
+	e := httptest.New(t, app)
+	app.Logger().SetLevel("info")
+	e.GET("/admin").Expect().Status(httptest.StatusUnauthorized)
+
+	e.GET("/login").Expect().Status(httptest.StatusOK)
+	e.GET("/cached").Expect().Body().Equal("cached")
+	e.GET("/cached").Expect().Body().Equal("cached")
+
+	withAuth := func(req *httpexpect.Request) *httpexpect.Response {
+		return req.WithBasicAuth("admin", "password").Expect()
+	}
+
+	withAuth(e.GET("/admin")).Status(httptest.StatusOK).Body().Contains(`src="/admin/assets/admin.js"`)
+	withAuth(e.GET("/admin/assets/admin.js")).Status(httptest.StatusOK).ContentType("text/javascript")
+
+	withAuth(e.GET("/admin/api/sessions")).JSON().Object().ValueEqual("default", 1)
+	withAuth(e.GET("/admin/api/cache")).JSON().Object().Value("cached").Object().
+		ValueEqual("entries", 1).ValueEqual("hits", 1).ValueEqual("misses", 1)
+	withAuth(e.GET("/admin/api/config")).JSON().Object().ContainsKey("charset")
+	withAuth(e.GET("/admin/api/routes")).JSON().Array().Length().Gt(8)
+
+	withAuth(e.GET("/admin/api/log-level")).JSON().Object().ValueEqual("level", "info")
+	withAuth(e.PUT("/admin/api/log-level").WithJSON(iris.Map{"level": "debug"})).
+		Status(httptest.StatusOK).JSON().Object().ValueEqual("level", "debug")
+	if app.Logger().Level != golog.DebugLevel {
+		t.Fatalf("expected the debug level but got: %d", app.Logger().Level)
+	}
+	withAuth(e.PUT("/admin/api/log-level").WithJSON(iris.Map{"level": "verbose"})).
+		Status(httptest.StatusBadRequest).JSON().Object().ValueEqual("error", "unknown log level: verbose")
+
+	withAuth(e.PUT("/admin/api/maintenance").WithJSON(iris.Map{"enabled": true})).
+		JSON().Object().ValueEqual("enabled", true)
+	e.GET("/login").Expect().Status(httptest.StatusServiceUnavailable)
+	withAuth(e.GET("/admin/api/maintenance")).Status(httptest.StatusOK).JSON().Object().ValueEqual("enabled", true)
+	withAuth(e.PUT("/admin/api/maintenance").WithJSON(iris.Map{"enabled": false})).
+		JSON().Object().ValueEqual("enabled", false)
+	e.GET("/login").Expect().Status(httptest.StatusOK)
+}
+
+func TestAdminAuthRequired(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic without an Auth handler")
+		}
+	}()
+
+	admin.New("/admin", admin.Config{})
+}
@@ -0,0 +1,98 @@
+package admin
+
+// The panel's assets, embedded into the binary so the panel needs no files on the disk.
+// The "{{.}}" of the index page is replaced with the panel's path.
+
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Iris Admin</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+pre { background: #f6f6f6; padding: 1em; overflow: auto; }
+</style>
+</head>
+<body data-base="{{.}}">
+<h1>Iris Admin</h1>
+
+<h2>Controls</h2>
+<p>
+<label>Log level
+<select id="log-level">
+<option>debug</option><option>info</option><option>warn</option><option>error</option><option>disable</option>
+</select>
+</label>
+<label><input type="checkbox" id="maintenance"> Maintenance mode</label>
+</p>
+
+<h2>Sessions</h2>
+<table id="sessions"></table>
+
+<h2>Cache</h2>
+<table id="cache"></table>
+
+<h2>Routes</h2>
+<table id="routes"></table>
+
+<h2>Configuration</h2>
+<pre id="config"></pre>
+
+<script src="{{.}}/assets/admin.js"></script>
+</body>
+</html>`
+
+const adminJS = `(function () {
+  var base = document.body.getAttribute("data-base") + "/api";
+
+  function api(method, path, body) {
+    return fetch(base + path, {
+      method: method,
+      credentials: "same-origin",
+      headers: { "Content-Type": "application/json" },
+      body: body ? JSON.stringify(body) : undefined
+    }).then(function (resp) { return resp.json(); });
+  }
+
+  function table(id, head, rows) {
+    var html = "<tr>" + head.map(function (h) { return "<th>" + h + "</th>"; }).join("") + "</tr>";
+    rows.forEach(function (row) {
+      html += "<tr>" + row.map(function (c) {
+        return "<td>" + String(c).replace(/</g, "&lt;") + "</td>";
+      }).join("") + "</tr>";
+    });
+    document.getElementById(id).innerHTML = html;
+  }
+
+  var level = document.getElementById("log-level");
+  var maintenance = document.getElementById("maintenance");
+
+  api("GET", "/log-level").then(function (r) { level.value = r.level; });
+  level.onchange = function () { api("PUT", "/log-level", { level: level.value }); };
+
+  api("GET", "/maintenance").then(function (r) { maintenance.checked = r.enabled; });
+  maintenance.onchange = function () { api("PUT", "/maintenance", { enabled: maintenance.checked }); };
+
+  api("GET", "/sessions").then(function (r) {
+    table("sessions", ["Name", "Active"], Object.keys(r).map(function (k) { return [k, r[k]]; }));
+  });
+
+  api("GET", "/cache").then(function (r) {
+    table("cache", ["Name", "Entries", "Hits", "Misses"], Object.keys(r).map(function (k) {
+      return [k, r[k].entries, r[k].hits, r[k].misses];
+    }));
+  });
+
+  api("GET", "/routes").then(function (r) {
+    table("routes", ["Method", "Path", "Name", "Handlers"], r.map(function (route) {
+      return [route.method, route.path, route.name, route.handlers.join(", ")];
+    }));
+  });
+
+  api("GET", "/config").then(function (r) {
+    document.getElementById("config").textContent = JSON.stringify(r, null, 2);
+  });
+})();
+`
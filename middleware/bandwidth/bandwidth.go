@@ -0,0 +1,220 @@
+// Package bandwidth provides a middleware which accounts the response body bytes
+// and throttles the aggregate bandwidth of each client,
+// so a single client can not saturate the server, i.e on multi-tenant APIs.
+package bandwidth
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/bandwidth.*", "Bandwidth")
+}
+
+// Config contains the options for the `Bandwidth` middleware.
+type Config struct {
+	// BytesPerSecond is the maximum bandwidth of a client, across all its responses.
+	// Zero means no throttling, only accounting.
+	BytesPerSecond int64
+	// Burst is the number of bytes a client may receive at once,
+	// the response body is written in chunks of this size.
+	//
+	// Defaults to the BytesPerSecond.
+	Burst int64
+	// Key returns the client's key of a request.
+	//
+	// Defaults to `ByIP`.
+	Key func(ctx context.Context) string
+}
+
+// ByIP is a `Config.Key` which returns the client's IP address.
+func ByIP(ctx context.Context) string {
+	return ctx.RemoteAddr()
+}
+
+// ByHeader returns a `Config.Key` which returns the value of the "name" request header,
+// i.e an API token, it fallbacks to the client's IP address when the header is missing.
+func ByHeader(name string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		if v := ctx.GetHeader(name); v != "" {
+			return v
+		}
+
+		return ByIP(ctx)
+	}
+}
+
+// Bandwidth accounts and throttles the response bytes per client,
+// register its `Handler` through `Use` or `UseGlobal`.
+type Bandwidth struct {
+	config Config
+	rate   float64 // bytes per nanosecond.
+
+	mu      sync.Mutex
+	clients map[string]*client
+}
+
+type client struct {
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	written int64
+}
+
+// New returns a new `Bandwidth` middleware.
+//
+// Usage:
+//  bw := bandwidth.New(bandwidth.Config{BytesPerSecond: 1 << 20, Key: bandwidth.ByHeader("X-API-Key")})
+//  api := app.Party("/api", bw.Handler)
+func New(c Config) *Bandwidth {
+	if c.Burst <= 0 {
+		c.Burst = c.BytesPerSecond
+	}
+
+	if c.Key == nil {
+		c.Key = ByIP
+	}
+
+	return &Bandwidth{
+		config:  c,
+		rate:    float64(c.BytesPerSecond) / float64(time.Second),
+		clients: make(map[string]*client),
+	}
+}
+
+// Handler is the middleware which wraps the response writer
+// to account and throttle the response body of the current request.
+func (b *Bandwidth) Handler(ctx context.Context) {
+	c := b.client(b.config.Key(ctx))
+
+	w := &responseWriter{ResponseWriter: ctx.ResponseWriter(), b: b, c: c, ctx: ctx}
+	ctx.ResetResponseWriter(w)
+	ctx.Next()
+
+	if ctx.ResponseWriter() == w {
+		ctx.ResetResponseWriter(w.ResponseWriter)
+	}
+}
+
+// Written returns the number of the response body bytes sent to the "key" client so far.
+// Clients which are idle for long are forgotten.
+func (b *Bandwidth) Written(key string) int64 {
+	b.mu.Lock()
+	c, ok := b.clients[key]
+	b.mu.Unlock()
+	if !ok {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.written
+}
+
+// idleTimeout is the time after a client is forgotten.
+const idleTimeout = 10 * time.Minute
+
+func (b *Bandwidth) client(key string) *client {
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.clients[key]
+	if !ok {
+		// remove the idle clients before adding a new one.
+		for k, idle := range b.clients {
+			idle.mu.Lock()
+			expired := now.Sub(idle.last) > idleTimeout
+			idle.mu.Unlock()
+			if expired {
+				delete(b.clients, k)
+			}
+		}
+
+		c = &client{tokens: float64(b.config.Burst), last: now}
+		b.clients[key] = c
+	}
+
+	return c
+}
+
+// reserve accounts "n" bytes to the "c" client and returns
+// the duration the caller should wait before writing them.
+func (b *Bandwidth) reserve(c *client, n int) time.Duration {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.written += int64(n)
+	if b.rate <= 0 {
+		c.last = now
+		return 0
+	}
+
+	c.tokens += float64(now.Sub(c.last)) * b.rate
+	if max := float64(b.config.Burst); c.tokens > max {
+		c.tokens = max
+	}
+	c.last = now
+
+	c.tokens -= float64(n)
+	if c.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-c.tokens / b.rate)
+}
+
+type responseWriter struct {
+	context.ResponseWriter
+	b   *Bandwidth
+	c   *client
+	ctx context.Context
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	chunkSize := len(p)
+	if w.b.rate > 0 && int64(chunkSize) > w.b.config.Burst {
+		chunkSize = int(w.b.config.Burst)
+	}
+
+	written := 0
+	for written < len(p) {
+		chunk := p[written:]
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+
+		if wait := w.b.reserve(w.c, len(chunk)); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-w.ctx.Request().Context().Done():
+				timer.Stop()
+				return written, w.ctx.Request().Context().Err()
+			case <-timer.C:
+			}
+		}
+
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *responseWriter) Writef(format string, a ...interface{}) (int, error) {
+	return fmt.Fprintf(w, format, a...)
+}
@@ -0,0 +1,54 @@
+package bandwidth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/bandwidth"
+)
+
+func TestBandwidth(t *testing.T) {
+	bw := bandwidth.New(bandwidth.Config{
+		BytesPerSecond: 100 << 10,
+		Burst:          10 << 10,
+		Key:            bandwidth.ByHeader("X-API-Key"),
+	})
+
+	body := strings.Repeat("a", 30<<10)
+
+	app := iris.New()
+	app.Use(bw.Handler)
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString(body)
+	})
+	app.Get("/small", func(ctx iris.Context) {
+		ctx.Writef("%s", body[:10<<10])
+	})
+
+	e := httptest.New(t, app)
+
+	start := time.Now()
+	e.GET("/").WithHeader("X-API-Key", "tenant-a").Expect().Status(httptest.StatusOK).Body().Equal(body)
+	// the first 10KB are sent at once, the rest 20KB at 100KB/s.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected the response to be throttled but it took: %s", elapsed)
+	}
+
+	// other clients have their own bandwidth.
+	start = time.Now()
+	e.GET("/small").WithHeader("X-API-Key", "tenant-b").Expect().Status(httptest.StatusOK).Body().Equal(body[:10<<10])
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("expected the response to not be throttled but it took: %s", elapsed)
+	}
+
+	if expected, got := int64(30<<10), bw.Written("tenant-a"); expected != got {
+		t.Fatalf("expected written bytes: %d but got: %d", expected, got)
+	}
+
+	if expected, got := int64(10<<10), bw.Written("tenant-b"); expected != got {
+		t.Fatalf("expected written bytes: %d but got: %d", expected, got)
+	}
+}
@@ -0,0 +1,256 @@
+// Package botdetect provides a middleware which classifies requests as humans, crawlers or bots,
+// based on their User-Agent and behavioral heuristics, and optionally throttles each class
+// separately or serves cached responses to crawlers.
+package botdetect
+
+import (
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/cache"
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/botdetect.*", "BotDetect")
+}
+
+// Class is the classification of a request's client.
+type Class string
+
+const (
+	// Human is the class of the requests which did not match any rule.
+	Human Class = "human"
+	// Crawler is the class of the well-known search engines and social media crawlers.
+	Crawler Class = "crawler"
+	// Bot is the class of the automation tools, HTTP libraries and headless browsers.
+	Bot Class = "bot"
+)
+
+// Classification is the result of the detection,
+// it's stored to the request's values, see `Get`.
+type Classification struct {
+	Class Class `json:"class"`
+	// Name is the name of the rule which matched, empty for humans.
+	Name string `json:"name,omitempty"`
+}
+
+// Rule classifies the requests which its Match function reports true.
+type Rule struct {
+	Name  string
+	Class Class
+	Match func(ctx context.Context) bool
+}
+
+// UserAgentRule returns a Rule which matches the User-Agent header
+// against the "pattern" regular expression, case-insensitive.
+func UserAgentRule(name string, class Class, pattern string) Rule {
+	expr := regexp.MustCompile("(?i)" + pattern)
+	return Rule{
+		Name:  name,
+		Class: class,
+		Match: func(ctx context.Context) bool {
+			return expr.MatchString(ctx.GetHeader("User-Agent"))
+		},
+	}
+}
+
+// DefaultRules are the rules used when `Config.Rules` is empty.
+// They detect the well-known crawlers, the common HTTP tools and libraries,
+// the headless browsers, requests without a User-Agent and
+// browser-like User-Agents which do not send the headers every browser sends.
+var DefaultRules = []Rule{
+	UserAgentRule("google", Crawler, `googlebot|adsbot-google|mediapartners-google`),
+	UserAgentRule("bing", Crawler, `bingbot|msnbot|bingpreview`),
+	UserAgentRule("yandex", Crawler, `yandex(bot|images)`),
+	UserAgentRule("baidu", Crawler, `baiduspider`),
+	UserAgentRule("duckduckgo", Crawler, `duckduckbot`),
+	UserAgentRule("apple", Crawler, `applebot`),
+	UserAgentRule("social", Crawler, `facebookexternalhit|twitterbot|linkedinbot|slackbot|discordbot|telegrambot|whatsapp`),
+	UserAgentRule("headless", Bot, `headlesschrome|phantomjs|puppeteer|playwright|selenium`),
+	UserAgentRule("tool", Bot, `curl|wget|httpie|python-requests|python-urllib|go-http-client|java/|okhttp|libwww-perl|scrapy|axios|node-fetch`),
+	UserAgentRule("generic", Bot, `bot\b|crawler|spider|scraper`),
+	{
+		Name:  "empty",
+		Class: Bot,
+		Match: func(ctx context.Context) bool {
+			return ctx.GetHeader("User-Agent") == ""
+		},
+	},
+	{
+		Name:  "incomplete",
+		Class: Bot,
+		Match: func(ctx context.Context) bool {
+			return ctx.GetHeader("Accept") == "" && ctx.GetHeader("Accept-Language") == ""
+		},
+	},
+}
+
+// Limit is the rate limit of a Class, per client IP.
+type Limit struct {
+	// Requests is the number of the allowed requests per "Per" duration,
+	// it's the burst size too.
+	Requests int
+	Per      time.Duration
+}
+
+// Config contains the options for the `New` middleware.
+type Config struct {
+	// Rules are checked in order, the first matched rule classifies the request.
+	//
+	// Defaults to the `DefaultRules`.
+	Rules []Rule
+	// Limits, if not empty, throttles the requests of each class separately.
+	// Classes without a limit are not throttled.
+	Limits map[Class]Limit
+	// OnLimited is fired when a client exceeded the limit of its class.
+	//
+	// Defaults to 429 Too Many Requests with a Retry-After header.
+	OnLimited context.Handler
+	// CacheCrawlers, if greater than zero, serves the responses to crawlers from a
+	// server-side cache which expires after this duration,
+	// so crawlers hit the route's handlers once per expiration.
+	CacheCrawlers time.Duration
+}
+
+const contextKey = "iris.botdetect"
+
+// Get returns the classification of the current request.
+// It returns a Human classification if the middleware was not executed.
+func Get(ctx context.Context) Classification {
+	if c, ok := ctx.Values().Get(contextKey).(Classification); ok {
+		return c
+	}
+
+	return Classification{Class: Human}
+}
+
+// Classify returns the classification of a request based on the "rules".
+func Classify(ctx context.Context, rules []Rule) Classification {
+	for _, rule := range rules {
+		if rule.Match(ctx) {
+			return Classification{Class: rule.Class, Name: rule.Name}
+		}
+	}
+
+	return Classification{Class: Human}
+}
+
+// New returns a middleware which classifies the requests, stores the classification
+// to the request's values (see `Get`) and applies the limits and the crawlers cache of the "c" Config.
+//
+// Usage:
+//  app.UseGlobal(botdetect.New(botdetect.Config{
+//      Limits:        map[botdetect.Class]botdetect.Limit{botdetect.Bot: {Requests: 10, Per: time.Minute}},
+//      CacheCrawlers: time.Hour,
+//  }))
+func New(c Config) context.Handler {
+	if len(c.Rules) == 0 {
+		c.Rules = DefaultRules
+	}
+
+	limiters := make(map[Class]*limiter, len(c.Limits))
+	for class, limit := range c.Limits {
+		if limit.Requests > 0 && limit.Per > 0 {
+			limiters[class] = newLimiter(limit)
+		}
+	}
+
+	if c.OnLimited == nil {
+		c.OnLimited = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusTooManyRequests)
+		}
+	}
+
+	var crawlersCache context.Handler
+	if c.CacheCrawlers > 0 {
+		crawlersCache = cache.Handler(c.CacheCrawlers)
+	}
+
+	return func(ctx context.Context) {
+		classification := Classify(ctx, c.Rules)
+		ctx.Values().Set(contextKey, classification)
+
+		if l, ok := limiters[classification.Class]; ok {
+			if wait := l.take(ctx.RemoteAddr(), time.Now()); wait > 0 {
+				ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				c.OnLimited(ctx)
+				return
+			}
+		}
+
+		if crawlersCache != nil && classification.Class == Crawler {
+			crawlersCache(ctx)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// limiter is a token bucket per client.
+type limiter struct {
+	capacity float64
+	rate     float64 // tokens per nanosecond.
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(l Limit) *limiter {
+	return &limiter{
+		capacity: float64(l.Requests),
+		rate:     float64(l.Requests) / float64(l.Per),
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// take consumes a token of the "key" client, it returns zero on success
+// or the duration the client should wait for the next token.
+func (l *limiter) take(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.capacity, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.capacity, b.tokens+float64(now.Sub(b.last))*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate)
+	}
+
+	b.tokens--
+	return 0
+}
+
+// sweep removes the full buckets, at most once per the time a bucket needs to refill.
+func (l *limiter) sweep(now time.Time) {
+	full := time.Duration(l.capacity / l.rate)
+	if now.Sub(l.lastSweep) < full {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
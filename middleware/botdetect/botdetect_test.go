@@ -0,0 +1,65 @@
+package botdetect_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/botdetect"
+)
+
+const browserUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0 Safari/537.36"
+
+func TestBotDetect(t *testing.T) {
+	app := iris.New()
+	app.UseGlobal(botdetect.New(botdetect.Config{
+		Limits: map[botdetect.Class]botdetect.Limit{
+			botdetect.Bot: {Requests: 2, Per: time.Minute},
+		},
+		CacheCrawlers: time.Minute,
+	}))
+
+	app.Get("/class", func(ctx iris.Context) {
+		ctx.JSON(botdetect.Get(ctx))
+	})
+
+	hits := 0
+	app.Get("/page", func(ctx iris.Context) {
+		hits++
+		ctx.WriteString(strconv.Itoa(hits))
+	})
+
+	e := httptest.New(t, app)
+
+	tests := []struct {
+		userAgent string
+		expected  botdetect.Classification
+	}{
+		{browserUserAgent, botdetect.Classification{Class: botdetect.Human}},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", botdetect.Classification{Class: botdetect.Crawler, Name: "google"}},
+		{"Twitterbot/1.0", botdetect.Classification{Class: botdetect.Crawler, Name: "social"}},
+	}
+
+	for i, tt := range tests {
+		// crawlers are cached by request URI.
+		e.GET("/class").WithQuery("i", i).WithHeader("User-Agent", tt.userAgent).WithHeader("Accept", "*/*").
+			Expect().Status(httptest.StatusOK).JSON().Equal(tt.expected)
+	}
+
+	// bots are throttled.
+	e.GET("/class").WithHeader("User-Agent", "curl/7.68.0").Expect().Status(httptest.StatusOK).
+		JSON().Equal(botdetect.Classification{Class: botdetect.Bot, Name: "tool"})
+	e.GET("/class").WithHeader("User-Agent", "").Expect().Status(httptest.StatusOK).
+		JSON().Equal(botdetect.Classification{Class: botdetect.Bot, Name: "empty"})
+	e.GET("/class").WithHeader("User-Agent", browserUserAgent).Expect().Status(httptest.StatusTooManyRequests).
+		Header("Retry-After").Equal("30")
+
+	// crawlers are served from the cache, humans are not.
+	googlebot := "Googlebot/2.1"
+	e.GET("/page").WithHeader("User-Agent", googlebot).Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/page").WithHeader("User-Agent", googlebot).Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/page").WithHeader("User-Agent", browserUserAgent).WithHeader("Accept", "text/html").
+		Expect().Status(httptest.StatusOK).Body().Equal("2")
+}
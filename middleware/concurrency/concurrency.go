@@ -0,0 +1,73 @@
+// Package concurrency provides a middleware which caps the simultaneous executions
+// of expensive routes, queues the excess requests and sheds load when saturated.
+package concurrency
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/concurrency.*", "Concurrency")
+}
+
+// Limit returns a middleware which allows up to "n" simultaneous executions
+// of the next handlers. Up to "queue" more requests wait for a free slot for "timeout" at most.
+// Requests that do not fit in the queue or time out waiting
+// are answered with 503 Service Unavailable and a Retry-After header.
+//
+// Each call of Limit has its own slots, register the same handler
+// to more than one routes to share them.
+//
+// Usage:
+//  app.Get("/reports/{id}", concurrency.Limit(4, 16, 5*time.Second), report)
+func Limit(n, queue int, timeout time.Duration) context.Handler {
+	if n <= 0 {
+		n = 1
+	}
+
+	slots := make(chan struct{}, n)
+	var waiting int64
+
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(timeout.Seconds()))))
+	shed := func(ctx context.Context) {
+		ctx.Header("Retry-After", retryAfter)
+		ctx.StopWithStatus(http.StatusServiceUnavailable)
+	}
+
+	return func(ctx context.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if atomic.AddInt64(&waiting, 1) > int64(queue) {
+				atomic.AddInt64(&waiting, -1)
+				shed(ctx)
+				return
+			}
+
+			timer := time.NewTimer(timeout)
+			select {
+			case slots <- struct{}{}:
+				timer.Stop()
+				atomic.AddInt64(&waiting, -1)
+			case <-timer.C:
+				atomic.AddInt64(&waiting, -1)
+				shed(ctx)
+				return
+			case <-ctx.Request().Context().Done():
+				timer.Stop()
+				atomic.AddInt64(&waiting, -1)
+				ctx.StopExecution()
+				return
+			}
+		}
+
+		defer func() { <-slots }()
+		ctx.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package concurrency_test
+
+import (
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/concurrency"
+)
+
+func TestLimit(t *testing.T) {
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	blocking := func(ctx iris.Context) {
+		entered <- struct{}{}
+		<-release
+		ctx.WriteString("done")
+	}
+
+	app := iris.New()
+	app.Get("/queued", concurrency.Limit(1, 1, time.Second), blocking)
+	app.Get("/timeout", concurrency.Limit(1, 1, 30*time.Millisecond), blocking)
+	handler := app.BuildHandler()
+
+	serve := func(path string) *stdhttptest.ResponseRecorder {
+		rec := stdhttptest.NewRecorder()
+		handler.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*stdhttptest.ResponseRecorder, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = serve("/queued")
+		}(i)
+
+		if i == 0 {
+			<-entered
+		}
+	}
+	time.Sleep(20 * time.Millisecond) // let the second request enter the queue.
+
+	// the slot and the queue are full.
+	if rec := serve("/queued"); rec.Code != iris.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected 503 with Retry-After: 1 but got: %d with Retry-After: %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	wg.Wait()
+	<-entered // the second one.
+	for i, rec := range results {
+		if rec.Code != iris.StatusOK || rec.Body.String() != "done" {
+			t.Fatalf("[%d] expected 200 done but got: %d %s", i, rec.Code, rec.Body.String())
+		}
+	}
+
+	// the queued request times out.
+	release = make(chan struct{})
+	go serve("/timeout")
+	<-entered
+	if rec := serve("/timeout"); rec.Code != iris.StatusServiceUnavailable {
+		t.Fatalf("expected 503 but got: %d", rec.Code)
+	}
+	close(release)
+}
@@ -0,0 +1,242 @@
+// Package debug provides a dashboard Party with the profiling, expvar, routes,
+// configuration, runtime and recent errors information of the running Application.
+package debug
+
+import (
+	"expvar"
+	"html/template"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/handlerconv"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/middleware/pprof"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/debug.*", "Debug")
+}
+
+// Config contains the options for the debug dashboard.
+type Config struct {
+	// Auth is the handler which guards all the dashboard's routes,
+	// e.g. a basicauth middleware. The dashboard exposes internal information of the Application.
+	// It is required, the client's address is not a proof of trust behind a reverse proxy.
+	Auth context.Handler
+	// ErrorsLimit is the maximum number of the recent errors to keep.
+	//
+	// Defaults to 50.
+	ErrorsLimit int
+}
+
+// Error is an entry of the recent errors of the Application,
+// a request which responded with a 5xx status code or stored an error through `Context.SetErr`.
+type Error struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route"`
+	StatusCode int       `json:"statusCode"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// New returns a function which registers the debug dashboard routes to a Party:
+//  GET /           the dashboard's index page
+//  GET /pprof/*    the profiling endpoints, see the pprof middleware
+//  GET /vars       the expvar variables
+//  GET /routes     the registered routes and their handlers chain
+//  GET /config     the Application's configuration
+//  GET /runtime    the goroutines count and memory statistics
+//  GET /errors     the recent errors
+//
+// Usage:
+//  app.PartyFunc("/debug", debug.New(debug.Config{Auth: basicauth.New(...)}))
+//
+// It panics if the Config.Auth is nil.
+func New(c Config) func(router.Party) {
+	if c.ErrorsLimit <= 0 {
+		c.ErrorsLimit = 50
+	}
+
+	if c.Auth == nil {
+		panic("debug: Config.Auth is required")
+	}
+
+	return func(p router.Party) {
+		errs := &recentErrors{limit: c.ErrorsLimit}
+		collectErrors(p, errs.collect)
+
+		p.Use(c.Auth)
+
+		startTime := time.Now()
+
+		p.Get("/", index)
+
+		profile := pprof.New()
+		p.Get("/pprof", profile)
+		p.Get("/pprof/{action:path}", profile)
+
+		p.Get("/vars", handlerconv.FromStd(expvar.Handler()))
+
+		p.Get("/routes", func(ctx context.Context) {
+			routes := ctx.Application().GetRoutesReadOnly()
+			infos := make([]routeInfo, 0, len(routes))
+			for _, r := range routes {
+				infos = append(infos, routeInfo{
+					Method:   r.Method(),
+					Path:     r.Subdomain() + r.Path(),
+					Name:     r.Name(),
+					Party:    r.PartyPath(),
+					Handlers: r.HandlersNames(),
+					Source:   r.SourceFileName(),
+					Line:     r.SourceLineNumber(),
+				})
+			}
+
+			ctx.JSON(infos)
+		})
+
+		p.Get("/config", func(ctx context.Context) {
+			ctx.JSON(ctx.Application().ConfigurationReadOnly())
+		})
+
+		p.Get("/runtime", func(ctx context.Context) {
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+
+			ctx.JSON(context.Map{
+				"goVersion":  runtime.Version(),
+				"cpus":       runtime.NumCPU(),
+				"goroutines": runtime.NumGoroutine(),
+				"uptime":     time.Since(startTime).String(),
+				"memory": context.Map{
+					"alloc":      mem.Alloc,
+					"totalAlloc": mem.TotalAlloc,
+					"sys":        mem.Sys,
+					"heapInuse":  mem.HeapInuse,
+					"numGC":      mem.NumGC,
+				},
+			})
+		})
+
+		p.Get("/errors", func(ctx context.Context) {
+			ctx.JSON(errs.list())
+		})
+	}
+}
+
+type routeInfo struct {
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Name     string   `json:"name"`
+	Party    string   `json:"party"`
+	Handlers []string `json:"handlers"`
+	Source   string   `json:"source"`
+	Line     int      `json:"line"`
+}
+
+// collectErrors prepends the "collect" handler to all routes of the Application,
+// including the ones registered after the dashboard.
+func collectErrors(p router.Party, collect context.Handler) {
+	routes, ok := p.(router.RoutesProvider)
+	if !ok {
+		return
+	}
+
+	collected := make(map[*router.Route]struct{})
+	p.OnBuild(func() error {
+		for _, r := range routes.GetRoutes() {
+			if _, ok := collected[r]; ok { // on RefreshRouter.
+				continue
+			}
+
+			collected[r] = struct{}{}
+			r.Use(collect)
+		}
+
+		return nil
+	})
+}
+
+type recentErrors struct {
+	mu    sync.RWMutex
+	limit int
+	// ring buffer.
+	entries []Error
+	next    int
+}
+
+func (e *recentErrors) collect(ctx context.Context) {
+	ctx.Next()
+
+	statusCode := ctx.GetStatusCode()
+	err := ctx.GetErr()
+	if statusCode < 500 && err == nil {
+		return
+	}
+
+	entry := Error{
+		Time:       time.Now(),
+		Method:     ctx.Method(),
+		Path:       ctx.Path(),
+		StatusCode: statusCode,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	if r := ctx.GetCurrentRoute(); r != nil {
+		entry.Route = r.String()
+	}
+
+	e.mu.Lock()
+	if len(e.entries) < e.limit {
+		e.entries = append(e.entries, entry)
+	} else {
+		e.entries[e.next] = entry
+	}
+	e.next = (e.next + 1) % e.limit
+	e.mu.Unlock()
+}
+
+// list returns the recent errors, the newest first.
+func (e *recentErrors) list() []Error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	n := len(e.entries)
+	list := make([]Error, 0, n)
+	for i := 1; i <= n; i++ {
+		list = append(list, e.entries[(e.next-i+n)%n])
+	}
+
+	return list
+}
+
+func index(ctx context.Context) {
+	ctx.ContentType(context.ContentHTMLHeaderValue)
+	if err := indexTmpl.Execute(ctx, ctx.Path()); err != nil {
+		ctx.Application().Logger().Error(err)
+	}
+}
+
+var indexTmpl = template.Must(template.New("index").Parse(`<html>
+	<head>
+	<title>{{.}}</title>
+	</head>
+	<body>
+	<h3>{{.}}</h3>
+	<ul>
+	<li><a href="{{.}}/pprof">profiling</a></li>
+	<li><a href="{{.}}/vars">expvar</a></li>
+	<li><a href="{{.}}/routes">routes</a></li>
+	<li><a href="{{.}}/config">configuration</a></li>
+	<li><a href="{{.}}/runtime">runtime</a></li>
+	<li><a href="{{.}}/errors">recent errors</a></li>
+	</ul>
+	</body>
+	</html>
+`))
@@ -0,0 +1,72 @@
+package debug_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kataras/iris/v12/middleware/debug"
+)
+
+func TestDebug(t *testing.T) {
+	app := iris.New()
+	auth := basicauth.New(basicauth.Config{Users: map[string]string{"admin": "password"}})
+	app.PartyFunc("/debug", debug.New(debug.Config{Auth: auth, ErrorsLimit: 2}))
+
+	// registered after the dashboard.
+	app.Get("/ok", func(ctx iris.Context) {})
+	app.Get("/fail/{n:int}", func(ctx iris.Context) {
+		ctx.SetErr(errors.New("failure " + ctx.Params().Get("n")))
+		ctx.StatusCode(iris.StatusInternalServerError)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/debug/errors").Expect().Status(httptest.StatusUnauthorized)
+
+	for _, path := range []string{"/ok", "/fail/1", "/fail/2", "/fail/3"} {
+		e.GET(path).Expect()
+	}
+
+	errs := e.GET("/debug/errors").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).JSON().Array()
+	errs.Length().Equal(2)
+	errs.Element(0).Object().ValueEqual("error", "failure 3").ValueEqual("statusCode", 500).
+		ValueEqual("route", "GET /fail/{n:int}")
+	errs.Element(1).Object().ValueEqual("error", "failure 2")
+
+	e.GET("/debug").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).Body().Contains(`href="/debug/routes"`)
+
+	routes := e.GET("/debug/routes").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).JSON().Array().Raw()
+	paths := make(map[string]bool)
+	for _, r := range routes {
+		paths[r.(map[string]interface{})["path"].(string)] = true
+	}
+	for _, path := range []string{"/ok", "/fail/{n:int}", "/debug/pprof/{action:path}"} {
+		if !paths[path] {
+			t.Fatalf("expected route %q to be listed", path)
+		}
+	}
+
+	e.GET("/debug/config").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).JSON().Object().ContainsKey("charset")
+	e.GET("/debug/runtime").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).JSON().Object().ContainsKey("goroutines")
+	e.GET("/debug/vars").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK).JSON().Object().ContainsKey("memstats")
+	e.GET("/debug/pprof/cmdline").WithBasicAuth("admin", "password").Expect().
+		Status(httptest.StatusOK)
+}
+
+func TestDebugAuthRequired(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic without an Auth handler")
+		}
+	}()
+
+	debug.New(debug.Config{})
+}
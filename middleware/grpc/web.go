@@ -0,0 +1,211 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kataras/iris/v12/core/router"
+)
+
+const (
+	contentTypeGRPC        = "application/grpc"
+	contentTypeGRPCWeb     = "application/grpc-web"
+	contentTypeGRPCWebText = "application/grpc-web-text"
+
+	// the MSB of the flags byte marks a trailers frame.
+	trailersFrameFlag byte = 1 << 7
+)
+
+// IsGRPCWeb reports whether the "r" is a gRPC-Web request,
+// its content type is "application/grpc-web[-text][+proto]".
+func IsGRPCWeb(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeGRPCWeb)
+}
+
+// NewWeb returns a new Iris router wrapper which serves both gRPC and gRPC-Web clients
+// through the "grpcServer". The gRPC-Web requests are translated to gRPC ones
+// and the responses (and their trailers) back to the gRPC-Web format,
+// so browser clients can call the gRPC services without a proxy (e.g. Envoy).
+// Both the binary and the base64 ("application/grpc-web-text") formats are supported.
+//
+// Browsers send cross-origin gRPC-Web requests,
+// register a CORS middleware which allows the "x-grpc-web", "x-user-agent" and "content-type"
+// request headers and exposes the "grpc-status" and "grpc-message" response headers.
+//
+// Usage:
+//  import grpcWrapper "github.com/kataras/iris/v12/middleware/grpc"
+//  [...]
+//  app := iris.New()
+//  grpcServer := grpc.NewServer()
+//  app.WrapRouter(grpcWrapper.NewWeb(grpcServer))
+func NewWeb(grpcServer http.Handler) router.WrapperFunc {
+	return func(w http.ResponseWriter, r *http.Request, mux http.HandlerFunc) {
+		if IsGRPCWeb(r) {
+			serveWeb(grpcServer, w, r)
+			return
+		}
+
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeGRPC) {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}
+}
+
+func serveWeb(grpcServer http.Handler, w http.ResponseWriter, r *http.Request) {
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, contentTypeGRPCWebText)
+
+	webContentType := contentTypeGRPCWeb
+	if text {
+		webContentType = contentTypeGRPCWebText
+	}
+	// e.g. +proto.
+	subtype := strings.TrimPrefix(contentType, webContentType)
+
+	req := r.Clone(r.Context())
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2", 2, 0
+	req.Header.Set("Content-Type", contentTypeGRPC+subtype)
+	req.Header.Set("Te", "trailers")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = ioutil.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	rw := &webResponseWriter{
+		w:           w,
+		header:      make(http.Header),
+		text:        text,
+		contentType: webContentType + subtype,
+	}
+	grpcServer.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+// webResponseWriter translates the gRPC server's response to gRPC-Web,
+// the trailers are sent as the last, length-prefixed, message of the body.
+type webResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	wroteHeader bool
+	text        bool
+	// enc encodes the whole response body of a text request as a single base64 stream,
+	// the last incomplete group is written on finish.
+	enc         io.WriteCloser
+	contentType string
+}
+
+var _ http.Flusher = (*webResponseWriter)(nil)
+
+func (rw *webResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+// isTrailer reports whether the header "key" is sent as trailer by the gRPC server.
+func (rw *webResponseWriter) isTrailer(key string) bool {
+	if strings.HasPrefix(key, http.TrailerPrefix) {
+		return true
+	}
+
+	for _, declared := range rw.header.Values("Trailer") {
+		for _, k := range strings.Split(declared, ",") {
+			if http.CanonicalHeaderKey(strings.TrimSpace(k)) == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (rw *webResponseWriter) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+
+	h := rw.w.Header()
+	for k, v := range rw.header {
+		if k == "Trailer" || rw.isTrailer(k) {
+			continue
+		}
+		h[k] = v
+	}
+
+	h.Set("Content-Type", rw.contentType)
+	h.Del("Content-Length")
+	rw.w.WriteHeader(statusCode)
+}
+
+func (rw *webResponseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	if rw.text {
+		if rw.enc == nil {
+			rw.enc = base64.NewEncoder(base64.StdEncoding, rw.w)
+		}
+		return rw.enc.Write(b)
+	}
+
+	return rw.w.Write(b)
+}
+
+func (rw *webResponseWriter) Flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := rw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the trailers frame.
+func (rw *webResponseWriter) finish() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	trailers := make(http.Header)
+	for k, v := range rw.header {
+		if k == "Trailer" || !rw.isTrailer(k) {
+			continue
+		}
+		trailers[strings.TrimPrefix(k, http.TrailerPrefix)] = v
+	}
+
+	keys := make([]string, 0, len(trailers))
+	for k := range trailers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var payload bytes.Buffer
+	for _, k := range keys {
+		for _, v := range trailers[k] {
+			payload.WriteString(strings.ToLower(k) + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+payload.Len())
+	frame[0] = trailersFrameFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(payload.Len()))
+	frame = append(frame, payload.Bytes()...)
+
+	rw.Write(frame)
+	if rw.enc != nil {
+		rw.enc.Close()
+	}
+	rw.Flush()
+}
@@ -0,0 +1,78 @@
+package grpc_test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	grpcWrapper "github.com/kataras/iris/v12/middleware/grpc"
+)
+
+func frame(flags byte, payload []byte) []byte {
+	b := make([]byte, 5, 5+len(payload))
+	b[0] = flags
+	binary.BigEndian.PutUint32(b[1:], uint32(len(payload)))
+	return append(b, payload...)
+}
+
+// echoServer acts like a gRPC server which echoes the request message.
+var echoServer = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || r.Header.Get("Content-Type") != "application/grpc+proto" {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusBadRequest)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) < 5 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Add("Trailer", "Grpc-Status")
+	w.Header().Add("Trailer", "Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+	w.Write(frame(0, body[5:]))
+
+	w.Header().Set("Grpc-Status", "0")
+	w.Header().Set("Grpc-Message", "")
+	w.Header().Set(http.TrailerPrefix+"X-Request-Id", "42")
+})
+
+func TestGRPCWeb(t *testing.T) {
+	app := iris.New()
+	app.WrapRouter(grpcWrapper.NewWeb(echoServer))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("index")
+
+	message := []byte("hello")
+	trailers := "grpc-message: \r\ngrpc-status: 0\r\nx-request-id: 42\r\n"
+	expected := string(frame(0, message)) + string(frame(1<<7, []byte(trailers)))
+
+	resp := e.POST("/echo.Service/Echo").WithHeader("Content-Type", "application/grpc-web+proto").
+		WithBytes(frame(0, message)).Expect().Status(httptest.StatusOK)
+	resp.Header("Content-Type").Equal("application/grpc-web+proto")
+	resp.Header("Grpc-Status").Empty()
+	resp.Body().Equal(expected)
+
+	resp = e.POST("/echo.Service/Echo").WithHeader("Content-Type", "application/grpc-web-text+proto").
+		WithBytes([]byte(base64.StdEncoding.EncodeToString(frame(0, message)))).Expect().Status(httptest.StatusOK)
+	resp.Header("Content-Type").Equal("application/grpc-web-text+proto")
+
+	// a single base64 stream, padded only at its end.
+	body, err := base64.StdEncoding.DecodeString(resp.Body().Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != expected {
+		t.Fatalf("expected body: %q but got: %q", expected, body)
+	}
+}
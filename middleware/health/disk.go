@@ -0,0 +1,24 @@
+package health
+
+import (
+	stdContext "context"
+	"fmt"
+)
+
+// DiskSpaceCheck returns a check which reports "down"
+// when the free space of the filesystem that contains the "path"
+// is less than the "minFreeBytes".
+func DiskSpaceCheck(path string, minFreeBytes uint64) CheckFunc {
+	return func(stdContext.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return err
+		}
+
+		if free < minFreeBytes {
+			return fmt.Errorf("disk space: %d bytes free, %d required", free, minFreeBytes)
+		}
+
+		return nil
+	}
+}
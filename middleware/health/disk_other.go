@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package health
+
+import "errors"
+
+func diskFree(string) (uint64, error) {
+	return 0, errors.New("disk space: not supported on this platform")
+}
@@ -0,0 +1,301 @@
+// Package health provides liveness and readiness endpoints with named dependency checks.
+package health
+
+import (
+	stdContext "context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/host"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/health.*", "Health")
+}
+
+// CheckFunc is the signature of a health check, e.g. a database ping.
+// A non-nil error reports that the dependency is down.
+// The "ctx" expires when the check's timeout is passed.
+type CheckFunc func(ctx stdContext.Context) error
+
+// Pinger is implemented by the `sql.DB` and most of the database clients.
+type Pinger interface {
+	PingContext(ctx stdContext.Context) error
+}
+
+// PingCheck returns a check which pings the given database.
+func PingCheck(p Pinger) CheckFunc {
+	return p.PingContext
+}
+
+// Status values of a check and of the whole report.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ErrShuttingDown is reported by the readiness endpoint when the server is shutting down.
+var ErrShuttingDown = errors.New("shutting down")
+
+// Config contains the options for the health endpoints.
+type Config struct {
+	// LivePath is the liveness endpoint's path.
+	//
+	// Defaults to "/healthz".
+	LivePath string
+	// ReadyPath is the readiness endpoint's path.
+	//
+	// Defaults to "/readyz".
+	ReadyPath string
+	// Timeout is the default timeout of each check.
+	//
+	// Defaults to 5 seconds.
+	Timeout time.Duration
+	// CacheDuration, if greater than zero, caches the result of each check
+	// for that amount of time, protecting the dependencies from aggressive probing.
+	//
+	// Defaults to zero.
+	CacheDuration time.Duration
+	// ShutdownDelay is the time to wait, after the readiness flipped to "down"
+	// on an interrupt signal, before the server starts draining its connections.
+	// It gives the load balancers the time to stop routing new requests to this instance.
+	//
+	// Defaults to zero.
+	ShutdownDelay time.Duration
+}
+
+// DefaultConfig returns the default configuration for the health endpoints.
+func DefaultConfig() Config {
+	return Config{
+		LivePath:  "/healthz",
+		ReadyPath: "/readyz",
+		Timeout:   5 * time.Second,
+	}
+}
+
+type check struct {
+	name    string
+	fn      CheckFunc
+	timeout time.Duration
+
+	mu        sync.Mutex
+	result    CheckResult
+	checkedAt time.Time
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the JSON response of the liveness and readiness endpoints.
+type Report struct {
+	Status string                 `json:"status"`
+	Error  string                 `json:"error,omitempty"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Health holds the liveness and readiness checks.
+//
+// Example Code:
+//  h := health.New()
+//  h.AddReadinessCheck("db", health.PingCheck(db))
+//  h.Register(app, app.Party("/health"))
+type Health struct {
+	config Config
+
+	mu              sync.RWMutex
+	livenessChecks  []*check
+	readinessChecks []*check
+
+	shuttingDown uint32
+}
+
+// New returns a new Health.
+// Receives an optional configuration.
+func New(cfg ...Config) *Health {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+		if c.LivePath == "" {
+			c.LivePath = "/healthz"
+		}
+		if c.ReadyPath == "" {
+			c.ReadyPath = "/readyz"
+		}
+		if c.Timeout <= 0 {
+			c.Timeout = 5 * time.Second
+		}
+	}
+
+	return &Health{config: c}
+}
+
+func (h *Health) newCheck(name string, fn CheckFunc, timeout []time.Duration) *check {
+	c := &check{name: name, fn: fn, timeout: h.config.Timeout}
+	if len(timeout) > 0 && timeout[0] > 0 {
+		c.timeout = timeout[0]
+	}
+
+	return c
+}
+
+// AddLivenessCheck adds a named check to the liveness endpoint.
+// Liveness checks should only report failures that a restart of the process would fix.
+// Receives an optional timeout, defaults to the Config.Timeout.
+func (h *Health) AddLivenessCheck(name string, fn CheckFunc, timeout ...time.Duration) *Health {
+	h.mu.Lock()
+	h.livenessChecks = append(h.livenessChecks, h.newCheck(name, fn, timeout))
+	h.mu.Unlock()
+	return h
+}
+
+// AddReadinessCheck adds a named check to the readiness endpoint,
+// e.g. a database ping, a redis ping or a free disk space check.
+// Receives an optional timeout, defaults to the Config.Timeout.
+func (h *Health) AddReadinessCheck(name string, fn CheckFunc, timeout ...time.Duration) *Health {
+	h.mu.Lock()
+	h.readinessChecks = append(h.readinessChecks, h.newCheck(name, fn, timeout))
+	h.mu.Unlock()
+	return h
+}
+
+// Register registers the liveness and readiness endpoints to the "app"
+// or to the optional "p" Party, e.g. app.Party("/health"),
+// an interrupt handler which flips the readiness to "down"
+// and waits for the Config.ShutdownDelay before the server starts draining
+// and an `OnShutdown` hook of the "app" which flips the readiness to "down"
+// on a programmatic `Shutdown` too.
+func (h *Health) Register(app *iris.Application, p ...router.Party) {
+	var party router.Party = app
+	if len(p) > 0 && p[0] != nil {
+		party = p[0]
+	}
+
+	party.Get(h.config.LivePath, h.Live)
+	party.Get(h.config.ReadyPath, h.Ready)
+
+	host.RegisterOnInterrupt(h.drain)
+	app.OnShutdown(h.SetShuttingDown)
+}
+
+func (h *Health) drain() {
+	h.SetShuttingDown()
+	if d := h.config.ShutdownDelay; d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// SetShuttingDown flips the readiness endpoint to "down",
+// it is called automatically on interrupt signals when `Register` is used.
+func (h *Health) SetShuttingDown() {
+	atomic.StoreUint32(&h.shuttingDown, 1)
+}
+
+// IsShuttingDown reports whether the `SetShuttingDown` was called.
+func (h *Health) IsShuttingDown() bool {
+	return atomic.LoadUint32(&h.shuttingDown) == 1
+}
+
+// Live is the liveness endpoint's handler.
+func (h *Health) Live(ctx context.Context) {
+	h.mu.RLock()
+	checks := h.livenessChecks
+	h.mu.RUnlock()
+
+	h.serve(ctx, checks, nil)
+}
+
+// Ready is the readiness endpoint's handler.
+func (h *Health) Ready(ctx context.Context) {
+	if h.IsShuttingDown() {
+		h.serve(ctx, nil, ErrShuttingDown)
+		return
+	}
+
+	h.mu.RLock()
+	checks := h.readinessChecks
+	h.mu.RUnlock()
+
+	h.serve(ctx, checks, nil)
+}
+
+func (h *Health) serve(ctx context.Context, checks []*check, err error) {
+	report := Report{Status: StatusUp}
+	if err != nil {
+		report.Status = StatusDown
+		report.Error = err.Error()
+	} else if len(checks) > 0 {
+		report.Checks = make(map[string]CheckResult, len(checks))
+		results := make([]CheckResult, len(checks))
+
+		var wg sync.WaitGroup
+		wg.Add(len(checks))
+		for i, c := range checks {
+			go func(i int, c *check) {
+				results[i] = h.run(c)
+				wg.Done()
+			}(i, c)
+		}
+		wg.Wait()
+
+		for i, c := range checks {
+			if results[i].Status != StatusUp {
+				report.Status = StatusDown
+			}
+			report.Checks[c.name] = results[i]
+		}
+	}
+
+	if report.Status != StatusUp {
+		ctx.StatusCode(http.StatusServiceUnavailable)
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.JSON(report)
+}
+
+// run runs the check with a context detached from the request's one,
+// a client which goes away does not fail, nor cache the failure of, the check.
+func (h *Health) run(c *check) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if h.config.CacheDuration > 0 && !c.checkedAt.IsZero() && time.Since(c.checkedAt) < h.config.CacheDuration {
+		return c.result
+	}
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{Status: StatusUp, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	c.result = result
+	c.checkedAt = time.Now()
+	return result
+}
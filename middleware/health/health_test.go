@@ -0,0 +1,91 @@
+package health_test
+
+import (
+	stdContext "context"
+	"errors"
+	stdhttptest "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/health"
+)
+
+func TestHealth(t *testing.T) {
+	app := iris.New()
+
+	var (
+		dbErr error
+		calls int
+	)
+
+	h := health.New(health.Config{CacheDuration: time.Hour})
+	h.AddLivenessCheck("goroutines", func(stdContext.Context) error { return nil })
+	h.AddReadinessCheck("db", func(stdContext.Context) error {
+		calls++
+		return dbErr
+	})
+	h.AddReadinessCheck("slow", func(ctx stdContext.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 10*time.Millisecond)
+	h.Register(app)
+
+	e := httptest.New(t, app)
+
+	live := e.GET("/healthz").Expect().Status(httptest.StatusOK).JSON().Object()
+	live.Value("status").Equal(health.StatusUp)
+	live.Value("checks").Object().Value("goroutines").Object().Value("status").Equal(health.StatusUp)
+
+	ready := e.GET("/readyz").Expect().Status(httptest.StatusServiceUnavailable).JSON().Object()
+	ready.Value("status").Equal(health.StatusDown)
+	checks := ready.Value("checks").Object()
+	checks.Value("db").Object().Value("status").Equal(health.StatusUp)
+	checks.Value("slow").Object().Value("error").Equal(stdContext.DeadlineExceeded.Error())
+
+	// cached.
+	dbErr = errors.New("connection refused")
+	e.GET("/readyz").Expect().JSON().Object().
+		Value("checks").Object().Value("db").Object().Value("status").Equal(health.StatusUp)
+	if expected, got := 1, calls; expected != got {
+		t.Fatalf("expected check to be called %d time(s) but called %d", expected, got)
+	}
+
+	h.SetShuttingDown()
+	e.GET("/readyz").Expect().Status(httptest.StatusServiceUnavailable).
+		JSON().Object().Value("error").Equal(health.ErrShuttingDown.Error())
+	e.GET("/healthz").Expect().Status(httptest.StatusOK)
+}
+
+func TestHealthDetachedChecks(t *testing.T) {
+	app := iris.New()
+
+	h := health.New(health.Config{CacheDuration: time.Hour})
+	h.AddReadinessCheck("db", func(ctx stdContext.Context) error {
+		return ctx.Err()
+	})
+	h.Register(app, app.Party("/health"))
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a client which went away does not fail the check.
+	reqCtx, cancel := stdContext.WithCancel(stdContext.Background())
+	cancel()
+	req := stdhttptest.NewRequest("GET", "/health/readyz", nil).WithContext(reqCtx)
+	app.ServeHTTP(stdhttptest.NewRecorder(), req)
+
+	e := httptest.New(t, app)
+	e.GET("/health/readyz").Expect().Status(httptest.StatusOK).JSON().Object().Value("status").Equal(health.StatusUp)
+
+	if err := app.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !h.IsShuttingDown() {
+		t.Fatalf("expected the readiness to flip to down on the application's shutdown")
+	}
+	e.GET("/health/readyz").Expect().Status(httptest.StatusServiceUnavailable)
+}
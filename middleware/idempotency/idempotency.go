@@ -0,0 +1,400 @@
+// Package idempotency provides a middleware which makes non-idempotent requests,
+// such as POST, safe to retry. The response of a request carrying an "Idempotency-Key" header
+// is stored and replayed to any duplicate request with the same key within a TTL,
+// while concurrent duplicates are rejected until the first one completes.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/idempotency.*", "Idempotency")
+}
+
+const (
+	// HeaderKey is the default request header key of the idempotency key.
+	HeaderKey = "Idempotency-Key"
+	// ReplayedHeaderKey is the response header key which is set to "true"
+	// when the response is replayed from the store.
+	ReplayedHeaderKey = "Idempotent-Replayed"
+)
+
+// Response is a stored response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Fingerprint is the SHA-256 hash of the request body which produced the response.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Store describes the storage of the idempotency keys and their responses.
+// See `NewMemoryStore` and the idempotency/redis sub-package.
+type Store interface {
+	// Get returns the stored response of the "key", if any.
+	Get(key string) (*Response, bool, error)
+	// Lock marks the "key" as in-progress for "ttl".
+	// It should report false if the "key" is already locked.
+	Lock(key string, ttl time.Duration) (bool, error)
+	// Save stores the "resp" of the "key" for "ttl" and releases its lock.
+	Save(key string, resp *Response, ttl time.Duration) error
+	// Unlock releases the lock of the "key" without storing a response.
+	Unlock(key string) error
+}
+
+// Options holds the settings for the `New` middleware.
+type Options struct {
+	// Store is the storage of the responses.
+	// Defaults to a `NewMemoryStore()`.
+	Store Store
+	// Header is the request header key of the idempotency key.
+	// Defaults to "Idempotency-Key".
+	Header string
+	// Methods is the list of HTTP methods the middleware applies to.
+	// Defaults to POST and PATCH.
+	Methods []string
+	// TTL is the time a response is kept for replays.
+	// Defaults to 24 hours.
+	TTL time.Duration
+	// LockTTL is the maximum time a request is considered in-progress,
+	// protects against locks that were never released (e.g. a crashed instance).
+	// Defaults to 1 minute.
+	LockTTL time.Duration
+	// OnConflict is fired when a request with the same key is still in-progress.
+	//
+	// Defaults to 409 Conflict.
+	OnConflict context.Handler
+	// OnMismatch is fired when a key is reused with a different request body.
+	//
+	// Defaults to 422 Unprocessable Entity.
+	OnMismatch context.Handler
+	// Scope returns the identity of the client, the keys of a client
+	// can not replay the responses of another one.
+	//
+	// Defaults to `DefaultScope`.
+	Scope func(ctx context.Context) string
+}
+
+// DefaultScope is the default `Options.Scope`.
+// It returns the "Authorization" request header or, if missing, the session ID, if any.
+// The requests without credentials share the same, anonymous, scope.
+func DefaultScope(ctx context.Context) string {
+	if auth := ctx.GetHeader("Authorization"); auth != "" {
+		return "authorization:" + auth
+	}
+
+	if sess := sessions.Get(ctx); sess != nil {
+		return "session:" + sess.ID()
+	}
+
+	return ""
+}
+
+// New returns a new idempotency middleware.
+// Server errors (5xx) are never stored so the client can retry them.
+// The keys are scoped to the client, see `Options.Scope`, a key reused with a different
+// request body is rejected with 422 and the "Set-Cookie" headers are never replayed.
+//
+// Usage:
+//  app.Post("/payments", idempotency.New(idempotency.Options{TTL: time.Hour}), createPayment)
+func New(opts ...Options) context.Handler {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.Store == nil {
+		o.Store = NewMemoryStore()
+	}
+
+	if o.Header == "" {
+		o.Header = HeaderKey
+	}
+
+	if len(o.Methods) == 0 {
+		o.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+
+	if o.TTL <= 0 {
+		o.TTL = 24 * time.Hour
+	}
+
+	if o.LockTTL <= 0 {
+		o.LockTTL = time.Minute
+	}
+
+	if o.OnConflict == nil {
+		o.OnConflict = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusConflict)
+		}
+	}
+
+	if o.OnMismatch == nil {
+		o.OnMismatch = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusUnprocessableEntity)
+		}
+	}
+
+	if o.Scope == nil {
+		o.Scope = DefaultScope
+	}
+
+	methods := make(map[string]struct{}, len(o.Methods))
+	for _, method := range o.Methods {
+		methods[method] = struct{}{}
+	}
+
+	return func(ctx context.Context) {
+		idempotencyKey := ctx.GetHeader(o.Header)
+		if idempotencyKey == "" {
+			ctx.Next()
+			return
+		}
+
+		if _, ok := methods[ctx.Method()]; !ok {
+			ctx.Next()
+			return
+		}
+
+		// scope the client's key to the client and the route so the same key
+		// can not replay a response of a different client or endpoint.
+		scope := sha256.Sum256([]byte(o.Scope(ctx)))
+		key := ctx.Method() + " " + ctx.Path() + " " + hex.EncodeToString(scope[:]) + " " + idempotencyKey
+
+		ctx.RecordRequestBody(true)
+		body, err := ctx.GetBody()
+		if err != nil {
+			if err == context.ErrRequestBodyTooLarge {
+				ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			ctx.StopWithStatus(http.StatusBadRequest)
+			return
+		}
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		// replayStored responds with the stored response of the key, if any.
+		replayStored := func() bool {
+			resp, ok, err := o.Store.Get(key)
+			if err != nil {
+				ctx.Application().Logger().Errorf("idempotency: get: %v", err)
+				ctx.StopWithStatus(http.StatusInternalServerError)
+				return true
+			}
+
+			if !ok {
+				return false
+			}
+
+			if resp.Fingerprint != fingerprint {
+				o.OnMismatch(ctx)
+				return true
+			}
+
+			replay(ctx, resp)
+			return true
+		}
+
+		if replayStored() {
+			return
+		}
+
+		locked, err := o.Store.Lock(key, o.LockTTL)
+		if err != nil {
+			ctx.Application().Logger().Errorf("idempotency: lock: %v", err)
+			ctx.StopWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !locked {
+			// the first request may have saved its response meanwhile.
+			if !replayStored() {
+				o.OnConflict(ctx)
+			}
+			return
+		}
+
+		saved := false
+		defer func() {
+			if !saved {
+				o.Store.Unlock(key)
+			}
+		}()
+
+		ctx.Record()
+		ctx.Next()
+
+		statusCode := ctx.GetStatusCode()
+		if statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		rec := ctx.Recorder()
+		resp := &Response{
+			StatusCode:  statusCode,
+			Header:      rec.Header().Clone(),
+			Body:        append([]byte(nil), rec.Body()...),
+			Fingerprint: fingerprint,
+		}
+		// the cookies belong to the original request, do not replay them.
+		resp.Header.Del("Set-Cookie")
+
+		if err = o.Store.Save(key, resp, o.TTL); err != nil {
+			ctx.Application().Logger().Errorf("idempotency: save: %v", err)
+			return
+		}
+
+		saved = true
+	}
+}
+
+func replay(ctx context.Context, resp *Response) {
+	header := ctx.ResponseWriter().Header()
+	for k, v := range resp.Header {
+		header[k] = v
+	}
+	header.Set(ReplayedHeaderKey, "true")
+
+	ctx.StatusCode(resp.StatusCode)
+	ctx.Write(resp.Body)
+	ctx.StopExecution()
+}
+
+type memoryEntry struct {
+	resp    *Response
+	expires time.Time
+}
+
+// DefaultMemoryStoreSize is the default maximum number of the keys of a `MemoryStore`.
+const DefaultMemoryStoreSize = 100000
+
+// memoryStoreEvictionSamples is the number of the entries compared to find
+// the one which expires first, when the store is full.
+const memoryStoreEvictionSamples = 8
+
+// MemoryStore is an in-memory `Store`, it's suitable for single-instance applications.
+// It keeps a limited number of keys, when it's full the expired keys
+// and the keys which expire first are removed.
+type MemoryStore struct {
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new in-memory `Store`.
+// Receives an optional maximum number of keys, defaults to `DefaultMemoryStoreSize`.
+func NewMemoryStore(maxSize ...int) *MemoryStore {
+	size := DefaultMemoryStoreSize
+	if len(maxSize) > 0 && maxSize[0] > 0 {
+		size = maxSize[0]
+	}
+
+	return &MemoryStore{
+		maxSize: size,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// evict removes the expired entries and the one which expires first
+// of a sample of the entries, the map's iteration order is random. Caller should lock.
+func (s *MemoryStore) evict(now time.Time) {
+	var (
+		oldestKey     string
+		oldestExpires time.Time
+		found         bool
+		sampled       int
+	)
+
+	for key, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+		} else if !found || entry.expires.Before(oldestExpires) {
+			oldestKey, oldestExpires, found = key, entry.expires, true
+		}
+
+		if sampled++; sampled == memoryStoreEvictionSamples {
+			break
+		}
+	}
+
+	if found && len(s.entries) >= s.maxSize {
+		delete(s.entries, oldestKey)
+	}
+}
+
+// get returns the non-expired entry of the "key", caller should lock.
+func (s *MemoryStore) get(key string) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return entry, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(s.entries, key)
+		return entry, false
+	}
+
+	return entry, true
+}
+
+// Get returns the stored response of the "key", if any.
+func (s *MemoryStore) Get(key string) (*Response, bool, error) {
+	s.mu.Lock()
+	entry, ok := s.get(key)
+	s.mu.Unlock()
+
+	if !ok || entry.resp == nil {
+		return nil, false, nil
+	}
+
+	return entry.resp, true, nil
+}
+
+// Lock marks the "key" as in-progress for "ttl".
+func (s *MemoryStore) Lock(key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+
+	now := time.Now()
+	if len(s.entries) >= s.maxSize {
+		s.evict(now)
+	}
+
+	s.entries[key] = memoryEntry{expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Save stores the "resp" of the "key" for "ttl" and releases its lock.
+func (s *MemoryStore) Save(key string, resp *Response, ttl time.Duration) error {
+	s.mu.Lock()
+	s.entries[key] = memoryEntry{resp: resp, expires: time.Now().Add(ttl)}
+	s.mu.Unlock()
+	return nil
+}
+
+// Unlock releases the lock of the "key" without storing a response.
+func (s *MemoryStore) Unlock(key string) error {
+	s.mu.Lock()
+	if entry, ok := s.entries[key]; ok && entry.resp == nil {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,134 @@
+package idempotency_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/idempotency"
+)
+
+func TestIdempotency(t *testing.T) {
+	var (
+		created uint32
+		fails   uint32
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	app := iris.New()
+	app.Use(idempotency.New())
+	app.Post("/payments", func(ctx iris.Context) {
+		n := atomic.AddUint32(&created, 1)
+		ctx.Header("X-Payment", fmt.Sprintf("%d", n))
+		ctx.SetCookieKV("payment", fmt.Sprintf("%d", n))
+		ctx.StatusCode(iris.StatusCreated)
+		ctx.Writef("payment %d", n)
+	})
+	app.Post("/fail", func(ctx iris.Context) {
+		if atomic.AddUint32(&fails, 1) == 1 {
+			ctx.StopWithStatus(iris.StatusServiceUnavailable)
+			return
+		}
+		ctx.WriteString("ok")
+	})
+	app.Post("/slow", func(ctx iris.Context) {
+		close(started)
+		<-release
+		ctx.WriteString("slow")
+	})
+
+	e := httptest.New(t, app)
+
+	e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").Expect().
+		Status(httptest.StatusCreated).Body().Equal("payment 1")
+	resp := e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").Expect().Status(httptest.StatusCreated)
+	resp.Header("X-Payment").Equal("1")
+	resp.Header(idempotency.ReplayedHeaderKey).Equal("true")
+	resp.Header("Set-Cookie").Empty()
+	resp.Body().Equal("payment 1")
+
+	// the same key of a different client or with a different body.
+	e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").WithHeader("Authorization", "Bearer other").Expect().
+		Status(httptest.StatusCreated).Body().Equal("payment 2")
+	e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").WithText("amount=10").Expect().
+		Status(httptest.StatusUnprocessableEntity)
+
+	// different key or no key at all executes the handler.
+	e.POST("/payments").WithHeader(idempotency.HeaderKey, "b").Expect().
+		Status(httptest.StatusCreated).Body().Equal("payment 3")
+	e.POST("/payments").Expect().Status(httptest.StatusCreated).Body().Equal("payment 4")
+
+	// server errors are not stored.
+	e.POST("/fail").WithHeader(idempotency.HeaderKey, "a").Expect().Status(httptest.StatusServiceUnavailable)
+	e.POST("/fail").WithHeader(idempotency.HeaderKey, "a").Expect().Status(httptest.StatusOK).Body().Equal("ok")
+
+	// concurrent duplicates.
+	done := make(chan struct{})
+	go func() {
+		e.POST("/slow").WithHeader(idempotency.HeaderKey, "c").Expect().Status(httptest.StatusOK).Body().Equal("slow")
+		close(done)
+	}()
+	<-started
+	e.POST("/slow").WithHeader(idempotency.HeaderKey, "c").Expect().Status(httptest.StatusConflict)
+	close(release)
+	<-done
+	e.POST("/slow").WithHeader(idempotency.HeaderKey, "c").Expect().Status(httptest.StatusOK).
+		Header(idempotency.ReplayedHeaderKey).Equal("true")
+}
+
+// racyStore misses the next Get, as if the response was saved right after it.
+type racyStore struct {
+	*idempotency.MemoryStore
+	misses int32
+}
+
+func (s *racyStore) Get(key string) (*idempotency.Response, bool, error) {
+	if atomic.AddInt32(&s.misses, -1) >= 0 {
+		return nil, false, nil
+	}
+
+	return s.MemoryStore.Get(key)
+}
+
+func TestIdempotencyReplayAfterLock(t *testing.T) {
+	store := &racyStore{MemoryStore: idempotency.NewMemoryStore()}
+
+	var created uint32
+	app := iris.New()
+	app.Use(idempotency.New(idempotency.Options{Store: store}))
+	app.Post("/payments", func(ctx iris.Context) {
+		ctx.StatusCode(iris.StatusCreated)
+		ctx.Writef("payment %d", atomic.AddUint32(&created, 1))
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").Expect().
+		Status(httptest.StatusCreated).Body().Equal("payment 1")
+
+	atomic.StoreInt32(&store.misses, 1)
+	resp := e.POST("/payments").WithHeader(idempotency.HeaderKey, "a").Expect().Status(httptest.StatusCreated)
+	resp.Header(idempotency.ReplayedHeaderKey).Equal("true")
+	resp.Body().Equal("payment 1")
+}
+
+func TestMemoryStoreSize(t *testing.T) {
+	store := idempotency.NewMemoryStore(2)
+
+	for i, key := range []string{"a", "b", "c"} {
+		if locked, _ := store.Lock(key, time.Duration(i+1)*time.Minute); !locked {
+			t.Fatalf("expected key: %s to be locked", key)
+		}
+	}
+
+	// "a" expires first, it was removed to keep the size.
+	if locked, _ := store.Lock("a", time.Minute); !locked {
+		t.Fatalf("expected the first key to be removed")
+	}
+	if locked, _ := store.Lock("c", time.Minute); locked {
+		t.Fatalf("expected the last key to be kept")
+	}
+}
@@ -0,0 +1,89 @@
+// Package redis provides a Redis `idempotency.Store`,
+// suitable for applications running on multiple instances.
+package redis
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kataras/iris/v12/middleware/idempotency"
+
+	"github.com/mediocregopher/radix/v3"
+)
+
+// lockValue is the value of an in-progress key.
+const lockValue = "-"
+
+// Store is a Redis `idempotency.Store`,
+// see the `New` package-level function.
+type Store struct {
+	// Prefix is prepended to the keys.
+	// Defaults to "iris:idempotency:".
+	Prefix string
+
+	client radix.Client
+}
+
+var _ idempotency.Store = (*Store)(nil)
+
+// New returns a new Redis Store which uses the "client" (e.g. a radix.Pool).
+//
+// Example Code:
+//  pool, _ := radix.NewPool("tcp", "127.0.0.1:6379", 10)
+//  app.Post("/payments", idempotency.New(idempotency.Options{Store: redis.New(pool)}), createPayment)
+func New(client radix.Client) *Store {
+	return &Store{
+		Prefix: "iris:idempotency:",
+		client: client,
+	}
+}
+
+// Get returns the stored response of the "key", if any.
+func (s *Store) Get(key string) (*idempotency.Response, bool, error) {
+	var mn radix.MaybeNil
+	var b []byte
+	mn.Rcv = &b
+	if err := s.client.Do(radix.Cmd(&mn, "GET", s.Prefix+key)); err != nil {
+		return nil, false, err
+	}
+
+	if mn.Nil || string(b) == lockValue {
+		return nil, false, nil
+	}
+
+	resp := new(idempotency.Response)
+	if err := json.Unmarshal(b, resp); err != nil {
+		return nil, false, err
+	}
+
+	return resp, true, nil
+}
+
+// Lock marks the "key" as in-progress for "ttl", through SET NX.
+func (s *Store) Lock(key string, ttl time.Duration) (bool, error) {
+	var mn radix.MaybeNil
+	err := s.client.Do(radix.FlatCmd(&mn, "SET", s.Prefix+key, lockValue, "PX", ttl.Milliseconds(), "NX"))
+	if err != nil {
+		return false, err
+	}
+
+	return !mn.Nil, nil
+}
+
+// Save stores the "resp" of the "key" for "ttl" and releases its lock.
+func (s *Store) Save(key string, resp *idempotency.Response, ttl time.Duration) error {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Do(radix.FlatCmd(nil, "SET", s.Prefix+key, b, "PX", ttl.Milliseconds()))
+}
+
+// unlockScript deletes the key only if it's still in-progress.
+var unlockScript = radix.NewEvalScript(1, `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// Unlock releases the lock of the "key" without storing a response.
+func (s *Store) Unlock(key string) error {
+	return s.client.Do(unlockScript.Cmd(nil, s.Prefix+key, lockValue))
+}
@@ -0,0 +1,329 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Collector describes a metric family which can write
+// its samples in the Prometheus text exposition format.
+type Collector interface {
+	Collect(w io.Writer)
+}
+
+// CollectorFunc implements the `Collector` interface through a simple function.
+type CollectorFunc func(w io.Writer)
+
+// Collect calls the "fn" function itself.
+func (fn CollectorFunc) Collect(w io.Writer) {
+	fn(w)
+}
+
+// DefBuckets are the default histogram buckets (in seconds) for request durations.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const labelValuesSep = "\xff"
+
+type vec struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+
+	mu       sync.RWMutex
+	children map[string]interface{}
+}
+
+func newVec(name, help, typ string, labels []string) *vec {
+	return &vec{
+		name:     name,
+		help:     help,
+		typ:      typ,
+		labels:   labels,
+		children: make(map[string]interface{}),
+	}
+}
+
+func (v *vec) get(labelValues []string, create func() interface{}) interface{} {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s: expected %d label values but got %d", v.name, len(v.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, labelValuesSep)
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	if child, ok = v.children[key]; !ok {
+		child = create()
+		v.children[key] = child
+	}
+	v.mu.Unlock()
+
+	return child
+}
+
+// each calls "fn" for each child sorted by their label values.
+func (v *vec) each(fn func(labelValues []string, child interface{})) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var labelValues []string
+		if len(v.labels) > 0 {
+			labelValues = strings.Split(key, labelValuesSep)
+		}
+		fn(labelValues, v.children[key])
+	}
+	v.mu.RUnlock()
+}
+
+func (v *vec) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, escapeHelp(v.help), v.name, v.typ)
+}
+
+// atomicFloat is a float64 which can be modified concurrently.
+type atomicFloat struct {
+	bits uint64
+}
+
+func (f *atomicFloat) add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&f.bits)
+		n := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&f.bits, old, n) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) set(v float64) {
+	atomic.StoreUint64(&f.bits, math.Float64bits(v))
+}
+
+func (f *atomicFloat) get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&f.bits))
+}
+
+// CounterVec is a counter metric partitioned by a set of labels.
+// A counter can only go up.
+type CounterVec struct {
+	*vec
+}
+
+// NewCounterVec returns a new counter metric.
+// Use the `Metrics.Register` to expose it.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(name, help, "counter", labelNames)}
+}
+
+func (c *CounterVec) value(labelValues []string) *atomicFloat {
+	return c.get(labelValues, func() interface{} { return new(atomicFloat) }).(*atomicFloat)
+}
+
+// Inc increments the counter of the given label values by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.value(labelValues).add(1)
+}
+
+// Add adds the given, non-negative, value to the counter of the given label values.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease in value")
+	}
+
+	c.value(labelValues).add(v)
+}
+
+// Collect writes the counter samples to "w".
+func (c *CounterVec) Collect(w io.Writer) {
+	c.writeHeader(w)
+	c.each(func(labelValues []string, child interface{}) {
+		writeSample(w, c.name, c.labels, labelValues, "", "", child.(*atomicFloat).get())
+	})
+}
+
+// GaugeVec is a gauge metric partitioned by a set of labels.
+// A gauge can go up and down.
+type GaugeVec struct {
+	*vec
+}
+
+// NewGaugeVec returns a new gauge metric.
+// Use the `Metrics.Register` to expose it.
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, help, "gauge", labelNames)}
+}
+
+func (g *GaugeVec) value(labelValues []string) *atomicFloat {
+	return g.get(labelValues, func() interface{} { return new(atomicFloat) }).(*atomicFloat)
+}
+
+// Set sets the gauge of the given label values to "v".
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.value(labelValues).set(v)
+}
+
+// Add adds "v" (may be negative) to the gauge of the given label values.
+func (g *GaugeVec) Add(v float64, labelValues ...string) {
+	g.value(labelValues).add(v)
+}
+
+// Inc increments the gauge of the given label values by one.
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.value(labelValues).add(1)
+}
+
+// Dec decrements the gauge of the given label values by one.
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.value(labelValues).add(-1)
+}
+
+// Collect writes the gauge samples to "w".
+func (g *GaugeVec) Collect(w io.Writer) {
+	g.writeHeader(w)
+	g.each(func(labelValues []string, child interface{}) {
+		writeSample(w, g.name, g.labels, labelValues, "", "", child.(*atomicFloat).get())
+	})
+}
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // per bucket, not cumulative.
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a histogram metric partitioned by a set of labels.
+// It counts observations in configurable buckets.
+type HistogramVec struct {
+	*vec
+	buckets []float64
+}
+
+// NewHistogramVec returns a new histogram metric.
+// If "buckets" is empty then the `DefBuckets` are used instead.
+// Use the `Metrics.Register` to expose it.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+
+	return &HistogramVec{vec: newVec(name, help, "histogram", labelNames), buckets: b}
+}
+
+// Observe adds a single observation to the histogram of the given label values.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	child := h.get(labelValues, func() interface{} {
+		return &histogram{counts: make([]uint64, len(h.buckets))}
+	}).(*histogram)
+
+	i := sort.SearchFloat64s(h.buckets, v)
+
+	child.mu.Lock()
+	if i < len(h.buckets) {
+		child.counts[i]++
+	}
+	child.count++
+	child.sum += v
+	child.mu.Unlock()
+}
+
+// Collect writes the histogram samples to "w".
+func (h *HistogramVec) Collect(w io.Writer) {
+	h.writeHeader(w)
+	h.each(func(labelValues []string, c interface{}) {
+		child := c.(*histogram)
+		child.mu.Lock()
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += child.counts[i]
+			writeSample(w, h.name+"_bucket", h.labels, labelValues, "le", formatFloat(upper), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, labelValues, "le", "+Inf", float64(child.count))
+		writeSample(w, h.name+"_sum", h.labels, labelValues, "", "", child.sum)
+		writeSample(w, h.name+"_count", h.labels, labelValues, "", "", float64(child.count))
+		child.mu.Unlock()
+	})
+}
+
+func writeSample(w io.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	var b bytes.Buffer
+	b.WriteString(name)
+
+	if len(labelNames) > 0 || extraName != "" {
+		b.WriteByte('{')
+		for i, labelName := range labelNames {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labelName)
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(labelValues[i]))
+			b.WriteByte('"')
+		}
+
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(extraName)
+			b.WriteString(`="`)
+			b.WriteString(extraValue)
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+
+	w.Write(b.Bytes())
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
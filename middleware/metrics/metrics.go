@@ -0,0 +1,179 @@
+// Package metrics provides a native metrics subsystem which can be scraped by Prometheus.
+// It records the total requests, their durations and the in-flight requests
+// labeled by route name, method and status class.
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/metrics.*", "Metrics")
+}
+
+// ContentType is the Prometheus text exposition format's content type.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Config contains the options for the metrics subsystem.
+type Config struct {
+	// Namespace is prepended, with an underscore, to the builtin and
+	// to the custom metrics registered through `NewCounter`, `NewGauge` and `NewHistogram`.
+	//
+	// Defaults to "iris".
+	Namespace string
+	// Buckets are the request duration histogram's buckets in seconds.
+	//
+	// Defaults to `DefBuckets`.
+	Buckets []float64
+	// DisableGoCollector disables the Go runtime metrics.
+	//
+	// Defaults to false.
+	DisableGoCollector bool
+	// DisableProcessCollector disables the process metrics.
+	//
+	// Defaults to false.
+	DisableProcessCollector bool
+}
+
+// DefaultConfig returns the default configuration for the metrics subsystem.
+func DefaultConfig() Config {
+	return Config{
+		Namespace: "iris",
+		Buckets:   DefBuckets,
+	}
+}
+
+// Metrics holds the registered collectors.
+// Use its `Handler` as a middleware to record the requests
+// and its `Expose` as the "/metrics" route's handler.
+//
+// Example Code:
+//  m := metrics.New()
+//  app.UseGlobal(m.Handler)
+//  app.Get("/metrics", m.Expose)
+type Metrics struct {
+	config Config
+
+	mu         sync.RWMutex
+	collectors []Collector
+
+	requests *CounterVec
+	duration *HistogramVec
+	inFlight *GaugeVec
+}
+
+// New returns a new metrics subsystem.
+// Receives an optional configuration.
+func New(cfg ...Config) *Metrics {
+	c := DefaultConfig()
+	if len(cfg) > 0 {
+		c = cfg[0]
+		if len(c.Buckets) == 0 {
+			c.Buckets = DefBuckets
+		}
+	}
+
+	m := &Metrics{config: c}
+
+	m.requests = m.NewCounter("http_requests_total", "Total number of HTTP requests.", "route", "method", "code")
+	m.duration = m.NewHistogram("http_request_duration_seconds", "The HTTP request latencies in seconds.", c.Buckets, "route", "method", "code")
+	m.inFlight = m.NewGauge("http_requests_in_flight", "Current number of HTTP requests being served.", "route", "method")
+
+	if !c.DisableGoCollector {
+		m.Register(NewGoCollector())
+	}
+
+	if !c.DisableProcessCollector {
+		m.Register(NewProcessCollector())
+	}
+
+	return m
+}
+
+func (m *Metrics) name(name string) string {
+	if m.config.Namespace == "" {
+		return name
+	}
+
+	return m.config.Namespace + "_" + name
+}
+
+// Register registers a custom collector.
+func (m *Metrics) Register(c Collector) {
+	m.mu.Lock()
+	m.collectors = append(m.collectors, c)
+	m.mu.Unlock()
+}
+
+// NewCounter registers and returns a new counter metric.
+// The metric's name is prefixed by the configured Namespace.
+func (m *Metrics) NewCounter(name, help string, labelNames ...string) *CounterVec {
+	c := NewCounterVec(m.name(name), help, labelNames...)
+	m.Register(c)
+	return c
+}
+
+// NewGauge registers and returns a new gauge metric.
+// The metric's name is prefixed by the configured Namespace.
+func (m *Metrics) NewGauge(name, help string, labelNames ...string) *GaugeVec {
+	g := NewGaugeVec(m.name(name), help, labelNames...)
+	m.Register(g)
+	return g
+}
+
+// NewHistogram registers and returns a new histogram metric.
+// The metric's name is prefixed by the configured Namespace.
+func (m *Metrics) NewHistogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := NewHistogramVec(m.name(name), help, buckets, labelNames...)
+	m.Register(h)
+	return h
+}
+
+// Handler is the middleware which records the requests count, durations and in-flight requests.
+// Register it through `app.UseGlobal` or `app.Use`.
+func (m *Metrics) Handler(ctx context.Context) {
+	route := ctx.RouteName()
+	if route == "" {
+		route = "unknown"
+	}
+	method := ctx.Method()
+
+	m.inFlight.Inc(route, method)
+	// decremented even if a next handler panics.
+	defer m.inFlight.Dec(route, method)
+	start := time.Now()
+
+	ctx.Next()
+
+	code := statusClass(ctx.GetStatusCode())
+	m.duration.Observe(time.Since(start).Seconds(), route, method, code)
+	m.requests.Inc(route, method, code)
+}
+
+// Expose is the handler which writes the registered metrics
+// in the Prometheus text exposition format.
+func (m *Metrics) Expose(ctx context.Context) {
+	var b bytes.Buffer
+
+	m.mu.RLock()
+	for _, c := range m.collectors {
+		c.Collect(&b)
+	}
+	m.mu.RUnlock()
+
+	ctx.Header(context.ContentTypeHeaderKey, ContentType)
+	ctx.Write(b.Bytes())
+}
+
+func statusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,63 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/metrics"
+	"github.com/kataras/iris/v12/middleware/recover"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func TestMetrics(t *testing.T) {
+	app := iris.New()
+
+	m := metrics.New(metrics.Config{
+		Namespace:               "test",
+		Buckets:                 []float64{0.5, 1},
+		DisableProcessCollector: true,
+	})
+	custom := m.NewCounter("logins_total", "Total logins.", "provider")
+	sess := sessions.New(sessions.Config{})
+	m.Register(metrics.NewSessionsCollector(sess))
+
+	app.UseGlobal(recover.New(), m.Handler)
+	app.Get("/metrics", m.Expose)
+	app.Get("/users/{id}", func(ctx iris.Context) {
+		sess.Start(ctx)
+		custom.Inc("github")
+		ctx.WriteString("user")
+	}).Name = "user"
+	app.Get("/fail", func(ctx iris.Context) {
+		ctx.StatusCode(iris.StatusInternalServerError)
+	}).Name = "fail"
+	app.Get("/panic", func(ctx iris.Context) {
+		panic("handler panic")
+	}).Name = "panic"
+
+	e := httptest.New(t, app)
+	e.GET("/users/1").Expect().Status(httptest.StatusOK)
+	e.GET("/users/2").Expect().Status(httptest.StatusOK)
+	e.GET("/fail").Expect().Status(httptest.StatusInternalServerError)
+	e.GET("/panic").Expect().Status(httptest.StatusInternalServerError)
+
+	body := e.GET("/metrics").Expect().Status(httptest.StatusOK).
+		ContentType("text/plain", "utf-8").Body()
+
+	body.Contains("# TYPE test_http_requests_total counter")
+	body.Contains(`test_http_requests_total{route="user",method="GET",code="2xx"} 2`)
+	body.Contains(`test_http_requests_total{route="fail",method="GET",code="5xx"} 1`)
+	body.Contains("# TYPE test_http_request_duration_seconds histogram")
+	body.Contains(`test_http_request_duration_seconds_bucket{route="user",method="GET",code="2xx",le="+Inf"} 2`)
+	body.Contains(`test_http_request_duration_seconds_count{route="user",method="GET",code="2xx"} 2`)
+	// the "/metrics" request itself is still in-flight.
+	body.Contains(`test_http_requests_in_flight{route="GET/metrics",method="GET"} 1`)
+	// the panicked request is not in-flight anymore.
+	body.Contains(`test_http_requests_in_flight{route="panic",method="GET"} 0`)
+	body.Contains(`test_logins_total{provider="github"} 2`)
+	body.Contains("sessions_live 1")
+	body.Contains("sessions_evictions_total 0")
+	body.Contains("go_goroutines")
+	body.NotContains("process_start_time_seconds")
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewGoCollector returns a collector which exposes
+// the Go runtime's goroutines, memory and garbage collector statistics.
+func NewGoCollector() Collector {
+	return CollectorFunc(func(w io.Writer) {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+
+		writeSingle(w, "go_goroutines", "Number of goroutines that currently exist.", "gauge", float64(runtime.NumGoroutine()))
+		fmt.Fprintf(w, "# HELP go_info Information about the Go environment.\n# TYPE go_info gauge\ngo_info{version=%q} 1\n", runtime.Version())
+		writeSingle(w, "go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", "gauge", float64(m.Alloc))
+		writeSingle(w, "go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", "counter", float64(m.TotalAlloc))
+		writeSingle(w, "go_memstats_sys_bytes", "Number of bytes obtained from system.", "gauge", float64(m.Sys))
+		writeSingle(w, "go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", "gauge", float64(m.HeapInuse))
+		writeSingle(w, "go_memstats_heap_objects", "Number of allocated objects.", "gauge", float64(m.HeapObjects))
+		writeSingle(w, "go_gc_cycles_total", "Number of completed GC cycles.", "counter", float64(m.NumGC))
+		writeSingle(w, "go_gc_pause_seconds_total", "Total GC stop-the-world pause time in seconds.", "counter", float64(m.PauseTotalNs)/1e9)
+	})
+}
+
+// NewProcessCollector returns a collector which exposes the current process' statistics:
+// start time and, on systems which provide the "/proc" filesystem, cpu time,
+// resident memory and the number of open file descriptors.
+func NewProcessCollector() Collector {
+	startTime := float64(time.Now().UnixNano()) / 1e9
+
+	return CollectorFunc(func(w io.Writer) {
+		writeSingle(w, "process_start_time_seconds", "Start time of the process since unix epoch in seconds.", "gauge", startTime)
+
+		if cpu, rss, ok := readProcStat(); ok {
+			writeSingle(w, "process_cpu_seconds_total", "Total user and system CPU time spent in seconds.", "counter", cpu)
+			writeSingle(w, "process_resident_memory_bytes", "Resident memory size in bytes.", "gauge", rss)
+		}
+
+		if fds, err := ioutil.ReadDir("/proc/self/fd"); err == nil {
+			writeSingle(w, "process_open_fds", "Number of open file descriptors.", "gauge", float64(len(fds)))
+		}
+	})
+}
+
+func writeSingle(w io.Writer, name, help, typ string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	writeSample(w, name, nil, nil, "", "", value)
+}
+
+// readProcStat reads the cpu seconds and the resident memory bytes from "/proc/self/stat".
+func readProcStat() (cpu float64, rss float64, ok bool) {
+	b, err := ioutil.ReadFile("/proc/self/stat")
+	if err != nil {
+		return
+	}
+
+	// skip the pid and the command name, the latter may contain spaces.
+	data := string(b)
+	if idx := strings.LastIndexByte(data, ')'); idx > 0 && idx+2 < len(data) {
+		data = data[idx+2:]
+	}
+
+	fields := strings.Fields(data)
+	// fields[0] is the 3rd field of the stat file (state).
+	if len(fields) < 22 {
+		return
+	}
+
+	const userHZ = 100 // most systems.
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	pages, _ := strconv.ParseFloat(fields[21], 64)
+
+	return (utime + stime) / userHZ, pages * float64(os.Getpagesize()), true
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"io"
+
+	"github.com/kataras/iris/v12/sessions"
+)
+
+// NewSessionsCollector returns a collector which exposes the statistics
+// of the in-memory sessions of the "sess" manager: the live sessions and the evictions.
+//
+// Usage:
+//  m.Register(metrics.NewSessionsCollector(sess))
+func NewSessionsCollector(sess *sessions.Sessions) Collector {
+	return CollectorFunc(func(w io.Writer) {
+		stats := sess.Stats()
+		writeSingle(w, "sessions_live", "Number of sessions kept in memory.", "gauge", float64(stats.Live))
+		writeSingle(w, "sessions_evictions_total", "Number of sessions evicted from memory.", "counter", float64(stats.Evictions))
+	})
+}
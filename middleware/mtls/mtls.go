@@ -0,0 +1,123 @@
+// Package mtls provides a middleware which demands verified client certificates (mutual TLS) per Party.
+// The server should be configured to verify the client certificates through the `host.WithClientAuth`.
+package mtls
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/mtls.*", "mTLS")
+}
+
+// Options contains the optional settings for the `Require` middleware.
+type Options struct {
+	// AllowedCommonNames, if not empty, accepts only client certificates
+	// with one of these subject common names.
+	AllowedCommonNames []string
+	// Verify, if not nil, is an additional check of the verified client certificate.
+	Verify func(ctx context.Context, cert *x509.Certificate) bool
+	// OnMissing is fired when the request has no verified client certificate.
+	//
+	// Defaults to 401 Unauthorized.
+	OnMissing context.Handler
+	// OnForbidden is fired when the client certificate did not pass the
+	// AllowedCommonNames or the Verify checks.
+	//
+	// Defaults to 403 Forbidden.
+	OnForbidden context.Handler
+}
+
+// Certificate returns the verified client certificate of the request, if any.
+func Certificate(ctx context.Context) *x509.Certificate {
+	state := ctx.Request().TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return state.VerifiedChains[0][0]
+}
+
+// ErrMissingCertificate is returned from the `Dependency` and `SubjectDependency`
+// when the request has no verified client certificate.
+var ErrMissingCertificate = errors.New("mtls: missing client certificate")
+
+// Dependency is the hero/mvc dependency of the request's verified client certificate.
+//
+// Usage:
+//  app.ConfigureContainer().RegisterDependency(mtls.Dependency)
+//  admin.Get("/", func(cert *x509.Certificate) string { return cert.Subject.CommonName })
+func Dependency(ctx context.Context) (*x509.Certificate, error) {
+	if cert := Certificate(ctx); cert != nil {
+		return cert, nil
+	}
+
+	return nil, ErrMissingCertificate
+}
+
+// SubjectDependency is the hero/mvc dependency of the request's verified client certificate's subject.
+func SubjectDependency(ctx context.Context) (pkix.Name, error) {
+	cert, err := Dependency(ctx)
+	if err != nil {
+		return pkix.Name{}, err
+	}
+
+	return cert.Subject, nil
+}
+
+// Require returns a middleware which allows only requests with a verified client certificate.
+// Receives optional settings to restrict the accepted certificates.
+//
+// Usage:
+//  admin := app.Party("/admin", mtls.Require(mtls.Options{AllowedCommonNames: []string{"ops"}}))
+//  admin.Get("/", func(ctx iris.Context) { ctx.WriteString(mtls.Certificate(ctx).Subject.CommonName) })
+func Require(opts ...Options) context.Handler {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.OnMissing == nil {
+		o.OnMissing = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusUnauthorized)
+		}
+	}
+
+	if o.OnForbidden == nil {
+		o.OnForbidden = func(ctx context.Context) {
+			ctx.StopWithStatus(http.StatusForbidden)
+		}
+	}
+
+	allowed := make(map[string]struct{}, len(o.AllowedCommonNames))
+	for _, name := range o.AllowedCommonNames {
+		allowed[name] = struct{}{}
+	}
+
+	return func(ctx context.Context) {
+		cert := Certificate(ctx)
+		if cert == nil {
+			o.OnMissing(ctx)
+			return
+		}
+
+		if len(allowed) > 0 {
+			if _, ok := allowed[cert.Subject.CommonName]; !ok {
+				o.OnForbidden(ctx)
+				return
+			}
+		}
+
+		if o.Verify != nil && !o.Verify(ctx, cert) {
+			o.OnForbidden(ctx)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package mtls_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/mtls"
+)
+
+func TestRequire(t *testing.T) {
+	app := iris.New()
+	// fake the TLS handshake, the "X-Client-CN" header is the verified client certificate's common name.
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		if cn := r.Header.Get("X-Client-CN"); cn != "" {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+			r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+
+		router(w, r)
+	})
+
+	app.Get("/public", func(ctx iris.Context) {
+		ctx.WriteString("public")
+	})
+
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.RegisterDependency(mtls.Dependency)
+		api.RegisterDependency(mtls.SubjectDependency)
+
+		admin := api.Party("/admin", mtls.Require(mtls.Options{AllowedCommonNames: []string{"ops"}}))
+		admin.Get("/", func(cert *x509.Certificate) string {
+			return cert.Subject.CommonName
+		})
+		admin.Get("/subject", func(subject pkix.Name) string {
+			return subject.String()
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/public").Expect().Status(httptest.StatusOK).Body().Equal("public")
+	e.GET("/admin").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/admin").WithHeader("X-Client-CN", "guest").Expect().Status(httptest.StatusForbidden)
+	e.GET("/admin").WithHeader("X-Client-CN", "ops").Expect().Status(httptest.StatusOK).Body().Equal("ops")
+	e.GET("/admin/subject").WithHeader("X-Client-CN", "ops").Expect().Status(httptest.StatusOK).Body().Equal("CN=ops")
+}
@@ -0,0 +1,406 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI 3 document, the parts of it which describe the requests and the responses.
+// JSON documents are valid YAML ones, both are decoded by the `Parse` and `Load` functions.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+
+	routes []*route
+}
+
+// Components holds the reusable objects of a Document, referenced by "$ref": "#/components/...".
+type Components struct {
+	Schemas       map[string]*Schema      `yaml:"schemas"`
+	Parameters    map[string]*Parameter   `yaml:"parameters"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies"`
+	Responses     map[string]*Response    `yaml:"responses"`
+}
+
+// PathItem describes the operations of a path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Delete     *Operation   `yaml:"delete"`
+	Options    *Operation   `yaml:"options"`
+	Head       *Operation   `yaml:"head"`
+	Patch      *Operation   `yaml:"patch"`
+}
+
+// Operations returns the operations of the path item by their HTTP method.
+func (p *PathItem) Operations() map[string]*Operation {
+	operations := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+	} {
+		if op != nil {
+			operations[method] = op
+		}
+	}
+
+	return operations
+}
+
+// Operation describes an API operation on a path.
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter describes an operation's parameter, "in" the "path", "query" or "header".
+type Parameter struct {
+	Ref      string      `yaml:"$ref"`
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Required bool        `yaml:"required"`
+	Schema   *Schema     `yaml:"schema"`
+	Example  interface{} `yaml:"example"`
+}
+
+// RequestBody describes the request body of an operation, by content type.
+type RequestBody struct {
+	Ref      string                `yaml:"$ref"`
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response describes a response of an operation, by content type.
+type Response struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType describes the schema and the examples of a content type.
+type MediaType struct {
+	Schema   *Schema             `yaml:"schema"`
+	Example  interface{}         `yaml:"example"`
+	Examples map[string]*Example `yaml:"examples"`
+}
+
+// Example is a named example of a MediaType.
+type Example struct {
+	Summary string      `yaml:"summary"`
+	Value   interface{} `yaml:"value"`
+}
+
+// Schema is a JSON schema, the OpenAPI 3.0 subset of it.
+type Schema struct {
+	Ref                  string                `yaml:"$ref"`
+	Type                 string                `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Nullable             bool                  `yaml:"nullable"`
+	Enum                 []interface{}         `yaml:"enum"`
+	Properties           map[string]*Schema    `yaml:"properties"`
+	Required             []string              `yaml:"required"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	Items                *Schema               `yaml:"items"`
+	MinItems             *int                  `yaml:"minItems"`
+	MaxItems             *int                  `yaml:"maxItems"`
+	MinLength            *int                  `yaml:"minLength"`
+	MaxLength            *int                  `yaml:"maxLength"`
+	Pattern              string                `yaml:"pattern"`
+	Minimum              *float64              `yaml:"minimum"`
+	Maximum              *float64              `yaml:"maximum"`
+	ExclusiveMinimum     bool                  `yaml:"exclusiveMinimum"`
+	ExclusiveMaximum     bool                  `yaml:"exclusiveMaximum"`
+	AllOf                []*Schema             `yaml:"allOf"`
+	AnyOf                []*Schema             `yaml:"anyOf"`
+	OneOf                []*Schema             `yaml:"oneOf"`
+	Example              interface{}           `yaml:"example"`
+	Default              interface{}           `yaml:"default"`
+}
+
+// AdditionalProperties is the "additionalProperties" of an object Schema,
+// a boolean or a schema of the values.
+type AdditionalProperties struct {
+	// Disallowed is true on "additionalProperties: false".
+	Disallowed bool
+	Schema     *Schema
+}
+
+// UnmarshalYAML decodes a boolean or a schema.
+func (a *AdditionalProperties) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var allowed bool
+		if err := value.Decode(&allowed); err != nil {
+			return err
+		}
+
+		a.Disallowed = !allowed
+		return nil
+	}
+
+	a.Schema = new(Schema)
+	return value.Decode(a.Schema)
+}
+
+// Load reads and parses the OpenAPI 3 document of the "filename", JSON or YAML.
+func Load(filename string) (*Document, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses an OpenAPI 3 document, JSON or YAML.
+func Parse(data []byte) (*Document, error) {
+	doc := new(Document)
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version: %q", doc.OpenAPI)
+	}
+
+	if err := doc.build(); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	return doc, nil
+}
+
+// MustLoad same as `Load` but it panics on errors.
+func MustLoad(filename string) *Document {
+	doc, err := Load(filename)
+	if err != nil {
+		panic(err)
+	}
+
+	return doc
+}
+
+const componentsPrefix = "#/components/"
+
+var errRef = errors.New("unresolved reference")
+
+func (doc *Document) refName(ref, kind string) (string, error) {
+	prefix := componentsPrefix + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("%w: %s", errRef, ref)
+	}
+
+	return ref[len(prefix):], nil
+}
+
+// schema follows the references of the "s".
+func (doc *Document) schema(s *Schema) (*Schema, error) {
+	for i := 0; s != nil && s.Ref != ""; i++ {
+		name, err := doc.refName(s.Ref, "schemas")
+		if err != nil {
+			return nil, err
+		}
+
+		ref, ok := doc.Components.Schemas[name]
+		if !ok || i > 32 {
+			return nil, fmt.Errorf("%w: %s", errRef, s.Ref)
+		}
+		s = ref
+	}
+
+	return s, nil
+}
+
+func (doc *Document) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+
+	name, err := doc.refName(p.Ref, "parameters")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, ok := doc.Components.Parameters[name]; ok && ref.Ref == "" {
+		return ref, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errRef, p.Ref)
+}
+
+func (doc *Document) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+
+	name, err := doc.refName(b.Ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, ok := doc.Components.RequestBodies[name]; ok && ref.Ref == "" {
+		return ref, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errRef, b.Ref)
+}
+
+func (doc *Document) response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+
+	name, err := doc.refName(r.Ref, "responses")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, ok := doc.Components.Responses[name]; ok && ref.Ref == "" {
+		return ref, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errRef, r.Ref)
+}
+
+// route is an operation of the document with its resolved parameters.
+type route struct {
+	method    string
+	path      string
+	segments  []string // the "{name}" ones are parameters.
+	literals  int
+	operation *Operation
+	params    []*Parameter
+	body      *RequestBody
+}
+
+// build resolves the parameters and the request bodies of the operations
+// and sorts them for the path matching, the more literal segments first.
+func (doc *Document) build() error {
+	for path, item := range doc.Paths {
+		if item == nil {
+			continue
+		}
+
+		for method, op := range item.Operations() {
+			r := &route{
+				method:    method,
+				path:      path,
+				segments:  splitPath(path),
+				operation: op,
+			}
+
+			for _, s := range r.segments {
+				if !isParamSegment(s) {
+					r.literals++
+				}
+			}
+
+			// the operation's parameters override the path item's ones of the same name and location.
+			seen := make(map[string]struct{})
+			for _, params := range [][]*Parameter{op.Parameters, item.Parameters} {
+				for _, p := range params {
+					p, err := doc.parameter(p)
+					if err != nil {
+						return fmt.Errorf("%s %s: %w", method, path, err)
+					}
+
+					key := p.In + ":" + p.Name
+					if _, ok := seen[key]; ok {
+						continue
+					}
+					seen[key] = struct{}{}
+					r.params = append(r.params, p)
+				}
+			}
+
+			body, err := doc.requestBody(op.RequestBody)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			r.body = body
+
+			doc.routes = append(doc.routes, r)
+		}
+	}
+
+	sort.SliceStable(doc.routes, func(i, j int) bool {
+		if doc.routes[i].literals != doc.routes[j].literals {
+			return doc.routes[i].literals > doc.routes[j].literals
+		}
+
+		return doc.routes[i].path < doc.routes[j].path
+	})
+
+	return nil
+}
+
+// find returns the route of the "method" and "path" and the path parameters' values.
+// It reports whether the path is documented, even if the method is not.
+func (doc *Document) find(method, path string) (r *route, params map[string]string, pathFound bool) {
+	segments := splitPath(path)
+
+	for _, candidate := range doc.routes {
+		values, ok := candidate.match(segments)
+		if !ok {
+			continue
+		}
+
+		pathFound = true
+		if candidate.method == method {
+			return candidate, values, true
+		}
+	}
+
+	return nil, nil, pathFound
+}
+
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	var values map[string]string
+	for i, s := range r.segments {
+		if isParamSegment(s) {
+			if segments[i] == "" {
+				return nil, false
+			}
+
+			if values == nil {
+				values = make(map[string]string)
+			}
+			values[s[1:len(s)-1]] = segments[i]
+			continue
+		}
+
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func isParamSegment(s string) bool {
+	return len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}'
+}
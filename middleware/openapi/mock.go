@@ -0,0 +1,359 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+// PreferHeaderKey is the request header key which selects the response of a mocked operation,
+// e.g. "Prefer: code=404" and "Prefer: example=empty", see `Mock`.
+const PreferHeaderKey = "Prefer"
+
+// Mock registers a route to the Party "p" for each operation of the "doc" which has no route yet,
+// so the frontend teams can develop against the same application before the real handlers exist.
+// The routes are registered right before the router is built, the real routes win
+// regardless of their registration order, the parameters' types do not matter, i.e.
+// a "/pets/{id:uint64}" route implements the document's "/pets/{id}" path.
+//
+// A mocked operation responds with its successful response, the one with the lowest 2xx status code,
+// and its first JSON content type, if any. The payload is the example of the content type,
+// its first named example or, if none, a value generated by its schema from the schema's examples,
+// defaults, enums and formats. The client can select a different response
+// through the "Prefer" header, e.g. "Prefer: code=404, example=notFound".
+// The operations with a path which can not be expressed by the router, e.g. "/files/{name}.{ext}", are skipped.
+//
+// Mock is for development, do not use it on production.
+//
+// Usage:
+//  app.Get("/pets", listPets) // a real handler.
+//  openapi.Mock(app, openapi.MustLoad("./openapi.yml"))
+func Mock(p router.Party, doc *Document) {
+	routes, ok := p.(router.RoutesProvider)
+	if !ok {
+		return
+	}
+
+	prefix := strings.TrimSuffix(p.GetRelPath(), "/")
+
+	p.OnBuild(func() error {
+		registered := make(map[string]struct{})
+		for _, r := range routes.GetRoutes() {
+			registered[r.Method+" "+routeKey(r.Subdomain+r.Path)] = struct{}{}
+		}
+
+		for _, r := range doc.routes {
+			if !mockable(r) {
+				continue
+			}
+
+			if _, ok := registered[r.method+" "+routeKey(prefix+r.path)]; ok {
+				continue
+			}
+
+			p.Handle(r.method, r.path, mockHandler(doc, r.operation))
+		}
+
+		return nil
+	})
+}
+
+// mockable reports whether the route's path can be registered, all parameters fill a whole path segment.
+func mockable(r *route) bool {
+	for _, s := range r.segments {
+		if !isParamSegment(s) && strings.ContainsAny(s, "{}:*") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// routeKey returns the "path" with its parameters' names and types removed,
+// i.e. both "/pets/:id" and "/pets/{petId}" result to "/pets/:".
+func routeKey(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if isParamSegment(s) || strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = ":"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func mockHandler(doc *Document, op *Operation) context.Handler {
+	return func(ctx context.Context) {
+		code, example := parsePrefer(ctx.GetHeader(PreferHeaderKey))
+
+		statusCode, resp, ok := mockResponse(op, code)
+		if !ok {
+			mockError(ctx, http.StatusNotImplemented, "no response for the operation")
+			return
+		}
+
+		resp, err := doc.response(resp)
+		if err != nil {
+			mockError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx.StatusCode(statusCode)
+		if resp == nil || len(resp.Content) == 0 {
+			return
+		}
+
+		contentType, mediaType := mockMediaType(resp.Content)
+		if mediaType == nil {
+			ctx.ContentType(contentType)
+			return
+		}
+
+		payload := mockPayload(doc, mediaType, example)
+		if s, ok := payload.(string); ok && !isJSON(contentType) {
+			ctx.ContentType(contentType)
+			ctx.WriteString(s)
+			return
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			mockError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx.ContentType(contentType)
+		ctx.Write(data)
+	}
+}
+
+func mockError(ctx context.Context, statusCode int, msg string) {
+	ctx.StopWithStatus(statusCode)
+	ctx.WriteString("mock: " + msg)
+}
+
+// parsePrefer parses the "code" and the "example" preferences of the "Prefer" header value.
+func parsePrefer(header string) (code, example string) {
+	for _, pref := range strings.Split(header, ",") {
+		name, value := pref, ""
+		if i := strings.IndexByte(pref, '='); i > 0 {
+			name, value = pref[:i], strings.Trim(strings.TrimSpace(pref[i+1:]), `"`)
+		}
+
+		switch strings.TrimSpace(name) {
+		case "code":
+			code = value
+		case "example":
+			example = value
+		}
+	}
+
+	return
+}
+
+// mockResponse returns the response of the "code" status code, if not empty,
+// otherwise the successful response of the operation.
+func mockResponse(op *Operation, code string) (int, *Response, bool) {
+	if code != "" {
+		statusCode, err := strconv.Atoi(code)
+		if err != nil {
+			return 0, nil, false
+		}
+
+		for _, key := range []string{code, code[:1] + "XX", "default"} {
+			if resp, ok := op.Responses[key]; ok {
+				return statusCode, resp, true
+			}
+		}
+
+		return 0, nil, false
+	}
+
+	codes := make([]int, 0, len(op.Responses))
+	for key := range op.Responses {
+		if statusCode, err := strconv.Atoi(key); err == nil && statusCode >= 200 && statusCode < 300 {
+			codes = append(codes, statusCode)
+		}
+	}
+
+	if len(codes) > 0 {
+		sort.Ints(codes)
+		return codes[0], op.Responses[strconv.Itoa(codes[0])], true
+	}
+
+	for _, key := range []string{"2XX", "default"} {
+		if resp, ok := op.Responses[key]; ok {
+			return http.StatusOK, resp, true
+		}
+	}
+
+	return 0, nil, false
+}
+
+// mockMediaType returns the first JSON content type, or the first content type, by name.
+func mockMediaType(content map[string]*MediaType) (string, *MediaType) {
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+
+	contentType := contentTypes[0]
+	for _, ct := range contentTypes {
+		if isJSON(ct) {
+			contentType = ct
+			break
+		}
+	}
+
+	mediaType := content[contentType]
+	// ranges, e.g. "application/*", are not valid response content types.
+	contentType = strings.Replace(contentType, "*/*", context.ContentBinaryHeaderValue, 1)
+	contentType = strings.Replace(contentType, "/*", "/octet-stream", 1)
+	return contentType, mediaType
+}
+
+// mockPayload returns the "example" named example of the media type, its example,
+// its first named example or a value generated by its schema.
+func mockPayload(doc *Document, m *MediaType, example string) interface{} {
+	if ex, ok := m.Examples[example]; ok && ex != nil {
+		return ex.Value
+	}
+
+	if m.Example != nil {
+		return m.Example
+	}
+
+	if len(m.Examples) > 0 {
+		names := make([]string, 0, len(m.Examples))
+		for name := range m.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if ex := m.Examples[names[0]]; ex != nil {
+			return ex.Value
+		}
+	}
+
+	return generate(doc, m.Schema, 0)
+}
+
+// maxGenerateDepth protects against recursive schemas, e.g. a tree node.
+const maxGenerateDepth = 8
+
+// generate returns a value which is valid against the schema "s".
+func generate(doc *Document, s *Schema, depth int) interface{} {
+	s, err := doc.schema(s)
+	if err != nil || s == nil || depth > maxGenerateDepth {
+		return nil
+	}
+
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	case len(s.OneOf) > 0:
+		return generate(doc, s.OneOf[0], depth+1)
+	case len(s.AnyOf) > 0:
+		return generate(doc, s.AnyOf[0], depth+1)
+	}
+
+	if len(s.AllOf) > 0 {
+		obj := make(map[string]interface{})
+		for _, sub := range s.AllOf {
+			if m, ok := generate(doc, sub, depth+1).(map[string]interface{}); ok {
+				for k, v := range m {
+					obj[k] = v
+				}
+			}
+		}
+
+		if s.Type == "" || s.Type == "object" {
+			for name, prop := range s.Properties {
+				obj[name] = generate(doc, prop, depth+1)
+			}
+			return obj
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		obj := make(map[string]interface{}, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = generate(doc, prop, depth+1)
+		}
+		return obj
+	case "array":
+		n := 1
+		if s.MinItems != nil && *s.MinItems > n {
+			n = *s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < n {
+			n = *s.MaxItems
+		}
+
+		arr := make([]interface{}, n)
+		for i := range arr {
+			arr[i] = generate(doc, s.Items, depth+1)
+		}
+		return arr
+	case "string":
+		return generateString(s)
+	case "integer", "number":
+		var n float64
+		if s.Minimum != nil {
+			n = *s.Minimum
+			if s.ExclusiveMinimum {
+				n++
+			}
+		} else if s.Maximum != nil && *s.Maximum < 0 {
+			n = *s.Maximum
+			if s.ExclusiveMaximum {
+				n--
+			}
+		}
+
+		if s.Type == "integer" {
+			return int64(n)
+		}
+		return n
+	case "boolean":
+		return true
+	}
+
+	return nil
+}
+
+func generateString(s *Schema) string {
+	switch s.Format {
+	case "date-time":
+		return "2020-01-01T00:00:00Z"
+	case "date":
+		return "2020-01-01"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	}
+
+	str := "string"
+	if s.MinLength != nil && len(str) < *s.MinLength {
+		str += strings.Repeat("s", *s.MinLength-len(str))
+	}
+	if s.MaxLength != nil && len(str) > *s.MaxLength {
+		str = str[:*s.MaxLength]
+	}
+
+	return str
+}
@@ -0,0 +1,295 @@
+// Package openapi provides a middleware which validates the requests, and optionally the responses,
+// against an OpenAPI 3 document: the path, query and header parameters, the content type
+// and the JSON schema of the body. Invalid requests are rejected with a 400 (or 415) problem response
+// which lists the offending fields, so the handlers and the document can not drift apart silently.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/openapi.*", "OpenAPI")
+}
+
+// Options holds the settings for the `New` middleware.
+type Options struct {
+	// Prefix is removed from the request path before it's matched against the document's paths,
+	// e.g. "/api/v1" when the document's paths are relative to that server URL.
+	Prefix string
+	// ValidateResponses validates the status code and the JSON body of the responses too,
+	// an invalid response is replaced by a 500 Internal Server Error.
+	// It records the responses, use it on development and testing.
+	ValidateResponses bool
+	// OnError is fired on validation failures.
+	//
+	// Defaults to an application/problem+json response with the errors under the "errors" field.
+	OnError func(ctx context.Context, err *ValidationError)
+}
+
+// ValidationError is the error of an invalid request or response.
+type ValidationError struct {
+	// Status is the status code to respond with:
+	// 400 Bad Request, 413 Request Entity Too Large, 415 Unsupported Media Type
+	// or 500 Internal Server Error for responses.
+	Status int
+	// Errors are the validation failures.
+	Errors []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.String())
+	}
+
+	return "openapi: " + strings.Join(msgs, "; ")
+}
+
+// DefaultErrorHandler is the default `Options.OnError`.
+func DefaultErrorHandler(ctx context.Context, err *ValidationError) {
+	title := "Invalid request"
+	if err.Status >= http.StatusInternalServerError {
+		title = "Invalid response"
+	}
+
+	ctx.StopWithProblem(err.Status, context.NewProblem().Title(title).Key("errors", err.Errors))
+}
+
+// New returns a new OpenAPI validation middleware of the "doc".
+// The requests whose path and method are not described by the document are served as they are.
+//
+// Usage:
+//  doc := openapi.MustLoad("./openapi.yml")
+//  app.Use(openapi.New(doc))
+func New(doc *Document, opts ...Options) context.Handler {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if o.OnError == nil {
+		o.OnError = DefaultErrorHandler
+	}
+
+	prefix := strings.TrimSuffix(o.Prefix, "/")
+
+	return func(ctx context.Context) {
+		path := ctx.Path()
+		if prefix != "" {
+			if !strings.HasPrefix(path, prefix) {
+				ctx.Next()
+				return
+			}
+			path = path[len(prefix):]
+		}
+
+		r, pathValues, _ := doc.find(ctx.Method(), path)
+		if r == nil {
+			ctx.Next()
+			return
+		}
+
+		if err := validateRequest(ctx, doc, r, pathValues); err != nil {
+			o.OnError(ctx, err)
+			return
+		}
+
+		if !o.ValidateResponses {
+			ctx.Next()
+			return
+		}
+
+		ctx.Record()
+		ctx.Next()
+
+		if err := validateResponse(ctx, doc, r); err != nil {
+			rec := ctx.Recorder()
+			rec.Reset()
+			// stop recording, so the error code handler does not replace the error response.
+			ctx.ResetResponseWriter(rec.ResponseWriter)
+			o.OnError(ctx, err)
+		}
+	}
+}
+
+func validateRequest(ctx context.Context, doc *Document, r *route, pathValues map[string]string) *ValidationError {
+	v := &validator{doc: doc}
+
+	for _, p := range r.params {
+		var raw []string
+		switch p.In {
+		case "path":
+			if value, ok := pathValues[p.Name]; ok {
+				raw = []string{value}
+			}
+		case "query":
+			raw = ctx.Request().URL.Query()[p.Name]
+		case "header":
+			raw = ctx.Request().Header.Values(p.Name)
+		default: // cookies are not validated.
+			continue
+		}
+
+		v.in = p.In
+		if len(raw) == 0 {
+			if p.Required || p.In == "path" {
+				v.fail(p.Name, "is required")
+			}
+			continue
+		}
+
+		value, ok := v.convertParam(p.Schema, raw)
+		if !ok {
+			v.fail(p.Name, "has an invalid value %q", firstValue(raw))
+			continue
+		}
+
+		v.validate(p.Schema, value, p.Name)
+	}
+
+	if r.body != nil {
+		v.in = "body"
+		if status := validateRequestBody(ctx, v, r.body); status > 0 {
+			return &ValidationError{Status: status, Errors: v.errors}
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Status: http.StatusBadRequest, Errors: v.errors}
+	}
+
+	return nil
+}
+
+// validateRequestBody validates the request body, the body is kept readable for the next handlers.
+// It returns a status code other than 400 Bad Request, if any.
+func validateRequestBody(ctx context.Context, v *validator, body *RequestBody) int {
+	contentType := ctx.GetContentTypeRequested()
+	if contentType == "" && ctx.Request().ContentLength == 0 {
+		if body.Required {
+			v.fail("", "is required")
+		}
+		return 0
+	}
+
+	mediaType, ok := findMediaType(body.Content, contentType)
+	if !ok {
+		v.fail("", "unsupported content type %q", contentType)
+		return http.StatusUnsupportedMediaType
+	}
+
+	if !isJSON(contentType) {
+		return 0
+	}
+
+	ctx.RecordRequestBody(true)
+	data, err := ctx.GetBody()
+	if err != nil {
+		v.fail("", "%v", err)
+		if err == context.ErrRequestBodyTooLarge {
+			return http.StatusRequestEntityTooLarge
+		}
+		return 0
+	}
+
+	if len(data) == 0 {
+		if body.Required {
+			v.fail("", "is required")
+		}
+		return 0
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		v.fail("", "invalid JSON: %v", err)
+		return 0
+	}
+
+	if mediaType != nil {
+		v.validate(mediaType.Schema, value, "")
+	}
+
+	return 0
+}
+
+func validateResponse(ctx context.Context, doc *Document, r *route) *ValidationError {
+	v := &validator{doc: doc, in: "response"}
+	statusCode := ctx.GetStatusCode()
+
+	resp, ok := r.operation.Responses[strconv.Itoa(statusCode)]
+	if !ok {
+		resp, ok = r.operation.Responses[strconv.Itoa(statusCode/100)+"XX"]
+	}
+	if !ok {
+		resp, ok = r.operation.Responses["default"]
+	}
+	if !ok {
+		v.fail("", "undocumented status code %d", statusCode)
+		return &ValidationError{Status: http.StatusInternalServerError, Errors: v.errors}
+	}
+
+	resp, err := doc.response(resp)
+	if err != nil {
+		v.fail("", "%v", err)
+		return &ValidationError{Status: http.StatusInternalServerError, Errors: v.errors}
+	}
+
+	rec := ctx.Recorder()
+	data := rec.Body()
+	if resp == nil || len(resp.Content) == 0 || len(data) == 0 {
+		return nil
+	}
+
+	contentType := context.TrimHeaderValue(rec.Header().Get(context.ContentTypeHeaderKey))
+	mediaType, ok := findMediaType(resp.Content, contentType)
+	if !ok {
+		v.fail("", "undocumented content type %q", contentType)
+	} else if mediaType != nil && isJSON(contentType) {
+		var value interface{}
+		if err = json.Unmarshal(data, &value); err != nil {
+			v.fail("", "invalid JSON: %v", err)
+		} else {
+			v.validate(mediaType.Schema, value, "")
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Status: http.StatusInternalServerError, Errors: v.errors}
+	}
+
+	return nil
+}
+
+// findMediaType returns the media type of the "contentType",
+// matched exactly or by a "type/*" or "*/*" range.
+func findMediaType(content map[string]*MediaType, contentType string) (*MediaType, bool) {
+	if len(content) == 0 {
+		return nil, true
+	}
+
+	contentType = strings.ToLower(contentType)
+	if m, ok := content[contentType]; ok {
+		return m, true
+	}
+
+	if i := strings.IndexByte(contentType, '/'); i > 0 {
+		if m, ok := content[contentType[:i]+"/*"]; ok {
+			return m, true
+		}
+	}
+
+	m, ok := content["*/*"]
+	return m, ok
+}
+
+func isJSON(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return contentType == context.ContentJSONHeaderValue || strings.HasSuffix(contentType, "+json")
+}
@@ -0,0 +1,296 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/openapi"
+
+	"github.com/gavv/httpexpect"
+)
+
+const testDocument = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [cat, dog]
+      responses:
+        "200":
+          description: The pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Pet"
+      responses:
+        "201":
+          description: Created.
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+    get:
+      parameters:
+        - name: X-Request-Id
+          in: header
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        "200":
+          description: The pet.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          minLength: 1
+        tags:
+          type: array
+          maxItems: 2
+          items:
+            type: string
+`
+
+func TestOpenAPI(t *testing.T) {
+	doc, err := openapi.Parse([]byte(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.Use(openapi.New(doc, openapi.Options{ValidateResponses: true}))
+	app.Get("/pets", func(ctx iris.Context) {
+		if ctx.URLParam("limit") == "13" {
+			ctx.JSON([]iris.Map{{"id": 13}}) // invalid response, missing name.
+			return
+		}
+
+		ctx.JSON([]iris.Map{{"id": 1, "name": "Rex"}})
+	})
+	app.Post("/pets", func(ctx iris.Context) {
+		var pet iris.Map
+		if err := ctx.ReadJSON(&pet); err != nil {
+			ctx.StopWithStatus(iris.StatusBadRequest)
+			return
+		}
+
+		ctx.StatusCode(iris.StatusCreated)
+		ctx.WriteString(pet["name"].(string))
+	})
+	app.Get("/pets/{id}", func(ctx iris.Context) {
+		ctx.JSON(iris.Map{"id": ctx.Params().GetIntDefault("id", 0), "name": "Rex"})
+	})
+	app.Get("/undocumented", func(ctx iris.Context) {
+		ctx.WriteString("ok")
+	})
+
+	e := httptest.New(t, app)
+
+	// valid requests.
+	e.GET("/pets").WithQuery("limit", 10).WithQuery("tags", "cat,dog").Expect().
+		Status(httptest.StatusOK).JSON().Array().Length().Equal(1)
+	e.POST("/pets").WithJSON(iris.Map{"name": "Rex", "tags": []string{"good"}}).Expect().
+		Status(httptest.StatusCreated).Body().Equal("Rex")
+	e.GET("/pets/1").WithHeader("X-Request-Id", "9b2b3a5e-6c41-4e33-9d4f-2a4c8f1e2b7d").Expect().
+		Status(httptest.StatusOK).JSON().Object().Value("name").Equal("Rex")
+	e.GET("/undocumented").Expect().Status(httptest.StatusOK).Body().Equal("ok")
+
+	// invalid parameters.
+	expectErrors(t, e.GET("/pets").WithQuery("limit", 0).WithQuery("tags", "bird").Expect(), httptest.StatusBadRequest,
+		openapi.FieldError{In: "query", Field: "limit", Message: "must be greater than or equal to 1"},
+		openapi.FieldError{In: "query", Field: "tags[0]", Message: "must be one of [cat dog]"})
+	expectErrors(t, e.GET("/pets").WithQuery("limit", "ten").Expect(), httptest.StatusBadRequest,
+		openapi.FieldError{In: "query", Field: "limit", Message: `has an invalid value "ten"`})
+	expectErrors(t, e.GET("/pets/one").WithHeader("X-Request-Id", "invalid").Expect(), httptest.StatusBadRequest,
+		openapi.FieldError{In: "header", Field: "X-Request-Id", Message: "must be a valid uuid"},
+		openapi.FieldError{In: "path", Field: "id", Message: `has an invalid value "one"`})
+
+	// invalid bodies.
+	expectErrors(t, e.POST("/pets").WithJSON(iris.Map{"name": "", "tags": []string{"a", "b", "c"}, "age": 1}).Expect(),
+		httptest.StatusBadRequest,
+		openapi.FieldError{In: "body", Field: "age", Message: "is not allowed"},
+		openapi.FieldError{In: "body", Field: "name", Message: "must have at least 1 characters"},
+		openapi.FieldError{In: "body", Field: "tags", Message: "must have at most 2 items"})
+	expectErrors(t, e.POST("/pets").Expect(), httptest.StatusBadRequest,
+		openapi.FieldError{In: "body", Message: "is required"})
+	e.POST("/pets").WithBytes([]byte("{")).WithHeader("Content-Type", "application/json").
+		Expect().Status(httptest.StatusBadRequest)
+	expectErrors(t, e.POST("/pets").WithText("Rex").Expect(), httptest.StatusUnsupportedMediaType,
+		openapi.FieldError{In: "body", Message: `unsupported content type "text/plain"`})
+
+	// invalid response.
+	expectErrors(t, e.GET("/pets").WithQuery("limit", 13).Expect(), httptest.StatusInternalServerError,
+		openapi.FieldError{In: "response", Field: "[0].name", Message: "is required"})
+}
+
+func expectErrors(t *testing.T, resp *httpexpect.Response, status int, expected ...openapi.FieldError) {
+	t.Helper()
+
+	resp.Status(status).ContentType("application/problem+json")
+
+	var problem struct {
+		Errors []openapi.FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body().Raw()), &problem); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(problem.Errors, expected) {
+		t.Fatalf("expected errors:\n%v\nbut got:\n%v", expected, problem.Errors)
+	}
+}
+
+func TestOpenAPIPrefix(t *testing.T) {
+	doc, err := openapi.Parse([]byte(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	api := app.Party("/api/v1")
+	api.Use(openapi.New(doc, openapi.Options{Prefix: "/api/v1"}))
+	api.Post("/pets", func(ctx iris.Context) {
+		ctx.StatusCode(iris.StatusCreated)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/api/v1/pets").WithJSON(iris.Map{"name": "Rex"}).Expect().Status(httptest.StatusCreated)
+	e.POST("/api/v1/pets").WithJSON(iris.Map{}).Expect().Status(httptest.StatusBadRequest)
+
+	if _, err = openapi.Parse([]byte("swagger: \"2.0\"")); err == nil {
+		t.Fatalf("expected an unsupported version error")
+	}
+}
+
+const testMockDocument = `
+openapi: 3.0.3
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: The pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+  /pets/{id}:
+    get:
+      responses:
+        "200":
+          description: The pet.
+          content:
+            application/json:
+              example: {"id": 1, "name": "Rex"}
+        "404":
+          description: Not found.
+          content:
+            application/json:
+              examples:
+                missing:
+                  value: {"message": "missing"}
+    delete:
+      responses:
+        "204":
+          description: Deleted.
+  /status:
+    get:
+      responses:
+        "200":
+          description: The status.
+          content:
+            text/plain:
+              schema:
+                type: string
+                default: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        name:
+          type: string
+          minLength: 8
+        born:
+          type: string
+          format: date
+        tags:
+          type: array
+          items:
+            type: string
+            enum: [cat, dog]
+`
+
+func TestMock(t *testing.T) {
+	doc, err := openapi.Parse([]byte(testMockDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	openapi.Mock(app, doc)
+	// registered after the mocks, the real handlers win.
+	app.Delete("/pets/{id:uint64}", func(ctx iris.Context) {
+		ctx.StatusCode(iris.StatusAccepted)
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/pets").Expect().Status(httptest.StatusOK).JSON().Array().Equal([]iris.Map{
+		{"id": 1, "name": "stringss", "born": "2020-01-01", "tags": []string{"cat"}},
+	})
+	e.GET("/pets/42").Expect().Status(httptest.StatusOK).JSON().Object().Equal(iris.Map{"id": 1, "name": "Rex"})
+	e.GET("/pets/42").WithHeader(openapi.PreferHeaderKey, "code=404, example=missing").Expect().
+		Status(httptest.StatusNotFound).JSON().Object().Equal(iris.Map{"message": "missing"})
+	e.GET("/pets/42").WithHeader(openapi.PreferHeaderKey, "code=500").Expect().Status(httptest.StatusNotImplemented)
+	e.DELETE("/pets/42").Expect().Status(httptest.StatusAccepted)
+	e.GET("/status").Expect().Status(httptest.StatusOK).ContentType("text/plain").Body().Equal("ok")
+}
@@ -0,0 +1,351 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is a validation failure of a request's or response's field.
+type FieldError struct {
+	// In is the location of the field: "path", "query", "header", "body" or "response".
+	In string `json:"in"`
+	// Field is the name of the parameter or the path of the body's field, e.g. "items[0].name".
+	// It's empty for the whole body.
+	Field string `json:"field,omitempty"`
+	// Message describes the failure, e.g. "is required".
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	if e.Field == "" {
+		return e.In + ": " + e.Message
+	}
+
+	return e.In + ": " + e.Field + ": " + e.Message
+}
+
+// validator validates a decoded JSON value, or a converted parameter, against a schema.
+type validator struct {
+	doc    *Document
+	in     string
+	errors []FieldError
+}
+
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{In: v.in, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(s *Schema, value interface{}, field string) {
+	s, err := v.doc.schema(s)
+	if err != nil {
+		v.fail(field, "schema: %v", err)
+		return
+	}
+
+	if s == nil {
+		return
+	}
+
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			v.fail(field, "must not be null")
+		}
+		return
+	}
+
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, field)
+	}
+
+	if len(s.AnyOf) > 0 && v.matches(s.AnyOf, value) == 0 {
+		v.fail(field, "does not match any of the schemas")
+	}
+
+	if len(s.OneOf) > 0 {
+		if n := v.matches(s.OneOf, value); n != 1 {
+			v.fail(field, "matches %d of the schemas instead of exactly one", n)
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		v.fail(field, "must be one of %v", s.Enum)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(field, "must be an object")
+			return
+		}
+		v.validateObject(s, obj, field)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			v.fail(field, "must be an array")
+			return
+		}
+		v.validateArray(s, arr, field)
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(field, "must be a string")
+			return
+		}
+		v.validateString(s, str, field)
+	case "integer":
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) {
+			v.fail(field, "must be an integer")
+			return
+		}
+		v.validateNumber(s, n, field)
+	case "number":
+		n, ok := toFloat(value)
+		if !ok {
+			v.fail(field, "must be a number")
+			return
+		}
+		v.validateNumber(s, n, field)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(field, "must be a boolean")
+		}
+	}
+}
+
+// matches returns the number of the "schemas" which the "value" matches.
+func (v *validator) matches(schemas []*Schema, value interface{}) int {
+	n := 0
+	for _, s := range schemas {
+		sub := &validator{doc: v.doc, in: v.in}
+		if sub.validate(s, value, ""); len(sub.errors) == 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (v *validator) validateObject(s *Schema, obj map[string]interface{}, field string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(joinField(field, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names) // stable errors.
+
+	for _, name := range names {
+		if prop, ok := s.Properties[name]; ok {
+			v.validate(prop, obj[name], joinField(field, name))
+			continue
+		}
+
+		if ap := s.AdditionalProperties; ap != nil {
+			if ap.Disallowed {
+				v.fail(joinField(field, name), "is not allowed")
+			} else if ap.Schema != nil {
+				v.validate(ap.Schema, obj[name], joinField(field, name))
+			}
+		}
+	}
+}
+
+func (v *validator) validateArray(s *Schema, arr []interface{}, field string) {
+	if s.MinItems != nil && len(arr) < *s.MinItems {
+		v.fail(field, "must have at least %d items", *s.MinItems)
+	}
+
+	if s.MaxItems != nil && len(arr) > *s.MaxItems {
+		v.fail(field, "must have at most %d items", *s.MaxItems)
+	}
+
+	if s.Items != nil {
+		for i, item := range arr {
+			v.validate(s.Items, item, field+"["+strconv.Itoa(i)+"]")
+		}
+	}
+}
+
+var uuidRegexp = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+func (v *validator) validateString(s *Schema, str, field string) {
+	if s.MinLength != nil || s.MaxLength != nil {
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			v.fail(field, "must have at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			v.fail(field, "must have at most %d characters", *s.MaxLength)
+		}
+	}
+
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			v.fail(field, "schema: invalid pattern: %v", err)
+		} else if !re.MatchString(str) {
+			v.fail(field, "must match the pattern %q", s.Pattern)
+		}
+	}
+
+	var err error
+	switch s.Format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, str)
+	case "date":
+		_, err = time.Parse("2006-01-02", str)
+	case "email":
+		_, err = mail.ParseAddress(str)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(str); err == nil && !u.IsAbs() {
+			err = fmt.Errorf("not absolute")
+		}
+	case "uuid":
+		if !uuidRegexp.MatchString(str) {
+			err = fmt.Errorf("invalid")
+		}
+	}
+
+	if err != nil {
+		v.fail(field, "must be a valid %s", s.Format)
+	}
+}
+
+func (v *validator) validateNumber(s *Schema, n float64, field string) {
+	if min := s.Minimum; min != nil {
+		if s.ExclusiveMinimum && n <= *min {
+			v.fail(field, "must be greater than %v", *min)
+		} else if n < *min {
+			v.fail(field, "must be greater than or equal to %v", *min)
+		}
+	}
+
+	if max := s.Maximum; max != nil {
+		if s.ExclusiveMaximum && n >= *max {
+			v.fail(field, "must be less than %v", *max)
+		} else if n > *max {
+			v.fail(field, "must be less than or equal to %v", *max)
+		}
+	}
+}
+
+var patterns sync.Map // pattern:*regexp.Regexp.
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// toFloat converts the JSON and the YAML numbers to float64.
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if a, ok := toFloat(e); ok {
+			if b, ok := toFloat(value); ok && a == b {
+				return true
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertParam converts the "raw" parameter value to the type of the schema "s",
+// so it can be validated, the array items are separated by commas or repeated.
+func (v *validator) convertParam(s *Schema, raw []string) (interface{}, bool) {
+	s, err := v.doc.schema(s)
+	if err != nil || s == nil {
+		return firstValue(raw), true
+	}
+
+	if s.Type == "array" {
+		var values []string
+		for _, r := range raw {
+			values = append(values, strings.Split(r, ",")...)
+		}
+
+		arr := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			item, ok := v.convertParam(s.Items, []string{value})
+			if !ok {
+				return nil, false
+			}
+			arr = append(arr, item)
+		}
+
+		return arr, true
+	}
+
+	str := firstValue(raw)
+	switch s.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(str, 64)
+		return n, err == nil
+	case "boolean":
+		b, err := strconv.ParseBool(str)
+		return b, err == nil
+	}
+
+	return str, true
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -26,6 +26,8 @@ func getRequestLogs(ctx context.Context) string {
 // New returns a new recover middleware,
 // it recovers from panics and logs
 // the panic message to the application's logger "Warn" level.
+// The panic information is stored as a `*context.ErrPanicRecovery`
+// through `ctx.SetErr`, so error handlers can render it.
 func New() context.Handler {
 	return func(ctx context.Context) {
 		defer func() {
@@ -34,14 +36,19 @@ func New() context.Handler {
 					return
 				}
 
-				var stacktrace string
+				var (
+					stacktrace string
+					callers    []string
+				)
 				for i := 1; ; i++ {
 					_, f, l, got := runtime.Caller(i)
 					if !got {
 						break
 					}
 
-					stacktrace += fmt.Sprintf("%s:%d\n", f, l)
+					caller := fmt.Sprintf("%s:%d", f, l)
+					callers = append(callers, caller)
+					stacktrace += caller + "\n"
 				}
 
 				// when stack finishes
@@ -51,6 +58,11 @@ func New() context.Handler {
 				logMessage += fmt.Sprintf("\n%s", stacktrace)
 				ctx.Application().Logger().Warn(logMessage)
 
+				ctx.SetErr(&context.ErrPanicRecovery{
+					Cause:       err,
+					HandlerName: ctx.HandlerName(),
+					Callers:     callers,
+				})
 				ctx.StatusCode(500)
 				ctx.StopExecution()
 			}
@@ -0,0 +1,366 @@
+// Package rewrite provides a router wrapper which redirects and rewrites
+// the incoming requests, injects or removes request and response headers
+// and canonicalizes the host, before the router's handlers are executed.
+// The rules can be loaded from a yaml or json file or built programmatically.
+package rewrite
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/core/router"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options holds the rules of the rewrite engine.
+// The rules are evaluated in the order:
+// host canonicalization, redirects, path rewrites and headers.
+// The first matching redirect wins, all matching rewrites and headers rules are applied in order.
+//
+// Example of a rules.yml file:
+//  CanonicalHost: apex
+//  Redirects:
+//    - 301 ^/seo/(.*)$ /$1
+//    - 302 ^/docs/v11/(.*)$ https://docs.example.com/$1
+//  Rewrites:
+//    - ^/legacy/users/(\d+)$ /users/$1
+//  Headers:
+//    - Match: ^/api/
+//      Response:
+//        Set:
+//          Cache-Control: no-store
+//        Remove: [Server]
+type Options struct {
+	// CanonicalHost redirects (301) the requests to the canonical host.
+	// Set it to "apex" to redirect "www.example.com" to "example.com"
+	// or to "www" to redirect "example.com" to "www.example.com".
+	// Defaults to empty, no canonicalization.
+	CanonicalHost string `json:"canonicalHost" yaml:"CanonicalHost"`
+	// Redirects is a list of "[status code] pattern target" rules,
+	// the status code is optional and it defaults to 301.
+	// The "pattern" is a regular expression which matches the request path
+	// and the "target" can contain its submatches ($1, $2, e.t.c.).
+	// The request's query is kept unless the "target" contains a query.
+	Redirects []string `json:"redirects" yaml:"Redirects"`
+	// Rewrites is a list of "pattern target" rules which
+	// replace the request path, internally, before routing.
+	Rewrites []string `json:"rewrites" yaml:"Rewrites"`
+	// Headers is a list of rules which set or remove
+	// request and response headers of the matching request paths.
+	Headers []HeaderRule `json:"headers" yaml:"Headers"`
+}
+
+// HeaderRule sets or removes request and response headers
+// of the requests whose path matches the "Match" regular expression.
+type HeaderRule struct {
+	// Match is the regular expression of the request path.
+	// Defaults to empty, matches all requests.
+	Match    string        `json:"match" yaml:"Match"`
+	Request  HeaderActions `json:"request" yaml:"Request"`
+	Response HeaderActions `json:"response" yaml:"Response"`
+}
+
+// HeaderActions holds the headers to set and to remove.
+type HeaderActions struct {
+	Set    map[string]string `json:"set" yaml:"Set"`
+	Remove []string          `json:"remove" yaml:"Remove"`
+}
+
+// Redirect adds a redirect rule, see `Options.Redirects`.
+func (opts *Options) Redirect(statusCode int, pattern, target string) *Options {
+	opts.Redirects = append(opts.Redirects, fmt.Sprintf("%d %s %s", statusCode, pattern, target))
+	return opts
+}
+
+// Rewrite adds a path rewrite rule, see `Options.Rewrites`.
+func (opts *Options) Rewrite(pattern, target string) *Options {
+	opts.Rewrites = append(opts.Rewrites, pattern+" "+target)
+	return opts
+}
+
+// Header adds a headers rule, see `Options.Headers`.
+func (opts *Options) Header(rule HeaderRule) *Options {
+	opts.Headers = append(opts.Headers, rule)
+	return opts
+}
+
+// Canonical sets the `Options.CanonicalHost`, "apex" or "www".
+func (opts *Options) Canonical(host string) *Options {
+	opts.CanonicalHost = host
+	return opts
+}
+
+// Load reads the rules of a yaml or json (.json extension) file
+// and returns a new rewrite router wrapper. It panics on errors.
+//
+// Usage:
+//  app.WrapRouter(rewrite.Load("rules.yml"))
+func Load(filename string) router.WrapperFunc {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic(fmt.Sprintf("rewrite: load: %v", err))
+	}
+
+	var opts Options
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(b, &opts)
+	} else {
+		err = yaml.Unmarshal(b, &opts)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("rewrite: load: %s: %v", filename, err))
+	}
+
+	wrapper, err := New(opts)
+	if err != nil {
+		panic(fmt.Sprintf("rewrite: load: %s: %v", filename, err))
+	}
+
+	return wrapper
+}
+
+type (
+	redirectRule struct {
+		pattern    *regexp.Regexp
+		target     string
+		statusCode int
+	}
+
+	rewriteRule struct {
+		pattern *regexp.Regexp
+		target  string
+	}
+
+	headerRule struct {
+		pattern  *regexp.Regexp // nil matches all.
+		request  HeaderActions
+		response HeaderActions
+	}
+
+	engine struct {
+		canonicalHost string
+		redirects     []redirectRule
+		rewrites      []rewriteRule
+		headers       []headerRule
+	}
+)
+
+// New compiles the "opts" rules and returns a new rewrite router wrapper.
+//
+// Usage:
+//  opts := new(rewrite.Options).
+//      Canonical("apex").
+//      Redirect(iris.StatusMovedPermanently, "^/seo/(.*)$", "/$1").
+//      Rewrite(`^/legacy/users/(\d+)$`, "/users/$1")
+//  wrapper, err := rewrite.New(*opts)
+//  app.WrapRouter(wrapper)
+func New(opts Options) (router.WrapperFunc, error) {
+	e := new(engine)
+
+	switch opts.CanonicalHost {
+	case "", "apex", "www":
+		e.canonicalHost = opts.CanonicalHost
+	default:
+		return nil, fmt.Errorf("rewrite: canonical host: expected \"apex\" or \"www\" but got %q", opts.CanonicalHost)
+	}
+
+	for _, rule := range opts.Redirects {
+		fields := strings.Fields(rule)
+		statusCode := http.StatusMovedPermanently
+		if len(fields) == 3 {
+			code, err := strconv.Atoi(fields[0])
+			if err != nil || code < 300 || code > 399 {
+				return nil, fmt.Errorf("rewrite: redirect: %q: invalid status code", rule)
+			}
+			statusCode = code
+			fields = fields[1:]
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("rewrite: redirect: %q: expected \"[status code] pattern target\"", rule)
+		}
+
+		pattern, err := regexp.Compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("rewrite: redirect: %q: %w", rule, err)
+		}
+
+		e.redirects = append(e.redirects, redirectRule{pattern: pattern, target: fields[1], statusCode: statusCode})
+	}
+
+	for _, rule := range opts.Rewrites {
+		fields := strings.Fields(rule)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("rewrite: rewrite: %q: expected \"pattern target\"", rule)
+		}
+
+		pattern, err := regexp.Compile(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("rewrite: rewrite: %q: %w", rule, err)
+		}
+
+		e.rewrites = append(e.rewrites, rewriteRule{pattern: pattern, target: fields[1]})
+	}
+
+	for _, rule := range opts.Headers {
+		h := headerRule{request: rule.Request, response: rule.Response}
+		if rule.Match != "" {
+			pattern, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return nil, fmt.Errorf("rewrite: headers: %q: %w", rule.Match, err)
+			}
+			h.pattern = pattern
+		}
+
+		e.headers = append(e.headers, h)
+	}
+
+	return e.wrapper, nil
+}
+
+func (e *engine) wrapper(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if target, ok := e.canonicalURL(r); ok {
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	path := r.URL.Path
+	for _, rule := range e.redirects {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+
+		target := rule.pattern.ReplaceAllString(path, rule.target)
+		if r.URL.RawQuery != "" && !strings.Contains(target, "?") {
+			target += "?" + r.URL.RawQuery
+		}
+
+		http.Redirect(w, r, target, rule.statusCode)
+		return
+	}
+
+	for _, rule := range e.rewrites {
+		if rule.pattern.MatchString(path) {
+			path = rule.pattern.ReplaceAllString(path, rule.target)
+		}
+	}
+
+	if path != r.URL.Path {
+		if idx := strings.IndexByte(path, '?'); idx != -1 {
+			r.URL.RawQuery = path[idx+1:]
+			path = path[:idx]
+		}
+
+		r.URL.Path = path
+		r.URL.RawPath = ""
+		r.RequestURI = r.URL.RequestURI()
+	}
+
+	var removeResponseHeaders []string
+	for _, rule := range e.headers {
+		if rule.pattern != nil && !rule.pattern.MatchString(path) {
+			continue
+		}
+
+		for _, key := range rule.request.Remove {
+			r.Header.Del(key)
+		}
+		for key, value := range rule.request.Set {
+			r.Header.Set(key, value)
+		}
+
+		for key, value := range rule.response.Set {
+			w.Header().Set(key, value)
+		}
+		removeResponseHeaders = append(removeResponseHeaders, rule.response.Remove...)
+	}
+
+	if len(removeResponseHeaders) > 0 {
+		w = &responseWriter{ResponseWriter: w, remove: removeResponseHeaders}
+	}
+
+	next(w, r)
+}
+
+// canonicalURL returns the URL of the canonical host, if the request's host is not the canonical one.
+func (e *engine) canonicalURL(r *http.Request) (string, bool) {
+	if e.canonicalHost == "" {
+		return "", false
+	}
+
+	host := r.Host
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	if hostname == "localhost" || net.ParseIP(hostname) != nil {
+		return "", false
+	}
+
+	hasWWW := strings.HasPrefix(host, "www.")
+	switch {
+	case e.canonicalHost == "apex" && hasWWW:
+		host = strings.TrimPrefix(host, "www.")
+	case e.canonicalHost == "www" && !hasWWW && strings.Count(hostname, ".") == 1:
+		host = "www." + host
+	default:
+		return "", false
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + host + r.URL.RequestURI(), true
+}
+
+// responseWriter removes headers before the response is written.
+type responseWriter struct {
+	http.ResponseWriter
+	remove      []string
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for _, key := range w.remove {
+			w.ResponseWriter.Header().Del(key)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, http.ErrNotSupported
+}
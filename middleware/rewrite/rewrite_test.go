@@ -0,0 +1,127 @@
+package rewrite_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/rewrite"
+
+	"github.com/gavv/httpexpect"
+)
+
+const rules = `
+CanonicalHost: apex
+Redirects:
+  - 302 ^/seo/(.*)$ /$1
+  - ^/docs/v11/(.*)$ https://docs.example.com/$1
+Rewrites:
+  - ^/legacy/users/(\d+)$ /users/$1
+Headers:
+  - Match: ^/users/
+    Request:
+      Set:
+        X-Legacy: "true"
+    Response:
+      Set:
+        Cache-Control: no-store
+      Remove: [X-Powered-By]
+`
+
+func newApp(t *testing.T) *iris.Application {
+	dir, err := ioutil.TempDir("", "rewrite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	filename := filepath.Join(dir, "rules.yml")
+	if err = ioutil.WriteFile(filename, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.WrapRouter(rewrite.Load(filename))
+
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index " + ctx.URLParam("q"))
+	})
+	app.Get("/users/{id:int}", func(ctx iris.Context) {
+		ctx.Header("X-Powered-By", "Iris")
+		ctx.Writef("user %d %s", ctx.Params().GetIntDefault("id", 0), ctx.GetHeader("X-Legacy"))
+	})
+
+	return app
+}
+
+func TestRewrite(t *testing.T) {
+	app := newApp(t)
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	www := httptest.New(t, app, httptest.URL("http://www.example.com"))
+	noFollow := noFollowClient(app)
+
+	e.GET("/seo/").WithQuery("q", "iris").Expect().Status(httptest.StatusOK).Body().Equal("index iris")
+	e.GET("/seo/").WithClient(noFollow).Expect().
+		Status(httptest.StatusFound).Header("Location").Equal("/")
+	e.GET("/docs/v11/routing").WithClient(noFollow).Expect().
+		Status(httptest.StatusMovedPermanently).Header("Location").Equal("https://docs.example.com/routing")
+
+	resp := e.GET("/legacy/users/42").Expect().Status(httptest.StatusOK)
+	resp.Body().Equal("user 42 true")
+	resp.Header("Cache-Control").Equal("no-store")
+	resp.Header("X-Powered-By").Empty()
+
+	www.GET("/users/1").WithClient(noFollow).Expect().
+		Status(httptest.StatusMovedPermanently).Header("Location").Equal("http://example.com/users/1")
+}
+
+func TestRewriteOptions(t *testing.T) {
+	opts := new(rewrite.Options).
+		Canonical("www").
+		Redirect(iris.StatusTemporaryRedirect, "^/old$", "/new?from=old").
+		Rewrite("^/v1/(.*)$", "/$1")
+
+	wrapper, err := rewrite.New(*opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.WrapRouter(wrapper)
+	app.Get("/ping", func(ctx iris.Context) {
+		ctx.WriteString("pong")
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	www := httptest.New(t, app, httptest.URL("http://www.example.com"))
+	noFollow := noFollowClient(app)
+	www.GET("/v1/ping").Expect().Status(httptest.StatusOK).Body().Equal("pong")
+	www.GET("/old").WithClient(noFollow).Expect().
+		Status(httptest.StatusTemporaryRedirect).Header("Location").Equal("/new?from=old")
+	e.GET("/ping").WithClient(noFollow).Expect().
+		Status(httptest.StatusMovedPermanently).Header("Location").Equal("http://www.example.com/ping")
+
+	for _, invalid := range []rewrite.Options{
+		{CanonicalHost: "invalid"},
+		{Redirects: []string{"200 ^/a$ /b"}},
+		{Redirects: []string{"^/a$"}},
+		{Rewrites: []string{"^/(a$ /b"}},
+	} {
+		if _, err = rewrite.New(invalid); err == nil {
+			t.Fatalf("expected an error for options: %#+v", invalid)
+		}
+	}
+}
+
+func noFollowClient(app *iris.Application) *http.Client {
+	return &http.Client{
+		Transport: httpexpect.NewBinder(app),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,161 @@
+// Package singleflight provides a middleware which coalesces concurrent identical GET requests
+// into a single handler execution, its response is shared with all the waiting requests.
+// It reduces the thundering-herd load on expensive endpoints, e.g. on a cache miss.
+package singleflight
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/singleflight.*", "Singleflight")
+}
+
+// SharedHeaderKey is the response header key which is set to "true"
+// on the responses of the coalesced (waiting) requests.
+const SharedHeaderKey = "X-Singleflight-Shared"
+
+// Options holds the settings for the `New` middleware.
+type Options struct {
+	// Vary is the list of request header keys, in addition to the method, host, path and URL query,
+	// that make two requests different.
+	// Defaults to "Accept", "Accept-Encoding" and "Accept-Language".
+	Vary []string
+	// Key, if not nil, overrides the default request key.
+	// Return an empty string to skip the coalescing of a request.
+	// A custom key must include the request's credentials, if the response depends on them,
+	// otherwise the response of one user is shared with the others.
+	Key func(ctx context.Context) string
+}
+
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+type call struct {
+	done chan struct{}
+	resp *response // nil if the leader's handler panicked.
+}
+
+// group holds the in-flight requests of a `New` middleware.
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// New returns a new singleflight middleware.
+// Only GET and HEAD requests are coalesced and a request's key is its
+// method, path, URL query and the values of the `Options.Vary` headers.
+// The requests with credentials, i.e. an "Authorization" or a "Cookie" header,
+// are not coalesced by the default key, their responses may be per-user.
+// The "Set-Cookie" headers of the shared response are never sent to the waiting requests.
+//
+// Usage:
+//  app.Get("/report", singleflight.New(), expensiveReport)
+func New(opts ...Options) context.Handler {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if len(o.Vary) == 0 {
+		o.Vary = []string{"Accept", "Accept-Encoding", "Accept-Language"}
+	}
+
+	if o.Key == nil {
+		o.Key = func(ctx context.Context) string {
+			r := ctx.Request()
+			if r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != "" {
+				return ""
+			}
+
+			var b strings.Builder
+			b.WriteString(r.Method)
+			b.WriteByte(' ')
+			b.WriteString(r.Host)
+			b.WriteString(r.URL.RequestURI())
+			for _, key := range o.Vary {
+				b.WriteByte('\n')
+				b.WriteString(r.Header.Get(key))
+			}
+
+			return b.String()
+		}
+	}
+
+	g := &group{calls: make(map[string]*call)}
+
+	return func(ctx context.Context) {
+		if method := ctx.Method(); method != http.MethodGet && method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		key := o.Key(ctx)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		g.mu.Lock()
+		if c, ok := g.calls[key]; ok {
+			g.mu.Unlock()
+
+			select {
+			case <-c.done:
+			case <-ctx.Request().Context().Done():
+				return
+			}
+
+			if c.resp == nil {
+				// leader failed, execute it.
+				ctx.Next()
+				return
+			}
+
+			write(ctx, c.resp)
+			return
+		}
+
+		c := &call{done: make(chan struct{})}
+		g.calls[key] = c
+		g.mu.Unlock()
+
+		defer func() {
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(c.done)
+		}()
+
+		ctx.Record()
+		ctx.Next()
+
+		rec := ctx.Recorder()
+		header := rec.Header().Clone()
+		header.Del("Set-Cookie") // the cookies belong to the leader's client.
+		c.resp = &response{
+			statusCode: ctx.GetStatusCode(),
+			header:     header,
+			body:       append([]byte(nil), rec.Body()...),
+		}
+	}
+}
+
+func write(ctx context.Context, resp *response) {
+	header := ctx.ResponseWriter().Header()
+	for k, v := range resp.header {
+		header[k] = v
+	}
+	header.Set(SharedHeaderKey, "true")
+
+	ctx.StatusCode(resp.statusCode)
+	ctx.Write(resp.body)
+	ctx.StopExecution()
+}
@@ -0,0 +1,91 @@
+package singleflight_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/singleflight"
+)
+
+func TestSingleflight(t *testing.T) {
+	var executions uint32
+
+	app := iris.New()
+	app.Get("/report", singleflight.New(), func(ctx iris.Context) {
+		atomic.AddUint32(&executions, 1)
+		time.Sleep(200 * time.Millisecond)
+		ctx.Header("X-Report", "1")
+		ctx.SetCookieKV("visited", "true")
+		ctx.WriteString("report of " + ctx.URLParamDefault("year", "all"))
+	})
+
+	e := httptest.New(t, app)
+
+	const n = 10
+	var (
+		wg     sync.WaitGroup
+		shared uint32
+	)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			resp := e.GET("/report").Expect().Status(httptest.StatusOK)
+			resp.Header("X-Report").Equal("1")
+			resp.Body().Equal("report of all")
+			if resp.Raw().Header.Get(singleflight.SharedHeaderKey) == "true" {
+				atomic.AddUint32(&shared, 1)
+				if cookie := resp.Raw().Header.Get("Set-Cookie"); cookie != "" {
+					t.Errorf("expected no cookies on a shared response but got: %s", cookie)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadUint32(&executions); got+atomic.LoadUint32(&shared) != n || got >= n {
+		t.Fatalf("expected coalesced executions but got %d executions and %d shared responses", got, shared)
+	}
+
+	// different query, different key, sequential requests are not coalesced.
+	atomic.StoreUint32(&executions, 0)
+	e.GET("/report").WithQuery("year", 2020).Expect().Status(httptest.StatusOK).Body().Equal("report of 2020")
+	e.GET("/report").Expect().Status(httptest.StatusOK).Header(singleflight.SharedHeaderKey).Empty()
+	if got := atomic.LoadUint32(&executions); got != 2 {
+		t.Fatalf("expected 2 executions but got %d", got)
+	}
+
+	// different hosts, different keys.
+	atomic.StoreUint32(&executions, 0)
+	wg.Add(2)
+	for _, host := range []string{"a.example.com", "b.example.com"} {
+		go func(host string) {
+			defer wg.Done()
+			e.GET("/report").WithHeader("Host", host).Expect().Status(httptest.StatusOK).
+				Header(singleflight.SharedHeaderKey).Empty()
+		}(host)
+	}
+	wg.Wait()
+	if got := atomic.LoadUint32(&executions); got != 2 {
+		t.Fatalf("expected 2 executions but got %d", got)
+	}
+
+	// requests with credentials are not coalesced.
+	atomic.StoreUint32(&executions, 0)
+	wg.Add(2)
+	for _, token := range []string{"Bearer a", "Bearer b"} {
+		go func(token string) {
+			defer wg.Done()
+			e.GET("/report").WithHeader("Authorization", token).Expect().Status(httptest.StatusOK).
+				Header(singleflight.SharedHeaderKey).Empty()
+		}(token)
+	}
+	wg.Wait()
+	if got := atomic.LoadUint32(&executions); got != 2 {
+		t.Fatalf("expected 2 executions but got %d", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Package timeline provides a middleware which records the timed phases of each request,
+// the handlers, the session's load, the view's render and the response writes,
+// and sends them through the Server-Timing response header, so the browser's developer tools
+// show where the latency of a request goes.
+package timeline
+
+import (
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/timeline.*", "Timeline")
+}
+
+// Options holds the optional settings of the timeline middleware.
+type Options struct {
+	// DisableHeader disables the timeline's metrics of the Server-Timing response header,
+	// see `Context.ServerTiming`.
+	// Defaults to false.
+	DisableHeader bool
+	// OnEnd, if not nil, is called after the handlers with the request's timeline,
+	// e.g. to log the slow requests' segments.
+	// Defaults to nil.
+	OnEnd func(ctx context.Context, t *context.Timeline)
+}
+
+// New returns a middleware which enables the timeline of the requests, see `Context.Timeline`.
+// Each next handler is recorded as a segment of its name, the sessions, the views
+// and the response writes add their own segments ("session", "view" and "write")
+// and custom ones can be added through `ctx.Timeline().Begin("name")`.
+//
+// The response is recorded, so the Server-Timing header contains all the segments.
+// Register it before any other handler, e.g. through `app.UseGlobal`,
+// as only the handlers after it are recorded. It's meant for development and profiling.
+//
+// Example Code:
+//  app.UseGlobal(timeline.New(timeline.Options{}))
+//  app.Get("/users", func(ctx iris.Context) {
+//      end := ctx.Timeline().Begin("db")
+//      users := db.Users()
+//      end()
+//      ctx.JSON(users)
+//  })
+func New(opts Options) context.Handler {
+	return func(ctx context.Context) {
+		t := context.NewTimeline()
+		ctx.SetTimeline(t)
+		ctx.Record()
+
+		// record each next handler, the route's handlers are not modified.
+		handlers := ctx.Handlers()
+		idx := ctx.HandlerIndex(-1)
+		recorded := make(context.Handlers, len(handlers))
+		copy(recorded, handlers)
+		for i := idx + 1; i < len(recorded); i++ {
+			recorded[i] = segment(recorded[i])
+		}
+		ctx.SetHandlers(recorded)
+
+		ctx.Next()
+
+		if opts.OnEnd != nil {
+			opts.OnEnd(ctx, t)
+		}
+
+		if !opts.DisableHeader {
+			st := ctx.ServerTiming()
+			for _, m := range t.ServerTimingMetrics() {
+				st.Add(m.Name, m.Duration, m.Description)
+			}
+		}
+	}
+}
+
+func segment(h context.Handler) context.Handler {
+	name := context.HandlerName(h)
+	return func(ctx context.Context) {
+		end := ctx.Timeline().Begin(name)
+		h(ctx)
+		end()
+	}
+}
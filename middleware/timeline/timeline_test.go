@@ -0,0 +1,72 @@
+package timeline_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/timeline"
+)
+
+func TestTimeline(t *testing.T) {
+	var segments []context.TimelineSegment
+
+	app := iris.New()
+	app.UseGlobal(timeline.New(timeline.Options{
+		OnEnd: func(ctx iris.Context, t *context.Timeline) {
+			segments = t.Segments()
+		},
+	}))
+	app.Use(func(ctx iris.Context) {
+		ctx.Next()
+	})
+	app.Get("/", func(ctx iris.Context) {
+		end := ctx.Timeline().Begin("db")
+		time.Sleep(5 * time.Millisecond)
+		end()
+
+		ctx.JSON(iris.Map{"ok": true})
+	})
+
+	e := httptest.New(t, app)
+	header := e.GET("/").Expect().Status(httptest.StatusOK).Header("Server-Timing").Raw()
+
+	for _, expected := range []string{"db;dur=", "write;dur=", "total;dur="} {
+		if !strings.Contains(header, expected) {
+			t.Fatalf("expected Server-Timing header to contain %q but got: %s", expected, header)
+		}
+	}
+
+	// middleware, main handler, db, write.
+	if expected, got := 4, len(segments); expected != got {
+		t.Fatalf("expected %d segments but got %d: %#+v", expected, got, segments)
+	}
+
+	if db := segments[2]; db.Name != "db" || db.Depth != 2 || db.Duration < 5*time.Millisecond {
+		t.Fatalf("unexpected db segment: %#+v", db)
+	}
+
+	if main, db := segments[1], segments[2]; main.Duration < db.Duration || db.Offset < main.Offset {
+		t.Fatalf("expected the db segment to be nested to the main handler's one: %#+v", segments)
+	}
+
+	tl := context.NewTimeline()
+	tl.Begin("example.com/users.list")()
+	tl.Begin("write")()
+	tl.Begin("write")()
+	tl.Begin("pending")
+	if header := tl.ServerTiming(); !strings.HasPrefix(header, "h0;") || !strings.Contains(header, `;desc="example.com/users.list", write`) ||
+		strings.Count(header, "write") != 1 || strings.Contains(header, "pending") {
+		t.Fatalf("unexpected Server-Timing header: %s", header)
+	}
+
+	// a nil timeline does nothing.
+	var nilTimeline *context.Timeline
+	nilTimeline.Begin("nothing")()
+	if nilTimeline.ServerTiming() != "" {
+		t.Fatal("expected an empty header of a nil timeline")
+	}
+}
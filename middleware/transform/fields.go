@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Fields returns a `Transformer` which implements JSON sparse fieldsets:
+// when the request contains the "param" URL query parameter (e.g. "?fields=id,name,author.name")
+// only those fields of the JSON object, or of each object of a JSON array, are sent.
+// Nested fields are separated by dots.
+//
+// The "param" defaults to "fields".
+func Fields(param string) Transformer {
+	if param == "" {
+		param = "fields"
+	}
+
+	return func(ctx context.Context, mediaType string, body []byte) ([]byte, error) {
+		if mediaType != context.ContentJSONHeaderValue {
+			return body, nil
+		}
+
+		fields := parseFields(ctx.URLParam(param))
+		if len(fields) == 0 {
+			return body, nil
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(filterFields(v, fields))
+	}
+}
+
+// fieldSet is a tree of the requested fields,
+// a nil value means the whole field.
+type fieldSet map[string]fieldSet
+
+func parseFields(s string) fieldSet {
+	if s == "" {
+		return nil
+	}
+
+	fields := make(fieldSet)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		set := fields
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			sub, ok := set[part]
+			if i == len(parts)-1 {
+				// "author" and "author.name" requested: keep the whole field.
+				set[part] = nil
+				break
+			}
+
+			if ok && sub == nil {
+				// the whole field is already requested.
+				break
+			}
+
+			if !ok {
+				sub = make(fieldSet)
+				set[part] = sub
+			}
+			set = sub
+		}
+	}
+
+	return fields
+}
+
+func filterFields(v interface{}, fields fieldSet) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		filtered := make(map[string]interface{}, len(fields))
+		for key, sub := range fields {
+			fieldValue, ok := value[key]
+			if !ok {
+				continue
+			}
+
+			if sub != nil {
+				fieldValue = filterFields(fieldValue, sub)
+			}
+			filtered[key] = fieldValue
+		}
+		return filtered
+	case []interface{}:
+		for i := range value {
+			value[i] = filterFields(value[i], fields)
+		}
+		return value
+	default:
+		return v
+	}
+}
@@ -0,0 +1,260 @@
+package transform
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Minify returns a `Transformer` which minifies HTML, CSS and JavaScript responses
+// based on their media type. The minification is conservative,
+// it never changes the meaning of the document, see `MinifyHTML`, `MinifyCSS` and `MinifyJS`.
+func Minify() Transformer {
+	return func(ctx context.Context, mediaType string, body []byte) ([]byte, error) {
+		switch mediaType {
+		case context.ContentHTMLHeaderValue:
+			return MinifyHTML(body), nil
+		case "text/css":
+			return MinifyCSS(body), nil
+		case context.ContentJavascriptHeaderValue, "application/javascript", "application/x-javascript":
+			return MinifyJS(body), nil
+		default:
+			return body, nil
+		}
+	}
+}
+
+// rawHTMLTags are the elements which their contents are kept as they are.
+var rawHTMLTags = []string{"pre", "textarea", "script"}
+
+// MinifyHTML removes the comments (except the conditional ones) and collapses the white spaces of an HTML document.
+// The contents of the pre, textarea and script elements are kept as they are,
+// the contents of the style elements are minified through `MinifyCSS`.
+func MinifyHTML(b []byte) []byte {
+	var (
+		out   = make([]byte, 0, len(b))
+		space = false
+	)
+
+	for i := 0; i < len(b); {
+		c := b[i]
+
+		if c == '<' {
+			if bytes.HasPrefix(b[i:], []byte("<!--")) && !bytes.HasPrefix(b[i:], []byte("<!--[if")) {
+				end := bytes.Index(b[i+4:], []byte("-->"))
+				if end == -1 {
+					break
+				}
+				i += 4 + end + 3
+				continue
+			}
+
+			if space {
+				out = append(out, ' ')
+				space = false
+			}
+
+			if tag, ok := openingTag(b[i:], "style"); ok {
+				start := i + len(tag)
+				end := indexClosingTag(b[start:], "style")
+				out = append(out, tag...)
+				out = append(out, MinifyCSS(b[start:start+end])...)
+				i = start + end
+				continue
+			}
+
+			raw := false
+			for _, name := range rawHTMLTags {
+				if tag, ok := openingTag(b[i:], name); ok {
+					start := i + len(tag)
+					end := indexClosingTag(b[start:], name)
+					out = append(out, b[i:start+end]...)
+					i = start + end
+					raw = true
+					break
+				}
+			}
+
+			if raw {
+				continue
+			}
+		}
+
+		if isSpace(c) {
+			if len(out) > 0 {
+				space = true
+			}
+			i++
+			continue
+		}
+
+		if space {
+			out = append(out, ' ')
+			space = false
+		}
+
+		out = append(out, c)
+		i++
+	}
+
+	return out
+}
+
+// openingTag reports whether "b" starts with the opening tag of the "name" element
+// and returns that whole tag.
+func openingTag(b []byte, name string) ([]byte, bool) {
+	if len(b) < len(name)+2 || !strings.EqualFold(string(b[1:1+len(name)]), name) {
+		return nil, false
+	}
+
+	if c := b[1+len(name)]; c != '>' && !isSpace(c) {
+		return nil, false
+	}
+
+	end := bytes.IndexByte(b, '>')
+	if end == -1 {
+		return nil, false
+	}
+
+	return b[:end+1], true
+}
+
+// indexClosingTag returns the index of the closing tag of the "name" element
+// or the length of "b" if it's missing.
+func indexClosingTag(b []byte, name string) int {
+	end := bytes.Index(bytes.ToLower(b), []byte("</"+name))
+	if end == -1 {
+		return len(b)
+	}
+
+	return end
+}
+
+// MinifyCSS removes the comments and the unnecessary white spaces of a stylesheet.
+// Strings are kept as they are.
+func MinifyCSS(b []byte) []byte {
+	var (
+		out   = make([]byte, 0, len(b))
+		space = false
+	)
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			if space && strings.IndexByte("{};,>:", out[len(out)-1]) == -1 {
+				out = append(out, ' ')
+			}
+			space = false
+			end := indexStringEnd(b[i+1:], c)
+			out = append(out, b[i:i+1+end]...)
+			i += end
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := bytes.Index(b[i+2:], []byte("*/"))
+			if end == -1 {
+				return out
+			}
+			i += 2 + end + 1
+		case isSpace(c):
+			space = len(out) > 0
+		default:
+			if strings.IndexByte("{};,>", c) != -1 {
+				// drop the white spaces around them and the last semicolon of a block.
+				space = false
+				if c == '}' && len(out) > 0 && out[len(out)-1] == ';' {
+					out = out[:len(out)-1]
+				}
+			} else if space {
+				if last := out[len(out)-1]; strings.IndexByte("{};,>:", last) == -1 {
+					out = append(out, ' ')
+				}
+				space = false
+			}
+
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// MinifyJS removes the whole-line comments, the indentation and the empty lines of a script.
+// It does not touch the lines inside template literals and it never joins lines,
+// so the automatic semicolon insertion rules are respected.
+func MinifyJS(b []byte) []byte {
+	var (
+		out      = make([]byte, 0, len(b))
+		template = false // inside a multi-line template literal.
+		comment  = false // inside a multi-line comment.
+	)
+
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		if template {
+			out = append(out, line...)
+			out = append(out, '\n')
+			template = countBackticks(line)%2 == 0
+			continue
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if comment {
+			if end := bytes.Index(trimmed, []byte("*/")); end != -1 {
+				comment = false
+				trimmed = bytes.TrimSpace(trimmed[end+2:])
+			} else {
+				continue
+			}
+		}
+
+		if bytes.HasPrefix(trimmed, []byte("/*")) {
+			end := bytes.Index(trimmed[2:], []byte("*/"))
+			if end == -1 {
+				comment = true
+				continue
+			}
+			trimmed = bytes.TrimSpace(trimmed[2+end+2:])
+		}
+
+		if len(trimmed) == 0 || bytes.HasPrefix(trimmed, []byte("//")) {
+			continue
+		}
+
+		out = append(out, trimmed...)
+		out = append(out, '\n')
+		template = countBackticks(trimmed)%2 == 1
+	}
+
+	return bytes.TrimSuffix(out, []byte("\n"))
+}
+
+func countBackticks(b []byte) int {
+	n := 0
+	for i, c := range b {
+		if c == '`' && (i == 0 || b[i-1] != '\\') {
+			n++
+		}
+	}
+
+	return n
+}
+
+// indexStringEnd returns the index of the closing "quote" of a string
+// or the last index of "b" if it's missing.
+func indexStringEnd(b []byte, quote byte) int {
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+
+	return len(b)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
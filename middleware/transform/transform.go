@@ -0,0 +1,53 @@
+// Package transform provides a middleware which post-processes the recorded response body
+// through a pipeline of transformers before it's flushed to the client,
+// e.g. to minify HTML, CSS and JavaScript responses (`Minify`)
+// or to filter the JSON response fields requested by the client (`Fields`).
+package transform
+
+import (
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/transform.*", "Transform")
+}
+
+// Transformer receives the recorded body of a response and its media type (e.g. "text/html")
+// and returns the new body. A transformer which does not apply to a media type
+// should return the "body" as it is.
+type Transformer func(ctx context.Context, mediaType string, body []byte) ([]byte, error)
+
+// New returns a new middleware which records the response
+// and passes its body through the "transformers", in order, before the response is sent.
+// Transformers are not fired for responses without a body or
+// with a Content-Encoding (e.g. compressed) already set.
+// A transformer's error is logged and the body remains as it was before that transformer.
+//
+// Usage:
+//  app.Use(transform.New(transform.Minify(), transform.Fields("fields")))
+func New(transformers ...Transformer) context.Handler {
+	return func(ctx context.Context) {
+		ctx.Record()
+		ctx.Next()
+
+		rec := ctx.Recorder()
+		body := rec.Body()
+		if len(body) == 0 || rec.Header().Get(context.ContentEncodingHeaderKey) != "" {
+			return
+		}
+
+		mediaType := context.TrimHeaderValue(ctx.GetContentType())
+		for _, transformer := range transformers {
+			newBody, err := transformer(ctx, mediaType, body)
+			if err != nil {
+				ctx.Application().Logger().Debugf("transform: %v", err)
+				continue
+			}
+
+			body = newBody
+		}
+
+		rec.Header().Del(context.ContentLengthHeaderKey)
+		rec.SetBody(body)
+	}
+}
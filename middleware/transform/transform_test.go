@@ -0,0 +1,69 @@
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/transform"
+)
+
+func TestMinify(t *testing.T) {
+	tests := []struct {
+		minify   func([]byte) []byte
+		input    string
+		expected string
+	}{
+		{
+			transform.MinifyHTML,
+			"<html>\n  <!-- comment -->\n  <body>\n    <h1>Hello   World</h1>\n    <pre>  keep\n  it </pre>\n" +
+				"<style>\n  body {\n    color: red;\n  }\n</style>\n  </body>\n</html>\n",
+			"<html> <body> <h1>Hello World</h1> <pre>  keep\n  it </pre> <style>body{color:red}</style> </body> </html>",
+		},
+		{
+			transform.MinifyCSS,
+			"/* header */\na :hover,\nb > i {\n  content: \"a  ;  b\";\n  margin: 0 auto;\n}\n",
+			`a :hover,b>i{content:"a  ;  b";margin:0 auto}`,
+		},
+		{
+			transform.MinifyJS,
+			"// comment\nfunction f() {\n  /* block\n  comment */\n  var s = `a\n  b`;\n\n  return s // trailing\n}\n",
+			"function f() {\nvar s = `a\n  b`;\nreturn s // trailing\n}",
+		},
+	}
+
+	for i, tt := range tests {
+		if got := string(tt.minify([]byte(tt.input))); got != tt.expected {
+			t.Fatalf("[%d] expected:\n%q\nbut got:\n%q", i, tt.expected, got)
+		}
+	}
+}
+
+func TestTransform(t *testing.T) {
+	type author struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	type book struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		Author author `json:"author"`
+	}
+
+	app := iris.New()
+	app.Use(transform.New(transform.Minify(), transform.Fields("")))
+	app.Get("/", func(ctx iris.Context) {
+		ctx.HTML("<h1>\n  Hello\n</h1>\n")
+	})
+	app.Get("/books", func(ctx iris.Context) {
+		ctx.JSON([]book{{ID: 1, Title: "Go", Author: author{ID: 2, Name: "Gerasimos"}}})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("<h1> Hello </h1>")
+	e.GET("/books").WithQuery("fields", "title,author.name").Expect().Status(httptest.StatusOK).
+		Body().Equal(`[{"author":{"name":"Gerasimos"},"title":"Go"}]`)
+	e.GET("/books").Expect().Status(httptest.StatusOK).
+		JSON().Array().First().Object().Value("id").Equal(1)
+}
@@ -0,0 +1,145 @@
+// Package urlsigner provides signed URLs, which expire, for protected downloads,
+// temporary links and callback (webhook) URLs verification.
+// A signed URL contains its expiration time, the signing key's identifier and
+// its HMAC-SHA256 signature as URL query parameters.
+package urlsigner
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/urlsigner.*", "URLSigner")
+}
+
+// The URL query parameters of a signed URL.
+const (
+	ExpiresParam   = "expires"
+	KeyIDParam     = "kid"
+	SignatureParam = "signature"
+)
+
+var (
+	// ErrMissingSignature is returned when the URL is not signed.
+	ErrMissingSignature = errors.New("urlsigner: missing signature")
+	// ErrInvalidSignature is returned when the signature does not match or the key is unknown.
+	ErrInvalidSignature = errors.New("urlsigner: invalid signature")
+	// ErrExpired is returned when the signed URL has expired.
+	ErrExpired = errors.New("urlsigner: expired")
+)
+
+// Key is a signing key. The ID is included in the signed URLs
+// so the old keys can be still accepted while they are rotated.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Sign returns the "rawURL" with the expiration, the key's ID and the signature query parameters added.
+// The signature covers the path and the query, not the scheme and the host,
+// so the same URL is valid behind reverse proxies.
+//
+// Example:
+//  link, err := urlsigner.Sign("/downloads/report.pdf", 10*time.Minute, key)
+func Sign(rawURL string, expiry time.Duration, key Key) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Del(SignatureParam)
+	q.Set(ExpiresParam, strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	if key.ID != "" {
+		q.Set(KeyIDParam, key.ID)
+	} else {
+		q.Del(KeyIDParam)
+	}
+
+	q.Set(SignatureParam, signature(u.EscapedPath(), q, key.Secret))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// signature returns the base64 HMAC-SHA256 of the path and the sorted query, without the signature.
+func signature(path string, q url.Values, secret []byte) string {
+	query := make(url.Values, len(q))
+	for k, v := range q {
+		if k != SignatureParam {
+			query[k] = v
+		}
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path + "?" + query.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyURL reports whether the "u" is a valid and not expired signed URL of one of the "keys".
+func VerifyURL(u *url.URL, keys ...Key) error {
+	q := u.Query()
+	sig := q.Get(SignatureParam)
+	if sig == "" {
+		return ErrMissingSignature
+	}
+
+	expires, err := strconv.ParseInt(q.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	kid := q.Get(KeyIDParam)
+	valid := false
+	for _, key := range keys {
+		if key.ID != kid {
+			continue
+		}
+
+		if hmac.Equal([]byte(sig), []byte(signature(u.EscapedPath(), q, key.Secret))) {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expires {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// Verify returns a middleware which allows only the valid and not expired signed URLs
+// of one of the "keys", the rest of the requests are responded with 403 Forbidden
+// and the error (`ErrMissingSignature`, `ErrInvalidSignature` or `ErrExpired`) as `Context.GetErr()`.
+// Pass both the current and the previous keys during a key rotation.
+//
+// Example:
+//  app.Get("/downloads/{file}", urlsigner.Verify(key, oldKey), download)
+func Verify(keys ...Key) context.Handler {
+	if len(keys) == 0 {
+		panic("urlsigner: at least one key is required")
+	}
+
+	return func(ctx context.Context) {
+		if err := VerifyURL(ctx.Request().URL, keys...); err != nil {
+			ctx.SetErr(err)
+			ctx.StopWithStatus(http.StatusForbidden)
+			return
+		}
+
+		ctx.Next()
+	}
+}
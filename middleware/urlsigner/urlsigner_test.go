@@ -0,0 +1,84 @@
+package urlsigner_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/urlsigner"
+
+	"github.com/gavv/httpexpect"
+)
+
+func TestURLSigner(t *testing.T) {
+	oldKey := urlsigner.Key{ID: "2020-01", Secret: []byte("old secret")}
+	key := urlsigner.Key{ID: "2020-06", Secret: []byte("new secret")}
+
+	app := iris.New()
+	app.Get("/downloads/{file}", urlsigner.Verify(key, oldKey), func(ctx iris.Context) {
+		ctx.WriteString(ctx.Params().Get("file"))
+	})
+
+	e := httptest.New(t, app)
+
+	sign := func(rawURL string, expiry time.Duration, key urlsigner.Key) string {
+		signed, err := urlsigner.Sign(rawURL, expiry, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	get := func(signed string) *httpexpect.Response {
+		u, _ := url.Parse(signed)
+		return e.GET(u.Path).WithQueryString(u.RawQuery).Expect()
+	}
+
+	get(sign("/downloads/report.pdf?lang=en", time.Minute, key)).
+		Status(httptest.StatusOK).Body().Equal("report.pdf")
+	// rotated key.
+	get(sign("/downloads/report.pdf", time.Minute, oldKey)).Status(httptest.StatusOK)
+
+	e.GET("/downloads/report.pdf").Expect().Status(httptest.StatusForbidden)
+	get(sign("/downloads/report.pdf", -time.Minute, key)).Status(httptest.StatusForbidden)
+	get(sign("/downloads/report.pdf", time.Minute, urlsigner.Key{ID: "2020-06", Secret: []byte("guess")})).
+		Status(httptest.StatusForbidden)
+
+	tampered := strings.Replace(sign("/downloads/report.pdf", time.Minute, key), "report.pdf", "secret.pdf", 1)
+	get(tampered).Status(httptest.StatusForbidden)
+}
+
+func TestVerifyURL(t *testing.T) {
+	key := urlsigner.Key{Secret: []byte("webhook secret")}
+
+	signed, err := urlsigner.Sign("https://example.com/webhooks/payments?order=42", time.Minute, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := url.Parse(signed)
+	if err = urlsigner.VerifyURL(u, key); err != nil {
+		t.Fatalf("expected a valid signed URL but got: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("order", "43")
+	u.RawQuery = q.Encode()
+	if err = urlsigner.VerifyURL(u, key); err != urlsigner.ErrInvalidSignature {
+		t.Fatalf("expected: %v but got: %v", urlsigner.ErrInvalidSignature, err)
+	}
+
+	u, _ = url.Parse(strings.Replace(signed, "expires=", "expired=", 1))
+	if err = urlsigner.VerifyURL(u, key); err != urlsigner.ErrInvalidSignature {
+		t.Fatalf("expected: %v but got: %v", urlsigner.ErrInvalidSignature, err)
+	}
+
+	expired, _ := urlsigner.Sign("/webhooks", -time.Second, key)
+	u, _ = url.Parse(expired)
+	if err = urlsigner.VerifyURL(u, key); err != urlsigner.ErrExpired {
+		t.Fatalf("expected: %v but got: %v", urlsigner.ErrExpired, err)
+	}
+}
@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider describes how the deliveries of a webhook sender are verified and identified.
+// See `GitHub`, `Stripe` and `Slack`.
+type Provider struct {
+	// Name is the name of the provider, i.e "github".
+	Name string
+	// Verify reports whether the request and its raw "body" are signed by the sender.
+	Verify func(r *http.Request, body []byte) error
+	// DeliveryID returns the unique identifier of the delivery, used for deduplication.
+	// Optional.
+	DeliveryID func(r *http.Request, body []byte) string
+	// EventType returns the type of the event, used by the `Dispatcher`.
+	// Optional.
+	EventType func(r *http.Request, body []byte) string
+}
+
+// Sign returns the hex HMAC-SHA256 of the "payload" using the "secret",
+// the signature format which the builtin providers expect.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifySignature(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}
+
+// verifyTimestamp parses the unix "timestamp" and reports
+// whether it is inside the "tolerance", protects against replays of captured deliveries.
+func verifyTimestamp(timestamp string, tolerance time.Duration) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		if d := time.Since(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+			return ErrTimestamp
+		}
+	}
+
+	return nil
+}
+
+// bodyField returns the string value of a top-level JSON field of the "body",
+// the "path" can contain one dot for a nested object's field, i.e "event.type".
+func bodyField(body []byte, path string) string {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+
+	name := path
+	if idx := strings.IndexByte(path, '.'); idx > 0 {
+		name = path[:idx]
+		return bodyField(fields[name], path[idx+1:])
+	}
+
+	var s string
+	json.Unmarshal(fields[name], &s)
+	return s
+}
+
+// GitHub returns the GitHub webhooks provider.
+// The deliveries are verified through the "X-Hub-Signature-256" header
+// and identified by the "X-GitHub-Delivery" and "X-GitHub-Event" headers.
+func GitHub(secret string) Provider {
+	return Provider{
+		Name: "github",
+		Verify: func(r *http.Request, body []byte) error {
+			signature := r.Header.Get("X-Hub-Signature-256")
+			if signature == "" {
+				return ErrMissingSignature
+			}
+
+			if !verifySignature(secret, body, strings.TrimPrefix(signature, "sha256=")) {
+				return ErrInvalidSignature
+			}
+
+			return nil
+		},
+		DeliveryID: func(r *http.Request, _ []byte) string {
+			return r.Header.Get("X-GitHub-Delivery")
+		},
+		EventType: func(r *http.Request, _ []byte) string {
+			return r.Header.Get("X-GitHub-Event")
+		},
+	}
+}
+
+// Stripe returns the Stripe webhooks provider.
+// The deliveries are verified through the "Stripe-Signature" header, its timestamp
+// must be inside the "tolerance" (zero disables the check, Stripe recommends 5 minutes).
+// The delivery ID and the event type are the "id" and "type" fields of the body.
+func Stripe(secret string, tolerance time.Duration) Provider {
+	return Provider{
+		Name: "stripe",
+		Verify: func(r *http.Request, body []byte) error {
+			header := r.Header.Get("Stripe-Signature")
+			if header == "" {
+				return ErrMissingSignature
+			}
+
+			var (
+				timestamp  string
+				signatures []string
+			)
+
+			for _, part := range strings.Split(header, ",") {
+				kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+
+				switch kv[0] {
+				case "t":
+					timestamp = kv[1]
+				case "v1":
+					signatures = append(signatures, kv[1])
+				}
+			}
+
+			if timestamp == "" || len(signatures) == 0 {
+				return ErrMissingSignature
+			}
+
+			if err := verifyTimestamp(timestamp, tolerance); err != nil {
+				return err
+			}
+
+			payload := append([]byte(timestamp+"."), body...)
+			// more than one signature is sent while the secret is rolled.
+			for _, signature := range signatures {
+				if verifySignature(secret, payload, signature) {
+					return nil
+				}
+			}
+
+			return ErrInvalidSignature
+		},
+		DeliveryID: func(_ *http.Request, body []byte) string {
+			return bodyField(body, "id")
+		},
+		EventType: func(_ *http.Request, body []byte) string {
+			return bodyField(body, "type")
+		},
+	}
+}
+
+// Slack returns the Slack webhooks provider.
+// The deliveries are verified through the "X-Slack-Signature" and "X-Slack-Request-Timestamp" headers,
+// the timestamp must be inside the "tolerance" (zero disables the check, Slack recommends 5 minutes).
+// The delivery ID is the "event_id" field of the body and the event type
+// is the "event.type" field or the "type" one for the non-event callbacks (e.g. "url_verification").
+func Slack(secret string, tolerance time.Duration) Provider {
+	return Provider{
+		Name: "slack",
+		Verify: func(r *http.Request, body []byte) error {
+			signature, timestamp := r.Header.Get("X-Slack-Signature"), r.Header.Get("X-Slack-Request-Timestamp")
+			if signature == "" || timestamp == "" {
+				return ErrMissingSignature
+			}
+
+			if err := verifyTimestamp(timestamp, tolerance); err != nil {
+				return err
+			}
+
+			payload := append([]byte("v0:"+timestamp+":"), body...)
+			if !verifySignature(secret, payload, strings.TrimPrefix(signature, "v0=")) {
+				return ErrInvalidSignature
+			}
+
+			return nil
+		},
+		DeliveryID: func(_ *http.Request, body []byte) string {
+			return bodyField(body, "event_id")
+		},
+		EventType: func(_ *http.Request, body []byte) string {
+			if typ := bodyField(body, "event.type"); typ != "" {
+				return typ
+			}
+
+			return bodyField(body, "type")
+		},
+	}
+}
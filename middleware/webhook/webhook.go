@@ -0,0 +1,363 @@
+// Package webhook provides a toolkit for receiving webhooks.
+// The `New` middleware verifies the signature of a delivery through a `Provider`
+// (`GitHub`, `Stripe`, `Slack` or a custom one), rejects the replayed and duplicated deliveries
+// and preserves the raw body, even after JSON binding, while the `Dispatcher`
+// routes each event to a typed handler based on its event type.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/webhook.*", "Webhook")
+}
+
+var (
+	// ErrMissingSignature is returned when the delivery is not signed.
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	// ErrInvalidSignature is returned when the signature does not match.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	// ErrTimestamp is returned when the signed timestamp is outside the provider's tolerance.
+	ErrTimestamp = errors.New("webhook: timestamp outside the tolerance")
+	// ErrDuplicate is returned when the delivery ID was already received.
+	ErrDuplicate = errors.New("webhook: duplicate delivery")
+	// ErrBodyTooLarge is returned when the body exceeds the `Options.MaxBodySize`.
+	ErrBodyTooLarge = errors.New("webhook: body too large")
+)
+
+// Event is a verified webhook delivery, see `Get`.
+type Event struct {
+	// Provider is the name of the provider, i.e "github".
+	Provider string
+	// ID is the delivery ID, may be empty.
+	ID string
+	// Type is the event type, may be empty.
+	Type string
+	// Body is the raw request body, the signed payload.
+	Body []byte
+}
+
+// Bind decodes the JSON body to the "outPtr".
+func (e *Event) Bind(outPtr interface{}) error {
+	return json.Unmarshal(e.Body, outPtr)
+}
+
+const eventContextKey = "iris.webhook.event"
+
+// Get returns the verified event of the request, if any.
+func Get(ctx context.Context) *Event {
+	if v := ctx.Values().Get(eventContextKey); v != nil {
+		if ev, ok := v.(*Event); ok {
+			return ev
+		}
+	}
+
+	return nil
+}
+
+// Options holds the settings for the `New` middleware.
+type Options struct {
+	// Provider verifies and identifies the deliveries. Required.
+	Provider Provider
+	// Store is the storage of the received delivery IDs.
+	// Defaults to a `NewMemoryStore()`.
+	Store Store
+	// TTL is the time a delivery ID is kept for deduplication.
+	// Defaults to 24 hours.
+	TTL time.Duration
+	// MaxBodySize is the maximum size of the request body.
+	// Defaults to 1MB.
+	MaxBodySize int64
+	// OnError is fired when a delivery is rejected.
+	// The error is one of the package-level errors or the `Provider.Verify` one.
+	//
+	// Defaults to 200 OK for duplicates, so the sender stops retrying,
+	// 413 for large bodies and 401 for the rest.
+	OnError func(ctx context.Context, err error)
+}
+
+// New returns a new webhook receiver middleware.
+// It verifies the signature of the request, stores the `Event` for `Get` and the `Dispatcher`,
+// and resets the request body so it can be read again by the next handlers.
+// The delivery ID is released on server errors (5xx) so the sender's retry is accepted.
+//
+// Usage:
+//  app.Post("/webhooks/github", webhook.New(webhook.Options{Provider: webhook.GitHub(secret)}), dispatcher.Handle)
+func New(opts Options) context.Handler {
+	if opts.Provider.Verify == nil {
+		panic("webhook: Provider.Verify is required")
+	}
+
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1 << 20
+	}
+
+	if opts.OnError == nil {
+		opts.OnError = func(ctx context.Context, err error) {
+			switch err {
+			case ErrDuplicate:
+				ctx.StopWithStatus(http.StatusOK)
+			case ErrBodyTooLarge:
+				ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+			default:
+				ctx.StopWithStatus(http.StatusUnauthorized)
+			}
+		}
+	}
+
+	p := opts.Provider
+
+	return func(ctx context.Context) {
+		r := ctx.Request()
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, opts.MaxBodySize+1))
+		if err != nil {
+			ctx.SetErr(err)
+			ctx.StopWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if int64(len(body)) > opts.MaxBodySize {
+			ctx.SetErr(ErrBodyTooLarge)
+			opts.OnError(ctx, ErrBodyTooLarge)
+			return
+		}
+
+		// preserve the raw body for the next handlers' ReadJSON and e.t.c.
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if err = p.Verify(r, body); err != nil {
+			ctx.SetErr(err)
+			opts.OnError(ctx, err)
+			return
+		}
+
+		ev := &Event{Provider: p.Name, Body: body}
+		if p.DeliveryID != nil {
+			ev.ID = p.DeliveryID(r, body)
+		}
+		if p.EventType != nil {
+			ev.Type = p.EventType(r, body)
+		}
+
+		if ev.ID != "" {
+			key := p.Name + " " + ev.ID
+			added, err := opts.Store.Add(key, opts.TTL)
+			if err != nil {
+				ctx.Application().Logger().Errorf("webhook: add: %v", err)
+				ctx.StopWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			if !added {
+				ctx.SetErr(ErrDuplicate)
+				opts.OnError(ctx, ErrDuplicate)
+				return
+			}
+
+			defer func() {
+				if ctx.GetStatusCode() >= http.StatusInternalServerError {
+					if err := opts.Store.Remove(key); err != nil {
+						ctx.Application().Logger().Errorf("webhook: remove: %v", err)
+					}
+				}
+			}()
+		}
+
+		ctx.Values().Set(eventContextKey, ev)
+		ctx.Next()
+	}
+}
+
+// Store describes the storage of the received delivery IDs.
+type Store interface {
+	// Add stores the "id" for "ttl".
+	// It should report false if the "id" is already stored.
+	Add(id string, ttl time.Duration) (bool, error)
+	// Remove deletes the "id".
+	Remove(id string) error
+}
+
+// MemoryStore is an in-memory `Store`, it's suitable for single-instance applications.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new in-memory `Store`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Add stores the "id" for "ttl", it reports false if the "id" is already stored.
+func (s *MemoryStore) Add(id string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expires, ok := s.entries[id]; ok && now.Before(expires) {
+		return false, nil
+	}
+
+	s.entries[id] = now.Add(ttl)
+
+	// drop the expired entries from time to time.
+	if len(s.entries)%1024 == 0 {
+		for k, expires := range s.entries {
+			if now.After(expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Remove deletes the "id".
+func (s *MemoryStore) Remove(id string) error {
+	s.mu.Lock()
+	delete(s.entries, id)
+	s.mu.Unlock()
+	return nil
+}
+
+var (
+	contextTyp = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorTyp   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type eventHandler struct {
+	fn          reflect.Value
+	withContext bool
+	payloadTyp  reflect.Type // nil when the handler does not accept a payload.
+}
+
+// Dispatcher routes the verified events to typed handlers based on their event type.
+// It should be registered after the `New` middleware.
+type Dispatcher struct {
+	handlers map[string]*eventHandler
+	// Unhandled is fired for the event types without a handler.
+	//
+	// Defaults to 204 No Content.
+	Unhandled context.Handler
+}
+
+// NewDispatcher returns a new empty Dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string]*eventHandler),
+		Unhandled: func(ctx context.Context) {
+			ctx.StatusCode(http.StatusNoContent)
+		},
+	}
+}
+
+// On registers a handler for the "eventType".
+// The "handler" is a function which accepts an optional iris.Context
+// and an optional payload, decoded from the JSON body, and returns nothing or an error, i.e
+//  func(ctx iris.Context, payload *PushEvent) error
+//  func(payload PaymentIntent)
+// A non-nil error responds with 500 Internal Server Error so the sender retries the delivery.
+// It panics on invalid handlers.
+func (d *Dispatcher) On(eventType string, handler interface{}) *Dispatcher {
+	fn := reflect.ValueOf(handler)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func {
+		panic(fmt.Sprintf("webhook: %s: handler is not a function", eventType))
+	}
+
+	h := &eventHandler{fn: fn}
+	in := 0
+	if in < typ.NumIn() && typ.In(in) == contextTyp {
+		h.withContext = true
+		in++
+	}
+
+	if in < typ.NumIn() {
+		h.payloadTyp = typ.In(in)
+		in++
+	}
+
+	if in != typ.NumIn() {
+		panic(fmt.Sprintf("webhook: %s: too many handler input arguments", eventType))
+	}
+
+	if typ.NumOut() > 1 || (typ.NumOut() == 1 && typ.Out(0) != errorTyp) {
+		panic(fmt.Sprintf("webhook: %s: handler should return nothing or an error", eventType))
+	}
+
+	d.handlers[eventType] = h
+	return d
+}
+
+// Handle is the Iris handler which calls the registered handler of the request's event.
+func (d *Dispatcher) Handle(ctx context.Context) {
+	ev := Get(ctx)
+	if ev == nil {
+		ctx.Application().Logger().Errorf("webhook: dispatcher: missing event, register the webhook.New middleware first")
+		ctx.StopWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	h, ok := d.handlers[ev.Type]
+	if !ok {
+		d.Unhandled(ctx)
+		return
+	}
+
+	args := make([]reflect.Value, 0, 2)
+	if h.withContext {
+		args = append(args, reflect.ValueOf(ctx))
+	}
+
+	if h.payloadTyp != nil {
+		ptr := h.payloadTyp.Kind() == reflect.Ptr
+		var payload reflect.Value
+		if ptr {
+			payload = reflect.New(h.payloadTyp.Elem())
+		} else {
+			payload = reflect.New(h.payloadTyp)
+		}
+
+		if err := ev.Bind(payload.Interface()); err != nil {
+			ctx.SetErr(err)
+			ctx.StopWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if !ptr {
+			payload = payload.Elem()
+		}
+		args = append(args, payload)
+	}
+
+	out := h.fn.Call(args)
+	if len(out) == 1 && !out[0].IsNil() {
+		ctx.SetErr(out[0].Interface().(error))
+		ctx.StopWithStatus(http.StatusInternalServerError)
+	}
+}
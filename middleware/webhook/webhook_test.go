@@ -0,0 +1,128 @@
+package webhook_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/webhook"
+
+	"github.com/gavv/httpexpect"
+)
+
+type pushEvent struct {
+	Ref string `json:"ref"`
+}
+
+func TestWebhookGitHub(t *testing.T) {
+	const secret = "github secret"
+
+	var pushes []string
+	d := webhook.NewDispatcher().
+		On("push", func(ctx iris.Context, ev *pushEvent) error {
+			// the raw body is still there after the payload's binding.
+			var again pushEvent
+			if err := ctx.ReadJSON(&again); err != nil || again.Ref != ev.Ref {
+				return errors.New("body was consumed")
+			}
+
+			pushes = append(pushes, ev.Ref)
+			return nil
+		}).
+		On("ping", func() error { return errors.New("ping failed") })
+
+	app := iris.New()
+	app.Post("/webhooks/github", webhook.New(webhook.Options{Provider: webhook.GitHub(secret)}), d.Handle)
+
+	e := httptest.New(t, app)
+
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	deliver := func(id, event, signature string) *httpexpect.Response {
+		return e.POST("/webhooks/github").WithBytes(body).
+			WithHeader("X-GitHub-Delivery", id).
+			WithHeader("X-GitHub-Event", event).
+			WithHeader("X-Hub-Signature-256", signature).Expect()
+	}
+
+	signature := "sha256=" + webhook.Sign(secret, body)
+	deliver("1", "push", signature).Status(httptest.StatusOK)
+	// duplicate.
+	deliver("1", "push", signature).Status(httptest.StatusOK)
+	if expected, got := 1, len(pushes); expected != got {
+		t.Fatalf("expected %d push events but got %d", expected, got)
+	}
+
+	deliver("2", "push", "sha256="+webhook.Sign("wrong", body)).Status(httptest.StatusUnauthorized)
+	e.POST("/webhooks/github").WithBytes(body).Expect().Status(httptest.StatusUnauthorized)
+
+	// failed deliveries can be retried.
+	deliver("3", "ping", signature).Status(httptest.StatusInternalServerError)
+	deliver("3", "ping", signature).Status(httptest.StatusInternalServerError)
+
+	deliver("4", "issues", signature).Status(httptest.StatusNoContent)
+}
+
+func TestWebhookStripe(t *testing.T) {
+	const secret = "whsec_test"
+
+	type paymentIntent struct {
+		Type string `json:"type"`
+		Data struct {
+			Object struct {
+				Amount int `json:"amount"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+
+	var amount int
+	d := webhook.NewDispatcher().On("payment_intent.succeeded", func(ev paymentIntent) {
+		amount += ev.Data.Object.Amount
+	})
+
+	app := iris.New()
+	app.Post("/webhooks/stripe", webhook.New(webhook.Options{Provider: webhook.Stripe(secret, 5*time.Minute)}), d.Handle)
+
+	e := httptest.New(t, app)
+
+	body := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","data":{"object":{"amount":2000}}}`)
+	sign := func(ts time.Time) string {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		return "t=" + timestamp + ",v1=" + webhook.Sign("old", body) + ",v1=" + webhook.Sign(secret, []byte(timestamp+"."+string(body)))
+	}
+
+	e.POST("/webhooks/stripe").WithBytes(body).WithHeader("Stripe-Signature", sign(time.Now())).Expect().
+		Status(httptest.StatusOK)
+	// replay of a captured delivery.
+	e.POST("/webhooks/stripe").WithBytes(body).WithHeader("Stripe-Signature", sign(time.Now().Add(-time.Hour))).Expect().
+		Status(httptest.StatusUnauthorized)
+
+	if expected := 2000; amount != expected {
+		t.Fatalf("expected amount: %d but got: %d", expected, amount)
+	}
+}
+
+func TestWebhookSlack(t *testing.T) {
+	const secret = "slack secret"
+
+	body := []byte(`{"type":"event_callback","event_id":"Ev1","event":{"type":"app_mention"}}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	app := iris.New()
+	app.Post("/webhooks/slack", webhook.New(webhook.Options{Provider: webhook.Slack(secret, 5*time.Minute)}), func(ctx iris.Context) {
+		ev := webhook.Get(ctx)
+		ctx.WriteString(ev.ID + " " + ev.Type)
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/webhooks/slack").WithBytes(body).
+		WithHeader("X-Slack-Request-Timestamp", ts).
+		WithHeader("X-Slack-Signature", "v0="+webhook.Sign(secret, []byte("v0:"+ts+":"+string(body)))).Expect().
+		Status(httptest.StatusOK).Body().Equal("Ev1 app_mention")
+	e.POST("/webhooks/slack").WithBytes(body).
+		WithHeader("X-Slack-Request-Timestamp", ts).
+		WithHeader("X-Slack-Signature", "v0="+webhook.Sign(secret, body)).Expect().
+		Status(httptest.StatusUnauthorized)
+}
@@ -0,0 +1,68 @@
+package iris
+
+import (
+	"net/http"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+// MockContextOptions holds the optional settings of a Context
+// created by the `NewMockContext` function.
+type MockContextOptions struct {
+	// Application is the Application which the Context belongs to,
+	// its configuration, view engines and error handlers are used by the Context.
+	// If nil, then a new, built, Application is used instead.
+	Application *Application
+	// Params are the named path parameters of the request, see `Context.Params`.
+	Params map[string]string
+	// Session, if not nil, starts a session through an in-memory sessions manager
+	// and fills it with these values, see `sessions.Get`.
+	Session map[string]interface{}
+}
+
+// NewMockContext returns a new, fully working, Context based on the given
+// "w" response writer (e.g. an `httptest.ResponseRecorder`) and "r" request,
+// outside of a running Application. Useful to unit test handlers by calling them directly.
+// Call the `EndRequest` of the returned Context after the handler
+// to flush the status code and the headers to the "w".
+//
+// Usage:
+//  w := httptest.NewRecorder()
+//  r := httptest.NewRequest("GET", "/users/42", nil)
+//  ctx := iris.NewMockContext(w, r, iris.MockContextOptions{
+//      Params:  map[string]string{"id": "42"},
+//      Session: map[string]interface{}{"user": "kataras"},
+//  })
+//  getUser(ctx)
+//  ctx.EndRequest()
+func NewMockContext(w http.ResponseWriter, r *http.Request, options ...MockContextOptions) context.Context {
+	var opts MockContextOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	app := opts.Application
+	if app == nil {
+		app = New()
+		app.Logger().SetLevel("disable")
+		app.Build()
+	}
+
+	ctx := context.NewContext(app)
+	ctx.BeginRequest(w, r)
+
+	for key, value := range opts.Params {
+		ctx.Params().Set(key, value)
+	}
+
+	if opts.Session != nil {
+		sessions.New(sessions.Config{Cookie: "iris_mock_session"}).Handler()(ctx)
+		sess := sessions.Get(ctx)
+		for key, value := range opts.Session {
+			sess.Set(key, value)
+		}
+	}
+
+	return ctx
+}
@@ -261,6 +261,7 @@ func (c *ControllerActivator) activate() {
 	}
 
 	c.parseMethods()
+	c.warnMutableSingleton()
 }
 
 // register all available, exported methods to handlers if possible.
@@ -272,7 +273,16 @@ func (c *ControllerActivator) parseMethods() {
 	}
 }
 
+// notFoundMethodName is the name of the controller's method which,
+// if exists, handles the not found paths under the controller's Party.
+const notFoundMethodName = "NotFound"
+
 func (c *ControllerActivator) parseMethod(m reflect.Method) {
+	if m.Name == notFoundMethodName {
+		c.handleNotFound()
+		return
+	}
+
 	httpMethod, httpPath, err := parseMethod(c.app.Router.Macros(), m, c.isReservedMethod)
 	if err != nil {
 		if err != errSkip {
@@ -285,6 +295,16 @@ func (c *ControllerActivator) parseMethod(m reflect.Method) {
 	c.Handle(httpMethod, httpPath, m.Name)
 }
 
+// handleNotFound registers the controller's "NotFound" method to all HTTP methods
+// of a wildcard path, so it's fired when no other route of the controller's Party matches the request.
+// The response's status code is 404 unless the method returns a different one.
+func (c *ControllerActivator) handleNotFound() {
+	c.HandleMany("ANY", "/{notfound:path}", notFoundMethodName, func(ctx context.Context) {
+		ctx.NotFound()
+		ctx.Next()
+	})
+}
+
 func (c *ControllerActivator) addErr(err error) bool {
 	return c.app.Router.GetReporter().Err(err) != nil
 }
@@ -2,6 +2,12 @@
 package mvc_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/kataras/iris/v12"
@@ -624,3 +630,178 @@ func TestControllersInsideControllerDeep(t *testing.T) {
 	e := httptest.New(t, app)
 	e.GET("/something").Expect().Status(httptest.StatusOK).Body().Equal("foo bar")
 }
+
+type testControllerRequestFields struct {
+	Version int64  `param:"version"`
+	Tenant  string `query:"tenant"`
+	Limit   *int   `query:"limit"`
+}
+
+func (c *testControllerRequestFields) Get() string {
+	limit := -1
+	if c.Limit != nil {
+		limit = *c.Limit
+	}
+
+	return fmt.Sprintf("v%d:%s:%d", c.Version, c.Tenant, limit)
+}
+
+func TestControllerRequestFields(t *testing.T) {
+	app := iris.New()
+	New(app.Party("/api/{version:int64}")).Handle(new(testControllerRequestFields))
+
+	e := httptest.New(t, app)
+	e.GET("/api/2").WithQuery("tenant", "acme").WithQuery("limit", 10).Expect().
+		Status(httptest.StatusOK).Body().Equal("v2:acme:10")
+	e.GET("/api/3").Expect().Status(httptest.StatusOK).Body().Equal("v3::-1")
+	e.GET("/api/3").WithQuery("limit", "ten").Expect().Status(httptest.StatusBadRequest)
+}
+
+type testControllerLifetime struct {
+	Service *testServiceDoSomething
+	visits  int
+}
+
+func (c *testControllerLifetime) Get() string {
+	c.visits++
+	return fmt.Sprintf("%d", c.visits)
+}
+
+type testControllerLifetimeWithContext struct {
+	Ctx iris.Context
+}
+
+func (c *testControllerLifetimeWithContext) Get() {}
+
+func TestControllerLifetime(t *testing.T) {
+	app := iris.New()
+	m := New(app)
+	m.Register(new(testServiceDoSomething))
+	m.Party("/singleton").Handle(new(testControllerLifetime), Singleton)
+	m.Party("/per-request").Handle(new(testControllerLifetime), PerRequest)
+
+	e := httptest.New(t, app)
+	e.GET("/singleton").Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/singleton").Expect().Status(httptest.StatusOK).Body().Equal("2")
+	e.GET("/per-request").Expect().Status(httptest.StatusOK).Body().Equal("1")
+	e.GET("/per-request").Expect().Status(httptest.StatusOK).Body().Equal("1")
+
+	app = iris.New()
+	New(app).Handle(new(testControllerLifetimeWithContext), Singleton)
+	if err := app.Build(); err == nil {
+		t.Fatalf("expected an error for a Singleton controller with request-scoped fields")
+	}
+}
+
+type testControllerFallback struct {
+	Ctx iris.Context
+}
+
+func (c *testControllerFallback) Get() string {
+	return "index"
+}
+
+func (c *testControllerFallback) GetUsers() string {
+	return "users"
+}
+
+func (c *testControllerFallback) AnyPing() string {
+	return "pong:" + c.Ctx.Method()
+}
+
+func (c *testControllerFallback) NotFound() string {
+	return "not found: " + c.Ctx.Path()
+}
+
+func TestControllerFallbackMethods(t *testing.T) {
+	app := iris.New()
+	New(app.Party("/api")).Handle(new(testControllerFallback))
+	app.Get("/other", func(ctx iris.Context) {})
+
+	e := httptest.New(t, app)
+	e.GET("/api").Expect().Status(httptest.StatusOK).Body().Equal("index")
+	e.GET("/api/users").Expect().Status(httptest.StatusOK).Body().Equal("users")
+	e.DELETE("/api/ping").Expect().Status(httptest.StatusOK).Body().Equal("pong:DELETE")
+	e.PATCH("/api/ping").Expect().Status(httptest.StatusOK).Body().Equal("pong:PATCH")
+	e.GET("/api/missing").Expect().Status(httptest.StatusNotFound).Body().Equal("not found: /api/missing")
+	e.POST("/api/users/1").Expect().Status(httptest.StatusNotFound).Body().Equal("not found: /api/users/1")
+	// outside of the controller's Party.
+	e.GET("/missing").Expect().Status(httptest.StatusNotFound).Body().Equal("Not Found")
+}
+
+type testControllerRoutesService struct{}
+
+type testControllerRoutes struct {
+	Service *testControllerRoutesService
+}
+
+func (c *testControllerRoutes) Get() string {
+	return "index"
+}
+
+func (c *testControllerRoutes) GetBy(id int64) string {
+	return fmt.Sprintf("%d", id)
+}
+
+func TestControllerRoutes(t *testing.T) {
+	app := iris.New()
+	m := New(app.Party("/api"))
+	m.Register(new(testControllerRoutesService))
+	m.Party("/users").Handle(new(testControllerRoutes))
+
+	dir, err := ioutil.TempDir("", "mvc-routes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "routes.json")
+	m.ExportRoutes(filename)
+
+	expected := []RouteInfo{
+		{
+			Method:       "GET",
+			Path:         "/api/users",
+			Controller:   "mvc_test.testControllerRoutes",
+			Func:         "Get",
+			Dependencies: []string{"*mvc_test.testControllerRoutesService"},
+		},
+		{
+			Method:       "GET",
+			Path:         "/api/users/{param1:int64}",
+			Controller:   "mvc_test.testControllerRoutes",
+			Func:         "GetBy",
+			Dependencies: []string{"*mvc_test.testControllerRoutesService", "int64"},
+		},
+	}
+
+	routes := m.Routes()
+	if len(routes) != len(expected) {
+		t.Fatalf("expected %d routes but got %d: %#+v", len(expected), len(routes), routes)
+	}
+
+	for i, r := range routes {
+		r.Name = "" // generated.
+		if !reflect.DeepEqual(r, expected[i]) {
+			t.Fatalf("[%d] expected route:\n%#+v\nbut got:\n%#+v", i, expected[i], r)
+		}
+	}
+
+	if err = app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported []RouteInfo
+	if err = json.Unmarshal(b, &exported); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(exported, routes) {
+		t.Fatalf("expected exported routes to match:\n%#+v\nbut got:\n%#+v", routes, exported)
+	}
+}
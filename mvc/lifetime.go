@@ -0,0 +1,100 @@
+package mvc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kataras/iris/v12/core/logging"
+)
+
+// Lifetime is an `Option` which overrides the automatic detection
+// of a controller's lifetime, see `Singleton` and `PerRequest`.
+//
+// Usage: `.Handle(new(TodoController), mvc.PerRequest)`.
+type Lifetime uint8
+
+const (
+	// Singleton forces a single controller instance for all incoming requests.
+	// The controller should not have fields which depend on the request,
+	// e.g. an iris.Context field or a request-scoped dependency, otherwise an error is reported.
+	Singleton Lifetime = iota + 1
+	// PerRequest forces a new controller instance on each incoming request,
+	// even if all of its fields are static. Note that the new instance's
+	// unexported fields are not copied from the registered controller value.
+	PerRequest
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case Singleton:
+		return "Singleton"
+	case PerRequest:
+		return "PerRequest"
+	default:
+		return "Auto"
+	}
+}
+
+// Apply sets the controller's lifetime.
+func (l Lifetime) Apply(c *ControllerActivator) {
+	c.attachInjector()
+
+	switch l {
+	case Singleton:
+		var fields []string
+		for _, f := range c.injector.BoundFields() {
+			if !f.Static {
+				fields = append(fields, f.Name)
+			}
+		}
+
+		if len(fields) > 0 {
+			c.addErr(fmt.Errorf("MVC: controller '%s' cannot be a Singleton: field(s) %s depend on the request", c.fullName, strings.Join(fields, ", ")))
+			return
+		}
+
+		c.injector.Singleton = true
+	case PerRequest:
+		c.injector.Singleton = false
+	}
+}
+
+// warnMutableSingleton logs a warning if a singleton controller has exported fields
+// which are not bound to a dependency and they may be modified by its methods,
+// such a state is shared among all the concurrent requests.
+func (c *ControllerActivator) warnMutableSingleton() {
+	if c.injector == nil || !c.injector.Singleton {
+		return
+	}
+
+	bound := make(map[string]struct{})
+	for _, f := range c.injector.BoundFields() {
+		bound[f.Name] = struct{}{}
+	}
+
+	var fields []string
+	typ := indirectType(c.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+
+		if _, ok := bound[f.Name]; ok {
+			continue
+		}
+
+		switch f.Type.Kind() {
+		case reflect.Func, reflect.Chan, reflect.Interface:
+			continue
+		}
+
+		fields = append(fields, f.Name)
+	}
+
+	if len(fields) > 0 {
+		logging.Default().Warn("MVC: singleton controller has exported fields which are not bound to a dependency, they are shared among all requests, use the mvc.PerRequest option if they hold per-request state",
+			"controller", c.fullName, "fields", strings.Join(fields, ", "))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/router"
 	"github.com/kataras/iris/v12/hero"
 	"github.com/kataras/iris/v12/websocket"
@@ -29,6 +30,7 @@ type Application struct {
 	Router               router.Party
 	Controllers          []*ControllerActivator
 	websocketControllers []websocket.ConnHandler
+	children             []*Application
 }
 
 func newApp(subRouter router.Party, container *hero.Container) *Application {
@@ -99,9 +101,8 @@ func (app *Application) Register(dependencies ...interface{}) *Application {
 			allControllerNamesSoFar[i] = app.Controllers[i].Name()
 		}
 
-		golog.Warnf(`mvc.Application#Register called after mvc.Application#Handle.
-	The controllers[%s] may miss required dependencies.
-	Set the Logger's Level to "debug" to view the active dependencies per controller.`, strings.Join(allControllerNamesSoFar, ","))
+		logging.Default().Warn("MVC: Register called after Handle, the controllers may miss required dependencies, set the logger's level to \"debug\" to view the active dependencies per controller",
+			"controllers", strings.Join(allControllerNamesSoFar, ","))
 	}
 
 	for _, dependency := range dependencies {
@@ -255,6 +256,7 @@ func (app *Application) HandleError(handler func(ctx context.Context, err error)
 // Example: `.Clone(app.Party("/path")).Handle(new(TodoSubController))`.
 func (app *Application) Clone(party router.Party) *Application {
 	cloned := newApp(party, app.container.Clone())
+	app.children = append(app.children, cloned)
 	return cloned
 }
 
@@ -0,0 +1,106 @@
+package mvc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"sort"
+)
+
+// RouteInfo describes a single route registered by a controller's method.
+// See `Application.Routes` and `Application.ExportRoutes`.
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Name is the route's name, it can be used for reverse routing.
+	Name string `json:"name"`
+	// Controller is the full name of the controller's type, e.g. "main.UserController".
+	Controller string `json:"controller"`
+	// Func is the controller's method which handles the route.
+	Func string `json:"func"`
+	// Dependencies is a list of the types that the route depends on,
+	// the controller's bound fields first and then the method's input arguments.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// Routes returns the routes registered by the controllers of this mvc Application
+// and its children (see `Party` and `Clone`), sorted by path and method.
+// It can be used to audit the exposed API or to generate client scaffolding.
+func (app *Application) Routes() []RouteInfo {
+	var routes []RouteInfo
+
+	for _, c := range app.Controllers {
+		var fieldDeps []string
+		if c.injector != nil {
+			for _, f := range c.injector.BoundFields() {
+				fieldDeps = appendDependency(fieldDeps, f.Type)
+			}
+		}
+
+		for funcName, funcRoutes := range c.routes {
+			if len(funcRoutes) == 0 { // reserved method names.
+				continue
+			}
+
+			deps := append([]string(nil), fieldDeps...)
+			if m, ok := c.Type.MethodByName(funcName); ok {
+				for i := 1; i < m.Type.NumIn(); i++ { // skip the receiver.
+					deps = appendDependency(deps, m.Type.In(i))
+				}
+			}
+
+			for _, r := range funcRoutes {
+				routes = append(routes, RouteInfo{
+					Method:       r.Method,
+					Path:         r.Subdomain + r.Tmpl().Src,
+					Name:         r.Name,
+					Controller:   c.Name(),
+					Func:         funcName,
+					Dependencies: deps,
+				})
+			}
+		}
+	}
+
+	for _, child := range app.children {
+		routes = append(routes, child.Routes()...)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	return routes
+}
+
+func appendDependency(deps []string, typ reflect.Type) []string {
+	name := typ.String()
+	for _, dep := range deps {
+		if dep == name {
+			return deps
+		}
+	}
+
+	return append(deps, name)
+}
+
+// ExportRoutes writes the `Routes` of this mvc Application to the "filename" as indented JSON
+// when the Iris Application is being built, e.g. on `app.Build()` or `app.Listen`.
+// If the file cannot be written then the build fails.
+//
+// Example: `mvc.New(app.Party("/api")).ExportRoutes("./routes.json")`.
+func (app *Application) ExportRoutes(filename string) *Application {
+	app.Router.OnBuild(func() error {
+		b, err := json.MarshalIndent(app.Routes(), "", "    ")
+		if err != nil {
+			return err
+		}
+
+		return ioutil.WriteFile(filename, b, 0644)
+	})
+
+	return app
+}
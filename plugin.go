@@ -0,0 +1,122 @@
+package iris
+
+import (
+	stdContext "context"
+	"fmt"
+)
+
+// Plugin is a reusable bundle of features, i.e authentication, an admin panel or metrics,
+// which hooks into the lifecycle of an Application. Register plugins with `Application.RegisterPlugin`.
+type Plugin interface {
+	// Configure is called on registration, it can register routes, middleware,
+	// dependencies and event listeners to the "app".
+	Configure(app *Application)
+	// Build is called once, right before the application is built,
+	// a non-nil error fails the build.
+	Build(app *Application) error
+	// Shutdown is called on the application's shutdown, after the servers
+	// were shutdown, in the reverse order of the registration.
+	Shutdown(ctx stdContext.Context) error
+}
+
+// PluginNamer can be optionally implemented by a Plugin
+// to give a name to its errors, defaults to its type name.
+type PluginNamer interface {
+	Name() string
+}
+
+func pluginName(p Plugin) string {
+	if n, ok := p.(PluginNamer); ok {
+		return n.Name()
+	}
+
+	return fmt.Sprintf("%T", p)
+}
+
+// callPlugin calls the "fn" and converts its panic to an error,
+// a faulty plugin should not bring the application down.
+func callPlugin(p Plugin, stage string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%s: %s: panic: %v", pluginName(p), stage, v)
+		}
+	}()
+
+	if err = fn(); err != nil {
+		err = fmt.Errorf("%s: %s: %w", pluginName(p), stage, err)
+	}
+
+	return
+}
+
+// RegisterPlugin registers one or more plugins and calls their `Configure` method, in order.
+// Each plugin is registered as a dependency of the application's container too,
+// so handlers and controllers can accept it as an input argument.
+// Their `Build` methods are called, in the same order, on `Build`
+// and their `Shutdown` methods in the reverse order on `Shutdown`.
+// A plugin's error or panic does not stop the rest of the plugins,
+// the errors are reported on `Build`.
+func (app *Application) RegisterPlugin(plugins ...Plugin) *Application {
+	for _, p := range plugins {
+		if p == nil {
+			continue
+		}
+
+		app.mu.Lock()
+		app.plugins = append(app.plugins, p)
+		app.mu.Unlock()
+
+		app.ConfigureContainer().RegisterDependency(p)
+		if err := callPlugin(p, "configure", func() error {
+			p.Configure(app)
+			return nil
+		}); err != nil {
+			app.pluginErrors = append(app.pluginErrors, err)
+		}
+	}
+
+	return app
+}
+
+// Plugins returns the registered plugins, in order.
+func (app *Application) Plugins() []Plugin {
+	app.mu.Lock()
+	plugins := make([]Plugin, len(app.plugins))
+	copy(plugins, app.plugins)
+	app.mu.Unlock()
+
+	return plugins
+}
+
+// buildPlugins returns the registration errors and
+// the errors of the plugins' Build methods.
+func (app *Application) buildPlugins() []error {
+	errs := app.pluginErrors
+	for _, p := range app.Plugins() {
+		p := p
+		if err := callPlugin(p, "build", func() error { return p.Build(app) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// shutdownPlugins calls the plugins' Shutdown methods in the reverse order
+// and returns the first error.
+func (app *Application) shutdownPlugins(ctx stdContext.Context) error {
+	var firstErr error
+
+	plugins := app.Plugins()
+	for i := len(plugins) - 1; i >= 0; i-- {
+		p := plugins[i]
+		if err := callPlugin(p, "shutdown", func() error { return p.Shutdown(ctx) }); err != nil {
+			app.log.Debug("shutdown: plugin failed", "plugin", pluginName(p), "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,89 @@
+package iris
+
+import (
+	stdContext "context"
+	"errors"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testPlugin struct {
+	name    string
+	calls   *[]string
+	panicOn string
+}
+
+func (p *testPlugin) Name() string { return p.name }
+
+func (p *testPlugin) call(stage string) {
+	*p.calls = append(*p.calls, p.name+"."+stage)
+	if p.panicOn == stage {
+		panic("oops")
+	}
+}
+
+func (p *testPlugin) Configure(app *Application) {
+	p.call("configure")
+	app.ConfigureContainer().Get("/"+p.name, func(ctx Context, self *testPlugin) {
+		ctx.WriteString(self.name)
+	})
+}
+
+func (p *testPlugin) Build(app *Application) error {
+	p.call("build")
+	return nil
+}
+
+func (p *testPlugin) Shutdown(ctx stdContext.Context) error {
+	p.call("shutdown")
+	if p.name == "b" {
+		return errors.New("close failed")
+	}
+	return nil
+}
+
+func TestApplicationPlugins(t *testing.T) {
+	var calls []string
+
+	app := New()
+	app.RegisterPlugin(&testPlugin{name: "a", calls: &calls}, &testPlugin{name: "b", calls: &calls})
+
+	h := app.BuildHandler()
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := stdhttptest.NewRecorder()
+	h.ServeHTTP(w, stdhttptest.NewRequest(http.MethodGet, "/b", nil))
+	if expected, got := "b", w.Body.String(); expected != got {
+		t.Fatalf("expected the plugin dependency: %q but got: %q", expected, got)
+	}
+
+	err := app.Shutdown(stdContext.Background())
+	if err == nil || err.Error() != "b: shutdown: close failed" {
+		t.Fatalf("expected the shutdown error of the plugin but got: %v", err)
+	}
+
+	expected := []string{"a.configure", "b.configure", "a.build", "b.build", "b.shutdown", "a.shutdown"}
+	if strings.Join(calls, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected calls: %v but got: %v", expected, calls)
+	}
+}
+
+func TestApplicationPluginPanic(t *testing.T) {
+	var calls []string
+
+	app := New()
+	app.RegisterPlugin(&testPlugin{name: "faulty", calls: &calls, panicOn: "build"}, &testPlugin{name: "ok", calls: &calls})
+
+	err := app.Build()
+	if err == nil || !strings.Contains(err.Error(), "faulty: build: panic: oops") {
+		t.Fatalf("expected the plugin's panic as build error but got: %v", err)
+	}
+
+	if expected, got := "faulty.configure ok.configure faulty.build ok.build", strings.Join(calls, " "); expected != got {
+		t.Fatalf("expected calls: %s but got: %s", expected, got)
+	}
+}
@@ -0,0 +1,201 @@
+package iris
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kataras/iris/v12/config"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/host"
+
+	"github.com/kataras/golog"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a named set of curated defaults for an environment,
+// e.g. "development", "staging" or "production", see `WithProfile`.
+type Profile struct {
+	// LogLevel is the level of the application's logger, e.g. "debug" or "warn".
+	LogLevel string `json:"logLevel" yaml:"LogLevel"`
+	// The timeouts of the application's servers,
+	// they are set only to the servers which did not set their own ones.
+	ReadTimeout       time.Duration `json:"readTimeout" yaml:"ReadTimeout"`
+	ReadHeaderTimeout time.Duration `json:"readHeaderTimeout" yaml:"ReadHeaderTimeout"`
+	WriteTimeout      time.Duration `json:"writeTimeout" yaml:"WriteTimeout"`
+	IdleTimeout       time.Duration `json:"idleTimeout" yaml:"IdleTimeout"`
+	// Compression enables the gzip compression of the responses, for all routes.
+	Compression bool `json:"compression" yaml:"Compression"`
+	// DebugErrorPages enables the detailed error pages, see `Configuration.EnableDebugErrorPages`.
+	// Only the builtin "development" profile enables them.
+	DebugErrorPages bool `json:"debugErrorPages" yaml:"DebugErrorPages"`
+	// Configurators are any other settings of the profile, e.g. `WithOptimizations`.
+	Configurators []Configurator `json:"-" yaml:"-"`
+}
+
+// Profiles are the registered profiles by name, see `WithProfile`.
+// Custom profiles can be registered or the builtin ones can be modified before `WithProfile`.
+var Profiles = map[string]Profile{
+	"development": {
+		LogLevel:        "debug",
+		DebugErrorPages: true,
+	},
+	"staging": {
+		LogLevel:          "info",
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		Compression:       true,
+	},
+	"production": {
+		LogLevel:          "warn",
+		ReadTimeout:       30 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		Compression:       true,
+		Configurators:     []Configurator{WithOptimizations},
+	},
+}
+
+// WithProfile applies the curated defaults of the registered profile of the "name",
+// see `Profiles`, and then the optional "overrides" sources on top of them.
+// The sources are decoded to the `Configuration`, field names are matched case-insensitively,
+// and to the `Profile` through its "profile" section, e.g.
+//  app.Configure(iris.WithProfile("production",
+//      config.File("iris.production.yml"), config.Env("IRIS")))
+// where "IRIS_ENABLEROUTESTATS=true" enables the route statistics
+// and "IRIS_PROFILE__LOGLEVEL=info" sets the logger's level.
+//
+// The write timeout is not set by the builtin profiles as it would break the long-lived responses,
+// e.g. server-sent events. Use the `Application.ConfigDump` to see the effective configuration.
+//
+// It panics on unknown profile or on overrides failures.
+func WithProfile(name string, overrides ...config.Source) Configurator {
+	return func(app *Application) {
+		profile, ok := Profiles[name]
+		if !ok {
+			panic(fmt.Sprintf("iris: profile: %q is not registered", name))
+		}
+
+		var c *config.Config
+		if len(overrides) > 0 {
+			c = config.New(overrides...)
+			if err := c.Load(); err != nil {
+				panic(fmt.Errorf("iris: profile: %s: %w", name, err))
+			}
+
+			overrideProfile(c, &profile)
+		}
+
+		app.profile = name
+		app.profileSettings = profile
+
+		if profile.LogLevel != "" {
+			app.logger.SetLevel(profile.LogLevel)
+		}
+
+		app.config.EnableDebugErrorPages = profile.DebugErrorPages
+		if profile.Compression {
+			app.UseGlobal(context.Gzip)
+		}
+
+		app.ConfigureHost(func(su *host.Supervisor) {
+			srv := su.Server
+			if srv.ReadTimeout == 0 {
+				srv.ReadTimeout = profile.ReadTimeout
+			}
+			if srv.ReadHeaderTimeout == 0 {
+				srv.ReadHeaderTimeout = profile.ReadHeaderTimeout
+			}
+			if srv.WriteTimeout == 0 {
+				srv.WriteTimeout = profile.WriteTimeout
+			}
+			if srv.IdleTimeout == 0 {
+				srv.IdleTimeout = profile.IdleTimeout
+			}
+		})
+
+		app.Configure(profile.Configurators...)
+
+		// the configuration overrides come last, so they win the profile's settings.
+		if c != nil {
+			if err := c.Decode("", app.config); err != nil {
+				panic(fmt.Errorf("iris: profile: %s: %w", name, err))
+			}
+		}
+	}
+}
+
+// overrideProfile sets the "profile" section's values of the "c" to the "p".
+func overrideProfile(c *config.Config, p *Profile) {
+	if v := c.Get("profile.loglevel"); v.Exists() {
+		p.LogLevel = v.String()
+	}
+
+	for key, d := range map[string]*time.Duration{
+		"profile.readtimeout":       &p.ReadTimeout,
+		"profile.readheadertimeout": &p.ReadHeaderTimeout,
+		"profile.writetimeout":      &p.WriteTimeout,
+		"profile.idletimeout":       &p.IdleTimeout,
+	} {
+		if v := c.Get(key); v.Exists() {
+			*d = v.Duration()
+		}
+	}
+
+	if v := c.Get("profile.compression"); v.Exists() {
+		p.Compression = v.Bool()
+	}
+
+	if v := c.Get("profile.debugerrorpages"); v.Exists() {
+		p.DebugErrorPages = v.Bool()
+	}
+}
+
+// ConfigDump contains the effective configuration of an Application, see `Application.ConfigDump`.
+type ConfigDump struct {
+	// Profile is the name of the applied profile, if any.
+	Profile       string        `json:"profile,omitempty" yaml:"Profile,omitempty"`
+	Settings      *Profile      `json:"settings,omitempty" yaml:"Settings,omitempty"`
+	LogLevel      string        `json:"logLevel" yaml:"LogLevel"`
+	Configuration Configuration `json:"configuration" yaml:"Configuration"`
+}
+
+// ConfigDump returns the effective configuration of the application,
+// the applied profile, the logger's level and the `Configuration`.
+// See `ConfigDumpHandler` to serve it and `ConfigDump.String` to print it, e.g. from a command line flag.
+func (app *Application) ConfigDump() ConfigDump {
+	dump := ConfigDump{
+		Profile:       app.profile,
+		Configuration: *app.config,
+	}
+
+	if meta, ok := golog.Levels[app.logger.Level]; ok {
+		dump.LogLevel = meta.Name
+	}
+
+	if app.profile != "" {
+		settings := app.profileSettings
+		dump.Settings = &settings
+	}
+
+	return dump
+}
+
+// ConfigDumpHandler returns a handler which writes the `ConfigDump` as JSON,
+// e.g. `app.Get("/debug/config", app.ConfigDumpHandler())`.
+// The configuration may contain sensitive values, protect that route.
+func (app *Application) ConfigDumpHandler() context.Handler {
+	return func(ctx context.Context) {
+		ctx.JSON(app.ConfigDump())
+	}
+}
+
+// String returns the YAML representation of the dump.
+func (d ConfigDump) String() string {
+	b, err := yaml.Marshal(d)
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
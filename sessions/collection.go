@@ -0,0 +1,162 @@
+package sessions
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Collection is a typed, ordered, list of entries stored in a session,
+// suitable for shopping carts, wishlists and e.t.c.
+// Each entry is stored under its own session key and the order of the entries under the collection's key,
+// so a change rewrites only the changed entry (and the order when an entry is added or removed)
+// to the session database, not the whole collection.
+//
+// Use the `Session.Collection` to create one.
+type Collection struct {
+	sess *Session
+	key  string
+	typ  reflect.Type
+}
+
+// Collection returns the collection stored under the "key".
+// The "elemPrototype" is a value of the entries' type, i.e CartItem{},
+// the values of `Range` and `Get` are always of this type,
+// even if the session database returns them as generic maps.
+//
+// Example:
+//  cart := sessions.Get(ctx).Collection("cart", CartItem{})
+//  cart.Add(productID, CartItem{Quantity: 1})
+//  cart.Range(func(id string, v interface{}) bool {
+//      item := v.(CartItem)
+//      return true
+//  })
+func (s *Session) Collection(key string, elemPrototype interface{}) *Collection {
+	if elemPrototype == nil {
+		panic("sessions: collection: nil element prototype")
+	}
+
+	return &Collection{
+		sess: s,
+		key:  key,
+		typ:  reflect.TypeOf(elemPrototype),
+	}
+}
+
+func (c *Collection) entryKey(id string) string {
+	return c.key + "." + id
+}
+
+// IDs returns the identifiers of the entries, in the order they were added.
+func (c *Collection) IDs() []string {
+	var ids []string
+	if !convertTo(c.sess.Get(c.key), reflect.ValueOf(&ids).Elem()) {
+		return nil
+	}
+
+	// copy, the memory database returns the stored slice itself.
+	return append([]string(nil), ids...)
+}
+
+// Len returns the number of the entries.
+func (c *Collection) Len() int {
+	return len(c.IDs())
+}
+
+// Has reports whether an entry of the "id" exists.
+func (c *Collection) Has(id string) bool {
+	return indexOf(c.IDs(), id) != -1
+}
+
+// Add inserts the "value" entry of the "id" or replaces the existing one.
+// It panics if the "value" is not of the collection's type.
+func (c *Collection) Add(id string, value interface{}) {
+	if typ := reflect.TypeOf(value); typ != c.typ {
+		panic(fmt.Sprintf("sessions: collection: %s: expected value of type %s but got %v", c.key, c.typ, typ))
+	}
+
+	ids := c.IDs()
+	if indexOf(ids, id) == -1 {
+		c.sess.Set(c.key, append(ids, id))
+	}
+
+	c.sess.Set(c.entryKey(id), value)
+}
+
+// Get binds the entry of the "id" to the "outPtr", a pointer to a value of the collection's type.
+// It reports false if the entry does not exist.
+func (c *Collection) Get(id string, outPtr interface{}) bool {
+	out := reflect.ValueOf(outPtr)
+	if out.Kind() != reflect.Ptr || out.Elem().Type() != c.typ {
+		panic(fmt.Sprintf("sessions: collection: %s: expected a pointer to %s but got %T", c.key, c.typ, outPtr))
+	}
+
+	return convertTo(c.sess.Get(c.entryKey(id)), out.Elem())
+}
+
+// Remove deletes the entry of the "id", it reports whether it existed.
+func (c *Collection) Remove(id string) bool {
+	ids := c.IDs()
+	idx := indexOf(ids, id)
+	if idx == -1 {
+		return false
+	}
+
+	c.sess.Set(c.key, append(ids[:idx], ids[idx+1:]...))
+	c.sess.Delete(c.entryKey(id))
+	return true
+}
+
+// Range calls the "fn" for each entry, in order, until "fn" returns false.
+// The "value" is of the collection's type.
+func (c *Collection) Range(fn func(id string, value interface{}) bool) {
+	for _, id := range c.IDs() {
+		v := reflect.New(c.typ).Elem()
+		if !convertTo(c.sess.Get(c.entryKey(id)), v) {
+			continue
+		}
+
+		if !fn(id, v.Interface()) {
+			return
+		}
+	}
+}
+
+// Clear removes all the entries.
+func (c *Collection) Clear() {
+	for _, id := range c.IDs() {
+		c.sess.Delete(c.entryKey(id))
+	}
+
+	c.sess.Delete(c.key)
+}
+
+func indexOf(ids []string, id string) int {
+	for i, s := range ids {
+		if s == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// convertTo sets the "value" to the "out", the databases which decode the values
+// to generic types (e.g. maps) are converted through the `DefaultTranscoder`.
+func convertTo(value interface{}, out reflect.Value) bool {
+	if value == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(out.Type()) {
+		out.Set(v)
+		return true
+	}
+
+	b, err := DefaultTranscoder.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	return DefaultTranscoder.Unmarshal(b, out.Addr().Interface()) == nil
+}
@@ -4,8 +4,7 @@ import (
 	"time"
 
 	"github.com/kataras/iris/v12/context"
-
-	uuid "github.com/iris-contrib/go.uuid"
+	"github.com/kataras/iris/v12/core/logging"
 )
 
 const (
@@ -105,16 +104,54 @@ type (
 		// Defaults to infinitive/unlimited life duration(0).
 		Expires time.Duration
 
+		// MaxSessions is the maximum number of sessions kept in the server-side memory,
+		// when it's exceeded the least recently used sessions are evicted.
+		// The evicted sessions are destroyed, unless a session database is registered
+		// which loads them again on their next request.
+		//
+		// Defaults to zero, unlimited.
+		MaxSessions int
+
+		// GCInterval is the interval of a full scan of the in-memory sessions
+		// for the expired ones, which are destroyed in batches by a single background sweeper
+		// instead of a timer per session. An expired session is never served,
+		// it is destroyed on its next request even if the sweeper did not reach it yet.
+		// A negative value disables the sweeper.
+		//
+		// Defaults to `DefaultGCInterval`, one minute.
+		GCInterval time.Duration
+
 		// SessionIDGenerator can be set to a function which
 		// return a unique session id.
 		// By default we will use a uuid impl package to generate
-		// that, but developers can change that with simple assignment.
+		// that, but developers can change that with simple assignment
+		// or use one of the builtin generators, i.e
+		// `RandomIDGenerator(length, alphabet)` and `KSUIDGenerator()`.
+		//
+		// Defaults to `UUIDGenerator()`.
 		SessionIDGenerator func(ctx context.Context) string
 
+		// DisallowExternalIDs set it to true in order to reject the client-provided session IDs
+		// which were not created by the server, i.e. not found in memory or in the registered database,
+		// a new session with a new ID is started instead.
+		// It protects against session fixation, where an attacker sets a known session ID to the victim's browser.
+		// Note that, with a database, the sessions without values are not considered as created,
+		// their ID is regenerated after a server restart.
+		// See `Session.RegenerateID` too.
+		//
+		// Defaults to false.
+		DisallowExternalIDs bool
+
 		// DisableSubdomainPersistence set it to true in order dissallow your subdomains to have access to the session cookie
 		//
 		// Defaults to false.
 		DisableSubdomainPersistence bool
+
+		// Logger is the structured logger of the registered database, if it logs its errors,
+		// see `DatabaseLogger`.
+		//
+		// Defaults to the logger of the Application which serves the first session.
+		Logger logging.Logger
 	}
 )
 
@@ -125,10 +162,11 @@ func (c Config) Validate() Config {
 	}
 
 	if c.SessionIDGenerator == nil {
-		c.SessionIDGenerator = func(context.Context) string {
-			id, _ := uuid.NewV4()
-			return id.String()
-		}
+		c.SessionIDGenerator = UUIDGenerator()
+	}
+
+	if c.GCInterval == 0 {
+		c.GCInterval = DefaultGCInterval
 	}
 
 	if c.Encoding != nil {
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/core/memstore"
 )
 
@@ -52,6 +53,12 @@ type Database interface {
 	Release(sid string)
 }
 
+// DatabaseLogger is implemented by the databases which log their errors,
+// the Sessions manager passes its `Config.Logger` to them, see `UseDatabase`.
+type DatabaseLogger interface {
+	SetLogger(logger logging.Logger)
+}
+
 type mem struct {
 	values map[string]*memstore.Store
 	mu     sync.RWMutex
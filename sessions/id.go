@@ -0,0 +1,116 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	uuid "github.com/iris-contrib/go.uuid"
+)
+
+// The session ID generators, see `Config.SessionIDGenerator`.
+// All of them read from a cryptographically secure source,
+// they panic if it fails instead of generating predictable IDs.
+
+// UUIDGenerator returns a session ID generator of random (version 4) UUIDs, the default one.
+func UUIDGenerator() func(ctx context.Context) string {
+	return func(context.Context) string {
+		id, err := uuid.NewV4()
+		if err != nil {
+			panic("sessions: generate id: " + err.Error())
+		}
+
+		return id.String()
+	}
+}
+
+const (
+	// DefaultIDLength is the length of the `RandomIDGenerator`'s IDs when a non-positive one is given.
+	DefaultIDLength = 32
+	// DefaultIDAlphabet is the alphabet of the `RandomIDGenerator`'s IDs when an empty one is given,
+	// it's URL and cookie safe.
+	DefaultIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// RandomIDGenerator returns a session ID generator of "length" random characters of the "alphabet",
+// the characters are uniformly distributed.
+// Prefer at least 128 bits of entropy, e.g. 22 characters of a 64 characters alphabet.
+//
+// It panics if the alphabet has less than 2 or more than 256 characters.
+func RandomIDGenerator(length int, alphabet string) func(ctx context.Context) string {
+	if length <= 0 {
+		length = DefaultIDLength
+	}
+
+	if alphabet == "" {
+		alphabet = DefaultIDAlphabet
+	}
+
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		panic("sessions: random id generator: the alphabet should have 2 to 256 characters")
+	}
+
+	// the random bytes are masked to the alphabet's next power of two
+	// and the ones out of it are dropped, so no character is more likely than the others.
+	mask := 1
+	for mask < len(alphabet) {
+		mask <<= 1
+	}
+	mask--
+
+	return func(context.Context) string {
+		id := make([]byte, 0, length)
+		buf := make([]byte, length+length/2)
+
+		for {
+			if _, err := rand.Read(buf); err != nil {
+				panic("sessions: generate id: " + err.Error())
+			}
+
+			for _, b := range buf {
+				if idx := int(b) & mask; idx < len(alphabet) {
+					id = append(id, alphabet[idx])
+					if len(id) == length {
+						return string(id)
+					}
+				}
+			}
+		}
+	}
+}
+
+const (
+	ksuidEpoch    = 1400000000
+	ksuidLength   = 27
+	ksuidAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// KSUIDGenerator returns a session ID generator of K-Sortable Unique IDentifiers,
+// 27 characters of a 32-bit timestamp and 128 random bits, base62-encoded,
+// which sort by their creation time, e.g. for database indexes.
+func KSUIDGenerator() func(ctx context.Context) string {
+	return func(context.Context) string {
+		var b [20]byte
+		binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()-ksuidEpoch))
+		if _, err := rand.Read(b[4:]); err != nil {
+			panic("sessions: generate id: " + err.Error())
+		}
+
+		var (
+			n    = new(big.Int).SetBytes(b[:])
+			base = big.NewInt(int64(len(ksuidAlphabet)))
+			mod  = new(big.Int)
+			id   = make([]byte, ksuidLength)
+		)
+
+		for i := ksuidLength - 1; i >= 0; i-- {
+			n.DivMod(n, base, mod)
+			id[i] = ksuidAlphabet[mod.Int64()]
+		}
+
+		return string(id)
+	}
+}
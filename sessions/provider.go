@@ -1,31 +1,79 @@
 package sessions
 
 import (
+	"container/list"
 	"errors"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// providerShards is the number of the provider's shards.
+const providerShards = 32
+
 type (
 	// provider contains the sessions and external databases (load and update).
-	// It's the session memory manager
+	// It's the session memory manager.
+	// The sessions are sharded by their ID's hash to reduce the lock contention under load.
 	provider struct {
-		// we don't use RWMutex because all actions have read and write at the same action function.
-		// (or write to a *Session's value which is race if we don't lock)
-		// narrow locks are fasters but are useless here.
+		live        int64  // atomic, first for 64-bit alignment.
+		evictions   uint64 // atomic.
+		shards      []*providerShard
+		maxSessions int
+
+		gcInterval time.Duration
+		gcRunning  int32 // atomic, 1 when the expired sessions' sweeper is running.
+
 		mu               sync.Mutex
-		sessions         map[string]*Session
 		db               Database
 		destroyListeners []DestroyListener
 	}
+
+	// providerShard holds a part of the sessions, the most recently used first.
+	providerShard struct {
+		// we don't use RWMutex because all actions have read and write at the same action function.
+		// (or write to a *Session's value which is race if we don't lock)
+		// narrow locks are fasters but are useless here.
+		mu       sync.Mutex
+		sessions map[string]*list.Element
+		lru      *list.List
+	}
 )
 
-// newProvider returns a new sessions provider
-func newProvider() *provider {
-	return &provider{
-		sessions: make(map[string]*Session),
-		db:       newMemDB(),
+// newProvider returns a new sessions provider of "shards" shards
+// which keeps up to "maxSessions" in memory, zero means unlimited.
+func newProvider(shards, maxSessions int) *provider {
+	p := &provider{
+		shards:      make([]*providerShard, shards),
+		maxSessions: maxSessions,
+		gcInterval:  DefaultGCInterval,
+		db:          newMemDB(),
+	}
+
+	for i := range p.shards {
+		p.shards[i] = &providerShard{
+			sessions: make(map[string]*list.Element),
+			lru:      list.New(),
+		}
+	}
+
+	return p
+}
+
+// shardIndex returns the shard index of the "sid", using the FNV-1a hash.
+func (p *provider) shardIndex(sid string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(sid); i++ {
+		h ^= uint32(sid[i])
+		h *= 16777619
 	}
+
+	return int(h % uint32(len(p.shards)))
+}
+
+func (p *provider) shard(sid string) *providerShard {
+	return p.shards[p.shardIndex(sid)]
 }
 
 // RegisterDatabase sets a session database.
@@ -37,25 +85,17 @@ func (p *provider) RegisterDatabase(db Database) {
 
 // newSession returns a new session from sessionid
 func (p *provider) newSession(man *Sessions, sid string, expires time.Duration) *Session {
-	onExpire := func() {
-		p.Destroy(sid)
-	}
-
 	lifetime := p.db.Acquire(sid, expires)
 
-	// simple and straight:
-	if !lifetime.IsZero() {
-		// if stored time is not zero
-		// start a timer based on the stored time, if not expired.
-		lifetime.Revive(onExpire)
-	} else {
-		// Remember:  if db not exist or it has been expired
-		// then the stored time will be zero(see loadSessionFromDB) and the values will be empty.
-		//
-		// Even if the database has an unlimited session (possible by a previous app run)
-		// priority to the "expires" is given,
-		// again if <=0 then it does nothing.
-		lifetime.Begin(expires, onExpire)
+	// No timers here, the expired sessions are destroyed by the provider's sweeper (see `gc`).
+	// Remember:  if db not exist or it has been expired
+	// then the stored time will be zero(see loadSessionFromDB) and the values will be empty.
+	//
+	// Even if the database has an unlimited session (possible by a previous app run)
+	// priority to the "expires" is given,
+	// again if <=0 then it does nothing.
+	if lifetime.IsZero() && expires > 0 {
+		lifetime.Time = time.Now().Add(expires)
 	}
 
 	sess := &Session{
@@ -72,18 +112,67 @@ func (p *provider) newSession(man *Sessions, sid string, expires time.Duration)
 // Init creates the session  and returns it
 func (p *provider) Init(man *Sessions, sid string, expires time.Duration) *Session {
 	newSession := p.newSession(man, sid, expires)
-	p.mu.Lock()
-	p.sessions[sid] = newSession
-	p.mu.Unlock()
+
+	idx := p.shardIndex(sid)
+	shard := p.shards[idx]
+	shard.mu.Lock()
+	if elem, found := shard.sessions[sid]; found {
+		elem.Value = newSession
+		shard.lru.MoveToFront(elem)
+	} else {
+		shard.sessions[sid] = shard.lru.PushFront(newSession)
+		atomic.AddInt64(&p.live, 1)
+	}
+	shard.mu.Unlock()
+
+	if p.maxSessions > 0 && atomic.LoadInt64(&p.live) > int64(p.maxSessions) {
+		p.evict(idx, sid)
+	}
+
+	if !newSession.Lifetime.IsZero() {
+		p.startGC()
+	}
+
 	return newSession
 }
 
+// evict removes the least recently used session of the shard of the "idx" index,
+// or of the next shards if that one holds only the "keep" session.
+// The evicted sessions are destroyed only when the sessions are stored in memory,
+// a database keeps them and they are loaded again on their next request.
+func (p *provider) evict(idx int, keep string) {
+	for i := 0; i < len(p.shards); i++ {
+		shard := p.shards[(idx+i)%len(p.shards)]
+
+		shard.mu.Lock()
+		elem := shard.lru.Back()
+		if elem == nil || elem.Value.(*Session).sid == keep {
+			shard.mu.Unlock()
+			continue
+		}
+
+		sess := shard.lru.Remove(elem).(*Session)
+		delete(shard.sessions, sess.sid)
+		shard.mu.Unlock()
+
+		atomic.AddInt64(&p.live, -1)
+		atomic.AddUint64(&p.evictions, 1)
+
+		if _, inMemory := p.db.(*mem); inMemory {
+			p.releaseSession(sess.sid)
+		}
+
+		return
+	}
+}
+
 // ErrNotFound may be returned from `UpdateExpiration` of a non-existing or
 // invalid session entry from memory storage or databases.
 // Usage:
-// if err != nil && err.Is(err, sessions.ErrNotFound) {
-//     [handle error...]
-// }
+//
+//	if err != nil && err.Is(err, sessions.ErrNotFound) {
+//	    [handle error...]
+//	}
 var ErrNotFound = errors.New("session not found")
 
 // UpdateExpiration resets the expiration of a session.
@@ -99,31 +188,119 @@ func (p *provider) UpdateExpiration(sid string, expires time.Duration) error {
 		return nil
 	}
 
-	p.mu.Lock()
-	sess, found := p.sessions[sid]
-	p.mu.Unlock()
+	shard := p.shard(sid)
+	shard.mu.Lock()
+	elem, found := shard.sessions[sid]
+	if found {
+		// under lock, the sweeper reads it.
+		sess := elem.Value.(*Session)
+		sess.mu.Lock()
+		sess.Lifetime.Time = time.Now().Add(expires)
+		sess.mu.Unlock()
+	}
+	shard.mu.Unlock()
 	if !found {
 		return ErrNotFound
 	}
 
-	sess.Lifetime.Shift(expires)
 	return p.db.OnUpdateExpiration(sid, expires)
 }
 
 // Read returns the store which sid parameter belongs
 func (p *provider) Read(man *Sessions, sid string, expires time.Duration) *Session {
-	p.mu.Lock()
-	if sess, found := p.sessions[sid]; found {
+	shard := p.shard(sid)
+	shard.mu.Lock()
+	if elem, found := shard.sessions[sid]; found {
+		sess := elem.Value.(*Session)
+		if sess.Lifetime.HasExpired() {
+			// not swept yet.
+			shard.mu.Unlock()
+			p.Destroy(sid)
+			return p.Init(man, sid, expires)
+		}
+
+		shard.lru.MoveToFront(elem)
 		sess.runFlashGC() // run the flash messages GC, new request here of existing session
-		p.mu.Unlock()
+		shard.mu.Unlock()
 
 		return sess
 	}
-	p.mu.Unlock()
+	shard.mu.Unlock()
 
 	return p.Init(man, sid, expires) // if not found create new
 }
 
+// Has reports whether the session of the "sid" was created by the server,
+// it's in memory or, if a database is registered, it has values in the database.
+func (p *provider) Has(sid string) bool {
+	shard := p.shard(sid)
+	shard.mu.Lock()
+	_, found := shard.sessions[sid]
+	shard.mu.Unlock()
+
+	if found {
+		return true
+	}
+
+	if _, inMemory := p.db.(*mem); inMemory {
+		return false
+	}
+
+	return p.db.Len(sid) > 0
+}
+
+// Regenerate moves the "sess" session to the "sid" ID, its values are copied
+// and the old ID is released from the database. The destroy listeners are not fired,
+// the session itself is not destroyed.
+func (p *provider) Regenerate(sess *Session, sid string) {
+	sess.mu.RLock()
+	oldSID, oldLifetime := sess.sid, sess.Lifetime
+	sess.mu.RUnlock()
+
+	var expires time.Duration
+	if !oldLifetime.IsZero() {
+		expires = time.Until(oldLifetime.Time)
+	}
+
+	lifetime := p.db.Acquire(sid, expires)
+	if lifetime.IsZero() {
+		lifetime = oldLifetime
+	}
+
+	// collect them first, a database may not allow writes while visiting.
+	var (
+		keys   []string
+		values []interface{}
+	)
+	p.db.Visit(oldSID, func(key string, value interface{}) {
+		keys = append(keys, key)
+		values = append(values, value)
+	})
+
+	for i, key := range keys {
+		p.db.Set(sid, lifetime, key, values[i], false)
+	}
+
+	removed := p.remove(oldSID)
+	p.db.Release(oldSID)
+
+	// it's not in the shards now, the concurrent requests of the same session read them under its lock.
+	sess.mu.Lock()
+	sess.sid = sid
+	sess.Lifetime = lifetime
+	sess.mu.Unlock()
+
+	shard := p.shard(sid)
+	shard.mu.Lock()
+	shard.sessions[sid] = shard.lru.PushFront(sess)
+	shard.mu.Unlock()
+	atomic.AddInt64(&p.live, 1)
+
+	if !removed && p.maxSessions > 0 && atomic.LoadInt64(&p.live) > int64(p.maxSessions) {
+		p.evict(p.shardIndex(sid), sid)
+	}
+}
+
 func (p *provider) registerDestroyListener(ln DestroyListener) {
 	if ln == nil {
 		return
@@ -141,28 +318,135 @@ func (p *provider) fireDestroy(sid string) {
 // the session itself and updates the registered session databases,
 // this called from sessionManager which removes the client's cookie also.
 func (p *provider) Destroy(sid string) {
-	p.mu.Lock()
-	if sess, found := p.sessions[sid]; found {
-		p.deleteSession(sess)
+	if p.remove(sid) {
+		p.releaseSession(sid)
 	}
-	p.mu.Unlock()
 }
 
 // DestroyAll removes all sessions
 // from the server-side memory (and database if registered).
 // Client's session cookie will still exist but it will be reseted on the next request.
 func (p *provider) DestroyAll() {
-	p.mu.Lock()
-	for _, sess := range p.sessions {
-		p.deleteSession(sess)
+	for _, shard := range p.shards {
+		shard.mu.Lock()
+		sids := make([]string, 0, len(shard.sessions))
+		for sid := range shard.sessions {
+			sids = append(sids, sid)
+		}
+		shard.mu.Unlock()
+
+		for _, sid := range sids {
+			p.Destroy(sid)
+		}
 	}
-	p.mu.Unlock()
 }
 
-func (p *provider) deleteSession(sess *Session) {
-	sid := sess.sid
+// Len returns the number of the sessions in memory.
+func (p *provider) Len() int {
+	return int(atomic.LoadInt64(&p.live))
+}
+
+// Evictions returns the number of the sessions evicted from memory.
+func (p *provider) Evictions() uint64 {
+	return atomic.LoadUint64(&p.evictions)
+}
+
+// remove removes the session of the "sid" from memory, it reports whether it was found.
+func (p *provider) remove(sid string) bool {
+	shard := p.shard(sid)
+	shard.mu.Lock()
+	elem, found := shard.sessions[sid]
+	if found {
+		shard.lru.Remove(elem)
+		delete(shard.sessions, sid)
+	}
+	shard.mu.Unlock()
+
+	if found {
+		atomic.AddInt64(&p.live, -1)
+	}
 
-	delete(p.sessions, sid)
+	return found
+}
+
+func (p *provider) releaseSession(sid string) {
 	p.db.Release(sid)
 	p.fireDestroy(sid)
 }
+
+func (p *provider) deleteSession(sess *Session) {
+	p.remove(sess.sid)
+	p.releaseSession(sess.sid)
+}
+
+// DefaultGCInterval is the default interval of a full scan
+// of the in-memory sessions for the expired ones, see `Config.GCInterval`.
+const DefaultGCInterval = time.Minute
+
+// startGC starts the expired sessions' sweeper, if not already running.
+func (p *provider) startGC() {
+	if p.gcInterval <= 0 || !atomic.CompareAndSwapInt32(&p.gcRunning, 0, 1) {
+		return
+	}
+
+	go p.gc()
+}
+
+// gc is the single background sweeper of the expired sessions, instead of a timer per session.
+// It scans one shard per step (incremental scanning), so all shards are scanned once per `gcInterval`,
+// and the steps are jittered (±10%) so the sweepers of different instances do not hit their databases at the same time.
+// It stops when no sessions are left in memory and it's started again by the next session with expiration.
+func (p *provider) gc() {
+	step := p.gcInterval / time.Duration(len(p.shards))
+	if step <= 0 {
+		step = p.gcInterval
+	}
+
+	for i := 0; ; i = (i + 1) % len(p.shards) {
+		time.Sleep(jitter(step))
+
+		p.sweep(p.shards[i], time.Now())
+
+		if atomic.LoadInt64(&p.live) == 0 {
+			atomic.StoreInt32(&p.gcRunning, 0)
+			// a session may be added between the check and the store.
+			if atomic.LoadInt64(&p.live) == 0 || !atomic.CompareAndSwapInt32(&p.gcRunning, 0, 1) {
+				return
+			}
+		}
+	}
+}
+
+// sweep removes the expired sessions of the "shard" in a batch, under a single lock,
+// and then releases them.
+func (p *provider) sweep(shard *providerShard, now time.Time) {
+	var expired []string
+
+	shard.mu.Lock()
+	for sid, elem := range shard.sessions {
+		if lifetime := elem.Value.(*Session).Lifetime; !lifetime.IsZero() && lifetime.Before(now) {
+			shard.lru.Remove(elem)
+			delete(shard.sessions, sid)
+			expired = append(expired, sid)
+		}
+	}
+	shard.mu.Unlock()
+
+	if len(expired) == 0 {
+		return
+	}
+
+	atomic.AddInt64(&p.live, -int64(len(expired)))
+	for _, sid := range expired {
+		p.releaseSession(sid)
+	}
+}
+
+// jitter returns the "d" ±10%.
+func jitter(d time.Duration) time.Duration {
+	if n := int64(d) / 5; n > 0 {
+		return d - d/10 + time.Duration(rand.Int63n(n))
+	}
+
+	return d
+}
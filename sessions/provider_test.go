@@ -0,0 +1,158 @@
+package sessions
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProviderEviction(t *testing.T) {
+	p := newProvider(1, 2)
+
+	var destroyed []string
+	p.registerDestroyListener(func(sid string) {
+		destroyed = append(destroyed, sid)
+	})
+
+	p.Init(nil, "a", 0)
+	p.Init(nil, "b", 0)
+	p.Read(nil, "a", 0) // "b" is the least recently used now.
+	p.Init(nil, "c", 0)
+
+	if expected, got := 2, p.Len(); expected != got {
+		t.Fatalf("expected %d live sessions but got %d", expected, got)
+	}
+
+	if expected, got := uint64(1), p.Evictions(); expected != got {
+		t.Fatalf("expected %d evictions but got %d", expected, got)
+	}
+
+	if len(destroyed) != 1 || destroyed[0] != "b" {
+		t.Fatalf("expected the session 'b' to be evicted but got: %v", destroyed)
+	}
+
+	p = newProvider(providerShards, 10)
+	for i := 0; i < 100; i++ {
+		p.Init(nil, strconv.Itoa(i), 0)
+	}
+
+	if expected, got := 10, p.Len(); expected != got {
+		t.Fatalf("expected %d live sessions but got %d", expected, got)
+	}
+
+	if expected, got := uint64(90), p.Evictions(); expected != got {
+		t.Fatalf("expected %d evictions but got %d", expected, got)
+	}
+
+	p.DestroyAll()
+	if got := p.Len(); got != 0 {
+		t.Fatalf("expected no live sessions but got %d", got)
+	}
+}
+
+func TestProviderGC(t *testing.T) {
+	p := newProvider(4, 0)
+	p.gcInterval = 40 * time.Millisecond
+
+	var (
+		mu        sync.Mutex
+		destroyed []string
+	)
+	p.registerDestroyListener(func(sid string) {
+		mu.Lock()
+		destroyed = append(destroyed, sid)
+		mu.Unlock()
+	})
+
+	p.Init(nil, "short", 10*time.Millisecond)
+	p.Init(nil, "long", time.Hour)
+	p.Init(nil, "unlimited", 0)
+
+	for deadline := time.Now().Add(2 * time.Second); p.Len() != 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the expired session to be swept but got %d live sessions", p.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	if len(destroyed) != 1 || destroyed[0] != "short" {
+		t.Fatalf("expected the session 'short' to be destroyed but got: %v", destroyed)
+	}
+	mu.Unlock()
+
+	// expired but not swept yet, it's not served.
+	p.gcInterval = time.Hour
+	sess := p.Init(nil, "again", time.Hour)
+	sess.Set("key", "value")
+	p.UpdateExpiration("again", time.Hour)
+	p.shard("again").mu.Lock()
+	p.shard("again").sessions["again"].Value.(*Session).Lifetime.Time = time.Now().Add(-time.Second)
+	p.shard("again").mu.Unlock()
+
+	if sess = p.Read(nil, "again", time.Hour); sess.Get("key") != nil || sess.Lifetime.HasExpired() {
+		t.Fatalf("expected a new session in place of the expired one")
+	}
+}
+
+// go test -run=^$ -bench=ProviderRead -cpu=8 ./sessions
+func BenchmarkProviderRead(b *testing.B) {
+	sids := make([]string, 1024)
+	for i := range sids {
+		sids[i] = strconv.Itoa(i)
+	}
+
+	for _, shards := range []int{1, providerShards} {
+		b.Run("shards="+strconv.Itoa(shards), func(b *testing.B) {
+			p := newProvider(shards, 0)
+			for _, sid := range sids {
+				p.Init(nil, sid, 0)
+			}
+
+			var n uint32
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(atomic.AddUint32(&n, 1))
+				for pb.Next() {
+					p.Read(nil, sids[i%len(sids)], 0)
+					i++
+				}
+			})
+		})
+	}
+}
+
+func TestProviderRegenerateConcurrently(t *testing.T) {
+	p := newProvider(providerShards, 0)
+	sess := p.Init(nil, "0", time.Hour)
+	sess.Set("user", "kataras")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sess.ID()
+				sess.Set("visits", j)
+				p.UpdateExpiration(sess.ID(), time.Hour)
+			}
+		}()
+	}
+
+	for i := 1; i <= 100; i++ {
+		p.Regenerate(sess, strconv.Itoa(i))
+	}
+	wg.Wait()
+
+	if expected, got := "100", sess.ID(); expected != got {
+		t.Fatalf("expected the session ID %q but got %q", expected, got)
+	}
+
+	if expected, got := "kataras", sess.GetString("user"); expected != got {
+		t.Fatalf("expected the value %q to be kept but got %q", expected, got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/memstore"
 )
 
@@ -18,7 +19,7 @@ type (
 		sid     string
 		isNew   bool
 		flashes map[string]*flashMessage
-		mu      sync.RWMutex // for flashes.
+		mu      sync.RWMutex // for flashes, the ID and the Lifetime.
 		// Lifetime it contains the expiration data, use it for read-only information.
 		// See `Sessions.UpdateExpiration` too.
 		Lifetime LifeTime
@@ -47,9 +48,20 @@ func (s *Session) Destroy() {
 	s.provider.deleteSession(s)
 }
 
+// RegenerateID replaces the session's ID with a new one and sets the new session cookie,
+// the session's values and flash messages are kept and the old ID is no longer valid.
+// Call it on privilege changes, e.g. right after a login or a logout,
+// so a session ID which was known before (session fixation) cannot be used after.
+func (s *Session) RegenerateID(ctx context.Context, cookieOptions ...context.CookieOption) {
+	s.Man.RegenerateID(ctx, s, cookieOptions...)
+}
+
 // ID returns the session's ID.
 func (s *Session) ID() string {
-	return s.sid
+	s.mu.RLock()
+	sid := s.sid
+	s.mu.RUnlock()
+	return sid
 }
 
 // IsNew returns true if this session is
@@ -60,7 +72,7 @@ func (s *Session) IsNew() bool {
 
 // Get returns a value based on its "key".
 func (s *Session) Get(key string) interface{} {
-	return s.provider.db.Get(s.sid, key)
+	return s.provider.db.Get(s.ID(), key)
 }
 
 // when running on the session manager removes any 'old' flash messages.
@@ -437,8 +449,8 @@ func (s *Session) GetBooleanDefault(key string, defaultValue bool) bool {
 
 // GetAll returns a copy of all session's values.
 func (s *Session) GetAll() map[string]interface{} {
-	items := make(map[string]interface{}, s.provider.db.Len(s.sid))
 	s.mu.RLock()
+	items := make(map[string]interface{}, s.provider.db.Len(s.sid))
 	s.provider.db.Visit(s.sid, func(key string, value interface{}) {
 		items[key] = value
 	})
@@ -461,16 +473,20 @@ func (s *Session) GetFlashes() map[string]interface{} {
 
 // Visit loops each of the entries and calls the callback function func(key, value).
 func (s *Session) Visit(cb func(k string, v interface{})) {
-	s.provider.db.Visit(s.sid, cb)
+	s.provider.db.Visit(s.ID(), cb)
 }
 
 // Len returns the total number of stored values in this session.
 func (s *Session) Len() int {
-	return s.provider.db.Len(s.sid)
+	return s.provider.db.Len(s.ID())
 }
 
 func (s *Session) set(key string, value interface{}, immutable bool) {
-	s.provider.db.Set(s.sid, s.Lifetime, key, value, immutable)
+	s.mu.RLock()
+	sid, lifetime := s.sid, s.Lifetime
+	s.mu.RUnlock()
+
+	s.provider.db.Set(sid, lifetime, key, value, immutable)
 
 	s.mu.Lock()
 	s.isNew = false
@@ -521,7 +537,7 @@ func (s *Session) SetFlash(key string, value interface{}) {
 // Delete removes an entry by its key,
 // returns true if actually something was removed.
 func (s *Session) Delete(key string) bool {
-	removed := s.provider.db.Delete(s.sid, key)
+	removed := s.provider.db.Delete(s.ID(), key)
 	if removed {
 		s.mu.Lock()
 		s.isNew = false
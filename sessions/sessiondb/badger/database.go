@@ -8,10 +8,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/sessions"
 
 	"github.com/dgraph-io/badger"
-	"github.com/kataras/golog"
 )
 
 /*
@@ -35,6 +35,8 @@ type Database struct {
 	Service *badger.DB
 
 	closed uint32 // if 1 is closed.
+
+	logger logging.Var
 }
 
 var _ sessions.Database = (*Database)(nil)
@@ -63,7 +65,6 @@ func New(directoryPath string) (*Database, error) {
 
 	service, err := badger.Open(opts)
 	if err != nil {
-		golog.Errorf("unable to initialize the badger-based session database: %v", err)
 		return nil, err
 	}
 
@@ -78,6 +79,14 @@ func NewFromDB(service *badger.DB) *Database {
 	return db
 }
 
+// SetLogger sets the logger of the database's errors,
+// the sessions manager sets it on `UseDatabase`, see `sessions.DatabaseLogger`.
+//
+// Defaults to the `logging.Default()`.
+func (db *Database) SetLogger(logger logging.Logger) {
+	db.logger.Set(logger)
+}
+
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
@@ -100,7 +109,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime
 	}
 
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: badger", "error", err)
 	}
 
 	return sessions.LifeTime{} // session manager will handle the rest.
@@ -127,7 +136,7 @@ func makeKey(sid, key string) []byte {
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
 	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: badger", "error", err)
 		return
 	}
 
@@ -137,7 +146,7 @@ func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, valu
 	})
 
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: badger", "error", err)
 	}
 }
 
@@ -155,7 +164,7 @@ func (db *Database) Get(sid string, key string) (value interface{}) {
 	})
 
 	if err != nil && err != badger.ErrKeyNotFound {
-		golog.Error(err)
+		db.logger.Error("sessions: badger", "error", err)
 		return nil
 	}
 
@@ -178,12 +187,12 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 
 		// err := item.Value(func(valueBytes []byte) {
 		// 	if err := sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
-		// 		golog.Error(err)
+		// 		db.logger.Error("sessions: badger", "error", err)
 		// 	}
 		// })
 
 		// if err != nil {
-		// 	golog.Error(err)
+		// 	db.logger.Error("sessions: badger", "error", err)
 		// 	continue
 		// }
 
@@ -191,7 +200,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 			return sessions.DefaultTranscoder.Unmarshal(valueBytes, &value)
 		})
 		if err != nil {
-			golog.Error(err)
+			db.logger.Error("sessions: badger", "error", err)
 			continue
 		}
 
@@ -227,7 +236,7 @@ func (db *Database) Delete(sid string, key string) (deleted bool) {
 	txn := db.Service.NewTransaction(true)
 	err := txn.Delete(makeKey(sid, key))
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: badger", "error", err)
 		return false
 	}
 	return txn.Commit() == nil
@@ -246,7 +255,7 @@ func (db *Database) Clear(sid string) {
 	for iter.Rewind(); iter.ValidForPrefix(prefix); iter.Next() {
 		key := iter.Item().Key()
 		if err := txn.Delete(key); err != nil {
-			golog.Warnf("Database.Clear: %s: %v", key, err)
+			db.logger.Warn("sessions: badger: clear failed", "key", key, "error", err)
 			continue
 		}
 	}
@@ -260,10 +269,10 @@ func (db *Database) Release(sid string) {
 	// and remove the $sid.
 	txn := db.Service.NewTransaction(true)
 	if err := txn.Delete([]byte(sid)); err != nil {
-		golog.Warnf("Database.Release.Delete: %s: %v", sid, err)
+		db.logger.Warn("sessions: badger: release failed", "sid", sid, "error", err)
 	}
 	if err := txn.Commit(); err != nil {
-		golog.Debugf("Database.Release.Commit: %s: %v", sid, err)
+		db.logger.Debug("sessions: badger: release commit failed", "sid", sid, "error", err)
 	}
 }
 
@@ -278,7 +287,7 @@ func closeDB(db *Database) error {
 	}
 	err := db.Service.Close()
 	if err != nil {
-		golog.Warnf("closing the badger connection: %v", err)
+		db.logger.Warn("sessions: badger: close failed", "error", err)
 	} else {
 		atomic.StoreUint32(&db.closed, 1)
 	}
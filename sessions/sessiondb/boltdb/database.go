@@ -7,9 +7,9 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/sessions"
 
-	"github.com/kataras/golog"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -27,6 +27,8 @@ type Database struct {
 	// it's initialized at `New` or `NewFromDB`.
 	// Can be used to get stats.
 	Service *bolt.DB
+
+	logger logging.Var
 }
 
 var errPathMissing = errors.New("path is required")
@@ -38,7 +40,6 @@ var errPathMissing = errors.New("path is required")
 // It will remove any old session files.
 func New(path string, fileMode os.FileMode) (*Database, error) {
 	if path == "" {
-		golog.Error(errPathMissing)
 		return nil, errPathMissing
 	}
 
@@ -48,7 +49,6 @@ func New(path string, fileMode os.FileMode) (*Database, error) {
 
 	// create directories if necessary
 	if err := os.MkdirAll(filepath.Dir(path), fileMode); err != nil {
-		golog.Errorf("error while trying to create the necessary directories for %s: %v", path, err)
 		return nil, err
 	}
 
@@ -56,7 +56,6 @@ func New(path string, fileMode os.FileMode) (*Database, error) {
 		&bolt.Options{Timeout: 20 * time.Second},
 	)
 	if err != nil {
-		golog.Errorf("unable to initialize the BoltDB-based session database: %v", err)
 		return nil, err
 	}
 
@@ -91,7 +90,7 @@ func (db *Database) getBucketForSession(tx *bolt.Tx, sid string) *bolt.Bucket {
 	if b == nil {
 		// session does not exist, it shouldn't happen, session bucket creation happens once at `Acquire`,
 		// no need to accept the `bolt.bucket.CreateBucketIfNotExists`'s performance cost.
-		golog.Debugf("unreachable session access for '%s'", sid)
+		db.logger.Debug("sessions: boltdb: unreachable session access", "sid", sid)
 	}
 
 	return b
@@ -122,20 +121,20 @@ func (db *Database) cleanup() error {
 			if bExp := b.Bucket(expirationName); bExp != nil { // has expiration.
 				_, expValue := bExp.Cursor().First() // the expiration bucket contains only one key(we don't care, see `Acquire`) value(time.Time) pair.
 				if expValue == nil {
-					golog.Debugf("cleanup: expiration is there but its value is empty '%s'", v) // should never happen.
+					db.logger.Debug("sessions: boltdb: cleanup: empty expiration value", "sid", v) // should never happen.
 					continue
 				}
 
 				var expirationTime time.Time
 				if err := sessions.DefaultTranscoder.Unmarshal(expValue, &expirationTime); err != nil {
-					golog.Debugf("cleanup: unable to retrieve expiration value for '%s'", v)
+					db.logger.Debug("sessions: boltdb: cleanup: retrieve expiration failed", "sid", v)
 					continue
 				}
 
 				if expirationTime.Before(time.Now()) {
 					// expired, delete the expiration bucket.
 					if err := b.DeleteBucket(expirationName); err != nil {
-						golog.Debugf("cleanup: unable to destroy a session '%s'", bsid)
+						db.logger.Debug("sessions: boltdb: cleanup: destroy failed", "sid", bsid)
 						return err
 					}
 
@@ -151,6 +150,14 @@ func (db *Database) cleanup() error {
 
 var expirationKey = []byte("exp") // it can be random.
 
+// SetLogger sets the logger of the database's errors,
+// the sessions manager sets it on `UseDatabase`, see `sessions.DatabaseLogger`.
+//
+// Defaults to the `logging.Default()`.
+func (db *Database) SetLogger(logger logging.Logger) {
+	db.logger.Set(logger)
+}
+
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) (lifetime sessions.LifeTime) {
@@ -166,14 +173,14 @@ func (db *Database) Acquire(sid string, expires time.Duration) (lifetime session
 				// don't return a lifetime, let it empty, session manager will do its job.
 				b, err = root.CreateBucket(name)
 				if err != nil {
-					golog.Debugf("unable to create a session bucket for '%s': %v", sid, err)
+					db.logger.Debug("sessions: boltdb: create bucket failed", "sid", sid, "error", err)
 					return err
 				}
 
 				expirationTime := time.Now().Add(expires)
 				timeBytes, err := sessions.DefaultTranscoder.Marshal(expirationTime)
 				if err != nil {
-					golog.Debugf("unable to set an expiration value on session expiration bucket for '%s': %v", sid, err)
+					db.logger.Debug("sessions: boltdb: set expiration failed", "sid", sid, "error", err)
 					return err
 				}
 
@@ -194,7 +201,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) (lifetime session
 
 			var expirationTime time.Time
 			if err = sessions.DefaultTranscoder.Unmarshal(expValue, &expirationTime); err != nil {
-				golog.Debugf("acquire: unable to retrieve expiration value for '%s', value was: '%s': %v", sid, expValue, err)
+				db.logger.Debug("sessions: boltdb: acquire: retrieve expiration failed", "sid", sid, "value", expValue, "error", err)
 				return
 			}
 
@@ -207,7 +214,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) (lifetime session
 		return
 	})
 	if err != nil {
-		golog.Debugf("unable to acquire session '%s': %v", sid, err)
+		db.logger.Debug("sessions: boltdb: acquire failed", "sid", sid, "error", err)
 		return sessions.LifeTime{}
 	}
 
@@ -235,7 +242,7 @@ func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) err
 	})
 
 	if err != nil {
-		golog.Debugf("unable to reset the expiration value for '%s': %v", sid, err)
+		db.logger.Debug("sessions: boltdb: reset expiration failed", "sid", sid, "error", err)
 	}
 
 	return err
@@ -250,7 +257,7 @@ func makeKey(key string) []byte {
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
 	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
 	if err != nil {
-		golog.Debug(err)
+		db.logger.Debug("sessions: boltdb", "error", err)
 		return
 	}
 
@@ -268,7 +275,7 @@ func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, valu
 	})
 
 	if err != nil {
-		golog.Debug(err)
+		db.logger.Debug("sessions: boltdb", "error", err)
 	}
 }
 
@@ -288,7 +295,7 @@ func (db *Database) Get(sid string, key string) (value interface{}) {
 		return sessions.DefaultTranscoder.Unmarshal(valueBytes, &value)
 	})
 	if err != nil {
-		golog.Debugf("session '%s' key '%s' not found", sid, key)
+		db.logger.Debug("sessions: boltdb: key not found", "sid", sid, "key", key)
 	}
 
 	return
@@ -305,7 +312,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 		return b.ForEach(func(k []byte, v []byte) error {
 			var value interface{}
 			if err := sessions.DefaultTranscoder.Unmarshal(v, &value); err != nil {
-				golog.Debugf("unable to retrieve value of key '%s' of '%s': %v", k, sid, err)
+				db.logger.Debug("sessions: boltdb: retrieve value failed", "sid", sid, "key", k, "error", err)
 				return err
 			}
 
@@ -315,7 +322,7 @@ func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
 	})
 
 	if err != nil {
-		golog.Debugf("Database.Visit: %s: %v", sid, err)
+		db.logger.Debug("sessions: boltdb: visit failed", "sid", sid, "error", err)
 	}
 }
 
@@ -332,7 +339,7 @@ func (db *Database) Len(sid string) (n int) {
 	})
 
 	if err != nil {
-		golog.Debugf("Database.Len: %s: %v", sid, err)
+		db.logger.Debug("sessions: boltdb: len failed", "sid", sid, "error", err)
 	}
 
 	return
@@ -366,7 +373,7 @@ func (db *Database) Clear(sid string) {
 	})
 
 	if err != nil {
-		golog.Debugf("Database.Clear: %s: %v", sid, err)
+		db.logger.Debug("sessions: boltdb: clear failed", "sid", sid, "error", err)
 	}
 }
 
@@ -384,7 +391,7 @@ func (db *Database) Release(sid string) {
 	})
 
 	if err != nil {
-		golog.Debugf("Database.Release: %s: %v", sid, err)
+		db.logger.Debug("sessions: boltdb: release failed", "sid", sid, "error", err)
 	}
 }
 
@@ -396,7 +403,7 @@ func (db *Database) Close() error {
 func closeDB(db *Database) error {
 	err := db.Service.Close()
 	if err != nil {
-		golog.Warnf("closing the BoltDB connection: %v", err)
+		db.logger.Warn("sessions: boltdb: close failed", "error", err)
 	}
 
 	return err
@@ -0,0 +1,510 @@
+// Package file provides a file-based session storage,
+// a file per session, written atomically and sharded in two levels of directories.
+// A session is stored on its first value.
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/core/logging"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+// SyncPolicy controls when the session files are flushed to the disk, see `Config.Sync`.
+type SyncPolicy uint8
+
+const (
+	// SyncNone leaves the flush to the operating system, the fastest one.
+	// A power failure may lose the latest writes but a session file is never half-written.
+	SyncNone SyncPolicy = iota
+	// SyncFile flushes a session file before it replaces the old one.
+	SyncFile
+	// SyncFileAndDir flushes a session file and its directory entry, the most durable one.
+	SyncFileAndDir
+)
+
+const (
+	// DefaultFileMode is the default `Config.FileMode` of the session files.
+	DefaultFileMode os.FileMode = 0600
+	// DefaultDirMode is the default `Config.DirMode` of the session directories.
+	DefaultDirMode os.FileMode = 0700
+	// DefaultGCInterval is the default `Config.GCInterval`.
+	DefaultGCInterval = 30 * time.Minute
+
+	fileExt      = ".json"
+	tempPrefix   = ".tmp-"
+	corruptedExt = ".corrupted"
+	locks        = 64
+	// the age of the temporary files which are considered as leftovers by the periodic cleanup,
+	// the younger ones may be written right now.
+	tempMaxAge = time.Minute
+)
+
+// Config is the configuration of the file-based session storage.
+type Config struct {
+	// Directory is the root directory of the session files, required.
+	// The files are sharded by the first two pairs of their hashed session ID,
+	// i.e. "<Directory>/ab/cd/abcd...json", so no directory holds too many files.
+	Directory string
+	// FileMode is the mode of the session files.
+	// Defaults to `DefaultFileMode`.
+	FileMode os.FileMode
+	// DirMode is the mode of the session directories.
+	// Defaults to `DefaultDirMode`.
+	DirMode os.FileMode
+	// Sync is the flush (fsync) policy of the session files.
+	// Defaults to `SyncNone`.
+	Sync SyncPolicy
+	// GCInterval is the interval of the removal of the expired session files,
+	// they are removed on `New` too. A negative value disables the periodic removal.
+	// Defaults to `DefaultGCInterval`.
+	GCInterval time.Duration
+}
+
+// Database the file-based session storage.
+// Each write goes to a temporary file which replaces the session's file (rename),
+// so a crash never leaves a half-written session behind.
+// The files which cannot be decoded are moved aside (".corrupted") and the session starts empty.
+type Database struct {
+	config Config
+	locks  [locks]sync.Mutex
+
+	logger logging.Var
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ sessions.Database = (*Database)(nil)
+
+var errDirectoryMissing = errors.New("directory is required")
+
+// New creates and returns a new file-based storage instance of the "cfg".
+// It removes the expired session files and any leftover temporary files
+// and it starts their periodic removal, see `Close`.
+func New(cfg Config) (*Database, error) {
+	if cfg.Directory == "" {
+		return nil, errDirectoryMissing
+	}
+
+	if cfg.FileMode == 0 {
+		cfg.FileMode = DefaultFileMode
+	}
+
+	if cfg.DirMode == 0 {
+		cfg.DirMode = DefaultDirMode
+	}
+
+	if err := os.MkdirAll(cfg.Directory, cfg.DirMode); err != nil {
+		return nil, err
+	}
+
+	if cfg.GCInterval == 0 {
+		cfg.GCInterval = DefaultGCInterval
+	}
+
+	db := &Database{config: cfg, stop: make(chan struct{})}
+	if err := db.cleanup(time.Now()); err != nil {
+		return nil, err
+	}
+
+	if cfg.GCInterval > 0 {
+		go db.gc()
+	}
+
+	return db, nil
+}
+
+// gc removes the expired session files every `Config.GCInterval`, until `Close`.
+func (db *Database) gc() {
+	ticker := time.NewTicker(db.config.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			if err := db.cleanup(time.Now().Add(-tempMaxAge)); err != nil {
+				db.logger.Warn("sessions: file: cleanup failed", "error", err)
+			}
+		}
+	}
+}
+
+// entry is the contents of a session file.
+type entry struct {
+	SID     string            `json:"sid"`
+	Expires time.Time         `json:"expires,omitempty"`
+	Values  map[string][]byte `json:"values"` // transcoded by the `sessions.DefaultTranscoder`.
+}
+
+func (e *entry) expired() bool {
+	return !e.Expires.IsZero() && e.Expires.Before(time.Now())
+}
+
+func hash(sid string) string {
+	h := sha256.Sum256([]byte(sid))
+	return hex.EncodeToString(h[:])
+}
+
+// filename returns the session file's path, the session ID is hashed
+// so a client-provided one cannot point outside of the directory.
+func (db *Database) filename(sid string) string {
+	name := hash(sid)
+	return filepath.Join(db.config.Directory, name[0:2], name[2:4], name+fileExt)
+}
+
+func (db *Database) lock(sid string) *sync.Mutex {
+	h := uint32(2166136261)
+	for i := 0; i < len(sid); i++ {
+		h ^= uint32(sid[i])
+		h *= 16777619
+	}
+
+	return &db.locks[h%locks]
+}
+
+// read returns the session's entry, nil if it does not exist or it's corrupted.
+func (db *Database) read(sid string) *entry {
+	filename := db.filename(sid)
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			db.logger.Debug("sessions: file: read failed", "sid", sid, "error", err)
+		}
+		return nil
+	}
+
+	e := new(entry)
+	if err = json.Unmarshal(b, e); err != nil || e.SID != sid {
+		db.quarantine(filename, err)
+		return nil
+	}
+
+	if e.Values == nil {
+		e.Values = make(map[string][]byte)
+	}
+
+	return e
+}
+
+// quarantine moves a corrupted session file aside, so it can be inspected, and the session starts empty.
+func (db *Database) quarantine(filename string, err error) {
+	db.logger.Warn("sessions: file: corrupted session file", "file", filename, "error", err)
+	if err = os.Rename(filename, filename+corruptedExt); err != nil {
+		os.Remove(filename)
+	}
+}
+
+// write replaces the session's file atomically.
+func (db *Database) write(e *entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	filename := db.filename(e.SID)
+	dir := filepath.Dir(filename)
+	if err = os.MkdirAll(dir, db.config.DirMode); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, tempPrefix)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	_, err = f.Write(b)
+	if err == nil && db.config.Sync >= SyncFile {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, db.config.FileMode)
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if db.config.Sync >= SyncFileAndDir {
+		return syncDir(dir)
+	}
+
+	return nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// update calls the "fn" with the existing session's entry and writes it.
+func (db *Database) update(sid string, fn func(e *entry)) error {
+	mu := db.lock(sid)
+	mu.Lock()
+	defer mu.Unlock()
+
+	e := db.read(sid)
+	if e == nil {
+		return sessions.ErrNotFound
+	}
+
+	fn(e)
+	return db.write(e)
+}
+
+// cleanup removes the expired session files and the temporary files modified before the "tempBefore"
+// and moves aside the corrupted ones.
+func (db *Database) cleanup(tempBefore time.Time) error {
+	return filepath.Walk(db.config.Directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if strings.HasPrefix(name, tempPrefix) {
+			if info.ModTime().Before(tempBefore) {
+				return ignoreNotExist(os.Remove(path))
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(name, fileExt) {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return ignoreNotExist(err) // removed after the walk started.
+		}
+
+		e := new(entry)
+		if err = json.Unmarshal(b, e); err != nil {
+			db.quarantine(path, err)
+			return nil
+		}
+
+		if !e.expired() {
+			return nil
+		}
+
+		// check again under the session's lock, its expiration may be updated right now.
+		mu := db.lock(e.SID)
+		mu.Lock()
+		defer mu.Unlock()
+
+		if e = db.read(e.SID); e != nil && e.expired() {
+			return ignoreNotExist(os.Remove(path))
+		}
+
+		return nil
+	})
+}
+
+func ignoreNotExist(err error) error {
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// SetLogger sets the logger of the database's errors,
+// the sessions manager sets it on `UseDatabase`, see `sessions.DatabaseLogger`.
+//
+// Defaults to the `logging.Default()`.
+func (db *Database) SetLogger(logger logging.Logger) {
+	db.logger.Set(logger)
+}
+
+// Acquire receives a session's lifetime from the database,
+// if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
+// A new session is not stored until its first value, see `Set`.
+func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	mu := db.lock(sid)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if e := db.read(sid); e != nil {
+		if !e.expired() {
+			return sessions.LifeTime{Time: e.Expires}
+		}
+
+		os.Remove(db.filename(sid))
+	}
+
+	return sessions.LifeTime{}
+}
+
+// OnUpdateExpiration will re-set the session file's expiration.
+// It does nothing if the session is not stored yet.
+func (db *Database) OnUpdateExpiration(sid string, newExpires time.Duration) error {
+	err := db.update(sid, func(e *entry) {
+		e.Expires = time.Now().Add(newExpires)
+	})
+	if err == sessions.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		db.logger.Debug("sessions: file: reset expiration failed", "sid", sid, "error", err)
+	}
+
+	return err
+}
+
+// Set sets a key value of a specific session.
+// The session is stored on its first value, with the "lifetime".
+// Ignore the "immutable".
+func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
+	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
+	if err != nil {
+		db.logger.Debug("sessions: file", "error", err)
+		return
+	}
+
+	mu := db.lock(sid)
+	mu.Lock()
+	defer mu.Unlock()
+
+	e := db.read(sid)
+	if e == nil {
+		e = &entry{SID: sid, Expires: lifetime.Time, Values: make(map[string][]byte)}
+	}
+
+	e.Values[key] = valueBytes
+	if err = db.write(e); err != nil {
+		db.logger.Debug("sessions: file: set failed", "sid", sid, "key", key, "error", err)
+	}
+}
+
+// Get retrieves a session value based on the key.
+func (db *Database) Get(sid string, key string) (value interface{}) {
+	mu := db.lock(sid)
+	mu.Lock()
+	e := db.read(sid)
+	mu.Unlock()
+
+	if e == nil {
+		return nil
+	}
+
+	valueBytes, ok := e.Values[key]
+	if !ok {
+		return nil
+	}
+
+	if err := sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
+		db.logger.Debug("sessions: file: retrieve value failed", "sid", sid, "key", key, "error", err)
+	}
+
+	return
+}
+
+// Visit loops through all session keys and values.
+func (db *Database) Visit(sid string, cb func(key string, value interface{})) {
+	mu := db.lock(sid)
+	mu.Lock()
+	e := db.read(sid)
+	mu.Unlock()
+
+	if e == nil {
+		return
+	}
+
+	for key, valueBytes := range e.Values {
+		var value interface{}
+		if err := sessions.DefaultTranscoder.Unmarshal(valueBytes, &value); err != nil {
+			db.logger.Debug("sessions: file: retrieve value failed", "sid", sid, "key", key, "error", err)
+			continue
+		}
+
+		cb(key, value)
+	}
+}
+
+// Len returns the length of the session's entries (keys).
+func (db *Database) Len(sid string) int {
+	mu := db.lock(sid)
+	mu.Lock()
+	e := db.read(sid)
+	mu.Unlock()
+
+	if e == nil {
+		return 0
+	}
+
+	return len(e.Values)
+}
+
+// Delete removes a session key value based on its key.
+func (db *Database) Delete(sid string, key string) (deleted bool) {
+	err := db.update(sid, func(e *entry) {
+		_, deleted = e.Values[key]
+		delete(e.Values, key)
+	})
+
+	return err == nil && deleted
+}
+
+// Clear removes all session key values but it keeps the session entry.
+func (db *Database) Clear(sid string) {
+	err := db.update(sid, func(e *entry) {
+		e.Values = make(map[string][]byte)
+	})
+	if err != nil {
+		db.logger.Debug("sessions: file: clear failed", "sid", sid, "error", err)
+	}
+}
+
+// Release destroys the session, it clears and removes the session entry,
+// session manager will create a new session ID on the next request after this call.
+func (db *Database) Release(sid string) {
+	mu := db.lock(sid)
+	mu.Lock()
+	err := os.Remove(db.filename(sid))
+	mu.Unlock()
+
+	if err != nil && !os.IsNotExist(err) {
+		db.logger.Debug("sessions: file: release failed", "sid", sid, "error", err)
+	}
+}
+
+// Close stops the periodic removal of the expired session files,
+// the files are closed after each operation.
+func (db *Database) Close() error {
+	db.closeOnce.Do(func() {
+		close(db.stop)
+	})
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12/core/logging"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func TestDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-sessions-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(Config{Directory: dir, Sync: SyncFileAndDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	sid := "../../session-id"
+	if lifetime := db.Acquire(sid, time.Hour); !lifetime.IsZero() {
+		t.Fatalf("expected a zero lifetime of a new session but got: %s", lifetime)
+	}
+
+	// not stored until its first value.
+	if _, err = os.Stat(db.filename(sid)); !os.IsNotExist(err) {
+		t.Fatalf("expected no session file before the first value: %v", err)
+	}
+
+	lifetime := sessions.LifeTime{Time: time.Now().Add(time.Hour)}
+	db.Set(sid, lifetime, "name", "kataras", false)
+	db.Set(sid, lifetime, "age", 27, false)
+
+	if expected, got := "kataras", db.Get(sid, "name"); expected != got {
+		t.Fatalf("expected: %v but got: %v", expected, got)
+	}
+
+	if expected, got := 2, db.Len(sid); expected != got {
+		t.Fatalf("expected %d keys but got %d", expected, got)
+	}
+
+	// sharded by the hashed session ID, inside the directory.
+	filename := db.filename(sid)
+	if rel, _ := filepath.Rel(dir, filename); strings.Count(rel, string(filepath.Separator)) != 2 || strings.Contains(rel, "..") {
+		t.Fatalf("unexpected session file: %s", rel)
+	}
+
+	// loaded again, i.e. after a restart.
+	if lifetime := db.Acquire(sid, time.Hour); lifetime.IsZero() || lifetime.HasExpired() {
+		t.Fatalf("expected the stored lifetime but got: %s", lifetime)
+	}
+
+	if !db.Delete(sid, "age") || db.Delete(sid, "age") {
+		t.Fatal("expected the key to be deleted once")
+	}
+
+	// the errors are logged through the logger of the sessions manager.
+	var records []logging.Record
+	sessions.New(sessions.Config{Logger: logging.New(logging.SinkFunc(func(r logging.Record) {
+		records = append(records, r)
+	}), nil)}).UseDatabase(db)
+
+	// corruption recovery.
+	if err = ioutil.WriteFile(filename, []byte(`{"sid":`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := db.Get(sid, "name"); got != nil {
+		t.Fatalf("expected no value of a corrupted session but got: %v", got)
+	}
+
+	if _, err = os.Stat(filename + corruptedExt); err != nil {
+		t.Fatalf("expected the corrupted file to be moved aside: %v", err)
+	}
+
+	if len(records) != 1 || records[0].Level != logging.WarnLevel {
+		t.Fatalf("expected a warning of the corrupted file but got: %v", records)
+	}
+
+	db.Set(sid, lifetime, "name", "kataras", false)
+	db.Release(sid)
+	if _, err = os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected the session file to be removed: %v", err)
+	}
+
+	// expired sessions and leftover temporary files are removed on start.
+	db.Set("expired", sessions.LifeTime{Time: time.Now().Add(time.Millisecond)}, "name", "kataras", false)
+	tmp := filepath.Join(filepath.Dir(db.filename("expired")), tempPrefix+"leftover")
+	if err = ioutil.WriteFile(tmp, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if db, err = New(Config{Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	for _, name := range []string{db.filename("expired"), tmp} {
+		if _, err = os.Stat(name); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed: %v", name, err)
+		}
+	}
+}
+
+func TestDatabaseGC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-sessions-file-gc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(Config{Directory: dir, GCInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.Set("expired", sessions.LifeTime{Time: time.Now().Add(time.Millisecond)}, "name", "kataras", false)
+	db.Set("alive", sessions.LifeTime{Time: time.Now().Add(time.Hour)}, "name", "kataras", false)
+	// a temporary file which may be written right now.
+	tmp := filepath.Join(dir, tempPrefix+"recent")
+	if err = ioutil.WriteFile(tmp, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err = os.Stat(db.filename("expired")); os.IsNotExist(err) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected the expired session file to be removed by the periodic cleanup")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, name := range []string{db.filename("alive"), tmp} {
+		if _, err = os.Stat(name); err != nil {
+			t.Fatalf("expected %s to be kept: %v", name, err)
+		}
+	}
+
+	db.Close()
+	db.Close() // it can be called more than once.
+	select {
+	case <-db.stop:
+	default:
+		t.Fatal("expected the periodic cleanup to be stopped")
+	}
+}
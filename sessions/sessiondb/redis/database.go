@@ -4,9 +4,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/kataras/iris/v12/core/logging"
 	"github.com/kataras/iris/v12/sessions"
-
-	"github.com/kataras/golog"
 )
 
 const (
@@ -70,6 +69,8 @@ func DefaultConfig() Config {
 // Database the redis back-end session database for the sessions.
 type Database struct {
 	c Config
+
+	logger logging.Var
 }
 
 var _ sessions.Database = (*Database)(nil)
@@ -112,7 +113,7 @@ func New(cfg ...Config) *Database {
 	db := &Database{c: c}
 	_, err := db.c.Driver.PingPong()
 	if err != nil {
-		golog.Debugf("error connecting to redis: %v", err)
+		db.logger.Debug("sessions: redis: connect failed", "error", err)
 		return nil
 	}
 	// runtime.SetFinalizer(db, closeDB)
@@ -124,6 +125,14 @@ func (db *Database) Config() *Config {
 	return &db.c // 6 Aug 2019 - keep that for no breaking change.
 }
 
+// SetLogger sets the logger of the database's errors,
+// the sessions manager sets it on `UseDatabase`, see `sessions.DatabaseLogger`.
+//
+// Defaults to the `logging.Default()`.
+func (db *Database) SetLogger(logger logging.Logger) {
+	db.logger.Set(logger)
+}
+
 // Acquire receives a session's lifetime from the database,
 // if the return value is LifeTime{} then the session manager sets the life time based on the expiration duration lives in configuration.
 func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime {
@@ -132,7 +141,7 @@ func (db *Database) Acquire(sid string, expires time.Duration) sessions.LifeTime
 		// fmt.Printf("db.Acquire expires: %s. Seconds: %v\n", expires, expires.Seconds())
 		// not found, create an entry with ttl and return an empty lifetime, session manager will do its job.
 		if err := db.c.Driver.Set(sid, sid, int64(expires.Seconds())); err != nil {
-			golog.Debug(err)
+			db.logger.Debug("sessions: redis", "error", err)
 		}
 
 		return sessions.LifeTime{} // session manager will handle the rest.
@@ -160,14 +169,14 @@ func (db *Database) makeKey(sid, key string) string {
 func (db *Database) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
 	valueBytes, err := sessions.DefaultTranscoder.Marshal(value)
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: redis", "error", err)
 		return
 	}
 
 	// fmt.Println("database.Set")
 	// fmt.Printf("lifetime.DurationUntilExpiration(): %s. Seconds: %v\n", lifetime.DurationUntilExpiration(), lifetime.DurationUntilExpiration().Seconds())
 	if err = db.c.Driver.Set(db.makeKey(sid, key), valueBytes, int64(lifetime.DurationUntilExpiration().Seconds())); err != nil {
-		golog.Debug(err)
+		db.logger.Debug("sessions: redis", "error", err)
 	}
 }
 
@@ -185,14 +194,14 @@ func (db *Database) get(key string, outPtr interface{}) {
 	}
 
 	if err = sessions.DefaultTranscoder.Unmarshal(data.([]byte), outPtr); err != nil {
-		golog.Debugf("unable to unmarshal value of key: '%s': %v", key, err)
+		db.logger.Debug("sessions: redis: unmarshal failed", "key", key, "error", err)
 	}
 }
 
 func (db *Database) keys(sid string) []string {
 	keys, err := db.c.Driver.GetKeys(sid)
 	if err != nil {
-		golog.Debugf("unable to get all redis keys of session '%s': %v", sid, err)
+		db.logger.Debug("sessions: redis: get keys failed", "sid", sid, "error", err)
 		return nil
 	}
 
@@ -218,7 +227,7 @@ func (db *Database) Len(sid string) (n int) {
 func (db *Database) Delete(sid string, key string) (deleted bool) {
 	err := db.c.Driver.Delete(db.makeKey(sid, key))
 	if err != nil {
-		golog.Error(err)
+		db.logger.Error("sessions: redis", "error", err)
 	}
 	return err == nil
 }
@@ -228,7 +237,7 @@ func (db *Database) Clear(sid string) {
 	keys := db.keys(sid)
 	for _, key := range keys {
 		if err := db.c.Driver.Delete(key); err != nil {
-			golog.Debugf("unable to delete session '%s' value of key: '%s': %v", sid, key, err)
+			db.logger.Debug("sessions: redis: delete failed", "sid", sid, "key", key, "error", err)
 		}
 	}
 }
@@ -241,7 +250,7 @@ func (db *Database) Release(sid string) {
 	// and remove the $sid.
 	err := db.c.Driver.Delete(sid)
 	if err != nil {
-		golog.Debugf("Database.Release.Driver.Delete: %s: %v", sid, err)
+		db.logger.Debug("sessions: redis: release failed", "sid", sid, "error", err)
 	}
 }
 
@@ -2,6 +2,7 @@ package sessions
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
@@ -17,23 +18,47 @@ import (
 type Sessions struct {
 	config   Config
 	provider *provider
+
+	db         Database
+	loggerOnce sync.Once
 }
 
 // New returns a new fast, feature-rich sessions manager
 // it can be adapted to an iris station
 func New(cfg Config) *Sessions {
+	cfg = cfg.Validate()
+
+	p := newProvider(providerShards, cfg.MaxSessions)
+	p.gcInterval = cfg.GCInterval
+
 	return &Sessions{
-		config:   cfg.Validate(),
-		provider: newProvider(),
+		config:   cfg,
+		provider: p,
 	}
 }
 
 // UseDatabase adds a session database to the manager's provider,
-// a session db doesn't have write access
+// a session db doesn't have write access.
+// A `DatabaseLogger` logs through the `Config.Logger`.
 func (s *Sessions) UseDatabase(db Database) {
+	if l, ok := db.(DatabaseLogger); ok && s.config.Logger != nil {
+		l.SetLogger(s.config.Logger)
+	}
+
+	s.db = db
 	s.provider.RegisterDatabase(db)
 }
 
+// setDatabaseLogger passes the application's logger to the database,
+// if the `Config.Logger` is missing.
+func (s *Sessions) setDatabaseLogger(ctx context.Context) {
+	s.loggerOnce.Do(func() {
+		if l, ok := s.db.(DatabaseLogger); ok && s.config.Logger == nil {
+			l.SetLogger(ctx.Application().Log())
+		}
+	})
+}
+
 // updateCookie gains the ability of updating the session browser cookie to any method which wants to update it
 func (s *Sessions) updateCookie(ctx context.Context, sid string, expires time.Duration, options ...context.CookieOption) {
 	cookie := &http.Cookie{}
@@ -79,7 +104,14 @@ func (s *Sessions) updateCookie(ctx context.Context, sid string, expires time.Du
 
 // Start creates or retrieves an existing session for the particular request.
 func (s *Sessions) Start(ctx context.Context, cookieOptions ...context.CookieOption) *Session {
+	defer ctx.Timeline().Begin("session")()
+	s.setDatabaseLogger(ctx)
+
 	cookieValue := s.decodeCookieValue(GetCookie(ctx, s.config.Cookie))
+	if cookieValue != "" && s.config.DisallowExternalIDs && !s.provider.Has(cookieValue) {
+		// not created by the server, e.g. set by an attacker, start a new one.
+		cookieValue = ""
+	}
 
 	if cookieValue == "" { // cookie doesn't exist, let's generate a session and set a cookie.
 		sid := s.config.SessionIDGenerator(ctx)
@@ -154,6 +186,14 @@ func (s *Sessions) UpdateExpiration(ctx context.Context, expires time.Duration,
 	return err
 }
 
+// RegenerateID replaces the ID of the "sess" session with a new one, generated by the `Config.SessionIDGenerator`,
+// and sets the new session cookie. See `Session.RegenerateID`.
+func (s *Sessions) RegenerateID(ctx context.Context, sess *Session, cookieOptions ...context.CookieOption) {
+	sid := s.config.SessionIDGenerator(ctx)
+	s.provider.Regenerate(sess, sid)
+	s.updateCookie(ctx, sid, s.config.Expires, cookieOptions...)
+}
+
 // DestroyListener is the form of a destroy listener.
 // Look `OnDestroy` for more.
 type DestroyListener func(sid string)
@@ -201,6 +241,27 @@ func (s *Sessions) DestroyAll() {
 	s.provider.DestroyAll()
 }
 
+// Len returns the number of the active sessions, the ones that are kept in the server-side memory.
+func (s *Sessions) Len() int {
+	return s.provider.Len()
+}
+
+// Stats holds the statistics of the sessions kept in the server-side memory, see `Sessions.Stats`.
+type Stats struct {
+	// Live is the number of the sessions in memory.
+	Live int
+	// Evictions is the number of the sessions evicted because of the `Config.MaxSessions` limit.
+	Evictions uint64
+}
+
+// Stats returns the statistics of the sessions kept in the server-side memory.
+func (s *Sessions) Stats() Stats {
+	return Stats{
+		Live:      s.provider.Len(),
+		Evictions: s.provider.Evictions(),
+	}
+}
+
 // let's keep these funcs simple, we can do it with two lines but we may add more things in the future.
 func (s *Sessions) decodeCookieValue(cookieValue string) string {
 	if cookieValue == "" {
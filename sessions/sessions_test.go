@@ -1,6 +1,7 @@
 package sessions_test
 
 import (
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -309,3 +310,169 @@ func TestSessionsUpdateExpirationConcurrently(t *testing.T) {
 	tt.Status(httptest.StatusOK).Body().Equal(id)
 	tt.Cookie(cookieName).MaxAge().InRange(29*time.Minute, 30*time.Minute)
 }
+
+type cartItem struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// jsonDB is a session database which, like the real ones, returns the values decoded as generic types
+// and counts the written keys.
+type jsonDB struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	writes []string
+}
+
+func (db *jsonDB) Acquire(sid string, expires time.Duration) sessions.LifeTime {
+	return sessions.LifeTime{}
+}
+func (db *jsonDB) OnUpdateExpiration(string, time.Duration) error { return nil }
+func (db *jsonDB) Set(sid string, lifetime sessions.LifeTime, key string, value interface{}, immutable bool) {
+	b, _ := sessions.DefaultTranscoder.Marshal(value)
+	db.mu.Lock()
+	db.values[key] = b
+	db.writes = append(db.writes, key)
+	db.mu.Unlock()
+}
+func (db *jsonDB) Get(sid string, key string) (value interface{}) {
+	db.mu.Lock()
+	b, ok := db.values[key]
+	db.mu.Unlock()
+	if ok {
+		sessions.DefaultTranscoder.Unmarshal(b, &value)
+	}
+	return
+}
+func (db *jsonDB) Visit(sid string, cb func(key string, value interface{})) {}
+func (db *jsonDB) Len(sid string) int                                       { return len(db.values) }
+func (db *jsonDB) Delete(sid string, key string) bool {
+	db.mu.Lock()
+	_, ok := db.values[key]
+	delete(db.values, key)
+	db.mu.Unlock()
+	return ok
+}
+func (db *jsonDB) Clear(sid string)   {}
+func (db *jsonDB) Release(sid string) {}
+
+func TestSessionsCollection(t *testing.T) {
+	db := &jsonDB{values: make(map[string][]byte)}
+	sess := sessions.New(sessions.Config{Cookie: "cart"})
+	sess.UseDatabase(db)
+
+	app := iris.New()
+	app.Use(sess.Handler())
+	app.Post("/cart/{id}/{quantity:int}", func(ctx iris.Context) {
+		cart := sessions.Get(ctx).Collection("cart", cartItem{})
+		cart.Add(ctx.Params().Get("id"), cartItem{Name: ctx.Params().Get("id"), Quantity: ctx.Params().GetIntDefault("quantity", 1)})
+	})
+	app.Delete("/cart/{id}", func(ctx iris.Context) {
+		if !sessions.Get(ctx).Collection("cart", cartItem{}).Remove(ctx.Params().Get("id")) {
+			ctx.StatusCode(iris.StatusNotFound)
+		}
+	})
+	app.Get("/cart", func(ctx iris.Context) {
+		var items []cartItem
+		sessions.Get(ctx).Collection("cart", cartItem{}).Range(func(id string, v interface{}) bool {
+			items = append(items, v.(cartItem))
+			return true
+		})
+		ctx.JSON(items)
+	})
+	app.Get("/cart/{id}", func(ctx iris.Context) {
+		var item cartItem
+		if !sessions.Get(ctx).Collection("cart", cartItem{}).Get(ctx.Params().Get("id"), &item) {
+			ctx.StatusCode(iris.StatusNotFound)
+			return
+		}
+		ctx.JSON(item)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	e.POST("/cart/apple/2").Expect().Status(httptest.StatusOK)
+	e.POST("/cart/pear/1").Expect().Status(httptest.StatusOK)
+
+	db.writes = nil
+	// updating an existing entry rewrites only that entry.
+	e.POST("/cart/apple/3").Expect().Status(httptest.StatusOK)
+	if expected := []string{"cart.apple"}; len(db.writes) != 1 || db.writes[0] != expected[0] {
+		t.Fatalf("expected writes: %v but got: %v", expected, db.writes)
+	}
+
+	e.GET("/cart").Expect().Status(httptest.StatusOK).JSON().Equal([]cartItem{{"apple", 3}, {"pear", 1}})
+	e.GET("/cart/pear").Expect().Status(httptest.StatusOK).JSON().Equal(cartItem{"pear", 1})
+
+	e.DELETE("/cart/apple").Expect().Status(httptest.StatusOK)
+	e.DELETE("/cart/apple").Expect().Status(httptest.StatusNotFound)
+	e.GET("/cart/apple").Expect().Status(httptest.StatusNotFound)
+	e.GET("/cart").Expect().Status(httptest.StatusOK).JSON().Equal([]cartItem{{"pear", 1}})
+}
+
+func TestSessionsRegenerateID(t *testing.T) {
+	app := iris.New()
+
+	cookieName := "mycustomsessionid"
+	sess := sessions.New(sessions.Config{
+		Cookie:              cookieName,
+		SessionIDGenerator:  sessions.RandomIDGenerator(22, ""),
+		DisallowExternalIDs: true,
+	})
+
+	app.Use(sess.Handler())
+	app.Get("/id", func(ctx iris.Context) {
+		ctx.WriteString(sessions.Get(ctx).ID())
+	})
+	app.Post("/login", func(ctx iris.Context) {
+		session := sessions.Get(ctx)
+		session.Set("user", "kataras")
+		session.RegenerateID(ctx)
+		ctx.WriteString(session.ID())
+	})
+	app.Get("/user", func(ctx iris.Context) {
+		ctx.WriteString(sessions.Get(ctx).GetString("user"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	// a client-provided ID is not accepted.
+	fixated := "attacker-known-session-id"
+	e.GET("/id").WithCookie(cookieName, fixated).Expect().Status(httptest.StatusOK).
+		Cookie(cookieName).Value().NotEqual(fixated)
+
+	sid := e.GET("/id").Expect().Status(httptest.StatusOK).Body().Raw()
+	if len(sid) != 22 {
+		t.Fatalf("expected a generated id of 22 characters but got: %q", sid)
+	}
+
+	newID := e.POST("/login").Expect().Status(httptest.StatusOK).Body().NotEqual(sid).Raw()
+	e.GET("/id").Expect().Status(httptest.StatusOK).Body().Equal(newID)
+	e.GET("/user").Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+
+	// the old ID is no longer valid.
+	e.GET("/user").WithCookie(cookieName, sid).Expect().Status(httptest.StatusOK).Body().Empty()
+}
+
+func TestSessionIDGenerators(t *testing.T) {
+	random := sessions.RandomIDGenerator(40, "ab")
+	ksuid := sessions.KSUIDGenerator()
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		id := random(nil)
+		if len(id) != 40 || strings.Trim(id, "ab") != "" {
+			t.Fatalf("unexpected random id: %q", id)
+		}
+
+		k := ksuid(nil)
+		if len(k) != 27 {
+			t.Fatalf("unexpected ksuid: %q", k)
+		}
+
+		if _, ok := seen[k]; ok {
+			t.Fatalf("duplicated ksuid: %q", k)
+		}
+		seen[k] = struct{}{}
+	}
+}
@@ -0,0 +1,177 @@
+package iris
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+var (
+	staticLinkRegexp   = regexp.MustCompile(`(?i)\s(?:href|src)\s*=\s*["']([^"'#]+)`)
+	staticCSSURLRegexp = regexp.MustCompile(`url\(\s*["']?([^"')]+)["']?\s*\)`)
+)
+
+// BuildStatic generates a static site of the application to the "outDir" directory,
+// for static hosting of mostly-static sites.
+// The GET routes are executed in-process (no network) starting from the "seeds" paths,
+// or the GET routes without dynamic path parameters if no seeds are given,
+// following the internal links (href and src attributes and CSS urls) of their responses.
+//
+// HTML pages are written as "index.html" files of their path's directory,
+// i.e "/about" to "outDir/about/index.html", the rest of the responses (assets)
+// are written as they are, i.e "/css/main.css" to "outDir/css/main.css".
+// Redirects to internal paths are followed, the rest of the non-successful responses are skipped with a warning.
+// An asset which its path is the directory of another response, i.e "/docs" (text/plain) and "/docs/intro",
+// can not be written, an error is returned instead.
+//
+// Example Code:
+//  if *generate {
+//      if err := app.BuildStatic("./public", "/", "/sitemap.xml"); err != nil { [...] }
+//      return
+//  }
+//  app.Listen(":8080")
+func (app *Application) BuildStatic(outDir string, seeds ...string) error {
+	if err := app.Build(); err != nil {
+		return err
+	}
+
+	if len(seeds) == 0 {
+		for _, r := range app.GetRoutes() {
+			if r.Method == http.MethodGet && len(r.Tmpl().Params) == 0 && r.Subdomain == "" {
+				seeds = append(seeds, r.Tmpl().Src)
+			}
+		}
+	}
+
+	var (
+		queue   []string
+		visited = make(map[string]struct{})
+		// files and dirs are the written filenames and their directories,
+		// by their request paths, to report the conflicts.
+		files = make(map[string]string)
+		dirs  = make(map[string]string)
+	)
+
+	enqueue := func(p string) {
+		if p == "" {
+			return
+		}
+
+		if _, ok := visited[p]; ok {
+			return
+		}
+
+		visited[p] = struct{}{}
+		queue = append(queue, p)
+	}
+
+	for _, seed := range seeds {
+		enqueue(staticPath("/", seed))
+	}
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		req := httptest.NewRequest(http.MethodGet, p, nil)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		switch {
+		case rec.Code >= 300 && rec.Code < 400:
+			enqueue(staticPath(p, rec.Header().Get("Location")))
+			continue
+		case rec.Code != http.StatusOK:
+			app.logger.Warnf("build static: %s: %d %s", p, rec.Code, http.StatusText(rec.Code))
+			continue
+		}
+
+		body := rec.Body.Bytes()
+		mediaType, _, _ := mime.ParseMediaType(rec.Header().Get(context.ContentTypeHeaderKey))
+
+		filename, err := url.PathUnescape(p)
+		if err != nil {
+			return fmt.Errorf("build static: %s: %w", p, err)
+		}
+		filename = path.Clean("/" + filename)
+		var links *regexp.Regexp
+		switch mediaType {
+		case context.ContentHTMLHeaderValue:
+			links = staticLinkRegexp
+			if path.Ext(filename) != ".html" {
+				filename = path.Join(filename, "index.html")
+			}
+		case "text/css":
+			links = staticCSSURLRegexp
+		}
+
+		if filename == "/" {
+			filename = "/index.html"
+		}
+
+		if other, ok := dirs[filename]; ok {
+			return fmt.Errorf("build static: %s: conflicts with the directory of %s", p, other)
+		}
+
+		for dir := path.Dir(filename); dir != "/"; dir = path.Dir(dir) {
+			if other, ok := files[dir]; ok {
+				return fmt.Errorf("build static: %s: conflicts with the file of %s", p, other)
+			}
+			dirs[dir] = p
+		}
+		files[filename] = p
+
+		dest := filepath.Join(outDir, filepath.FromSlash(filename))
+		if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+			return fmt.Errorf("build static: %w", err)
+		}
+
+		if err := ioutil.WriteFile(dest, body, 0644); err != nil {
+			return fmt.Errorf("build static: %w", err)
+		}
+
+		if links != nil {
+			for _, m := range links.FindAllSubmatch(body, -1) {
+				enqueue(staticPath(p, string(m[1])))
+			}
+		}
+	}
+
+	return nil
+}
+
+// staticPath returns the clean, escaped, path of the "link" relative to the "base" path,
+// or empty if the "link" is external.
+func staticPath(base, link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Opaque != "" {
+		return ""
+	}
+
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+
+	// keep it escaped, it's used as a request target.
+	p := b.ResolveReference(u).EscapedPath()
+	if p == "" {
+		return ""
+	}
+
+	if strings.HasSuffix(p, "/") && p != "/" {
+		return path.Clean(p) + "/"
+	}
+
+	return path.Clean(p)
+}
@@ -0,0 +1,55 @@
+package tenancy
+
+import (
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+// Sessions returns a sessions middleware which scopes the sessions per tenant:
+// each tenant has its own sessions manager and its cookie name is the "c.Cookie"
+// followed by "_" and the tenant's ID, so a session of a tenant is never read by another.
+// The optional "configure" is called once for each new manager, i.e to register a database.
+// Register it after the `New` middleware and use the `sessions.Get(ctx)`
+// or the `*sessions.Session` input argument as usual.
+// A manager is kept for each tenant, so the `Config.Store` should be set
+// when the tenants are resolved from client-controlled input.
+func Sessions(c sessions.Config, configure func(tenant string, sess *sessions.Sessions)) context.Handler {
+	c = c.Validate()
+
+	var (
+		mu       sync.Mutex
+		managers = make(map[string]context.Handler)
+	)
+
+	manager := func(tenant string) context.Handler {
+		mu.Lock()
+		defer mu.Unlock()
+
+		h, ok := managers[tenant]
+		if !ok {
+			tc := c
+			tc.Cookie = c.Cookie + "_" + tenant
+			sess := sessions.New(tc)
+			if configure != nil {
+				configure(tenant, sess)
+			}
+
+			h = sess.Handler()
+			managers[tenant] = h
+		}
+
+		return h
+	}
+
+	return func(ctx context.Context) {
+		t := FromContext(ctx)
+		if t == nil {
+			ctx.Next()
+			return
+		}
+
+		manager(t.ID)(ctx)
+	}
+}
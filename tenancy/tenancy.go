@@ -0,0 +1,257 @@
+// Package tenancy provides the tenant resolution of multi-tenant applications.
+// The tenant is resolved from the request's host, a header or the path
+// and it's available through `FromContext` and, when `Dependency` is registered,
+// as a hero/mvc `*tenancy.Tenant` input argument.
+package tenancy
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+func init() {
+	context.SetHandlerName("iris/tenancy.*", "Tenancy")
+}
+
+// Tenant is the resolved tenant of a request.
+type Tenant struct {
+	// ID is the tenant's identifier, as it was resolved.
+	ID string
+	// Config is the tenant's configuration, loaded from the `Config.Store`.
+	Config map[string]interface{}
+}
+
+// Get returns the value of a configuration "key" of the tenant, or nil.
+func (t *Tenant) Get(key string) interface{} {
+	return t.Config[key]
+}
+
+// GetString returns the string value of a configuration "key" of the tenant, or empty.
+func (t *Tenant) GetString(key string) string {
+	s, _ := t.Config[key].(string)
+	return s
+}
+
+var (
+	// ErrNotFound is returned from a Store when the tenant does not exist.
+	ErrNotFound = errors.New("tenancy: tenant not found")
+	// ErrNoTenant is the error of the `New` middleware and the `Dependency`
+	// when the request has no resolved tenant.
+	ErrNoTenant = errors.New("tenancy: no tenant")
+)
+
+// Store loads the configuration of the tenants.
+type Store interface {
+	// Load returns the configuration of the tenant "id"
+	// or `ErrNotFound` if the tenant does not exist.
+	Load(id string) (map[string]interface{}, error)
+}
+
+// MemoryStore is a Store which keeps the tenants' configuration in memory,
+// safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	tenants map[string]map[string]interface{}
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tenants: make(map[string]map[string]interface{})}
+}
+
+// Set adds or replaces the configuration of the tenant "id".
+func (s *MemoryStore) Set(id string, config map[string]interface{}) {
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+
+	s.mu.Lock()
+	s.tenants[id] = config
+	s.mu.Unlock()
+}
+
+// Delete removes the tenant "id".
+func (s *MemoryStore) Delete(id string) {
+	s.mu.Lock()
+	delete(s.tenants, id)
+	s.mu.Unlock()
+}
+
+// Load implements the Store.
+func (s *MemoryStore) Load(id string) (map[string]interface{}, error) {
+	s.mu.RLock()
+	config, ok := s.tenants[id]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return config, nil
+}
+
+// Resolver returns the tenant's identifier of a request or empty if it can not be resolved.
+type Resolver func(ctx context.Context) string
+
+// FromHost returns a Resolver which resolves the tenant from the subdomain of the "domain",
+// e.g. FromHost("example.com") resolves "acme" from "acme.example.com:8080".
+func FromHost(domain string) Resolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(domain, "."))
+
+	return func(ctx context.Context) string {
+		host := strings.ToLower(ctx.Host())
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+
+		return strings.TrimSuffix(host, suffix)
+	}
+}
+
+// FromHeader returns a Resolver which resolves the tenant from a request header,
+// e.g. FromHeader("X-Tenant").
+func FromHeader(name string) Resolver {
+	return func(ctx context.Context) string {
+		return ctx.GetHeader(name)
+	}
+}
+
+// FromPath returns a Resolver which resolves the tenant from the first segment of the request path,
+// e.g. "acme" from "/acme/users". Register the routes under a "/{tenant}" Party.
+func FromPath() Resolver {
+	return func(ctx context.Context) string {
+		path := strings.TrimPrefix(ctx.Path(), "/")
+		if idx := strings.IndexByte(path, '/'); idx >= 0 {
+			path = path[:idx]
+		}
+
+		return path
+	}
+}
+
+// Config contains the options for the tenancy middleware.
+type Config struct {
+	// Resolvers are the tenant resolvers, the first non-empty identifier wins.
+	Resolvers []Resolver
+	// Store, if not nil, loads the tenant's configuration,
+	// the requests of unknown tenants are rejected.
+	Store Store
+	// Optional, if true, allows the requests without a tenant to continue.
+	// Defaults to false, they are rejected with 400 Bad Request.
+	Optional bool
+	// OnError, if not nil, is fired instead of the default error responses
+	// with the "err" as `Context.GetErr()`: `ErrNoTenant`, `ErrNotFound` or the Store's error.
+	OnError context.Handler
+}
+
+const contextKey = "iris.tenant"
+
+// New returns a middleware which resolves the tenant of each request,
+// see `FromContext`. Valid tenant identifiers contain letters, digits, '-' and '_' only.
+func New(c Config) context.Handler {
+	if len(c.Resolvers) == 0 {
+		panic("tenancy: at least one resolver is required")
+	}
+
+	fail := func(ctx context.Context, statusCode int, err error) {
+		if c.OnError != nil {
+			ctx.SetErr(err)
+			c.OnError(ctx)
+			return
+		}
+
+		ctx.StopWithStatus(statusCode)
+	}
+
+	return func(ctx context.Context) {
+		id := ""
+		for _, resolve := range c.Resolvers {
+			if id = resolve(ctx); id != "" {
+				break
+			}
+		}
+
+		if id == "" {
+			if c.Optional {
+				ctx.Next()
+				return
+			}
+
+			fail(ctx, http.StatusBadRequest, ErrNoTenant)
+			return
+		}
+
+		if !validID(id) {
+			fail(ctx, http.StatusBadRequest, ErrNoTenant)
+			return
+		}
+
+		tenant := &Tenant{ID: id}
+		if c.Store != nil {
+			config, err := c.Store.Load(id)
+			if err != nil {
+				if err == ErrNotFound {
+					fail(ctx, http.StatusNotFound, err)
+				} else {
+					fail(ctx, http.StatusInternalServerError, err)
+				}
+				return
+			}
+
+			tenant.Config = config
+		}
+
+		ctx.Values().Set(contextKey, tenant)
+		ctx.Next()
+	}
+}
+
+func validID(id string) bool {
+	if len(id) > 64 {
+		return false
+	}
+
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FromContext returns the resolved tenant of the request, or nil.
+func FromContext(ctx context.Context) *Tenant {
+	if v := ctx.Values().Get(contextKey); v != nil {
+		if t, ok := v.(*Tenant); ok {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// Dependency is the hero/mvc dependency of the request's `*Tenant`,
+// it fails with `ErrNoTenant` when the request has no resolved tenant.
+//
+// Usage:
+//  app.ConfigureContainer().RegisterDependency(tenancy.Dependency)
+//  app.ConfigureContainer().Get("/", func(tenant *tenancy.Tenant) string { ... })
+func Dependency(ctx context.Context) (*Tenant, error) {
+	if t := FromContext(ctx); t != nil {
+		return t, nil
+	}
+
+	return nil, ErrNoTenant
+}
@@ -0,0 +1,61 @@
+package tenancy_test
+
+import (
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/sessions"
+	"github.com/kataras/iris/v12/tenancy"
+)
+
+func TestTenancy(t *testing.T) {
+	store := tenancy.NewMemoryStore()
+	store.Set("acme", map[string]interface{}{"theme": "dark"})
+	store.Set("globex", nil)
+
+	app := iris.New()
+	app.Use(tenancy.New(tenancy.Config{
+		Resolvers: []tenancy.Resolver{tenancy.FromHeader("X-Tenant"), tenancy.FromHost("example.com")},
+		Store:     store,
+	}))
+
+	app.Get("/", func(ctx iris.Context) {
+		tenant := tenancy.FromContext(ctx)
+		ctx.Writef("%s:%s", tenant.ID, tenant.GetString("theme"))
+	})
+	app.ConfigureContainer().RegisterDependency(tenancy.Dependency)
+	app.ConfigureContainer().Get("/hero", func(tenant *tenancy.Tenant) string {
+		return tenant.ID
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithHeader("X-Tenant", "acme").Expect().Status(httptest.StatusOK).Body().Equal("acme:dark")
+	e.GET("/").WithHeader("Host", "globex.example.com:8080").Expect().Status(httptest.StatusOK).Body().Equal("globex:")
+	e.GET("/hero").WithHeader("X-Tenant", "globex").Expect().Status(httptest.StatusOK).Body().Equal("globex")
+
+	e.GET("/").Expect().Status(httptest.StatusBadRequest)
+	e.GET("/").WithHeader("X-Tenant", "../acme").Expect().Status(httptest.StatusBadRequest)
+	e.GET("/").WithHeader("X-Tenant", "initech").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestTenancyFromPathAndSessions(t *testing.T) {
+	app := iris.New()
+	app.Use(tenancy.New(tenancy.Config{Resolvers: []tenancy.Resolver{tenancy.FromPath()}}))
+	app.Use(tenancy.Sessions(sessions.Config{Cookie: "sid"}, nil))
+
+	p := app.Party("/{tenant}")
+	p.Get("/login", func(ctx iris.Context) {
+		sessions.Get(ctx).Set("user", ctx.URLParam("user"))
+	})
+	p.Get("/user", func(ctx iris.Context) {
+		ctx.WriteString(sessions.Get(ctx).GetString("user"))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	e.GET("/acme/login").WithQuery("user", "kataras").Expect().Status(httptest.StatusOK).
+		Cookie("sid_acme").Value().NotEmpty()
+
+	e.GET("/acme/user").Expect().Status(httptest.StatusOK).Body().Equal("kataras")
+	e.GET("/globex/user").Expect().Status(httptest.StatusOK).Body().Equal("")
+}
@@ -1,6 +1,7 @@
 package versioning
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
@@ -10,15 +11,23 @@ import (
 // - "X-API-Warn": options.WarnMessage
 // - "X-API-Deprecation-Date": context.FormatTime(ctx, options.DeprecationDate))
 // - "X-API-Deprecation-Info": options.DeprecationInfo
+// - "Deprecation": the options.DeprecationDate as HTTP-date or "true"
+// - "Sunset": the options.SunsetDate as HTTP-date (RFC 8594)
+// - "Link": <options.Link>; rel="deprecation"
 type DeprecationOptions struct {
 	WarnMessage     string
 	DeprecationDate time.Time
 	DeprecationInfo string
+	// SunsetDate is the date the deprecated version will become unavailable.
+	SunsetDate time.Time
+	// Link is the URL of the deprecation documentation, e.g. a migration guide.
+	Link string
 }
 
 // ShouldHandle reports whether the deprecation headers should be present or no.
 func (opts DeprecationOptions) ShouldHandle() bool {
-	return opts.WarnMessage != "" || !opts.DeprecationDate.IsZero() || opts.DeprecationInfo != ""
+	return opts.WarnMessage != "" || !opts.DeprecationDate.IsZero() || opts.DeprecationInfo != "" ||
+		!opts.SunsetDate.IsZero() || opts.Link != ""
 }
 
 // DefaultDeprecationOptions are the default deprecation options,
@@ -46,6 +55,20 @@ func Deprecated(handler context.Handler, options DeprecationOptions) context.Han
 			ctx.Header("X-API-Deprecation-Info", options.DeprecationInfo)
 		}
 
+		if options.DeprecationDate.IsZero() {
+			ctx.Header("Deprecation", "true")
+		} else {
+			ctx.Header("Deprecation", options.DeprecationDate.UTC().Format(http.TimeFormat))
+		}
+
+		if !options.SunsetDate.IsZero() {
+			ctx.Header("Sunset", options.SunsetDate.UTC().Format(http.TimeFormat))
+		}
+
+		if options.Link != "" {
+			ctx.ResponseWriter().Header().Add("Link", "<"+options.Link+`>; rel="deprecation"`)
+		}
+
 		handler(ctx)
 	}
 }
@@ -1,6 +1,7 @@
 package versioning_test
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -30,4 +31,20 @@ func TestDeprecated(t *testing.T) {
 	ex.Header("X-API-Warn").Equal(opts.WarnMessage)
 	expectedDateStr := opts.DeprecationDate.Format(app.ConfigurationReadOnly().GetTimeFormat())
 	ex.Header("X-API-Deprecation-Date").Equal(expectedDateStr)
+	ex.Header("Deprecation").Equal(opts.DeprecationDate.Format(http.TimeFormat))
+	ex.Header("Sunset").Empty()
+
+	app = iris.New()
+	sunset := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	app.Get("/", versioning.Deprecated(writeVesion, versioning.DeprecationOptions{
+		SunsetDate: sunset,
+		Link:       "https://example.com/migrate",
+	}))
+
+	ex = httptest.New(t, app).GET("/").Expect()
+	ex.Status(iris.StatusOK)
+	ex.Header("X-API-Warn").Equal(versioning.DefaultDeprecationOptions.WarnMessage)
+	ex.Header("Deprecation").Equal("true")
+	ex.Header("Sunset").Equal("Fri, 01 Jan 2021 00:00:00 GMT")
+	ex.Header("Link").Equal(`<https://example.com/migrate>; rel="deprecation"`)
 }
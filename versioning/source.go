@@ -0,0 +1,90 @@
+package versioning
+
+import (
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// SetVersion sets the requested version of the current request,
+// it overrides the version sent by the client, see `GetVersion`.
+func SetVersion(ctx context.Context, version string) {
+	ctx.Values().Set(Key, version)
+}
+
+// FromHeader returns a middleware which reads the requested version
+// from a custom "key" request header, e.g. "X-API-Version".
+// The "Accept" and "Accept-Version" headers are still used when the "key" header is missing.
+func FromHeader(key string) context.Handler {
+	return func(ctx context.Context) {
+		if version := ctx.GetHeader(key); version != "" {
+			SetVersion(ctx, version)
+		}
+
+		ctx.Next()
+	}
+}
+
+// FromPath returns a middleware which reads the requested version
+// from the "paramName" path parameter, its "v" prefix is trimmed,
+// e.g. app.Party("/api/{version:string}", versioning.FromPath("version")) serves "/api/v1/users" as version "1".
+func FromPath(paramName string) context.Handler {
+	return func(ctx context.Context) {
+		if version := trimVersionPrefix(ctx.Params().Get(paramName)); version != "" {
+			SetVersion(ctx, version)
+		}
+
+		ctx.Next()
+	}
+}
+
+// FromQuery returns a middleware which reads the requested version
+// from the "name" URL query parameter, e.g. "?api-version=2".
+func FromQuery(name string) context.Handler {
+	return func(ctx context.Context) {
+		if version := trimVersionPrefix(ctx.URLParam(name)); version != "" {
+			SetVersion(ctx, version)
+		}
+
+		ctx.Next()
+	}
+}
+
+func trimVersionPrefix(version string) string {
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') {
+		return version[1:]
+	}
+
+	return version
+}
+
+// Aliases returns a middleware which replaces the requested version aliases,
+// e.g. "latest" and "stable", with their actual versions.
+// An empty alias key sets the version of the requests without a version.
+// Register it after any `FromHeader`, `FromPath` or `FromQuery` middleware.
+//
+// Example Code:
+//  api.Use(versioning.Aliases(map[string]string{
+//      versioning.Empty: "1",
+//      "stable":         "1.5.0",
+//      "latest":         "2.0.0",
+//  }))
+func Aliases(aliases map[string]string) context.Handler {
+	cp := make(map[string]string, len(aliases))
+	for alias, version := range aliases {
+		cp[strings.ToLower(alias)] = version
+	}
+
+	return func(ctx context.Context) {
+		requested := GetVersion(ctx)
+		if requested == NotFound {
+			requested = Empty
+		}
+
+		if version, ok := cp[strings.ToLower(requested)]; ok {
+			SetVersion(ctx, version)
+		}
+
+		ctx.Next()
+	}
+}
@@ -25,6 +25,8 @@ const (
 	// NotFound is the key that can be used inside a `Map` or inside `ctx.Values().Set(versioning.Key, versioning.NotFound)`
 	// to tell that a version wasn't found, therefore the not found handler should handle the request instead.
 	NotFound = Key + ".notfound"
+	// Empty is the alias key of the requests without a version, see `Aliases`.
+	Empty = ""
 )
 
 // NotFoundHandler is the default version not found handler that
@@ -53,7 +55,8 @@ var NotFoundHandler = func(ctx context.Context) {
 // - "Accept-Version" header, i.e Accept-Version: "1.0"
 //
 // However, the end developer can also set a custom version for a handler via a middleware by using the context's store key
-// for versions (see `Key` for further details on that).
+// for versions (see `Key` for further details on that)
+// or through the `FromHeader`, `FromPath` and `FromQuery` middleware.
 func GetVersion(ctx context.Context) string {
 	// firstly by context store, if manually set-ed by a middleware.
 	if version := ctx.Values().GetString(Key); version != "" {
@@ -65,31 +68,34 @@ func GetVersion(ctx context.Context) string {
 		return version
 	}
 
-	// thirdly by the "Accept" header which is like"...; version=1.0"
-	acceptValue := ctx.GetHeader(AcceptHeaderKey)
-	if acceptValue != "" {
-		if idx := strings.Index(acceptValue, AcceptHeaderVersionValue); idx != -1 {
-			rem := acceptValue[idx:]
-			startVersion := strings.Index(rem, "=")
-			if startVersion == -1 || len(rem) < startVersion+1 {
+	// thirdly by the "Accept" header's media type parameter which is like "...; version=1.0".
+	if acceptValue := ctx.GetHeader(AcceptHeaderKey); acceptValue != "" {
+		if version, found := versionFromAccept(acceptValue); found {
+			if version == "" {
 				return NotFound
 			}
 
-			rem = rem[startVersion+1:]
+			return version
+		}
+	}
 
-			end := strings.Index(rem, " ")
-			if end == -1 {
-				end = strings.Index(rem, ";")
-			}
-			if end == -1 {
-				end = len(rem)
-			}
+	return NotFound
+}
 
-			if version := rem[:end]; version != "" {
-				return version
+// versionFromAccept returns the "version" parameter of the "Accept" header's media ranges,
+// e.g. "application/vnd.api+json; version=2.1, application/json".
+func versionFromAccept(acceptValue string) (string, bool) {
+	for _, mediaRange := range strings.Split(acceptValue, ",") {
+		for _, param := range strings.Split(mediaRange, ";") {
+			param = strings.TrimSpace(param)
+			idx := strings.IndexByte(param, '=')
+			if idx == -1 || !strings.EqualFold(strings.TrimSpace(param[:idx]), AcceptHeaderVersionValue) {
+				continue
 			}
+
+			return strings.Trim(strings.TrimSpace(param[idx+1:]), `"`), true
 		}
 	}
 
-	return NotFound
+	return "", false
 }
@@ -45,4 +45,43 @@ func TestGetVersion(t *testing.T) {
 		Status(iris.StatusOK).Body().Equal(versioning.NotFound)
 
 	e.GET("/manual").Expect().Status(iris.StatusOK).Body().Equal("11.0.5")
+
+	// multiple media ranges.
+	e.GET("/").WithHeader(versioning.AcceptHeaderKey, "text/html, application/vnd.api+json;version=\"3.0\"").Expect().
+		Status(iris.StatusOK).Body().Equal("3.0")
+	e.GET("/").WithHeader(versioning.AcceptHeaderKey, "application/vnd.version+json").Expect().
+		Status(iris.StatusOK).Body().Equal(versioning.NotFound)
+}
+
+func TestVersionSources(t *testing.T) {
+	app := iris.New()
+
+	writeVesion := func(ctx iris.Context) {
+		ctx.WriteString(versioning.GetVersion(ctx))
+	}
+
+	aliases := versioning.Aliases(map[string]string{
+		versioning.Empty: "1.0",
+		"stable":         "1.5.0",
+		"Latest":         "2.0.0",
+	})
+
+	app.Get("/header", versioning.FromHeader("X-API-Version"), aliases, writeVesion)
+	app.Get("/query", versioning.FromQuery("api-version"), aliases, writeVesion)
+	app.Get("/api/{version:string}/users", versioning.FromPath("version"), aliases, versioning.NewMatcher(versioning.Map{
+		"1.0":       writeVesion,
+		">= 2, < 3": writeVesion,
+	}))
+
+	e := httptest.New(t, app)
+
+	e.GET("/header").WithHeader("X-API-Version", "1.1").Expect().Status(iris.StatusOK).Body().Equal("1.1")
+	e.GET("/header").WithHeader("X-API-Version", "latest").Expect().Status(iris.StatusOK).Body().Equal("2.0.0")
+	e.GET("/header").WithHeader(versioning.AcceptVersionHeaderKey, "stable").Expect().Status(iris.StatusOK).Body().Equal("1.5.0")
+	e.GET("/header").Expect().Status(iris.StatusOK).Body().Equal("1.0")
+	e.GET("/query").WithQuery("api-version", "v3").Expect().Status(iris.StatusOK).Body().Equal("3")
+
+	e.GET("/api/v1.0/users").Expect().Status(iris.StatusOK).Body().Equal("1.0")
+	e.GET("/api/latest/users").Expect().Status(iris.StatusOK).Header("X-API-Version").Equal("2.0.0")
+	e.GET("/api/v5/users").Expect().Status(iris.StatusNotImplemented)
 }
@@ -29,3 +29,10 @@ type Engine interface {
 	// Ext should return the final file extension which this view engine is responsible to render.
 	Ext() string
 }
+
+// EngineReader is an optional interface which view engines can implement
+// to return the raw contents of a template file, see `TemplateError`.
+type EngineReader interface {
+	// ReadFile should return the contents of the template file, relative to the engine's directory.
+	ReadFile(filename string) ([]byte, error)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -294,6 +295,17 @@ func (s *HTMLEngine) loadDirectory() error {
 	return templateErr
 }
 
+// ReadFile returns the contents of a template file, relative to the engine's directory.
+// It implements the `EngineReader` interface.
+func (s *HTMLEngine) ReadFile(filename string) ([]byte, error) {
+	if s.assetFn != nil {
+		virtualDirectory := strings.TrimPrefix(strings.TrimPrefix(filepath.ToSlash(s.directory), "."), "/")
+		return s.assetFn(path.Join(virtualDirectory, filename))
+	}
+
+	return ioutil.ReadFile(filepath.Join(s.directory, filename))
+}
+
 // loadAssets loads the templates by binary (go-bindata for embedded).
 func (s *HTMLEngine) loadAssets() error {
 	virtualDirectory, virtualExtension := s.directory, s.extension
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -49,7 +51,64 @@ func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindin
 		return fmt.Errorf("no view engine found for '%s'", filepath.Ext(filename))
 	}
 
-	return e.ExecuteWriter(w, filename, layout, bindingData)
+	if err := e.ExecuteWriter(w, filename, layout, bindingData); err != nil {
+		return newTemplateError(e, err)
+	}
+
+	return nil
+}
+
+// TemplateError wraps a template's parse or execution error
+// with the name, the line and the source of the failing template.
+// It's returned by `View.ExecuteWriter` when the view engine
+// implements the `EngineReader` and the error contains the template's line.
+type TemplateError struct {
+	Name   string
+	Line   int
+	Source []byte
+	Err    error
+}
+
+// Error implements the error interface, it returns the underline error's message.
+func (e *TemplateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underline error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// i.e template: index.html:5:12: executing "index.html" at <.Missing>...
+var templateErrorLineRegex = regexp.MustCompile(`template: ([^:\s]+):(\d+)`)
+
+func newTemplateError(e Engine, err error) error {
+	reader, ok := e.(EngineReader)
+	if !ok {
+		return err
+	}
+
+	matches := templateErrorLineRegex.FindStringSubmatch(err.Error())
+	if len(matches) != 3 {
+		return err
+	}
+
+	line, convErr := strconv.Atoi(matches[2])
+	if convErr != nil {
+		return err
+	}
+
+	source, readErr := reader.ReadFile(matches[1])
+	if readErr != nil {
+		return err
+	}
+
+	return &TemplateError{
+		Name:   matches[1],
+		Line:   line,
+		Source: source,
+		Err:    err,
+	}
 }
 
 // AddFunc adds a function to all registered engines.
@@ -0,0 +1,341 @@
+package websocket
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/kataras/neffos"
+)
+
+// FallbackTokenHeader is the request header of the fallback clients' POST messages,
+// its value is the token sent by the server on the "open" event of the stream.
+const FallbackTokenHeader = "X-Fallback-Token"
+
+// Fallback is a transport which serves the websocket clients and,
+// for the clients behind proxies that do not allow websockets,
+// the server-sent events (SSE) and POST clients through the same neffos server,
+// so the same events handle both kind of clients.
+//
+// The transport is detected automatically on each new connection:
+// a request with the "Upgrade: websocket" header is a websocket client
+// and a request which accepts "text/event-stream" is a fallback client.
+// A fallback client reads the messages from the event stream, the first event is the
+// "open" one and its data is the connection's token, each "message" event is a text message
+// and each "binary" event is a base64-encoded binary message. It sends its messages with POST requests
+// to the same endpoint, with the token as the `FallbackTokenHeader`
+// and the "application/octet-stream" content type for binary messages.
+// The messages follow the neffos protocol, as the websocket ones.
+//
+// Usage:
+//  fallback := websocket.NewFallback(websocket.DefaultGorillaUpgrader)
+//  ws := websocket.New(fallback.Upgrade, websocket.Events{...})
+//  app.Any("/socket", fallback.Handler(ws))
+type Fallback struct {
+	// KeepAlive is the interval of the comments written to the event streams
+	// to keep the idle connections open through proxies.
+	// Defaults to 30 seconds.
+	KeepAlive time.Duration
+	// MaxMessageSize is the maximum size of a POST message.
+	// Defaults to 1MB.
+	MaxMessageSize int64
+
+	upgrader neffos.Upgrader
+
+	mu      sync.RWMutex
+	sockets map[string]*sseSocket
+	pending map[*http.Request]*sseSocket
+}
+
+// NewFallback returns a new Fallback transport which upgrades the websocket clients
+// through the "upgrader", e.g. `DefaultGorillaUpgrader`.
+func NewFallback(upgrader neffos.Upgrader) *Fallback {
+	return &Fallback{
+		KeepAlive:      30 * time.Second,
+		MaxMessageSize: 1 << 20,
+		upgrader:       upgrader,
+		sockets:        make(map[string]*sseSocket),
+		pending:        make(map[*http.Request]*sseSocket),
+	}
+}
+
+// IsFallbackRequest reports whether the request should be served by the fallback transport.
+func IsFallbackRequest(r *http.Request) bool {
+	return !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// Upgrade is the neffos Upgrader of the Fallback, pass it to the `New` function.
+func (f *Fallback) Upgrade(w http.ResponseWriter, r *http.Request) (neffos.Socket, error) {
+	if !IsFallbackRequest(r) {
+		return f.upgrader(w, r)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, errors.New("websocket: fallback: streaming is not supported")
+	}
+
+	token, err := newFallbackToken()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, err
+	}
+
+	s := &sseSocket{
+		token:   token,
+		request: r,
+		w:       w,
+		flusher: flusher,
+		in:      make(chan sseMessage, 16),
+		done:    make(chan struct{}),
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err = s.writeEvent("open", []byte(token)); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.sockets[token] = s
+	f.pending[r] = s
+	f.mu.Unlock()
+
+	return s, nil
+}
+
+// Handler returns the Iris handler which serves both the websocket and the fallback clients
+// of the "s" neffos server, which must be created with the `Fallback.Upgrade`.
+// Register it for the GET and POST methods, e.g. with `Party.Any`.
+func (f *Fallback) Handler(s *neffos.Server, idGen ...IDGenerator) context.Handler {
+	upgrade := Handler(s, idGen...)
+
+	return func(ctx context.Context) {
+		if ctx.Method() == http.MethodPost {
+			f.receive(ctx)
+			return
+		}
+
+		upgrade(ctx)
+
+		r := ctx.Request()
+		f.mu.Lock()
+		sock, ok := f.pending[r]
+		delete(f.pending, r)
+		f.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		// keep the event stream open.
+		f.serve(sock, r)
+	}
+}
+
+func (f *Fallback) serve(s *sseSocket, r *http.Request) {
+	defer func() {
+		s.Close()
+		f.mu.Lock()
+		delete(f.sockets, s.token)
+		f.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(f.KeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if err := s.write([]byte(":\n\n")); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (f *Fallback) receive(ctx context.Context) {
+	f.mu.RLock()
+	s, ok := f.sockets[ctx.GetHeader(FallbackTokenHeader)]
+	f.mu.RUnlock()
+
+	if !ok {
+		ctx.StopWithStatus(http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(ctx.Request().Body, f.MaxMessageSize+1))
+	if err != nil {
+		ctx.StopWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if int64(len(body)) > f.MaxMessageSize {
+		ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	msg := sseMessage{body: body, typ: neffos.TextMessage}
+	if ctx.GetContentTypeRequested() == context.ContentBinaryHeaderValue {
+		msg.typ = neffos.BinaryMessage
+	}
+
+	select {
+	case s.in <- msg:
+		ctx.StatusCode(http.StatusAccepted)
+	case <-s.done:
+		ctx.StopWithStatus(http.StatusGone)
+	case <-ctx.Request().Context().Done():
+	}
+}
+
+func newFallbackToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+type sseMessage struct {
+	body []byte
+	typ  neffos.MessageType
+}
+
+var errFallbackClosed = errors.New("websocket: fallback: connection closed")
+
+// sseSocket is the neffos Socket of the fallback clients.
+type sseSocket struct {
+	token   string
+	request *http.Request
+
+	mu      sync.Mutex // protects the writes.
+	w       http.ResponseWriter
+	flusher http.Flusher
+
+	in        chan sseMessage
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ neffos.Socket = (*sseSocket)(nil)
+
+func (s *sseSocket) NetConn() net.Conn {
+	return sseConn{s}
+}
+
+func (s *sseSocket) Request() *http.Request {
+	return s.request
+}
+
+func (s *sseSocket) ReadData(timeout time.Duration) ([]byte, neffos.MessageType, error) {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case msg := <-s.in:
+		return msg.body, msg.typ, nil
+	case <-s.done:
+		return nil, 0, errFallbackClosed
+	case <-deadline:
+		return nil, 0, errors.New("websocket: fallback: read timeout")
+	}
+}
+
+func (s *sseSocket) WriteBinary(body []byte, timeout time.Duration) error {
+	return s.writeEvent("binary", []byte(base64.StdEncoding.EncodeToString(body)))
+}
+
+func (s *sseSocket) WriteText(body []byte, timeout time.Duration) error {
+	return s.writeEvent("message", body)
+}
+
+func (s *sseSocket) writeEvent(event string, data []byte) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("event: ")
+	buf.WriteString(event)
+	buf.WriteByte('\n')
+
+	// multiline data are joined with "\n" by the clients.
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return s.write(buf.Bytes())
+}
+
+func (s *sseSocket) write(b []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return errFallbackClosed
+	default:
+	}
+
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+// Close closes the event stream, the pending writes are completed first.
+func (s *sseSocket) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		close(s.done)
+		s.mu.Unlock()
+	})
+
+	return nil
+}
+
+// sseConn is the net.Conn of a fallback socket, neffos uses it to close the connection.
+type sseConn struct {
+	s *sseSocket
+}
+
+func (c sseConn) Read([]byte) (int, error)         { return 0, errFallbackClosed }
+func (c sseConn) Write([]byte) (int, error)        { return 0, errFallbackClosed }
+func (c sseConn) Close() error                     { return c.s.Close() }
+func (c sseConn) LocalAddr() net.Addr              { return sseAddr("local") }
+func (c sseConn) RemoteAddr() net.Addr             { return sseAddr(c.s.request.RemoteAddr) }
+func (c sseConn) SetDeadline(time.Time) error      { return nil }
+func (c sseConn) SetReadDeadline(time.Time) error  { return nil }
+func (c sseConn) SetWriteDeadline(time.Time) error { return nil }
+
+type sseAddr string
+
+func (a sseAddr) Network() string { return "sse" }
+func (a sseAddr) String() string  { return string(a) }
@@ -0,0 +1,93 @@
+package websocket_test
+
+import (
+	"bufio"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/websocket"
+)
+
+func TestFallback(t *testing.T) {
+	fallback := websocket.NewFallback(websocket.DefaultGorillaUpgrader)
+	ws := websocket.New(fallback.Upgrade, websocket.Events{
+		websocket.OnNativeMessage: func(nsConn *websocket.NSConn, msg websocket.Message) error {
+			msg.Body = append([]byte("echo: "), msg.Body...)
+			nsConn.Conn.Write(msg)
+			return nil
+		},
+	})
+
+	app := iris.New()
+	app.Any("/socket", fallback.Handler(ws))
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/socket", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if expected, got := "text/event-stream", resp.Header.Get("Content-Type"); expected != got {
+		t.Fatalf("expected content type: %s but got: %s", expected, got)
+	}
+
+	events := bufio.NewReader(resp.Body)
+	next := func() (event, data string) {
+		t.Helper()
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+
+			switch {
+			case line == "":
+				return
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+
+	event, token := next()
+	if event != "open" || token == "" {
+		t.Fatalf("expected the open event with the token but got: %s %s", event, token)
+	}
+
+	send := func(token, body string) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/socket", strings.NewReader(body))
+		req.Header.Set(websocket.FallbackTokenHeader, token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := send(token, "hello"); code != http.StatusAccepted {
+		t.Fatalf("expected status: %d but got: %d", http.StatusAccepted, code)
+	}
+	if event, data := next(); event != "message" || data != "echo: hello" {
+		t.Fatalf("expected the echo message but got: %s %s", event, data)
+	}
+
+	if code := send("invalid", "hello"); code != http.StatusNotFound {
+		t.Fatalf("expected status: %d for an unknown token but got: %d", http.StatusNotFound, code)
+	}
+}
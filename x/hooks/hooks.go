@@ -0,0 +1,464 @@
+// Package hooks provides the delivery of outgoing webhooks (events) to HTTP targets
+// with at-least-once semantics. Each delivery is persisted to a `Store` before its first attempt
+// and it is retried with an exponential backoff until it succeeds or its attempts are exhausted.
+// The deliveries are signed with the target's secret and the `Manager`,
+// as an iris.Plugin, flushes the pending ones on the application's shutdown.
+package hooks
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/webhook"
+
+	"github.com/iris-contrib/go.uuid"
+	"github.com/kataras/golog"
+)
+
+// The request headers of a delivery.
+const (
+	// IDHeaderKey is the delivery ID, it is the same on retries so receivers can drop duplicates.
+	IDHeaderKey = "X-Hook-ID"
+	// EventHeaderKey is the event type.
+	EventHeaderKey = "X-Hook-Event"
+	// TimestampHeaderKey is the unix time of the attempt.
+	TimestampHeaderKey = "X-Hook-Timestamp"
+	// SignatureHeaderKey is the "sha256=" prefixed hex HMAC-SHA256 of the timestamp,
+	// a dot and the body, using the target's secret.
+	SignatureHeaderKey = "X-Hook-Signature"
+)
+
+// ErrClosed is returned by `Deliver` after the Manager's shutdown.
+var ErrClosed = errors.New("hooks: manager is closed")
+
+type (
+	// Event is an outgoing event.
+	Event struct {
+		// ID is the event's identifier, defaults to a random UUID.
+		ID string
+		// Type is the event type, i.e "order.created".
+		Type string
+		// Payload is encoded to JSON as the request body.
+		// A []byte or a json.RawMessage is sent as it is.
+		Payload interface{}
+	}
+
+	// Target is a receiver of events.
+	Target struct {
+		URL string
+		// Secret signs the deliveries, optional.
+		Secret string
+		// Header holds extra request headers, optional.
+		Header http.Header
+	}
+
+	// Delivery is an event's delivery to a target.
+	Delivery struct {
+		ID          string
+		EventID     string
+		EventType   string
+		Target      Target
+		Body        []byte
+		Attempts    int
+		NextAttempt time.Time
+		LastError   string
+		CreatedAt   time.Time
+	}
+)
+
+// Options holds the settings of a Manager.
+type Options struct {
+	// Client sends the deliveries.
+	// Defaults to a client with 30 seconds timeout.
+	Client *http.Client
+	// Store persists the pending deliveries.
+	// Defaults to a `NewMemoryStore()`.
+	Store Store
+	// MaxAttempts is the maximum attempts of a delivery.
+	// Defaults to 10.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it's doubled on each retry.
+	// Defaults to 1 second.
+	Backoff time.Duration
+	// MaxBackoff is the maximum delay between retries.
+	// Defaults to 1 hour.
+	MaxBackoff time.Duration
+	// Workers is the maximum number of concurrent attempts.
+	// Defaults to 4.
+	Workers int
+	// PollInterval is the interval which the Store is checked for due retries.
+	// Defaults to 1 second.
+	PollInterval time.Duration
+	// OnFailure is fired when a delivery has exhausted its attempts, optional.
+	OnFailure func(d *Delivery)
+}
+
+// Manager delivers events to targets, see `New` and `Deliver`.
+// It's an iris.Plugin: it starts on the application's build
+// and flushes the pending deliveries on its shutdown.
+// It is started on the first `Deliver` call too, when used standalone.
+type Manager struct {
+	opts   Options
+	logger *golog.Logger
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	wake      chan struct{}
+	closed    chan struct{}
+	done      chan struct{} // closed when the poll loop exits.
+	workers   chan struct{}
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	inflight map[string]struct{}
+}
+
+var _ iris.Plugin = (*Manager)(nil)
+
+// New returns a new Manager.
+func New(opts Options) *Manager {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Hour
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	return &Manager{
+		opts:     opts,
+		logger:   golog.Default,
+		wake:     make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+		workers:  make(chan struct{}, opts.Workers),
+		inflight: make(map[string]struct{}),
+	}
+}
+
+// Default is the Manager of the package-level `Deliver` function.
+var Default = New(Options{})
+
+// Deliver delivers the "event" to the "targets" through the `Default` Manager.
+func Deliver(event Event, targets ...Target) error {
+	return Default.Deliver(event, targets...)
+}
+
+// Name returns "hooks", implements the iris.PluginNamer.
+func (m *Manager) Name() string {
+	return "hooks"
+}
+
+// Configure uses the "app"'s logger, implements the iris.Plugin.
+func (m *Manager) Configure(app *iris.Application) {
+	m.logger = app.Logger()
+}
+
+// Build starts the Manager, so the deliveries pending from a previous run are retried.
+// Implements the iris.Plugin.
+func (m *Manager) Build(*iris.Application) error {
+	m.start()
+	return nil
+}
+
+// Deliver persists a delivery of the "event" for each one of the "targets"
+// and attempts them in the background. An error is returned when
+// the payload cannot be encoded or a delivery cannot be stored.
+func (m *Manager) Deliver(event Event, targets ...Target) error {
+	select {
+	case <-m.closed:
+		return ErrClosed
+	default:
+	}
+
+	var body []byte
+	switch payload := event.Payload.(type) {
+	case []byte:
+		body = payload
+	case json.RawMessage:
+		body = payload
+	default:
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	if event.ID == "" {
+		event.ID = newID()
+	}
+
+	now := time.Now()
+	for _, target := range targets {
+		d := &Delivery{
+			ID:          newID(),
+			EventID:     event.ID,
+			EventType:   event.Type,
+			Target:      target,
+			Body:        body,
+			NextAttempt: now,
+			CreatedAt:   now,
+		}
+
+		if err := m.opts.Store.Save(d); err != nil {
+			return err
+		}
+	}
+
+	m.start()
+
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func newID() string {
+	id, _ := uuid.NewV4()
+	return id.String()
+}
+
+func (m *Manager) start() {
+	m.startOnce.Do(func() {
+		go m.run()
+	})
+}
+
+func (m *Manager) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		m.dispatch(time.Now(), m.closed)
+
+		select {
+		case <-m.closed:
+			return
+		case <-ticker.C:
+		case <-m.wake:
+		}
+	}
+}
+
+// dispatch attempts the deliveries which are due until "until" and not in-flight,
+// it returns early when "stop" is closed.
+func (m *Manager) dispatch(until time.Time, stop <-chan struct{}) {
+	list, err := m.opts.Store.Pending(until)
+	if err != nil {
+		m.logger.Errorf("hooks: pending: %v", err)
+		return
+	}
+
+	for _, d := range list {
+		m.mu.Lock()
+		_, busy := m.inflight[d.ID]
+		if !busy {
+			m.inflight[d.ID] = struct{}{}
+		}
+		m.mu.Unlock()
+
+		if busy {
+			continue
+		}
+
+		select {
+		case m.workers <- struct{}{}:
+		case <-stop:
+			m.release(d.ID)
+			return
+		}
+
+		m.wg.Add(1)
+		go func(d *Delivery) {
+			defer func() {
+				<-m.workers
+				m.release(d.ID)
+				m.wg.Done()
+			}()
+
+			m.attempt(d)
+		}(d)
+	}
+}
+
+func (m *Manager) release(id string) {
+	m.mu.Lock()
+	delete(m.inflight, id)
+	m.mu.Unlock()
+}
+
+func (m *Manager) attempt(d *Delivery) {
+	err := m.send(d)
+	d.Attempts++
+
+	if err == nil {
+		if err = m.opts.Store.Delete(d.ID); err != nil {
+			m.logger.Errorf("hooks: delete: %s: %v", d.ID, err)
+		}
+		return
+	}
+
+	d.LastError = err.Error()
+
+	if d.Attempts >= m.opts.MaxAttempts {
+		if err = m.opts.Store.Delete(d.ID); err != nil {
+			m.logger.Errorf("hooks: delete: %s: %v", d.ID, err)
+		}
+
+		if m.opts.OnFailure != nil {
+			m.opts.OnFailure(d)
+		}
+		return
+	}
+
+	d.NextAttempt = time.Now().Add(m.backoff(d.Attempts))
+	if err = m.opts.Store.Save(d); err != nil {
+		m.logger.Errorf("hooks: save: %s: %v", d.ID, err)
+	}
+}
+
+// backoff returns the delay after the "attempts" failed attempts.
+func (m *Manager) backoff(attempts int) time.Duration {
+	delay := m.opts.Backoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= m.opts.MaxBackoff {
+			return m.opts.MaxBackoff
+		}
+	}
+
+	return delay
+}
+
+func (m *Manager) send(d *Delivery) error {
+	req, err := http.NewRequest(http.MethodPost, d.Target.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range d.Target.Header {
+		req.Header[k] = v
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeaderKey, d.ID)
+	req.Header.Set(EventHeaderKey, d.EventType)
+	req.Header.Set(TimestampHeaderKey, timestamp)
+	if d.Target.Secret != "" {
+		req.Header.Set(SignatureHeaderKey, "sha256="+webhook.Sign(d.Target.Secret, append([]byte(timestamp+"."), d.Body...)))
+	}
+
+	resp, err := m.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hooks: %s: unexpected status code: %d", d.Target.URL, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Shutdown stops the Manager and attempts all the pending deliveries once, even if they are not due yet,
+// until they complete or the "ctx" is done. The failed ones are kept in the Store.
+// Implements the iris.Plugin.
+func (m *Manager) Shutdown(ctx stdContext.Context) error {
+	m.closeOnce.Do(func() {
+		close(m.closed)
+	})
+
+	// wait for the poll loop, if it was ever started.
+	m.startOnce.Do(func() { close(m.done) })
+	<-m.done
+
+	m.dispatch(time.Now().Add(m.opts.MaxBackoff), ctx.Done())
+
+	flushed := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Provider returns the webhook.Provider which verifies the deliveries of a Manager,
+// for applications receiving events from other Iris applications, i.e
+//  app.Post("/events", webhook.New(webhook.Options{Provider: hooks.Provider(secret, 5*time.Minute)}), dispatcher.Handle)
+func Provider(secret string, tolerance time.Duration) webhook.Provider {
+	return webhook.Provider{
+		Name: "hooks",
+		Verify: func(r *http.Request, body []byte) error {
+			signature, timestamp := r.Header.Get(SignatureHeaderKey), r.Header.Get(TimestampHeaderKey)
+			if signature == "" || timestamp == "" {
+				return webhook.ErrMissingSignature
+			}
+
+			sec, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return webhook.ErrInvalidSignature
+			}
+
+			if tolerance > 0 {
+				if d := time.Since(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+					return webhook.ErrTimestamp
+				}
+			}
+
+			expected := "sha256=" + webhook.Sign(secret, append([]byte(timestamp+"."), body...))
+			if !hmac.Equal([]byte(signature), []byte(expected)) {
+				return webhook.ErrInvalidSignature
+			}
+
+			return nil
+		},
+		DeliveryID: func(r *http.Request, _ []byte) string {
+			return r.Header.Get(IDHeaderKey)
+		},
+		EventType: func(r *http.Request, _ []byte) string {
+			return r.Header.Get(EventHeaderKey)
+		},
+	}
+}
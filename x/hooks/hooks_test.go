@@ -0,0 +1,158 @@
+package hooks_test
+
+import (
+	stdContext "context"
+	stdhttptest "net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/webhook"
+	"github.com/kataras/iris/v12/x/hooks"
+)
+
+type order struct {
+	ID int `json:"id"`
+}
+
+func TestDeliver(t *testing.T) {
+	const secret = "hooks secret"
+
+	var (
+		attempts uint32
+		mu       sync.Mutex
+		received []int
+		done     = make(chan struct{})
+	)
+
+	d := webhook.NewDispatcher().On("order.created", func(o order) error {
+		mu.Lock()
+		received = append(received, o.ID)
+		mu.Unlock()
+		close(done)
+		return nil
+	})
+
+	app := iris.New()
+	app.Post("/events", func(ctx iris.Context) {
+		// fail the first two attempts.
+		if atomic.AddUint32(&attempts, 1) <= 2 {
+			ctx.StopWithStatus(iris.StatusServiceUnavailable)
+			return
+		}
+		ctx.Next()
+	}, webhook.New(webhook.Options{Provider: hooks.Provider(secret, time.Minute)}), d.Handle)
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	m := hooks.New(hooks.Options{Backoff: 10 * time.Millisecond, PollInterval: 5 * time.Millisecond})
+	err := m.Deliver(hooks.Event{Type: "order.created", Payload: order{ID: 42}}, hooks.Target{URL: srv.URL + "/events", Secret: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery timed out")
+	}
+
+	if err = m.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := uint32(3), atomic.LoadUint32(&attempts); expected != got {
+		t.Fatalf("expected %d attempts but got %d", expected, got)
+	}
+
+	if len(received) != 1 || received[0] != 42 {
+		t.Fatalf("unexpected received orders: %v", received)
+	}
+
+	if err = m.Deliver(hooks.Event{Type: "order.created"}); err != hooks.ErrClosed {
+		t.Fatalf("expected: %v but got: %v", hooks.ErrClosed, err)
+	}
+}
+
+func TestDeliverFailure(t *testing.T) {
+	app := iris.New()
+	app.Post("/events", func(ctx iris.Context) {
+		ctx.StopWithStatus(iris.StatusInternalServerError)
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	failed := make(chan *hooks.Delivery, 1)
+	store := hooks.NewMemoryStore()
+	m := hooks.New(hooks.Options{
+		Store:        store,
+		MaxAttempts:  3,
+		Backoff:      time.Millisecond,
+		PollInterval: time.Millisecond,
+		OnFailure: func(d *hooks.Delivery) {
+			failed <- d
+		},
+	})
+
+	m.Deliver(hooks.Event{Type: "ping", Payload: []byte(`{}`)}, hooks.Target{URL: srv.URL + "/events"})
+
+	select {
+	case d := <-failed:
+		if expected, got := 3, d.Attempts; expected != got {
+			t.Fatalf("expected %d attempts but got %d", expected, got)
+		}
+		if d.LastError == "" {
+			t.Fatal("expected the last error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure timed out")
+	}
+
+	if pending, _ := store.Pending(time.Now().Add(time.Hour)); len(pending) != 0 {
+		t.Fatalf("expected no pending deliveries but got %d", len(pending))
+	}
+}
+
+func TestShutdownFlush(t *testing.T) {
+	var delivered uint32
+
+	app := iris.New()
+	app.Post("/events", func(ctx iris.Context) {
+		atomic.AddUint32(&delivered, 1)
+	})
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := stdhttptest.NewServer(app)
+	defer srv.Close()
+
+	// a delivery pending from a previous run, not due yet.
+	store := hooks.NewMemoryStore()
+	store.Save(&hooks.Delivery{
+		ID:          "1",
+		Target:      hooks.Target{URL: srv.URL + "/events"},
+		Body:        []byte(`{}`),
+		Attempts:    1,
+		NextAttempt: time.Now().Add(time.Minute),
+	})
+
+	m := hooks.New(hooks.Options{Store: store})
+	if err := m.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := uint32(1), atomic.LoadUint32(&delivered); expected != got {
+		t.Fatalf("expected %d flushed deliveries but got %d", expected, got)
+	}
+}
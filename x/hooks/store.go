@@ -0,0 +1,71 @@
+package hooks
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store describes the storage of the pending deliveries.
+// A persistent Store makes the deliveries survive restarts,
+// the pending ones are retried when the Manager starts again.
+type Store interface {
+	// Save inserts or updates the "d" delivery.
+	Save(d *Delivery) error
+	// Delete removes the delivery of the "id".
+	Delete(id string) error
+	// Pending returns the pending deliveries which
+	// should be attempted until "until", sorted by their next attempt.
+	Pending(until time.Time) ([]*Delivery, error)
+}
+
+// MemoryStore is an in-memory `Store`, the pending deliveries are lost on restart.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	deliveries map[string]*Delivery
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns a new in-memory `Store`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		deliveries: make(map[string]*Delivery),
+	}
+}
+
+// Save inserts or updates a copy of the "d" delivery.
+func (s *MemoryStore) Save(d *Delivery) error {
+	c := *d
+	s.mu.Lock()
+	s.deliveries[d.ID] = &c
+	s.mu.Unlock()
+	return nil
+}
+
+// Delete removes the delivery of the "id".
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	delete(s.deliveries, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// Pending returns copies of the deliveries which should be attempted until "until".
+func (s *MemoryStore) Pending(until time.Time) ([]*Delivery, error) {
+	s.mu.RLock()
+	list := make([]*Delivery, 0, len(s.deliveries))
+	for _, d := range s.deliveries {
+		if !d.NextAttempt.After(until) {
+			c := *d
+			list = append(list, &c)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].NextAttempt.Before(list[j].NextAttempt)
+	})
+
+	return list, nil
+}
@@ -0,0 +1,219 @@
+// Package pagination provides a request-scoped pagination `Params` value,
+// bindable through the hero dependency injection, which parses
+// the page, limit and cursor URL query parameters
+// and can write the RFC 5988 "Link" and "X-Total-Count" response headers.
+//
+// Usage:
+//
+//  api := app.Party("/books").ConfigureContainer()
+//  api.RegisterDependency(pagination.Dependency)
+//  api.Get("/", func(ctx iris.Context, p pagination.Params) []Book {
+//      books, total := store.List(p.Offset(), p.Limit)
+//      p.WriteLinkHeaders(ctx, total)
+//      return books
+//  })
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+const (
+	// LinkHeaderKey is the header key of "Link".
+	LinkHeaderKey = "Link"
+	// TotalCountHeaderKey is the header key of "X-Total-Count".
+	TotalCountHeaderKey = "X-Total-Count"
+)
+
+// Options holds the configuration for the `New` function.
+type Options struct {
+	// PageParam is the URL query parameter name of the page number.
+	// Defaults to "page".
+	PageParam string
+	// LimitParam is the URL query parameter name of the items per page.
+	// Defaults to "limit".
+	LimitParam string
+	// CursorParam is the URL query parameter name of the cursor,
+	// used by cursor-based pagination instead of the page number.
+	// Defaults to "cursor".
+	CursorParam string
+	// DefaultLimit is the limit when the client did not provide one.
+	// Defaults to 20.
+	DefaultLimit int
+	// MaxLimit caps the client's limit.
+	// Defaults to 100.
+	MaxLimit int
+}
+
+// DefaultOptions is the default options used by the `Dependency` and `Read`.
+var DefaultOptions = Options{
+	PageParam:    "page",
+	LimitParam:   "limit",
+	CursorParam:  "cursor",
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// ErrInvalid is returned when the page or limit URL query parameters are not positive integers.
+var ErrInvalid = errors.New("pagination: invalid page or limit")
+
+// Params holds the pagination parameters of the current request.
+type Params struct {
+	// Page is the 1-based requested page number.
+	Page int
+	// Limit is the number of items per page, it's always between 1 and `Options.MaxLimit`.
+	Limit int
+	// Cursor is the opaque cursor sent by the client, if any.
+	Cursor string
+
+	opts Options
+}
+
+// Offset returns the number of items to skip for the requested page.
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// Dependency is the hero dependency which binds a `Params` value
+// based on the `DefaultOptions`. Register it through `Party.ConfigureContainer().RegisterDependency`.
+var Dependency = New(DefaultOptions)
+
+// Read returns the `Params` of the current request based on the `DefaultOptions`.
+func Read(ctx context.Context) (Params, error) {
+	return Dependency(ctx)
+}
+
+// New returns a dependency function which parses the pagination `Params` of the request
+// based on the given options. Missing options fields are set to their defaults.
+func New(opts Options) func(ctx context.Context) (Params, error) {
+	if opts.PageParam == "" {
+		opts.PageParam = DefaultOptions.PageParam
+	}
+
+	if opts.LimitParam == "" {
+		opts.LimitParam = DefaultOptions.LimitParam
+	}
+
+	if opts.CursorParam == "" {
+		opts.CursorParam = DefaultOptions.CursorParam
+	}
+
+	if opts.DefaultLimit <= 0 {
+		opts.DefaultLimit = DefaultOptions.DefaultLimit
+	}
+
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = DefaultOptions.MaxLimit
+	}
+
+	if opts.DefaultLimit > opts.MaxLimit {
+		opts.DefaultLimit = opts.MaxLimit
+	}
+
+	return func(ctx context.Context) (Params, error) {
+		p := Params{Page: 1, Limit: opts.DefaultLimit, opts: opts}
+
+		query := ctx.Request().URL.Query()
+		if s := query.Get(opts.PageParam); s != "" {
+			page, err := strconv.Atoi(s)
+			if err != nil || page < 1 {
+				return p, ErrInvalid
+			}
+			p.Page = page
+		}
+
+		if s := query.Get(opts.LimitParam); s != "" {
+			limit, err := strconv.Atoi(s)
+			if err != nil || limit < 1 {
+				return p, ErrInvalid
+			}
+
+			if limit > opts.MaxLimit {
+				limit = opts.MaxLimit
+			}
+			p.Limit = limit
+		}
+
+		p.Cursor = query.Get(opts.CursorParam)
+		return p, nil
+	}
+}
+
+// LastPage returns the last page number based on the "total" number of items.
+func (p Params) LastPage(total int) int {
+	if total <= 0 || p.Limit <= 0 {
+		return 1
+	}
+
+	return (total + p.Limit - 1) / p.Limit
+}
+
+// WriteLinkHeaders sets the "X-Total-Count" response header to "total"
+// and the "Link" response header to the "first", "prev", "next" and "last" page links,
+// the "prev" and "next" ones are omitted when there is no such page.
+func (p Params) WriteLinkHeaders(ctx context.Context, total int) {
+	ctx.Header(TotalCountHeaderKey, strconv.Itoa(total))
+
+	last := p.LastPage(total)
+	links := []string{p.link(ctx, "first", 1, "")}
+	if p.Page > 1 {
+		prev := p.Page - 1
+		if prev > last {
+			prev = last
+		}
+		links = append(links, p.link(ctx, "prev", prev, ""))
+	}
+
+	if p.Page < last {
+		links = append(links, p.link(ctx, "next", p.Page+1, ""))
+	}
+
+	links = append(links, p.link(ctx, "last", last, ""))
+	ctx.Header(LinkHeaderKey, strings.Join(links, ", "))
+}
+
+// WriteCursorLinkHeader sets the "Link" response header to the "next" link
+// of a cursor-based pagination. It does nothing if "nextCursor" is empty (last page).
+func (p Params) WriteCursorLinkHeader(ctx context.Context, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+
+	ctx.Header(LinkHeaderKey, p.link(ctx, "next", 0, nextCursor))
+}
+
+func (p Params) link(ctx context.Context, rel string, page int, cursor string) string {
+	r := ctx.Request()
+
+	query := r.URL.Query()
+	if cursor != "" {
+		query.Del(p.opts.PageParam)
+		query.Set(p.opts.CursorParam, cursor)
+	} else {
+		query.Del(p.opts.CursorParam)
+		query.Set(p.opts.PageParam, strconv.Itoa(page))
+	}
+	query.Set(p.opts.LimitParam, strconv.Itoa(p.Limit))
+
+	u := url.URL{
+		Host:     ctx.Host(),
+		Path:     r.URL.Path,
+		RawPath:  r.URL.RawPath,
+		RawQuery: query.Encode(),
+	}
+	// relative links when the host is unknown.
+	if u.Host != "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
@@ -0,0 +1,56 @@
+package pagination_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/x/pagination"
+)
+
+func TestPagination(t *testing.T) {
+	app := iris.New()
+	api := app.Party("/books").ConfigureContainer()
+	api.RegisterDependency(pagination.New(pagination.Options{DefaultLimit: 10, MaxLimit: 50}))
+	api.OnError(func(ctx iris.Context, err error) {
+		ctx.StatusCode(iris.StatusBadRequest)
+		ctx.WriteString(err.Error())
+	})
+	api.Get("/", func(ctx iris.Context, p pagination.Params) string {
+		p.WriteLinkHeaders(ctx, 95)
+		return fmt.Sprintf("page=%d limit=%d offset=%d", p.Page, p.Limit, p.Offset())
+	})
+	api.Get("/cursor", func(ctx iris.Context, p pagination.Params) string {
+		p.WriteCursorLinkHeader(ctx, "abc")
+		return p.Cursor
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/books").Expect().Status(httptest.StatusOK).
+		Body().Equal("page=1 limit=10 offset=0")
+	e.GET("/books").WithQuery("page", 3).WithQuery("limit", 1000).Expect().Status(httptest.StatusOK).
+		Body().Equal("page=3 limit=50 offset=100")
+
+	resp := e.GET("/books").WithQuery("page", 2).WithQuery("limit", 20).Expect().Status(httptest.StatusOK)
+	resp.Header(pagination.TotalCountHeaderKey).Equal("95")
+	resp.Header(pagination.LinkHeaderKey).Equal(
+		`</books?limit=20&page=1>; rel="first", ` +
+			`</books?limit=20&page=1>; rel="prev", ` +
+			`</books?limit=20&page=3>; rel="next", ` +
+			`</books?limit=20&page=5>; rel="last"`)
+
+	resp = e.GET("/books").WithQuery("page", 5).WithQuery("limit", 20).Expect().Status(httptest.StatusOK)
+	resp.Header(pagination.LinkHeaderKey).Equal(
+		`</books?limit=20&page=1>; rel="first", ` +
+			`</books?limit=20&page=4>; rel="prev", ` +
+			`</books?limit=20&page=5>; rel="last"`)
+
+	e.GET("/books").WithQuery("page", 0).Expect().Status(httptest.StatusBadRequest).
+		Body().Equal(pagination.ErrInvalid.Error())
+	e.GET("/books").WithQuery("limit", "ten").Expect().Status(httptest.StatusBadRequest)
+
+	e.GET("/books/cursor").WithQuery("cursor", "xyz").Expect().Status(httptest.StatusOK).
+		Header(pagination.LinkHeaderKey).Equal(`</books/cursor?cursor=abc&limit=10>; rel="next"`)
+}
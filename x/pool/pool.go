@@ -0,0 +1,154 @@
+// Package pool provides a fixed-size worker pool bound to the lifecycle of an Iris Application.
+// Handlers submit tasks to the pool instead of starting their own goroutines,
+// the tasks are panic-recovered and the queued ones are drained on the application's shutdown.
+package pool
+
+import (
+	stdContext "context"
+	"errors"
+	"runtime/debug"
+	"sync"
+
+	"github.com/kataras/iris/v12"
+
+	"github.com/kataras/golog"
+)
+
+// ErrClosed is returned by `Submit` after the pool's shutdown.
+var ErrClosed = errors.New("pool: closed")
+
+// Task is a job submitted to a Pool.
+// Its context is canceled when the shutdown's deadline is exceeded before the task completes.
+type Task func(ctx stdContext.Context)
+
+// Pool is a fixed-size worker pool, see `New`.
+// It's an iris.Plugin, register it with `app.RegisterPlugin(p)`, so its queued tasks are drained on `app.Shutdown`
+// and handlers can accept it as an input argument, i.e
+//  func(p *pool.Pool, order Order) error {
+//      return p.Submit(func(ctx context.Context) { sendReceipt(ctx, order) })
+//  }
+// Note that the Context should not be used inside a task, use the `Context.Detach` instead.
+type Pool struct {
+	logger *golog.Logger
+
+	tasks  chan Task
+	quit   chan struct{}
+	ctx    stdContext.Context
+	cancel stdContext.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+var _ iris.Plugin = (*Pool)(nil)
+
+// New returns a new Pool of "size" workers, its queue holds up to "size" pending tasks.
+func New(size int) *Pool {
+	if size <= 0 {
+		size = 1
+	}
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	p := &Pool{
+		logger: golog.Default,
+		tasks:  make(chan Task, size),
+		quit:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	p.wg.Add(size)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// Name returns "pool", implements the iris.PluginNamer.
+func (p *Pool) Name() string {
+	return "pool"
+}
+
+// Configure uses the "app"'s logger for the tasks' panics, implements the iris.Plugin.
+func (p *Pool) Configure(app *iris.Application) {
+	p.logger = app.Logger()
+}
+
+// Build does nothing, implements the iris.Plugin.
+func (p *Pool) Build(*iris.Application) error {
+	return nil
+}
+
+// Submit queues the "task", it blocks while the queue is full.
+// It returns `ErrClosed` after the pool's shutdown.
+func (p *Pool) Submit(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	p.tasks <- task
+	return nil
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case task := <-p.tasks:
+			p.run(task)
+		case <-p.quit:
+			// drain the queued tasks.
+			for {
+				select {
+				case task := <-p.tasks:
+					p.run(task)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool) run(task Task) {
+	defer func() {
+		if v := recover(); v != nil {
+			p.logger.Errorf("pool: task panic: %v\n%s", v, debug.Stack())
+		}
+	}()
+
+	task(p.ctx)
+}
+
+// Shutdown stops accepting new tasks and waits for the queued and the running ones to complete.
+// If the "ctx" is done first, the tasks' context is canceled and the "ctx"'s error is returned.
+// Implements the iris.Plugin.
+func (p *Pool) Shutdown(ctx stdContext.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.quit)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
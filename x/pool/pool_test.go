@@ -0,0 +1,73 @@
+package pool_test
+
+import (
+	stdContext "context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/x/pool"
+)
+
+func TestPool(t *testing.T) {
+	var completed uint32
+
+	app := iris.New()
+	app.RegisterPlugin(pool.New(2))
+
+	app.ConfigureContainer().Post("/jobs", func(p *pool.Pool) (int, error) {
+		for i := 0; i < 5; i++ {
+			if err := p.Submit(func(stdContext.Context) {
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddUint32(&completed, 1)
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		// a panic does not bring the worker down.
+		p.Submit(func(stdContext.Context) { panic("task failed") })
+		return iris.StatusAccepted, nil
+	})
+
+	e := httptest.New(t, app)
+	e.POST("/jobs").Expect().Status(httptest.StatusAccepted)
+
+	if err := app.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := uint32(5), atomic.LoadUint32(&completed); expected != got {
+		t.Fatalf("expected %d completed tasks but got %d", expected, got)
+	}
+
+	p := app.Plugins()[0].(*pool.Pool)
+	if err := p.Submit(func(stdContext.Context) {}); err != pool.ErrClosed {
+		t.Fatalf("expected: %v but got: %v", pool.ErrClosed, err)
+	}
+}
+
+func TestPoolShutdownDeadline(t *testing.T) {
+	p := pool.New(1)
+
+	canceled := make(chan struct{})
+	p.Submit(func(ctx stdContext.Context) {
+		<-ctx.Done()
+		close(canceled)
+	})
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := p.Shutdown(ctx); err != stdContext.DeadlineExceeded {
+		t.Fatalf("expected: %v but got: %v", stdContext.DeadlineExceeded, err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the task's context to be canceled")
+	}
+}
@@ -0,0 +1,229 @@
+// Package uow provides a database-agnostic, request-scoped unit of work.
+// A transaction is started lazily, on the first `UnitOfWork.Tx` call of the request,
+// and it's committed when the request succeeds (2xx) or rolled back on errors and panics.
+//
+// Usage:
+//
+//  api := app.Party("/users").ConfigureContainer(uow.Configure(uow.SQL(db, nil)))
+//  api.RegisterDependency(NewUserService) // func(u *uow.UnitOfWork) *UserService
+//  api.Post("/", func(s *UserService, u User) error {
+//      return s.Create(u) // tx, err := s.uow.Tx(); tx.(*sql.Tx).Exec(...)
+//  })
+package uow
+
+import (
+	stdContext "context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/hero"
+)
+
+func init() {
+	context.SetHandlerName("iris/x/uow.*", "UnitOfWork")
+}
+
+// Tx is a database transaction, e.g. a *sql.Tx.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// TxProvider begins the transactions.
+type TxProvider interface {
+	Begin(ctx stdContext.Context) (Tx, error)
+}
+
+// TxProviderFunc is a function which implements the TxProvider.
+type TxProviderFunc func(ctx stdContext.Context) (Tx, error)
+
+// Begin calls the "fn".
+func (fn TxProviderFunc) Begin(ctx stdContext.Context) (Tx, error) {
+	return fn(ctx)
+}
+
+// SQL returns a TxProvider which begins database/sql transactions,
+// the `UnitOfWork.Tx` returns a *sql.Tx.
+func SQL(db *sql.DB, opts *sql.TxOptions) TxProvider {
+	return TxProviderFunc(func(ctx stdContext.Context) (Tx, error) {
+		return db.BeginTx(ctx, opts)
+	})
+}
+
+// ErrMissing is returned from the `Dependency` when the `New` middleware was not registered.
+var ErrMissing = errors.New("uow: missing unit of work, register the uow.New middleware")
+
+// UnitOfWork is the request-scoped unit of work, see `Get` and `Dependency`.
+type UnitOfWork struct {
+	ctx      context.Context
+	provider TxProvider
+
+	mu     sync.Mutex
+	tx     Tx
+	failed error
+}
+
+// Tx returns the request's transaction, it's started on the first call.
+func (u *UnitOfWork) Tx() (Tx, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.tx == nil {
+		tx, err := u.provider.Begin(u.ctx.Request().Context())
+		if err != nil {
+			return nil, err
+		}
+
+		u.tx = tx
+	}
+
+	return u.tx, nil
+}
+
+// Started reports whether the transaction was started.
+func (u *UnitOfWork) Started() bool {
+	u.mu.Lock()
+	started := u.tx != nil
+	u.mu.Unlock()
+	return started
+}
+
+// Fail marks the unit of work as failed, the transaction is rolled back
+// even if the request succeeds.
+func (u *UnitOfWork) Fail(err error) {
+	if err == nil {
+		err = errors.New("uow: failed")
+	}
+
+	u.mu.Lock()
+	if u.failed == nil {
+		u.failed = err
+	}
+	u.mu.Unlock()
+}
+
+// end commits or rolls back the transaction, if started.
+func (u *UnitOfWork) end(commit bool) error {
+	u.mu.Lock()
+	tx := u.tx
+	commit = commit && u.failed == nil
+	u.tx = nil
+	u.mu.Unlock()
+
+	if tx == nil {
+		return nil
+	}
+
+	if commit {
+		return tx.Commit()
+	}
+
+	return tx.Rollback()
+}
+
+const contextKey = "iris.uow"
+
+// New returns the middleware which creates the request's UnitOfWork.
+// After the next handlers, the transaction, if started, is committed when the response status code is 2xx
+// and there is no `Context.GetErr`, otherwise it's rolled back. On panic the transaction is rolled back
+// and the panic continues. A commit failure is stored as the `Context.SetErr` and it's logged.
+//
+// The response is recorded and it's sent to the client after the commit,
+// on commit failure it's replaced by a 500 Internal Server Error.
+func New(provider TxProvider) context.Handler {
+	if provider == nil {
+		panic("uow: nil TxProvider")
+	}
+
+	return func(ctx context.Context) {
+		u := &UnitOfWork{ctx: ctx, provider: provider}
+		ctx.Values().Set(contextKey, u)
+		// do not send the response before the commit.
+		ctx.Record()
+
+		defer func() {
+			if v := recover(); v != nil {
+				u.end(false)
+				panic(v)
+			}
+
+			status := ctx.GetStatusCode()
+			commit := status >= 200 && status < 300 && ctx.GetErr() == nil
+			err := u.end(commit)
+			if err != nil {
+				ctx.SetErr(err)
+				ctx.Application().Log().Error("uow: transaction failed", "method", ctx.Method(), "path", ctx.Path(), "commit", commit, "error", err)
+			}
+
+			rec, ok := ctx.IsRecording()
+			if !ok {
+				return
+			}
+
+			if err != nil && commit {
+				// keep recording, the error code handler writes the response.
+				rec.Reset()
+				ctx.StopWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			// send the response and stop recording,
+			// so the error code handlers do not replace the non-successful responses.
+			ctx.ResetResponseWriter(rec.ResponseWriter)
+			rec.FlushResponse()
+		}()
+
+		ctx.Next()
+	}
+}
+
+// Get returns the request's UnitOfWork, or nil if the `New` middleware was not registered.
+func Get(ctx context.Context) *UnitOfWork {
+	if v := ctx.Values().Get(contextKey); v != nil {
+		if u, ok := v.(*UnitOfWork); ok {
+			return u
+		}
+	}
+
+	return nil
+}
+
+// Dependency is the hero dependency of the request's *UnitOfWork,
+// services and handlers can accept it as an input argument.
+func Dependency(ctx context.Context) (*UnitOfWork, error) {
+	if u := Get(ctx); u != nil {
+		return u, nil
+	}
+
+	return nil, ErrMissing
+}
+
+// ResultHandler is a hero result handler which marks the UnitOfWork as failed
+// when the handler's result could not be dispatched.
+func ResultHandler(next hero.ResultHandler) hero.ResultHandler {
+	return func(ctx context.Context, v interface{}) error {
+		err := next(ctx, v)
+		if err != nil {
+			if u := Get(ctx); u != nil {
+				u.Fail(err)
+			}
+		}
+
+		return err
+	}
+}
+
+// Configure returns a function which registers the `New` middleware, the `Dependency`
+// and the `ResultHandler` to a Party's container, i.e
+//  app.ConfigureContainer(uow.Configure(provider))
+func Configure(provider TxProvider) func(*router.APIContainer) {
+	return func(api *router.APIContainer) {
+		api.Self.Use(New(provider))
+		api.RegisterDependency(Dependency)
+		api.UseResultHandler(ResultHandler)
+	}
+}
@@ -0,0 +1,125 @@
+package uow_test
+
+import (
+	stdContext "context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/recover"
+	"github.com/kataras/iris/v12/x/uow"
+)
+
+type testTx struct {
+	mu         *sync.Mutex
+	events     *[]string
+	failCommit bool
+}
+
+func (tx *testTx) record(event string) error {
+	tx.mu.Lock()
+	*tx.events = append(*tx.events, event)
+	tx.mu.Unlock()
+	return nil
+}
+
+func (tx *testTx) Commit() error {
+	if tx.failCommit {
+		tx.record("commit failed")
+		return errors.New("commit failed")
+	}
+
+	return tx.record("commit")
+}
+
+func (tx *testTx) Rollback() error { return tx.record("rollback") }
+
+type userService struct {
+	u *uow.UnitOfWork
+}
+
+func (s *userService) Create(name string) error {
+	if _, err := s.u.Tx(); err != nil {
+		return err
+	}
+
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+func TestUnitOfWork(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		events     []string
+		failCommit bool
+	)
+
+	provider := uow.TxProviderFunc(func(stdContext.Context) (uow.Tx, error) {
+		tx := &testTx{mu: &mu, events: &events, failCommit: failCommit}
+		tx.record("begin")
+		return tx, nil
+	})
+
+	app := iris.New()
+	app.Use(recover.New())
+	api := app.Party("/users").ConfigureContainer(uow.Configure(provider))
+	api.RegisterDependency(func(u *uow.UnitOfWork) *userService {
+		return &userService{u: u}
+	})
+	api.Post("/{name:string}", func(s *userService, name string) error {
+		return s.Create(name)
+	})
+	api.Post("/", func(s *userService) error {
+		return s.Create("")
+	})
+	api.Get("/", func() string { // no transaction.
+		return "users"
+	})
+	api.Self.Post("/panic", func(ctx iris.Context) {
+		uow.Get(ctx).Tx()
+		panic("oops")
+	})
+
+	e := httptest.New(t, app)
+
+	expect := func(expected ...string) {
+		t.Helper()
+
+		mu.Lock()
+		got := events
+		events = nil
+		mu.Unlock()
+
+		if len(got) != len(expected) {
+			t.Fatalf("expected events: %v but got: %v", expected, got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("expected events: %v but got: %v", expected, got)
+			}
+		}
+	}
+
+	e.POST("/users/kataras").Expect().Status(httptest.StatusOK)
+	expect("begin", "commit")
+
+	// the successful response is not sent when the commit fails.
+	failCommit = true
+	e.POST("/users/kataras").Expect().Status(httptest.StatusInternalServerError)
+	expect("begin", "commit failed")
+	failCommit = false
+
+	e.POST("/users").Expect().Status(httptest.StatusBadRequest)
+	expect("begin", "rollback")
+
+	e.GET("/users").Expect().Status(httptest.StatusOK).Body().Equal("users")
+	expect()
+
+	e.POST("/users/panic").Expect().Status(httptest.StatusInternalServerError)
+	expect("begin", "rollback")
+}